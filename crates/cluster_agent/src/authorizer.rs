@@ -14,6 +14,40 @@ pub struct Authorizer {
     k8s_config: Config,
 }
 
+/// Identity forwarded by the cluster-api in the `impersonate-*` request metadata.
+#[derive(Debug, PartialEq)]
+struct Impersonation {
+    user: String,
+    uid: Option<String>,
+    groups: Vec<String>,
+}
+
+impl Impersonation {
+    /// Reads the forwarded identity from the request metadata, if present.
+    fn from_metadata(request_metadata: &MetadataMap) -> Option<Self> {
+        let user = request_metadata
+            .get("impersonate-user")
+            .and_then(|user| user.to_str().ok())
+            .filter(|user| !user.is_empty())?
+            .to_owned();
+
+        let uid = request_metadata
+            .get("impersonate-uid")
+            .and_then(|uid| uid.to_str().ok())
+            .filter(|uid| !uid.is_empty())
+            .map(str::to_owned);
+
+        let groups = request_metadata
+            .get_all("impersonate-group")
+            .iter()
+            .filter_map(|group| group.to_str().ok())
+            .map(str::to_owned)
+            .collect();
+
+        Some(Self { user, uid, groups })
+    }
+}
+
 /// Checks that the the k8s doing the request has proper rights to access the log files.
 #[cfg(not(test))]
 impl Authorizer {
@@ -43,6 +77,21 @@ impl Authorizer {
             ..Default::default()
         };
 
+        // Act as the forwarded identity. The API server checks that the token is
+        // allowed to impersonate it.
+        if let Some(impersonation) = Impersonation::from_metadata(request_metadata) {
+            if let Some(uid) = impersonation.uid {
+                let value = http::HeaderValue::from_str(&uid).map_err(|error| {
+                    Status::new(tonic::Code::InvalidArgument, error.to_string())
+                })?;
+                k8s_config
+                    .headers
+                    .push((http::HeaderName::from_static("impersonate-uid"), value));
+            }
+            k8s_config.auth_info.impersonate = Some(impersonation.user);
+            k8s_config.auth_info.impersonate_groups = Some(impersonation.groups);
+        }
+
         Ok(Self { k8s_config })
     }
 
@@ -111,3 +160,28 @@ impl Authorizer {
         Ok(())
     }
 }
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_impersonation_from_metadata() {
+        let mut metadata = MetadataMap::new();
+        assert_eq!(Impersonation::from_metadata(&metadata), None);
+
+        metadata.insert("impersonate-user", "alice".parse().unwrap());
+        metadata.insert("impersonate-uid", "42".parse().unwrap());
+        metadata.append("impersonate-group", "dev".parse().unwrap());
+        metadata.append("impersonate-group", "ops".parse().unwrap());
+
+        assert_eq!(
+            Impersonation::from_metadata(&metadata),
+            Some(Impersonation {
+                user: "alice".to_owned(),
+                uid: Some("42".to_owned()),
+                groups: vec!["dev".to_owned(), "ops".to_owned()],
+            })
+        );
+    }
+}
@@ -16,6 +16,7 @@ pub struct Config {
     pub logs_dir: PathBuf,
     pub logging: LoggingConfig,
     pub tls: TlsConfig,
+    pub tracing: TracingConfig,
 }
 
 #[derive(Deserialize, Debug)]
@@ -26,6 +27,7 @@ struct ConfigInternal {
     logs_dir: PathBuf,
     logging: LoggingConfig,
     tls: TlsConfig,
+    tracing: TracingConfig,
 }
 
 #[derive(Deserialize, Debug)]
@@ -58,6 +60,15 @@ pub struct TlsConfig {
     pub client_auth: Option<String>,
 }
 
+#[derive(Deserialize, Debug)]
+pub struct TracingConfig {
+    pub enabled: bool,
+    pub endpoint: String,
+
+    #[serde(rename(deserialize = "sampling-ratio"))]
+    pub sampling_ratio: f64,
+}
+
 impl Config {
     pub async fn parse(
         path: &Path,
@@ -105,6 +116,7 @@ impl Config {
             logs_dir: full_config.cluster_agent.logs_dir,
             logging: full_config.cluster_agent.logging,
             tls,
+            tracing: full_config.cluster_agent.tracing,
         })
     }
 
@@ -127,7 +139,10 @@ impl Config {
             .set_default("cluster-agent.logging.enabled", true)?
             .set_default("cluster-agent.logging.level", "info")?
             .set_default("cluster-agent.logging.format", "json")?
-            .set_default("cluster-agent.tls.enabled", false)
+            .set_default("cluster-agent.tls.enabled", false)?
+            .set_default("cluster-agent.tracing.enabled", false)?
+            .set_default("cluster-agent.tracing.endpoint", "localhost:4317")?
+            .set_default("cluster-agent.tracing.sampling-ratio", 1.0)
     }
 
     fn get_format(path: &Path) -> Result<FileFormat, Box<io::Error>> {
@@ -409,6 +424,9 @@ enabled = false
         assert!(config.logging.enabled);
         assert_eq!(config.logging.level, "info");
         assert_eq!(config.logging.format, "json");
+        assert!(!config.tracing.enabled);
+        assert_eq!(config.tracing.endpoint, "localhost:4317");
+        assert!((config.tracing.sampling_ratio - 1.0).abs() < f64::EPSILON);
     }
 
     #[tokio::test]
@@ -1,20 +1,38 @@
+use std::collections::BTreeMap;
 use std::fs;
 use std::path::PathBuf;
+use std::time::Duration;
 
 use chrono::{DateTime, Utc};
+use prost_types::Timestamp;
 use tokio::sync::mpsc::{self};
+use tokio::time::{Instant, timeout_at};
 use tokio_stream::wrappers::ReceiverStream;
 use tokio_util::sync::CancellationToken;
 use tokio_util::task::TaskTracker;
 use types::cluster_agent::log_records_service_server::LogRecordsService;
-use types::cluster_agent::{LogRecord, LogRecordsStreamRequest};
+use types::cluster_agent::{
+    FollowFrom, LogRecord, LogRecordBatch, LogRecordsCountBucket, LogRecordsCountRequest,
+    LogRecordsCountResponse, LogRecordsStreamRequest,
+};
 
+use rgkl::util::filter::LogFilter;
 use rgkl::{stream_backward, stream_forward};
 
 use tonic::{Request, Response, Status};
+use tracing::Instrument;
 
 use crate::authorizer::Authorizer;
 
+/// Maximum number of records in a batch.
+const MAX_BATCH_RECORDS: usize = 256;
+
+/// Maximum number of message bytes in a batch.
+const MAX_BATCH_BYTES: usize = 64 * 1024;
+
+/// Maximum time the first record in a batch waits before it's sent.
+const MAX_BATCH_LATENCY: Duration = Duration::from_millis(50);
+
 #[derive(Debug)]
 pub struct LogRecordsImpl {
     ctx: CancellationToken,
@@ -53,54 +71,65 @@ impl LogRecordsImpl {
             )
         })
     }
-}
 
-#[tonic::async_trait]
-impl LogRecordsService for LogRecordsImpl {
-    type StreamForwardStream = ReceiverStream<Result<LogRecord, Status>>;
-    type StreamBackwardStream = ReceiverStream<Result<LogRecord, Status>>;
+    fn get_log_filter(request: &LogRecordsStreamRequest) -> Result<Option<LogFilter>, Status> {
+        let Some(filter) = &request.filter else {
+            return Ok(None);
+        };
 
-    #[tracing::instrument]
-    async fn stream_backward(
+        LogFilter::new(filter).map_err(|e| {
+            Status::new(
+                tonic::Code::InvalidArgument,
+                format!("invalid filter: {}", e),
+            )
+        })
+    }
+
+    async fn spawn_stream_backward(
         &self,
         request: Request<LogRecordsStreamRequest>,
-    ) -> Result<Response<Self::StreamBackwardStream>, Status> {
+    ) -> Result<mpsc::Receiver<Result<LogRecord, Status>>, Status> {
         let authorizer = Authorizer::new(request.metadata()).await?;
         let request = request.into_inner();
         let file_path = self.get_log_filename(&request)?;
+        let filter = Self::get_log_filter(&request)?;
         let (tx, rx) = mpsc::channel(100);
         let local_ctx = self.ctx.child_token();
 
         let namespaces = vec![request.namespace.clone()];
         authorizer.is_authorized(&namespaces, "list").await?;
 
-        self.task_tracker.spawn(async move {
-            stream_backward::stream_backward(
-                local_ctx,
-                &file_path,
-                request.start_time.parse::<DateTime<Utc>>().ok(),
-                request.stop_time.parse::<DateTime<Utc>>().ok(),
-                if request.grep.is_empty() {
-                    None
-                } else {
-                    Some(&request.grep)
-                },
-                tx,
-            )
-            .await;
-        });
+        self.task_tracker.spawn(
+            async move {
+                stream_backward::stream_backward(
+                    local_ctx,
+                    &file_path,
+                    request.start_time.parse::<DateTime<Utc>>().ok(),
+                    request.stop_time.parse::<DateTime<Utc>>().ok(),
+                    if request.grep.is_empty() {
+                        None
+                    } else {
+                        Some(&request.grep)
+                    },
+                    filter.as_ref(),
+                    tx,
+                )
+                .await;
+            }
+            .in_current_span(),
+        );
 
-        Ok(Response::new(ReceiverStream::new(rx)))
+        Ok(rx)
     }
 
-    #[tracing::instrument]
-    async fn stream_forward(
+    async fn spawn_stream_forward(
         &self,
         request: Request<LogRecordsStreamRequest>,
-    ) -> Result<Response<Self::StreamForwardStream>, Status> {
+    ) -> Result<mpsc::Receiver<Result<LogRecord, Status>>, Status> {
         let authorizer = Authorizer::new(request.metadata()).await?;
         let request = request.into_inner();
         let file_path = self.get_log_filename(&request)?;
+        let filter = Self::get_log_filter(&request)?;
 
         let namespaces = vec![request.namespace.clone()];
         authorizer.is_authorized(&namespaces, "list").await?;
@@ -108,23 +137,338 @@ impl LogRecordsService for LogRecordsImpl {
         let (tx, rx) = mpsc::channel(100);
         let local_ctx = self.ctx.child_token();
 
-        self.task_tracker.spawn(async move {
-            stream_forward::stream_forward(
-                local_ctx,
-                &file_path,
-                request.start_time.parse::<DateTime<Utc>>().ok(),
-                request.stop_time.parse::<DateTime<Utc>>().ok(),
-                if request.grep.is_empty() {
-                    None
-                } else {
-                    Some(&request.grep)
-                },
-                request.follow_from(),
-                tx,
-            )
-            .await;
-        });
+        self.task_tracker.spawn(
+            async move {
+                stream_forward::stream_forward(
+                    local_ctx,
+                    &file_path,
+                    request.start_time.parse::<DateTime<Utc>>().ok(),
+                    request.stop_time.parse::<DateTime<Utc>>().ok(),
+                    if request.grep.is_empty() {
+                        None
+                    } else {
+                        Some(&request.grep)
+                    },
+                    filter.as_ref(),
+                    request.follow_from(),
+                    tx,
+                )
+                .await;
+            }
+            .in_current_span(),
+        );
+
+        Ok(rx)
+    }
+
+    fn spawn_batcher(
+        &self,
+        rx: mpsc::Receiver<Result<LogRecord, Status>>,
+    ) -> mpsc::Receiver<Result<LogRecordBatch, Status>> {
+        let (tx, batch_rx) = mpsc::channel(10);
+        self.task_tracker
+            .spawn(batch_records(rx, tx).in_current_span());
+        batch_rx
+    }
+}
+
+#[tonic::async_trait]
+impl LogRecordsService for LogRecordsImpl {
+    type StreamForwardStream = ReceiverStream<Result<LogRecord, Status>>;
+    type StreamBackwardStream = ReceiverStream<Result<LogRecord, Status>>;
+    type StreamForwardBatchStream = ReceiverStream<Result<LogRecordBatch, Status>>;
+    type StreamBackwardBatchStream = ReceiverStream<Result<LogRecordBatch, Status>>;
 
+    #[tracing::instrument]
+    async fn stream_backward(
+        &self,
+        request: Request<LogRecordsStreamRequest>,
+    ) -> Result<Response<Self::StreamBackwardStream>, Status> {
+        let rx = self.spawn_stream_backward(request).await?;
+        Ok(Response::new(ReceiverStream::new(rx)))
+    }
+
+    #[tracing::instrument]
+    async fn stream_forward(
+        &self,
+        request: Request<LogRecordsStreamRequest>,
+    ) -> Result<Response<Self::StreamForwardStream>, Status> {
+        let rx = self.spawn_stream_forward(request).await?;
         Ok(Response::new(ReceiverStream::new(rx)))
     }
+
+    #[tracing::instrument]
+    async fn stream_backward_batch(
+        &self,
+        request: Request<LogRecordsStreamRequest>,
+    ) -> Result<Response<Self::StreamBackwardBatchStream>, Status> {
+        let rx = self.spawn_stream_backward(request).await?;
+        Ok(Response::new(ReceiverStream::new(self.spawn_batcher(rx))))
+    }
+
+    #[tracing::instrument]
+    async fn stream_forward_batch(
+        &self,
+        request: Request<LogRecordsStreamRequest>,
+    ) -> Result<Response<Self::StreamForwardBatchStream>, Status> {
+        let rx = self.spawn_stream_forward(request).await?;
+        Ok(Response::new(ReceiverStream::new(self.spawn_batcher(rx))))
+    }
+
+    #[tracing::instrument]
+    async fn count(
+        &self,
+        request: Request<LogRecordsCountRequest>,
+    ) -> Result<Response<LogRecordsCountResponse>, Status> {
+        let authorizer = Authorizer::new(request.metadata()).await?;
+        let request = request.into_inner();
+        let bucket_seconds = request.bucket_seconds.max(0);
+
+        // Count the output of a non-following forward stream
+        let stream_request = LogRecordsStreamRequest {
+            namespace: request.namespace,
+            pod_name: request.pod_name,
+            container_name: request.container_name,
+            container_id: request.container_id,
+            start_time: request.start_time,
+            stop_time: request.stop_time,
+            grep: request.grep,
+            follow_from: FollowFrom::Noop as i32,
+            filter: request.filter,
+        };
+        let file_path = self.get_log_filename(&stream_request)?;
+        let filter = Self::get_log_filter(&stream_request)?;
+
+        let namespaces = vec![stream_request.namespace.clone()];
+        authorizer.is_authorized(&namespaces, "list").await?;
+
+        let (tx, mut rx) = mpsc::channel(100);
+        let local_ctx = self.ctx.child_token();
+
+        self.task_tracker.spawn(
+            async move {
+                stream_forward::stream_forward(
+                    local_ctx,
+                    &file_path,
+                    stream_request.start_time.parse::<DateTime<Utc>>().ok(),
+                    stream_request.stop_time.parse::<DateTime<Utc>>().ok(),
+                    if stream_request.grep.is_empty() {
+                        None
+                    } else {
+                        Some(&stream_request.grep)
+                    },
+                    filter.as_ref(),
+                    FollowFrom::Noop,
+                    tx,
+                )
+                .await;
+            }
+            .in_current_span(),
+        );
+
+        let mut counts: BTreeMap<i64, i64> = BTreeMap::new();
+        while let Some(result) = rx.recv().await {
+            let record = result?;
+
+            // Partial lines are counted once, on their final chunk
+            if !record.is_final {
+                continue;
+            }
+
+            let key = match &record.timestamp {
+                Some(ts) if bucket_seconds > 0 => {
+                    ts.seconds.div_euclid(bucket_seconds) * bucket_seconds
+                }
+                _ => 0,
+            };
+            *counts.entry(key).or_default() += 1;
+        }
+
+        let buckets = counts
+            .into_iter()
+            .map(|(start, count)| LogRecordsCountBucket {
+                start: (bucket_seconds > 0).then_some(Timestamp {
+                    seconds: start,
+                    nanos: 0,
+                }),
+                count,
+            })
+            .collect();
+
+        Ok(Response::new(LogRecordsCountResponse { buckets }))
+    }
+}
+
+/// Groups records from `rx` into batches and sends them to `tx`. A batch is
+/// sent when it reaches the record or byte limit, when its first record has
+/// waited for the latency limit or when `rx` closes. Errors are sent after
+/// flushing the pending batch and end the stream.
+async fn batch_records(
+    mut rx: mpsc::Receiver<Result<LogRecord, Status>>,
+    tx: mpsc::Sender<Result<LogRecordBatch, Status>>,
+) {
+    let mut records = Vec::new();
+    let mut num_bytes = 0;
+    let mut deadline: Option<Instant> = None;
+
+    loop {
+        let item = match deadline {
+            Some(at) => match timeout_at(at, rx.recv()).await {
+                Ok(item) => item,
+                Err(_) => {
+                    if !flush_batch(&tx, &mut records, &mut num_bytes).await {
+                        return;
+                    }
+                    deadline = None;
+                    continue;
+                }
+            },
+            None => rx.recv().await,
+        };
+
+        match item {
+            Some(Ok(record)) => {
+                num_bytes += record.message.len();
+                records.push(record);
+
+                let is_full = records.len() >= MAX_BATCH_RECORDS || num_bytes >= MAX_BATCH_BYTES;
+                if is_full && !flush_batch(&tx, &mut records, &mut num_bytes).await {
+                    return;
+                }
+            }
+            Some(Err(status)) => {
+                if flush_batch(&tx, &mut records, &mut num_bytes).await {
+                    let _ = tx.send(Err(status)).await;
+                }
+                return;
+            }
+            None => {
+                flush_batch(&tx, &mut records, &mut num_bytes).await;
+                return;
+            }
+        }
+
+        if records.is_empty() {
+            deadline = None;
+        } else if deadline.is_none() {
+            deadline = Some(Instant::now() + MAX_BATCH_LATENCY);
+        }
+    }
+}
+
+/// Sends pending records as a batch. Returns false if the receiver is gone.
+async fn flush_batch(
+    tx: &mpsc::Sender<Result<LogRecordBatch, Status>>,
+    records: &mut Vec<LogRecord>,
+    num_bytes: &mut usize,
+) -> bool {
+    if records.is_empty() {
+        return true;
+    }
+
+    *num_bytes = 0;
+    let batch = LogRecordBatch {
+        records: std::mem::take(records),
+    };
+    tx.send(Ok(batch)).await.is_ok()
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    fn new_record(message: &str) -> LogRecord {
+        LogRecord {
+            message: message.to_owned(),
+            is_final: true,
+            ..Default::default()
+        }
+    }
+
+    async fn collect_batches(
+        records: Vec<Result<LogRecord, Status>>,
+    ) -> Vec<Result<LogRecordBatch, Status>> {
+        let (tx, rx) = mpsc::channel(records.len().max(1));
+        let (batch_tx, mut batch_rx) = mpsc::channel(100);
+
+        for record in records {
+            tx.send(record).await.unwrap();
+        }
+        drop(tx);
+
+        batch_records(rx, batch_tx).await;
+
+        let mut batches = Vec::new();
+        while let Some(batch) = batch_rx.recv().await {
+            batches.push(batch);
+        }
+        batches
+    }
+
+    #[tokio::test]
+    async fn test_batch_records_flushes_on_close() {
+        let batches = collect_batches(vec![Ok(new_record("a")), Ok(new_record("b"))]).await;
+
+        assert_eq!(batches.len(), 1);
+        assert_eq!(batches[0].as_ref().unwrap().records.len(), 2);
+    }
+
+    #[tokio::test]
+    async fn test_batch_records_flushes_on_record_limit() {
+        let records = (0..MAX_BATCH_RECORDS + 1)
+            .map(|_| Ok(new_record("x")))
+            .collect();
+        let batches = collect_batches(records).await;
+
+        assert_eq!(batches.len(), 2);
+        assert_eq!(
+            batches[0].as_ref().unwrap().records.len(),
+            MAX_BATCH_RECORDS
+        );
+        assert_eq!(batches[1].as_ref().unwrap().records.len(), 1);
+    }
+
+    #[tokio::test]
+    async fn test_batch_records_flushes_on_byte_limit() {
+        let big = "x".repeat(MAX_BATCH_BYTES);
+        let batches = collect_batches(vec![Ok(new_record(&big)), Ok(new_record("a"))]).await;
+
+        assert_eq!(batches.len(), 2);
+        assert_eq!(batches[0].as_ref().unwrap().records.len(), 1);
+        assert_eq!(batches[1].as_ref().unwrap().records.len(), 1);
+    }
+
+    #[tokio::test]
+    async fn test_batch_records_sends_error_last() {
+        let batches = collect_batches(vec![
+            Ok(new_record("a")),
+            Err(Status::internal("boom")),
+            Ok(new_record("b")),
+        ])
+        .await;
+
+        assert_eq!(batches.len(), 2);
+        assert_eq!(batches[0].as_ref().unwrap().records.len(), 1);
+        assert_eq!(batches[1].as_ref().unwrap_err().message(), "boom");
+    }
+
+    #[tokio::test]
+    async fn test_batch_records_flushes_on_latency_limit() {
+        let (tx, rx) = mpsc::channel(10);
+        let (batch_tx, mut batch_rx) = mpsc::channel(10);
+
+        tokio::spawn(batch_records(rx, batch_tx));
+        tx.send(Ok(new_record("a"))).await.unwrap();
+
+        // Batch arrives while the input is still open
+        let batch = tokio::time::timeout(MAX_BATCH_LATENCY * 10, batch_rx.recv())
+            .await
+            .expect("batch not flushed")
+            .unwrap()
+            .unwrap();
+        assert_eq!(batch.records.len(), 1);
+
+        drop(tx);
+        assert!(batch_rx.recv().await.is_none());
+    }
 }
@@ -4,12 +4,17 @@ use std::path::PathBuf;
 use std::str::FromStr;
 
 use clap::{ArgAction, arg, command, value_parser};
+use opentelemetry_sdk::trace::SdkTracer;
 use tokio::signal::ctrl_c;
 use tokio::signal::unix::{SignalKind, signal};
 use tokio_util::sync::CancellationToken;
 use tokio_util::task::TaskTracker;
 use tonic::transport::{Certificate, Identity, Server, ServerTlsConfig};
-use tracing::info;
+use tracing::{info, warn};
+use tracing_subscriber::Layer;
+use tracing_subscriber::filter::LevelFilter;
+use tracing_subscriber::layer::SubscriberExt;
+use tracing_subscriber::util::SubscriberInitExt;
 use types::cluster_agent::FILE_DESCRIPTOR_SET;
 use types::cluster_agent::log_metadata_service_server::LogMetadataServiceServer;
 use types::cluster_agent::log_records_service_server::LogRecordsServiceServer;
@@ -18,6 +23,7 @@ mod authorizer;
 mod config;
 mod log_metadata;
 mod log_records;
+mod otel;
 use log_metadata::LogMetadataImpl;
 use log_records::LogRecordsImpl;
 
@@ -27,7 +33,12 @@ use crate::config::{Config, LoggingConfig, TlsConfig};
 async fn main() -> Result<(), Box<dyn Error>> {
     let config = parse_config().await?;
 
-    configure_logging(&config.logging)?;
+    let tracer_provider = otel::init_tracer(&config.tracing)?;
+
+    configure_logging(
+        &config.logging,
+        tracer_provider.as_ref().map(|(_, tracer)| tracer.clone()),
+    )?;
 
     let (_, agent_health_service) = tonic_health::server::health_reporter();
     let reflection_service = tonic_reflection::server::Builder::configure()
@@ -36,7 +47,7 @@ async fn main() -> Result<(), Box<dyn Error>> {
     let task_tracker = TaskTracker::new();
     let root_ctx = CancellationToken::new();
 
-    let mut server = enable_tls(Server::builder(), &config.tls)?;
+    let mut server = enable_tls(Server::builder().trace_fn(otel::server_span), &config.tls)?;
 
     info!("Starting cluster-agent on {}", config.address);
 
@@ -59,6 +70,13 @@ async fn main() -> Result<(), Box<dyn Error>> {
     task_tracker.close();
     task_tracker.wait().await;
 
+    #[allow(clippy::collapsible_if)]
+    if let Some((provider, _)) = tracer_provider {
+        if let Err(e) = provider.shutdown() {
+            warn!("Failed to flush spans: {}", e);
+        }
+    }
+
     info!("Shutdown completed.");
 
     Ok(())
@@ -147,19 +165,30 @@ fn enable_tls(server: Server, tls_config: &TlsConfig) -> Result<Server, Box<dyn
     server.tls_config(server_tls_config).map_err(Into::into)
 }
 
-fn configure_logging(logging_config: &LoggingConfig) -> Result<(), Box<dyn Error>> {
-    if !logging_config.enabled {
-        return Ok(());
-    }
+fn configure_logging(
+    logging_config: &LoggingConfig,
+    tracer: Option<SdkTracer>,
+) -> Result<(), Box<dyn Error>> {
+    let fmt_layer = if logging_config.enabled {
+        let level = LevelFilter::from_level(tracing::Level::from_str(&logging_config.level)?);
+        let layer = tracing_subscriber::fmt::layer();
+
+        if logging_config.format == "pretty" {
+            Some(layer.pretty().with_filter(level).boxed())
+        } else {
+            Some(layer.json().with_filter(level).boxed())
+        }
+    } else {
+        None
+    };
 
-    let sub_builder =
-        tracing_subscriber::fmt().with_max_level(tracing::Level::from_str(&logging_config.level)?);
+    // Spans are exported regardless of the log level
+    let otel_layer = tracer.map(|tracer| tracing_opentelemetry::layer().with_tracer(tracer));
 
-    if logging_config.format == "pretty" {
-        sub_builder.pretty().init();
-    } else {
-        sub_builder.json().init();
-    }
+    tracing_subscriber::registry()
+        .with(fmt_layer)
+        .with(otel_layer)
+        .init();
 
     Ok(())
 }
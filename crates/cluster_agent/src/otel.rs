@@ -0,0 +1,115 @@
+use std::error::Error;
+
+use opentelemetry::global;
+use opentelemetry::propagation::Extractor;
+use opentelemetry::trace::TracerProvider;
+use opentelemetry_otlp::{SpanExporter, WithExportConfig};
+use opentelemetry_sdk::Resource;
+use opentelemetry_sdk::propagation::TraceContextPropagator;
+use opentelemetry_sdk::trace::{Sampler, SdkTracer, SdkTracerProvider};
+use tonic::codegen::http::{HeaderMap, Request};
+use tracing::{Span, info_span};
+use tracing_opentelemetry::OpenTelemetrySpanExt;
+
+use crate::config::TracingConfig;
+
+const SERVICE_NAME: &str = "kubetail-cluster-agent";
+
+/// Reads trace context from gRPC request headers.
+struct HeaderExtractor<'a>(&'a HeaderMap);
+
+impl Extractor for HeaderExtractor<'_> {
+    fn get(&self, key: &str) -> Option<&str> {
+        self.0.get(key).and_then(|value| value.to_str().ok())
+    }
+
+    fn keys(&self) -> Vec<&str> {
+        self.0.keys().map(|key| key.as_str()).collect()
+    }
+}
+
+/// Sets up trace context propagation and returns a tracer provider if
+/// tracing is enabled. Trace context is extracted from requests even when
+/// tracing is disabled so log lines can be correlated with callers.
+pub fn init_tracer(
+    tracing_config: &TracingConfig,
+) -> Result<Option<(SdkTracerProvider, SdkTracer)>, Box<dyn Error>> {
+    global::set_text_map_propagator(TraceContextPropagator::new());
+
+    if !tracing_config.enabled {
+        return Ok(None);
+    }
+
+    let exporter = SpanExporter::builder()
+        .with_tonic()
+        .with_endpoint(format!("http://{}", tracing_config.endpoint))
+        .build()?;
+
+    // Child spans follow the sampling decision of the caller
+    let sampler = Sampler::ParentBased(Box::new(Sampler::TraceIdRatioBased(
+        tracing_config.sampling_ratio,
+    )));
+
+    let provider = SdkTracerProvider::builder()
+        .with_batch_exporter(exporter)
+        .with_sampler(sampler)
+        .with_resource(Resource::builder().with_service_name(SERVICE_NAME).build())
+        .build();
+
+    let tracer = provider.tracer(SERVICE_NAME);
+
+    Ok(Some((provider, tracer)))
+}
+
+/// Returns the server span for a gRPC request. The span continues the trace
+/// started by the caller (i.e. cluster-api) if the request has a
+/// `traceparent` header.
+pub fn server_span<B>(request: &Request<B>) -> Span {
+    let parent_ctx = global::get_text_map_propagator(|propagator| {
+        propagator.extract(&HeaderExtractor(request.headers()))
+    });
+
+    let span = info_span!(
+        "grpc.request",
+        otel.name = request.uri().path(),
+        otel.kind = "server",
+        rpc.system = "grpc",
+    );
+    let _ = span.set_parent(parent_ctx);
+
+    span
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use opentelemetry::trace::TraceContextExt;
+    use tracing_subscriber::layer::SubscriberExt;
+
+    #[test]
+    fn test_server_span_continues_caller_trace() {
+        global::set_text_map_propagator(TraceContextPropagator::new());
+
+        let provider = SdkTracerProvider::builder().build();
+        let subscriber = tracing_subscriber::registry()
+            .with(tracing_opentelemetry::layer().with_tracer(provider.tracer("test")));
+        let _guard = tracing::subscriber::set_default(subscriber);
+
+        let request = Request::builder()
+            .uri("/cluster_agent.LogRecordsService/StreamForward")
+            .header(
+                "traceparent",
+                "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+            )
+            .body(())
+            .expect("Failed to build request");
+
+        let span = server_span(&request);
+        let span_ctx = span.context().span().span_context().clone();
+
+        assert_eq!(
+            span_ctx.trace_id().to_string(),
+            "4bf92f3577b34da6a3ce929d0e0e4736"
+        );
+    }
+}
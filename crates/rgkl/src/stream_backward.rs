@@ -24,8 +24,9 @@ use grep::printer::JSONBuilder;
 use grep::searcher::{MmapChoice, SearcherBuilder};
 
 use crate::fs_watcher_error::FsWatcherError;
+use crate::util::filter::LogFilter;
 use crate::util::format::FileFormat;
-use crate::util::matcher::{LogFileRegexMatcher, PassThroughMatcher};
+use crate::util::matcher::{LogFileFilterMatcher, LogFileRegexMatcher, PassThroughMatcher};
 use crate::util::offset::{find_nearest_offset_since, find_nearest_offset_until};
 use crate::util::reader::{ReverseLineReader, TermReader};
 use crate::util::writer::{process_output, CallbackWriter};
@@ -36,9 +37,10 @@ pub async fn stream_backward(
     start_time: Option<DateTime<Utc>>,
     stop_time: Option<DateTime<Utc>>,
     grep: Option<&str>,
+    filter: Option<&LogFilter>,
     sender: Sender<Result<LogRecord, Status>>,
 ) {
-    let result = stream_backward_internal(ctx, path, start_time, stop_time, grep, &sender);
+    let result = stream_backward_internal(ctx, path, start_time, stop_time, grep, filter, &sender);
 
     if let Err(error) = result {
         let _ = sender.send(Err(error.into())).await;
@@ -51,6 +53,7 @@ fn stream_backward_internal(
     start_time: Option<DateTime<Utc>>,
     stop_time: Option<DateTime<Utc>>,
     grep: Option<&str>,
+    filter: Option<&LogFilter>,
     sender: &Sender<Result<LogRecord, Status>>,
 ) -> Result<(), FsWatcherError> {
     // Open file
@@ -110,7 +113,11 @@ fn stream_backward_internal(
     // Remove leading and trailing whitespace
     let trimmed_grep = grep.map(str::trim).filter(|grep| !grep.is_empty());
 
-    if let Some(grep) = trimmed_grep {
+    if let Some(filter) = filter {
+        let matcher = LogFileFilterMatcher::new(trimmed_grep, filter, format).unwrap();
+        let sink = printer.sink(&matcher);
+        let _ = searcher.search_reader(&matcher, term_reverse_reader, sink);
+    } else if let Some(grep) = trimmed_grep {
         let matcher = LogFileRegexMatcher::new(grep, format).unwrap();
         let sink = printer.sink(&matcher);
         let _ = searcher.search_reader(&matcher, term_reverse_reader, sink);
@@ -129,6 +136,7 @@ mod test {
     use std::{io::Write, sync::LazyLock};
     use tempfile::NamedTempFile;
     use tokio::sync::mpsc;
+    use types::cluster_agent::LogRecordsFilter;
 
     use super::*;
 
@@ -199,7 +207,16 @@ mod test {
         // Create output channel
         let (tx, mut rx) = mpsc::channel(100);
 
-        stream_backward(CancellationToken::new(), &path, start_time, None, None, tx).await;
+        stream_backward(
+            CancellationToken::new(),
+            &path,
+            start_time,
+            None,
+            None,
+            None,
+            tx,
+        )
+        .await;
 
         // Create a buffer to capture output
         let mut output = Vec::new();
@@ -237,7 +254,16 @@ mod test {
         // Create output channel
         let (tx, mut rx) = mpsc::channel(100);
 
-        stream_backward(CancellationToken::new(), &path, None, stop_time, None, tx).await;
+        stream_backward(
+            CancellationToken::new(),
+            &path,
+            None,
+            stop_time,
+            None,
+            None,
+            tx,
+        )
+        .await;
 
         // Create a buffer to capture output
         let mut output = Vec::new();
@@ -290,6 +316,51 @@ mod test {
             start_time,
             stop_time,
             None,
+            None,
+            tx,
+        )
+        .await;
+
+        // Create a buffer to capture output
+        let mut output = Vec::new();
+
+        while let Some(record) = rx.recv().await {
+            output.push(record);
+        }
+
+        // Compare output with expected lines
+        compare_lines(output, expected_lines);
+    }
+
+    // Test `grep` and `filter` args together
+    #[tokio::test(flavor = "multi_thread")]
+    #[rstest]
+    #[case("", vec!["linenum [2-9]"], vec!["linenum 10", "linenum 1"])]
+    #[case("linenum 1", vec!["linenum 1$"], vec!["linenum 10"])]
+    #[case("", vec!["linenum"], vec![])]
+    async fn test_filter(
+        #[case] grep: &str,
+        #[case] exclude: Vec<&str>,
+        #[case] expected_lines: Vec<&'static str>,
+    ) {
+        let path = TEST_FILE.path().to_path_buf();
+
+        let filter = LogFilter::new(&LogRecordsFilter {
+            exclude: exclude.into_iter().map(String::from).collect(),
+            ..Default::default()
+        })
+        .unwrap();
+
+        // Create output channel
+        let (tx, mut rx) = mpsc::channel(100);
+
+        stream_backward(
+            CancellationToken::new(),
+            &path,
+            None,
+            None,
+            Some(grep),
+            filter.as_ref(),
             tx,
         )
         .await;
@@ -312,7 +383,7 @@ mod test {
         // Create output channel
         let (tx, mut rx) = mpsc::channel(100);
 
-        stream_backward(CancellationToken::new(), &path, None, None, None, tx).await;
+        stream_backward(CancellationToken::new(), &path, None, None, None, None, tx).await;
 
         let result = rx.recv().await.unwrap();
         assert!(matches!(result, Err(_)));
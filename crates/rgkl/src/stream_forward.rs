@@ -28,8 +28,9 @@ use tonic::Status;
 use types::cluster_agent::{FollowFrom, LogRecord};
 
 use crate::fs_watcher_error::FsWatcherError;
+use crate::util::filter::LogFilter;
 use crate::util::format::FileFormat;
-use crate::util::matcher::{LogFileRegexMatcher, PassThroughMatcher};
+use crate::util::matcher::{LogFileFilterMatcher, LogFileRegexMatcher, PassThroughMatcher};
 use crate::util::offset::{find_nearest_offset_since, find_nearest_offset_until};
 use crate::util::reader::TermReader;
 use crate::util::writer::{process_output, CallbackWriter};
@@ -64,12 +65,14 @@ where
     _notify_watcher: RecommendedWatcher,
 }
 
+#[allow(clippy::too_many_arguments)]
 pub async fn stream_forward(
     ctx: CancellationToken,
     path: &PathBuf,
     start_time: Option<DateTime<Utc>>,
     stop_time: Option<DateTime<Utc>>,
     grep: Option<&str>,
+    filter: Option<&LogFilter>,
     follow_from: FollowFrom,
     sender: Sender<Result<LogRecord, Status>>,
 ) {
@@ -79,6 +82,7 @@ pub async fn stream_forward(
         start_time,
         stop_time,
         grep,
+        filter,
         follow_from,
         sender,
         None,
@@ -93,6 +97,7 @@ async fn stream_forward_with_lifecyle_events(
     start_time: Option<DateTime<Utc>>,
     stop_time: Option<DateTime<Utc>>,
     grep: Option<&str>,
+    filter: Option<&LogFilter>,
     follow_from: FollowFrom,
     sender: Sender<Result<LogRecord, Status>>,
     lifecycle_tx: Option<broadcast::Sender<LifecycleEvent>>,
@@ -103,6 +108,7 @@ async fn stream_forward_with_lifecyle_events(
         start_time,
         stop_time,
         grep,
+        filter,
         follow_from,
         &sender,
     );
@@ -120,12 +126,14 @@ async fn stream_forward_with_lifecyle_events(
 
 type ResultOption<T, E> = Result<Option<T>, E>;
 
+#[allow(clippy::too_many_arguments)]
 fn setup_fs_watcher<'a>(
     ctx: CancellationToken,
     path: &PathBuf,
     start_time: Option<DateTime<Utc>>,
     stop_time: Option<DateTime<Utc>>,
     grep: Option<&'a str>,
+    filter: Option<&'a LogFilter>,
     follow_from: FollowFrom,
     sender: &'a Sender<Result<LogRecord, Status>>,
 ) -> ResultOption<FsWatcher<impl FnMut(&[u8]) + use<'a>>, FsWatcherError> {
@@ -198,7 +206,11 @@ fn setup_fs_watcher<'a>(
     // Remove leading and trailing whitespace
     let trimmed_grep = grep.map(str::trim).filter(|grep| !grep.is_empty());
 
-    if let Some(grep) = trimmed_grep {
+    if let Some(filter) = filter {
+        let matcher = LogFileFilterMatcher::new(trimmed_grep, filter, format).unwrap();
+        let sink = printer.sink(&matcher);
+        let _ = searcher.search_reader(&matcher, term_reader, sink);
+    } else if let Some(grep) = trimmed_grep {
         let matcher = LogFileRegexMatcher::new(grep, format).unwrap();
         let sink = printer.sink(&matcher);
         let _ = searcher.search_reader(&matcher, term_reader, sink);
@@ -221,7 +233,11 @@ fn setup_fs_watcher<'a>(
     }
 
     let search_slice = move |input_str: &[u8]| {
-        if let Some(grep) = trimmed_grep {
+        if let Some(filter) = filter {
+            let matcher = LogFileFilterMatcher::new(trimmed_grep, filter, format).unwrap();
+            let sink = printer.sink(&matcher);
+            let _ = searcher.search_slice(&matcher, input_str, sink);
+        } else if let Some(grep) = trimmed_grep {
             let matcher = LogFileRegexMatcher::new(grep, format).unwrap();
             let sink = printer.sink(&matcher);
             let _ = searcher.search_slice(&matcher, input_str, sink);
@@ -418,6 +434,7 @@ mod test {
             start_time,
             None,             // No stop time
             None,             // No grep filter
+            None,             // No structured filter
             FollowFrom::Noop, // Don't follow
             tx,
         )
@@ -466,6 +483,7 @@ mod test {
             None, // No start time
             stop_time,
             None,             // No grep filter
+            None,             // No structured filter
             FollowFrom::Noop, // Don't follow
             tx,
         )
@@ -523,6 +541,7 @@ mod test {
             start_time,
             stop_time,
             None,             // No grep filter
+            None,             // No structured filter
             FollowFrom::Noop, // Don't follow
             tx,
         )
@@ -586,6 +605,7 @@ mod test {
                 start_time,
                 None, // No stop time
                 None, // No grep filter
+                None, // No structured filter
                 follow_from,
                 tx,
                 Some(lifecycle_tx_clone),
@@ -632,6 +652,7 @@ mod test {
             None,
             None,
             None,             // No grep filter
+            None,             // No structured filter
             FollowFrom::Noop, // Don't follow
             tx,
         )
@@ -671,6 +692,7 @@ mod test {
                 None,            // No start time
                 None,            // No stop time
                 None,            // No grep filter
+                None,            // No structured filter
                 FollowFrom::End, // Enter listen loop immediately
                 tx,
                 Some(lifecycle_tx_clone),
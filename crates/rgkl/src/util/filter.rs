@@ -0,0 +1,330 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+use std::collections::{HashMap, HashSet};
+
+use regex::Regex;
+use serde_json::{Map, Value};
+use types::cluster_agent::{FieldOperator, LogRecordsFilter};
+
+/// Field names checked (in order) for a line's level
+const LEVEL_FIELD_NAMES: [&str; 4] = ["level", "lvl", "severity", "loglevel"];
+
+/// A compiled field predicate
+struct FieldCheck {
+    field: String,
+    op: FieldOperator,
+    value: String,
+    regex: Option<Regex>,
+}
+
+/// Structured filter evaluated against the message of each log line
+pub struct LogFilter {
+    fields: Vec<FieldCheck>,
+    exclude: Vec<Regex>,
+    levels: HashSet<String>,
+}
+
+/// Fields parsed from a JSON or logfmt line
+enum Fields {
+    Json(Map<String, Value>),
+    Logfmt(HashMap<String, String>),
+    None,
+}
+
+impl LogFilter {
+    /// Compiles the filter. Returns None if the filter is empty.
+    pub fn new(filter: &LogRecordsFilter) -> Result<Option<Self>, regex::Error> {
+        if filter.fields.is_empty() && filter.exclude.is_empty() && filter.levels.is_empty() {
+            return Ok(None);
+        }
+
+        let mut fields = Vec::with_capacity(filter.fields.len());
+        for pred in &filter.fields {
+            let op = pred.op();
+            let regex = if op == FieldOperator::Matches {
+                Some(Regex::new(&pred.value)?)
+            } else {
+                None
+            };
+            fields.push(FieldCheck {
+                field: pred.field.clone(),
+                op,
+                value: pred.value.clone(),
+                regex,
+            });
+        }
+
+        let exclude = filter
+            .exclude
+            .iter()
+            .map(|pattern| Regex::new(pattern))
+            .collect::<Result<Vec<_>, _>>()?;
+
+        let levels = filter.levels.iter().map(|l| normalize_level(l)).collect();
+
+        Ok(Some(Self {
+            fields,
+            exclude,
+            levels,
+        }))
+    }
+
+    /// Returns true if the message passes the filter
+    pub fn is_match(&self, msg: &[u8]) -> bool {
+        let msg = String::from_utf8_lossy(msg);
+        let msg = msg.trim();
+
+        if self.exclude.iter().any(|regex| regex.is_match(msg)) {
+            return false;
+        }
+
+        if self.fields.is_empty() && self.levels.is_empty() {
+            return true;
+        }
+
+        let fields = parse_fields(msg);
+
+        if !self.levels.is_empty() {
+            let level = LEVEL_FIELD_NAMES
+                .iter()
+                .find_map(|name| fields.lookup(name));
+            match level {
+                Some(level) if self.levels.contains(&normalize_level(&level)) => {}
+                _ => return false,
+            }
+        }
+
+        self.fields.iter().all(|check| {
+            let value = fields.lookup(&check.field);
+            match check.op {
+                FieldOperator::Equals => value.is_some_and(|v| v == check.value),
+                FieldOperator::NotEquals => value.is_none_or(|v| v != check.value),
+                FieldOperator::Contains => value.is_some_and(|v| v.contains(&check.value)),
+                FieldOperator::Matches => value
+                    .is_some_and(|v| check.regex.as_ref().is_some_and(|regex| regex.is_match(&v))),
+                FieldOperator::Exists => value.is_some(),
+            }
+        })
+    }
+}
+
+impl Fields {
+    /// Returns string representation of field. Dots in the name descend into
+    /// nested objects unless the name itself is present.
+    fn lookup(&self, name: &str) -> Option<String> {
+        match self {
+            Fields::Json(obj) => {
+                if let Some(v) = obj.get(name) {
+                    return Some(value_string(v));
+                }
+
+                let mut parts = name.split('.');
+                let mut current = obj.get(parts.next()?)?;
+                for part in parts {
+                    current = current.as_object()?.get(part)?;
+                }
+                Some(value_string(current))
+            }
+            Fields::Logfmt(map) => map.get(name).cloned(),
+            Fields::None => None,
+        }
+    }
+}
+
+/// Parses fields of a JSON or logfmt line
+fn parse_fields(msg: &str) -> Fields {
+    if msg.starts_with('{') {
+        if let Ok(obj) = serde_json::from_str::<Map<String, Value>>(msg) {
+            return Fields::Json(obj);
+        }
+    }
+
+    let map = parse_logfmt(msg);
+    if map.is_empty() {
+        Fields::None
+    } else {
+        Fields::Logfmt(map)
+    }
+}
+
+/// Parses `key=value` pairs from a logfmt line. Bare keys are ignored.
+fn parse_logfmt(line: &str) -> HashMap<String, String> {
+    let mut fields = HashMap::new();
+    let bytes = line.as_bytes();
+    let mut i = 0;
+
+    while i < bytes.len() {
+        // Skip whitespace
+        while i < bytes.len() && bytes[i] == b' ' {
+            i += 1;
+        }
+
+        // Read key
+        let start = i;
+        while i < bytes.len() && bytes[i] != b' ' && bytes[i] != b'=' {
+            i += 1;
+        }
+        let key = &line[start..i];
+
+        if i >= bytes.len() || bytes[i] != b'=' {
+            continue;
+        }
+        i += 1;
+
+        // Read value
+        let value = if i < bytes.len() && bytes[i] == b'"' {
+            let mut buf = Vec::new();
+            i += 1;
+            while i < bytes.len() && bytes[i] != b'"' {
+                if bytes[i] == b'\\' && i + 1 < bytes.len() {
+                    i += 1;
+                }
+                buf.push(bytes[i]);
+                i += 1;
+            }
+            i += 1;
+            String::from_utf8_lossy(&buf).into_owned()
+        } else {
+            let start = i;
+            while i < bytes.len() && bytes[i] != b' ' {
+                i += 1;
+            }
+            line[start..i].to_string()
+        };
+
+        if key.is_empty() || key.contains('"') {
+            continue;
+        }
+
+        fields.entry(key.to_string()).or_insert(value);
+    }
+
+    fields
+}
+
+/// Returns string representation of JSON value
+fn value_string(v: &Value) -> String {
+    match v {
+        Value::String(s) => s.clone(),
+        other => other.to_string(),
+    }
+}
+
+/// Returns lower-case level with common aliases collapsed
+fn normalize_level(level: &str) -> String {
+    let level = level.trim().to_lowercase();
+    match level.as_str() {
+        "warning" => "warn".to_string(),
+        "err" => "error".to_string(),
+        _ => level,
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    use rstest::rstest;
+    use types::cluster_agent::FieldPredicate;
+
+    const JSON_LINE: &str = r#"{"level":"warning","msg":"request timeout","status":504,"user":{"id":42},"http.method":"GET"}"#;
+    const LOGFMT_LINE: &str =
+        r#"ts=2025-01-01T00:00:00Z level=ERROR msg="connection reset by peer" status=500"#;
+    const PLAIN_LINE: &str = "listening on :8080";
+
+    fn predicate(field: &str, op: FieldOperator, value: &str) -> FieldPredicate {
+        FieldPredicate {
+            field: field.to_string(),
+            op: op as i32,
+            value: value.to_string(),
+        }
+    }
+
+    #[test]
+    fn test_empty_filter() {
+        let filter = LogFilter::new(&LogRecordsFilter::default()).unwrap();
+        assert!(filter.is_none());
+    }
+
+    #[test]
+    fn test_invalid_pattern() {
+        let filter = LogRecordsFilter {
+            exclude: vec!["(".to_string()],
+            ..Default::default()
+        };
+        assert!(LogFilter::new(&filter).is_err());
+    }
+
+    #[rstest]
+    #[case("status", FieldOperator::Equals, "504", JSON_LINE, true)]
+    #[case("status", FieldOperator::Equals, "500", JSON_LINE, false)]
+    #[case("user.id", FieldOperator::Equals, "42", JSON_LINE, true)]
+    #[case("http.method", FieldOperator::Equals, "GET", JSON_LINE, true)]
+    #[case("msg", FieldOperator::Contains, "timeout", JSON_LINE, true)]
+    #[case("msg", FieldOperator::Matches, "^request", JSON_LINE, true)]
+    #[case("user", FieldOperator::Equals, r#"{"id":42}"#, JSON_LINE, true)]
+    #[case("trace_id", FieldOperator::Exists, "", JSON_LINE, false)]
+    #[case(
+        "msg",
+        FieldOperator::Equals,
+        "connection reset by peer",
+        LOGFMT_LINE,
+        true
+    )]
+    #[case("status", FieldOperator::Equals, "500", LOGFMT_LINE, true)]
+    #[case("status", FieldOperator::NotEquals, "500", LOGFMT_LINE, false)]
+    #[case("status", FieldOperator::NotEquals, "500", PLAIN_LINE, true)]
+    #[case("msg", FieldOperator::Exists, "", PLAIN_LINE, false)]
+    fn test_field_predicates(
+        #[case] field: &str,
+        #[case] op: FieldOperator,
+        #[case] value: &str,
+        #[case] msg: &str,
+        #[case] expected: bool,
+    ) {
+        let filter = LogRecordsFilter {
+            fields: vec![predicate(field, op, value)],
+            ..Default::default()
+        };
+        let filter = LogFilter::new(&filter).unwrap().unwrap();
+        assert_eq!(filter.is_match(msg.as_bytes()), expected);
+    }
+
+    #[rstest]
+    #[case(vec!["warn"], JSON_LINE, true)]
+    #[case(vec!["error", "fatal"], LOGFMT_LINE, true)]
+    #[case(vec!["error"], JSON_LINE, false)]
+    #[case(vec!["info"], PLAIN_LINE, false)]
+    fn test_levels(#[case] levels: Vec<&str>, #[case] msg: &str, #[case] expected: bool) {
+        let filter = LogRecordsFilter {
+            levels: levels.into_iter().map(String::from).collect(),
+            ..Default::default()
+        };
+        let filter = LogFilter::new(&filter).unwrap().unwrap();
+        assert_eq!(filter.is_match(msg.as_bytes()), expected);
+    }
+
+    #[rstest]
+    #[case("timeout", JSON_LINE, false)]
+    #[case("healthz", PLAIN_LINE, true)]
+    fn test_exclude(#[case] pattern: &str, #[case] msg: &str, #[case] expected: bool) {
+        let filter = LogRecordsFilter {
+            exclude: vec![pattern.to_string()],
+            ..Default::default()
+        };
+        let filter = LogFilter::new(&filter).unwrap().unwrap();
+        assert_eq!(filter.is_match(msg.as_bytes()), expected);
+    }
+}
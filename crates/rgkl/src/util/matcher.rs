@@ -17,6 +17,7 @@ use grep::regex::{self, RegexMatcher, RegexMatcherBuilder};
 use memchr::memmem;
 use serde::Deserialize;
 
+use crate::util::filter::LogFilter;
 use crate::util::format::FileFormat;
 
 // PassThroughMatcher
@@ -119,6 +120,65 @@ impl LogFileRegexMatcher {
     }
 }
 
+// LogFileFilterMatcher
+pub struct LogFileFilterMatcher<'a> {
+    grep: Option<LogFileRegexMatcher>,
+    filter: &'a LogFilter,
+    format: FileFormat,
+}
+
+impl<'a> LogFileFilterMatcher<'a> {
+    pub fn new(
+        grep: Option<&str>,
+        filter: &'a LogFilter,
+        format: FileFormat,
+    ) -> Result<LogFileFilterMatcher<'a>, regex::Error> {
+        let grep = grep
+            .map(|pattern| LogFileRegexMatcher::new(pattern, format))
+            .transpose()?;
+
+        Ok(LogFileFilterMatcher {
+            grep,
+            filter,
+            format,
+        })
+    }
+}
+
+impl Matcher for LogFileFilterMatcher<'_> {
+    type Captures = matcher::NoCaptures;
+    type Error = matcher::NoError;
+
+    fn find_at(&self, haystack: &[u8], start: usize) -> Result<Option<Match>, Self::Error> {
+        // We can ignore haystacks with multiple messages
+        if start > 0 {
+            return Ok(None);
+        }
+
+        // Check grep first since it doesn't need to parse the message
+        if let Some(grep) = &self.grep {
+            if grep.find_at(haystack, start)?.is_none() {
+                return Ok(None);
+            }
+        }
+
+        let is_match = match self.format {
+            FileFormat::Docker => {
+                extract_message_docker(haystack).is_some_and(|msg| self.filter.is_match(&msg))
+            }
+            FileFormat::CRI => {
+                extract_message_cri(haystack).is_some_and(|msg| self.filter.is_match(msg))
+            }
+        };
+
+        Ok(is_match.then(|| Match::new(start, haystack.len())))
+    }
+
+    fn new_captures(&self) -> Result<Self::Captures, Self::Error> {
+        Ok(matcher::NoCaptures::new())
+    }
+}
+
 // Extract <message> from docker format
 pub fn extract_message_docker(line: &[u8]) -> Option<Vec<u8>> {
     /// A helper struct that only deserializes the top-level "log" field.
@@ -12,6 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+pub mod filter;
 pub mod format;
 pub mod matcher;
 pub mod offset;
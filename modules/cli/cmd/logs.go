@@ -19,6 +19,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"strings"
@@ -26,16 +27,19 @@ import (
 	"text/template"
 	"time"
 
-	"github.com/sosodev/duration"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
 	"github.com/kubetail-org/kubetail/modules/shared/config"
+	"github.com/kubetail-org/kubetail/modules/shared/forward"
 	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
 	"github.com/kubetail-org/kubetail/modules/shared/logs"
+	"github.com/kubetail-org/kubetail/modules/shared/permalink"
 
 	"github.com/kubetail-org/kubetail/modules/cli/internal/cli"
 	"github.com/kubetail-org/kubetail/modules/cli/internal/tablewriter"
+	"github.com/kubetail-org/kubetail/modules/shared/util"
 )
 
 type logsStreamMode int
@@ -75,6 +79,15 @@ Examples:
 		# Tail 'web' deployment in the 'frontend' namespace
 		{{.CommandDisplayName}} frontend:deployments/web
 
+		# Tail the pods behind the 'web' service
+		{{.CommandDisplayName}} services/web
+
+		# Tail only the pods that are currently serving traffic for the 'web' service
+		{{.CommandDisplayName}} services/web --serving-only
+
+		# Tail the pods behind the backend services of the 'web' ingress
+		{{.CommandDisplayName}} ingresses/web
+
 		# Tail multiple sources
 		{{.CommandDisplayName}} <source1> <source2>
 
@@ -138,6 +151,20 @@ Examples:
 		# Stream new records that match "GET /about"
 		{{.CommandDisplayName}} nginx --grep "GET /about" --follow --force
 
+	- Structured filters (requires --force)
+
+		# Return last 10 JSON or logfmt records with level "error"
+		{{.CommandDisplayName}} deployments/web --level error --force
+
+		# Return last 10 records where the "status" field is 500
+		{{.CommandDisplayName}} deployments/web --field status=500 --force
+
+		# Stream new records where "user.id" is not 42 and "path" starts with "/api"
+		{{.CommandDisplayName}} deployments/web --field 'user.id!=42' --field 'path=~^/api' --follow --force
+
+		# Stream new records that have a "trace_id" field, excluding health checks
+		{{.CommandDisplayName}} deployments/web --field trace_id --exclude healthz --follow --force
+
 	- Source filters
 
 		# Tail 'web' deployment pods in 'us-east-1'
@@ -155,6 +182,44 @@ Examples:
 		# Tail 'web' deployment pods in 'us-east-1a' or 'us-east-1b' zone
 		{{.CommandDisplayName}} deployments/web --zone=us-east-1a,us-east-1b
 
+	- Kubernetes events
+
+		# Tail 'web' deployment with events for its pods, replica sets and itself
+		{{.CommandDisplayName}} deployments/web --with-events
+
+		# Stream new records and events
+		{{.CommandDisplayName}} deployments/web --with-events --follow
+
+	- Lifecycle markers
+
+		# Stream new records and show when pods are added, removed or restarted
+		{{.CommandDisplayName}} deployments/web --follow --with-lifecycle
+
+	- Forwarding
+
+		# Stream new records and forward them to an OTLP collector
+		{{.CommandDisplayName}} deployments/web --follow --forward otlp://localhost:4317
+
+		# Forward to a collector that requires TLS and an auth header
+		{{.CommandDisplayName}} deployments/web --follow --forward otlps://otlp.example.com:4317 --forward-header "authorization=Bearer <token>"
+
+		# Backfill a time range into a multi-tenant Loki
+		{{.CommandDisplayName}} deployments/web --all --since PT6H --sink loki=http://loki:3100 --forward-header "loki:x-scope-orgid=team-1" > /dev/null
+
+		# Backfill a time range into Loki
+		{{.CommandDisplayName}} deployments/web --all --since 2025-01-01T00:00:00Z --until 2025-01-01T06:00:00Z --sink loki=http://loki:3100 > /dev/null
+
+		# Backfill a time range into the 'backfill' Elasticsearch index
+		{{.CommandDisplayName}} deployments/web --all --since PT6H --sink elasticsearch=http://elasticsearch:9200/backfill > /dev/null
+
+	- Permalinks
+
+		# Run the query from a dashboard permalink
+		{{.CommandDisplayName}} --from-link "https://kubetail.example.com/console?link=<token>"
+
+		# Run the query from a permalink but only for the 'web' container
+		{{.CommandDisplayName}} --from-link "<url>" --container=web
+
 Notes:
 
 	- The 'since' and 'until' flags accept the following:
@@ -171,7 +236,31 @@ Notes:
 	- Default behavior is "tail" unless 'since' is specified
 
 	- Using 'grep' requires 'force' because the command may unexpectedly download
-	  more log records than expected
+	  more log records than expected (the same applies to 'field', 'exclude' and 'level')
+
+	- The 'field' flag accepts the following:
+
+	  * field=value (equals)
+	  * field!=value (not equals or missing)
+	  * field*=value (contains)
+	  * field=~regex (matches)
+	  * field (exists)
+
+	- Flags given on the command line take precedence over values from 'from-link'
+
+	- The 'forward' flag accepts the following:
+
+	  * otlp://host:port (OTLP/gRPC)
+	  * otlps://host:port (OTLP/gRPC over TLS)
+
+	- The 'sink' flag accepts the following:
+
+	  * loki=<url> (Loki push API, path defaults to /loki/api/v1/push)
+	  * elasticsearch=<url>[/<index>] (Elasticsearch bulk API, index defaults to "kubetail")
+	  * otlp=<otlp-url> (same as 'forward')
+
+	- The 'forward-header' flag accepts [<sink-type>:]<key>=<value>. Headers
+	  without a sink type are only sent to OTLP endpoints.
 
 `
 
@@ -197,22 +286,39 @@ var logsCmd = &cobra.Command{
 	Use:   "logs [source1] [source2] ...",
 	Short: "Fetch logs for a container or a set of workloads",
 	Long:  strings.ReplaceAll(getLogsHelp(), "\t", "  "),
-	Args:  cobra.MinimumNArgs(1),
-	PreRunE: func(cmd *cobra.Command, args []string) error {
-		flags := cmd.Flags()
-		grep, _ := flags.GetString("grep")
-		force, _ := flags.GetBool("force")
-
-		if grep != "" && !force {
-			return fmt.Errorf("--force is required when using --grep")
+	Args: func(cmd *cobra.Command, args []string) error {
+		// Sources can come from permalink
+		if cmd.Flags().Changed("from-link") {
+			return nil
 		}
-
-		return nil
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return validateLogsFlags(cmd.Flags())
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		// Get flags
 		flags := cmd.Flags()
 
+		// Apply permalink
+		var highlight *permalink.Highlight
+		if fromLink, _ := flags.GetString("from-link"); fromLink != "" {
+			link, err := permalink.Parse(permalink.TokenFromURL(fromLink))
+			cli.ExitOnError(err)
+
+			err = applyLinkToFlags(flags, link)
+			cli.ExitOnError(err)
+
+			// Flags may have changed
+			err = validateLogsFlags(flags)
+			cli.ExitOnError(err)
+
+			if len(args) == 0 {
+				args = link.State.Sources
+			}
+			highlight = link.State.Highlight
+		}
+
 		kubeContext, _ := flags.GetString(KubeContextFlag)
 		kubeconfigPath, _ := flags.GetString(KubeconfigFlag)
 		inCluster, _ := flags.GetBool(InClusterFlag)
@@ -231,11 +337,15 @@ var logsCmd = &cobra.Command{
 		before, _ := flags.GetString("before")
 
 		grep, _ := flags.GetString("grep")
+		fieldList, _ := flags.GetStringArray("field")
+		excludeList, _ := flags.GetStringArray("exclude")
+		levelList, _ := flags.GetStringSlice("level")
 		regionList, _ := flags.GetStringSlice("region")
 		zoneList, _ := flags.GetStringSlice("zone")
 		osList, _ := flags.GetStringSlice("os")
 		archList, _ := flags.GetStringSlice("arch")
 		nodeList, _ := flags.GetStringSlice("node")
+		containerList, _ := flags.GetStringSlice("container")
 
 		hideHeader, _ := flags.GetBool("hide-header")
 		hideTs, _ := flags.GetBool("hide-ts")
@@ -244,6 +354,9 @@ var logsCmd = &cobra.Command{
 		withTs := !hideTs
 		withDot := !hideDot
 		allContainers, _ := flags.GetBool("all-containers")
+		servingOnly, _ := flags.GetBool("serving-only")
+		withEvents, _ := flags.GetBool("with-events")
+		withLifecycle, _ := flags.GetBool("with-lifecycle")
 
 		withNode, _ := flags.GetBool("with-node")
 		withRegion, _ := flags.GetBool("with-region")
@@ -255,6 +368,10 @@ var logsCmd = &cobra.Command{
 		withContainer, _ := flags.GetBool("with-container")
 		withCursors, _ := flags.GetBool("with-cursors")
 
+		forwardURL, _ := flags.GetString("forward")
+		sinkList, _ := flags.GetStringArray("sink")
+		forwardHeaderList, _ := flags.GetStringArray("forward-header")
+
 		raw, _ := flags.GetBool("raw")
 		if raw {
 			hideHeader = true
@@ -271,6 +388,11 @@ var logsCmd = &cobra.Command{
 			allContainers = false
 		}
 
+		// Only style messages if output supports it
+		colorMode, _ := flags.GetString("color")
+		color, err := useColor(colorMode, cmd.OutOrStdout())
+		cli.ExitOnError(err)
+
 		// Stream mode
 		streamMode := logsStreamModeUnknown
 		if head {
@@ -291,19 +413,19 @@ var logsCmd = &cobra.Command{
 		}
 
 		// Parse `since`
-		sinceTime, err := parseTimeArg(since)
+		sinceTime, err := util.ParseTimeArg(since)
 		cli.ExitOnError(err)
 
 		// Parse `until`
-		untilTime, err := parseTimeArg(until)
+		untilTime, err := util.ParseTimeArg(until)
 		cli.ExitOnError(err)
 
 		// Parse `after`
-		afterTime, err := parseTimeArg(after)
+		afterTime, err := util.ParseTimeArg(after)
 		cli.ExitOnError(err)
 
 		// Parse `before`
-		beforeTime, err := parseTimeArg(before)
+		beforeTime, err := util.ParseTimeArg(before)
 		cli.ExitOnError(err)
 
 		// Handle after/before
@@ -315,12 +437,25 @@ var logsCmd = &cobra.Command{
 			untilTime = beforeTime.Add(-1 * time.Nanosecond)
 		}
 
+		// Parse structured filter
+		fields := make([]logs.FieldPredicate, len(fieldList))
+		for i, expr := range fieldList {
+			fields[i], err = logs.ParseFieldPredicate(expr)
+			cli.ExitOnError(err)
+		}
+
+		filter, err := logs.NewFilter(fields, excludeList, levelList)
+		cli.ExitOnError(err)
+
 		// Init connection manager
 		env := config.EnvironmentDesktop
 		if inCluster {
 			env = config.EnvironmentCluster
 		}
-		cm, err := k8shelpers.NewConnectionManager(env, k8shelpers.WithKubeconfigPath(kubeconfigPath), k8shelpers.WithLazyConnect(true))
+		imp, err := impersonationFromFlags(flags)
+		cli.ExitOnError(err)
+
+		cm, err := k8shelpers.NewConnectionManager(env, k8shelpers.WithKubeconfigPath(kubeconfigPath), k8shelpers.WithLazyConnect(true), k8shelpers.WithImpersonation(imp))
 		cli.ExitOnError(err)
 
 		// Init stream
@@ -330,12 +465,17 @@ var logsCmd = &cobra.Command{
 			logs.WithUntil(untilTime),
 			logs.WithFollow(follow),
 			logs.WithGrep(grep),
+			logs.WithFilter(filter),
 			logs.WithRegions(regionList),
 			logs.WithZones(zoneList),
 			logs.WithOSes(osList),
 			logs.WithArches(archList),
 			logs.WithNodes(nodeList),
+			logs.WithContainers(containerList),
 			logs.WithAllContainers(allContainers),
+			logs.WithServingOnly(servingOnly),
+			logs.WithEvents(withEvents),
+			logs.WithLifecycle(withLifecycle),
 		}
 
 		switch streamMode {
@@ -353,6 +493,25 @@ var logsCmd = &cobra.Command{
 		rootCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 		defer stop() // clean up resources
 
+		// Init forwarders
+		sinkSpecs := sinkList
+		if forwardURL != "" {
+			sinkSpecs = append([]string{forwardURL}, sinkSpecs...)
+		}
+
+		var forwarders []*forward.Forwarder
+		if len(sinkSpecs) > 0 {
+			headers, err := parseForwardHeaders(forwardHeaderList)
+			cli.ExitOnError(err)
+
+			for _, spec := range sinkSpecs {
+				sinkType, _ := forward.ParseSinkSpec(spec)
+				sink, err := forward.NewSink(spec, headers[sinkType])
+				cli.ExitOnError(err)
+				forwarders = append(forwarders, forward.NewForwarder(sink))
+			}
+		}
+
 		stream, err := logs.NewStream(rootCtx, cm, args, streamOpts...)
 		cli.ExitOnError(err)
 		defer stream.Close()
@@ -382,6 +541,11 @@ var logsCmd = &cobra.Command{
 			}
 			lastRecord = &record
 
+			// Only fails once the user issues SIGTERM
+			for _, fwd := range forwarders {
+				fwd.Forward(rootCtx, record)
+			}
+
 			// Prepare row data
 			row := []string{}
 			if withTs {
@@ -390,6 +554,11 @@ var logsCmd = &cobra.Command{
 
 			if withDot {
 				dot := getDotIndicator(record.Source.ContainerID)
+				if record.Event != nil {
+					dot = getEventIndicator(record.Event)
+				} else if record.Lifecycle != nil {
+					dot = getLifecycleIndicator(record.Lifecycle)
+				}
 				row = append(row, dot)
 			}
 
@@ -417,13 +586,37 @@ var logsCmd = &cobra.Command{
 			if withContainer {
 				row = append(row, orDefault(record.Source.ContainerName, "-"))
 			}
-			row = append(row, record.Message)
+			message := record.Message
+			if record.Event != nil {
+				message = formatEventMessage(record.Event, record.Message)
+			}
+
+			if color && !raw && isHighlighted(highlight, &record) {
+				row = append(row, "\033[7m"+message+"\033[0m")
+			} else if color && !raw && record.Event != nil && record.Event.Type == "Warning" {
+				row = append(row, "\033[33m"+message+"\033[0m")
+			} else if color && !raw && record.Lifecycle != nil {
+				row = append(row, "\033[1m"+message+"\033[0m")
+			} else {
+				row = append(row, message)
+			}
 
 			// Add row to table
 			tw.WriteRow(row)
 			writer.Flush()
 		}
 
+		// Flush forwarded records
+		if len(forwarders) > 0 {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			for _, fwd := range forwarders {
+				if err := fwd.Close(ctx); err != nil {
+					fmt.Fprintf(cmd.OutOrStderr(), "\nError: failed to flush forwarded records: %v\n", err)
+				}
+			}
+			cancel()
+		}
+
 		// Exit early if user issued SIGTERM
 		if rootCtx.Err() != nil {
 			return
@@ -456,6 +649,130 @@ var logsCmd = &cobra.Command{
 	},
 }
 
+// Validate flag combinations
+func validateLogsFlags(flags *pflag.FlagSet) error {
+	grep, _ := flags.GetString("grep")
+	force, _ := flags.GetBool("force")
+
+	if grep != "" && !force {
+		return fmt.Errorf("--force is required when using --grep")
+	}
+
+	for _, name := range []string{"field", "exclude", "level"} {
+		if flags.Changed(name) && !force {
+			return fmt.Errorf("--force is required when using --%s", name)
+		}
+	}
+
+	if flags.Changed("forward-header") && !flags.Changed("forward") && !flags.Changed("sink") {
+		return fmt.Errorf("--forward or --sink is required when using --forward-header")
+	}
+
+	return nil
+}
+
+// Parse forward headers given as [sink-type:]key=value and group them by sink
+// type. Headers without a sink type belong to OTLP sinks.
+func parseForwardHeaders(list []string) (map[string]map[string]string, error) {
+	headers := make(map[string]map[string]string)
+	for _, item := range list {
+		key, value, ok := strings.Cut(item, "=")
+
+		sinkType := forward.SinkOTLP
+		if t, k, found := strings.Cut(key, ":"); found {
+			sinkType, key = strings.ToLower(strings.TrimSpace(t)), k
+		}
+
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid forward header %q (expected [sink-type:]key=value)", item)
+		}
+
+		switch sinkType {
+		case forward.SinkOTLP, forward.SinkLoki, forward.SinkElasticsearch:
+		default:
+			return nil, fmt.Errorf("invalid forward header %q (sink type not supported: %s)", item, sinkType)
+		}
+
+		if headers[sinkType] == nil {
+			headers[sinkType] = make(map[string]string)
+		}
+		headers[sinkType][strings.ToLower(key)] = value
+	}
+	return headers, nil
+}
+
+// Apply permalink query state to flags that weren't set explicitly
+func applyLinkToFlags(flags *pflag.FlagSet, link *permalink.Link) error {
+	st := link.State
+
+	setIfUnset := func(name string, value string) error {
+		if value == "" || flags.Changed(name) {
+			return nil
+		}
+		return flags.Set(name, value)
+	}
+
+	values := []struct {
+		name  string
+		value string
+	}{
+		{KubeContextFlag, st.KubeContext},
+		{"grep", st.Grep},
+		{"since", st.Since},
+		{"until", st.Until},
+		{"region", strings.Join(st.SourceFilter.Region, ",")},
+		{"zone", strings.Join(st.SourceFilter.Zone, ",")},
+		{"os", strings.Join(st.SourceFilter.OS, ",")},
+		{"arch", strings.Join(st.SourceFilter.Arch, ",")},
+		{"node", strings.Join(st.SourceFilter.Node, ",")},
+		{"container", strings.Join(st.SourceFilter.Container, ",")},
+	}
+
+	for _, v := range values {
+		if err := setIfUnset(v.name, v.value); err != nil {
+			return err
+		}
+	}
+
+	// Mode is only applied if user didn't choose one
+	if flags.Changed("head") || flags.Changed("tail") || flags.Changed("all") {
+		return nil
+	}
+
+	switch st.Mode {
+	case "HEAD":
+		return flags.Set("head", flags.Lookup("head").DefValue)
+	case "TAIL":
+		return flags.Set("tail", flags.Lookup("tail").DefValue)
+	}
+
+	return nil
+}
+
+// Check if messages should be styled with ANSI escape codes
+func useColor(mode string, out io.Writer) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		f, ok := out.(*os.File)
+		return ok && (isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())), nil
+	default:
+		return false, fmt.Errorf("invalid --color value %q (must be auto, always or never)", mode)
+	}
+}
+
+// Check if record matches permalink highlight
+func isHighlighted(highlight *permalink.Highlight, record *logs.LogRecord) bool {
+	if highlight == nil || !record.Timestamp.Equal(highlight.Timestamp) {
+		return false
+	}
+	return highlight.ContainerID == "" || highlight.ContainerID == record.Source.ContainerID
+}
+
 // Return ANSI color coded dot indicator based on container ID
 func getDotIndicator(containerID string) string {
 	colors := []string{
@@ -493,6 +810,38 @@ func getDotIndicator(containerID string) string {
 	return dot
 }
 
+// Return ANSI color coded diamond indicator for events
+func getEventIndicator(event *logs.KubeEvent) string {
+	color := "90m" // gray
+	if event.Type == "Warning" {
+		color = "33m" // yellow
+	}
+	return fmt.Sprintf("\033[%s%s\033[0m", color, "\u25C6")
+}
+
+// Return ANSI color coded indicator for lifecycle markers
+func getLifecycleIndicator(lc *logs.Lifecycle) string {
+	switch lc.Type {
+	case logs.LifecycleTypeSourceRemoved:
+		return "\033[31m-\033[0m" // red
+	case logs.LifecycleTypeContainerRestarted:
+		return "\033[33m\u21BB\033[0m" // yellow
+	case logs.LifecycleTypeSourceSkipped:
+		return "\033[33m!\033[0m" // yellow
+	default:
+		return "\033[32m+\033[0m" // green
+	}
+}
+
+// Return event message prefixed with its type, reason and involved object
+func formatEventMessage(event *logs.KubeEvent, message string) string {
+	out := fmt.Sprintf("[%s] %s %s/%s: %s", event.Type, event.Reason, strings.ToLower(event.Kind), event.Name, message)
+	if event.Count > 1 {
+		out += fmt.Sprintf(" (x%d)", event.Count)
+	}
+	return out
+}
+
 // Return table writer headers and col widths
 func getTableWriterHeaders(flags *pflag.FlagSet, sources []logs.LogSource) ([]string, []int) {
 	hideTs, _ := flags.GetBool("hide-ts")
@@ -582,24 +931,6 @@ func getTableWriterHeaders(flags *pflag.FlagSet, sources []logs.LogSource) ([]st
 	return headers, colWidths
 }
 
-// Parse an input either as an ISO timestamp or an ISO duration string
-func parseTimeArg(arg string) (time.Time, error) {
-	var zero time.Time
-
-	arg = strings.TrimSpace(arg)
-	if arg == "" {
-		return zero, nil
-	} else if timeAgo, err := duration.Parse(arg); err == nil {
-		// Parsed as ISO duration
-		return time.Now().Add(-1 * timeAgo.ToTimeDuration()), nil
-	} else if ts, err := time.Parse(time.RFC3339Nano, arg); err == nil {
-		// Parsed as ISO timestamp
-		return ts, nil
-	}
-
-	return zero, fmt.Errorf("unable to parse arg %s", arg)
-}
-
 // Return value or default
 func orDefault[T comparable](val T, defaultVal T) T {
 	var zero T
@@ -609,19 +940,8 @@ func orDefault[T comparable](val T, defaultVal T) T {
 	return val
 }
 
-func init() {
-	rootCmd.AddCommand(logsCmd)
-
-	// Here you will define your flags and configuration settings.
-
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// serveCmd.PersistentFlags().String("foo", "", "A help for foo")
-
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// serveCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
-	flagset := logsCmd.Flags()
+func addLogsCmdFlags(cmd *cobra.Command) {
+	flagset := cmd.Flags()
 	flagset.SortFlags = false
 
 	flagset.String(KubeContextFlag, "", "Specify the kubeconfig context to use")
@@ -630,7 +950,7 @@ func init() {
 	flagset.Int64P("tail", "t", 10, "Return last N records")
 	flagset.Lookup("tail").NoOptDefVal = "10"
 	flagset.Bool("all", false, "Return all records")
-	logsCmd.MarkFlagsMutuallyExclusive("head", "tail", "all")
+	cmd.MarkFlagsMutuallyExclusive("head", "tail", "all")
 
 	flagset.BoolP("follow", "f", false, "Stream new records")
 
@@ -638,16 +958,20 @@ func init() {
 	flagset.String("until", "", "Include records up to the specified point (inclusive)")
 	flagset.String("after", "", "Include records strictly after the specified point")
 	flagset.String("before", "", "Include records strictly before the specified point")
-	logsCmd.MarkFlagsMutuallyExclusive("since", "after")
-	logsCmd.MarkFlagsMutuallyExclusive("until", "before")
+	cmd.MarkFlagsMutuallyExclusive("since", "after")
+	cmd.MarkFlagsMutuallyExclusive("until", "before")
 
 	flagset.StringP("grep", "g", "", "Filter records by a regular expression")
+	flagset.StringArray("field", []string{}, "Filter JSON or logfmt records by a field predicate (repeatable)")
+	flagset.StringArray("exclude", []string{}, "Exclude records matching a regular expression (repeatable)")
+	flagset.StringSlice("level", []string{}, "Filter JSON or logfmt records by level")
 
 	flagset.StringSlice("region", []string{}, "Filter source pods by region")
 	flagset.StringSlice("zone", []string{}, "Filter source pods by zone")
 	flagset.StringSlice("os", []string{}, "Filter source pods by operating system")
 	flagset.StringSlice("arch", []string{}, "Filter source pods by CPU architecture")
 	flagset.StringSlice("node", []string{}, "Filter source pods by node name")
+	flagset.StringSlice("container", []string{}, "Filter source containers by name")
 
 	flagset.Bool("raw", false, "Output only raw log messages without metadata")
 	flagset.Bool("hide-ts", false, "Hide the timestamp of each record")
@@ -662,13 +986,38 @@ func init() {
 	flagset.Bool("with-cursors", false, "Show paging cursors")
 
 	flagset.Bool("hide-header", false, "Hide table header")
+	flagset.String("color", "auto", "Style highlighted, warning and lifecycle messages (auto, always or never)")
 	flagset.Bool("hide-dot", false, "Hide the dot indicator in the records")
 	flagset.Bool("all-containers", false, "Show logs from all containers in a Pod")
+	flagset.Bool("serving-only", false, "Only include serving endpoint pods for service and ingress sources")
+	flagset.Bool("with-events", false, "Include Kubernetes events for the source pods and workloads")
+	flagset.Bool("with-lifecycle", false, "Show markers when sources are added, removed or restarted (requires --follow)")
 
 	//flagset.BoolP("reverse", "r", false, "List records in reverse order")
 
+	flagset.String("from-link", "", "Run the query from a dashboard permalink")
+
+	flagset.String("forward", "", "Forward records to an external endpoint (e.g. otlp://localhost:4317)")
+	flagset.StringArray("sink", []string{}, "Forward records to a sink given as type=url (loki, elasticsearch or otlp; repeatable)")
+	flagset.StringArray("forward-header", []string{}, "Header sent with forwarded records as [sink-type:]key=value, OTLP only if no sink type is given (repeatable)")
+
 	flagset.Bool("force", false, "Force command (if necessary)")
 
 	// Define help here to avoid re-defining 'h' shorthand
 	flagset.Bool("help", false, "help for logs")
 }
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// serveCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// serveCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	addLogsCmdFlags(logsCmd)
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+	"github.com/kubetail-org/kubetail/modules/shared/permalink"
+)
+
+func newTestLink() *permalink.Link {
+	return &permalink.Link{
+		State: permalink.State{
+			KubeContext: "prod",
+			Sources:     []string{"default:deployments/web"},
+			SourceFilter: permalink.SourceFilter{
+				Region:    []string{"us-east-1", "us-east-2"},
+				Container: []string{"web"},
+			},
+			Grep:  "error",
+			Mode:  "HEAD",
+			Since: "PT30M",
+		},
+	}
+}
+
+func TestApplyLinkToFlags(t *testing.T) {
+	cmd := &cobra.Command{}
+	addLogsCmdFlags(cmd)
+	flags := cmd.Flags()
+
+	err := applyLinkToFlags(flags, newTestLink())
+	require.NoError(t, err)
+
+	kubeContext, _ := flags.GetString(KubeContextFlag)
+	assert.Equal(t, "prod", kubeContext)
+
+	grep, _ := flags.GetString("grep")
+	assert.Equal(t, "error", grep)
+
+	since, _ := flags.GetString("since")
+	assert.Equal(t, "PT30M", since)
+
+	regions, _ := flags.GetStringSlice("region")
+	assert.Equal(t, []string{"us-east-1", "us-east-2"}, regions)
+
+	containers, _ := flags.GetStringSlice("container")
+	assert.Equal(t, []string{"web"}, containers)
+
+	assert.True(t, flags.Changed("head"))
+	assert.False(t, flags.Changed("tail"))
+
+	// Grep from link still requires --force
+	assert.Error(t, validateLogsFlags(flags))
+}
+
+func TestValidateLogsFlagsFilter(t *testing.T) {
+	for _, name := range []string{"field", "exclude", "level"} {
+		t.Run(name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			addLogsCmdFlags(cmd)
+			flags := cmd.Flags()
+
+			require.NoError(t, flags.Set(name, "error"))
+			assert.Error(t, validateLogsFlags(flags))
+
+			require.NoError(t, flags.Set("force", "true"))
+			assert.NoError(t, validateLogsFlags(flags))
+		})
+	}
+}
+
+func TestValidateLogsFlagsForward(t *testing.T) {
+	cmd := &cobra.Command{}
+	addLogsCmdFlags(cmd)
+	flags := cmd.Flags()
+
+	require.NoError(t, flags.Set("forward-header", "authorization=Bearer xyz"))
+	assert.Error(t, validateLogsFlags(flags))
+
+	require.NoError(t, flags.Set("forward", "otlp://localhost:4317"))
+	assert.NoError(t, validateLogsFlags(flags))
+
+	cmd = &cobra.Command{}
+	addLogsCmdFlags(cmd)
+	flags = cmd.Flags()
+
+	require.NoError(t, flags.Set("forward-header", "loki:x-scope-orgid=team-1"))
+	require.NoError(t, flags.Set("sink", "loki=http://localhost:3100"))
+	assert.NoError(t, validateLogsFlags(flags))
+}
+
+func TestParseForwardHeaders(t *testing.T) {
+	headers, err := parseForwardHeaders([]string{"Authorization=Bearer a=b", "Loki:x-scope-orgid=team-1", "elasticsearch:authorization=ApiKey c"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"otlp":          {"authorization": "Bearer a=b"},
+		"loki":          {"x-scope-orgid": "team-1"},
+		"elasticsearch": {"authorization": "ApiKey c"},
+	}, headers)
+
+	for _, item := range []string{"authorization", "=value", "loki:=value", "splunk:authorization=x"} {
+		_, err := parseForwardHeaders([]string{item})
+		assert.Error(t, err, item)
+	}
+}
+
+func TestApplyLinkToFlagsPrecedence(t *testing.T) {
+	cmd := &cobra.Command{}
+	addLogsCmdFlags(cmd)
+	flags := cmd.Flags()
+
+	// Explicit flags win
+	require.NoError(t, flags.Set(KubeContextFlag, "staging"))
+	require.NoError(t, flags.Set("region", "eu-west-1"))
+	require.NoError(t, flags.Set("tail", "50"))
+
+	err := applyLinkToFlags(flags, newTestLink())
+	require.NoError(t, err)
+
+	kubeContext, _ := flags.GetString(KubeContextFlag)
+	assert.Equal(t, "staging", kubeContext)
+
+	regions, _ := flags.GetStringSlice("region")
+	assert.Equal(t, []string{"eu-west-1"}, regions)
+
+	assert.False(t, flags.Changed("head"))
+
+	tail, _ := flags.GetInt64("tail")
+	assert.Equal(t, int64(50), tail)
+}
+
+func TestIsHighlighted(t *testing.T) {
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	record := &logs.LogRecord{
+		Timestamp: ts,
+		Source:    logs.LogSource{ContainerID: "abc"},
+	}
+
+	assert.False(t, isHighlighted(nil, record))
+	assert.True(t, isHighlighted(&permalink.Highlight{Timestamp: ts}, record))
+	assert.True(t, isHighlighted(&permalink.Highlight{Timestamp: ts, ContainerID: "abc"}, record))
+	assert.False(t, isHighlighted(&permalink.Highlight{Timestamp: ts, ContainerID: "def"}, record))
+	assert.False(t, isHighlighted(&permalink.Highlight{Timestamp: ts.Add(time.Nanosecond)}, record))
+}
+
+func TestUseColor(t *testing.T) {
+	var buf bytes.Buffer
+
+	color, err := useColor("auto", &buf)
+	assert.NoError(t, err)
+	assert.False(t, color)
+
+	color, err = useColor("always", &buf)
+	assert.NoError(t, err)
+	assert.True(t, color)
+
+	color, err = useColor("never", os.Stdout)
+	assert.NoError(t, err)
+	assert.False(t, color)
+
+	_, err = useColor("sometimes", &buf)
+	assert.Error(t, err)
+}
+
+func TestFormatEventMessage(t *testing.T) {
+	event := &logs.KubeEvent{Type: "Warning", Reason: "BackOff", Kind: "Pod", Name: "web-abc123", Count: 1}
+	assert.Equal(t, "[Warning] BackOff pod/web-abc123: Back-off restarting failed container", formatEventMessage(event, "Back-off restarting failed container"))
+
+	event.Count = 5
+	assert.Equal(t, "[Warning] BackOff pod/web-abc123: Back-off restarting failed container (x5)", formatEventMessage(event, "Back-off restarting failed container"))
+}
+
+func TestGetLifecycleIndicator(t *testing.T) {
+	assert.Equal(t, "\033[32m+\033[0m", getLifecycleIndicator(&logs.Lifecycle{Type: logs.LifecycleTypeSourceAdded}))
+	assert.Equal(t, "\033[31m-\033[0m", getLifecycleIndicator(&logs.Lifecycle{Type: logs.LifecycleTypeSourceRemoved}))
+	assert.Equal(t, "\033[33m\u21BB\033[0m", getLifecycleIndicator(&logs.Lifecycle{Type: logs.LifecycleTypeContainerRestarted}))
+	assert.Equal(t, "\033[33m!\033[0m", getLifecycleIndicator(&logs.Lifecycle{Type: logs.LifecycleTypeSourceSkipped}))
+}
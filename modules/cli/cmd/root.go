@@ -15,6 +15,7 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -22,6 +23,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
@@ -29,6 +31,9 @@ const (
 	KubeconfigFlag  = clientcmd.RecommendedConfigPathFlag
 	KubeContextFlag = "kube-context"
 	InClusterFlag   = "in-cluster"
+	AsFlag          = "as"
+	AsGroupFlag     = "as-group"
+	AsUIDFlag       = "as-uid"
 )
 
 var version = "dev" // default version for local builds
@@ -97,6 +102,19 @@ func applyFlagNormalization(cmd *cobra.Command) {
 	}
 }
 
+// impersonationFromFlags returns the identity set with --as, --as-group and --as-uid
+func impersonationFromFlags(flags *pflag.FlagSet) (rest.ImpersonationConfig, error) {
+	user, _ := flags.GetString(AsFlag)
+	groups, _ := flags.GetStringArray(AsGroupFlag)
+	uid, _ := flags.GetString(AsUIDFlag)
+
+	if user == "" && (uid != "" || len(groups) > 0) {
+		return rest.ImpersonationConfig{}, fmt.Errorf("--%s and --%s require --%s", AsGroupFlag, AsUIDFlag, AsFlag)
+	}
+
+	return rest.ImpersonationConfig{UserName: user, UID: uid, Groups: groups}, nil
+}
+
 func init() {
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
@@ -109,6 +127,9 @@ func init() {
 	flagset := rootCmd.PersistentFlags()
 	flagset.String(KubeconfigFlag, "", "Path to kubeconfig file")
 	flagset.Bool(InClusterFlag, false, "Use in-cluster Kubernetes configuration")
+	flagset.String(AsFlag, "", "Username to impersonate for Kubernetes requests")
+	flagset.StringArray(AsGroupFlag, []string{}, "Group to impersonate for Kubernetes requests (can be repeated)")
+	flagset.String(AsUIDFlag, "", "UID to impersonate for Kubernetes requests")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
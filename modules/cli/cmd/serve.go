@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	"sync"
 	"syscall"
@@ -218,9 +219,16 @@ func loadServerConfig(cmd *cobra.Command) (*config.Config, *serveOptions, error)
 	kubeconfigPath, _ := cmd.Flags().GetString(KubeconfigFlag)
 	inCluster, _ := cmd.Flags().GetBool(InClusterFlag)
 
+	// Get impersonated identity (if set)
+	imp, err := impersonationFromFlags(cmd.Flags())
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Init viper
 	v := viper.New()
 	v.BindPFlag("dashboard.logging.level", cmd.Flags().Lookup("log-level"))
+	v.BindPFlag("dashboard.data-dir", cmd.Flags().Lookup("data-dir"))
 	v.Set("dashboard.addr", fmt.Sprintf("%s:%d", host, port))
 
 	// init config
@@ -235,6 +243,16 @@ func loadServerConfig(cmd *cobra.Command) (*config.Config, *serveOptions, error)
 		cfg.Dashboard.Environment = config.EnvironmentCluster
 	}
 	cfg.Dashboard.Logging.AccessLog.Enabled = false
+	cfg.Dashboard.Impersonate.User = imp.UserName
+	cfg.Dashboard.Impersonate.UID = imp.UID
+	cfg.Dashboard.Impersonate.Groups = imp.Groups
+
+	// Persist saved searches in user config dir by default
+	if cfg.Dashboard.DataDir == "" {
+		if configDir, err := os.UserConfigDir(); err == nil {
+			cfg.Dashboard.DataDir = filepath.Join(configDir, "kubetail")
+		}
+	}
 
 	serveOptions := &serveOptions{
 		port:     port,
@@ -286,6 +304,7 @@ func addServerCmdFlags(cmd *cobra.Command) {
 	flagset.String("host", "localhost", "Host address to bind to")
 	flagset.StringP("log-level", "l", "info", "Log level (debug, info, warn, error, disabled)")
 	flagset.Bool("skip-open", false, "Skip opening the browser")
+	flagset.String("data-dir", "", "Directory for saved searches and bookmarks (default: user config dir)")
 	// flagset.Bool("remote", false, "Open tunnel to remote dashboard")
 	flagset.Bool("test", false, "Run internal tests and exit")
 }
@@ -81,3 +81,37 @@ func TestLoadServerConfig(t *testing.T) {
 		assert.Equal(t, opts.skipOpen, val.skipOpen)
 	}
 }
+
+func TestLoadServerConfigImpersonation(t *testing.T) {
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		addServerCmdFlags(cmd)
+
+		// adding this to test the flags added in the root command
+		cmd.Flags().String(AsFlag, "", "")
+		cmd.Flags().StringArray(AsGroupFlag, []string{}, "")
+		cmd.Flags().String(AsUIDFlag, "", "")
+		return cmd
+	}
+
+	t.Run("user and groups", func(t *testing.T) {
+		cmd := newCmd()
+		cmd.Flags().Set(AsFlag, "alice")
+		cmd.Flags().Set(AsGroupFlag, "dev")
+		cmd.Flags().Set(AsGroupFlag, "ops")
+
+		cfg, _, err := loadServerConfig(cmd)
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", cfg.Dashboard.Impersonate.User)
+		assert.Equal(t, []string{"dev", "ops"}, cfg.Dashboard.Impersonate.Groups)
+		assert.Equal(t, "", cfg.Dashboard.Impersonate.UID)
+	})
+
+	t.Run("groups without user", func(t *testing.T) {
+		cmd := newCmd()
+		cmd.Flags().Set(AsGroupFlag, "dev")
+
+		_, _, err := loadServerConfig(cmd)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,211 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/kubetail-org/kubetail/modules/shared/clusterapi"
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+
+	"github.com/kubetail-org/kubetail/modules/cli/internal/cli"
+)
+
+const usageHelp = `
+This command shows how much disk space container logs are using, grouped by
+namespace, workload, node or container. Growth rates are measured by querying
+the cluster twice, --sample apart. Requires the Kubetail Cluster API.
+
+Examples:
+
+  # Show log volume per workload in all namespaces
+  kubetail usage
+
+  # Show log volume per node
+  kubetail usage --group-by node
+
+  # Show log volume per container in one namespace
+  kubetail usage -n default --group-by container
+`
+
+// usageCmd represents the `usage` command
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show log volume and growth rate",
+	Long:  usageHelp,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return validateUsageFlags(cmd.Flags())
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		// Get flags
+		flags := cmd.Flags()
+
+		kubeconfigPath, _ := flags.GetString(KubeconfigFlag)
+		kubeContext, _ := flags.GetString(KubeContextFlag)
+		namespace, _ := flags.GetString("namespace")
+		groupByStr, _ := flags.GetString("group-by")
+		sample, _ := flags.GetDuration("sample")
+		hideHeader, _ := flags.GetBool("hide-header")
+
+		groupBy := logs.UsageGroupBy(strings.ToUpper(groupByStr))
+
+		// Init connection manager
+		imp, err := impersonationFromFlags(flags)
+		cli.ExitOnError(err)
+
+		cm, err := k8shelpers.NewConnectionManager(config.EnvironmentDesktop, k8shelpers.WithKubeconfigPath(kubeconfigPath), k8shelpers.WithLazyConnect(true), k8shelpers.WithImpersonation(imp))
+		cli.ExitOnError(err)
+
+		// Init client
+		client := clusterapi.NewDesktopClient(cm, clusterapi.DefaultNamespace, clusterapi.DefaultServiceName)
+		defer client.Shutdown()
+
+		rootCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		// Take first sample
+		groups, err := client.LogUsageSummary(rootCtx, kubeContext, &namespace, groupBy)
+		cli.ExitOnError(err)
+
+		// Take second sample to measure growth
+		if sample > 0 {
+			select {
+			case <-rootCtx.Done():
+				return
+			case <-time.After(sample):
+			}
+
+			groups, err = client.LogUsageSummary(rootCtx, kubeContext, &namespace, groupBy)
+			cli.ExitOnError(err)
+		}
+
+		writeUsageTable(cmd.OutOrStdout(), groups, groupBy, hideHeader)
+
+		// Graceful close
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		err = cm.Shutdown(ctx)
+		cli.ExitOnError(err)
+	},
+}
+
+// Validate flag values
+func validateUsageFlags(flags *pflag.FlagSet) error {
+	groupBy, _ := flags.GetString("group-by")
+	switch logs.UsageGroupBy(strings.ToUpper(groupBy)) {
+	case logs.UsageGroupByNamespace, logs.UsageGroupByWorkload, logs.UsageGroupByNode, logs.UsageGroupByContainer:
+	default:
+		return fmt.Errorf("invalid --group-by value: %s", groupBy)
+	}
+
+	sample, _ := flags.GetDuration("sample")
+	if sample < 0 {
+		return fmt.Errorf("--sample must not be negative")
+	}
+
+	return nil
+}
+
+// Write usage groups as a table
+func writeUsageTable(out io.Writer, groups []logs.UsageGroup, groupBy logs.UsageGroupBy, hideHeader bool) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	var headers []string
+	switch groupBy {
+	case logs.UsageGroupByNamespace:
+		headers = []string{"NAMESPACE"}
+	case logs.UsageGroupByWorkload:
+		headers = []string{"NAMESPACE", "WORKLOAD"}
+	case logs.UsageGroupByNode:
+		headers = []string{"NODE"}
+	case logs.UsageGroupByContainer:
+		headers = []string{"NAMESPACE", "WORKLOAD", "CONTAINER"}
+	}
+	headers = append(headers, "FILES", "SIZE", "RATE")
+
+	if !hideHeader {
+		fmt.Fprintln(w, strings.Join(headers, "\t"))
+	}
+
+	for _, g := range groups {
+		var row []string
+		switch groupBy {
+		case logs.UsageGroupByNamespace:
+			row = []string{g.Namespace}
+		case logs.UsageGroupByWorkload:
+			row = []string{g.Namespace, formatWorkload(g)}
+		case logs.UsageGroupByNode:
+			row = []string{g.NodeName}
+		case logs.UsageGroupByContainer:
+			row = []string{g.Namespace, formatWorkload(g), g.ContainerName}
+		}
+
+		rate := "-"
+		if g.BytesPerSecond != nil {
+			rate = formatBytes(*g.BytesPerSecond) + "/s"
+		}
+		row = append(row, fmt.Sprint(g.FileCount), formatBytes(float64(g.Bytes)), rate)
+
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	w.Flush()
+}
+
+// Return workload as `kind/name`
+func formatWorkload(g logs.UsageGroup) string {
+	return fmt.Sprintf("%s/%s", strings.ToLower(g.WorkloadKind), g.WorkloadName)
+}
+
+// Return human-readable byte count
+func formatBytes(n float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+
+	i := 0
+	for (n >= 1024 || n <= -1024) && i < len(units)-1 {
+		n /= 1024
+		i++
+	}
+
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", n, units[i])
+	}
+	return fmt.Sprintf("%.1f %s", n, units[i])
+}
+
+func init() {
+	rootCmd.AddCommand(usageCmd)
+
+	flagset := usageCmd.Flags()
+	flagset.SortFlags = false
+	flagset.String(KubeContextFlag, "", "Specify the kubeconfig context to use")
+	flagset.StringP("namespace", "n", "", "Limit results to a namespace (default: all namespaces)")
+	flagset.String("group-by", "workload", "Group results by namespace, workload, node or container")
+	flagset.Duration("sample", 5*time.Second, "Time between samples used to measure growth rate (0 to disable)")
+	flagset.Bool("hide-header", false, "Hide table header")
+}
@@ -0,0 +1,114 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+)
+
+func TestValidateUsageFlags(t *testing.T) {
+	tests := []struct {
+		name       string
+		setGroupBy string
+		setSample  string
+		wantErr    bool
+	}{
+		{"defaults", "", "", false},
+		{"node", "node", "", false},
+		{"upper case", "CONTAINER", "", false},
+		{"invalid group by", "pod", "", true},
+		{"no sampling", "", "0s", false},
+		{"negative sample", "", "-1s", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().AddFlagSet(usageCmd.Flags())
+
+			flags := cmd.Flags()
+			if tt.setGroupBy != "" {
+				require.NoError(t, flags.Set("group-by", tt.setGroupBy))
+				defer flags.Set("group-by", "workload")
+			}
+			if tt.setSample != "" {
+				require.NoError(t, flags.Set("sample", tt.setSample))
+				defer flags.Set("sample", "5s")
+			}
+
+			err := validateUsageFlags(flags)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		setN float64
+		want string
+	}{
+		{0, "0 B"},
+		{512, "512 B"},
+		{1536, "1.5 KiB"},
+		{10 * 1024 * 1024, "10.0 MiB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatBytes(tt.setN))
+		})
+	}
+}
+
+func TestWriteUsageTable(t *testing.T) {
+	groups := []logs.UsageGroup{
+		{Namespace: "ns1", WorkloadKind: "Deployment", WorkloadName: "web", ContainerName: "app", Bytes: 2048, BytesPerSecond: ptr.To(512.0), FileCount: 2},
+		{Namespace: "ns2", WorkloadKind: "Pod", WorkloadName: "db-1", ContainerName: "db", Bytes: 100, FileCount: 1},
+	}
+
+	t.Run("workload", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeUsageTable(&buf, groups, logs.UsageGroupByWorkload, false)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 3)
+		assert.Equal(t, []string{"NAMESPACE", "WORKLOAD", "FILES", "SIZE", "RATE"}, strings.Fields(lines[0]))
+		assert.Equal(t, []string{"ns1", "deployment/web", "2", "2.0", "KiB", "512", "B/s"}, strings.Fields(lines[1]))
+		assert.Equal(t, []string{"ns2", "pod/db-1", "1", "100", "B", "-"}, strings.Fields(lines[2]))
+	})
+
+	t.Run("container without header", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeUsageTable(&buf, groups, logs.UsageGroupByContainer, true)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.Equal(t, "app", strings.Fields(lines[0])[2])
+	})
+}
@@ -92,6 +92,16 @@ func main() {
 				zlog.Fatal().Caller().Err(err).Send()
 			}
 
+			// Apply config file changes to the running app (also on SIGHUP)
+			if cli.Config != "" {
+				watcher, err := config.NewWatcher(v, cli.Config, cfg)
+				if err != nil {
+					zlog.Fatal().Caller().Err(err).Send()
+				}
+				defer watcher.Close()
+				watcher.Subscribe(app.ApplyConfig)
+			}
+
 			// Create sserver
 			server := &http.Server{
 				Addr:         cfg.ClusterAPI.Addr,
@@ -99,6 +109,7 @@ func main() {
 				IdleTimeout:  1 * time.Minute,
 				ReadTimeout:  5 * time.Second,
 				WriteTimeout: 10 * time.Second,
+				TLSConfig:    app.TLSConfig(),
 			}
 
 			// Run server in goroutine
@@ -107,7 +118,8 @@ func main() {
 				zlog.Info().Msg("Starting server on " + cfg.ClusterAPI.Addr)
 
 				if cfg.ClusterAPI.TLS.Enabled {
-					serverErr = server.ListenAndServeTLS(cfg.ClusterAPI.TLS.CertFile, cfg.ClusterAPI.TLS.KeyFile)
+					// certificates are loaded by the app's tls config
+					serverErr = server.ListenAndServeTLS("", "")
 				} else {
 					serverErr = server.ListenAndServe()
 				}
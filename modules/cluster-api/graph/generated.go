@@ -46,6 +46,7 @@ type Config struct {
 }
 
 type ResolverRoot interface {
+	LogRecordsCountBucket() LogRecordsCountBucketResolver
 	Query() QueryResolver
 	Subscription() SubscriptionResolver
 }
@@ -85,16 +86,42 @@ type ComplexityRoot struct {
 	}
 
 	LogRecord struct {
+		Lifecycle func(childComplexity int) int
 		Message   func(childComplexity int) int
 		Source    func(childComplexity int) int
 		Timestamp func(childComplexity int) int
 	}
 
+	LogRecordLifecycle struct {
+		ExitCode     func(childComplexity int) int
+		Reason       func(childComplexity int) int
+		RestartCount func(childComplexity int) int
+		Type         func(childComplexity int) int
+	}
+
+	LogRecordsCountBucket struct {
+		Count func(childComplexity int) int
+		Start func(childComplexity int) int
+	}
+
+	LogRecordsCountResponse struct {
+		Buckets func(childComplexity int) int
+		Sources func(childComplexity int) int
+		Total   func(childComplexity int) int
+	}
+
 	LogRecordsQueryResponse struct {
+		FetchPaths func(childComplexity int) int
 		NextCursor func(childComplexity int) int
 		Records    func(childComplexity int) int
 	}
 
+	LogRecordsSourceCount struct {
+		Buckets func(childComplexity int) int
+		Source  func(childComplexity int) int
+		Total   func(childComplexity int) int
+	}
+
 	LogSource struct {
 		ContainerID   func(childComplexity int) int
 		ContainerName func(childComplexity int) int
@@ -103,6 +130,11 @@ type ComplexityRoot struct {
 		PodName       func(childComplexity int) int
 	}
 
+	LogSourceFetchPath struct {
+		Path   func(childComplexity int) int
+		Source func(childComplexity int) int
+	}
+
 	LogSourceMetadata struct {
 		Arch   func(childComplexity int) int
 		Node   func(childComplexity int) int
@@ -116,6 +148,17 @@ type ComplexityRoot struct {
 		Type   func(childComplexity int) int
 	}
 
+	LogUsageGroup struct {
+		Bytes          func(childComplexity int) int
+		BytesPerSecond func(childComplexity int) int
+		ContainerName  func(childComplexity int) int
+		FileCount      func(childComplexity int) int
+		Namespace      func(childComplexity int) int
+		NodeName       func(childComplexity int) int
+		WorkloadKind   func(childComplexity int) int
+		WorkloadName   func(childComplexity int) int
+	}
+
 	PageInfo struct {
 		EndCursor       func(childComplexity int) int
 		HasNextPage     func(childComplexity int) int
@@ -130,23 +173,30 @@ type ComplexityRoot struct {
 
 	Query struct {
 		LogMetadataList func(childComplexity int, namespace *string) int
-		LogRecordsFetch func(childComplexity int, kubeContext *string, sources []string, mode *model.LogRecordsQueryMode, since *string, until *string, after *string, before *string, grep *string, sourceFilter *model.LogSourceFilter, limit *int) int
+		LogRecordsCount func(childComplexity int, kubeContext *string, sources []string, since *string, until *string, grep *string, filter *model.LogRecordsFilter, sourceFilter *model.LogSourceFilter, servingOnly *bool, bucketSize *string) int
+		LogRecordsFetch func(childComplexity int, kubeContext *string, sources []string, mode *model.LogRecordsQueryMode, since *string, until *string, after *string, before *string, grep *string, filter *model.LogRecordsFilter, sourceFilter *model.LogSourceFilter, servingOnly *bool, limit *int) int
+		LogUsageSummary func(childComplexity int, namespace *string, groupBy *logs.UsageGroupBy) int
 	}
 
 	Subscription struct {
 		LogMetadataWatch func(childComplexity int, namespace *string) int
-		LogRecordsFollow func(childComplexity int, kubeContext *string, sources []string, since *string, after *string, grep *string, sourceFilter *model.LogSourceFilter) int
+		LogRecordsFollow func(childComplexity int, kubeContext *string, sources []string, since *string, after *string, grep *string, filter *model.LogRecordsFilter, sourceFilter *model.LogSourceFilter, servingOnly *bool, withLifecycle *bool) int
 		LogSourcesWatch  func(childComplexity int, kubeContext *string, sources []string) int
 	}
 }
 
+type LogRecordsCountBucketResolver interface {
+	Start(ctx context.Context, obj *logs.CountBucket) (*time.Time, error)
+}
 type QueryResolver interface {
 	LogMetadataList(ctx context.Context, namespace *string) (*clusteragentpb.LogMetadataList, error)
-	LogRecordsFetch(ctx context.Context, kubeContext *string, sources []string, mode *model.LogRecordsQueryMode, since *string, until *string, after *string, before *string, grep *string, sourceFilter *model.LogSourceFilter, limit *int) (*model.LogRecordsQueryResponse, error)
+	LogUsageSummary(ctx context.Context, namespace *string, groupBy *logs.UsageGroupBy) ([]*logs.UsageGroup, error)
+	LogRecordsFetch(ctx context.Context, kubeContext *string, sources []string, mode *model.LogRecordsQueryMode, since *string, until *string, after *string, before *string, grep *string, filter *model.LogRecordsFilter, sourceFilter *model.LogSourceFilter, servingOnly *bool, limit *int) (*model.LogRecordsQueryResponse, error)
+	LogRecordsCount(ctx context.Context, kubeContext *string, sources []string, since *string, until *string, grep *string, filter *model.LogRecordsFilter, sourceFilter *model.LogSourceFilter, servingOnly *bool, bucketSize *string) (*logs.CountResult, error)
 }
 type SubscriptionResolver interface {
 	LogMetadataWatch(ctx context.Context, namespace *string) (<-chan *clusteragentpb.LogMetadataWatchEvent, error)
-	LogRecordsFollow(ctx context.Context, kubeContext *string, sources []string, since *string, after *string, grep *string, sourceFilter *model.LogSourceFilter) (<-chan *logs.LogRecord, error)
+	LogRecordsFollow(ctx context.Context, kubeContext *string, sources []string, since *string, after *string, grep *string, filter *model.LogRecordsFilter, sourceFilter *model.LogSourceFilter, servingOnly *bool, withLifecycle *bool) (<-chan *logs.LogRecord, error)
 	LogSourcesWatch(ctx context.Context, kubeContext *string, sources []string) (<-chan *model.LogSourceWatchEvent, error)
 }
 
@@ -260,6 +310,13 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.LogMetadataWatchEvent.Type(childComplexity), true
 
+	case "LogRecord.lifecycle":
+		if e.complexity.LogRecord.Lifecycle == nil {
+			break
+		}
+
+		return e.complexity.LogRecord.Lifecycle(childComplexity), true
+
 	case "LogRecord.message":
 		if e.complexity.LogRecord.Message == nil {
 			break
@@ -281,6 +338,76 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.LogRecord.Timestamp(childComplexity), true
 
+	case "LogRecordLifecycle.exitCode":
+		if e.complexity.LogRecordLifecycle.ExitCode == nil {
+			break
+		}
+
+		return e.complexity.LogRecordLifecycle.ExitCode(childComplexity), true
+
+	case "LogRecordLifecycle.reason":
+		if e.complexity.LogRecordLifecycle.Reason == nil {
+			break
+		}
+
+		return e.complexity.LogRecordLifecycle.Reason(childComplexity), true
+
+	case "LogRecordLifecycle.restartCount":
+		if e.complexity.LogRecordLifecycle.RestartCount == nil {
+			break
+		}
+
+		return e.complexity.LogRecordLifecycle.RestartCount(childComplexity), true
+
+	case "LogRecordLifecycle.type":
+		if e.complexity.LogRecordLifecycle.Type == nil {
+			break
+		}
+
+		return e.complexity.LogRecordLifecycle.Type(childComplexity), true
+
+	case "LogRecordsCountBucket.count":
+		if e.complexity.LogRecordsCountBucket.Count == nil {
+			break
+		}
+
+		return e.complexity.LogRecordsCountBucket.Count(childComplexity), true
+
+	case "LogRecordsCountBucket.start":
+		if e.complexity.LogRecordsCountBucket.Start == nil {
+			break
+		}
+
+		return e.complexity.LogRecordsCountBucket.Start(childComplexity), true
+
+	case "LogRecordsCountResponse.buckets":
+		if e.complexity.LogRecordsCountResponse.Buckets == nil {
+			break
+		}
+
+		return e.complexity.LogRecordsCountResponse.Buckets(childComplexity), true
+
+	case "LogRecordsCountResponse.sources":
+		if e.complexity.LogRecordsCountResponse.Sources == nil {
+			break
+		}
+
+		return e.complexity.LogRecordsCountResponse.Sources(childComplexity), true
+
+	case "LogRecordsCountResponse.total":
+		if e.complexity.LogRecordsCountResponse.Total == nil {
+			break
+		}
+
+		return e.complexity.LogRecordsCountResponse.Total(childComplexity), true
+
+	case "LogRecordsQueryResponse.fetchPaths":
+		if e.complexity.LogRecordsQueryResponse.FetchPaths == nil {
+			break
+		}
+
+		return e.complexity.LogRecordsQueryResponse.FetchPaths(childComplexity), true
+
 	case "LogRecordsQueryResponse.nextCursor":
 		if e.complexity.LogRecordsQueryResponse.NextCursor == nil {
 			break
@@ -295,6 +422,27 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.LogRecordsQueryResponse.Records(childComplexity), true
 
+	case "LogRecordsSourceCount.buckets":
+		if e.complexity.LogRecordsSourceCount.Buckets == nil {
+			break
+		}
+
+		return e.complexity.LogRecordsSourceCount.Buckets(childComplexity), true
+
+	case "LogRecordsSourceCount.source":
+		if e.complexity.LogRecordsSourceCount.Source == nil {
+			break
+		}
+
+		return e.complexity.LogRecordsSourceCount.Source(childComplexity), true
+
+	case "LogRecordsSourceCount.total":
+		if e.complexity.LogRecordsSourceCount.Total == nil {
+			break
+		}
+
+		return e.complexity.LogRecordsSourceCount.Total(childComplexity), true
+
 	case "LogSource.containerID":
 		if e.complexity.LogSource.ContainerID == nil {
 			break
@@ -330,6 +478,20 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.LogSource.PodName(childComplexity), true
 
+	case "LogSourceFetchPath.path":
+		if e.complexity.LogSourceFetchPath.Path == nil {
+			break
+		}
+
+		return e.complexity.LogSourceFetchPath.Path(childComplexity), true
+
+	case "LogSourceFetchPath.source":
+		if e.complexity.LogSourceFetchPath.Source == nil {
+			break
+		}
+
+		return e.complexity.LogSourceFetchPath.Source(childComplexity), true
+
 	case "LogSourceMetadata.arch":
 		if e.complexity.LogSourceMetadata.Arch == nil {
 			break
@@ -379,6 +541,62 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.LogSourceWatchEvent.Type(childComplexity), true
 
+	case "LogUsageGroup.bytes":
+		if e.complexity.LogUsageGroup.Bytes == nil {
+			break
+		}
+
+		return e.complexity.LogUsageGroup.Bytes(childComplexity), true
+
+	case "LogUsageGroup.bytesPerSecond":
+		if e.complexity.LogUsageGroup.BytesPerSecond == nil {
+			break
+		}
+
+		return e.complexity.LogUsageGroup.BytesPerSecond(childComplexity), true
+
+	case "LogUsageGroup.containerName":
+		if e.complexity.LogUsageGroup.ContainerName == nil {
+			break
+		}
+
+		return e.complexity.LogUsageGroup.ContainerName(childComplexity), true
+
+	case "LogUsageGroup.fileCount":
+		if e.complexity.LogUsageGroup.FileCount == nil {
+			break
+		}
+
+		return e.complexity.LogUsageGroup.FileCount(childComplexity), true
+
+	case "LogUsageGroup.namespace":
+		if e.complexity.LogUsageGroup.Namespace == nil {
+			break
+		}
+
+		return e.complexity.LogUsageGroup.Namespace(childComplexity), true
+
+	case "LogUsageGroup.nodeName":
+		if e.complexity.LogUsageGroup.NodeName == nil {
+			break
+		}
+
+		return e.complexity.LogUsageGroup.NodeName(childComplexity), true
+
+	case "LogUsageGroup.workloadKind":
+		if e.complexity.LogUsageGroup.WorkloadKind == nil {
+			break
+		}
+
+		return e.complexity.LogUsageGroup.WorkloadKind(childComplexity), true
+
+	case "LogUsageGroup.workloadName":
+		if e.complexity.LogUsageGroup.WorkloadName == nil {
+			break
+		}
+
+		return e.complexity.LogUsageGroup.WorkloadName(childComplexity), true
+
 	case "PageInfo.endCursor":
 		if e.complexity.PageInfo.EndCursor == nil {
 			break
@@ -433,6 +651,18 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.Query.LogMetadataList(childComplexity, args["namespace"].(*string)), true
 
+	case "Query.logRecordsCount":
+		if e.complexity.Query.LogRecordsCount == nil {
+			break
+		}
+
+		args, err := ec.field_Query_logRecordsCount_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.LogRecordsCount(childComplexity, args["kubeContext"].(*string), args["sources"].([]string), args["since"].(*string), args["until"].(*string), args["grep"].(*string), args["filter"].(*model.LogRecordsFilter), args["sourceFilter"].(*model.LogSourceFilter), args["servingOnly"].(*bool), args["bucketSize"].(*string)), true
+
 	case "Query.logRecordsFetch":
 		if e.complexity.Query.LogRecordsFetch == nil {
 			break
@@ -443,7 +673,19 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Query.LogRecordsFetch(childComplexity, args["kubeContext"].(*string), args["sources"].([]string), args["mode"].(*model.LogRecordsQueryMode), args["since"].(*string), args["until"].(*string), args["after"].(*string), args["before"].(*string), args["grep"].(*string), args["sourceFilter"].(*model.LogSourceFilter), args["limit"].(*int)), true
+		return e.complexity.Query.LogRecordsFetch(childComplexity, args["kubeContext"].(*string), args["sources"].([]string), args["mode"].(*model.LogRecordsQueryMode), args["since"].(*string), args["until"].(*string), args["after"].(*string), args["before"].(*string), args["grep"].(*string), args["filter"].(*model.LogRecordsFilter), args["sourceFilter"].(*model.LogSourceFilter), args["servingOnly"].(*bool), args["limit"].(*int)), true
+
+	case "Query.logUsageSummary":
+		if e.complexity.Query.LogUsageSummary == nil {
+			break
+		}
+
+		args, err := ec.field_Query_logUsageSummary_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.LogUsageSummary(childComplexity, args["namespace"].(*string), args["groupBy"].(*logs.UsageGroupBy)), true
 
 	case "Subscription.logMetadataWatch":
 		if e.complexity.Subscription.LogMetadataWatch == nil {
@@ -467,7 +709,7 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Subscription.LogRecordsFollow(childComplexity, args["kubeContext"].(*string), args["sources"].([]string), args["since"].(*string), args["after"].(*string), args["grep"].(*string), args["sourceFilter"].(*model.LogSourceFilter)), true
+		return e.complexity.Subscription.LogRecordsFollow(childComplexity, args["kubeContext"].(*string), args["sources"].([]string), args["since"].(*string), args["after"].(*string), args["grep"].(*string), args["filter"].(*model.LogRecordsFilter), args["sourceFilter"].(*model.LogSourceFilter), args["servingOnly"].(*bool), args["withLifecycle"].(*bool)), true
 
 	case "Subscription.logSourcesWatch":
 		if e.complexity.Subscription.LogSourcesWatch == nil {
@@ -489,6 +731,8 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 	opCtx := graphql.GetOperationContext(ctx)
 	ec := executionContext{opCtx, e, 0, 0, make(chan graphql.DeferredResult)}
 	inputUnmarshalMap := graphql.BuildUnmarshalerMap(
+		ec.unmarshalInputLogRecordsFieldPredicate,
+		ec.unmarshalInputLogRecordsFilter,
 		ec.unmarshalInputLogSourceFilter,
 	)
 	first := true
@@ -705,62 +949,57 @@ func (ec *executionContext) field_Query_logMetadataList_argsNamespace(
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Query_logRecordsFetch_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+func (ec *executionContext) field_Query_logRecordsCount_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
-	arg0, err := ec.field_Query_logRecordsFetch_argsKubeContext(ctx, rawArgs)
+	arg0, err := ec.field_Query_logRecordsCount_argsKubeContext(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
 	args["kubeContext"] = arg0
-	arg1, err := ec.field_Query_logRecordsFetch_argsSources(ctx, rawArgs)
+	arg1, err := ec.field_Query_logRecordsCount_argsSources(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
 	args["sources"] = arg1
-	arg2, err := ec.field_Query_logRecordsFetch_argsMode(ctx, rawArgs)
-	if err != nil {
-		return nil, err
-	}
-	args["mode"] = arg2
-	arg3, err := ec.field_Query_logRecordsFetch_argsSince(ctx, rawArgs)
+	arg2, err := ec.field_Query_logRecordsCount_argsSince(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
-	args["since"] = arg3
-	arg4, err := ec.field_Query_logRecordsFetch_argsUntil(ctx, rawArgs)
+	args["since"] = arg2
+	arg3, err := ec.field_Query_logRecordsCount_argsUntil(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
-	args["until"] = arg4
-	arg5, err := ec.field_Query_logRecordsFetch_argsAfter(ctx, rawArgs)
+	args["until"] = arg3
+	arg4, err := ec.field_Query_logRecordsCount_argsGrep(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
-	args["after"] = arg5
-	arg6, err := ec.field_Query_logRecordsFetch_argsBefore(ctx, rawArgs)
+	args["grep"] = arg4
+	arg5, err := ec.field_Query_logRecordsCount_argsFilter(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
-	args["before"] = arg6
-	arg7, err := ec.field_Query_logRecordsFetch_argsGrep(ctx, rawArgs)
+	args["filter"] = arg5
+	arg6, err := ec.field_Query_logRecordsCount_argsSourceFilter(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
-	args["grep"] = arg7
-	arg8, err := ec.field_Query_logRecordsFetch_argsSourceFilter(ctx, rawArgs)
+	args["sourceFilter"] = arg6
+	arg7, err := ec.field_Query_logRecordsCount_argsServingOnly(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
-	args["sourceFilter"] = arg8
-	arg9, err := ec.field_Query_logRecordsFetch_argsLimit(ctx, rawArgs)
+	args["servingOnly"] = arg7
+	arg8, err := ec.field_Query_logRecordsCount_argsBucketSize(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
-	args["limit"] = arg9
+	args["bucketSize"] = arg8
 	return args, nil
 }
-func (ec *executionContext) field_Query_logRecordsFetch_argsKubeContext(
+func (ec *executionContext) field_Query_logRecordsCount_argsKubeContext(
 	ctx context.Context,
 	rawArgs map[string]any,
 ) (*string, error) {
@@ -773,7 +1012,7 @@ func (ec *executionContext) field_Query_logRecordsFetch_argsKubeContext(
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Query_logRecordsFetch_argsSources(
+func (ec *executionContext) field_Query_logRecordsCount_argsSources(
 	ctx context.Context,
 	rawArgs map[string]any,
 ) ([]string, error) {
@@ -786,20 +1025,7 @@ func (ec *executionContext) field_Query_logRecordsFetch_argsSources(
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Query_logRecordsFetch_argsMode(
-	ctx context.Context,
-	rawArgs map[string]any,
-) (*model.LogRecordsQueryMode, error) {
-	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("mode"))
-	if tmp, ok := rawArgs["mode"]; ok {
-		return ec.unmarshalOLogRecordsQueryMode2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐLogRecordsQueryMode(ctx, tmp)
-	}
-
-	var zeroVal *model.LogRecordsQueryMode
-	return zeroVal, nil
-}
-
-func (ec *executionContext) field_Query_logRecordsFetch_argsSince(
+func (ec *executionContext) field_Query_logRecordsCount_argsSince(
 	ctx context.Context,
 	rawArgs map[string]any,
 ) (*string, error) {
@@ -812,7 +1038,7 @@ func (ec *executionContext) field_Query_logRecordsFetch_argsSince(
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Query_logRecordsFetch_argsUntil(
+func (ec *executionContext) field_Query_logRecordsCount_argsUntil(
 	ctx context.Context,
 	rawArgs map[string]any,
 ) (*string, error) {
@@ -825,25 +1051,12 @@ func (ec *executionContext) field_Query_logRecordsFetch_argsUntil(
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Query_logRecordsFetch_argsAfter(
-	ctx context.Context,
-	rawArgs map[string]any,
-) (*string, error) {
-	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("after"))
-	if tmp, ok := rawArgs["after"]; ok {
-		return ec.unmarshalOString2ᚖstring(ctx, tmp)
-	}
-
-	var zeroVal *string
-	return zeroVal, nil
-}
-
-func (ec *executionContext) field_Query_logRecordsFetch_argsBefore(
+func (ec *executionContext) field_Query_logRecordsCount_argsGrep(
 	ctx context.Context,
 	rawArgs map[string]any,
 ) (*string, error) {
-	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("before"))
-	if tmp, ok := rawArgs["before"]; ok {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("grep"))
+	if tmp, ok := rawArgs["grep"]; ok {
 		return ec.unmarshalOString2ᚖstring(ctx, tmp)
 	}
 
@@ -851,20 +1064,20 @@ func (ec *executionContext) field_Query_logRecordsFetch_argsBefore(
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Query_logRecordsFetch_argsGrep(
+func (ec *executionContext) field_Query_logRecordsCount_argsFilter(
 	ctx context.Context,
 	rawArgs map[string]any,
-) (*string, error) {
-	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("grep"))
-	if tmp, ok := rawArgs["grep"]; ok {
-		return ec.unmarshalOString2ᚖstring(ctx, tmp)
+) (*model.LogRecordsFilter, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("filter"))
+	if tmp, ok := rawArgs["filter"]; ok {
+		return ec.unmarshalOLogRecordsFilter2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐLogRecordsFilter(ctx, tmp)
 	}
 
-	var zeroVal *string
+	var zeroVal *model.LogRecordsFilter
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Query_logRecordsFetch_argsSourceFilter(
+func (ec *executionContext) field_Query_logRecordsCount_argsSourceFilter(
 	ctx context.Context,
 	rawArgs map[string]any,
 ) (*model.LogSourceFilter, error) {
@@ -877,70 +1090,25 @@ func (ec *executionContext) field_Query_logRecordsFetch_argsSourceFilter(
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Query_logRecordsFetch_argsLimit(
+func (ec *executionContext) field_Query_logRecordsCount_argsServingOnly(
 	ctx context.Context,
 	rawArgs map[string]any,
-) (*int, error) {
-	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("limit"))
-	directive0 := func(ctx context.Context) (any, error) {
-		tmp, ok := rawArgs["limit"]
-		if !ok {
-			var zeroVal *int
-			return zeroVal, nil
-		}
-		return ec.unmarshalOInt2ᚖint(ctx, tmp)
-	}
-
-	directive1 := func(ctx context.Context) (any, error) {
-		rule, err := ec.unmarshalNString2string(ctx, "gt=0")
-		if err != nil {
-			var zeroVal *int
-			return zeroVal, err
-		}
-		message, err := ec.unmarshalOString2ᚖstring(ctx, "Value must be > 0")
-		if err != nil {
-			var zeroVal *int
-			return zeroVal, err
-		}
-		if ec.directives.Validate == nil {
-			var zeroVal *int
-			return zeroVal, errors.New("directive validate is not implemented")
-		}
-		return ec.directives.Validate(ctx, rawArgs, directive0, rule, message)
+) (*bool, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("servingOnly"))
+	if tmp, ok := rawArgs["servingOnly"]; ok {
+		return ec.unmarshalOBoolean2ᚖbool(ctx, tmp)
 	}
 
-	tmp, err := directive1(ctx)
-	if err != nil {
-		var zeroVal *int
-		return zeroVal, graphql.ErrorOnPath(ctx, err)
-	}
-	if data, ok := tmp.(*int); ok {
-		return data, nil
-	} else if tmp == nil {
-		var zeroVal *int
-		return zeroVal, nil
-	} else {
-		var zeroVal *int
-		return zeroVal, graphql.ErrorOnPath(ctx, fmt.Errorf(`unexpected type %T from directive, should be *int`, tmp))
-	}
+	var zeroVal *bool
+	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Subscription_logMetadataWatch_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
-	var err error
-	args := map[string]any{}
-	arg0, err := ec.field_Subscription_logMetadataWatch_argsNamespace(ctx, rawArgs)
-	if err != nil {
-		return nil, err
-	}
-	args["namespace"] = arg0
-	return args, nil
-}
-func (ec *executionContext) field_Subscription_logMetadataWatch_argsNamespace(
+func (ec *executionContext) field_Query_logRecordsCount_argsBucketSize(
 	ctx context.Context,
 	rawArgs map[string]any,
 ) (*string, error) {
-	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
-	if tmp, ok := rawArgs["namespace"]; ok {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("bucketSize"))
+	if tmp, ok := rawArgs["bucketSize"]; ok {
 		return ec.unmarshalOString2ᚖstring(ctx, tmp)
 	}
 
@@ -948,42 +1116,72 @@ func (ec *executionContext) field_Subscription_logMetadataWatch_argsNamespace(
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Subscription_logRecordsFollow_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+func (ec *executionContext) field_Query_logRecordsFetch_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
-	arg0, err := ec.field_Subscription_logRecordsFollow_argsKubeContext(ctx, rawArgs)
+	arg0, err := ec.field_Query_logRecordsFetch_argsKubeContext(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
 	args["kubeContext"] = arg0
-	arg1, err := ec.field_Subscription_logRecordsFollow_argsSources(ctx, rawArgs)
+	arg1, err := ec.field_Query_logRecordsFetch_argsSources(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
 	args["sources"] = arg1
-	arg2, err := ec.field_Subscription_logRecordsFollow_argsSince(ctx, rawArgs)
+	arg2, err := ec.field_Query_logRecordsFetch_argsMode(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
-	args["since"] = arg2
-	arg3, err := ec.field_Subscription_logRecordsFollow_argsAfter(ctx, rawArgs)
+	args["mode"] = arg2
+	arg3, err := ec.field_Query_logRecordsFetch_argsSince(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
-	args["after"] = arg3
-	arg4, err := ec.field_Subscription_logRecordsFollow_argsGrep(ctx, rawArgs)
+	args["since"] = arg3
+	arg4, err := ec.field_Query_logRecordsFetch_argsUntil(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
-	args["grep"] = arg4
-	arg5, err := ec.field_Subscription_logRecordsFollow_argsSourceFilter(ctx, rawArgs)
+	args["until"] = arg4
+	arg5, err := ec.field_Query_logRecordsFetch_argsAfter(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["after"] = arg5
+	arg6, err := ec.field_Query_logRecordsFetch_argsBefore(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["before"] = arg6
+	arg7, err := ec.field_Query_logRecordsFetch_argsGrep(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["grep"] = arg7
+	arg8, err := ec.field_Query_logRecordsFetch_argsFilter(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["filter"] = arg8
+	arg9, err := ec.field_Query_logRecordsFetch_argsSourceFilter(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["sourceFilter"] = arg9
+	arg10, err := ec.field_Query_logRecordsFetch_argsServingOnly(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["servingOnly"] = arg10
+	arg11, err := ec.field_Query_logRecordsFetch_argsLimit(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
-	args["sourceFilter"] = arg5
+	args["limit"] = arg11
 	return args, nil
 }
-func (ec *executionContext) field_Subscription_logRecordsFollow_argsKubeContext(
+func (ec *executionContext) field_Query_logRecordsFetch_argsKubeContext(
 	ctx context.Context,
 	rawArgs map[string]any,
 ) (*string, error) {
@@ -996,7 +1194,7 @@ func (ec *executionContext) field_Subscription_logRecordsFollow_argsKubeContext(
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Subscription_logRecordsFollow_argsSources(
+func (ec *executionContext) field_Query_logRecordsFetch_argsSources(
 	ctx context.Context,
 	rawArgs map[string]any,
 ) ([]string, error) {
@@ -1009,7 +1207,20 @@ func (ec *executionContext) field_Subscription_logRecordsFollow_argsSources(
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Subscription_logRecordsFollow_argsSince(
+func (ec *executionContext) field_Query_logRecordsFetch_argsMode(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*model.LogRecordsQueryMode, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("mode"))
+	if tmp, ok := rawArgs["mode"]; ok {
+		return ec.unmarshalOLogRecordsQueryMode2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐLogRecordsQueryMode(ctx, tmp)
+	}
+
+	var zeroVal *model.LogRecordsQueryMode
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Query_logRecordsFetch_argsSince(
 	ctx context.Context,
 	rawArgs map[string]any,
 ) (*string, error) {
@@ -1022,7 +1233,20 @@ func (ec *executionContext) field_Subscription_logRecordsFollow_argsSince(
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Subscription_logRecordsFollow_argsAfter(
+func (ec *executionContext) field_Query_logRecordsFetch_argsUntil(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*string, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("until"))
+	if tmp, ok := rawArgs["until"]; ok {
+		return ec.unmarshalOString2ᚖstring(ctx, tmp)
+	}
+
+	var zeroVal *string
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Query_logRecordsFetch_argsAfter(
 	ctx context.Context,
 	rawArgs map[string]any,
 ) (*string, error) {
@@ -1035,7 +1259,20 @@ func (ec *executionContext) field_Subscription_logRecordsFollow_argsAfter(
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Subscription_logRecordsFollow_argsGrep(
+func (ec *executionContext) field_Query_logRecordsFetch_argsBefore(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*string, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("before"))
+	if tmp, ok := rawArgs["before"]; ok {
+		return ec.unmarshalOString2ᚖstring(ctx, tmp)
+	}
+
+	var zeroVal *string
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Query_logRecordsFetch_argsGrep(
 	ctx context.Context,
 	rawArgs map[string]any,
 ) (*string, error) {
@@ -1048,7 +1285,20 @@ func (ec *executionContext) field_Subscription_logRecordsFollow_argsGrep(
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Subscription_logRecordsFollow_argsSourceFilter(
+func (ec *executionContext) field_Query_logRecordsFetch_argsFilter(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*model.LogRecordsFilter, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("filter"))
+	if tmp, ok := rawArgs["filter"]; ok {
+		return ec.unmarshalOLogRecordsFilter2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐLogRecordsFilter(ctx, tmp)
+	}
+
+	var zeroVal *model.LogRecordsFilter
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Query_logRecordsFetch_argsSourceFilter(
 	ctx context.Context,
 	rawArgs map[string]any,
 ) (*model.LogSourceFilter, error) {
@@ -1061,27 +1311,88 @@ func (ec *executionContext) field_Subscription_logRecordsFollow_argsSourceFilter
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Subscription_logSourcesWatch_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+func (ec *executionContext) field_Query_logRecordsFetch_argsServingOnly(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*bool, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("servingOnly"))
+	if tmp, ok := rawArgs["servingOnly"]; ok {
+		return ec.unmarshalOBoolean2ᚖbool(ctx, tmp)
+	}
+
+	var zeroVal *bool
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Query_logRecordsFetch_argsLimit(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*int, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("limit"))
+	directive0 := func(ctx context.Context) (any, error) {
+		tmp, ok := rawArgs["limit"]
+		if !ok {
+			var zeroVal *int
+			return zeroVal, nil
+		}
+		return ec.unmarshalOInt2ᚖint(ctx, tmp)
+	}
+
+	directive1 := func(ctx context.Context) (any, error) {
+		rule, err := ec.unmarshalNString2string(ctx, "gt=0")
+		if err != nil {
+			var zeroVal *int
+			return zeroVal, err
+		}
+		message, err := ec.unmarshalOString2ᚖstring(ctx, "Value must be > 0")
+		if err != nil {
+			var zeroVal *int
+			return zeroVal, err
+		}
+		if ec.directives.Validate == nil {
+			var zeroVal *int
+			return zeroVal, errors.New("directive validate is not implemented")
+		}
+		return ec.directives.Validate(ctx, rawArgs, directive0, rule, message)
+	}
+
+	tmp, err := directive1(ctx)
+	if err != nil {
+		var zeroVal *int
+		return zeroVal, graphql.ErrorOnPath(ctx, err)
+	}
+	if data, ok := tmp.(*int); ok {
+		return data, nil
+	} else if tmp == nil {
+		var zeroVal *int
+		return zeroVal, nil
+	} else {
+		var zeroVal *int
+		return zeroVal, graphql.ErrorOnPath(ctx, fmt.Errorf(`unexpected type %T from directive, should be *int`, tmp))
+	}
+}
+
+func (ec *executionContext) field_Query_logUsageSummary_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
-	arg0, err := ec.field_Subscription_logSourcesWatch_argsKubeContext(ctx, rawArgs)
+	arg0, err := ec.field_Query_logUsageSummary_argsNamespace(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
-	args["kubeContext"] = arg0
-	arg1, err := ec.field_Subscription_logSourcesWatch_argsSources(ctx, rawArgs)
+	args["namespace"] = arg0
+	arg1, err := ec.field_Query_logUsageSummary_argsGroupBy(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
-	args["sources"] = arg1
+	args["groupBy"] = arg1
 	return args, nil
 }
-func (ec *executionContext) field_Subscription_logSourcesWatch_argsKubeContext(
+func (ec *executionContext) field_Query_logUsageSummary_argsNamespace(
 	ctx context.Context,
 	rawArgs map[string]any,
 ) (*string, error) {
-	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("kubeContext"))
-	if tmp, ok := rawArgs["kubeContext"]; ok {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
+	if tmp, ok := rawArgs["namespace"]; ok {
 		return ec.unmarshalOString2ᚖstring(ctx, tmp)
 	}
 
@@ -1089,121 +1400,1408 @@ func (ec *executionContext) field_Subscription_logSourcesWatch_argsKubeContext(
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field_Subscription_logSourcesWatch_argsSources(
+func (ec *executionContext) field_Query_logUsageSummary_argsGroupBy(
 	ctx context.Context,
 	rawArgs map[string]any,
-) ([]string, error) {
-	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("sources"))
-	if tmp, ok := rawArgs["sources"]; ok {
-		return ec.unmarshalNString2ᚕstringᚄ(ctx, tmp)
+) (*logs.UsageGroupBy, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("groupBy"))
+	if tmp, ok := rawArgs["groupBy"]; ok {
+		return ec.unmarshalOLogUsageGroupBy2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐUsageGroupBy(ctx, tmp)
 	}
 
-	var zeroVal []string
+	var zeroVal *logs.UsageGroupBy
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field___Directive_args_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+func (ec *executionContext) field_Subscription_logMetadataWatch_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
-	arg0, err := ec.field___Directive_args_argsIncludeDeprecated(ctx, rawArgs)
+	arg0, err := ec.field_Subscription_logMetadataWatch_argsNamespace(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
-	args["includeDeprecated"] = arg0
+	args["namespace"] = arg0
 	return args, nil
 }
-func (ec *executionContext) field___Directive_args_argsIncludeDeprecated(
+func (ec *executionContext) field_Subscription_logMetadataWatch_argsNamespace(
 	ctx context.Context,
 	rawArgs map[string]any,
-) (*bool, error) {
-	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("includeDeprecated"))
-	if tmp, ok := rawArgs["includeDeprecated"]; ok {
-		return ec.unmarshalOBoolean2ᚖbool(ctx, tmp)
+) (*string, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("namespace"))
+	if tmp, ok := rawArgs["namespace"]; ok {
+		return ec.unmarshalOString2ᚖstring(ctx, tmp)
 	}
 
-	var zeroVal *bool
+	var zeroVal *string
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field___Field_args_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+func (ec *executionContext) field_Subscription_logRecordsFollow_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
-	arg0, err := ec.field___Field_args_argsIncludeDeprecated(ctx, rawArgs)
+	arg0, err := ec.field_Subscription_logRecordsFollow_argsKubeContext(ctx, rawArgs)
 	if err != nil {
 		return nil, err
 	}
-	args["includeDeprecated"] = arg0
+	args["kubeContext"] = arg0
+	arg1, err := ec.field_Subscription_logRecordsFollow_argsSources(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["sources"] = arg1
+	arg2, err := ec.field_Subscription_logRecordsFollow_argsSince(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["since"] = arg2
+	arg3, err := ec.field_Subscription_logRecordsFollow_argsAfter(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["after"] = arg3
+	arg4, err := ec.field_Subscription_logRecordsFollow_argsGrep(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["grep"] = arg4
+	arg5, err := ec.field_Subscription_logRecordsFollow_argsFilter(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["filter"] = arg5
+	arg6, err := ec.field_Subscription_logRecordsFollow_argsSourceFilter(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["sourceFilter"] = arg6
+	arg7, err := ec.field_Subscription_logRecordsFollow_argsServingOnly(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["servingOnly"] = arg7
+	arg8, err := ec.field_Subscription_logRecordsFollow_argsWithLifecycle(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["withLifecycle"] = arg8
 	return args, nil
 }
-func (ec *executionContext) field___Field_args_argsIncludeDeprecated(
+func (ec *executionContext) field_Subscription_logRecordsFollow_argsKubeContext(
 	ctx context.Context,
 	rawArgs map[string]any,
-) (*bool, error) {
-	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("includeDeprecated"))
-	if tmp, ok := rawArgs["includeDeprecated"]; ok {
-		return ec.unmarshalOBoolean2ᚖbool(ctx, tmp)
+) (*string, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("kubeContext"))
+	if tmp, ok := rawArgs["kubeContext"]; ok {
+		return ec.unmarshalOString2ᚖstring(ctx, tmp)
 	}
 
-	var zeroVal *bool
+	var zeroVal *string
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Subscription_logRecordsFollow_argsSources(
+	ctx context.Context,
+	rawArgs map[string]any,
+) ([]string, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("sources"))
+	if tmp, ok := rawArgs["sources"]; ok {
+		return ec.unmarshalNString2ᚕstringᚄ(ctx, tmp)
+	}
+
+	var zeroVal []string
 	return zeroVal, nil
 }
 
-func (ec *executionContext) field___Type_enumValues_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
-	var err error
-	args := map[string]any{}
-	arg0, err := ec.field___Type_enumValues_argsIncludeDeprecated(ctx, rawArgs)
+func (ec *executionContext) field_Subscription_logRecordsFollow_argsSince(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*string, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("since"))
+	if tmp, ok := rawArgs["since"]; ok {
+		return ec.unmarshalOString2ᚖstring(ctx, tmp)
+	}
+
+	var zeroVal *string
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Subscription_logRecordsFollow_argsAfter(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*string, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("after"))
+	if tmp, ok := rawArgs["after"]; ok {
+		return ec.unmarshalOString2ᚖstring(ctx, tmp)
+	}
+
+	var zeroVal *string
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Subscription_logRecordsFollow_argsGrep(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*string, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("grep"))
+	if tmp, ok := rawArgs["grep"]; ok {
+		return ec.unmarshalOString2ᚖstring(ctx, tmp)
+	}
+
+	var zeroVal *string
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Subscription_logRecordsFollow_argsFilter(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*model.LogRecordsFilter, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("filter"))
+	if tmp, ok := rawArgs["filter"]; ok {
+		return ec.unmarshalOLogRecordsFilter2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐLogRecordsFilter(ctx, tmp)
+	}
+
+	var zeroVal *model.LogRecordsFilter
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Subscription_logRecordsFollow_argsSourceFilter(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*model.LogSourceFilter, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("sourceFilter"))
+	if tmp, ok := rawArgs["sourceFilter"]; ok {
+		return ec.unmarshalOLogSourceFilter2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐLogSourceFilter(ctx, tmp)
+	}
+
+	var zeroVal *model.LogSourceFilter
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Subscription_logRecordsFollow_argsServingOnly(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*bool, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("servingOnly"))
+	if tmp, ok := rawArgs["servingOnly"]; ok {
+		return ec.unmarshalOBoolean2ᚖbool(ctx, tmp)
+	}
+
+	var zeroVal *bool
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Subscription_logRecordsFollow_argsWithLifecycle(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*bool, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("withLifecycle"))
+	if tmp, ok := rawArgs["withLifecycle"]; ok {
+		return ec.unmarshalOBoolean2ᚖbool(ctx, tmp)
+	}
+
+	var zeroVal *bool
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Subscription_logSourcesWatch_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := ec.field_Subscription_logSourcesWatch_argsKubeContext(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["kubeContext"] = arg0
+	arg1, err := ec.field_Subscription_logSourcesWatch_argsSources(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["sources"] = arg1
+	return args, nil
+}
+func (ec *executionContext) field_Subscription_logSourcesWatch_argsKubeContext(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*string, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("kubeContext"))
+	if tmp, ok := rawArgs["kubeContext"]; ok {
+		return ec.unmarshalOString2ᚖstring(ctx, tmp)
+	}
+
+	var zeroVal *string
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field_Subscription_logSourcesWatch_argsSources(
+	ctx context.Context,
+	rawArgs map[string]any,
+) ([]string, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("sources"))
+	if tmp, ok := rawArgs["sources"]; ok {
+		return ec.unmarshalNString2ᚕstringᚄ(ctx, tmp)
+	}
+
+	var zeroVal []string
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field___Directive_args_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := ec.field___Directive_args_argsIncludeDeprecated(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["includeDeprecated"] = arg0
+	return args, nil
+}
+func (ec *executionContext) field___Directive_args_argsIncludeDeprecated(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*bool, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("includeDeprecated"))
+	if tmp, ok := rawArgs["includeDeprecated"]; ok {
+		return ec.unmarshalOBoolean2ᚖbool(ctx, tmp)
+	}
+
+	var zeroVal *bool
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field___Field_args_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := ec.field___Field_args_argsIncludeDeprecated(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["includeDeprecated"] = arg0
+	return args, nil
+}
+func (ec *executionContext) field___Field_args_argsIncludeDeprecated(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (*bool, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("includeDeprecated"))
+	if tmp, ok := rawArgs["includeDeprecated"]; ok {
+		return ec.unmarshalOBoolean2ᚖbool(ctx, tmp)
+	}
+
+	var zeroVal *bool
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field___Type_enumValues_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := ec.field___Type_enumValues_argsIncludeDeprecated(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["includeDeprecated"] = arg0
+	return args, nil
+}
+func (ec *executionContext) field___Type_enumValues_argsIncludeDeprecated(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (bool, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("includeDeprecated"))
+	if tmp, ok := rawArgs["includeDeprecated"]; ok {
+		return ec.unmarshalOBoolean2bool(ctx, tmp)
+	}
+
+	var zeroVal bool
+	return zeroVal, nil
+}
+
+func (ec *executionContext) field___Type_fields_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := ec.field___Type_fields_argsIncludeDeprecated(ctx, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+	args["includeDeprecated"] = arg0
+	return args, nil
+}
+func (ec *executionContext) field___Type_fields_argsIncludeDeprecated(
+	ctx context.Context,
+	rawArgs map[string]any,
+) (bool, error) {
+	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("includeDeprecated"))
+	if tmp, ok := rawArgs["includeDeprecated"]; ok {
+		return ec.unmarshalOBoolean2bool(ctx, tmp)
+	}
+
+	var zeroVal bool
+	return zeroVal, nil
+}
+
+// endregion ***************************** args.gotpl *****************************
+
+// region    ************************** directives.gotpl **************************
+
+// endregion ************************** directives.gotpl **************************
+
+// region    **************************** field.gotpl *****************************
+
+func (ec *executionContext) _LogMetadata_id(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadata) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogMetadata_id(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Id, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNID2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogMetadata_id(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogMetadata",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type ID does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogMetadata_spec(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadata) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogMetadata_spec(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Spec, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*clusteragentpb.LogMetadataSpec)
+	fc.Result = res
+	return ec.marshalNLogMetadataSpec2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋclusteragentpbᚐLogMetadataSpec(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogMetadata_spec(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogMetadata",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "nodeName":
+				return ec.fieldContext_LogMetadataSpec_nodeName(ctx, field)
+			case "namespace":
+				return ec.fieldContext_LogMetadataSpec_namespace(ctx, field)
+			case "podName":
+				return ec.fieldContext_LogMetadataSpec_podName(ctx, field)
+			case "containerName":
+				return ec.fieldContext_LogMetadataSpec_containerName(ctx, field)
+			case "containerID":
+				return ec.fieldContext_LogMetadataSpec_containerID(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type LogMetadataSpec", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogMetadata_fileInfo(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadata) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogMetadata_fileInfo(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.FileInfo, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*clusteragentpb.LogMetadataFileInfo)
+	fc.Result = res
+	return ec.marshalNLogMetadataFileInfo2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋclusteragentpbᚐLogMetadataFileInfo(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogMetadata_fileInfo(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogMetadata",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "size":
+				return ec.fieldContext_LogMetadataFileInfo_size(ctx, field)
+			case "lastModifiedAt":
+				return ec.fieldContext_LogMetadataFileInfo_lastModifiedAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type LogMetadataFileInfo", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogMetadataFileInfo_size(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataFileInfo) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogMetadataFileInfo_size(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Size, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int64)
+	fc.Result = res
+	return ec.marshalNInt642int64(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogMetadataFileInfo_size(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogMetadataFileInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int64 does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogMetadataFileInfo_lastModifiedAt(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataFileInfo) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogMetadataFileInfo_lastModifiedAt(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.LastModifiedAt, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*timestamppb.Timestamp)
+	fc.Result = res
+	return ec.marshalOTimestampPBTimestamp2ᚖgoogleᚗgolangᚗorgᚋprotobufᚋtypesᚋknownᚋtimestamppbᚐTimestamp(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogMetadataFileInfo_lastModifiedAt(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogMetadataFileInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type TimestampPBTimestamp does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogMetadataList_items(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataList) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogMetadataList_items(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Items, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*clusteragentpb.LogMetadata)
+	fc.Result = res
+	return ec.marshalNLogMetadata2ᚕᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋclusteragentpbᚐLogMetadataᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogMetadataList_items(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogMetadataList",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_LogMetadata_id(ctx, field)
+			case "spec":
+				return ec.fieldContext_LogMetadata_spec(ctx, field)
+			case "fileInfo":
+				return ec.fieldContext_LogMetadata_fileInfo(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type LogMetadata", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogMetadataSpec_nodeName(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataSpec) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogMetadataSpec_nodeName(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.NodeName, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogMetadataSpec_nodeName(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogMetadataSpec",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogMetadataSpec_namespace(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataSpec) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogMetadataSpec_namespace(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Namespace, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogMetadataSpec_namespace(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogMetadataSpec",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogMetadataSpec_podName(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataSpec) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogMetadataSpec_podName(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.PodName, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogMetadataSpec_podName(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogMetadataSpec",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogMetadataSpec_containerName(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataSpec) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogMetadataSpec_containerName(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ContainerName, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogMetadataSpec_containerName(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogMetadataSpec",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogMetadataSpec_containerID(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataSpec) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogMetadataSpec_containerID(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ContainerId, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNID2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogMetadataSpec_containerID(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogMetadataSpec",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type ID does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogMetadataWatchEvent_type(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataWatchEvent) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogMetadataWatchEvent_type(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Type, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogMetadataWatchEvent_type(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogMetadataWatchEvent",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogMetadataWatchEvent_object(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataWatchEvent) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogMetadataWatchEvent_object(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Object, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*clusteragentpb.LogMetadata)
+	fc.Result = res
+	return ec.marshalOLogMetadata2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋclusteragentpbᚐLogMetadata(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogMetadataWatchEvent_object(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogMetadataWatchEvent",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_LogMetadata_id(ctx, field)
+			case "spec":
+				return ec.fieldContext_LogMetadata_spec(ctx, field)
+			case "fileInfo":
+				return ec.fieldContext_LogMetadata_fileInfo(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type LogMetadata", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogRecord_timestamp(ctx context.Context, field graphql.CollectedField, obj *logs.LogRecord) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogRecord_timestamp(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Timestamp, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(time.Time)
+	fc.Result = res
+	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogRecord_timestamp(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogRecord",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Time does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogRecord_message(ctx context.Context, field graphql.CollectedField, obj *logs.LogRecord) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogRecord_message(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Message, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogRecord_message(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogRecord",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogRecord_source(ctx context.Context, field graphql.CollectedField, obj *logs.LogRecord) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogRecord_source(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Source, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(logs.LogSource)
+	fc.Result = res
+	return ec.marshalNLogSource2githubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogSource(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogRecord_source(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogRecord",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "metadata":
+				return ec.fieldContext_LogSource_metadata(ctx, field)
+			case "namespace":
+				return ec.fieldContext_LogSource_namespace(ctx, field)
+			case "podName":
+				return ec.fieldContext_LogSource_podName(ctx, field)
+			case "containerName":
+				return ec.fieldContext_LogSource_containerName(ctx, field)
+			case "containerID":
+				return ec.fieldContext_LogSource_containerID(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type LogSource", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogRecord_lifecycle(ctx context.Context, field graphql.CollectedField, obj *logs.LogRecord) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogRecord_lifecycle(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Lifecycle, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*logs.Lifecycle)
+	fc.Result = res
+	return ec.marshalOLogRecordLifecycle2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLifecycle(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogRecord_lifecycle(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogRecord",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "type":
+				return ec.fieldContext_LogRecordLifecycle_type(ctx, field)
+			case "restartCount":
+				return ec.fieldContext_LogRecordLifecycle_restartCount(ctx, field)
+			case "exitCode":
+				return ec.fieldContext_LogRecordLifecycle_exitCode(ctx, field)
+			case "reason":
+				return ec.fieldContext_LogRecordLifecycle_reason(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type LogRecordLifecycle", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogRecordLifecycle_type(ctx context.Context, field graphql.CollectedField, obj *logs.Lifecycle) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogRecordLifecycle_type(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Type, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(logs.LifecycleType)
+	fc.Result = res
+	return ec.marshalNLogRecordLifecycleType2githubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLifecycleType(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogRecordLifecycle_type(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogRecordLifecycle",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type LogRecordLifecycleType does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogRecordLifecycle_restartCount(ctx context.Context, field graphql.CollectedField, obj *logs.Lifecycle) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogRecordLifecycle_restartCount(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.RestartCount, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int32)
+	fc.Result = res
+	return ec.marshalNInt2int32(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogRecordLifecycle_restartCount(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogRecordLifecycle",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogRecordLifecycle_exitCode(ctx context.Context, field graphql.CollectedField, obj *logs.Lifecycle) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogRecordLifecycle_exitCode(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ExitCode, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int32)
+	fc.Result = res
+	return ec.marshalNInt2int32(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogRecordLifecycle_exitCode(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogRecordLifecycle",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogRecordLifecycle_reason(ctx context.Context, field graphql.CollectedField, obj *logs.Lifecycle) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogRecordLifecycle_reason(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Reason, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogRecordLifecycle_reason(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogRecordLifecycle",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogRecordsCountBucket_start(ctx context.Context, field graphql.CollectedField, obj *logs.CountBucket) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogRecordsCountBucket_start(ctx, field)
 	if err != nil {
-		return nil, err
+		return graphql.Null
 	}
-	args["includeDeprecated"] = arg0
-	return args, nil
-}
-func (ec *executionContext) field___Type_enumValues_argsIncludeDeprecated(
-	ctx context.Context,
-	rawArgs map[string]any,
-) (bool, error) {
-	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("includeDeprecated"))
-	if tmp, ok := rawArgs["includeDeprecated"]; ok {
-		return ec.unmarshalOBoolean2bool(ctx, tmp)
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.LogRecordsCountBucket().Start(rctx, obj)
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
 	}
+	res := resTmp.(*time.Time)
+	fc.Result = res
+	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
+}
 
-	var zeroVal bool
-	return zeroVal, nil
+func (ec *executionContext) fieldContext_LogRecordsCountBucket_start(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogRecordsCountBucket",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Time does not have child fields")
+		},
+	}
+	return fc, nil
 }
 
-func (ec *executionContext) field___Type_fields_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
-	var err error
-	args := map[string]any{}
-	arg0, err := ec.field___Type_fields_argsIncludeDeprecated(ctx, rawArgs)
+func (ec *executionContext) _LogRecordsCountBucket_count(ctx context.Context, field graphql.CollectedField, obj *logs.CountBucket) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogRecordsCountBucket_count(ctx, field)
 	if err != nil {
-		return nil, err
+		return graphql.Null
 	}
-	args["includeDeprecated"] = arg0
-	return args, nil
-}
-func (ec *executionContext) field___Type_fields_argsIncludeDeprecated(
-	ctx context.Context,
-	rawArgs map[string]any,
-) (bool, error) {
-	ctx = graphql.WithPathContext(ctx, graphql.NewPathWithField("includeDeprecated"))
-	if tmp, ok := rawArgs["includeDeprecated"]; ok {
-		return ec.unmarshalOBoolean2bool(ctx, tmp)
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Count, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
 	}
-
-	var zeroVal bool
-	return zeroVal, nil
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int64)
+	fc.Result = res
+	return ec.marshalNInt642int64(ctx, field.Selections, res)
 }
 
-// endregion ***************************** args.gotpl *****************************
-
-// region    ************************** directives.gotpl **************************
-
-// endregion ************************** directives.gotpl **************************
-
-// region    **************************** field.gotpl *****************************
+func (ec *executionContext) fieldContext_LogRecordsCountBucket_count(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogRecordsCountBucket",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int64 does not have child fields")
+		},
+	}
+	return fc, nil
+}
 
-func (ec *executionContext) _LogMetadata_id(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadata) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogMetadata_id(ctx, field)
+func (ec *executionContext) _LogRecordsCountResponse_total(ctx context.Context, field graphql.CollectedField, obj *logs.CountResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogRecordsCountResponse_total(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1216,7 +2814,7 @@ func (ec *executionContext) _LogMetadata_id(ctx context.Context, field graphql.C
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Id, nil
+		return obj.Total, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -1228,26 +2826,26 @@ func (ec *executionContext) _LogMetadata_id(ctx context.Context, field graphql.C
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(int64)
 	fc.Result = res
-	return ec.marshalNID2string(ctx, field.Selections, res)
+	return ec.marshalNInt642int64(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogMetadata_id(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogRecordsCountResponse_total(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogMetadata",
+		Object:     "LogRecordsCountResponse",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type ID does not have child fields")
+			return nil, errors.New("field of type Int64 does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogMetadata_spec(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadata) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogMetadata_spec(ctx, field)
+func (ec *executionContext) _LogRecordsCountResponse_buckets(ctx context.Context, field graphql.CollectedField, obj *logs.CountResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogRecordsCountResponse_buckets(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1260,7 +2858,7 @@ func (ec *executionContext) _LogMetadata_spec(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Spec, nil
+		return obj.Buckets, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -1272,38 +2870,32 @@ func (ec *executionContext) _LogMetadata_spec(ctx context.Context, field graphql
 		}
 		return graphql.Null
 	}
-	res := resTmp.(*clusteragentpb.LogMetadataSpec)
+	res := resTmp.([]logs.CountBucket)
 	fc.Result = res
-	return ec.marshalNLogMetadataSpec2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋclusteragentpbᚐLogMetadataSpec(ctx, field.Selections, res)
+	return ec.marshalNLogRecordsCountBucket2ᚕgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐCountBucketᚄ(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogMetadata_spec(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogRecordsCountResponse_buckets(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogMetadata",
+		Object:     "LogRecordsCountResponse",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "nodeName":
-				return ec.fieldContext_LogMetadataSpec_nodeName(ctx, field)
-			case "namespace":
-				return ec.fieldContext_LogMetadataSpec_namespace(ctx, field)
-			case "podName":
-				return ec.fieldContext_LogMetadataSpec_podName(ctx, field)
-			case "containerName":
-				return ec.fieldContext_LogMetadataSpec_containerName(ctx, field)
-			case "containerID":
-				return ec.fieldContext_LogMetadataSpec_containerID(ctx, field)
+			case "start":
+				return ec.fieldContext_LogRecordsCountBucket_start(ctx, field)
+			case "count":
+				return ec.fieldContext_LogRecordsCountBucket_count(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type LogMetadataSpec", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type LogRecordsCountBucket", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogMetadata_fileInfo(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadata) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogMetadata_fileInfo(ctx, field)
+func (ec *executionContext) _LogRecordsCountResponse_sources(ctx context.Context, field graphql.CollectedField, obj *logs.CountResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogRecordsCountResponse_sources(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1316,7 +2908,7 @@ func (ec *executionContext) _LogMetadata_fileInfo(ctx context.Context, field gra
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.FileInfo, nil
+		return obj.Sources, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -1328,32 +2920,34 @@ func (ec *executionContext) _LogMetadata_fileInfo(ctx context.Context, field gra
 		}
 		return graphql.Null
 	}
-	res := resTmp.(*clusteragentpb.LogMetadataFileInfo)
+	res := resTmp.([]logs.SourceCount)
 	fc.Result = res
-	return ec.marshalNLogMetadataFileInfo2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋclusteragentpbᚐLogMetadataFileInfo(ctx, field.Selections, res)
+	return ec.marshalNLogRecordsSourceCount2ᚕgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐSourceCountᚄ(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogMetadata_fileInfo(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogRecordsCountResponse_sources(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogMetadata",
+		Object:     "LogRecordsCountResponse",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "size":
-				return ec.fieldContext_LogMetadataFileInfo_size(ctx, field)
-			case "lastModifiedAt":
-				return ec.fieldContext_LogMetadataFileInfo_lastModifiedAt(ctx, field)
+			case "source":
+				return ec.fieldContext_LogRecordsSourceCount_source(ctx, field)
+			case "total":
+				return ec.fieldContext_LogRecordsSourceCount_total(ctx, field)
+			case "buckets":
+				return ec.fieldContext_LogRecordsSourceCount_buckets(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type LogMetadataFileInfo", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type LogRecordsSourceCount", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogMetadataFileInfo_size(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataFileInfo) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogMetadataFileInfo_size(ctx, field)
+func (ec *executionContext) _LogRecordsQueryResponse_records(ctx context.Context, field graphql.CollectedField, obj *model.LogRecordsQueryResponse) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogRecordsQueryResponse_records(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1366,7 +2960,7 @@ func (ec *executionContext) _LogMetadataFileInfo_size(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Size, nil
+		return obj.Records, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -1378,26 +2972,36 @@ func (ec *executionContext) _LogMetadataFileInfo_size(ctx context.Context, field
 		}
 		return graphql.Null
 	}
-	res := resTmp.(int64)
+	res := resTmp.([]*logs.LogRecord)
 	fc.Result = res
-	return ec.marshalNInt642int64(ctx, field.Selections, res)
+	return ec.marshalNLogRecord2ᚕᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogRecordᚄ(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogMetadataFileInfo_size(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogRecordsQueryResponse_records(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogMetadataFileInfo",
+		Object:     "LogRecordsQueryResponse",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int64 does not have child fields")
+			switch field.Name {
+			case "timestamp":
+				return ec.fieldContext_LogRecord_timestamp(ctx, field)
+			case "message":
+				return ec.fieldContext_LogRecord_message(ctx, field)
+			case "source":
+				return ec.fieldContext_LogRecord_source(ctx, field)
+			case "lifecycle":
+				return ec.fieldContext_LogRecord_lifecycle(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type LogRecord", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogMetadataFileInfo_lastModifiedAt(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataFileInfo) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogMetadataFileInfo_lastModifiedAt(ctx, field)
+func (ec *executionContext) _LogRecordsQueryResponse_nextCursor(ctx context.Context, field graphql.CollectedField, obj *model.LogRecordsQueryResponse) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogRecordsQueryResponse_nextCursor(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1410,7 +3014,7 @@ func (ec *executionContext) _LogMetadataFileInfo_lastModifiedAt(ctx context.Cont
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.LastModifiedAt, nil
+		return obj.NextCursor, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -1419,26 +3023,26 @@ func (ec *executionContext) _LogMetadataFileInfo_lastModifiedAt(ctx context.Cont
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*timestamppb.Timestamp)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOTimestampPBTimestamp2ᚖgoogleᚗgolangᚗorgᚋprotobufᚋtypesᚋknownᚋtimestamppbᚐTimestamp(ctx, field.Selections, res)
+	return ec.marshalOID2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogMetadataFileInfo_lastModifiedAt(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogRecordsQueryResponse_nextCursor(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogMetadataFileInfo",
+		Object:     "LogRecordsQueryResponse",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type TimestampPBTimestamp does not have child fields")
+			return nil, errors.New("field of type ID does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogMetadataList_items(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataList) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogMetadataList_items(ctx, field)
+func (ec *executionContext) _LogRecordsQueryResponse_fetchPaths(ctx context.Context, field graphql.CollectedField, obj *model.LogRecordsQueryResponse) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogRecordsQueryResponse_fetchPaths(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1451,7 +3055,7 @@ func (ec *executionContext) _LogMetadataList_items(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Items, nil
+		return obj.FetchPaths, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -1463,34 +3067,88 @@ func (ec *executionContext) _LogMetadataList_items(ctx context.Context, field gr
 		}
 		return graphql.Null
 	}
-	res := resTmp.([]*clusteragentpb.LogMetadata)
+	res := resTmp.([]*model.LogSourceFetchPath)
 	fc.Result = res
-	return ec.marshalNLogMetadata2ᚕᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋclusteragentpbᚐLogMetadataᚄ(ctx, field.Selections, res)
+	return ec.marshalNLogSourceFetchPath2ᚕᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐLogSourceFetchPathᚄ(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogMetadataList_items(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogRecordsQueryResponse_fetchPaths(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogMetadataList",
+		Object:     "LogRecordsQueryResponse",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "id":
-				return ec.fieldContext_LogMetadata_id(ctx, field)
-			case "spec":
-				return ec.fieldContext_LogMetadata_spec(ctx, field)
-			case "fileInfo":
-				return ec.fieldContext_LogMetadata_fileInfo(ctx, field)
+			case "source":
+				return ec.fieldContext_LogSourceFetchPath_source(ctx, field)
+			case "path":
+				return ec.fieldContext_LogSourceFetchPath_path(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type LogMetadata", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type LogSourceFetchPath", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogMetadataSpec_nodeName(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataSpec) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogMetadataSpec_nodeName(ctx, field)
+func (ec *executionContext) _LogRecordsSourceCount_source(ctx context.Context, field graphql.CollectedField, obj *logs.SourceCount) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogRecordsSourceCount_source(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Source, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(logs.LogSource)
+	fc.Result = res
+	return ec.marshalNLogSource2githubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogSource(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_LogRecordsSourceCount_source(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "LogRecordsSourceCount",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "metadata":
+				return ec.fieldContext_LogSource_metadata(ctx, field)
+			case "namespace":
+				return ec.fieldContext_LogSource_namespace(ctx, field)
+			case "podName":
+				return ec.fieldContext_LogSource_podName(ctx, field)
+			case "containerName":
+				return ec.fieldContext_LogSource_containerName(ctx, field)
+			case "containerID":
+				return ec.fieldContext_LogSource_containerID(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type LogSource", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _LogRecordsSourceCount_total(ctx context.Context, field graphql.CollectedField, obj *logs.SourceCount) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogRecordsSourceCount_total(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1503,7 +3161,7 @@ func (ec *executionContext) _LogMetadataSpec_nodeName(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.NodeName, nil
+		return obj.Total, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -1515,26 +3173,26 @@ func (ec *executionContext) _LogMetadataSpec_nodeName(ctx context.Context, field
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(int64)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalNInt642int64(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogMetadataSpec_nodeName(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogRecordsSourceCount_total(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogMetadataSpec",
+		Object:     "LogRecordsSourceCount",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Int64 does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogMetadataSpec_namespace(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataSpec) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogMetadataSpec_namespace(ctx, field)
+func (ec *executionContext) _LogRecordsSourceCount_buckets(ctx context.Context, field graphql.CollectedField, obj *logs.SourceCount) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogRecordsSourceCount_buckets(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1547,7 +3205,7 @@ func (ec *executionContext) _LogMetadataSpec_namespace(ctx context.Context, fiel
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Namespace, nil
+		return obj.Buckets, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -1559,26 +3217,32 @@ func (ec *executionContext) _LogMetadataSpec_namespace(ctx context.Context, fiel
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.([]logs.CountBucket)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalNLogRecordsCountBucket2ᚕgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐCountBucketᚄ(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogMetadataSpec_namespace(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogRecordsSourceCount_buckets(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogMetadataSpec",
+		Object:     "LogRecordsSourceCount",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			switch field.Name {
+			case "start":
+				return ec.fieldContext_LogRecordsCountBucket_start(ctx, field)
+			case "count":
+				return ec.fieldContext_LogRecordsCountBucket_count(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type LogRecordsCountBucket", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogMetadataSpec_podName(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataSpec) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogMetadataSpec_podName(ctx, field)
+func (ec *executionContext) _LogSource_metadata(ctx context.Context, field graphql.CollectedField, obj *logs.LogSource) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogSource_metadata(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1591,7 +3255,7 @@ func (ec *executionContext) _LogMetadataSpec_podName(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.PodName, nil
+		return obj.Metadata, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -1603,26 +3267,38 @@ func (ec *executionContext) _LogMetadataSpec_podName(ctx context.Context, field
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(logs.LogSourceMetadata)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalNLogSourceMetadata2githubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogSourceMetadata(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogMetadataSpec_podName(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogSource_metadata(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogMetadataSpec",
+		Object:     "LogSource",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			switch field.Name {
+			case "region":
+				return ec.fieldContext_LogSourceMetadata_region(ctx, field)
+			case "zone":
+				return ec.fieldContext_LogSourceMetadata_zone(ctx, field)
+			case "os":
+				return ec.fieldContext_LogSourceMetadata_os(ctx, field)
+			case "arch":
+				return ec.fieldContext_LogSourceMetadata_arch(ctx, field)
+			case "node":
+				return ec.fieldContext_LogSourceMetadata_node(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type LogSourceMetadata", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogMetadataSpec_containerName(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataSpec) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogMetadataSpec_containerName(ctx, field)
+func (ec *executionContext) _LogSource_namespace(ctx context.Context, field graphql.CollectedField, obj *logs.LogSource) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogSource_namespace(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1635,7 +3311,7 @@ func (ec *executionContext) _LogMetadataSpec_containerName(ctx context.Context,
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ContainerName, nil
+		return obj.Namespace, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -1652,9 +3328,9 @@ func (ec *executionContext) _LogMetadataSpec_containerName(ctx context.Context,
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogMetadataSpec_containerName(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogSource_namespace(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogMetadataSpec",
+		Object:     "LogSource",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -1665,8 +3341,8 @@ func (ec *executionContext) fieldContext_LogMetadataSpec_containerName(_ context
 	return fc, nil
 }
 
-func (ec *executionContext) _LogMetadataSpec_containerID(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataSpec) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogMetadataSpec_containerID(ctx, field)
+func (ec *executionContext) _LogSource_podName(ctx context.Context, field graphql.CollectedField, obj *logs.LogSource) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogSource_podName(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1679,7 +3355,7 @@ func (ec *executionContext) _LogMetadataSpec_containerID(ctx context.Context, fi
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ContainerId, nil
+		return obj.PodName, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -1693,24 +3369,24 @@ func (ec *executionContext) _LogMetadataSpec_containerID(ctx context.Context, fi
 	}
 	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalNID2string(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogMetadataSpec_containerID(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogSource_podName(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogMetadataSpec",
+		Object:     "LogSource",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type ID does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogMetadataWatchEvent_type(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataWatchEvent) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogMetadataWatchEvent_type(ctx, field)
+func (ec *executionContext) _LogSource_containerName(ctx context.Context, field graphql.CollectedField, obj *logs.LogSource) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogSource_containerName(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1723,7 +3399,7 @@ func (ec *executionContext) _LogMetadataWatchEvent_type(ctx context.Context, fie
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Type, nil
+		return obj.ContainerName, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -1740,9 +3416,9 @@ func (ec *executionContext) _LogMetadataWatchEvent_type(ctx context.Context, fie
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogMetadataWatchEvent_type(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogSource_containerName(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogMetadataWatchEvent",
+		Object:     "LogSource",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -1753,8 +3429,8 @@ func (ec *executionContext) fieldContext_LogMetadataWatchEvent_type(_ context.Co
 	return fc, nil
 }
 
-func (ec *executionContext) _LogMetadataWatchEvent_object(ctx context.Context, field graphql.CollectedField, obj *clusteragentpb.LogMetadataWatchEvent) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogMetadataWatchEvent_object(ctx, field)
+func (ec *executionContext) _LogSource_containerID(ctx context.Context, field graphql.CollectedField, obj *logs.LogSource) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogSource_containerID(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1767,43 +3443,38 @@ func (ec *executionContext) _LogMetadataWatchEvent_object(ctx context.Context, f
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Object, nil
+		return obj.ContainerID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*clusteragentpb.LogMetadata)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalOLogMetadata2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋclusteragentpbᚐLogMetadata(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogMetadataWatchEvent_object(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogSource_containerID(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogMetadataWatchEvent",
+		Object:     "LogSource",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "id":
-				return ec.fieldContext_LogMetadata_id(ctx, field)
-			case "spec":
-				return ec.fieldContext_LogMetadata_spec(ctx, field)
-			case "fileInfo":
-				return ec.fieldContext_LogMetadata_fileInfo(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type LogMetadata", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogRecord_timestamp(ctx context.Context, field graphql.CollectedField, obj *logs.LogRecord) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogRecord_timestamp(ctx, field)
+func (ec *executionContext) _LogSourceFetchPath_source(ctx context.Context, field graphql.CollectedField, obj *model.LogSourceFetchPath) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogSourceFetchPath_source(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1816,7 +3487,7 @@ func (ec *executionContext) _LogRecord_timestamp(ctx context.Context, field grap
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Timestamp, nil
+		return obj.Source, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -1828,26 +3499,38 @@ func (ec *executionContext) _LogRecord_timestamp(ctx context.Context, field grap
 		}
 		return graphql.Null
 	}
-	res := resTmp.(time.Time)
+	res := resTmp.(*logs.LogSource)
 	fc.Result = res
-	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
+	return ec.marshalNLogSource2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogSource(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogRecord_timestamp(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogSourceFetchPath_source(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogRecord",
+		Object:     "LogSourceFetchPath",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Time does not have child fields")
+			switch field.Name {
+			case "metadata":
+				return ec.fieldContext_LogSource_metadata(ctx, field)
+			case "namespace":
+				return ec.fieldContext_LogSource_namespace(ctx, field)
+			case "podName":
+				return ec.fieldContext_LogSource_podName(ctx, field)
+			case "containerName":
+				return ec.fieldContext_LogSource_containerName(ctx, field)
+			case "containerID":
+				return ec.fieldContext_LogSource_containerID(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type LogSource", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogRecord_message(ctx context.Context, field graphql.CollectedField, obj *logs.LogRecord) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogRecord_message(ctx, field)
+func (ec *executionContext) _LogSourceFetchPath_path(ctx context.Context, field graphql.CollectedField, obj *model.LogSourceFetchPath) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogSourceFetchPath_path(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1860,7 +3543,7 @@ func (ec *executionContext) _LogRecord_message(ctx context.Context, field graphq
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Message, nil
+		return obj.Path, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -1872,26 +3555,26 @@ func (ec *executionContext) _LogRecord_message(ctx context.Context, field graphq
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(model.LogFetchPath)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalNLogFetchPath2githubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐLogFetchPath(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogRecord_message(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogSourceFetchPath_path(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogRecord",
+		Object:     "LogSourceFetchPath",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type LogFetchPath does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogRecord_source(ctx context.Context, field graphql.CollectedField, obj *logs.LogRecord) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogRecord_source(ctx, field)
+func (ec *executionContext) _LogSourceMetadata_region(ctx context.Context, field graphql.CollectedField, obj *logs.LogSourceMetadata) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogSourceMetadata_region(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1904,7 +3587,7 @@ func (ec *executionContext) _LogRecord_source(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Source, nil
+		return obj.Region, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -1916,38 +3599,26 @@ func (ec *executionContext) _LogRecord_source(ctx context.Context, field graphql
 		}
 		return graphql.Null
 	}
-	res := resTmp.(logs.LogSource)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalNLogSource2githubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogSource(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogRecord_source(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogSourceMetadata_region(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogRecord",
+		Object:     "LogSourceMetadata",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "metadata":
-				return ec.fieldContext_LogSource_metadata(ctx, field)
-			case "namespace":
-				return ec.fieldContext_LogSource_namespace(ctx, field)
-			case "podName":
-				return ec.fieldContext_LogSource_podName(ctx, field)
-			case "containerName":
-				return ec.fieldContext_LogSource_containerName(ctx, field)
-			case "containerID":
-				return ec.fieldContext_LogSource_containerID(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type LogSource", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogRecordsQueryResponse_records(ctx context.Context, field graphql.CollectedField, obj *model.LogRecordsQueryResponse) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogRecordsQueryResponse_records(ctx, field)
+func (ec *executionContext) _LogSourceMetadata_zone(ctx context.Context, field graphql.CollectedField, obj *logs.LogSourceMetadata) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogSourceMetadata_zone(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1960,7 +3631,7 @@ func (ec *executionContext) _LogRecordsQueryResponse_records(ctx context.Context
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Records, nil
+		return obj.Zone, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -1972,34 +3643,26 @@ func (ec *executionContext) _LogRecordsQueryResponse_records(ctx context.Context
 		}
 		return graphql.Null
 	}
-	res := resTmp.([]*logs.LogRecord)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalNLogRecord2ᚕᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogRecordᚄ(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogRecordsQueryResponse_records(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogSourceMetadata_zone(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogRecordsQueryResponse",
+		Object:     "LogSourceMetadata",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "timestamp":
-				return ec.fieldContext_LogRecord_timestamp(ctx, field)
-			case "message":
-				return ec.fieldContext_LogRecord_message(ctx, field)
-			case "source":
-				return ec.fieldContext_LogRecord_source(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type LogRecord", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogRecordsQueryResponse_nextCursor(ctx context.Context, field graphql.CollectedField, obj *model.LogRecordsQueryResponse) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogRecordsQueryResponse_nextCursor(ctx, field)
+func (ec *executionContext) _LogSourceMetadata_os(ctx context.Context, field graphql.CollectedField, obj *logs.LogSourceMetadata) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogSourceMetadata_os(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2012,35 +3675,38 @@ func (ec *executionContext) _LogRecordsQueryResponse_nextCursor(ctx context.Cont
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.NextCursor, nil
+		return obj.OS, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalOID2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogRecordsQueryResponse_nextCursor(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogSourceMetadata_os(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogRecordsQueryResponse",
+		Object:     "LogSourceMetadata",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type ID does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogSource_metadata(ctx context.Context, field graphql.CollectedField, obj *logs.LogSource) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogSource_metadata(ctx, field)
+func (ec *executionContext) _LogSourceMetadata_arch(ctx context.Context, field graphql.CollectedField, obj *logs.LogSourceMetadata) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogSourceMetadata_arch(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2053,7 +3719,7 @@ func (ec *executionContext) _LogSource_metadata(ctx context.Context, field graph
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Metadata, nil
+		return obj.Arch, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2065,38 +3731,26 @@ func (ec *executionContext) _LogSource_metadata(ctx context.Context, field graph
 		}
 		return graphql.Null
 	}
-	res := resTmp.(logs.LogSourceMetadata)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalNLogSourceMetadata2githubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogSourceMetadata(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogSource_metadata(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogSourceMetadata_arch(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogSource",
+		Object:     "LogSourceMetadata",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "region":
-				return ec.fieldContext_LogSourceMetadata_region(ctx, field)
-			case "zone":
-				return ec.fieldContext_LogSourceMetadata_zone(ctx, field)
-			case "os":
-				return ec.fieldContext_LogSourceMetadata_os(ctx, field)
-			case "arch":
-				return ec.fieldContext_LogSourceMetadata_arch(ctx, field)
-			case "node":
-				return ec.fieldContext_LogSourceMetadata_node(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type LogSourceMetadata", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogSource_namespace(ctx context.Context, field graphql.CollectedField, obj *logs.LogSource) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogSource_namespace(ctx, field)
+func (ec *executionContext) _LogSourceMetadata_node(ctx context.Context, field graphql.CollectedField, obj *logs.LogSourceMetadata) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogSourceMetadata_node(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2109,7 +3763,7 @@ func (ec *executionContext) _LogSource_namespace(ctx context.Context, field grap
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Namespace, nil
+		return obj.Node, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2126,9 +3780,9 @@ func (ec *executionContext) _LogSource_namespace(ctx context.Context, field grap
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogSource_namespace(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogSourceMetadata_node(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogSource",
+		Object:     "LogSourceMetadata",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2139,8 +3793,8 @@ func (ec *executionContext) fieldContext_LogSource_namespace(_ context.Context,
 	return fc, nil
 }
 
-func (ec *executionContext) _LogSource_podName(ctx context.Context, field graphql.CollectedField, obj *logs.LogSource) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogSource_podName(ctx, field)
+func (ec *executionContext) _LogSourceWatchEvent_type(ctx context.Context, field graphql.CollectedField, obj *model.LogSourceWatchEvent) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogSourceWatchEvent_type(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2153,7 +3807,7 @@ func (ec *executionContext) _LogSource_podName(ctx context.Context, field graphq
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.PodName, nil
+		return obj.Type, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2165,26 +3819,26 @@ func (ec *executionContext) _LogSource_podName(ctx context.Context, field graphq
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(watch.EventType)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalNWatchEventType2k8sᚗioᚋapimachineryᚋpkgᚋwatchᚐEventType(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogSource_podName(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogSourceWatchEvent_type(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogSource",
+		Object:     "LogSourceWatchEvent",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type WatchEventType does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogSource_containerName(ctx context.Context, field graphql.CollectedField, obj *logs.LogSource) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogSource_containerName(ctx, field)
+func (ec *executionContext) _LogSourceWatchEvent_object(ctx context.Context, field graphql.CollectedField, obj *model.LogSourceWatchEvent) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogSourceWatchEvent_object(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2197,38 +3851,47 @@ func (ec *executionContext) _LogSource_containerName(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ContainerName, nil
+		return obj.Object, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*logs.LogSource)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalOLogSource2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogSource(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogSource_containerName(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogSourceWatchEvent_object(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogSource",
+		Object:     "LogSourceWatchEvent",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			switch field.Name {
+			case "metadata":
+				return ec.fieldContext_LogSource_metadata(ctx, field)
+			case "namespace":
+				return ec.fieldContext_LogSource_namespace(ctx, field)
+			case "podName":
+				return ec.fieldContext_LogSource_podName(ctx, field)
+			case "containerName":
+				return ec.fieldContext_LogSource_containerName(ctx, field)
+			case "containerID":
+				return ec.fieldContext_LogSource_containerID(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type LogSource", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogSource_containerID(ctx context.Context, field graphql.CollectedField, obj *logs.LogSource) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogSource_containerID(ctx, field)
+func (ec *executionContext) _LogUsageGroup_namespace(ctx context.Context, field graphql.CollectedField, obj *logs.UsageGroup) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogUsageGroup_namespace(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2241,7 +3904,7 @@ func (ec *executionContext) _LogSource_containerID(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ContainerID, nil
+		return obj.Namespace, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2258,9 +3921,9 @@ func (ec *executionContext) _LogSource_containerID(ctx context.Context, field gr
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogSource_containerID(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogUsageGroup_namespace(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogSource",
+		Object:     "LogUsageGroup",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2271,8 +3934,8 @@ func (ec *executionContext) fieldContext_LogSource_containerID(_ context.Context
 	return fc, nil
 }
 
-func (ec *executionContext) _LogSourceMetadata_region(ctx context.Context, field graphql.CollectedField, obj *logs.LogSourceMetadata) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogSourceMetadata_region(ctx, field)
+func (ec *executionContext) _LogUsageGroup_workloadKind(ctx context.Context, field graphql.CollectedField, obj *logs.UsageGroup) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogUsageGroup_workloadKind(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2285,7 +3948,7 @@ func (ec *executionContext) _LogSourceMetadata_region(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Region, nil
+		return obj.WorkloadKind, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2302,9 +3965,9 @@ func (ec *executionContext) _LogSourceMetadata_region(ctx context.Context, field
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogSourceMetadata_region(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogUsageGroup_workloadKind(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogSourceMetadata",
+		Object:     "LogUsageGroup",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2315,8 +3978,8 @@ func (ec *executionContext) fieldContext_LogSourceMetadata_region(_ context.Cont
 	return fc, nil
 }
 
-func (ec *executionContext) _LogSourceMetadata_zone(ctx context.Context, field graphql.CollectedField, obj *logs.LogSourceMetadata) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogSourceMetadata_zone(ctx, field)
+func (ec *executionContext) _LogUsageGroup_workloadName(ctx context.Context, field graphql.CollectedField, obj *logs.UsageGroup) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogUsageGroup_workloadName(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2329,7 +3992,7 @@ func (ec *executionContext) _LogSourceMetadata_zone(ctx context.Context, field g
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Zone, nil
+		return obj.WorkloadName, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2346,9 +4009,9 @@ func (ec *executionContext) _LogSourceMetadata_zone(ctx context.Context, field g
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogSourceMetadata_zone(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogUsageGroup_workloadName(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogSourceMetadata",
+		Object:     "LogUsageGroup",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2359,8 +4022,8 @@ func (ec *executionContext) fieldContext_LogSourceMetadata_zone(_ context.Contex
 	return fc, nil
 }
 
-func (ec *executionContext) _LogSourceMetadata_os(ctx context.Context, field graphql.CollectedField, obj *logs.LogSourceMetadata) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogSourceMetadata_os(ctx, field)
+func (ec *executionContext) _LogUsageGroup_nodeName(ctx context.Context, field graphql.CollectedField, obj *logs.UsageGroup) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogUsageGroup_nodeName(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2373,7 +4036,7 @@ func (ec *executionContext) _LogSourceMetadata_os(ctx context.Context, field gra
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.OS, nil
+		return obj.NodeName, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2390,9 +4053,9 @@ func (ec *executionContext) _LogSourceMetadata_os(ctx context.Context, field gra
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogSourceMetadata_os(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogUsageGroup_nodeName(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogSourceMetadata",
+		Object:     "LogUsageGroup",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2403,8 +4066,8 @@ func (ec *executionContext) fieldContext_LogSourceMetadata_os(_ context.Context,
 	return fc, nil
 }
 
-func (ec *executionContext) _LogSourceMetadata_arch(ctx context.Context, field graphql.CollectedField, obj *logs.LogSourceMetadata) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogSourceMetadata_arch(ctx, field)
+func (ec *executionContext) _LogUsageGroup_containerName(ctx context.Context, field graphql.CollectedField, obj *logs.UsageGroup) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogUsageGroup_containerName(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2417,7 +4080,7 @@ func (ec *executionContext) _LogSourceMetadata_arch(ctx context.Context, field g
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Arch, nil
+		return obj.ContainerName, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2434,9 +4097,9 @@ func (ec *executionContext) _LogSourceMetadata_arch(ctx context.Context, field g
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogSourceMetadata_arch(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogUsageGroup_containerName(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogSourceMetadata",
+		Object:     "LogUsageGroup",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -2447,8 +4110,8 @@ func (ec *executionContext) fieldContext_LogSourceMetadata_arch(_ context.Contex
 	return fc, nil
 }
 
-func (ec *executionContext) _LogSourceMetadata_node(ctx context.Context, field graphql.CollectedField, obj *logs.LogSourceMetadata) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogSourceMetadata_node(ctx, field)
+func (ec *executionContext) _LogUsageGroup_bytes(ctx context.Context, field graphql.CollectedField, obj *logs.UsageGroup) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogUsageGroup_bytes(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2461,7 +4124,7 @@ func (ec *executionContext) _LogSourceMetadata_node(ctx context.Context, field g
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Node, nil
+		return obj.Bytes, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2473,26 +4136,26 @@ func (ec *executionContext) _LogSourceMetadata_node(ctx context.Context, field g
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(int64)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalNInt642int64(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogSourceMetadata_node(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogUsageGroup_bytes(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogSourceMetadata",
+		Object:     "LogUsageGroup",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Int64 does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogSourceWatchEvent_type(ctx context.Context, field graphql.CollectedField, obj *model.LogSourceWatchEvent) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogSourceWatchEvent_type(ctx, field)
+func (ec *executionContext) _LogUsageGroup_bytesPerSecond(ctx context.Context, field graphql.CollectedField, obj *logs.UsageGroup) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogUsageGroup_bytesPerSecond(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2505,38 +4168,35 @@ func (ec *executionContext) _LogSourceWatchEvent_type(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Type, nil
+		return obj.BytesPerSecond, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(watch.EventType)
+	res := resTmp.(*float64)
 	fc.Result = res
-	return ec.marshalNWatchEventType2k8sᚗioᚋapimachineryᚋpkgᚋwatchᚐEventType(ctx, field.Selections, res)
+	return ec.marshalOFloat2ᚖfloat64(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogSourceWatchEvent_type(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogUsageGroup_bytesPerSecond(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogSourceWatchEvent",
+		Object:     "LogUsageGroup",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type WatchEventType does not have child fields")
+			return nil, errors.New("field of type Float does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _LogSourceWatchEvent_object(ctx context.Context, field graphql.CollectedField, obj *model.LogSourceWatchEvent) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_LogSourceWatchEvent_object(ctx, field)
+func (ec *executionContext) _LogUsageGroup_fileCount(ctx context.Context, field graphql.CollectedField, obj *logs.UsageGroup) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_LogUsageGroup_fileCount(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2549,40 +4209,31 @@ func (ec *executionContext) _LogSourceWatchEvent_object(ctx context.Context, fie
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Object, nil
+		return obj.FileCount, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*logs.LogSource)
+	res := resTmp.(int)
 	fc.Result = res
-	return ec.marshalOLogSource2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogSource(ctx, field.Selections, res)
+	return ec.marshalNInt2int(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_LogSourceWatchEvent_object(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_LogUsageGroup_fileCount(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "LogSourceWatchEvent",
+		Object:     "LogUsageGroup",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "metadata":
-				return ec.fieldContext_LogSource_metadata(ctx, field)
-			case "namespace":
-				return ec.fieldContext_LogSource_namespace(ctx, field)
-			case "podName":
-				return ec.fieldContext_LogSource_podName(ctx, field)
-			case "containerName":
-				return ec.fieldContext_LogSource_containerName(ctx, field)
-			case "containerID":
-				return ec.fieldContext_LogSource_containerID(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type LogSource", field.Name)
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
@@ -2803,6 +4454,8 @@ func (ec *executionContext) fieldContext_PodLogQueryResponse_results(_ context.C
 				return ec.fieldContext_LogRecord_message(ctx, field)
 			case "source":
 				return ec.fieldContext_LogRecord_source(ctx, field)
+			case "lifecycle":
+				return ec.fieldContext_LogRecord_lifecycle(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type LogRecord", field.Name)
 		},
@@ -2920,6 +4573,76 @@ func (ec *executionContext) fieldContext_Query_logMetadataList(ctx context.Conte
 	return fc, nil
 }
 
+func (ec *executionContext) _Query_logUsageSummary(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_logUsageSummary(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().LogUsageSummary(rctx, fc.Args["namespace"].(*string), fc.Args["groupBy"].(*logs.UsageGroupBy))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.([]*logs.UsageGroup)
+	fc.Result = res
+	return ec.marshalOLogUsageGroup2ᚕᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐUsageGroupᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_logUsageSummary(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "namespace":
+				return ec.fieldContext_LogUsageGroup_namespace(ctx, field)
+			case "workloadKind":
+				return ec.fieldContext_LogUsageGroup_workloadKind(ctx, field)
+			case "workloadName":
+				return ec.fieldContext_LogUsageGroup_workloadName(ctx, field)
+			case "nodeName":
+				return ec.fieldContext_LogUsageGroup_nodeName(ctx, field)
+			case "containerName":
+				return ec.fieldContext_LogUsageGroup_containerName(ctx, field)
+			case "bytes":
+				return ec.fieldContext_LogUsageGroup_bytes(ctx, field)
+			case "bytesPerSecond":
+				return ec.fieldContext_LogUsageGroup_bytesPerSecond(ctx, field)
+			case "fileCount":
+				return ec.fieldContext_LogUsageGroup_fileCount(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type LogUsageGroup", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_logUsageSummary_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Query_logRecordsFetch(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	fc, err := ec.fieldContext_Query_logRecordsFetch(ctx, field)
 	if err != nil {
@@ -2935,7 +4658,7 @@ func (ec *executionContext) _Query_logRecordsFetch(ctx context.Context, field gr
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		directive0 := func(rctx context.Context) (any, error) {
 			ctx = rctx // use context from middleware stack in children
-			return ec.resolvers.Query().LogRecordsFetch(rctx, fc.Args["kubeContext"].(*string), fc.Args["sources"].([]string), fc.Args["mode"].(*model.LogRecordsQueryMode), fc.Args["since"].(*string), fc.Args["until"].(*string), fc.Args["after"].(*string), fc.Args["before"].(*string), fc.Args["grep"].(*string), fc.Args["sourceFilter"].(*model.LogSourceFilter), fc.Args["limit"].(*int))
+			return ec.resolvers.Query().LogRecordsFetch(rctx, fc.Args["kubeContext"].(*string), fc.Args["sources"].([]string), fc.Args["mode"].(*model.LogRecordsQueryMode), fc.Args["since"].(*string), fc.Args["until"].(*string), fc.Args["after"].(*string), fc.Args["before"].(*string), fc.Args["grep"].(*string), fc.Args["filter"].(*model.LogRecordsFilter), fc.Args["sourceFilter"].(*model.LogSourceFilter), fc.Args["servingOnly"].(*bool), fc.Args["limit"].(*int))
 		}
 
 		directive1 := func(ctx context.Context) (any, error) {
@@ -2982,6 +4705,8 @@ func (ec *executionContext) fieldContext_Query_logRecordsFetch(ctx context.Conte
 				return ec.fieldContext_LogRecordsQueryResponse_records(ctx, field)
 			case "nextCursor":
 				return ec.fieldContext_LogRecordsQueryResponse_nextCursor(ctx, field)
+			case "fetchPaths":
+				return ec.fieldContext_LogRecordsQueryResponse_fetchPaths(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type LogRecordsQueryResponse", field.Name)
 		},
@@ -3000,6 +4725,66 @@ func (ec *executionContext) fieldContext_Query_logRecordsFetch(ctx context.Conte
 	return fc, nil
 }
 
+func (ec *executionContext) _Query_logRecordsCount(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_logRecordsCount(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().LogRecordsCount(rctx, fc.Args["kubeContext"].(*string), fc.Args["sources"].([]string), fc.Args["since"].(*string), fc.Args["until"].(*string), fc.Args["grep"].(*string), fc.Args["filter"].(*model.LogRecordsFilter), fc.Args["sourceFilter"].(*model.LogSourceFilter), fc.Args["servingOnly"].(*bool), fc.Args["bucketSize"].(*string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*logs.CountResult)
+	fc.Result = res
+	return ec.marshalOLogRecordsCountResponse2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐCountResult(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_logRecordsCount(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "total":
+				return ec.fieldContext_LogRecordsCountResponse_total(ctx, field)
+			case "buckets":
+				return ec.fieldContext_LogRecordsCountResponse_buckets(ctx, field)
+			case "sources":
+				return ec.fieldContext_LogRecordsCountResponse_sources(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type LogRecordsCountResponse", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_logRecordsCount_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Query___type(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	fc, err := ec.fieldContext_Query___type(ctx, field)
 	if err != nil {
@@ -3218,7 +5003,7 @@ func (ec *executionContext) _Subscription_logRecordsFollow(ctx context.Context,
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		directive0 := func(rctx context.Context) (any, error) {
 			ctx = rctx // use context from middleware stack in children
-			return ec.resolvers.Subscription().LogRecordsFollow(rctx, fc.Args["kubeContext"].(*string), fc.Args["sources"].([]string), fc.Args["since"].(*string), fc.Args["after"].(*string), fc.Args["grep"].(*string), fc.Args["sourceFilter"].(*model.LogSourceFilter))
+			return ec.resolvers.Subscription().LogRecordsFollow(rctx, fc.Args["kubeContext"].(*string), fc.Args["sources"].([]string), fc.Args["since"].(*string), fc.Args["after"].(*string), fc.Args["grep"].(*string), fc.Args["filter"].(*model.LogRecordsFilter), fc.Args["sourceFilter"].(*model.LogSourceFilter), fc.Args["servingOnly"].(*bool), fc.Args["withLifecycle"].(*bool))
 		}
 
 		directive1 := func(ctx context.Context) (any, error) {
@@ -3281,6 +5066,8 @@ func (ec *executionContext) fieldContext_Subscription_logRecordsFollow(ctx conte
 				return ec.fieldContext_LogRecord_message(ctx, field)
 			case "source":
 				return ec.fieldContext_LogRecord_source(ctx, field)
+			case "lifecycle":
+				return ec.fieldContext_LogRecord_lifecycle(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type LogRecord", field.Name)
 		},
@@ -5315,12 +7102,98 @@ func (ec *executionContext) fieldContext___Type_isOneOf(_ context.Context, field
 			return nil, errors.New("field of type Boolean does not have child fields")
 		},
 	}
-	return fc, nil
-}
-
-// endregion **************************** field.gotpl *****************************
+	return fc, nil
+}
+
+// endregion **************************** field.gotpl *****************************
+
+// region    **************************** input.gotpl *****************************
+
+func (ec *executionContext) unmarshalInputLogRecordsFieldPredicate(ctx context.Context, obj any) (model.LogRecordsFieldPredicate, error) {
+	var it model.LogRecordsFieldPredicate
+	asMap := map[string]any{}
+	for k, v := range obj.(map[string]any) {
+		asMap[k] = v
+	}
+
+	if _, present := asMap["op"]; !present {
+		asMap["op"] = "EQUALS"
+	}
+
+	fieldsInOrder := [...]string{"field", "op", "value"}
+	for _, k := range fieldsInOrder {
+		v, ok := asMap[k]
+		if !ok {
+			continue
+		}
+		switch k {
+		case "field":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("field"))
+			data, err := ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Field = data
+		case "op":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("op"))
+			data, err := ec.unmarshalOLogRecordsFieldOperator2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐFieldOperator(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Op = data
+		case "value":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("value"))
+			data, err := ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Value = data
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputLogRecordsFilter(ctx context.Context, obj any) (model.LogRecordsFilter, error) {
+	var it model.LogRecordsFilter
+	asMap := map[string]any{}
+	for k, v := range obj.(map[string]any) {
+		asMap[k] = v
+	}
+
+	fieldsInOrder := [...]string{"fields", "exclude", "levels"}
+	for _, k := range fieldsInOrder {
+		v, ok := asMap[k]
+		if !ok {
+			continue
+		}
+		switch k {
+		case "fields":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("fields"))
+			data, err := ec.unmarshalOLogRecordsFieldPredicate2ᚕᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐLogRecordsFieldPredicateᚄ(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Fields = data
+		case "exclude":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("exclude"))
+			data, err := ec.unmarshalOString2ᚕstringᚄ(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Exclude = data
+		case "levels":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("levels"))
+			data, err := ec.unmarshalOString2ᚕstringᚄ(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Levels = data
+		}
+	}
 
-// region    **************************** input.gotpl *****************************
+	return it, nil
+}
 
 func (ec *executionContext) unmarshalInputLogSourceFilter(ctx context.Context, obj any) (model.LogSourceFilter, error) {
 	var it model.LogSourceFilter
@@ -5647,6 +7520,183 @@ func (ec *executionContext) _LogRecord(ctx context.Context, sel ast.SelectionSet
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "lifecycle":
+			out.Values[i] = ec._LogRecord_lifecycle(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var logRecordLifecycleImplementors = []string{"LogRecordLifecycle"}
+
+func (ec *executionContext) _LogRecordLifecycle(ctx context.Context, sel ast.SelectionSet, obj *logs.Lifecycle) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, logRecordLifecycleImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("LogRecordLifecycle")
+		case "type":
+			out.Values[i] = ec._LogRecordLifecycle_type(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "restartCount":
+			out.Values[i] = ec._LogRecordLifecycle_restartCount(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "exitCode":
+			out.Values[i] = ec._LogRecordLifecycle_exitCode(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "reason":
+			out.Values[i] = ec._LogRecordLifecycle_reason(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var logRecordsCountBucketImplementors = []string{"LogRecordsCountBucket"}
+
+func (ec *executionContext) _LogRecordsCountBucket(ctx context.Context, sel ast.SelectionSet, obj *logs.CountBucket) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, logRecordsCountBucketImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("LogRecordsCountBucket")
+		case "start":
+			field := field
+
+			innerFunc := func(ctx context.Context, _ *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._LogRecordsCountBucket_start(ctx, field, obj)
+				return res
+			}
+
+			if field.Deferrable != nil {
+				dfs, ok := deferred[field.Deferrable.Label]
+				di := 0
+				if ok {
+					dfs.AddField(field)
+					di = len(dfs.Values) - 1
+				} else {
+					dfs = graphql.NewFieldSet([]graphql.CollectedField{field})
+					deferred[field.Deferrable.Label] = dfs
+				}
+				dfs.Concurrently(di, func(ctx context.Context) graphql.Marshaler {
+					return innerFunc(ctx, dfs)
+				})
+
+				// don't run the out.Concurrently() call below
+				out.Values[i] = graphql.Null
+				continue
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+		case "count":
+			out.Values[i] = ec._LogRecordsCountBucket_count(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&out.Invalids, 1)
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var logRecordsCountResponseImplementors = []string{"LogRecordsCountResponse"}
+
+func (ec *executionContext) _LogRecordsCountResponse(ctx context.Context, sel ast.SelectionSet, obj *logs.CountResult) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, logRecordsCountResponseImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("LogRecordsCountResponse")
+		case "total":
+			out.Values[i] = ec._LogRecordsCountResponse_total(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "buckets":
+			out.Values[i] = ec._LogRecordsCountResponse_buckets(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "sources":
+			out.Values[i] = ec._LogRecordsCountResponse_sources(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -5688,6 +7738,60 @@ func (ec *executionContext) _LogRecordsQueryResponse(ctx context.Context, sel as
 			}
 		case "nextCursor":
 			out.Values[i] = ec._LogRecordsQueryResponse_nextCursor(ctx, field, obj)
+		case "fetchPaths":
+			out.Values[i] = ec._LogRecordsQueryResponse_fetchPaths(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var logRecordsSourceCountImplementors = []string{"LogRecordsSourceCount"}
+
+func (ec *executionContext) _LogRecordsSourceCount(ctx context.Context, sel ast.SelectionSet, obj *logs.SourceCount) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, logRecordsSourceCountImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("LogRecordsSourceCount")
+		case "source":
+			out.Values[i] = ec._LogRecordsSourceCount_source(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "total":
+			out.Values[i] = ec._LogRecordsSourceCount_total(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "buckets":
+			out.Values[i] = ec._LogRecordsSourceCount_buckets(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -5770,6 +7874,50 @@ func (ec *executionContext) _LogSource(ctx context.Context, sel ast.SelectionSet
 	return out
 }
 
+var logSourceFetchPathImplementors = []string{"LogSourceFetchPath"}
+
+func (ec *executionContext) _LogSourceFetchPath(ctx context.Context, sel ast.SelectionSet, obj *model.LogSourceFetchPath) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, logSourceFetchPathImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("LogSourceFetchPath")
+		case "source":
+			out.Values[i] = ec._LogSourceFetchPath_source(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "path":
+			out.Values[i] = ec._LogSourceFetchPath_path(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
 var logSourceMetadataImplementors = []string{"LogSourceMetadata"}
 
 func (ec *executionContext) _LogSourceMetadata(ctx context.Context, sel ast.SelectionSet, obj *logs.LogSourceMetadata) graphql.Marshaler {
@@ -5829,24 +7977,95 @@ func (ec *executionContext) _LogSourceMetadata(ctx context.Context, sel ast.Sele
 	return out
 }
 
-var logSourceWatchEventImplementors = []string{"LogSourceWatchEvent"}
+var logSourceWatchEventImplementors = []string{"LogSourceWatchEvent"}
+
+func (ec *executionContext) _LogSourceWatchEvent(ctx context.Context, sel ast.SelectionSet, obj *model.LogSourceWatchEvent) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, logSourceWatchEventImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("LogSourceWatchEvent")
+		case "type":
+			out.Values[i] = ec._LogSourceWatchEvent_type(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "object":
+			out.Values[i] = ec._LogSourceWatchEvent_object(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
+var logUsageGroupImplementors = []string{"LogUsageGroup"}
 
-func (ec *executionContext) _LogSourceWatchEvent(ctx context.Context, sel ast.SelectionSet, obj *model.LogSourceWatchEvent) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, logSourceWatchEventImplementors)
+func (ec *executionContext) _LogUsageGroup(ctx context.Context, sel ast.SelectionSet, obj *logs.UsageGroup) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, logUsageGroupImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	deferred := make(map[string]*graphql.FieldSet)
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("LogSourceWatchEvent")
-		case "type":
-			out.Values[i] = ec._LogSourceWatchEvent_type(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("LogUsageGroup")
+		case "namespace":
+			out.Values[i] = ec._LogUsageGroup_namespace(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "workloadKind":
+			out.Values[i] = ec._LogUsageGroup_workloadKind(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "workloadName":
+			out.Values[i] = ec._LogUsageGroup_workloadName(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "nodeName":
+			out.Values[i] = ec._LogUsageGroup_nodeName(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "containerName":
+			out.Values[i] = ec._LogUsageGroup_containerName(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "bytes":
+			out.Values[i] = ec._LogUsageGroup_bytes(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "bytesPerSecond":
+			out.Values[i] = ec._LogUsageGroup_bytesPerSecond(ctx, field, obj)
+		case "fileCount":
+			out.Values[i] = ec._LogUsageGroup_fileCount(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
-		case "object":
-			out.Values[i] = ec._LogSourceWatchEvent_object(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -5999,6 +8218,25 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
 			}
 
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "logUsageSummary":
+			field := field
+
+			innerFunc := func(ctx context.Context, _ *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_logUsageSummary(ctx, field)
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
 		case "logRecordsFetch":
 			field := field
@@ -6018,6 +8256,25 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
 			}
 
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
+		case "logRecordsCount":
+			field := field
+
+			innerFunc := func(ctx context.Context, _ *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_logRecordsCount(ctx, field)
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx,
+					func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+			}
+
 			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return rrm(innerCtx) })
 		case "__type":
 			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
@@ -6409,46 +8666,233 @@ func (ec *executionContext) ___Type(ctx context.Context, sel ast.SelectionSet, o
 
 // region    ***************************** type.gotpl *****************************
 
-func (ec *executionContext) unmarshalNBoolean2bool(ctx context.Context, v any) (bool, error) {
-	res, err := graphql.UnmarshalBoolean(v)
-	return res, graphql.ErrorOnPath(ctx, err)
-}
+func (ec *executionContext) unmarshalNBoolean2bool(ctx context.Context, v any) (bool, error) {
+	res, err := graphql.UnmarshalBoolean(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNBoolean2bool(ctx context.Context, sel ast.SelectionSet, v bool) graphql.Marshaler {
+	_ = sel
+	res := graphql.MarshalBoolean(v)
+	if res == graphql.Null {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+	}
+	return res
+}
+
+func (ec *executionContext) unmarshalNID2string(ctx context.Context, v any) (string, error) {
+	res, err := graphql.UnmarshalID(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNID2string(ctx context.Context, sel ast.SelectionSet, v string) graphql.Marshaler {
+	_ = sel
+	res := graphql.MarshalID(v)
+	if res == graphql.Null {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+	}
+	return res
+}
+
+func (ec *executionContext) unmarshalNInt2int(ctx context.Context, v any) (int, error) {
+	res, err := graphql.UnmarshalInt(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNInt2int(ctx context.Context, sel ast.SelectionSet, v int) graphql.Marshaler {
+	_ = sel
+	res := graphql.MarshalInt(v)
+	if res == graphql.Null {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+	}
+	return res
+}
+
+func (ec *executionContext) unmarshalNInt2int32(ctx context.Context, v any) (int32, error) {
+	res, err := graphql.UnmarshalInt32(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNInt2int32(ctx context.Context, sel ast.SelectionSet, v int32) graphql.Marshaler {
+	_ = sel
+	res := graphql.MarshalInt32(v)
+	if res == graphql.Null {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+	}
+	return res
+}
+
+func (ec *executionContext) unmarshalNInt642int64(ctx context.Context, v any) (int64, error) {
+	res, err := model1.UnmarshalInt64(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNInt642int64(ctx context.Context, sel ast.SelectionSet, v int64) graphql.Marshaler {
+	_ = sel
+	res := model1.MarshalInt64(v)
+	if res == graphql.Null {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+	}
+	return res
+}
+
+func (ec *executionContext) unmarshalNLogFetchPath2githubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐLogFetchPath(ctx context.Context, v any) (model.LogFetchPath, error) {
+	var res model.LogFetchPath
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNLogFetchPath2githubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐLogFetchPath(ctx context.Context, sel ast.SelectionSet, v model.LogFetchPath) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) marshalNLogMetadata2ᚕᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋclusteragentpbᚐLogMetadataᚄ(ctx context.Context, sel ast.SelectionSet, v []*clusteragentpb.LogMetadata) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNLogMetadata2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋclusteragentpbᚐLogMetadata(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) marshalNLogMetadata2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋclusteragentpbᚐLogMetadata(ctx context.Context, sel ast.SelectionSet, v *clusteragentpb.LogMetadata) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._LogMetadata(ctx, sel, v)
+}
+
+func (ec *executionContext) marshalNLogMetadataFileInfo2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋclusteragentpbᚐLogMetadataFileInfo(ctx context.Context, sel ast.SelectionSet, v *clusteragentpb.LogMetadataFileInfo) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._LogMetadataFileInfo(ctx, sel, v)
+}
+
+func (ec *executionContext) marshalNLogMetadataSpec2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋclusteragentpbᚐLogMetadataSpec(ctx context.Context, sel ast.SelectionSet, v *clusteragentpb.LogMetadataSpec) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._LogMetadataSpec(ctx, sel, v)
+}
+
+func (ec *executionContext) marshalNLogRecord2ᚕᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogRecordᚄ(ctx context.Context, sel ast.SelectionSet, v []*logs.LogRecord) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNLogRecord2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogRecord(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
 
-func (ec *executionContext) marshalNBoolean2bool(ctx context.Context, sel ast.SelectionSet, v bool) graphql.Marshaler {
-	_ = sel
-	res := graphql.MarshalBoolean(v)
-	if res == graphql.Null {
-		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
-			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
 		}
 	}
-	return res
-}
 
-func (ec *executionContext) unmarshalNID2string(ctx context.Context, v any) (string, error) {
-	res, err := graphql.UnmarshalID(v)
-	return res, graphql.ErrorOnPath(ctx, err)
+	return ret
 }
 
-func (ec *executionContext) marshalNID2string(ctx context.Context, sel ast.SelectionSet, v string) graphql.Marshaler {
-	_ = sel
-	res := graphql.MarshalID(v)
-	if res == graphql.Null {
+func (ec *executionContext) marshalNLogRecord2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogRecord(ctx context.Context, sel ast.SelectionSet, v *logs.LogRecord) graphql.Marshaler {
+	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
 		}
+		return graphql.Null
 	}
-	return res
+	return ec._LogRecord(ctx, sel, v)
 }
 
-func (ec *executionContext) unmarshalNInt642int64(ctx context.Context, v any) (int64, error) {
-	res, err := model1.UnmarshalInt64(v)
+func (ec *executionContext) unmarshalNLogRecordLifecycleType2githubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLifecycleType(ctx context.Context, v any) (logs.LifecycleType, error) {
+	tmp, err := graphql.UnmarshalString(v)
+	res := logs.LifecycleType(tmp)
 	return res, graphql.ErrorOnPath(ctx, err)
 }
 
-func (ec *executionContext) marshalNInt642int64(ctx context.Context, sel ast.SelectionSet, v int64) graphql.Marshaler {
+func (ec *executionContext) marshalNLogRecordLifecycleType2githubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLifecycleType(ctx context.Context, sel ast.SelectionSet, v logs.LifecycleType) graphql.Marshaler {
 	_ = sel
-	res := model1.MarshalInt64(v)
+	res := graphql.MarshalString(string(v))
 	if res == graphql.Null {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
@@ -6457,7 +8901,11 @@ func (ec *executionContext) marshalNInt642int64(ctx context.Context, sel ast.Sel
 	return res
 }
 
-func (ec *executionContext) marshalNLogMetadata2ᚕᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋclusteragentpbᚐLogMetadataᚄ(ctx context.Context, sel ast.SelectionSet, v []*clusteragentpb.LogMetadata) graphql.Marshaler {
+func (ec *executionContext) marshalNLogRecordsCountBucket2githubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐCountBucket(ctx context.Context, sel ast.SelectionSet, v logs.CountBucket) graphql.Marshaler {
+	return ec._LogRecordsCountBucket(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNLogRecordsCountBucket2ᚕgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐCountBucketᚄ(ctx context.Context, sel ast.SelectionSet, v []logs.CountBucket) graphql.Marshaler {
 	ret := make(graphql.Array, len(v))
 	var wg sync.WaitGroup
 	isLen1 := len(v) == 1
@@ -6481,7 +8929,7 @@ func (ec *executionContext) marshalNLogMetadata2ᚕᚖgithubᚗcomᚋkubetailᚑ
 			if !isLen1 {
 				defer wg.Done()
 			}
-			ret[i] = ec.marshalNLogMetadata2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋclusteragentpbᚐLogMetadata(ctx, sel, v[i])
+			ret[i] = ec.marshalNLogRecordsCountBucket2githubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐCountBucket(ctx, sel, v[i])
 		}
 		if isLen1 {
 			f(i)
@@ -6501,37 +8949,74 @@ func (ec *executionContext) marshalNLogMetadata2ᚕᚖgithubᚗcomᚋkubetailᚑ
 	return ret
 }
 
-func (ec *executionContext) marshalNLogMetadata2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋclusteragentpbᚐLogMetadata(ctx context.Context, sel ast.SelectionSet, v *clusteragentpb.LogMetadata) graphql.Marshaler {
-	if v == nil {
-		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
-			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+func (ec *executionContext) unmarshalNLogRecordsFieldPredicate2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐLogRecordsFieldPredicate(ctx context.Context, v any) (*model.LogRecordsFieldPredicate, error) {
+	res, err := ec.unmarshalInputLogRecordsFieldPredicate(ctx, v)
+	return &res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNLogRecordsSourceCount2githubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐSourceCount(ctx context.Context, sel ast.SelectionSet, v logs.SourceCount) graphql.Marshaler {
+	return ec._LogRecordsSourceCount(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNLogRecordsSourceCount2ᚕgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐSourceCountᚄ(ctx context.Context, sel ast.SelectionSet, v []logs.SourceCount) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
 		}
-		return graphql.Null
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNLogRecordsSourceCount2githubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐSourceCount(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
 	}
-	return ec._LogMetadata(ctx, sel, v)
-}
+	wg.Wait()
 
-func (ec *executionContext) marshalNLogMetadataFileInfo2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋclusteragentpbᚐLogMetadataFileInfo(ctx context.Context, sel ast.SelectionSet, v *clusteragentpb.LogMetadataFileInfo) graphql.Marshaler {
-	if v == nil {
-		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
-			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
 		}
-		return graphql.Null
 	}
-	return ec._LogMetadataFileInfo(ctx, sel, v)
+
+	return ret
 }
 
-func (ec *executionContext) marshalNLogMetadataSpec2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋclusteragentpbᚐLogMetadataSpec(ctx context.Context, sel ast.SelectionSet, v *clusteragentpb.LogMetadataSpec) graphql.Marshaler {
+func (ec *executionContext) marshalNLogSource2githubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogSource(ctx context.Context, sel ast.SelectionSet, v logs.LogSource) graphql.Marshaler {
+	return ec._LogSource(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNLogSource2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogSource(ctx context.Context, sel ast.SelectionSet, v *logs.LogSource) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
 		}
 		return graphql.Null
 	}
-	return ec._LogMetadataSpec(ctx, sel, v)
+	return ec._LogSource(ctx, sel, v)
 }
 
-func (ec *executionContext) marshalNLogRecord2ᚕᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogRecordᚄ(ctx context.Context, sel ast.SelectionSet, v []*logs.LogRecord) graphql.Marshaler {
+func (ec *executionContext) marshalNLogSourceFetchPath2ᚕᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐLogSourceFetchPathᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.LogSourceFetchPath) graphql.Marshaler {
 	ret := make(graphql.Array, len(v))
 	var wg sync.WaitGroup
 	isLen1 := len(v) == 1
@@ -6555,7 +9040,7 @@ func (ec *executionContext) marshalNLogRecord2ᚕᚖgithubᚗcomᚋkubetailᚑor
 			if !isLen1 {
 				defer wg.Done()
 			}
-			ret[i] = ec.marshalNLogRecord2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogRecord(ctx, sel, v[i])
+			ret[i] = ec.marshalNLogSourceFetchPath2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐLogSourceFetchPath(ctx, sel, v[i])
 		}
 		if isLen1 {
 			f(i)
@@ -6575,24 +9060,30 @@ func (ec *executionContext) marshalNLogRecord2ᚕᚖgithubᚗcomᚋkubetailᚑor
 	return ret
 }
 
-func (ec *executionContext) marshalNLogRecord2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogRecord(ctx context.Context, sel ast.SelectionSet, v *logs.LogRecord) graphql.Marshaler {
+func (ec *executionContext) marshalNLogSourceFetchPath2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐLogSourceFetchPath(ctx context.Context, sel ast.SelectionSet, v *model.LogSourceFetchPath) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
 		}
 		return graphql.Null
 	}
-	return ec._LogRecord(ctx, sel, v)
-}
-
-func (ec *executionContext) marshalNLogSource2githubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogSource(ctx context.Context, sel ast.SelectionSet, v logs.LogSource) graphql.Marshaler {
-	return ec._LogSource(ctx, sel, &v)
+	return ec._LogSourceFetchPath(ctx, sel, v)
 }
 
 func (ec *executionContext) marshalNLogSourceMetadata2githubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLogSourceMetadata(ctx context.Context, sel ast.SelectionSet, v logs.LogSourceMetadata) graphql.Marshaler {
 	return ec._LogSourceMetadata(ctx, sel, &v)
 }
 
+func (ec *executionContext) marshalNLogUsageGroup2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐUsageGroup(ctx context.Context, sel ast.SelectionSet, v *logs.UsageGroup) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._LogUsageGroup(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalNPageInfo2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐPageInfo(ctx context.Context, sel ast.SelectionSet, v *model.PageInfo) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
@@ -6965,6 +9456,23 @@ func (ec *executionContext) marshalOBoolean2ᚖbool(ctx context.Context, sel ast
 	return res
 }
 
+func (ec *executionContext) unmarshalOFloat2ᚖfloat64(ctx context.Context, v any) (*float64, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := graphql.UnmarshalFloatContext(ctx, v)
+	return &res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOFloat2ᚖfloat64(ctx context.Context, sel ast.SelectionSet, v *float64) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	_ = sel
+	res := graphql.MarshalFloatContext(*v)
+	return graphql.WrapContextMarshaler(ctx, res)
+}
+
 func (ec *executionContext) unmarshalOID2ᚖstring(ctx context.Context, v any) (*string, error) {
 	if v == nil {
 		return nil, nil
@@ -7029,6 +9537,65 @@ func (ec *executionContext) marshalOLogRecord2ᚖgithubᚗcomᚋkubetailᚑorg
 	return ec._LogRecord(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalOLogRecordLifecycle2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐLifecycle(ctx context.Context, sel ast.SelectionSet, v *logs.Lifecycle) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._LogRecordLifecycle(ctx, sel, v)
+}
+
+func (ec *executionContext) marshalOLogRecordsCountResponse2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐCountResult(ctx context.Context, sel ast.SelectionSet, v *logs.CountResult) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._LogRecordsCountResponse(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalOLogRecordsFieldOperator2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐFieldOperator(ctx context.Context, v any) (*logs.FieldOperator, error) {
+	if v == nil {
+		return nil, nil
+	}
+	tmp, err := graphql.UnmarshalString(v)
+	res := logs.FieldOperator(tmp)
+	return &res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOLogRecordsFieldOperator2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐFieldOperator(ctx context.Context, sel ast.SelectionSet, v *logs.FieldOperator) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	_ = sel
+	_ = ctx
+	res := graphql.MarshalString(string(*v))
+	return res
+}
+
+func (ec *executionContext) unmarshalOLogRecordsFieldPredicate2ᚕᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐLogRecordsFieldPredicateᚄ(ctx context.Context, v any) ([]*model.LogRecordsFieldPredicate, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var vSlice []any
+	vSlice = graphql.CoerceList(v)
+	var err error
+	res := make([]*model.LogRecordsFieldPredicate, len(vSlice))
+	for i := range vSlice {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i))
+		res[i], err = ec.unmarshalNLogRecordsFieldPredicate2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐLogRecordsFieldPredicate(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (ec *executionContext) unmarshalOLogRecordsFilter2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐLogRecordsFilter(ctx context.Context, v any) (*model.LogRecordsFilter, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalInputLogRecordsFilter(ctx, v)
+	return &res, graphql.ErrorOnPath(ctx, err)
+}
+
 func (ec *executionContext) unmarshalOLogRecordsQueryMode2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋclusterᚑapiᚋgraphᚋmodelᚐLogRecordsQueryMode(ctx context.Context, v any) (*model.LogRecordsQueryMode, error) {
 	if v == nil {
 		return nil, nil
@@ -7074,6 +9641,72 @@ func (ec *executionContext) marshalOLogSourceWatchEvent2ᚖgithubᚗcomᚋkubeta
 	return ec._LogSourceWatchEvent(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalOLogUsageGroup2ᚕᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐUsageGroupᚄ(ctx context.Context, sel ast.SelectionSet, v []*logs.UsageGroup) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNLogUsageGroup2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐUsageGroup(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) unmarshalOLogUsageGroupBy2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐUsageGroupBy(ctx context.Context, v any) (*logs.UsageGroupBy, error) {
+	if v == nil {
+		return nil, nil
+	}
+	tmp, err := graphql.UnmarshalString(v)
+	res := logs.UsageGroupBy(tmp)
+	return &res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOLogUsageGroupBy2ᚖgithubᚗcomᚋkubetailᚑorgᚋkubetailᚋmodulesᚋsharedᚋlogsᚐUsageGroupBy(ctx context.Context, sel ast.SelectionSet, v *logs.UsageGroupBy) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	_ = sel
+	_ = ctx
+	res := graphql.MarshalString(string(*v))
+	return res
+}
+
 func (ec *executionContext) unmarshalOString2ᚕstringᚄ(ctx context.Context, v any) ([]string, error) {
 	if v == nil {
 		return nil, nil
@@ -7128,6 +9761,24 @@ func (ec *executionContext) marshalOString2ᚖstring(ctx context.Context, sel as
 	return res
 }
 
+func (ec *executionContext) unmarshalOTime2ᚖtimeᚐTime(ctx context.Context, v any) (*time.Time, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := graphql.UnmarshalTime(v)
+	return &res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOTime2ᚖtimeᚐTime(ctx context.Context, sel ast.SelectionSet, v *time.Time) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	_ = sel
+	_ = ctx
+	res := graphql.MarshalTime(*v)
+	return res
+}
+
 func (ec *executionContext) unmarshalOTimestampPBTimestamp2ᚖgoogleᚗgolangᚗorgᚋprotobufᚋtypesᚋknownᚋtimestamppbᚐTimestamp(ctx context.Context, v any) (*timestamppb.Timestamp, error) {
 	if v == nil {
 		return nil, nil
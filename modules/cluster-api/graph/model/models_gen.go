@@ -12,9 +12,27 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 )
 
+type LogRecordsFieldPredicate struct {
+	Field string              `json:"field"`
+	Op    *logs.FieldOperator `json:"op,omitempty"`
+	Value *string             `json:"value,omitempty"`
+}
+
+type LogRecordsFilter struct {
+	Fields  []*LogRecordsFieldPredicate `json:"fields,omitempty"`
+	Exclude []string                    `json:"exclude,omitempty"`
+	Levels  []string                    `json:"levels,omitempty"`
+}
+
 type LogRecordsQueryResponse struct {
-	Records    []*logs.LogRecord `json:"records"`
-	NextCursor *string           `json:"nextCursor,omitempty"`
+	Records    []*logs.LogRecord     `json:"records"`
+	NextCursor *string               `json:"nextCursor,omitempty"`
+	FetchPaths []*LogSourceFetchPath `json:"fetchPaths"`
+}
+
+type LogSourceFetchPath struct {
+	Source *logs.LogSource `json:"source"`
+	Path   LogFetchPath    `json:"path"`
 }
 
 type LogSourceFilter struct {
@@ -50,6 +68,63 @@ type PodLogQueryResponse struct {
 type Subscription struct {
 }
 
+type LogFetchPath string
+
+const (
+	LogFetchPathCache   LogFetchPath = "CACHE"
+	LogFetchPathAgent   LogFetchPath = "AGENT"
+	LogFetchPathKubeAPI LogFetchPath = "KUBE_API"
+)
+
+var AllLogFetchPath = []LogFetchPath{
+	LogFetchPathCache,
+	LogFetchPathAgent,
+	LogFetchPathKubeAPI,
+}
+
+func (e LogFetchPath) IsValid() bool {
+	switch e {
+	case LogFetchPathCache, LogFetchPathAgent, LogFetchPathKubeAPI:
+		return true
+	}
+	return false
+}
+
+func (e LogFetchPath) String() string {
+	return string(e)
+}
+
+func (e *LogFetchPath) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = LogFetchPath(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid LogFetchPath", str)
+	}
+	return nil
+}
+
+func (e LogFetchPath) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *LogFetchPath) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e LogFetchPath) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type LogRecordsQueryMode string
 
 const (
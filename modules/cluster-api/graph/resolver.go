@@ -17,11 +17,23 @@ package graph
 import (
 	"context"
 	"fmt"
+	"os"
+	"slices"
 	"strings"
 
 	grpcdispatcher "github.com/kubetail-org/grpc-dispatcher-go"
+	zlog "github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"k8s.io/client-go/rest"
 
+	"github.com/kubetail-org/kubetail/modules/shared/audit"
+	"github.com/kubetail-org/kubetail/modules/shared/clusteragentpb"
+	"github.com/kubetail-org/kubetail/modules/shared/grpchelpers"
 	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+	"github.com/kubetail-org/kubetail/modules/shared/quota"
+
+	"github.com/kubetail-org/kubetail/modules/cluster-api/internal/followmux"
 )
 
 // This file will not be regenerated automatically.
@@ -33,7 +45,22 @@ import (
 type Resolver struct {
 	cm                k8shelpers.ConnectionManager
 	grpcDispatcher    *grpcdispatcher.Dispatcher
-	allowedNamespaces []string
+	allowedNamespaces func() []string
+	audit             *audit.Logger
+	quotas            *quota.Limiter
+	usageTracker      *logs.UsageTracker
+	followMux         *followmux.Mux
+	recordCache       *logs.RecordCache
+	agentFetcher      *logs.AgentLogFetcher
+	authorizer        k8shelpers.InClusterAuthorizer
+}
+
+// Return current namespace allow-list
+func (r *Resolver) getAllowedNamespaces() []string {
+	if r.allowedNamespaces == nil {
+		return nil
+	}
+	return r.allowedNamespaces()
 }
 
 func (r *Resolver) getBearerTokenRequired(ctx context.Context) (string, error) {
@@ -48,3 +75,119 @@ func (r *Resolver) getBearerTokenRequired(ctx context.Context) (string, error) {
 
 	return token, nil
 }
+
+// Check that the caller can read pod logs in every allowed namespace
+// referenced by `sources`
+func (r *Resolver) authorizePodLogs(ctx context.Context, token string, sources []string) error {
+	restConfig, err := r.cm.GetOrCreateRestConfig("")
+	if err != nil {
+		return err
+	}
+
+	namespaces, err := logs.SourceNamespaces(sources, r.cm.GetDefaultNamespace(""))
+	if err != nil {
+		return err
+	}
+
+	allowedNamespaces := r.getAllowedNamespaces()
+	for _, namespace := range namespaces {
+		// Paths outside the allow-list are dropped by the stream
+		if len(allowedNamespaces) > 0 && !slices.Contains(allowedNamespaces, namespace) {
+			continue
+		}
+
+		if err := r.authorizer.IsAllowedPodLogs(ctx, restConfig, token, namespace); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Return log fetcher that reads from the cluster agent and falls back to the
+// Kubernetes API on nodes without one. Kubernetes API requests use the
+// bearer token in the request context. The agent fetcher is shared across
+// requests.
+func (r *Resolver) newLogFetcher() (*logs.CompositeLogFetcher, error) {
+	clientset, err := r.cm.GetOrCreateClientset("")
+	if err != nil {
+		return nil, err
+	}
+
+	f := logs.NewCompositeLogFetcher(r.grpcDispatcher, clientset)
+	if r.agentFetcher != nil {
+		f = f.WithAgentFetcher(r.agentFetcher)
+	}
+	return f.TrackFetchPaths(), nil
+}
+
+// Return fetcher that answers tail queries from the recent-records cache
+// when possible. The cache is shared by all callers so cached records are
+// only served if `token` can read the source's logs.
+func (r *Resolver) withRecordCache(fetcher logs.LogFetcher, token string) logs.LogFetcher {
+	if r.recordCache == nil {
+		return fetcher
+	}
+	return logs.NewCachingLogFetcher(fetcher, r.recordCache).WithAuthorizer(func(ctx context.Context, source logs.LogSource) error {
+		restConfig, err := r.cm.GetOrCreateRestConfig("")
+		if err != nil {
+			return err
+		}
+		return r.authorizer.IsAllowedPodLogs(ctx, restConfig, token, source.Namespace)
+	})
+}
+
+// Watch log metadata in all namespaces using the app's own credentials so
+// that log file growth is tracked and cached records are invalidated
+// independently of client subscriptions
+func (r *Resolver) watchLogMetadata(ctx context.Context) (*grpcdispatcher.Subscription, error) {
+	restConfig, err := r.cm.GetOrCreateRestConfig("")
+	if err != nil {
+		return nil, err
+	}
+
+	req := &clusteragentpb.LogMetadataWatchRequest{}
+
+	return r.grpcDispatcher.FanoutSubscribe(ctx, func(ctx context.Context, conn *grpc.ClientConn) {
+		// Re-read token on every connection in case it was rotated
+		token, err := bearerToken(restConfig)
+		if err != nil {
+			zlog.Error().Err(err).Msg("Unable to read service account token")
+			return
+		}
+		ctx = context.WithValue(ctx, grpchelpers.K8STokenCtxKey, token)
+
+		recvLogMetadataEvents(ctx, conn, req, func(ev *clusteragentpb.LogMetadataWatchEvent) {
+			observeLogMetadataEvent(r.usageTracker, r.recordCache, ev)
+		})
+	})
+}
+
+// Return context that authenticates agent requests with the app's own
+// service account token. Kubernetes API requests use the app's credentials
+// when the context has no bearer token.
+func (r *Resolver) withAppToken(ctx context.Context) (context.Context, error) {
+	restConfig, err := r.cm.GetOrCreateRestConfig("")
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := bearerToken(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return context.WithValue(ctx, grpchelpers.K8STokenCtxKey, token), nil
+}
+
+// Return bearer token from rest config
+func bearerToken(restConfig *rest.Config) (string, error) {
+	if restConfig.BearerTokenFile != "" {
+		b, err := os.ReadFile(restConfig.BearerTokenFile)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+	return restConfig.BearerToken, nil
+}
@@ -16,11 +16,20 @@ package graph
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
 
 	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	k8shelpersmock "github.com/kubetail-org/kubetail/modules/shared/k8shelpers/mock"
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
 )
 
 func TestGetBearerTokenRequired(t *testing.T) {
@@ -50,3 +59,77 @@ func TestGetBearerTokenRequired(t *testing.T) {
 		assert.Equal(t, "xxx", token)
 	})
 }
+
+func TestBearerToken(t *testing.T) {
+	t.Run("reads token file", func(t *testing.T) {
+		tokenFile := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(tokenFile, []byte("from-file\n"), 0600))
+
+		token, err := bearerToken(&rest.Config{BearerToken: "stale", BearerTokenFile: tokenFile})
+		assert.NoError(t, err)
+		assert.Equal(t, "from-file", token)
+	})
+
+	t.Run("falls back to static token", func(t *testing.T) {
+		token, err := bearerToken(&rest.Config{BearerToken: "xxx"})
+		assert.NoError(t, err)
+		assert.Equal(t, "xxx", token)
+	})
+
+	t.Run("returns error if token file is missing", func(t *testing.T) {
+		_, err := bearerToken(&rest.Config{BearerTokenFile: filepath.Join(t.TempDir(), "missing")})
+		assert.Error(t, err)
+	})
+}
+
+// LogFetcher that follows records from a channel and answers backward
+// queries with a single record
+type stubLogFetcher struct {
+	forwardCh chan logs.LogRecord
+}
+
+func (f *stubLogFetcher) StreamForward(ctx context.Context, source logs.LogSource, opts logs.FetcherOptions) (<-chan logs.LogRecord, error) {
+	return f.forwardCh, nil
+}
+
+func (f *stubLogFetcher) StreamBackward(ctx context.Context, source logs.LogSource, opts logs.FetcherOptions) (<-chan logs.LogRecord, error) {
+	ch := make(chan logs.LogRecord, 1)
+	ch <- logs.LogRecord{Timestamp: time.Now(), Message: "from-node", Source: source}
+	close(ch)
+	return ch, nil
+}
+
+func TestWithRecordCacheAuthorizesCallers(t *testing.T) {
+	source := logs.LogSource{Namespace: "ns1", PodName: "pod1", ContainerName: "c", ContainerID: "containerd://abc"}
+
+	cm := &k8shelpersmock.MockConnectionManager{}
+	cm.On("GetOrCreateRestConfig", "").Return(&rest.Config{}, nil)
+
+	authorizer := &mockInClusterAuthorizer{}
+	authorizer.On("IsAllowedPodLogs", mock.Anything, mock.Anything, "allowed", "ns1").Return(nil)
+	authorizer.On("IsAllowedPodLogs", mock.Anything, mock.Anything, "denied", "ns1").Return(errors.New("permission denied"))
+
+	r := &Resolver{cm: cm, authorizer: authorizer, recordCache: logs.NewRecordCache(10, 10)}
+
+	// Warm cache with a follow stream
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stub := &stubLogFetcher{forwardCh: make(chan logs.LogRecord)}
+	outCh, err := r.withRecordCache(stub, "").StreamForward(ctx, source, logs.FetcherOptions{FollowFrom: logs.FollowFromEnd})
+	require.NoError(t, err)
+
+	stub.forwardCh <- logs.LogRecord{Timestamp: time.Now(), Message: "cached", Source: source}
+	<-outCh
+
+	// Read latest record with each token
+	readLatest := func(token string) string {
+		ch, err := r.withRecordCache(stub, token).StreamBackward(context.Background(), source, logs.FetcherOptions{MaxNum: 1})
+		require.NoError(t, err)
+		record := <-ch
+		return record.Message
+	}
+
+	assert.Equal(t, "cached", readLatest("allowed"))
+	assert.Equal(t, "from-node", readLatest("denied"))
+}
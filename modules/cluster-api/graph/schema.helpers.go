@@ -15,27 +15,227 @@
 package graph
 
 import (
-	"fmt"
-	"strings"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
 	"time"
 
-	"github.com/sosodev/duration"
+	zlog "github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubetail-org/kubetail/modules/shared/audit"
+	"github.com/kubetail-org/kubetail/modules/shared/clusteragentpb"
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+	"github.com/kubetail-org/kubetail/modules/shared/metrics"
+
+	"github.com/kubetail-org/kubetail/modules/cluster-api/graph/model"
 )
 
-// Parse an input either as an ISO timestamp or an ISO duration string
-func parseTimeArg(arg string) (time.Time, error) {
-	var zero time.Time
+// Convert log metadata to log file usage
+func newLogFileUsage(item *clusteragentpb.LogMetadata) logs.LogFileUsage {
+	spec := item.GetSpec()
+	return logs.LogFileUsage{
+		ContainerID:   spec.GetContainerId(),
+		NodeName:      spec.GetNodeName(),
+		Namespace:     spec.GetNamespace(),
+		PodName:       spec.GetPodName(),
+		ContainerName: spec.GetContainerName(),
+		Size:          item.GetFileInfo().GetSize(),
+	}
+}
+
+// Record log file size changes from a log metadata watch event and
+// invalidate cached records of modified and deleted files
+func observeLogMetadataEvent(tracker *logs.UsageTracker, cache *logs.RecordCache, ev *clusteragentpb.LogMetadataWatchEvent) {
+	if ev.GetObject() == nil {
+		return
+	}
+
+	file := newLogFileUsage(ev.GetObject())
+	now := time.Now()
+
+	if tracker != nil {
+		switch ev.GetType() {
+		case "ADDED", "MODIFIED":
+			tracker.Observe(file, now)
+		case "DELETED":
+			tracker.Forget(file.ContainerID)
+		}
+	}
+
+	if cache != nil {
+		switch ev.GetType() {
+		case "MODIFIED":
+			cache.ObserveModified(file.ContainerID, now)
+		case "DELETED":
+			cache.Invalidate(file.ContainerID)
+		}
+	}
+}
+
+// Delays between log metadata watch retries (doubled after each attempt)
+var (
+	logMetadataWatchMinRetryDelay = time.Second
+	logMetadataWatchMaxRetryDelay = 30 * time.Second
+)
+
+// Watch log metadata on a cluster agent and pass events to `handler` until
+// the stream ends. Unexpected errors (e.g. PermissionDenied) are logged and
+// the watch is retried with exponential backoff until `ctx` is done.
+func recvLogMetadataEvents(ctx context.Context, conn *grpc.ClientConn, req *clusteragentpb.LogMetadataWatchRequest, handler func(ev *clusteragentpb.LogMetadataWatchEvent)) {
+	delay := logMetadataWatchMinRetryDelay
+	for {
+		start := time.Now()
+
+		err := recvLogMetadataEventsOnce(ctx, conn, req, handler)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+
+		// Reset backoff after a healthy stream
+		if time.Since(start) > logMetadataWatchMaxRetryDelay {
+			delay = logMetadataWatchMinRetryDelay
+		}
+
+		zlog.Error().Err(err).Dur("retryIn", delay).Msg("Log metadata watch failed")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, logMetadataWatchMaxRetryDelay)
+	}
+}
+
+// Run a single log metadata watch. Returns nil if the stream ended normally
+// (e.g. EOF, cancellation or agent shutdown) and an error otherwise.
+func recvLogMetadataEventsOnce(ctx context.Context, conn *grpc.ClientConn, req *clusteragentpb.LogMetadataWatchRequest, handler func(ev *clusteragentpb.LogMetadataWatchEvent)) error {
+	// init client
+	c := clusteragentpb.NewLogMetadataServiceClient(conn)
+
+	// execute
+	var p peer.Peer
+	stream, err := c.Watch(ctx, req, grpc.Peer(&p))
+
+	for err == nil {
+		var ev *clusteragentpb.LogMetadataWatchEvent
+		if ev, err = stream.Recv(); err != nil {
+			break
+		}
+		handler(ev)
+	}
+
+	// ignore normal errors
+	if err == io.EOF || errors.Is(err, context.Canceled) {
+		return nil
+	}
 
-	arg = strings.TrimSpace(arg)
-	if arg == "" {
-		return zero, nil
-	} else if timeAgo, err := duration.Parse(arg); err == nil {
-		// Parsed as ISO duration
-		return time.Now().Add(-1 * timeAgo.ToTimeDuration()), nil
-	} else if ts, err := time.Parse(time.RFC3339Nano, arg); err == nil {
-		// Parsed as ISO timestamp
-		return ts, nil
+	// check for grpc status error
+	switch status.Code(err) {
+	case codes.Unavailable:
+		// server down (probably restarting)
+		return nil
+	case codes.Canceled:
+		// connection closed client-side
+		return nil
 	}
 
-	return zero, fmt.Errorf("unable to parse arg %s", arg)
+	recordAgentError(&p, "Watch")
+	return err
+}
+
+// Convert records filter input to logs filter
+func newLogsFilter(f *model.LogRecordsFilter) (*logs.Filter, error) {
+	if f == nil {
+		return nil, nil
+	}
+
+	fields := make([]logs.FieldPredicate, len(f.Fields))
+	for i, pred := range f.Fields {
+		fields[i] = logs.FieldPredicate{
+			Field: pred.Field,
+			Op:    ptr.Deref(pred.Op, logs.FieldOperatorEquals),
+			Value: ptr.Deref(pred.Value, ""),
+		}
+	}
+
+	return logs.NewFilter(fields, f.Exclude, f.Levels)
+}
+
+// Convert source filter to audit representation
+func auditSourceFilter(f *model.LogSourceFilter) audit.SourceFilter {
+	if f == nil {
+		return audit.SourceFilter{}
+	}
+	return audit.SourceFilter{
+		Region:    f.Region,
+		Zone:      f.Zone,
+		OS:        f.Os,
+		Arch:      f.Arch,
+		Node:      f.Node,
+		Container: f.Container,
+	}
+}
+
+// Convert fetch paths to sorted list
+func newLogSourceFetchPaths(paths map[logs.LogSource]logs.FetchPath) []*model.LogSourceFetchPath {
+	out := make([]*model.LogSourceFetchPath, 0, len(paths))
+	for source, path := range paths {
+		out = append(out, &model.LogSourceFetchPath{
+			Source: &source,
+			Path:   model.LogFetchPath(path),
+		})
+	}
+
+	slices.SortFunc(out, func(a, b *model.LogSourceFetchPath) int {
+		return cmp.Or(
+			cmp.Compare(a.Source.Namespace, b.Source.Namespace),
+			cmp.Compare(a.Source.PodName, b.Source.PodName),
+			cmp.Compare(a.Source.ContainerName, b.Source.ContainerName),
+			cmp.Compare(a.Source.ContainerID, b.Source.ContainerID),
+		)
+	})
+
+	return out
+}
+
+// Return key identifying follow subscriptions that can share an upstream.
+// Upstreams run with the app's credentials and subscribers are authorized
+// before joining so the key doesn't include the caller's identity.
+func followKey(allowedNamespaces []string, kubeContext string, sources []string, grep string, filter *model.LogRecordsFilter, sourceFilter model.LogSourceFilter, servingOnly bool, withLifecycle bool) string {
+	sorted := slices.Clone(sources)
+	slices.Sort(sorted)
+
+	sortedNamespaces := slices.Clone(allowedNamespaces)
+	slices.Sort(sortedNamespaces)
+
+	b, _ := json.Marshal(struct {
+		AllowedNamespaces []string                `json:"allowedNamespaces"`
+		KubeContext       string                  `json:"kubeContext"`
+		Sources           []string                `json:"sources"`
+		Grep              string                  `json:"grep"`
+		Filter            *model.LogRecordsFilter `json:"filter"`
+		SourceFilter      model.LogSourceFilter   `json:"sourceFilter"`
+		ServingOnly       bool                    `json:"servingOnly"`
+		WithLifecycle     bool                    `json:"withLifecycle"`
+	}{slices.Compact(sortedNamespaces), kubeContext, slices.Compact(sorted), grep, filter, sourceFilter, servingOnly, withLifecycle})
+
+	return string(b)
+}
+
+// Increment agent error counter using the peer address of a fan-out request
+func recordAgentError(p *peer.Peer, method string) {
+	agent := "unknown"
+	if p != nil && p.Addr != nil {
+		agent = p.Addr.String()
+	}
+	metrics.AgentErrors.WithLabelValues(agent, method).Inc()
 }
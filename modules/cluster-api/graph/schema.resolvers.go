@@ -6,33 +6,50 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"io"
 	"sync"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/kubetail-org/kubetail/modules/cluster-api/graph/model"
+	"github.com/kubetail-org/kubetail/modules/cluster-api/internal/followmux"
+	"github.com/kubetail-org/kubetail/modules/shared/audit"
 	"github.com/kubetail-org/kubetail/modules/shared/clusteragentpb"
 	gqlerrors "github.com/kubetail-org/kubetail/modules/shared/graphql/errors"
 	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
 	"github.com/kubetail-org/kubetail/modules/shared/logs"
+	"github.com/kubetail-org/kubetail/modules/shared/util"
 	zlog "github.com/rs/zerolog/log"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 )
 
+// Start is the resolver for the start field.
+func (r *logRecordsCountBucketResolver) Start(ctx context.Context, obj *logs.CountBucket) (*time.Time, error) {
+	if obj.Start.IsZero() {
+		return nil, nil
+	}
+	return &obj.Start, nil
+}
+
 // LogMetadataList is the resolver for the logMetadataList field.
 func (r *queryResolver) LogMetadataList(ctx context.Context, namespace *string) (*clusteragentpb.LogMetadataList, error) {
+	// Init audit entry
+	ae := r.audit.Start(ctx, audit.Event{Operation: "logMetadataList"})
+
 	// Deref namespace
-	nsList, err := k8shelpers.DerefNamespaceToList(r.allowedNamespaces, namespace, metav1.NamespaceDefault)
+	nsList, err := k8shelpers.DerefNamespaceToList(r.getAllowedNamespaces(), namespace, metav1.NamespaceDefault)
 	if err != nil {
+		ae.Finish(err)
 		return nil, err
 	}
+	ae.SetNamespaces(nsList)
 
 	outList := &clusteragentpb.LogMetadataList{}
 	req := &clusteragentpb.LogMetadataListRequest{Namespaces: nsList}
@@ -46,7 +63,8 @@ func (r *queryResolver) LogMetadataList(ctx context.Context, namespace *string)
 		c := clusteragentpb.NewLogMetadataServiceClient(conn)
 
 		// execute
-		resp, err := c.List(ctx, req)
+		var p peer.Peer
+		resp, err := c.List(ctx, req, grpc.Peer(&p))
 
 		// aquire lock
 		mu.Lock()
@@ -54,6 +72,7 @@ func (r *queryResolver) LogMetadataList(ctx context.Context, namespace *string)
 
 		// update vars
 		if err != nil {
+			recordAgentError(&p, "List")
 			errs = append(errs, NewGrpcError(conn, err))
 		} else {
 			// update items
@@ -63,38 +82,145 @@ func (r *queryResolver) LogMetadataList(ctx context.Context, namespace *string)
 
 	// throw error if response is missing
 	if len(errs) != 0 {
+		ae.Finish(errs)
 		return nil, errs
 	}
 
+	ae.AddRecords(len(outList.Items))
+	ae.Finish(nil)
+
 	return outList, nil
 }
 
+// LogUsageSummary is the resolver for the logUsageSummary field.
+func (r *queryResolver) LogUsageSummary(ctx context.Context, namespace *string, groupBy *logs.UsageGroupBy) ([]*logs.UsageGroup, error) {
+	// Init audit entry
+	ae := r.audit.Start(ctx, audit.Event{Operation: "logUsageSummary"})
+
+	// Deref namespace
+	nsList, err := k8shelpers.DerefNamespaceToList(r.getAllowedNamespaces(), namespace, metav1.NamespaceDefault)
+	if err != nil {
+		ae.Finish(err)
+		return nil, err
+	}
+	ae.SetNamespaces(nsList)
+
+	groupByVal := ptr.Deref(groupBy, logs.UsageGroupByWorkload)
+
+	// Get token
+	token, err := r.getBearerTokenRequired(ctx)
+	if err != nil {
+		ae.Finish(gqlerrors.ErrUnauthenticated)
+		return nil, gqlerrors.ErrUnauthenticated
+	}
+
+	files := []logs.LogFileUsage{}
+	req := &clusteragentpb.LogMetadataListRequest{Namespaces: nsList}
+
+	// Execute
+	var mu sync.Mutex
+	errs := gqlerror.List{}
+
+	r.grpcDispatcher.Fanout(ctx, func(ctx context.Context, conn *grpc.ClientConn) {
+		// init client
+		c := clusteragentpb.NewLogMetadataServiceClient(conn)
+
+		// execute
+		var p peer.Peer
+		resp, err := c.List(ctx, req, grpc.Peer(&p))
+
+		// aquire lock
+		mu.Lock()
+		defer mu.Unlock()
+
+		// update vars
+		if err != nil {
+			recordAgentError(&p, "List")
+			errs = append(errs, NewGrpcError(conn, err))
+		} else {
+			for _, item := range resp.GetItems() {
+				files = append(files, newLogFileUsage(item))
+			}
+		}
+	})
+
+	// throw error if response is missing
+	if len(errs) != 0 {
+		ae.Finish(errs)
+		return nil, errs
+	}
+
+	// Record sizes for growth rates
+	now := time.Now()
+	for _, file := range files {
+		r.usageTracker.Observe(file, now)
+	}
+
+	// Init workload resolver
+	var workloadResolver *logs.WorkloadResolver
+	if groupByVal == logs.UsageGroupByWorkload || groupByVal == logs.UsageGroupByContainer {
+		workloadResolver, err = logs.NewWorkloadResolver(ctx, r.cm, "", token, nsList)
+		if err != nil {
+			ae.Finish(err)
+			return nil, err
+		}
+	}
+
+	// Summarize
+	groups := logs.SummarizeUsage(files, groupByVal, r.usageTracker, workloadResolver)
+
+	out := make([]*logs.UsageGroup, len(groups))
+	for i := range groups {
+		out[i] = &groups[i]
+	}
+
+	ae.AddRecords(len(out))
+	ae.Finish(nil)
+
+	return out, nil
+}
+
 // LogRecordsFetch is the resolver for the logRecordsFetch field.
-func (r *queryResolver) LogRecordsFetch(ctx context.Context, kubeContext *string, sources []string, mode *model.LogRecordsQueryMode, since *string, until *string, after *string, before *string, grep *string, sourceFilter *model.LogSourceFilter, limit *int) (*model.LogRecordsQueryResponse, error) {
+func (r *queryResolver) LogRecordsFetch(ctx context.Context, kubeContext *string, sources []string, mode *model.LogRecordsQueryMode, since *string, until *string, after *string, before *string, grep *string, filter *model.LogRecordsFilter, sourceFilter *model.LogSourceFilter, servingOnly *bool, limit *int) (*model.LogRecordsQueryResponse, error) {
+	// Init audit entry
+	ae := r.audit.Start(ctx, audit.Event{
+		Operation:    "logRecordsFetch",
+		KubeContext:  ptr.Deref(kubeContext, ""),
+		Sources:      sources,
+		SourceFilter: auditSourceFilter(sourceFilter),
+		Grep:         ptr.Deref(grep, ""),
+		Mode:         string(ptr.Deref(mode, model.LogRecordsQueryModeTail)),
+	})
+
 	// Get bearer token
 	token, err := r.getBearerTokenRequired(ctx)
 	if err != nil {
+		ae.Finish(gqlerrors.ErrUnauthenticated)
 		return nil, gqlerrors.ErrUnauthenticated
 	}
 
 	// Parse time args
-	sinceTime, err := parseTimeArg(ptr.Deref(since, ""))
+	sinceTime, err := util.ParseTimeArg(ptr.Deref(since, ""))
 	if err != nil {
+		ae.Finish(err)
 		return nil, err
 	}
 
-	untilTime, err := parseTimeArg(ptr.Deref(until, ""))
+	untilTime, err := util.ParseTimeArg(ptr.Deref(until, ""))
 	if err != nil {
+		ae.Finish(err)
 		return nil, err
 	}
 
-	afterTime, err := parseTimeArg(ptr.Deref(after, ""))
+	afterTime, err := util.ParseTimeArg(ptr.Deref(after, ""))
 	if err != nil {
+		ae.Finish(err)
 		return nil, err
 	}
 
-	beforeTime, err := parseTimeArg(ptr.Deref(before, ""))
+	beforeTime, err := util.ParseTimeArg(ptr.Deref(before, ""))
 	if err != nil {
+		ae.Finish(err)
 		return nil, err
 	}
 
@@ -107,22 +233,41 @@ func (r *queryResolver) LogRecordsFetch(ctx context.Context, kubeContext *string
 		untilTime = beforeTime.Add(-1 * time.Nanosecond)
 	}
 
+	ae.SetTimeRange(sinceTime, untilTime)
+
+	// Parse filter
+	filterVal, err := newLogsFilter(filter)
+	if err != nil {
+		ae.Finish(err)
+		return nil, err
+	}
+
+	// Check quotas
+	release, err := r.quotas.AcquireFetch(ctx)
+	if err != nil {
+		ae.Finish(err)
+		return nil, err
+	}
+	defer release()
+
 	// Init stream
 	sourceFilterVal := ptr.Deref(sourceFilter, model.LogSourceFilter{})
 
 	streamOpts := []logs.Option{
 		logs.WithBearerToken(token),
-		logs.WithAllowedNamespaces(r.allowedNamespaces),
-		logs.WithLogFetcher(logs.NewAgentLogFetcher(r.grpcDispatcher)),
+		logs.WithAllowedNamespaces(r.getAllowedNamespaces()),
 		logs.WithSince(sinceTime),
 		logs.WithUntil(untilTime),
 		logs.WithGrep(ptr.Deref(grep, "")),
+		logs.WithFilter(filterVal),
 		logs.WithRegions(sourceFilterVal.Region),
 		logs.WithZones(sourceFilterVal.Zone),
 		logs.WithOSes(sourceFilterVal.Os),
 		logs.WithArches(sourceFilterVal.Arch),
 		logs.WithNodes(sourceFilterVal.Node),
 		logs.WithContainers(sourceFilterVal.Container),
+		logs.WithServingOnly(ptr.Deref(servingOnly, false)),
+		logs.WithMaxSources(r.quotas.MaxSourcesPerStream()),
 	}
 
 	limitVal := int64(ptr.Deref(limit, 100))
@@ -132,17 +277,29 @@ func (r *queryResolver) LogRecordsFetch(ctx context.Context, kubeContext *string
 	case model.LogRecordsQueryModeTail:
 		streamOpts = append(streamOpts, logs.WithTail(limitVal))
 	default:
-		return nil, fmt.Errorf("not implemented %s", mode)
+		err := fmt.Errorf("not implemented %s", mode)
+		ae.Finish(err)
+		return nil, err
 	}
 
+	fetcher, err := r.newLogFetcher()
+	if err != nil {
+		ae.Finish(err)
+		return nil, err
+	}
+	streamOpts = append(streamOpts, logs.WithLogFetcher(r.withRecordCache(fetcher, token)))
+
 	stream, err := logs.NewStream(ctx, r.cm, sources, streamOpts...)
 	if err != nil {
+		ae.Finish(err)
 		return nil, err
 	}
 	defer stream.Close()
 
 	// Start stream
 	if err := stream.Start(ctx); err != nil {
+		err = r.quotas.Error(err)
+		ae.Finish(err)
 		return nil, err
 	}
 
@@ -154,74 +311,148 @@ func (r *queryResolver) LogRecordsFetch(ctx context.Context, kubeContext *string
 	}
 
 	if ctx.Err() != nil {
+		ae.Finish(ctx.Err())
 		return nil, ctx.Err()
 	}
 
 	if stream.Err() != nil {
+		ae.Finish(stream.Err())
 		return nil, stream.Err()
 	}
 
+	out.FetchPaths = newLogSourceFetchPaths(fetcher.FetchPaths())
+
+	ae.AddRecords(len(out.Records))
+	ae.Finish(nil)
+
 	return out, nil
 }
 
-// LogMetadataWatch is the resolver for the logMetadataWatch field.
-func (r *subscriptionResolver) LogMetadataWatch(ctx context.Context, namespace *string) (<-chan *clusteragentpb.LogMetadataWatchEvent, error) {
-	// Deref namespaces
-	nsList, err := k8shelpers.DerefNamespaceToList(r.allowedNamespaces, namespace, metav1.NamespaceDefault)
+// LogRecordsCount is the resolver for the logRecordsCount field.
+func (r *queryResolver) LogRecordsCount(ctx context.Context, kubeContext *string, sources []string, since *string, until *string, grep *string, filter *model.LogRecordsFilter, sourceFilter *model.LogSourceFilter, servingOnly *bool, bucketSize *string) (*logs.CountResult, error) {
+	// Init audit entry
+	ae := r.audit.Start(ctx, audit.Event{
+		Operation:    "logRecordsCount",
+		KubeContext:  ptr.Deref(kubeContext, ""),
+		Sources:      sources,
+		SourceFilter: auditSourceFilter(sourceFilter),
+		Grep:         ptr.Deref(grep, ""),
+	})
+
+	// Get bearer token
+	token, err := r.getBearerTokenRequired(ctx)
 	if err != nil {
+		ae.Finish(gqlerrors.ErrUnauthenticated)
+		return nil, gqlerrors.ErrUnauthenticated
+	}
+
+	// Parse time args
+	sinceTime, err := util.ParseTimeArg(ptr.Deref(since, ""))
+	if err != nil {
+		ae.Finish(err)
 		return nil, err
 	}
 
-	outCh := make(chan *clusteragentpb.LogMetadataWatchEvent)
+	untilTime, err := util.ParseTimeArg(ptr.Deref(until, ""))
+	if err != nil {
+		ae.Finish(err)
+		return nil, err
+	}
 
-	sub, err := r.grpcDispatcher.FanoutSubscribe(ctx, func(ctx context.Context, conn *grpc.ClientConn) {
-		// init client
-		c := clusteragentpb.NewLogMetadataServiceClient(conn)
+	ae.SetTimeRange(sinceTime, untilTime)
 
-		// init request
-		req := &clusteragentpb.LogMetadataWatchRequest{Namespaces: nsList}
+	// Parse bucket size
+	bucketSizeVal, err := util.ParseDurationArg(ptr.Deref(bucketSize, ""))
+	if err != nil {
+		ae.Finish(err)
+		return nil, err
+	}
 
-		// execute
-		stream, err := c.Watch(ctx, req)
-		if err != nil {
-			return
-		}
+	// Parse filter
+	filterVal, err := newLogsFilter(filter)
+	if err != nil {
+		ae.Finish(err)
+		return nil, err
+	}
 
-		for {
-			ev, err := stream.Recv()
+	// Check quotas
+	release, err := r.quotas.AcquireFetch(ctx)
+	if err != nil {
+		ae.Finish(err)
+		return nil, err
+	}
+	defer release()
 
-			// handle errors
-			if err != nil {
-				// ignore normal errors
-				if err == io.EOF {
-					break
-				} else if err == context.Canceled {
-					break
-				}
+	// Init counter
+	sourceFilterVal := ptr.Deref(sourceFilter, model.LogSourceFilter{})
 
-				// check for grpc status error
-				if s, ok := status.FromError(err); ok {
-					switch s.Code() {
-					case codes.Unavailable:
-						// server down (probably restarting)
-					case codes.Canceled:
-						// connection closed client-side
-					default:
-						zlog.Error().Caller().Err(err).Msgf("Unexpected gRPC error: %v\n", s.Message())
-					}
-					break
-				}
+	clientset, err := r.cm.GetOrCreateClientset("")
+	if err != nil {
+		ae.Finish(err)
+		return nil, err
+	}
 
-				zlog.Error().Caller().Err(err).Msg("Unexpected error")
+	counter, err := logs.NewCounter(r.cm, sources,
+		logs.WithBearerToken(token),
+		logs.WithAllowedNamespaces(r.getAllowedNamespaces()),
+		logs.WithLogCounter(logs.NewCompositeLogCounter(r.grpcDispatcher, clientset)),
+		logs.WithSince(sinceTime),
+		logs.WithUntil(untilTime),
+		logs.WithGrep(ptr.Deref(grep, "")),
+		logs.WithFilter(filterVal),
+		logs.WithBucketSize(bucketSizeVal),
+		logs.WithRegions(sourceFilterVal.Region),
+		logs.WithZones(sourceFilterVal.Zone),
+		logs.WithOSes(sourceFilterVal.Os),
+		logs.WithArches(sourceFilterVal.Arch),
+		logs.WithNodes(sourceFilterVal.Node),
+		logs.WithContainers(sourceFilterVal.Container),
+		logs.WithServingOnly(ptr.Deref(servingOnly, false)),
+		logs.WithMaxSources(r.quotas.MaxSourcesPerStream()),
+	)
+	if err != nil {
+		ae.Finish(err)
+		return nil, err
+	}
 
-				break
-			}
+	// Count records on the agents
+	result, err := counter.Count(ctx)
+	if err != nil {
+		err = r.quotas.Error(err)
+		ae.Finish(err)
+		return nil, err
+	}
+
+	ae.Finish(nil)
+
+	return result, nil
+}
+
+// LogMetadataWatch is the resolver for the logMetadataWatch field.
+func (r *subscriptionResolver) LogMetadataWatch(ctx context.Context, namespace *string) (<-chan *clusteragentpb.LogMetadataWatchEvent, error) {
+	// Init audit entry
+	ae := r.audit.Start(ctx, audit.Event{Operation: "logMetadataWatch"})
+
+	// Deref namespaces
+	nsList, err := k8shelpers.DerefNamespaceToList(r.getAllowedNamespaces(), namespace, metav1.NamespaceDefault)
+	if err != nil {
+		ae.Finish(err)
+		return nil, err
+	}
+	ae.SetNamespaces(nsList)
+
+	outCh := make(chan *clusteragentpb.LogMetadataWatchEvent)
+
+	req := &clusteragentpb.LogMetadataWatchRequest{Namespaces: nsList}
 
-			// forward event
+	sub, err := r.grpcDispatcher.FanoutSubscribe(ctx, func(ctx context.Context, conn *grpc.ClientConn) {
+		recvLogMetadataEvents(ctx, conn, req, func(ev *clusteragentpb.LogMetadataWatchEvent) {
 			outCh <- ev
-		}
+			ae.AddRecords(1)
+		})
 	})
 	if err != nil {
+		ae.Finish(err)
 		return nil, err
 	}
 
@@ -229,27 +460,40 @@ func (r *subscriptionResolver) LogMetadataWatch(ctx context.Context, namespace *
 	go func() {
 		<-ctx.Done()
 		sub.Unsubscribe()
+		ae.Finish(nil)
 	}()
 
 	return outCh, nil
 }
 
 // LogRecordsFollow is the resolver for the logRecordsFollow field.
-func (r *subscriptionResolver) LogRecordsFollow(ctx context.Context, kubeContext *string, sources []string, since *string, after *string, grep *string, sourceFilter *model.LogSourceFilter) (<-chan *logs.LogRecord, error) {
+func (r *subscriptionResolver) LogRecordsFollow(ctx context.Context, kubeContext *string, sources []string, since *string, after *string, grep *string, filter *model.LogRecordsFilter, sourceFilter *model.LogSourceFilter, servingOnly *bool, withLifecycle *bool) (<-chan *logs.LogRecord, error) {
+	// Init audit entry
+	ae := r.audit.Start(ctx, audit.Event{
+		Operation:    "logRecordsFollow",
+		KubeContext:  ptr.Deref(kubeContext, ""),
+		Sources:      sources,
+		SourceFilter: auditSourceFilter(sourceFilter),
+		Grep:         ptr.Deref(grep, ""),
+	})
+
 	// Get bearer token
 	token, err := r.getBearerTokenRequired(ctx)
 	if err != nil {
+		ae.Finish(gqlerrors.ErrUnauthenticated)
 		return nil, gqlerrors.ErrUnauthenticated
 	}
 
 	// Parse time args
-	sinceTime, err := parseTimeArg(ptr.Deref(since, ""))
+	sinceTime, err := util.ParseTimeArg(ptr.Deref(since, ""))
 	if err != nil {
+		ae.Finish(err)
 		return nil, err
 	}
 
-	afterTime, err := parseTimeArg(ptr.Deref(after, ""))
+	afterTime, err := util.ParseTimeArg(ptr.Deref(after, ""))
 	if err != nil {
+		ae.Finish(err)
 		return nil, err
 	}
 
@@ -258,32 +502,97 @@ func (r *subscriptionResolver) LogRecordsFollow(ctx context.Context, kubeContext
 		sinceTime = afterTime.Add(1 * time.Nanosecond)
 	}
 
-	// Init stream
+	ae.SetTimeRange(sinceTime, time.Time{})
+
+	// Parse filter
+	filterVal, err := newLogsFilter(filter)
+	if err != nil {
+		ae.Finish(err)
+		return nil, err
+	}
+
+	// Check quotas
+	release, err := r.quotas.AcquireSubscription(ctx)
+	if err != nil {
+		ae.Finish(err)
+		return nil, err
+	}
+
+	// Init stream options
 	sourceFilterVal := ptr.Deref(sourceFilter, model.LogSourceFilter{})
 
-	streamOpts := []logs.Option{
-		logs.WithBearerToken(token),
-		logs.WithAllowedNamespaces(r.allowedNamespaces),
-		logs.WithLogFetcher(logs.NewAgentLogFetcher(r.grpcDispatcher)),
-		logs.WithAll(),
-		logs.WithFollow(true),
-		logs.WithSince(sinceTime),
-		logs.WithGrep(ptr.Deref(grep, "")),
-		logs.WithRegions(sourceFilterVal.Region),
-		logs.WithZones(sourceFilterVal.Zone),
-		logs.WithOSes(sourceFilterVal.Os),
-		logs.WithArches(sourceFilterVal.Arch),
-		logs.WithNodes(sourceFilterVal.Node),
-		logs.WithContainers(sourceFilterVal.Container),
+	newStream := func(ctx context.Context, token string, extraOpts ...logs.Option) (*logs.Stream, error) {
+		fetcher, err := r.newLogFetcher()
+		if err != nil {
+			return nil, err
+		}
+
+		streamOpts := []logs.Option{
+			logs.WithBearerToken(token),
+			logs.WithAllowedNamespaces(r.getAllowedNamespaces()),
+			logs.WithAll(),
+			logs.WithGrep(ptr.Deref(grep, "")),
+			logs.WithFilter(filterVal),
+			logs.WithRegions(sourceFilterVal.Region),
+			logs.WithZones(sourceFilterVal.Zone),
+			logs.WithOSes(sourceFilterVal.Os),
+			logs.WithArches(sourceFilterVal.Arch),
+			logs.WithNodes(sourceFilterVal.Node),
+			logs.WithContainers(sourceFilterVal.Container),
+			logs.WithServingOnly(ptr.Deref(servingOnly, false)),
+			logs.WithMaxSources(r.quotas.MaxSourcesPerStream()),
+			logs.WithLifecycle(ptr.Deref(withLifecycle, false)),
+			logs.WithLogFetcher(r.withRecordCache(fetcher, token)),
+		}
+
+		stream, err := logs.NewStream(ctx, r.cm, sources, append(streamOpts, extraOpts...)...)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := stream.Start(ctx); err != nil {
+			stream.Close()
+			return nil, err
+		}
+
+		return stream, nil
 	}
 
-	stream, err := logs.NewStream(ctx, r.cm, sources, streamOpts...)
+	// Check that the subscriber can read the sources' logs before joining an
+	// upstream that may have been started by someone else
+	if err := r.authorizePodLogs(ctx, token, sources); err != nil {
+		release()
+		ae.Finish(err)
+		return nil, err
+	}
+
+	// Join shared upstream for identical subscriptions. The upstream runs with
+	// the app's own credentials so it can be shared across callers.
+	key := followKey(r.getAllowedNamespaces(), ptr.Deref(kubeContext, ""), sources, ptr.Deref(grep, ""), filter, sourceFilterVal, ptr.Deref(servingOnly, false), ptr.Deref(withLifecycle, false))
+
+	sub, err := r.followMux.Subscribe(ctx, key, func(ctx context.Context, since time.Time) (followmux.Upstream, error) {
+		ctx, err := r.withAppToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return newStream(ctx, "", logs.WithFollow(true), logs.WithSince(since))
+	})
 	if err != nil {
+		release()
+		err = r.quotas.Error(err)
+		ae.Finish(err)
 		return nil, err
 	}
 
-	// Start stream
-	if err := stream.Start(ctx); err != nil {
+	// Fetch records up to the subscription cutoff with the subscriber's own
+	// token. This also checks that the subscriber is allowed to read the
+	// upstream's sources.
+	catchUp, err := newStream(ctx, token, logs.WithSince(sinceTime), logs.WithUntil(sub.Cutoff()))
+	if err != nil {
+		sub.Close()
+		release()
+		err = r.quotas.Error(err)
+		ae.Finish(err)
 		return nil, err
 	}
 
@@ -292,24 +601,62 @@ func (r *subscriptionResolver) LogRecordsFollow(ctx context.Context, kubeContext
 
 	// Write out in goroutine
 	go func() {
+		defer release()
 		defer close(outCh)
-		defer stream.Close()
+		defer sub.Close()
+		defer catchUp.Close()
 
-		for record := range stream.Records() {
+		// Send record to client, returns false if client closed subscription
+		send := func(record logs.LogRecord) bool {
 			select {
 			case <-ctx.Done():
-				return
+				return false
 			case outCh <- &record:
-				// Success
+				ae.AddRecords(1)
+				return true
+			}
+		}
+
+		// Catch-up records first
+		for record := range catchUp.Records() {
+			if !send(record) {
+				ae.Finish(nil)
+				return
 			}
 		}
 
+		err := catchUp.Err()
+
+		// Then live records from upstream, which were held back during catch-up
+		if err == nil {
+			for record := range sub.Records() {
+				if record.Timestamp.Before(sinceTime) {
+					continue
+				}
+				if !send(record) {
+					ae.Finish(nil)
+					return
+				}
+			}
+			err = sub.Err()
+		}
+
+		// Client closed subscription
+		if ctx.Err() != nil {
+			ae.Finish(nil)
+			return
+		}
+
+		ae.Finish(err)
+
 		// Handle errors
-		if err := stream.Err(); err != nil {
-			if status.Code(err) == codes.Unavailable {
+		if err != nil {
+			if errors.Is(err, followmux.ErrSlowSubscriber) {
+				transport.AddSubscriptionError(ctx, gqlerrors.ErrSlowSubscriber)
+			} else if status.Code(err) == codes.Unavailable {
 				transport.AddSubscriptionError(ctx, gqlerrors.ErrServiceUnavailable)
 			} else {
-				zlog.Error().Err(stream.Err()).Caller().Send()
+				zlog.Error().Err(err).Caller().Send()
 				transport.AddSubscriptionError(ctx, gqlerrors.ErrInternalServerError)
 			}
 		}
@@ -323,11 +670,17 @@ func (r *subscriptionResolver) LogSourcesWatch(ctx context.Context, kubeContext
 	panic(fmt.Errorf("not implemented: LogSourcesWatch - logSourcesWatch"))
 }
 
+// LogRecordsCountBucket returns LogRecordsCountBucketResolver implementation.
+func (r *Resolver) LogRecordsCountBucket() LogRecordsCountBucketResolver {
+	return &logRecordsCountBucketResolver{r}
+}
+
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
 // Subscription returns SubscriptionResolver implementation.
 func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
 
+type logRecordsCountBucketResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
 type subscriptionResolver struct{ *Resolver }
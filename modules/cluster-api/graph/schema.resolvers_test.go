@@ -15,22 +15,409 @@
 package graph
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
 
+	"github.com/kubetail-org/kubetail/modules/shared/audit"
+	"github.com/kubetail-org/kubetail/modules/shared/clusteragentpb"
 	gqlerrors "github.com/kubetail-org/kubetail/modules/shared/graphql/errors"
+	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	k8shelpersmock "github.com/kubetail-org/kubetail/modules/shared/k8shelpers/mock"
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+	"github.com/kubetail-org/kubetail/modules/shared/quota"
+
+	"github.com/kubetail-org/kubetail/modules/cluster-api/graph/model"
+	"github.com/kubetail-org/kubetail/modules/cluster-api/internal/followmux"
 )
 
+func TestLogUsageSummaryRequiresToken(t *testing.T) {
+	r := &queryResolver{&Resolver{}}
+	for _, groupBy := range []logs.UsageGroupBy{logs.UsageGroupByNamespace, logs.UsageGroupByWorkload, logs.UsageGroupByNode, logs.UsageGroupByContainer} {
+		_, err := r.LogUsageSummary(context.Background(), nil, &groupBy)
+		assert.ErrorIs(t, gqlerrors.ErrUnauthenticated, err)
+	}
+}
+
+func TestLogUsageSummaryForbiddenNamespace(t *testing.T) {
+	r := &queryResolver{&Resolver{allowedNamespaces: func() []string { return []string{"ns1"} }}}
+	_, err := r.LogUsageSummary(context.Background(), ptr.To("ns2"), ptr.To(logs.UsageGroupByNode))
+	assert.ErrorIs(t, err, gqlerrors.ErrForbidden)
+}
+
+func TestObserveLogMetadataEvent(t *testing.T) {
+	tracker := logs.NewUsageTracker(time.Minute)
+
+	newEvent := func(evType string, size int64) *clusteragentpb.LogMetadataWatchEvent {
+		return &clusteragentpb.LogMetadataWatchEvent{
+			Type: evType,
+			Object: &clusteragentpb.LogMetadata{
+				Spec:     &clusteragentpb.LogMetadataSpec{ContainerId: "c1"},
+				FileInfo: &clusteragentpb.LogMetadataFileInfo{Size: size},
+			},
+		}
+	}
+
+	observeLogMetadataEvent(tracker, nil, newEvent("ADDED", 100))
+	time.Sleep(10 * time.Millisecond)
+	observeLogMetadataEvent(tracker, nil, newEvent("MODIFIED", 200))
+
+	rate, ok := tracker.GrowthRate("c1")
+	require.True(t, ok)
+	assert.Greater(t, rate, 0.0)
+
+	observeLogMetadataEvent(tracker, nil, newEvent("DELETED", 200))
+	_, ok = tracker.GrowthRate("c1")
+	assert.False(t, ok)
+
+	// Nil tracker, nil cache and empty events are ignored
+	observeLogMetadataEvent(nil, nil, newEvent("ADDED", 100))
+	observeLogMetadataEvent(tracker, nil, &clusteragentpb.LogMetadataWatchEvent{Type: "ADDED"})
+}
+
 func TestLogRecordsFetchRequiresToken(t *testing.T) {
-	r := &queryResolver{}
-	_, err := r.LogRecordsFetch(context.Background(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	r := &queryResolver{&Resolver{}}
+	_, err := r.LogRecordsFetch(context.Background(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	assert.ErrorIs(t, gqlerrors.ErrUnauthenticated, err)
+}
+
+func TestLogRecordsCountRequiresToken(t *testing.T) {
+	r := &queryResolver{&Resolver{}}
+	_, err := r.LogRecordsCount(context.Background(), nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	assert.ErrorIs(t, gqlerrors.ErrUnauthenticated, err)
 }
 
+func TestLogRecordsCountInvalidArgs(t *testing.T) {
+	ctx := context.WithValue(context.Background(), k8shelpers.K8STokenCtxKey, "xxx")
+	sources := []string{"default:pods/web"}
+
+	_, err := (&queryResolver{&Resolver{}}).LogRecordsCount(ctx, nil, sources, nil, nil, nil, nil, nil, nil, ptr.To("5m"))
+	assert.ErrorContains(t, err, "unable to parse arg 5m")
+
+	filter := &model.LogRecordsFilter{Exclude: []string{"("}}
+	_, err = (&queryResolver{&Resolver{}}).LogRecordsCount(ctx, nil, sources, nil, nil, nil, filter, nil, nil, nil)
+	assert.ErrorContains(t, err, "invalid exclude pattern")
+}
+
+func TestLogRecordsCountBucketStart(t *testing.T) {
+	r := &logRecordsCountBucketResolver{}
+
+	start, err := r.Start(context.Background(), &logs.CountBucket{Count: 1})
+	require.NoError(t, err)
+	assert.Nil(t, start)
+
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	start, err = r.Start(context.Background(), &logs.CountBucket{Start: ts, Count: 1})
+	require.NoError(t, err)
+	assert.Equal(t, &ts, start)
+}
+
+func TestNewLogSourceFetchPaths(t *testing.T) {
+	s1 := logs.LogSource{Namespace: "ns1", PodName: "pod1", ContainerName: "c"}
+	s2 := logs.LogSource{Namespace: "ns1", PodName: "pod2", ContainerName: "c"}
+
+	out := newLogSourceFetchPaths(map[logs.LogSource]logs.FetchPath{
+		s2: logs.FetchPathKubeAPI,
+		s1: logs.FetchPathAgent,
+	})
+
+	require.Len(t, out, 2)
+	assert.Equal(t, s1, *out[0].Source)
+	assert.Equal(t, model.LogFetchPathAgent, out[0].Path)
+	assert.Equal(t, s2, *out[1].Source)
+	assert.Equal(t, model.LogFetchPathKubeAPI, out[1].Path)
+}
+
+func TestFollowKey(t *testing.T) {
+	filter := &model.LogRecordsFilter{Levels: []string{"error"}}
+	sourceFilter := model.LogSourceFilter{Node: []string{"node-1"}}
+
+	base := followKey([]string{"ns1", "ns2"}, "", []string{"default:pods/a", "default:pods/b"}, "err", filter, sourceFilter, false, false)
+
+	// Source order and duplicates don't matter
+	assert.Equal(t, base, followKey([]string{"ns1", "ns2"}, "", []string{"default:pods/b", "default:pods/a", "default:pods/a"}, "err", filter, sourceFilter, false, false))
+
+	// Everything else does
+	assert.Equal(t, base, followKey([]string{"ns2", "ns1"}, "", []string{"default:pods/a", "default:pods/b"}, "err", filter, sourceFilter, false, false))
+	assert.NotEqual(t, base, followKey([]string{"ns1"}, "", []string{"default:pods/a", "default:pods/b"}, "err", filter, sourceFilter, false, false))
+	assert.NotEqual(t, base, followKey([]string{"ns1", "ns2"}, "ctx", []string{"default:pods/a", "default:pods/b"}, "err", filter, sourceFilter, false, false))
+	assert.NotEqual(t, base, followKey([]string{"ns1", "ns2"}, "", []string{"default:pods/a"}, "err", filter, sourceFilter, false, false))
+	assert.NotEqual(t, base, followKey([]string{"ns1", "ns2"}, "", []string{"default:pods/a", "default:pods/b"}, "warn", filter, sourceFilter, false, false))
+	assert.NotEqual(t, base, followKey([]string{"ns1", "ns2"}, "", []string{"default:pods/a", "default:pods/b"}, "err", nil, sourceFilter, false, false))
+	assert.NotEqual(t, base, followKey([]string{"ns1", "ns2"}, "", []string{"default:pods/a", "default:pods/b"}, "err", filter, model.LogSourceFilter{}, false, false))
+	assert.NotEqual(t, base, followKey([]string{"ns1", "ns2"}, "", []string{"default:pods/a", "default:pods/b"}, "err", filter, sourceFilter, true, false))
+	assert.NotEqual(t, base, followKey([]string{"ns1", "ns2"}, "", []string{"default:pods/a", "default:pods/b"}, "err", filter, sourceFilter, false, true))
+}
+
+type mockInClusterAuthorizer struct {
+	mock.Mock
+}
+
+func (m *mockInClusterAuthorizer) IsAllowedInformer(ctx context.Context, restConfig *rest.Config, token string, namespace string, gvr schema.GroupVersionResource) error {
+	return m.Called(ctx, restConfig, token, namespace, gvr).Error(0)
+}
+
+func (m *mockInClusterAuthorizer) IsAllowedPodLogs(ctx context.Context, restConfig *rest.Config, token string, namespace string) error {
+	return m.Called(ctx, restConfig, token, namespace).Error(0)
+}
+
+func (m *mockInClusterAuthorizer) Permissions(ctx context.Context, restConfig *rest.Config, token string, namespace string) (*k8shelpers.Permissions, error) {
+	ret := m.Called(ctx, restConfig, token, namespace)
+	p, _ := ret.Get(0).(*k8shelpers.Permissions)
+	return p, ret.Error(1)
+}
+
+func (m *mockInClusterAuthorizer) Invalidate() {
+	m.Called()
+}
+
+func TestLogRecordsFollowSharedUpstream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Serve informers from a fake clientset
+	factory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	start := func() { factory.Start(ctx.Done()) }
+
+	cm := &k8shelpersmock.MockConnectionManager{}
+	cm.On("GetOrCreateRestConfig", "").Return(&rest.Config{BearerToken: "app-token"}, nil)
+	cm.On("GetOrCreateClientset", "").Return(fake.NewSimpleClientset(), nil)
+	cm.On("GetDefaultNamespace", mock.Anything).Return("default")
+	for _, gvr := range []schema.GroupVersionResource{
+		{Version: "v1", Resource: "pods"},
+		{Version: "v1", Resource: "nodes"},
+	} {
+		informer, err := factory.ForResource(gvr)
+		require.NoError(t, err)
+		cm.On("NewInformer", mock.Anything, "", mock.Anything, mock.Anything, gvr).Return(informer, start, nil)
+	}
+
+	authorizer := &mockInClusterAuthorizer{}
+	authorizer.On("IsAllowedPodLogs", mock.Anything, mock.Anything, "token1", "default").Return(nil)
+	authorizer.On("IsAllowedPodLogs", mock.Anything, mock.Anything, "token2", "default").Return(nil)
+	authorizer.On("IsAllowedPodLogs", mock.Anything, mock.Anything, "token3", "default").Return(errors.New("permission denied"))
+
+	r := &subscriptionResolver{&Resolver{
+		cm:         cm,
+		followMux:  followmux.New(0),
+		authorizer: authorizer,
+	}}
+
+	sources := []string{"default:pods/web"}
+
+	// Two callers with different tokens share one upstream
+	for _, token := range []string{"token1", "token2"} {
+		tokenCtx := context.WithValue(ctx, k8shelpers.K8STokenCtxKey, token)
+		_, err := r.LogRecordsFollow(tokenCtx, nil, sources, nil, nil, nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, r.followMux.Len())
+
+	// Upstream runs with the app's credentials, catch-up streams with the caller's
+	numUpstreamInformers := 0
+	for _, call := range cm.Calls {
+		if call.Method == "NewInformer" && call.Arguments.String(2) == "" {
+			numUpstreamInformers++
+		}
+	}
+	assert.Equal(t, 2, numUpstreamInformers)
+	cm.AssertCalled(t, "NewInformer", mock.Anything, "", "token1", "default", mock.Anything)
+	cm.AssertCalled(t, "NewInformer", mock.Anything, "", "token2", "default", mock.Anything)
+
+	// Caller without access to the sources' logs is rejected before joining
+	tokenCtx := context.WithValue(ctx, k8shelpers.K8STokenCtxKey, "token3")
+	_, err := r.LogRecordsFollow(tokenCtx, nil, sources, nil, nil, nil, nil, nil, nil, nil)
+	assert.ErrorContains(t, err, "permission denied")
+	cm.AssertNotCalled(t, "NewInformer", mock.Anything, "", "token3", mock.Anything, mock.Anything)
+}
+
 func TestLogRecordsFollowRequiresToken(t *testing.T) {
-	r := &subscriptionResolver{}
-	_, err := r.LogRecordsFollow(context.Background(), nil, nil, nil, nil, nil, nil)
+	r := &subscriptionResolver{&Resolver{}}
+	_, err := r.LogRecordsFollow(context.Background(), nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	assert.ErrorIs(t, gqlerrors.ErrUnauthenticated, err)
 }
+
+func TestLogRecordsInvalidFilter(t *testing.T) {
+	ctx := context.WithValue(context.Background(), k8shelpers.K8STokenCtxKey, "xxx")
+	filter := &model.LogRecordsFilter{Exclude: []string{"("}}
+
+	_, err := (&queryResolver{&Resolver{}}).LogRecordsFetch(ctx, nil, []string{"default:pods/web"}, nil, nil, nil, nil, nil, nil, filter, nil, nil, nil)
+	assert.ErrorContains(t, err, "invalid exclude pattern")
+
+	_, err = (&subscriptionResolver{&Resolver{}}).LogRecordsFollow(ctx, nil, []string{"default:pods/web"}, nil, nil, nil, filter, nil, nil, nil)
+	assert.ErrorContains(t, err, "invalid exclude pattern")
+}
+
+func TestLogRecordsFetchAudit(t *testing.T) {
+	var buf bytes.Buffer
+	identify := func(ctx context.Context) (string, []string) {
+		return "system:serviceaccount:kubetail:dashboard", nil
+	}
+
+	r := &queryResolver{&Resolver{
+		audit: audit.NewLogger("cluster-api", audit.NewWriterSink(&buf), identify),
+	}}
+
+	// Invalid mode fails before the stream is created
+	ctx := context.WithValue(context.Background(), k8shelpers.K8STokenCtxKey, "xxx")
+	mode := model.LogRecordsQueryMode("XXX")
+	_, err := r.LogRecordsFetch(ctx, nil, []string{"default:pods/web"}, &mode, nil, nil, nil, nil, ptr.To("error"), nil, nil, nil, nil)
+	require.Error(t, err)
+
+	var ev audit.Event
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &ev))
+	assert.Equal(t, "cluster-api", ev.App)
+	assert.Equal(t, "logRecordsFetch", ev.Operation)
+	assert.Equal(t, "system:serviceaccount:kubetail:dashboard", ev.User)
+	assert.Equal(t, []string{"default:pods/web"}, ev.Sources)
+	assert.Equal(t, "error", ev.Grep)
+	assert.Equal(t, audit.OutcomeError, ev.Outcome)
+}
+
+func TestLogRecordsRejectedAudit(t *testing.T) {
+	var buf bytes.Buffer
+	r := &Resolver{audit: audit.NewLogger("cluster-api", audit.NewWriterSink(&buf), nil)}
+
+	tokenCtx := context.WithValue(context.Background(), k8shelpers.K8STokenCtxKey, "xxx")
+
+	tests := []struct {
+		name      string
+		operation string
+		run       func() error
+	}{
+		{"fetch unauthenticated", "logRecordsFetch", func() error {
+			_, err := (&queryResolver{r}).LogRecordsFetch(context.Background(), nil, []string{"default:pods/web"}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+			return err
+		}},
+		{"fetch invalid since", "logRecordsFetch", func() error {
+			_, err := (&queryResolver{r}).LogRecordsFetch(tokenCtx, nil, []string{"default:pods/web"}, nil, ptr.To("xxx"), nil, nil, nil, nil, nil, nil, nil, nil)
+			return err
+		}},
+		{"count invalid bucket size", "logRecordsCount", func() error {
+			_, err := (&queryResolver{r}).LogRecordsCount(tokenCtx, nil, []string{"default:pods/web"}, nil, nil, nil, nil, nil, nil, ptr.To("xxx"))
+			return err
+		}},
+		{"follow invalid since", "logRecordsFollow", func() error {
+			_, err := (&subscriptionResolver{r}).LogRecordsFollow(tokenCtx, nil, []string{"default:pods/web"}, ptr.To("xxx"), nil, nil, nil, nil, nil, nil)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			require.Error(t, tt.run())
+
+			var ev audit.Event
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &ev))
+			assert.Equal(t, tt.operation, ev.Operation)
+			assert.Equal(t, []string{"default:pods/web"}, ev.Sources)
+			assert.Equal(t, audit.OutcomeError, ev.Outcome)
+		})
+	}
+}
+
+func TestLogRecordsQuotas(t *testing.T) {
+	identify := func(ctx context.Context) (string, []string) {
+		return "alice", nil
+	}
+
+	quotas := quota.NewLimiter(quota.Limits{MaxConcurrentFetches: 1, MaxConcurrentSubscriptions: 1}, identify)
+	r := &Resolver{quotas: quotas}
+
+	ctx := context.WithValue(context.Background(), k8shelpers.K8STokenCtxKey, "xxx")
+
+	// Use up all slots
+	releaseFetch, err := quotas.AcquireFetch(ctx)
+	require.NoError(t, err)
+	defer releaseFetch()
+
+	releaseSub, err := quotas.AcquireSubscription(ctx)
+	require.NoError(t, err)
+	defer releaseSub()
+
+	// Both operations are rejected before a stream is created
+	_, err = (&queryResolver{r}).LogRecordsFetch(ctx, nil, []string{"default:pods/web"}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	var gqlErr *gqlerror.Error
+	require.ErrorAs(t, err, &gqlErr)
+	assert.Equal(t, "KUBETAIL_QUOTA_EXCEEDED", gqlErr.Extensions["code"])
+	assert.Equal(t, quota.QuotaMaxConcurrentFetches, gqlErr.Extensions["quota"])
+
+	_, err = (&queryResolver{r}).LogRecordsCount(ctx, nil, []string{"default:pods/web"}, nil, nil, nil, nil, nil, nil, nil)
+	require.ErrorAs(t, err, &gqlErr)
+	assert.Equal(t, quota.QuotaMaxConcurrentFetches, gqlErr.Extensions["quota"])
+
+	_, err = (&subscriptionResolver{r}).LogRecordsFollow(ctx, nil, []string{"default:pods/web"}, nil, nil, nil, nil, nil, nil, nil)
+	require.ErrorAs(t, err, &gqlErr)
+	assert.Equal(t, quota.QuotaMaxConcurrentSubscriptions, gqlErr.Extensions["quota"])
+}
+
+// Agent that rejects the first `failures` watches
+type flakyLogMetadataServer struct {
+	clusteragentpb.UnimplementedLogMetadataServiceServer
+	failures int
+	calls    atomic.Int32
+}
+
+func (s *flakyLogMetadataServer) Watch(req *clusteragentpb.LogMetadataWatchRequest, stream grpc.ServerStreamingServer[clusteragentpb.LogMetadataWatchEvent]) error {
+	if int(s.calls.Add(1)) <= s.failures {
+		return status.Error(codes.PermissionDenied, "permission denied")
+	}
+	return stream.Send(&clusteragentpb.LogMetadataWatchEvent{Type: "ADDED"})
+}
+
+func TestRecvLogMetadataEventsRetries(t *testing.T) {
+	minDelay, maxDelay := logMetadataWatchMinRetryDelay, logMetadataWatchMaxRetryDelay
+	logMetadataWatchMinRetryDelay, logMetadataWatchMaxRetryDelay = time.Millisecond, 10*time.Millisecond
+	defer func() {
+		logMetadataWatchMinRetryDelay, logMetadataWatchMaxRetryDelay = minDelay, maxDelay
+	}()
+
+	srv := &flakyLogMetadataServer{failures: 2}
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	clusteragentpb.RegisterLogMetadataServiceServer(s, srv)
+	go s.Serve(lis)
+	defer s.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var events []string
+	recvLogMetadataEvents(ctx, conn, &clusteragentpb.LogMetadataWatchRequest{}, func(ev *clusteragentpb.LogMetadataWatchEvent) {
+		events = append(events, ev.Type)
+	})
+
+	// Stream ends normally after the rejected attempts are retried
+	require.NoError(t, ctx.Err())
+	assert.Equal(t, []string{"ADDED"}, events)
+	assert.Equal(t, int32(3), srv.calls.Load())
+}
@@ -25,13 +25,23 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler/lru"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/gorilla/websocket"
+	zlog "github.com/rs/zerolog/log"
 	"github.com/vektah/gqlparser/v2/ast"
 
 	grpcdispatcher "github.com/kubetail-org/grpc-dispatcher-go"
 
+	"github.com/kubetail-org/kubetail/modules/shared/audit"
 	"github.com/kubetail-org/kubetail/modules/shared/config"
 	"github.com/kubetail-org/kubetail/modules/shared/graphql/directives"
+	"github.com/kubetail-org/kubetail/modules/shared/graphql/limits"
 	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+	"github.com/kubetail-org/kubetail/modules/shared/metrics"
+	"github.com/kubetail-org/kubetail/modules/shared/otel"
+	"github.com/kubetail-org/kubetail/modules/shared/quota"
+
+	"github.com/kubetail-org/kubetail/modules/cluster-api/graph/model"
+	"github.com/kubetail-org/kubetail/modules/cluster-api/internal/followmux"
 )
 
 // Represents Server
@@ -45,15 +55,46 @@ type Server struct {
 // It's defined at the package level to avoid re-allocation on every WebSocket upgrade request.
 var allowedSecFetchSite = []string{"same-origin"}
 
-// Create new Server instance
-func NewServer(config *config.Config, cm k8shelpers.ConnectionManager, grpcDispatcher *grpcdispatcher.Dispatcher, allowedNamespaces []string) *Server {
+// Window used to compute log file growth rates
+const usageWindow = 10 * time.Minute
+
+// Create new Server instance. Allowed namespaces and CSRF settings are read
+// from `live` on every request so they can be reloaded at runtime.
+func NewServer(live *config.Live, cm k8shelpers.ConnectionManager, grpcDispatcher *grpcdispatcher.Dispatcher, auditLogger *audit.Logger, quotas *quota.Limiter) *Server {
+	config := live.Load()
+
 	// Init resolver
-	r := &Resolver{cm, grpcDispatcher, allowedNamespaces}
+	r := &Resolver{cm, grpcDispatcher, live.AllowedNamespaces, auditLogger, quotas, logs.NewUsageTracker(usageWindow), followmux.New(followmux.DefaultBufferSize), nil, logs.NewAgentLogFetcher(grpcDispatcher), k8shelpers.NewInClusterAuthorizer()}
+
+	// Init recent-records cache
+	if config.ClusterAPI.RecordCache.Enabled {
+		r.recordCache = logs.NewRecordCache(config.ClusterAPI.RecordCache.MaxRecords, config.ClusterAPI.RecordCache.MaxContainers)
+	}
+
+	// Init shutdown channel
+	shutdownCh := make(chan struct{})
+
+	// Feed usage tracker and record cache from an app-owned log metadata watch
+	if cm != nil && grpcDispatcher != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		sub, err := r.watchLogMetadata(ctx)
+		if err != nil {
+			zlog.Error().Err(err).Msg("Unable to watch log metadata")
+		}
+		go func() {
+			<-shutdownCh
+			cancel()
+			if sub != nil {
+				sub.Unsubscribe()
+			}
+		}()
+	}
 
 	// Init config
 	cfg := Config{Resolvers: r}
 	cfg.Directives.Validate = directives.ValidateDirective
 	cfg.Directives.NullIfValidationFailed = directives.NullIfValidationFailedDirective
+	setComplexity(&cfg, live.AllowedNamespaces)
 
 	// Init schema
 	schema := NewExecutableSchema(cfg)
@@ -68,13 +109,12 @@ func NewServer(config *config.Config, cm k8shelpers.ConnectionManager, grpcDispa
 	h.SetQueryCache(lru.New[*ast.QueryDocument](1000))
 
 	// Configure WebSocket (without CORS)
-	shutdownCh := make(chan struct{})
 
 	h.AddTransport(&transport.Websocket{
 		Upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				// Allow all if CSRF protection is disabled
-				if !config.ClusterAPI.CSRF.Enabled {
+				if !live.Load().ClusterAPI.CSRF.Enabled {
 					return true
 				}
 
@@ -110,6 +150,13 @@ func NewServer(config *config.Config, cm k8shelpers.ConnectionManager, grpcDispa
 	})
 
 	h.Use(extension.Introspection{})
+	h.Use(metrics.SubscriptionTracker{})
+	h.Use(otel.GraphQLTracer{})
+	h.Use(limits.New(limits.Limits{
+		MaxComplexity: config.ClusterAPI.GraphQL.MaxComplexity,
+		MaxDepth:      config.ClusterAPI.GraphQL.MaxDepth,
+		MaxAliases:    config.ClusterAPI.GraphQL.MaxAliases,
+	}))
 	h.Use(extension.AutomaticPersistedQuery{
 		Cache: lru.New[string](100),
 	})
@@ -117,6 +164,24 @@ func NewServer(config *config.Config, cm k8shelpers.ConnectionManager, grpcDispa
 	return &Server{r, h, shutdownCh}
 }
 
+// Set complexity functions for fields that are expensive to resolve
+func setComplexity(cfg *Config, allowedNamespaces func() []string) {
+	logMetadata := func(childComplexity int, namespace *string) int {
+		return 1 + childComplexity*limits.NamespaceMultiplier(allowedNamespaces(), namespace)
+	}
+
+	cfg.Complexity.Query.LogMetadataList = logMetadata
+	cfg.Complexity.Subscription.LogMetadataWatch = logMetadata
+
+	cfg.Complexity.Query.LogUsageSummary = func(childComplexity int, namespace *string, groupBy *logs.UsageGroupBy) int {
+		return logMetadata(childComplexity, namespace)
+	}
+
+	cfg.Complexity.Query.LogRecordsFetch = func(childComplexity int, kubeContext *string, sources []string, mode *model.LogRecordsQueryMode, since *string, until *string, after *string, before *string, grep *string, filter *model.LogRecordsFilter, sourceFilter *model.LogSourceFilter, servingOnly *bool, limit *int) int {
+		return limits.LogRecordsFetchComplexity(childComplexity, limit)
+	}
+}
+
 // Shutdown
 func (s *Server) Shutdown() {
 	close(s.shutdownCh)
@@ -76,7 +76,7 @@ func TestServer(t *testing.T) {
 			cfg := &config.Config{}
 			cfg.ClusterAPI.CSRF.Enabled = tt.setCsrfEnabled
 
-			graphqlServer := NewServer(cfg, nil, nil, []string{})
+			graphqlServer := NewServer(config.NewLive(cfg), nil, nil, nil, nil)
 
 			client := testutils.NewWebTestClient(t, graphqlServer)
 			defer client.Teardown()
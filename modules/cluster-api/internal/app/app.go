@@ -16,21 +16,31 @@ package app
 
 import (
 	"context"
+	"crypto/tls"
 	"io/fs"
 	"net/http"
+	"path"
 	"strings"
 
 	"github.com/gin-contrib/gzip"
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-contrib/secure"
 	"github.com/gin-gonic/gin"
+	zlog "github.com/rs/zerolog/log"
+	"k8s.io/client-go/kubernetes"
 
 	grpcdispatcher "github.com/kubetail-org/grpc-dispatcher-go"
 
+	"github.com/kubetail-org/kubetail/modules/shared/audit"
 	"github.com/kubetail-org/kubetail/modules/shared/config"
+	"github.com/kubetail-org/kubetail/modules/shared/download"
 	"github.com/kubetail-org/kubetail/modules/shared/ginhelpers"
 	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+	"github.com/kubetail-org/kubetail/modules/shared/metrics"
 	"github.com/kubetail-org/kubetail/modules/shared/middleware"
+	"github.com/kubetail-org/kubetail/modules/shared/otel"
+	"github.com/kubetail-org/kubetail/modules/shared/quota"
 
 	clusterapi "github.com/kubetail-org/kubetail/modules/cluster-api"
 	"github.com/kubetail-org/kubetail/modules/cluster-api/graph"
@@ -41,11 +51,33 @@ type App struct {
 	cm             k8shelpers.ConnectionManager
 	grpcDispatcher *grpcdispatcher.Dispatcher
 	graphqlServer  *graph.Server
+	live           *config.Live
+	auditLogger    *audit.Logger
+	quotas         *quota.Limiter
+	tlsConfig      *tls.Config
+	shutdownTracer otel.ShutdownFunc
+	logForwarder   *logForwarder
 
 	// for testing
 	dynamicRoutes *gin.RouterGroup
 }
 
+// TLSConfig returns the server tls config or nil if tls is disabled
+func (a *App) TLSConfig() *tls.Config {
+	return a.tlsConfig
+}
+
+// ApplyConfig applies the hot-swappable fields of `cfg` (allowed namespaces,
+// csrf, quotas and logging) to the running app
+func (a *App) ApplyConfig(cfg *config.Config) {
+	a.live.Store(cfg)
+	a.quotas.SetLimits(newQuotaLimits(cfg))
+
+	if err := config.SetLogLevel(cfg.ClusterAPI.Logging.Level); err != nil {
+		zlog.Error().Err(err).Msg("Failed to set log level")
+	}
+}
+
 // Shutdown
 func (a *App) Shutdown(ctx context.Context) error {
 	// Stop grpc dispatcher
@@ -57,6 +89,23 @@ func (a *App) Shutdown(ctx context.Context) error {
 	// Shutdown GraphQL server
 	a.graphqlServer.Shutdown()
 
+	// Stop log forwarder and flush queued records
+	if a.logForwarder != nil {
+		if err := a.logForwarder.Shutdown(ctx); err != nil {
+			zlog.Error().Err(err).Msg("Failed to flush forwarded records")
+		}
+	}
+
+	// Flush audit log
+	a.auditLogger.Close()
+
+	// Flush pending spans
+	if a.shutdownTracer != nil {
+		if err := a.shutdownTracer(ctx); err != nil {
+			zlog.Error().Err(err).Msg("Failed to flush spans")
+		}
+	}
+
 	// Shutdown connection manager
 	return a.cm.Shutdown(ctx)
 }
@@ -64,7 +113,21 @@ func (a *App) Shutdown(ctx context.Context) error {
 // Create new gin app
 func NewApp(cfg *config.Config) (*App, error) {
 	// Init app
-	app := &App{Engine: gin.New()}
+	app := &App{Engine: gin.New(), live: config.NewLive(cfg)}
+
+	// Init tls config
+	tlsConfig, err := newServerTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	app.tlsConfig = tlsConfig
+
+	// Init tracer
+	shutdownTracer, err := otel.InitTracer(newOTelConfig(cfg))
+	if err != nil {
+		return nil, err
+	}
+	app.shutdownTracer = shutdownTracer
 
 	// If not in test-mode
 	if gin.Mode() != gin.TestMode {
@@ -79,23 +142,57 @@ func NewApp(cfg *config.Config) (*App, error) {
 
 		// init grpc dispatcher
 		app.grpcDispatcher = mustNewGrpcDispatcher(cfg)
+
+		// Init log forwarder
+		if cfg.ClusterAPI.Forwarder.Enabled {
+			lf, err := newLogForwarder(cfg, cm, app.live.AllowedNamespaces)
+			if err != nil {
+				return nil, err
+			}
+			app.logForwarder = lf
+		}
+	}
+
+	// Init identifier (shared by audit log and quotas)
+	identify := audit.NewTokenReviewIdentifier(func() (kubernetes.Interface, error) {
+		return app.cm.GetOrCreateClientset("")
+	})
+
+	// Init audit logger
+	if cfg.ClusterAPI.Audit.Enabled {
+		sink, err := audit.NewSink(cfg.ClusterAPI.Audit.Sink, cfg.ClusterAPI.Audit.File, cfg.ClusterAPI.Audit.WebhookURL)
+		if err != nil {
+			return nil, err
+		}
+		app.auditLogger = audit.NewLogger("cluster-api", sink, identify)
 	}
 
+	// Init quota limiter
+	app.quotas = quota.NewReloadableLimiter(newQuotaLimits(cfg), identify)
+
 	// Add request-id middleware
 	app.Use(requestid.New())
 
+	// Add tracing middleware
+	app.Use(middleware.TracingMiddleware())
+
 	// Add logging middleware
-	if cfg.ClusterAPI.Logging.AccessLog.Enabled {
-		app.Use(middleware.LoggingMiddleware(cfg.ClusterAPI.Logging.AccessLog.HideHealthChecks))
-	}
+	app.Use(middleware.DynamicLoggingMiddleware(func() middleware.AccessLogOptions {
+		accessLog := app.live.Load().ClusterAPI.Logging.AccessLog
+		return middleware.AccessLogOptions{Enabled: accessLog.Enabled, HideHealthChecks: accessLog.HideHealthChecks}
+	}))
 
 	// Gzip middleware
+	downloadPath := path.Join(cfg.ClusterAPI.BasePath, "/api/logs/download")
 	app.Use(gzip.Gzip(gzip.DefaultCompression,
 		gzip.WithCustomShouldCompressFn(func(c *gin.Context) bool {
 			ae := c.GetHeader("Accept-Encoding")
 			if !strings.Contains(ae, "gzip") {
 				return false
 			}
+			if c.Request.URL.Path == downloadPath {
+				return false // compressed by handler
+			}
 			return !ginhelpers.IsWebSocketRequest(c)
 		}),
 	))
@@ -119,8 +216,20 @@ func NewApp(cfg *config.Config) (*App, error) {
 		dynamicRoutes.Use(authenticationMiddleware)
 
 		// GraphQL endpoint
-		app.graphqlServer = graph.NewServer(cfg, app.cm, app.grpcDispatcher, cfg.AllowedNamespaces)
+		app.graphqlServer = graph.NewServer(app.live, app.cm, app.grpcDispatcher, app.auditLogger, app.quotas)
 		dynamicRoutes.Any("/graphql", gin.WrapH(app.graphqlServer))
+
+		// Log download endpoint
+		var downloadFetcher logs.LogFetcher = logs.NewAgentLogFetcher(app.grpcDispatcher)
+		if app.cm != nil {
+			clientset, err := app.cm.GetOrCreateClientset("")
+			if err != nil {
+				return nil, err
+			}
+			downloadFetcher = logs.NewCompositeLogFetcher(app.grpcDispatcher, clientset)
+		}
+		downloadHandler := download.NewHandler(app.cm, app.live.AllowedNamespaces, app.auditLogger, app.quotas, logs.WithLogFetcher(downloadFetcher))
+		dynamicRoutes.GET("/api/logs/download", tokenRequiredMiddleware, gin.WrapH(downloadHandler))
 	}
 	app.dynamicRoutes = dynamicRoutes // for unit tests
 
@@ -136,6 +245,11 @@ func NewApp(cfg *config.Config) (*App, error) {
 		})
 	})
 
+	// Metrics endpoint
+	if cfg.ClusterAPI.Metrics.Enabled {
+		root.GET("/metrics", gin.WrapH(metrics.Handler(k8shelpers.NewInformerCacheCollector(app.cm))))
+	}
+
 	// Init staticFS
 	sub, err := fs.Sub(clusterapi.StaticEmbedFS, "static")
 	if err != nil {
@@ -16,14 +16,21 @@ package app
 
 import (
 	"compress/gzip"
+	"crypto/tls"
+	"crypto/x509"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubetail-org/kubetail/modules/shared/testutils"
 )
 
 func TestRequestID(t *testing.T) {
@@ -52,6 +59,19 @@ func TestRequestID(t *testing.T) {
 	assert.NotEqual(t, id1, id2)
 }
 
+func TestApplyConfig(t *testing.T) {
+	app := NewTestApp(nil)
+
+	cfg := NewTestConfig()
+	cfg.AllowedNamespaces = []string{"ns1"}
+	cfg.ClusterAPI.Logging.Level = zerolog.GlobalLevel().String()
+	cfg.ClusterAPI.Quotas.MaxSourcesPerStream = 5
+	app.ApplyConfig(cfg)
+
+	assert.Equal(t, []string{"ns1"}, app.live.AllowedNamespaces())
+	assert.Equal(t, 5, app.quotas.MaxSourcesPerStream())
+}
+
 func TestGzip(t *testing.T) {
 	app := NewTestApp(nil)
 
@@ -92,3 +112,104 @@ func TestHealthz(t *testing.T) {
 	assert.Equal(t, http.StatusOK, result.StatusCode)
 	assert.Equal(t, "{\"status\":\"ok\"}", w.Body.String())
 }
+
+func TestMutualTLS(t *testing.T) {
+	certs := testutils.NewTestCerts(t)
+
+	cfg := NewTestConfig()
+	cfg.ClusterAPI.TLS.Enabled = true
+	cfg.ClusterAPI.TLS.CertFile = certs.ServerCertFile
+	cfg.ClusterAPI.TLS.KeyFile = certs.ServerKeyFile
+	cfg.ClusterAPI.TLS.CAFile = certs.CAFile
+	cfg.ClusterAPI.TLS.ClientAuth = tls.RequireAndVerifyClientCert
+
+	app := NewTestApp(cfg)
+	require.NotNil(t, app.TLSConfig())
+
+	srv := httptest.NewUnstartedServer(app)
+	srv.TLS = app.TLSConfig()
+	srv.StartTLS()
+	defer srv.Close()
+
+	caPem, err := os.ReadFile(certs.CAFile)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(caPem))
+
+	clientCert, err := tls.LoadX509KeyPair(certs.ClientCertFile, certs.ClientKeyFile)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		setClientCert bool
+		wantErr       bool
+	}{
+		{"with client cert", true, false},
+		{"without client cert", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsCfg := &tls.Config{RootCAs: roots}
+			if tt.setClientCert {
+				tlsCfg.Certificates = []tls.Certificate{clientCert}
+			}
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsCfg}}
+
+			resp, err := client.Get(srv.URL + "/healthz")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	t.Run("enabled", func(t *testing.T) {
+		cfg := NewTestConfig()
+		cfg.ClusterAPI.Metrics.Enabled = true
+		app := NewTestApp(cfg)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/metrics", nil)
+		app.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Contains(t, w.Body.String(), "kubetail_logs_streams_open")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		app := NewTestApp(nil)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/metrics", nil)
+		app.ServeHTTP(w, r)
+
+		assert.NotContains(t, w.Body.String(), "kubetail_logs_streams_open")
+	})
+}
+
+func TestLogsDownload(t *testing.T) {
+	app := NewTestApp(nil)
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/api/logs/download?source=ns1/pods/pod1", nil)
+		app.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	})
+
+	t.Run("bad request", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/api/logs/download?format=xml", nil)
+		r.Header.Set("Authorization", "Bearer xxx")
+		app.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+}
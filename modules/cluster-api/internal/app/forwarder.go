@@ -0,0 +1,141 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"time"
+
+	zlog "github.com/rs/zerolog/log"
+
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+	"github.com/kubetail-org/kubetail/modules/shared/forward"
+	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+)
+
+// Time to wait before re-opening a stream that ended
+const logForwarderRestartDelay = 5 * time.Second
+
+// Represents background job that follows the configured sources and
+// forwards their records to an external endpoint. Records are read from the
+// Kubernetes API using the cluster-api service account, which needs `get`
+// access to pods/log.
+type logForwarder struct {
+	cm                k8shelpers.ConnectionManager
+	fwd               *forward.Forwarder
+	sources           []string
+	grep              string
+	allowedNamespaces func() []string
+	cancel            context.CancelFunc
+	doneCh            chan struct{}
+}
+
+// Create new logForwarder instance and start following sources.
+// `allowedNamespaces` is read each time the stream is (re-)opened.
+func newLogForwarder(cfg *config.Config, cm k8shelpers.ConnectionManager, allowedNamespaces func() []string) (*logForwarder, error) {
+	opts := cfg.ClusterAPI.Forwarder
+
+	sink, err := forward.NewSink(opts.Endpoint, opts.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	lf := &logForwarder{
+		cm:                cm,
+		fwd:               forward.NewForwarder(sink),
+		sources:           opts.Sources,
+		grep:              opts.Grep,
+		allowedNamespaces: allowedNamespaces,
+		cancel:            cancel,
+		doneCh:            make(chan struct{}),
+	}
+
+	go lf.run(ctx)
+
+	return lf, nil
+}
+
+// Stop following sources and flush queued records
+func (lf *logForwarder) Shutdown(ctx context.Context) error {
+	lf.cancel()
+	<-lf.doneCh
+	return lf.fwd.Close(ctx)
+}
+
+// Follow sources until shutdown, re-opening the stream from the last
+// forwarded record if it ends
+func (lf *logForwarder) run(ctx context.Context) {
+	defer close(lf.doneCh)
+
+	since := time.Now()
+	for {
+		last, err := lf.follow(ctx, since)
+		if !last.IsZero() {
+			since = last.Add(time.Nanosecond)
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		zlog.Error().Err(err).Strs("sources", lf.sources).Msg("log forwarder stream ended, restarting")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(logForwarderRestartDelay):
+		}
+	}
+}
+
+// Forward records starting at `since` and return the timestamp of the last
+// forwarded record
+func (lf *logForwarder) follow(ctx context.Context, since time.Time) (time.Time, error) {
+	var last time.Time
+
+	clientset, err := lf.cm.GetOrCreateClientset("")
+	if err != nil {
+		return last, err
+	}
+
+	stream, err := logs.NewStream(ctx, lf.cm, lf.sources,
+		logs.WithAllowedNamespaces(lf.allowedNamespaces()),
+		logs.WithAll(),
+		logs.WithSince(since),
+		logs.WithFollow(true),
+		logs.WithGrep(lf.grep),
+		logs.WithLogFetcher(logs.NewKubeLogFetcher(clientset)),
+	)
+	if err != nil {
+		return last, err
+	}
+	defer stream.Close()
+
+	if err := stream.Start(ctx); err != nil {
+		return last, err
+	}
+
+	for record := range stream.Records() {
+		if err := lf.fwd.Forward(ctx, record); err != nil {
+			return last, err
+		}
+		last = record.Timestamp
+	}
+
+	return last, stream.Err()
+}
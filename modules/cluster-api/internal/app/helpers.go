@@ -17,9 +17,11 @@ package app
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
 	"os"
 
 	zlog "github.com/rs/zerolog/log"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
@@ -28,12 +30,80 @@ import (
 
 	"github.com/kubetail-org/kubetail/modules/shared/config"
 	"github.com/kubetail-org/kubetail/modules/shared/grpchelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/otel"
+	"github.com/kubetail-org/kubetail/modules/shared/quota"
 )
 
+// Return tracer config
+func newOTelConfig(cfg *config.Config) *otel.OTelConfig {
+	opts := cfg.ClusterAPI.Tracing
+
+	otelCfg := &otel.OTelConfig{
+		Enabled:       opts.Enabled,
+		Exporter:      otel.Exporter(opts.Exporter),
+		Endpoint:      opts.Endpoint,
+		Headers:       opts.Headers,
+		SamplingRatio: opts.SamplingRatio,
+		ServiceName:   "kubetail-cluster-api",
+	}
+	otelCfg.TLS.Enabled = opts.TLS.Enabled
+	otelCfg.TLS.CAFile = opts.TLS.CAFile
+	otelCfg.TLS.CertFile = opts.TLS.CertFile
+	otelCfg.TLS.KeyFile = opts.TLS.KeyFile
+
+	return otelCfg
+}
+
+// Return per-user quota limits
+func newQuotaLimits(cfg *config.Config) quota.Limits {
+	return quota.Limits{
+		MaxConcurrentSubscriptions: cfg.ClusterAPI.Quotas.MaxConcurrentSubscriptions,
+		MaxConcurrentFetches:       cfg.ClusterAPI.Quotas.MaxConcurrentFetches,
+		RequestsPerMinute:          cfg.ClusterAPI.Quotas.RequestsPerMinute,
+		MaxSourcesPerStream:        cfg.ClusterAPI.Quotas.MaxSourcesPerStream,
+	}
+}
+
+// Return server tls config or nil if tls is disabled
+func newServerTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	if !cfg.ClusterAPI.TLS.Enabled {
+		return nil, nil
+	}
+
+	// Server cert
+	serverCert, err := tls.LoadX509KeyPair(cfg.ClusterAPI.TLS.CertFile, cfg.ClusterAPI.TLS.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Init tls config
+	tlsCfg := &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   cfg.ClusterAPI.TLS.ClientAuth,
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if cfg.ClusterAPI.TLS.CAFile != "" {
+		// Root CA for client verification
+		caPem, err := os.ReadFile(cfg.ClusterAPI.TLS.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caPem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.ClusterAPI.TLS.CAFile)
+		}
+		tlsCfg.ClientCAs = clientCAs
+	}
+
+	return tlsCfg, nil
+}
+
 func mustNewGrpcDispatcher(cfg *config.Config) *grpcdispatcher.Dispatcher {
 	dialOpts := []grpc.DialOption{
 		grpc.WithUnaryInterceptor(grpchelpers.AuthUnaryClientInterceptor),
 		grpc.WithStreamInterceptor(grpchelpers.AuthStreamClientInterceptor),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()), // span per agent request and trace propagation
 	}
 
 	// configure tls
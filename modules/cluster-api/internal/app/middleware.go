@@ -16,6 +16,7 @@ package app
 
 import (
 	"context"
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -49,6 +50,22 @@ func authenticationMiddleware(c *gin.Context) {
 		c.Request = c.Request.WithContext(ctx)
 	}
 
+	// Add forwarded identity to context (the token must be allowed to impersonate it)
+	if imp := k8shelpers.ParseForwardedImpersonationHeaders(c.Request.Header); token != "" && imp != nil {
+		ctx := context.WithValue(c.Request.Context(), k8shelpers.ImpersonateCtxKey, imp)
+		ctx = context.WithValue(ctx, grpchelpers.ImpersonateCtxKey, imp)
+		c.Request = c.Request.WithContext(ctx)
+	}
+
 	// Continue
 	c.Next()
 }
+
+// Reject requests without a bearer token
+func tokenRequiredMiddleware(c *gin.Context) {
+	if _, ok := c.Request.Context().Value(k8shelpers.K8STokenCtxKey).(string); !ok {
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	c.Next()
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/kubetail-org/kubetail/modules/shared/grpchelpers"
 	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
 )
 
 func TestAuthenticationMiddleware(t *testing.T) {
@@ -109,3 +110,81 @@ func TestAuthenticationMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthenticationMiddlewareImpersonation(t *testing.T) {
+	tests := []struct {
+		name       string
+		setHeaders map[string]string
+		wantUser   string
+	}{
+		{
+			"forwarded identity with token",
+			map[string]string{
+				"X-Forwarded-Authorization":     "Bearer xxx",
+				"X-Forwarded-Impersonate-User":  "alice",
+				"X-Forwarded-Impersonate-Group": "dev",
+			},
+			"alice",
+		},
+		{
+			"forwarded identity without token",
+			map[string]string{
+				"X-Forwarded-Impersonate-User": "alice",
+			},
+			"",
+		},
+		{
+			"token without forwarded identity",
+			map[string]string{
+				"Authorization": "Bearer xxx",
+			},
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init router
+			router := gin.New()
+
+			// Add middleware
+			router.Use(authenticationMiddleware)
+
+			// Add route for testing
+			router.GET("/", func(c *gin.Context) {
+				ctx := c.Request.Context()
+
+				// Check identity for kubernetes requests
+				imp := k8shelpers.ImpersonationFromContext(ctx)
+
+				// Check identity for gRPC requests
+				grpcImp, _ := ctx.Value(grpchelpers.ImpersonateCtxKey).(*rest.ImpersonationConfig)
+
+				if tt.wantUser == "" {
+					assert.Nil(t, imp)
+					assert.Nil(t, grpcImp)
+				} else if assert.NotNil(t, imp) && assert.NotNil(t, grpcImp) {
+					assert.Equal(t, tt.wantUser, imp.UserName)
+					assert.Equal(t, []string{"dev"}, imp.Groups)
+					assert.Equal(t, imp, grpcImp)
+				}
+
+				c.String(http.StatusOK, "ok")
+			})
+
+			// Build request
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/", nil)
+
+			for key, val := range tt.setHeaders {
+				r.Header.Add(key, val)
+			}
+
+			// Execute request
+			router.ServeHTTP(w, r)
+
+			// Check response
+			assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		})
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package followmux
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+	"github.com/kubetail-org/kubetail/modules/shared/metrics"
+)
+
+// Default number of records buffered per subscriber
+const DefaultBufferSize = 1024
+
+// Multiple of the buffer size that subscribers can hold back while they
+// catch up on earlier records
+const catchUpBufferFactor = 16
+
+// ErrSlowSubscriber is returned to subscribers that fall too far behind the
+// shared upstream
+var ErrSlowSubscriber = errors.New("subscriber too slow to keep up with log stream")
+
+// Upstream is a live record stream shared by subscribers
+type Upstream interface {
+	Records() <-chan logs.LogRecord
+	Err() error
+	Close()
+}
+
+// UpstreamFactory starts an upstream that follows records from `since`. The
+// context carries no subscriber values so the factory must supply its own
+// credentials. It ends when the last subscriber leaves.
+type UpstreamFactory func(ctx context.Context, since time.Time) (Upstream, error)
+
+// Mux shares one upstream among subscriptions with the same key
+type Mux struct {
+	bufferSize int
+	upstreams  map[string]*upstream
+	mu         sync.Mutex
+}
+
+// New creates a new Mux. Each subscriber buffers up to `bufferSize` records
+// and is dropped if its buffer overflows so slow subscribers don't hold up
+// the others.
+func New(bufferSize int) *Mux {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Mux{
+		bufferSize: bufferSize,
+		upstreams:  make(map[string]*upstream),
+	}
+}
+
+// Subscribe joins the upstream for `key`, starting one with `newUpstream` if
+// there isn't one. The upstream outlives individual subscribers so it
+// doesn't run with any subscriber's credentials and callers must authorize
+// subscribers before joining. The subscription only receives records with
+// timestamps after its cutoff, so callers should fetch earlier records
+// separately. Records are held back until Records() is first called.
+func (m *Mux) Subscribe(ctx context.Context, key string, newUpstream UpstreamFactory) (*Subscription, error) {
+	for {
+		m.mu.Lock()
+		u, exists := m.upstreams[key]
+		if !exists {
+			u = m.startUpstream_UNSAFE(key, newUpstream)
+		}
+		m.mu.Unlock()
+
+		// Wait for upstream to start
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-u.readyCh:
+		}
+
+		if u.startErr != nil {
+			return nil, u.startErr
+		}
+
+		if sub := u.add(m.bufferSize, catchUpBufferFactor*m.bufferSize); sub != nil {
+			return sub, nil
+		}
+
+		// Upstream ended before we joined, try again with a new one
+		m.removeUpstream(key, u)
+	}
+}
+
+// Return number of running upstreams
+func (m *Mux) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.upstreams)
+}
+
+// Register new upstream and start it in the background
+func (m *Mux) startUpstream_UNSAFE(key string, newUpstream UpstreamFactory) *upstream {
+	// Detach from the first subscriber so the upstream doesn't end or keep
+	// running with its credentials when it leaves
+	uCtx, cancel := context.WithCancel(context.Background())
+
+	u := &upstream{
+		since:       time.Now(),
+		subscribers: make(map[*Subscription]struct{}),
+		readyCh:     make(chan struct{}),
+	}
+
+	// Stop when last subscriber leaves
+	u.onEmpty = func() {
+		m.removeUpstream(key, u)
+		cancel()
+	}
+
+	m.upstreams[key] = u
+	metrics.FollowUpstreams.Inc()
+
+	go func() {
+		defer cancel()
+		defer m.removeUpstream(key, u)
+
+		stream, err := newUpstream(uCtx, u.since)
+		if err != nil {
+			u.startErr = err
+			close(u.readyCh)
+			return
+		}
+		defer stream.Close()
+
+		close(u.readyCh)
+
+		u.run(uCtx, stream)
+	}()
+
+	return u
+}
+
+// Remove upstream from registry if it's still registered under `key`
+func (m *Mux) removeUpstream(key string, u *upstream) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.upstreams[key] == u {
+		delete(m.upstreams, key)
+		metrics.FollowUpstreams.Dec()
+	}
+}
+
+// Represents a running upstream and its subscribers
+type upstream struct {
+	since       time.Time
+	subscribers map[*Subscription]struct{}
+	readyCh     chan struct{}
+	startErr    error
+	onEmpty     func()
+	closed      bool
+	mu          sync.Mutex
+}
+
+// Add subscriber. Returns nil if the upstream has ended.
+func (u *upstream) add(bufferSize int, maxPending int) *Subscription {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.closed {
+		return nil
+	}
+
+	sub := &Subscription{
+		cutoff:     time.Now(),
+		ch:         make(chan logs.LogRecord, bufferSize),
+		maxPending: maxPending,
+		u:          u,
+	}
+	u.subscribers[sub] = struct{}{}
+
+	return sub
+}
+
+// Remove subscriber and stop upstream if it was the last one
+func (u *upstream) remove(sub *Subscription) {
+	u.mu.Lock()
+	_, exists := u.subscribers[sub]
+	if exists {
+		delete(u.subscribers, sub)
+		sub.end_UNSAFE(nil)
+	}
+	isEmpty := len(u.subscribers) == 0 && !u.closed
+	if isEmpty {
+		u.closed = true
+	}
+	u.mu.Unlock()
+
+	if isEmpty {
+		u.onEmpty()
+	}
+}
+
+// Forward records to subscribers until the stream ends
+func (u *upstream) run(ctx context.Context, stream Upstream) {
+	for record := range stream.Records() {
+		if ctx.Err() != nil {
+			break
+		}
+		u.broadcast(record)
+	}
+
+	// End subscriptions
+	err := stream.Err()
+	if err == nil && ctx.Err() == nil {
+		err = errors.New("log stream ended")
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.closed = true
+	for sub := range u.subscribers {
+		delete(u.subscribers, sub)
+		sub.end_UNSAFE(err)
+	}
+}
+
+// Send record to subscribers without blocking
+func (u *upstream) broadcast(record logs.LogRecord) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for sub := range u.subscribers {
+		if !record.Timestamp.After(sub.cutoff) {
+			continue
+		}
+
+		// Hold back records until subscriber has caught up
+		if !sub.isLive {
+			if len(sub.pending) < sub.maxPending {
+				sub.pending = append(sub.pending, record)
+				continue
+			}
+		} else {
+			select {
+			case sub.ch <- record:
+				continue
+			default:
+			}
+		}
+
+		// Drop subscriber if it can't keep up
+		delete(u.subscribers, sub)
+		sub.pending = nil
+		sub.end_UNSAFE(ErrSlowSubscriber)
+	}
+
+	if len(u.subscribers) == 0 && !u.closed {
+		u.closed = true
+		go u.onEmpty()
+	}
+}
+
+// Subscription represents a subscriber's view of a shared upstream
+type Subscription struct {
+	cutoff     time.Time
+	ch         chan logs.LogRecord
+	pending    []logs.LogRecord // held back until Records() is called
+	maxPending int
+	isLive     bool
+	isEnded    bool
+	err        error
+	u          *upstream
+}
+
+// Cutoff returns the time after which records are delivered
+func (s *Subscription) Cutoff() time.Time {
+	return s.cutoff
+}
+
+// Records returns the subscription's record channel, starting with any
+// records held back since the subscription started. The channel is closed
+// when the subscription ends.
+func (s *Subscription) Records() <-chan logs.LogRecord {
+	s.u.mu.Lock()
+	defer s.u.mu.Unlock()
+
+	if !s.isLive {
+		s.isLive = true
+
+		// Grow channel to fit held back records
+		if len(s.pending) > 0 {
+			ch := make(chan logs.LogRecord, len(s.pending)+cap(s.ch))
+			for _, record := range s.pending {
+				ch <- record
+			}
+			s.ch = ch
+			s.pending = nil
+		}
+
+		if s.isEnded {
+			close(s.ch)
+		}
+	}
+
+	return s.ch
+}
+
+// Err returns the reason the subscription ended. Should only be called
+// after the record channel is closed.
+func (s *Subscription) Err() error {
+	s.u.mu.Lock()
+	defer s.u.mu.Unlock()
+	return s.err
+}
+
+// Close leaves the upstream
+func (s *Subscription) Close() {
+	s.u.remove(s)
+}
+
+// End subscription. The channel is closed once the subscriber is reading
+// from it.
+func (s *Subscription) end_UNSAFE(err error) {
+	if err != nil {
+		s.err = err
+	}
+	s.isEnded = true
+	if s.isLive {
+		close(s.ch)
+	}
+}
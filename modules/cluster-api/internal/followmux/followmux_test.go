@@ -0,0 +1,295 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package followmux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+)
+
+// mockUpstream implements Upstream for testing
+type mockUpstream struct {
+	ch     chan logs.LogRecord
+	err    error
+	closed atomic.Bool
+}
+
+func newMockUpstream(ctx context.Context) *mockUpstream {
+	u := &mockUpstream{ch: make(chan logs.LogRecord)}
+	go func() {
+		<-ctx.Done()
+		u.closed.Store(true)
+	}()
+	return u
+}
+
+func (u *mockUpstream) Records() <-chan logs.LogRecord {
+	return u.ch
+}
+
+func (u *mockUpstream) Err() error {
+	return u.err
+}
+
+func (u *mockUpstream) Close() {
+}
+
+// Return factory that records the upstreams it creates
+func newFactory(upstreams chan<- *mockUpstream) UpstreamFactory {
+	return func(ctx context.Context, since time.Time) (Upstream, error) {
+		u := newMockUpstream(ctx)
+		upstreams <- u
+		return u, nil
+	}
+}
+
+// Read next record or fail
+func nextRecord(t *testing.T, sub *Subscription) logs.LogRecord {
+	t.Helper()
+	select {
+	case r, ok := <-sub.Records():
+		require.True(t, ok, "subscription ended")
+		return r
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for record")
+	}
+	return logs.LogRecord{}
+}
+
+// Wait for subscription to end
+func waitForEnd(t *testing.T, sub *Subscription) {
+	t.Helper()
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-sub.Records():
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for subscription to end")
+		}
+	}
+}
+
+func TestSubscribeSharesUpstream(t *testing.T) {
+	m := New(10)
+	upstreams := make(chan *mockUpstream, 10)
+
+	sub1, err := m.Subscribe(context.Background(), "k1", newFactory(upstreams))
+	require.NoError(t, err)
+	defer sub1.Close()
+
+	sub2, err := m.Subscribe(context.Background(), "k1", newFactory(upstreams))
+	require.NoError(t, err)
+	defer sub2.Close()
+
+	sub3, err := m.Subscribe(context.Background(), "k2", newFactory(upstreams))
+	require.NoError(t, err)
+	defer sub3.Close()
+
+	assert.Len(t, upstreams, 2)
+	assert.Equal(t, 2, m.Len())
+
+	// Records are sent to every subscriber of the upstream
+	u := <-upstreams
+	u.ch <- logs.LogRecord{Message: "msg1", Timestamp: time.Now()}
+
+	assert.Equal(t, "msg1", nextRecord(t, sub1).Message)
+	assert.Equal(t, "msg1", nextRecord(t, sub2).Message)
+}
+
+func TestSubscribeCutoff(t *testing.T) {
+	m := New(10)
+	upstreams := make(chan *mockUpstream, 1)
+
+	sub, err := m.Subscribe(context.Background(), "k1", newFactory(upstreams))
+	require.NoError(t, err)
+	defer sub.Close()
+
+	u := <-upstreams
+
+	// Records at or before the cutoff are skipped
+	u.ch <- logs.LogRecord{Message: "old", Timestamp: sub.Cutoff()}
+	u.ch <- logs.LogRecord{Message: "new", Timestamp: sub.Cutoff().Add(time.Nanosecond)}
+
+	assert.Equal(t, "new", nextRecord(t, sub).Message)
+}
+
+func TestSlowSubscriber(t *testing.T) {
+	m := New(1)
+	upstreams := make(chan *mockUpstream, 1)
+
+	slow, err := m.Subscribe(context.Background(), "k1", newFactory(upstreams))
+	require.NoError(t, err)
+	defer slow.Close()
+
+	fast, err := m.Subscribe(context.Background(), "k1", newFactory(upstreams))
+	require.NoError(t, err)
+	defer fast.Close()
+
+	u := <-upstreams
+	ts := fast.Cutoff()
+
+	// Both subscribers have caught up
+	slow.Records()
+
+	// Fast subscriber keeps reading while slow one doesn't
+	for i := 1; i <= 3; i++ {
+		u.ch <- logs.LogRecord{Message: "msg", Timestamp: ts.Add(time.Duration(i))}
+		nextRecord(t, fast)
+	}
+
+	waitForEnd(t, slow)
+	assert.ErrorIs(t, slow.Err(), ErrSlowSubscriber)
+
+	// Fast subscriber is unaffected
+	u.ch <- logs.LogRecord{Message: "msg4", Timestamp: ts.Add(4)}
+	assert.Equal(t, "msg4", nextRecord(t, fast).Message)
+}
+
+func TestCatchUpHoldsBackRecords(t *testing.T) {
+	m := New(1)
+	upstreams := make(chan *mockUpstream, 1)
+
+	sub, err := m.Subscribe(context.Background(), "k1", newFactory(upstreams))
+	require.NoError(t, err)
+	defer sub.Close()
+
+	u := <-upstreams
+	ts := sub.Cutoff()
+
+	// More records than the buffer size arrive during catch-up
+	for i := 1; i <= 5; i++ {
+		u.ch <- logs.LogRecord{Message: fmt.Sprintf("msg%d", i), Timestamp: ts.Add(time.Duration(i))}
+	}
+
+	for i := 1; i <= 5; i++ {
+		assert.Equal(t, fmt.Sprintf("msg%d", i), nextRecord(t, sub).Message)
+	}
+}
+
+func TestCatchUpOverflow(t *testing.T) {
+	m := New(1)
+	upstreams := make(chan *mockUpstream, 1)
+
+	sub, err := m.Subscribe(context.Background(), "k1", newFactory(upstreams))
+	require.NoError(t, err)
+	defer sub.Close()
+
+	// Live subscriber confirms each record was broadcast
+	live, err := m.Subscribe(context.Background(), "k1", newFactory(upstreams))
+	require.NoError(t, err)
+	defer live.Close()
+
+	u := <-upstreams
+	ts := live.Cutoff()
+
+	for i := 1; i <= catchUpBufferFactor+1; i++ {
+		u.ch <- logs.LogRecord{Message: "msg", Timestamp: ts.Add(time.Duration(i))}
+		nextRecord(t, live)
+	}
+
+	waitForEnd(t, sub)
+	assert.ErrorIs(t, sub.Err(), ErrSlowSubscriber)
+}
+
+func TestLastSubscriberStopsUpstream(t *testing.T) {
+	m := New(10)
+	upstreams := make(chan *mockUpstream, 2)
+
+	sub1, err := m.Subscribe(context.Background(), "k1", newFactory(upstreams))
+	require.NoError(t, err)
+
+	sub2, err := m.Subscribe(context.Background(), "k1", newFactory(upstreams))
+	require.NoError(t, err)
+
+	u := <-upstreams
+
+	sub1.Close()
+	assert.Equal(t, 1, m.Len())
+
+	sub2.Close()
+	assert.Equal(t, 0, m.Len())
+	assert.Eventually(t, u.closed.Load, time.Second, 10*time.Millisecond)
+
+	// New subscription starts a new upstream
+	sub3, err := m.Subscribe(context.Background(), "k1", newFactory(upstreams))
+	require.NoError(t, err)
+	defer sub3.Close()
+	assert.Len(t, upstreams, 1)
+}
+
+func TestUpstreamError(t *testing.T) {
+	m := New(10)
+	upstreams := make(chan *mockUpstream, 1)
+
+	sub, err := m.Subscribe(context.Background(), "k1", newFactory(upstreams))
+	require.NoError(t, err)
+	defer sub.Close()
+
+	expectedErr := errors.New("agent unavailable")
+
+	u := <-upstreams
+	u.err = expectedErr
+	close(u.ch)
+
+	waitForEnd(t, sub)
+	assert.ErrorIs(t, sub.Err(), expectedErr)
+	assert.Eventually(t, func() bool { return m.Len() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestUpstreamStartError(t *testing.T) {
+	m := New(10)
+	expectedErr := errors.New("forbidden")
+
+	_, err := m.Subscribe(context.Background(), "k1", func(ctx context.Context, since time.Time) (Upstream, error) {
+		return nil, expectedErr
+	})
+	assert.ErrorIs(t, err, expectedErr)
+	assert.Eventually(t, func() bool { return m.Len() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestUpstreamOutlivesFirstSubscriber(t *testing.T) {
+	m := New(10)
+	upstreams := make(chan *mockUpstream, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sub1, err := m.Subscribe(ctx, "k1", newFactory(upstreams))
+	require.NoError(t, err)
+
+	sub2, err := m.Subscribe(context.Background(), "k1", newFactory(upstreams))
+	require.NoError(t, err)
+	defer sub2.Close()
+
+	// First subscriber goes away
+	cancel()
+	sub1.Close()
+
+	u := <-upstreams
+	u.ch <- logs.LogRecord{Message: "msg1", Timestamp: time.Now()}
+	assert.Equal(t, "msg1", nextRecord(t, sub2).Message)
+	assert.False(t, u.closed.Load())
+}
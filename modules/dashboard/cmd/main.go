@@ -108,6 +108,16 @@ func main() {
 				zlog.Fatal().Caller().Err(err).Send()
 			}
 
+			// Apply config file changes to the running app (also on SIGHUP)
+			if cli.Config != "" {
+				watcher, err := config.NewWatcher(v, cli.Config, cfg)
+				if err != nil {
+					zlog.Fatal().Caller().Err(err).Send()
+				}
+				defer watcher.Close()
+				watcher.Subscribe(app.ApplyConfig)
+			}
+
 			// Create server
 			server := &http.Server{
 				Addr:         cfg.Dashboard.Addr,
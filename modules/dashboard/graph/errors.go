@@ -21,6 +21,13 @@ import (
 	"github.com/kubetail-org/kubetail/modules/shared/graphql/errors"
 )
 
+// Permalink errors
+var (
+	errPermalinksDisabled = errors.NewError("KUBETAIL_PERMALINKS_DISABLED", "Permalinks require a session secret")
+	errInvalidPermalink   = errors.NewError("KUBETAIL_INVALID_PERMALINK", "Invalid permalink")
+	errExpiredPermalink   = errors.NewError("KUBETAIL_EXPIRED_PERMALINK", "Permalink has expired")
+)
+
 // New Watch API error
 func newWatchErrorFromMetaV1Status(status *metav1.Status) *gqlerror.Error {
 	// init error
@@ -13,6 +13,15 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 )
 
+type BookmarkCreateInput struct {
+	SavedSearchID *string         `json:"savedSearchID,omitempty"`
+	KubeContext   *string         `json:"kubeContext,omitempty"`
+	Timestamp     time.Time       `json:"timestamp"`
+	Message       string          `json:"message"`
+	Source        *LogSourceInput `json:"source"`
+	Note          *string         `json:"note,omitempty"`
+}
+
 type HealthCheckResponse struct {
 	Status    HealthCheckStatus `json:"status"`
 	Message   *string           `json:"message,omitempty"`
@@ -38,6 +47,22 @@ type LogSourceFilter struct {
 	Container []string `json:"container,omitempty"`
 }
 
+type LogSourceInput struct {
+	Metadata      *LogSourceMetadataInput `json:"metadata,omitempty"`
+	Namespace     string                  `json:"namespace"`
+	PodName       string                  `json:"podName"`
+	ContainerName string                  `json:"containerName"`
+	ContainerID   string                  `json:"containerID"`
+}
+
+type LogSourceMetadataInput struct {
+	Region *string `json:"region,omitempty"`
+	Zone   *string `json:"zone,omitempty"`
+	Os     *string `json:"os,omitempty"`
+	Arch   *string `json:"arch,omitempty"`
+	Node   *string `json:"node,omitempty"`
+}
+
 type LogSourceWatchEvent struct {
 	Type   watch.EventType `json:"type"`
 	Object *logs.LogSource `json:"object,omitempty"`
@@ -57,6 +82,64 @@ type PageInfo struct {
 	StartCursor *string `json:"startCursor,omitempty"`
 }
 
+type Permalink struct {
+	Token     string     `json:"token"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+type PermalinkHighlight struct {
+	Timestamp   time.Time `json:"timestamp"`
+	ContainerID string    `json:"containerID"`
+}
+
+type PermalinkHighlightInput struct {
+	Timestamp   time.Time `json:"timestamp"`
+	ContainerID *string   `json:"containerID,omitempty"`
+}
+
+type PermalinkInput struct {
+	KubeContext  *string                  `json:"kubeContext,omitempty"`
+	Sources      []string                 `json:"sources"`
+	SourceFilter *LogSourceFilter         `json:"sourceFilter,omitempty"`
+	Grep         *string                  `json:"grep,omitempty"`
+	Mode         *LogRecordsQueryMode     `json:"mode,omitempty"`
+	Since        *string                  `json:"since,omitempty"`
+	Until        *string                  `json:"until,omitempty"`
+	Highlight    *PermalinkHighlightInput `json:"highlight,omitempty"`
+}
+
+type PermalinkSourceFilter struct {
+	Region    []string `json:"region"`
+	Zone      []string `json:"zone"`
+	Os        []string `json:"os"`
+	Arch      []string `json:"arch"`
+	Node      []string `json:"node"`
+	Container []string `json:"container"`
+}
+
+type PermalinkState struct {
+	KubeContext  string                 `json:"kubeContext"`
+	Sources      []string               `json:"sources"`
+	SourceFilter *PermalinkSourceFilter `json:"sourceFilter"`
+	Grep         string                 `json:"grep"`
+	Mode         *LogRecordsQueryMode   `json:"mode,omitempty"`
+	Since        string                 `json:"since"`
+	Until        string                 `json:"until"`
+	Highlight    *PermalinkHighlight    `json:"highlight,omitempty"`
+	ExpiresAt    *time.Time             `json:"expiresAt,omitempty"`
+}
+
+type SavedSearchInput struct {
+	Name         string           `json:"name"`
+	Description  *string          `json:"description,omitempty"`
+	KubeContext  *string          `json:"kubeContext,omitempty"`
+	Sources      []string         `json:"sources"`
+	SourceFilter *LogSourceFilter `json:"sourceFilter,omitempty"`
+	Grep         *string          `json:"grep,omitempty"`
+	Since        *string          `json:"since,omitempty"`
+	Until        *string          `json:"until,omitempty"`
+}
+
 type Subscription struct {
 }
 
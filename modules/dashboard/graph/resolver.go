@@ -16,21 +16,31 @@ package graph
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"sync"
 	"time"
 
+	authv1 "k8s.io/api/authentication/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
 	"k8s.io/utils/ptr"
 
+	"github.com/kubetail-org/kubetail/modules/shared/audit"
+	clusterapiclient "github.com/kubetail-org/kubetail/modules/shared/clusterapi"
 	"github.com/kubetail-org/kubetail/modules/shared/config"
+	gqlerrors "github.com/kubetail-org/kubetail/modules/shared/graphql/errors"
 	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/quota"
+	"github.com/kubetail-org/kubetail/modules/shared/util"
 
 	"github.com/kubetail-org/kubetail/modules/dashboard/graph/model"
 	clusterapi "github.com/kubetail-org/kubetail/modules/dashboard/internal/cluster-api"
+	"github.com/kubetail-org/kubetail/modules/dashboard/internal/store"
 )
 
 // This file will not be regenerated automatically.
@@ -43,8 +53,30 @@ type Resolver struct {
 	config            *config.Config
 	cm                k8shelpers.ConnectionManager
 	hm                clusterapi.HealthMonitor
+	clusterAPIClient  clusterapiclient.Client
+	store             *store.Store
+	audit             *audit.Logger
+	quotas            *quota.Limiter
 	environment       config.Environment
-	allowedNamespaces []string
+	allowedNamespaces func() []string
+	owners            util.SyncMap[string, ownerCacheValue]
+}
+
+// Owner lookups are cached to avoid sending a TokenReview on every request
+const ownerCacheTTL = 1 * time.Minute
+
+// ownerCacheValue represents a cached owner lookup with expiration
+type ownerCacheValue struct {
+	owner      string
+	expiration time.Time
+}
+
+// Return current namespace allow-list
+func (r *Resolver) getAllowedNamespaces() []string {
+	if r.allowedNamespaces == nil {
+		return nil
+	}
+	return r.allowedNamespaces()
 }
 
 // Teardown
@@ -55,7 +87,7 @@ func (r *Resolver) Teardown() {
 // listResource
 func (r *Resolver) listResource(ctx context.Context, kubeContext string, namespace *string, options *metav1.ListOptions, modelPtr runtime.Object) error {
 	// Deref namespace
-	nsList, err := k8shelpers.DerefNamespaceToList(r.allowedNamespaces, namespace, r.cm.GetDefaultNamespace(kubeContext))
+	nsList, err := k8shelpers.DerefNamespaceToList(r.getAllowedNamespaces(), namespace, r.cm.GetDefaultNamespace(kubeContext))
 	if err != nil {
 		return err
 	}
@@ -95,7 +127,7 @@ func (r *Resolver) listResource(ctx context.Context, kubeContext string, namespa
 // watchResourceMulti
 func (r *Resolver) watchResourceMulti(ctx context.Context, kubeContext string, namespace *string, options *metav1.ListOptions, gvr schema.GroupVersionResource) (<-chan *watch.Event, error) {
 	// Deref namespace
-	nsList, err := k8shelpers.DerefNamespaceToList(r.allowedNamespaces, namespace, r.cm.GetDefaultNamespace(kubeContext))
+	nsList, err := k8shelpers.DerefNamespaceToList(r.getAllowedNamespaces(), namespace, r.cm.GetDefaultNamespace(kubeContext))
 	if err != nil {
 		return nil, err
 	}
@@ -165,6 +197,89 @@ func (r *Resolver) watchResourceMulti(ctx context.Context, kubeContext string, n
 	return outCh, nil
 }
 
+// Return owner key for stored records. In token, oidc and header modes records are
+// scoped to the authenticated user, otherwise they're shared by everyone using the dashboard.
+func (r *Resolver) storeOwner(ctx context.Context) (string, error) {
+	switch r.config.Dashboard.AuthMode {
+	case config.AuthModeToken, config.AuthModeOIDC, config.AuthModeHeader:
+	default:
+		return "", nil
+	}
+
+	// Impersonated users were already authenticated by the dashboard
+	if imp, ok := ctx.Value(k8shelpers.ImpersonateCtxKey).(*rest.ImpersonationConfig); ok && imp != nil && imp.UserName != "" {
+		return "user:" + imp.UserName, nil
+	}
+
+	token, _ := ctx.Value(k8shelpers.K8STokenCtxKey).(string)
+	if token == "" {
+		return "", gqlerrors.ErrUnauthenticated
+	}
+
+	// Check cache (keyed by token hash so raw tokens aren't kept around)
+	sum := sha256.Sum256([]byte(token))
+	cacheKey := hex.EncodeToString(sum[:])
+
+	if cachedVal, ok := r.owners.Load(cacheKey); ok {
+		if time.Now().Before(cachedVal.expiration) {
+			return cachedVal.owner, nil
+		}
+		r.owners.Delete(cacheKey)
+	}
+
+	// Get client
+	clientset, err := r.cm.GetOrCreateClientset("")
+	if err != nil {
+		return "", err
+	}
+
+	// Resolve token to username
+	tokenReview := &authv1.TokenReview{
+		Spec: authv1.TokenReviewSpec{
+			Token: token,
+		},
+	}
+
+	result, err := clientset.AuthenticationV1().TokenReviews().Create(ctx, tokenReview, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	if !result.Status.Authenticated || result.Status.User.Username == "" {
+		return "", gqlerrors.ErrUnauthenticated
+	}
+
+	owner := "user:" + result.Status.User.Username
+
+	// Cache successful lookups only
+	r.pruneOwners()
+	r.owners.Store(cacheKey, ownerCacheValue{
+		owner:      owner,
+		expiration: time.Now().Add(ownerCacheTTL),
+	})
+
+	return owner, nil
+}
+
+// Remove expired owner lookups
+func (r *Resolver) pruneOwners() {
+	now := time.Now()
+	r.owners.Range(func(key string, val ownerCacheValue) bool {
+		if now.After(val.expiration) {
+			r.owners.Delete(key)
+		}
+		return true
+	})
+}
+
+// getStore returns the store or an error if persistence is unavailable
+func (r *Resolver) getStore() (*store.Store, error) {
+	if r.store == nil {
+		return nil, gqlerrors.ErrServiceUnavailable
+	}
+	return r.store, nil
+}
+
 // kubernetesAPIHealthzGet
 func (r *Resolver) kubernetesAPIHealthzGet(ctx context.Context, kubeContext string) *model.HealthCheckResponse {
 	resp := &model.HealthCheckResponse{
@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"strconv"
@@ -26,7 +27,6 @@ import (
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler/transport"
-	"github.com/sosodev/duration"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -39,10 +39,15 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/utils/ptr"
 
+	"github.com/kubetail-org/kubetail/modules/shared/audit"
 	gqlerrors "github.com/kubetail-org/kubetail/modules/shared/graphql/errors"
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+	"github.com/kubetail-org/kubetail/modules/shared/permalink"
 
 	"github.com/kubetail-org/kubetail/modules/dashboard/graph/model"
 	clusterapi "github.com/kubetail-org/kubetail/modules/dashboard/internal/cluster-api"
+	"github.com/kubetail-org/kubetail/modules/dashboard/internal/store"
+	"github.com/kubetail-org/kubetail/modules/shared/util"
 )
 
 // Represents response from fetchListResource()
@@ -115,6 +120,8 @@ func GetGVR(obj runtime.Object) (schema.GroupVersionResource, error) {
 		return schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}, nil
 	case *batchv1.CronJob, *batchv1.CronJobList:
 		return schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}, nil
+	case *corev1.Event, *corev1.EventList:
+		return schema.GroupVersionResource{Group: "", Version: "v1", Resource: "events"}, nil
 	case *corev1.Pod, *corev1.PodList:
 		return schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}, nil
 	case *corev1.Service, *corev1.ServiceList:
@@ -421,24 +428,6 @@ func typeassertRuntimeObject[T any](object runtime.Object) (T, error) {
 	}
 }
 
-// Parse an input either as an ISO timestamp or an ISO duration string
-func parseTimeArg(arg string) (time.Time, error) {
-	var zero time.Time
-
-	arg = strings.TrimSpace(arg)
-	if arg == "" {
-		return zero, nil
-	} else if timeAgo, err := duration.Parse(arg); err == nil {
-		// Parsed as ISO duration
-		return time.Now().Add(-1 * timeAgo.ToTimeDuration()), nil
-	} else if ts, err := time.Parse(time.RFC3339Nano, arg); err == nil {
-		// Parsed as ISO timestamp
-		return ts, nil
-	}
-
-	return zero, fmt.Errorf("unable to parse arg %s", arg)
-}
-
 func healthCheckStatusFromClusterAPIHealthStatus(statusIn clusterapi.HealthStatus) model.HealthCheckStatus {
 	switch statusIn {
 	case clusterapi.HealthStatusSuccess:
@@ -455,3 +444,189 @@ func healthCheckStatusFromClusterAPIHealthStatus(statusIn clusterapi.HealthStatu
 		panic("not implemented")
 	}
 }
+
+// Convert SavedSearchInput to store record
+func savedSearchFromInput(input model.SavedSearchInput) (store.SavedSearch, error) {
+	out := store.SavedSearch{
+		Name:        strings.TrimSpace(input.Name),
+		Description: ptr.Deref(input.Description, ""),
+		KubeContext: ptr.Deref(input.KubeContext, ""),
+		Sources:     input.Sources,
+		Grep:        ptr.Deref(input.Grep, ""),
+		Since:       strings.TrimSpace(ptr.Deref(input.Since, "")),
+		Until:       strings.TrimSpace(ptr.Deref(input.Until, "")),
+	}
+
+	// Check that time args are parseable
+	if _, err := util.ParseTimeArg(out.Since); err != nil {
+		return out, gqlerrors.NewValidationError("time", "Invalid since value")
+	}
+
+	if _, err := util.ParseTimeArg(out.Until); err != nil {
+		return out, gqlerrors.NewValidationError("time", "Invalid until value")
+	}
+
+	if input.SourceFilter != nil {
+		out.Filter = store.SourceFilter{
+			Region:    input.SourceFilter.Region,
+			Zone:      input.SourceFilter.Zone,
+			OS:        input.SourceFilter.Os,
+			Arch:      input.SourceFilter.Arch,
+			Node:      input.SourceFilter.Node,
+			Container: input.SourceFilter.Container,
+		}
+	}
+
+	return out, nil
+}
+
+// Convert BookmarkCreateInput to store record
+func bookmarkFromInput(input model.BookmarkCreateInput) store.Bookmark {
+	out := store.Bookmark{
+		SavedSearchID: ptr.Deref(input.SavedSearchID, ""),
+		KubeContext:   ptr.Deref(input.KubeContext, ""),
+		Timestamp:     input.Timestamp,
+		Message:       input.Message,
+		Note:          ptr.Deref(input.Note, ""),
+	}
+
+	if input.Source != nil {
+		out.Source = logs.LogSource{
+			Namespace:     input.Source.Namespace,
+			PodName:       input.Source.PodName,
+			ContainerName: input.Source.ContainerName,
+			ContainerID:   input.Source.ContainerID,
+		}
+
+		if md := input.Source.Metadata; md != nil {
+			out.Source.Metadata = logs.LogSourceMetadata{
+				Region: ptr.Deref(md.Region, ""),
+				Zone:   ptr.Deref(md.Zone, ""),
+				OS:     ptr.Deref(md.Os, ""),
+				Arch:   ptr.Deref(md.Arch, ""),
+				Node:   ptr.Deref(md.Node, ""),
+			}
+		}
+	}
+
+	return out
+}
+
+// Convert PermalinkInput to permalink state
+func permalinkStateFromInput(input model.PermalinkInput) (permalink.State, error) {
+	out := permalink.State{
+		KubeContext: ptr.Deref(input.KubeContext, ""),
+		Sources:     input.Sources,
+		Grep:        ptr.Deref(input.Grep, ""),
+		Since:       strings.TrimSpace(ptr.Deref(input.Since, "")),
+		Until:       strings.TrimSpace(ptr.Deref(input.Until, "")),
+	}
+
+	// Check that time args are parseable
+	if _, err := util.ParseTimeArg(out.Since); err != nil {
+		return out, gqlerrors.NewValidationError("time", "Invalid since value")
+	}
+
+	if _, err := util.ParseTimeArg(out.Until); err != nil {
+		return out, gqlerrors.NewValidationError("time", "Invalid until value")
+	}
+
+	if input.Mode != nil {
+		out.Mode = input.Mode.String()
+	}
+
+	if input.SourceFilter != nil {
+		out.SourceFilter = permalink.SourceFilter{
+			Region:    input.SourceFilter.Region,
+			Zone:      input.SourceFilter.Zone,
+			OS:        input.SourceFilter.Os,
+			Arch:      input.SourceFilter.Arch,
+			Node:      input.SourceFilter.Node,
+			Container: input.SourceFilter.Container,
+		}
+	}
+
+	if input.Highlight != nil {
+		out.Highlight = &permalink.Highlight{
+			Timestamp:   input.Highlight.Timestamp,
+			ContainerID: ptr.Deref(input.Highlight.ContainerID, ""),
+		}
+	}
+
+	return out, nil
+}
+
+// Convert decoded permalink to GraphQL model
+func permalinkStateToModel(link *permalink.Link) *model.PermalinkState {
+	st := link.State
+
+	out := &model.PermalinkState{
+		KubeContext: st.KubeContext,
+		Sources:     st.Sources,
+		SourceFilter: &model.PermalinkSourceFilter{
+			Region:    st.SourceFilter.Region,
+			Zone:      st.SourceFilter.Zone,
+			Os:        st.SourceFilter.OS,
+			Arch:      st.SourceFilter.Arch,
+			Node:      st.SourceFilter.Node,
+			Container: st.SourceFilter.Container,
+		},
+		Grep:  st.Grep,
+		Since: st.Since,
+		Until: st.Until,
+	}
+
+	if mode := model.LogRecordsQueryMode(st.Mode); mode.IsValid() {
+		out.Mode = &mode
+	}
+
+	if st.Highlight != nil {
+		out.Highlight = &model.PermalinkHighlight{
+			Timestamp:   st.Highlight.Timestamp,
+			ContainerID: st.Highlight.ContainerID,
+		}
+	}
+
+	if !link.ExpiresAt.IsZero() {
+		out.ExpiresAt = ptr.To(link.ExpiresAt)
+	}
+
+	return out
+}
+
+// Convert permalink errors to GraphQL errors
+func permalinkError(err error) error {
+	switch {
+	case errors.Is(err, permalink.ErrNoSecret):
+		return errPermalinksDisabled
+	case errors.Is(err, permalink.ErrExpired):
+		return errExpiredPermalink
+	case errors.Is(err, permalink.ErrInvalidToken):
+		return errInvalidPermalink
+	default:
+		return err
+	}
+}
+
+// Convert store errors to GraphQL errors
+func storeError(err error) error {
+	if errors.Is(err, store.ErrNotFound) {
+		return gqlerrors.ErrRecordNotFound
+	}
+	return err
+}
+
+// Convert source filter to audit representation
+func auditSourceFilter(f *model.LogSourceFilter) audit.SourceFilter {
+	if f == nil {
+		return audit.SourceFilter{}
+	}
+	return audit.SourceFilter{
+		Region:    f.Region,
+		Zone:      f.Zone,
+		OS:        f.Os,
+		Arch:      f.Arch,
+		Node:      f.Node,
+		Container: f.Container,
+	}
+}
@@ -48,6 +48,8 @@ func TestGetGVRSuccess(t *testing.T) {
 		{"DaemonSetList", &appsv1.DaemonSetList{}, newGVR("apps", "v1", "daemonsets")},
 		{"Deployment", &appsv1.Deployment{}, newGVR("apps", "v1", "deployments")},
 		{"DeploymentList", &appsv1.DeploymentList{}, newGVR("apps", "v1", "deployments")},
+		{"Event", &corev1.Event{}, newGVR("", "v1", "events")},
+		{"EventList", &corev1.EventList{}, newGVR("", "v1", "events")},
 		{"Job", &batchv1.Job{}, newGVR("batch", "v1", "jobs")},
 		{"JobList", &batchv1.JobList{}, newGVR("batch", "v1", "jobs")},
 		{"Pod", &corev1.Pod{}, newGVR("", "v1", "pods")},
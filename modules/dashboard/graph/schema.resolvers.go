@@ -12,12 +12,17 @@ import (
 
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/kubetail-org/kubetail/modules/dashboard/graph/model"
+	"github.com/kubetail-org/kubetail/modules/dashboard/internal/store"
+	"github.com/kubetail-org/kubetail/modules/shared/audit"
 	"github.com/kubetail-org/kubetail/modules/shared/config"
 	gqlerrors "github.com/kubetail-org/kubetail/modules/shared/graphql/errors"
 	"github.com/kubetail-org/kubetail/modules/shared/helm"
 	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
 	"github.com/kubetail-org/kubetail/modules/shared/logs"
+	"github.com/kubetail-org/kubetail/modules/shared/permalink"
+	"github.com/kubetail-org/kubetail/modules/shared/util"
 	zlog "github.com/rs/zerolog/log"
+	"github.com/sosodev/duration"
 	"helm.sh/helm/v3/pkg/release"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
@@ -59,6 +64,11 @@ func (r *batchV1JobsWatchEventResolver) Object(ctx context.Context, obj *watch.E
 	return typeassertRuntimeObject[*batchv1.Job](obj.Object)
 }
 
+// Object is the resolver for the object field.
+func (r *coreV1EventsWatchEventResolver) Object(ctx context.Context, obj *watch.Event) (*corev1.Event, error) {
+	return typeassertRuntimeObject[*corev1.Event](obj.Object)
+}
+
 // Object is the resolver for the object field.
 func (r *coreV1NamespacesWatchEventResolver) Object(ctx context.Context, obj *watch.Event) (*corev1.Namespace, error) {
 	return typeassertRuntimeObject[*corev1.Namespace](obj.Object)
@@ -142,12 +152,172 @@ func (r *mutationResolver) HelmInstallLatest(ctx context.Context, kubeContext *s
 	return release, nil
 }
 
+// PermalinksCreate is the resolver for the permalinksCreate field.
+func (r *mutationResolver) PermalinksCreate(ctx context.Context, input model.PermalinkInput, expiresIn *string) (*model.Permalink, error) {
+	state, err := permalinkStateFromInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	link := permalink.Link{State: state}
+
+	// Parse expiry
+	if expiresIn != nil && *expiresIn != "" {
+		d, err := duration.Parse(*expiresIn)
+		if err != nil || d.ToTimeDuration() <= 0 {
+			return nil, gqlerrors.NewValidationError("duration", "Invalid expiresIn value")
+		}
+		link.ExpiresAt = time.Now().Add(d.ToTimeDuration()).UTC().Truncate(time.Second)
+	}
+
+	token, err := permalink.Sign(r.config.Dashboard.Session.Secret, link)
+	if err != nil {
+		return nil, permalinkError(err)
+	}
+
+	out := &model.Permalink{Token: token}
+	if !link.ExpiresAt.IsZero() {
+		out.ExpiresAt = ptr.To(link.ExpiresAt)
+	}
+
+	return out, nil
+}
+
+// SavedSearchesCreate is the resolver for the savedSearchesCreate field.
+func (r *mutationResolver) SavedSearchesCreate(ctx context.Context, input model.SavedSearchInput) (*store.SavedSearch, error) {
+	st, err := r.getStore()
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := r.storeOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := savedSearchFromInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return st.CreateSavedSearch(owner, item)
+}
+
+// SavedSearchesUpdate is the resolver for the savedSearchesUpdate field.
+func (r *mutationResolver) SavedSearchesUpdate(ctx context.Context, id string, input model.SavedSearchInput) (*store.SavedSearch, error) {
+	st, err := r.getStore()
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := r.storeOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := savedSearchFromInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := st.UpdateSavedSearch(owner, id, item)
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return out, nil
+}
+
+// SavedSearchesDelete is the resolver for the savedSearchesDelete field.
+func (r *mutationResolver) SavedSearchesDelete(ctx context.Context, id string) (bool, error) {
+	st, err := r.getStore()
+	if err != nil {
+		return false, err
+	}
+
+	owner, err := r.storeOwner(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := st.DeleteSavedSearch(owner, id); err != nil {
+		return false, storeError(err)
+	}
+
+	return true, nil
+}
+
+// BookmarksCreate is the resolver for the bookmarksCreate field.
+func (r *mutationResolver) BookmarksCreate(ctx context.Context, input model.BookmarkCreateInput) (*store.Bookmark, error) {
+	st, err := r.getStore()
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := r.storeOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := st.CreateBookmark(owner, bookmarkFromInput(input))
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return out, nil
+}
+
+// BookmarksUpdateNote is the resolver for the bookmarksUpdateNote field.
+func (r *mutationResolver) BookmarksUpdateNote(ctx context.Context, id string, note string) (*store.Bookmark, error) {
+	st, err := r.getStore()
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := r.storeOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := st.UpdateBookmarkNote(owner, id, note)
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return out, nil
+}
+
+// BookmarksDelete is the resolver for the bookmarksDelete field.
+func (r *mutationResolver) BookmarksDelete(ctx context.Context, id string) (bool, error) {
+	st, err := r.getStore()
+	if err != nil {
+		return false, err
+	}
+
+	owner, err := r.storeOwner(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := st.DeleteBookmark(owner, id); err != nil {
+		return false, storeError(err)
+	}
+
+	return true, nil
+}
+
+// CanReadLogs is the resolver for the canReadLogs field.
+func (r *permissionsResolver) CanReadLogs(ctx context.Context, obj *k8shelpers.Permissions) (bool, error) {
+	// Log streams need to watch pods and fetch their logs
+	return obj.Allows("", "pods", "list") && obj.Allows("", "pods", "watch") && obj.Allows("", "pods/log", "get"), nil
+}
+
 // AppsV1DaemonSetsGet is the resolver for the appsV1DaemonSetsGet field.
 func (r *queryResolver) AppsV1DaemonSetsGet(ctx context.Context, kubeContext *string, namespace *string, name string, options *metav1.GetOptions) (*appsv1.DaemonSet, error) {
 	kubeContextVal := r.cm.DerefKubeContext(kubeContext)
 
 	// Deref namespace
-	ns, err := k8shelpers.DerefNamespace(r.allowedNamespaces, namespace, r.cm.GetDefaultNamespace(kubeContextVal))
+	ns, err := k8shelpers.DerefNamespace(r.getAllowedNamespaces(), namespace, r.cm.GetDefaultNamespace(kubeContextVal))
 	if err != nil {
 		return nil, err
 	}
@@ -182,7 +352,7 @@ func (r *queryResolver) AppsV1DeploymentsGet(ctx context.Context, kubeContext *s
 	kubeContextVal := r.cm.DerefKubeContext(kubeContext)
 
 	// Deref namespace
-	ns, err := k8shelpers.DerefNamespace(r.allowedNamespaces, namespace, r.cm.GetDefaultNamespace(kubeContextVal))
+	ns, err := k8shelpers.DerefNamespace(r.getAllowedNamespaces(), namespace, r.cm.GetDefaultNamespace(kubeContextVal))
 	if err != nil {
 		return nil, err
 	}
@@ -217,7 +387,7 @@ func (r *queryResolver) AppsV1ReplicaSetsGet(ctx context.Context, kubeContext *s
 	kubeContextVal := r.cm.DerefKubeContext(kubeContext)
 
 	// Deref namespace
-	ns, err := k8shelpers.DerefNamespace(r.allowedNamespaces, namespace, r.cm.GetDefaultNamespace(kubeContextVal))
+	ns, err := k8shelpers.DerefNamespace(r.getAllowedNamespaces(), namespace, r.cm.GetDefaultNamespace(kubeContextVal))
 	if err != nil {
 		return nil, err
 	}
@@ -252,7 +422,7 @@ func (r *queryResolver) AppsV1StatefulSetsGet(ctx context.Context, kubeContext *
 	kubeContextVal := r.cm.DerefKubeContext(kubeContext)
 
 	// Deref namespace
-	ns, err := k8shelpers.DerefNamespace(r.allowedNamespaces, namespace, r.cm.GetDefaultNamespace(kubeContextVal))
+	ns, err := k8shelpers.DerefNamespace(r.getAllowedNamespaces(), namespace, r.cm.GetDefaultNamespace(kubeContextVal))
 	if err != nil {
 		return nil, err
 	}
@@ -287,7 +457,7 @@ func (r *queryResolver) BatchV1CronJobsGet(ctx context.Context, kubeContext *str
 	kubeContextVal := r.cm.DerefKubeContext(kubeContext)
 
 	// Deref namespace
-	ns, err := k8shelpers.DerefNamespace(r.allowedNamespaces, namespace, r.cm.GetDefaultNamespace(kubeContextVal))
+	ns, err := k8shelpers.DerefNamespace(r.getAllowedNamespaces(), namespace, r.cm.GetDefaultNamespace(kubeContextVal))
 	if err != nil {
 		return nil, err
 	}
@@ -322,7 +492,7 @@ func (r *queryResolver) BatchV1JobsGet(ctx context.Context, kubeContext *string,
 	kubeContextVal := r.cm.DerefKubeContext(kubeContext)
 
 	// Deref namespace
-	ns, err := k8shelpers.DerefNamespace(r.allowedNamespaces, namespace, r.cm.GetDefaultNamespace(kubeContextVal))
+	ns, err := k8shelpers.DerefNamespace(r.getAllowedNamespaces(), namespace, r.cm.GetDefaultNamespace(kubeContextVal))
 	if err != nil {
 		return nil, err
 	}
@@ -352,6 +522,18 @@ func (r *queryResolver) BatchV1JobsList(ctx context.Context, kubeContext *string
 	return outList, nil
 }
 
+// CoreV1EventsList is the resolver for the coreV1EventsList field.
+func (r *queryResolver) CoreV1EventsList(ctx context.Context, kubeContext *string, namespace *string, options *metav1.ListOptions) (*corev1.EventList, error) {
+	kubeContextVal := r.cm.DerefKubeContext(kubeContext)
+
+	outList := &corev1.EventList{}
+	if err := r.listResource(ctx, kubeContextVal, namespace, options, outList); err != nil {
+		return nil, err
+	}
+
+	return outList, nil
+}
+
 // CoreV1NamespacesList is the resolver for the coreV1NamespacesList field.
 func (r *queryResolver) CoreV1NamespacesList(ctx context.Context, kubeContext *string, options *metav1.ListOptions) (*corev1.NamespaceList, error) {
 	kubeContextVal := r.cm.DerefKubeContext(kubeContext)
@@ -371,10 +553,10 @@ func (r *queryResolver) CoreV1NamespacesList(ctx context.Context, kubeContext *s
 	}
 
 	// apply app namespace filter
-	if len(r.allowedNamespaces) > 0 {
+	if allowedNamespaces := r.getAllowedNamespaces(); len(allowedNamespaces) > 0 {
 		items := []corev1.Namespace{}
 		for _, item := range response.Items {
-			if slices.Contains(r.allowedNamespaces, item.Name) {
+			if slices.Contains(allowedNamespaces, item.Name) {
 				items = append(items, item)
 			}
 		}
@@ -406,7 +588,7 @@ func (r *queryResolver) CoreV1PodsGet(ctx context.Context, kubeContext *string,
 	kubeContextVal := r.cm.DerefKubeContext(kubeContext)
 
 	// Deref namespace
-	ns, err := k8shelpers.DerefNamespace(r.allowedNamespaces, namespace, r.cm.GetDefaultNamespace(kubeContextVal))
+	ns, err := k8shelpers.DerefNamespace(r.getAllowedNamespaces(), namespace, r.cm.GetDefaultNamespace(kubeContextVal))
 	if err != nil {
 		return nil, err
 	}
@@ -441,7 +623,7 @@ func (r *queryResolver) CoreV1ServicesGet(ctx context.Context, kubeContext *stri
 	kubeContextVal := r.cm.DerefKubeContext(kubeContext)
 
 	// Deref namespace
-	ns, err := k8shelpers.DerefNamespace(r.allowedNamespaces, namespace, r.cm.GetDefaultNamespace(kubeContextVal))
+	ns, err := k8shelpers.DerefNamespace(r.getAllowedNamespaces(), namespace, r.cm.GetDefaultNamespace(kubeContextVal))
 	if err != nil {
 		return nil, err
 	}
@@ -581,27 +763,41 @@ func (r *queryResolver) KubernetesAPIHealthzGet(ctx context.Context, kubeContext
 }
 
 // LogRecordsFetch is the resolver for the logRecordsFetch field.
-func (r *queryResolver) LogRecordsFetch(ctx context.Context, kubeContext *string, sources []string, mode *model.LogRecordsQueryMode, since *string, until *string, after *string, before *string, grep *string, sourceFilter *model.LogSourceFilter, limit *int) (*model.LogRecordsQueryResponse, error) {
+func (r *queryResolver) LogRecordsFetch(ctx context.Context, kubeContext *string, sources []string, mode *model.LogRecordsQueryMode, since *string, until *string, after *string, before *string, grep *string, sourceFilter *model.LogSourceFilter, servingOnly *bool, limit *int) (*model.LogRecordsQueryResponse, error) {
 	kubeContextVal := r.cm.DerefKubeContext(kubeContext)
 
+	// Init audit entry
+	ae := r.audit.Start(ctx, audit.Event{
+		Operation:    "logRecordsFetch",
+		KubeContext:  kubeContextVal,
+		Sources:      sources,
+		SourceFilter: auditSourceFilter(sourceFilter),
+		Grep:         ptr.Deref(grep, ""),
+		Mode:         string(ptr.Deref(mode, model.LogRecordsQueryModeTail)),
+	})
+
 	// Parse time args
-	sinceTime, err := parseTimeArg(ptr.Deref(since, ""))
+	sinceTime, err := util.ParseTimeArg(ptr.Deref(since, ""))
 	if err != nil {
+		ae.Finish(err)
 		return nil, err
 	}
 
-	untilTime, err := parseTimeArg(ptr.Deref(until, ""))
+	untilTime, err := util.ParseTimeArg(ptr.Deref(until, ""))
 	if err != nil {
+		ae.Finish(err)
 		return nil, err
 	}
 
-	afterTime, err := parseTimeArg(ptr.Deref(after, ""))
+	afterTime, err := util.ParseTimeArg(ptr.Deref(after, ""))
 	if err != nil {
+		ae.Finish(err)
 		return nil, err
 	}
 
-	beforeTime, err := parseTimeArg(ptr.Deref(before, ""))
+	beforeTime, err := util.ParseTimeArg(ptr.Deref(before, ""))
 	if err != nil {
+		ae.Finish(err)
 		return nil, err
 	}
 
@@ -614,19 +810,29 @@ func (r *queryResolver) LogRecordsFetch(ctx context.Context, kubeContext *string
 		untilTime = beforeTime.Add(-1 * time.Nanosecond)
 	}
 
+	ae.SetTimeRange(sinceTime, untilTime)
+
 	// Get bearer token
 	var token string
 	if tokenValue, ok := ctx.Value(k8shelpers.K8STokenCtxKey).(string); ok {
 		token = tokenValue
 	}
 
+	// Check quotas
+	release, err := r.quotas.AcquireFetch(ctx)
+	if err != nil {
+		ae.Finish(err)
+		return nil, err
+	}
+	defer release()
+
 	// Init stream
 	sourceFilterVal := ptr.Deref(sourceFilter, model.LogSourceFilter{})
 
 	streamOpts := []logs.Option{
 		logs.WithKubeContext(kubeContextVal),
 		logs.WithBearerToken(token),
-		logs.WithAllowedNamespaces(r.allowedNamespaces),
+		logs.WithAllowedNamespaces(r.getAllowedNamespaces()),
 		logs.WithSince(sinceTime),
 		logs.WithUntil(untilTime),
 		logs.WithGrep(ptr.Deref(grep, "")),
@@ -636,6 +842,8 @@ func (r *queryResolver) LogRecordsFetch(ctx context.Context, kubeContext *string
 		logs.WithArches(sourceFilterVal.Arch),
 		logs.WithNodes(sourceFilterVal.Node),
 		logs.WithContainers(sourceFilterVal.Container),
+		logs.WithServingOnly(ptr.Deref(servingOnly, false)),
+		logs.WithMaxSources(r.quotas.MaxSourcesPerStream()),
 	}
 
 	limitVal := int64(ptr.Deref(limit, 100))
@@ -645,17 +853,22 @@ func (r *queryResolver) LogRecordsFetch(ctx context.Context, kubeContext *string
 	case model.LogRecordsQueryModeTail:
 		streamOpts = append(streamOpts, logs.WithTail(limitVal))
 	default:
-		return nil, fmt.Errorf("not implemented %s", mode)
+		err := fmt.Errorf("not implemented %s", mode)
+		ae.Finish(err)
+		return nil, err
 	}
 
 	stream, err := logs.NewStream(ctx, r.cm, sources, streamOpts...)
 	if err != nil {
+		ae.Finish(err)
 		return nil, err
 	}
 	defer stream.Close()
 
 	// Start stream
 	if err := stream.Start(ctx); err != nil {
+		err = r.quotas.Error(err)
+		ae.Finish(err)
 		return nil, err
 	}
 
@@ -667,16 +880,146 @@ func (r *queryResolver) LogRecordsFetch(ctx context.Context, kubeContext *string
 	}
 
 	if ctx.Err() != nil {
+		ae.Finish(ctx.Err())
 		return nil, ctx.Err()
 	}
 
 	if stream.Err() != nil {
+		ae.Finish(stream.Err())
 		return nil, stream.Err()
 	}
 
+	ae.AddRecords(len(out.Records))
+	ae.Finish(nil)
+
 	return out, nil
 }
 
+// LogUsageSummary is the resolver for the logUsageSummary field.
+func (r *queryResolver) LogUsageSummary(ctx context.Context, kubeContext *string, namespace *string, groupBy *logs.UsageGroupBy) ([]*logs.UsageGroup, error) {
+	kubeContextVal := r.cm.DerefKubeContext(kubeContext)
+
+	// Init audit entry
+	ae := r.audit.Start(ctx, audit.Event{
+		Operation:   "logUsageSummary",
+		KubeContext: kubeContextVal,
+	})
+
+	// Check namespace before forwarding
+	namespaceVal := ptr.Deref(namespace, r.cm.GetDefaultNamespace(kubeContextVal))
+	nsList, err := k8shelpers.DerefNamespaceToList(r.getAllowedNamespaces(), &namespaceVal, "")
+	if err != nil {
+		ae.Finish(err)
+		return nil, err
+	}
+	ae.SetNamespaces(nsList)
+
+	if r.clusterAPIClient == nil {
+		err := fmt.Errorf("cluster-api client unavailable")
+		ae.Finish(err)
+		return nil, err
+	}
+
+	groups, err := r.clusterAPIClient.LogUsageSummary(ctx, kubeContextVal, &namespaceVal, ptr.Deref(groupBy, logs.UsageGroupByWorkload))
+	if err != nil {
+		ae.Finish(err)
+		return nil, err
+	}
+
+	out := make([]*logs.UsageGroup, len(groups))
+	for i := range groups {
+		out[i] = &groups[i]
+	}
+
+	ae.AddRecords(len(out))
+	ae.Finish(nil)
+
+	return out, nil
+}
+
+// PermalinksGet is the resolver for the permalinksGet field.
+func (r *queryResolver) PermalinksGet(ctx context.Context, token string) (*model.PermalinkState, error) {
+	link, err := permalink.Verify(r.config.Dashboard.Session.Secret, permalink.TokenFromURL(token))
+	if err != nil {
+		return nil, permalinkError(err)
+	}
+
+	return permalinkStateToModel(link), nil
+}
+
+// Permissions is the resolver for the permissions field.
+func (r *queryResolver) Permissions(ctx context.Context, kubeContext *string, namespace *string) (*k8shelpers.Permissions, error) {
+	kubeContextVal := r.cm.DerefKubeContext(kubeContext)
+
+	ns, err := k8shelpers.DerefNamespace(r.getAllowedNamespaces(), namespace, r.cm.GetDefaultNamespace(kubeContextVal))
+	if err != nil {
+		return nil, err
+	}
+
+	// Rules reviews are namespace-scoped
+	if ns == "" {
+		return nil, gqlerrors.NewValidationError("namespace", "Namespace is required")
+	}
+
+	// Get bearer token
+	var token string
+	if tokenValue, ok := ctx.Value(k8shelpers.K8STokenCtxKey).(string); ok {
+		token = tokenValue
+	}
+
+	return r.cm.GetPermissions(ctx, kubeContextVal, token, ns)
+}
+
+// SavedSearchesGet is the resolver for the savedSearchesGet field.
+func (r *queryResolver) SavedSearchesGet(ctx context.Context, id string) (*store.SavedSearch, error) {
+	st, err := r.getStore()
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := r.storeOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := st.GetSavedSearch(owner, id)
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return out, nil
+}
+
+// SavedSearchesList is the resolver for the savedSearchesList field.
+func (r *queryResolver) SavedSearchesList(ctx context.Context) ([]*store.SavedSearch, error) {
+	st, err := r.getStore()
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := r.storeOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return st.ListSavedSearches(owner), nil
+}
+
+// BookmarksList is the resolver for the bookmarksList field.
+func (r *queryResolver) BookmarksList(ctx context.Context, savedSearchID *string) ([]*store.Bookmark, error) {
+	st, err := r.getStore()
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := r.storeOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return st.ListBookmarks(owner, ptr.Deref(savedSearchID, "")), nil
+}
+
 // AppsV1DaemonSetsWatch is the resolver for the appsV1DaemonSetsWatch field.
 func (r *subscriptionResolver) AppsV1DaemonSetsWatch(ctx context.Context, kubeContext *string, namespace *string, options *metav1.ListOptions) (<-chan *watch.Event, error) {
 	kubeContextVal := r.cm.DerefKubeContext(kubeContext)
@@ -719,6 +1062,13 @@ func (r *subscriptionResolver) BatchV1JobsWatch(ctx context.Context, kubeContext
 	return r.watchResourceMulti(ctx, kubeContextVal, namespace, options, gvr)
 }
 
+// CoreV1EventsWatch is the resolver for the coreV1EventsWatch field.
+func (r *subscriptionResolver) CoreV1EventsWatch(ctx context.Context, kubeContext *string, namespace *string, options *metav1.ListOptions) (<-chan *watch.Event, error) {
+	kubeContextVal := r.cm.DerefKubeContext(kubeContext)
+	gvr := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "events"}
+	return r.watchResourceMulti(ctx, kubeContextVal, namespace, options, gvr)
+}
+
 // CoreV1NamespacesWatch is the resolver for the coreV1NamespacesWatch field.
 func (r *subscriptionResolver) CoreV1NamespacesWatch(ctx context.Context, kubeContext *string, options *metav1.ListOptions) (<-chan *watch.Event, error) {
 	kubeContextVal := r.cm.DerefKubeContext(kubeContext)
@@ -749,7 +1099,8 @@ func (r *subscriptionResolver) CoreV1NamespacesWatch(ctx context.Context, kubeCo
 			}
 
 			// filter out non-authorized namespaces
-			if len(r.allowedNamespaces) == 0 || (len(r.allowedNamespaces) > 0 && slices.Contains(r.allowedNamespaces, ns.Name)) {
+			allowedNamespaces := r.getAllowedNamespaces()
+			if len(allowedNamespaces) == 0 || (len(allowedNamespaces) > 0 && slices.Contains(allowedNamespaces, ns.Name)) {
 				outCh <- ev
 			}
 		}
@@ -964,17 +1315,28 @@ func (r *subscriptionResolver) KubeConfigWatch(ctx context.Context) (<-chan *mod
 }
 
 // LogRecordsFollow is the resolver for the logRecordsFollow field.
-func (r *subscriptionResolver) LogRecordsFollow(ctx context.Context, kubeContext *string, sources []string, since *string, after *string, grep *string, sourceFilter *model.LogSourceFilter) (<-chan *logs.LogRecord, error) {
+func (r *subscriptionResolver) LogRecordsFollow(ctx context.Context, kubeContext *string, sources []string, since *string, after *string, grep *string, sourceFilter *model.LogSourceFilter, servingOnly *bool, withLifecycle *bool) (<-chan *logs.LogRecord, error) {
 	kubeContextVal := r.cm.DerefKubeContext(kubeContext)
 
+	// Init audit entry
+	ae := r.audit.Start(ctx, audit.Event{
+		Operation:    "logRecordsFollow",
+		KubeContext:  kubeContextVal,
+		Sources:      sources,
+		SourceFilter: auditSourceFilter(sourceFilter),
+		Grep:         ptr.Deref(grep, ""),
+	})
+
 	// Parse time args
-	sinceTime, err := parseTimeArg(ptr.Deref(since, ""))
+	sinceTime, err := util.ParseTimeArg(ptr.Deref(since, ""))
 	if err != nil {
+		ae.Finish(err)
 		return nil, err
 	}
 
-	afterTime, err := parseTimeArg(ptr.Deref(after, ""))
+	afterTime, err := util.ParseTimeArg(ptr.Deref(after, ""))
 	if err != nil {
+		ae.Finish(err)
 		return nil, err
 	}
 
@@ -983,19 +1345,28 @@ func (r *subscriptionResolver) LogRecordsFollow(ctx context.Context, kubeContext
 		sinceTime = afterTime.Add(1 * time.Nanosecond)
 	}
 
+	ae.SetTimeRange(sinceTime, time.Time{})
+
 	// Get bearer token
 	var token string
 	if tokenValue, ok := ctx.Value(k8shelpers.K8STokenCtxKey).(string); ok {
 		token = tokenValue
 	}
 
+	// Check quotas
+	release, err := r.quotas.AcquireSubscription(ctx)
+	if err != nil {
+		ae.Finish(err)
+		return nil, err
+	}
+
 	// Init stream
 	sourceFilterVal := ptr.Deref(sourceFilter, model.LogSourceFilter{})
 
 	streamOpts := []logs.Option{
 		logs.WithKubeContext(kubeContextVal),
 		logs.WithBearerToken(token),
-		logs.WithAllowedNamespaces(r.allowedNamespaces),
+		logs.WithAllowedNamespaces(r.getAllowedNamespaces()),
 		logs.WithAll(),
 		logs.WithFollow(true),
 		logs.WithSince(sinceTime),
@@ -1006,15 +1377,24 @@ func (r *subscriptionResolver) LogRecordsFollow(ctx context.Context, kubeContext
 		logs.WithArches(sourceFilterVal.Arch),
 		logs.WithNodes(sourceFilterVal.Node),
 		logs.WithContainers(sourceFilterVal.Container),
+		logs.WithServingOnly(ptr.Deref(servingOnly, false)),
+		logs.WithMaxSources(r.quotas.MaxSourcesPerStream()),
+		logs.WithLifecycle(ptr.Deref(withLifecycle, false)),
 	}
 
 	stream, err := logs.NewStream(ctx, r.cm, sources, streamOpts...)
 	if err != nil {
+		release()
+		ae.Finish(err)
 		return nil, err
 	}
 
 	// Start stream
 	if err := stream.Start(ctx); err != nil {
+		stream.Close()
+		release()
+		err = r.quotas.Error(err)
+		ae.Finish(err)
 		return nil, err
 	}
 
@@ -1023,18 +1403,23 @@ func (r *subscriptionResolver) LogRecordsFollow(ctx context.Context, kubeContext
 
 	// Write out in goroutine
 	go func() {
+		defer release()
 		defer close(outCh)
 		defer stream.Close()
 
 		for record := range stream.Records() {
 			select {
 			case <-ctx.Done():
+				// Client closed subscription
+				ae.Finish(nil)
 				return
 			case outCh <- &record:
-				// Success
+				ae.AddRecords(1)
 			}
 		}
 
+		ae.Finish(stream.Err())
+
 		// Handle errors
 		if stream.Err() != nil {
 			// Log the error on the server, including caller info as requested.
@@ -1049,11 +1434,27 @@ func (r *subscriptionResolver) LogRecordsFollow(ctx context.Context, kubeContext
 }
 
 // LogSourcesWatch is the resolver for the logSourcesWatch field.
-func (r *subscriptionResolver) LogSourcesWatch(ctx context.Context, kubeContext *string, sources []string) (<-chan *model.LogSourceWatchEvent, error) {
+func (r *subscriptionResolver) LogSourcesWatch(ctx context.Context, kubeContext *string, sources []string, servingOnly *bool) (<-chan *model.LogSourceWatchEvent, error) {
 	kubeContextVal := r.cm.DerefKubeContext(kubeContext)
 
-	sw, err := logs.NewSourceWatcher(r.cm, sources, logs.WithKubeContext(kubeContextVal))
+	// Init audit entry
+	ae := r.audit.Start(ctx, audit.Event{
+		Operation:   "logSourcesWatch",
+		KubeContext: kubeContextVal,
+		Sources:     sources,
+	})
+
+	// Check quotas
+	release, err := r.quotas.AcquireSubscription(ctx)
 	if err != nil {
+		ae.Finish(err)
+		return nil, err
+	}
+
+	sw, err := logs.NewSourceWatcher(r.cm, sources, logs.WithKubeContext(kubeContextVal), logs.WithServingOnly(ptr.Deref(servingOnly, false)))
+	if err != nil {
+		release()
+		ae.Finish(err)
 		return nil, err
 	}
 
@@ -1061,6 +1462,8 @@ func (r *subscriptionResolver) LogSourcesWatch(ctx context.Context, kubeContext
 
 	// Start source watcher
 	go func() {
+		defer release()
+
 		handleSourceAdd := func(source logs.LogSource) {
 			ev := &model.LogSourceWatchEvent{
 				Type:   watch.Added,
@@ -1070,6 +1473,7 @@ func (r *subscriptionResolver) LogSourcesWatch(ctx context.Context, kubeContext
 			select {
 			case <-ctx.Done():
 			case outCh <- ev:
+				ae.AddRecords(1)
 			}
 		}
 
@@ -1082,6 +1486,7 @@ func (r *subscriptionResolver) LogSourcesWatch(ctx context.Context, kubeContext
 			select {
 			case <-ctx.Done():
 			case outCh <- ev:
+				ae.AddRecords(1)
 			}
 		}
 
@@ -1091,6 +1496,7 @@ func (r *subscriptionResolver) LogSourcesWatch(ctx context.Context, kubeContext
 
 		// Start source watcher
 		if err := sw.Start(ctx); err != nil {
+			ae.Finish(err)
 			transport.AddSubscriptionError(ctx, gqlerrors.ErrInternalServerError)
 			close(outCh)
 			sw.Close()
@@ -1105,6 +1511,7 @@ func (r *subscriptionResolver) LogSourcesWatch(ctx context.Context, kubeContext
 
 		// Wait for client close
 		<-ctx.Done()
+		ae.Finish(nil)
 	}()
 
 	return outCh, nil
@@ -1140,6 +1547,11 @@ func (r *Resolver) BatchV1JobsWatchEvent() BatchV1JobsWatchEventResolver {
 	return &batchV1JobsWatchEventResolver{r}
 }
 
+// CoreV1EventsWatchEvent returns CoreV1EventsWatchEventResolver implementation.
+func (r *Resolver) CoreV1EventsWatchEvent() CoreV1EventsWatchEventResolver {
+	return &coreV1EventsWatchEventResolver{r}
+}
+
 // CoreV1NamespacesWatchEvent returns CoreV1NamespacesWatchEventResolver implementation.
 func (r *Resolver) CoreV1NamespacesWatchEvent() CoreV1NamespacesWatchEventResolver {
 	return &coreV1NamespacesWatchEventResolver{r}
@@ -1166,6 +1578,9 @@ func (r *Resolver) KubeConfig() KubeConfigResolver { return &kubeConfigResolver{
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
+// Permissions returns PermissionsResolver implementation.
+func (r *Resolver) Permissions() PermissionsResolver { return &permissionsResolver{r} }
+
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
@@ -1178,11 +1593,13 @@ type appsV1ReplicaSetsWatchEventResolver struct{ *Resolver }
 type appsV1StatefulSetsWatchEventResolver struct{ *Resolver }
 type batchV1CronJobsWatchEventResolver struct{ *Resolver }
 type batchV1JobsWatchEventResolver struct{ *Resolver }
+type coreV1EventsWatchEventResolver struct{ *Resolver }
 type coreV1NamespacesWatchEventResolver struct{ *Resolver }
 type coreV1NodesWatchEventResolver struct{ *Resolver }
 type coreV1PodsWatchEventResolver struct{ *Resolver }
 type coreV1ServicesWatchEventResolver struct{ *Resolver }
 type kubeConfigResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
+type permissionsResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
 type subscriptionResolver struct{ *Resolver }
@@ -15,16 +15,33 @@
 package graph
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	authv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	ktesting "k8s.io/client-go/testing"
 	"k8s.io/utils/ptr"
 
+	"github.com/kubetail-org/kubetail/modules/shared/audit"
 	"github.com/kubetail-org/kubetail/modules/shared/config"
 	"github.com/kubetail-org/kubetail/modules/shared/graphql/errors"
+	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
 	k8shelpersmock "github.com/kubetail-org/kubetail/modules/shared/k8shelpers/mock"
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+
+	"github.com/kubetail-org/kubetail/modules/dashboard/graph/model"
+	"github.com/kubetail-org/kubetail/modules/dashboard/internal/store"
 )
 
 func TestAllowedNamespacesGetQueries(t *testing.T) {
@@ -35,7 +52,7 @@ func TestAllowedNamespacesGetQueries(t *testing.T) {
 
 	// Init resolver
 	r := &queryResolver{&Resolver{
-		allowedNamespaces: []string{"ns1", "ns2"},
+		allowedNamespaces: func() []string { return []string{"ns1", "ns2"} },
 		cm:                cm,
 	}}
 
@@ -78,6 +95,10 @@ func TestAllowedNamespacesGetQueries(t *testing.T) {
 			_, err = r.CoreV1PodsGet(context.Background(), nil, tt.setNamespace, "", nil)
 			assert.NotNil(t, err)
 			assert.Equal(t, err, errors.ErrForbidden)
+
+			_, err = r.Permissions(context.Background(), nil, tt.setNamespace)
+			assert.NotNil(t, err)
+			assert.Equal(t, err, errors.ErrForbidden)
 		})
 	}
 }
@@ -90,7 +111,7 @@ func TestAllowedNamespacesListQueries(t *testing.T) {
 
 	// Init resolver
 	r := &queryResolver{&Resolver{
-		allowedNamespaces: []string{"ns1", "ns2"},
+		allowedNamespaces: func() []string { return []string{"ns1", "ns2"} },
 		cm:                cm,
 	}}
 
@@ -130,13 +151,95 @@ func TestAllowedNamespacesListQueries(t *testing.T) {
 			assert.NotNil(t, err)
 			assert.Equal(t, err, errors.ErrForbidden)
 
+			_, err = r.CoreV1EventsList(context.Background(), nil, tt.setNamespace, nil)
+			assert.NotNil(t, err)
+			assert.Equal(t, err, errors.ErrForbidden)
+
 			_, err = r.CoreV1PodsList(context.Background(), nil, tt.setNamespace, nil)
 			assert.NotNil(t, err)
 			assert.Equal(t, err, errors.ErrForbidden)
+
+			_, err = r.LogUsageSummary(context.Background(), nil, tt.setNamespace, nil)
+			assert.NotNil(t, err)
+			assert.Equal(t, err, errors.ErrForbidden)
 		})
 	}
 }
 
+// Represents fake cluster-api client
+type fakeClusterAPIClient struct {
+	kubeContext string
+	namespace   *string
+	groupBy     logs.UsageGroupBy
+	groups      []logs.UsageGroup
+}
+
+func (c *fakeClusterAPIClient) LogUsageSummary(ctx context.Context, kubeContext string, namespace *string, groupBy logs.UsageGroupBy) ([]logs.UsageGroup, error) {
+	c.kubeContext, c.namespace, c.groupBy = kubeContext, namespace, groupBy
+	return c.groups, nil
+}
+
+func (c *fakeClusterAPIClient) Shutdown() {}
+
+func TestLogUsageSummaryPassthrough(t *testing.T) {
+	cm := &k8shelpersmock.MockConnectionManager{}
+	cm.On("GetDefaultNamespace", "ctx1").Return("ns1")
+	cm.On("DerefKubeContext", mock.Anything).Return("ctx1")
+
+	client := &fakeClusterAPIClient{
+		groups: []logs.UsageGroup{{Namespace: "ns1", WorkloadKind: "Deployment", WorkloadName: "web", Bytes: 100, FileCount: 1}},
+	}
+
+	r := &queryResolver{&Resolver{cm: cm, clusterAPIClient: client}}
+
+	groups, err := r.LogUsageSummary(context.Background(), ptr.To("ctx1"), nil, nil)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, "web", groups[0].WorkloadName)
+
+	assert.Equal(t, "ctx1", client.kubeContext)
+	assert.Equal(t, ptr.To("ns1"), client.namespace)
+	assert.Equal(t, logs.UsageGroupByWorkload, client.groupBy)
+}
+
+func TestPermissions(t *testing.T) {
+	rules := []authorizationv1.ResourceRule{
+		{Verbs: []string{"get", "list", "watch"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+		{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods/log"}},
+	}
+
+	cm := &k8shelpersmock.MockConnectionManager{}
+	cm.On("GetDefaultNamespace", "ctx1").Return("ns1")
+	cm.On("DerefKubeContext", mock.Anything).Return("ctx1")
+	cm.On("GetPermissions", mock.Anything, "ctx1", "my-token", "ns1").Return(&k8shelpers.Permissions{Namespace: "ns1", Rules: rules}, nil)
+
+	r := &Resolver{cm: cm}
+
+	t.Run("uses default namespace and bearer token", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), k8shelpers.K8STokenCtxKey, "my-token")
+
+		p, err := r.Query().Permissions(ctx, ptr.To("ctx1"), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "ns1", p.Namespace)
+		assert.Equal(t, rules, p.Rules)
+
+		canReadLogs, err := r.Permissions().CanReadLogs(ctx, p)
+		require.NoError(t, err)
+		assert.True(t, canReadLogs)
+	})
+
+	t.Run("all namespaces is invalid", func(t *testing.T) {
+		_, err := r.Query().Permissions(context.Background(), ptr.To("ctx1"), ptr.To(""))
+		assert.Error(t, err)
+	})
+
+	t.Run("canReadLogs requires pods/log", func(t *testing.T) {
+		canReadLogs, err := r.Permissions().CanReadLogs(context.Background(), &k8shelpers.Permissions{Rules: rules[:1]})
+		require.NoError(t, err)
+		assert.False(t, canReadLogs)
+	})
+}
+
 func TestDesktopOnlyRequests(t *testing.T) {
 	cm := &k8shelpersmock.MockConnectionManager{}
 	cm.On("DerefKubeContext", mock.Anything).Return("")
@@ -174,3 +277,238 @@ func TestDesktopOnlyRequests(t *testing.T) {
 		assert.Equal(t, err, errors.ErrForbidden)
 	})
 }
+
+func TestSavedSearchesOwnerScoping(t *testing.T) {
+	// Init clientset that maps tokens to usernames
+	var tokenReviews atomic.Int32
+	clientset := fake.NewSimpleClientset()
+	clientset.Fake.PrependReactor("create", "tokenreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		tokenReviews.Add(1)
+		obj := action.(ktesting.CreateAction).GetObject().(*authv1.TokenReview)
+		switch obj.Spec.Token {
+		case "alice-token":
+			obj.Status = authv1.TokenReviewStatus{Authenticated: true, User: authv1.UserInfo{Username: "alice"}}
+		case "bob-token":
+			obj.Status = authv1.TokenReviewStatus{Authenticated: true, User: authv1.UserInfo{Username: "bob"}}
+		}
+		return true, obj, nil
+	})
+
+	cm := &k8shelpersmock.MockConnectionManager{}
+	cm.On("GetOrCreateClientset", "").Return(clientset, nil)
+
+	st, err := store.Open("")
+	require.NoError(t, err)
+
+	newResolver := func(authMode config.AuthMode) *Resolver {
+		cfg := config.DefaultConfig()
+		cfg.Dashboard.AuthMode = authMode
+		return &Resolver{config: cfg, cm: cm, store: st}
+	}
+
+	input := model.SavedSearchInput{
+		Name:    "web errors",
+		Sources: []string{"deployments/web"},
+		Since:   ptr.To("PT1H"),
+	}
+
+	t.Run("token mode", func(t *testing.T) {
+		resolver := newResolver(config.AuthModeToken)
+		aliceCtx := context.WithValue(context.Background(), k8shelpers.K8STokenCtxKey, "alice-token")
+		bobCtx := context.WithValue(context.Background(), k8shelpers.K8STokenCtxKey, "bob-token")
+
+		created, err := (&mutationResolver{resolver}).SavedSearchesCreate(aliceCtx, input)
+		require.NoError(t, err)
+		assert.Equal(t, "PT1H", created.Since)
+
+		list, err := (&queryResolver{resolver}).SavedSearchesList(aliceCtx)
+		require.NoError(t, err)
+		assert.Len(t, list, 1)
+
+		list, err = (&queryResolver{resolver}).SavedSearchesList(bobCtx)
+		require.NoError(t, err)
+		assert.Empty(t, list)
+
+		_, err = (&queryResolver{resolver}).SavedSearchesGet(bobCtx, created.ID)
+		assert.Equal(t, errors.ErrRecordNotFound, err)
+
+		// Invalid token
+		badCtx := context.WithValue(context.Background(), k8shelpers.K8STokenCtxKey, "bad-token")
+		_, err = (&queryResolver{resolver}).SavedSearchesList(badCtx)
+		assert.Equal(t, errors.ErrUnauthenticated, err)
+
+		// Missing token
+		_, err = (&queryResolver{resolver}).SavedSearchesList(context.Background())
+		assert.Equal(t, errors.ErrUnauthenticated, err)
+
+		// Successful lookups are cached
+		n := tokenReviews.Load()
+		_, err = (&queryResolver{resolver}).SavedSearchesList(aliceCtx)
+		require.NoError(t, err)
+		assert.Equal(t, n, tokenReviews.Load())
+	})
+
+	t.Run("oidc mode with impersonation", func(t *testing.T) {
+		resolver := newResolver(config.AuthModeOIDC)
+		aliceCtx := context.WithValue(context.Background(), k8shelpers.ImpersonateCtxKey, &rest.ImpersonationConfig{UserName: "alice"})
+		carolCtx := context.WithValue(context.Background(), k8shelpers.ImpersonateCtxKey, &rest.ImpersonationConfig{UserName: "carol"})
+
+		// Records created with the token above belong to the same user
+		list, err := (&queryResolver{resolver}).SavedSearchesList(aliceCtx)
+		require.NoError(t, err)
+		assert.Len(t, list, 1)
+
+		list, err = (&queryResolver{resolver}).SavedSearchesList(carolCtx)
+		require.NoError(t, err)
+		assert.Empty(t, list)
+
+		// Missing identity
+		_, err = (&queryResolver{resolver}).SavedSearchesList(context.Background())
+		assert.Equal(t, errors.ErrUnauthenticated, err)
+	})
+
+	t.Run("auto mode", func(t *testing.T) {
+		resolver := newResolver(config.AuthModeAuto)
+
+		created, err := (&mutationResolver{resolver}).SavedSearchesCreate(context.Background(), input)
+		require.NoError(t, err)
+
+		list, err := (&queryResolver{resolver}).SavedSearchesList(context.Background())
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, created.ID, list[0].ID)
+	})
+
+	t.Run("invalid time range", func(t *testing.T) {
+		resolver := newResolver(config.AuthModeAuto)
+
+		badInput := input
+		badInput.Until = ptr.To("yesterday")
+
+		_, err := (&mutationResolver{resolver}).SavedSearchesCreate(context.Background(), badInput)
+		assert.Error(t, err)
+	})
+}
+
+func TestPermalinks(t *testing.T) {
+	newResolver := func(secret string) *Resolver {
+		cfg := config.DefaultConfig()
+		cfg.Dashboard.Session.Secret = secret
+		return &Resolver{config: cfg}
+	}
+
+	input := model.PermalinkInput{
+		KubeContext:  ptr.To("prod"),
+		Sources:      []string{"default:deployments/web"},
+		SourceFilter: &model.LogSourceFilter{Region: []string{"us-east-1"}},
+		Grep:         ptr.To("error"),
+		Mode:         ptr.To(model.LogRecordsQueryModeHead),
+		Since:        ptr.To("PT30M"),
+		Highlight: &model.PermalinkHighlightInput{
+			Timestamp:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			ContainerID: ptr.To("abc123"),
+		},
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		resolver := newResolver("TESTSECRET")
+
+		link, err := (&mutationResolver{resolver}).PermalinksCreate(context.Background(), input, ptr.To("PT1H"))
+		require.NoError(t, err)
+		require.NotNil(t, link.ExpiresAt)
+
+		// Resolve token directly and from dashboard url
+		for _, token := range []string{link.Token, "https://kubetail.example.com/console?link=" + link.Token} {
+			state, err := (&queryResolver{resolver}).PermalinksGet(context.Background(), token)
+			require.NoError(t, err)
+			assert.Equal(t, "prod", state.KubeContext)
+			assert.Equal(t, []string{"default:deployments/web"}, state.Sources)
+			assert.Equal(t, []string{"us-east-1"}, state.SourceFilter.Region)
+			assert.Equal(t, "error", state.Grep)
+			assert.Equal(t, model.LogRecordsQueryModeHead, *state.Mode)
+			assert.Equal(t, "PT30M", state.Since)
+			assert.Equal(t, "abc123", state.Highlight.ContainerID)
+			assert.Equal(t, link.ExpiresAt.Unix(), state.ExpiresAt.Unix())
+		}
+	})
+
+	t.Run("signed with different secret", func(t *testing.T) {
+		link, err := (&mutationResolver{newResolver("SECRET1")}).PermalinksCreate(context.Background(), input, nil)
+		require.NoError(t, err)
+		assert.Nil(t, link.ExpiresAt)
+
+		_, err = (&queryResolver{newResolver("SECRET2")}).PermalinksGet(context.Background(), link.Token)
+		assert.Equal(t, errInvalidPermalink, err)
+	})
+
+	t.Run("no secret", func(t *testing.T) {
+		_, err := (&mutationResolver{newResolver("")}).PermalinksCreate(context.Background(), input, nil)
+		assert.Equal(t, errPermalinksDisabled, err)
+	})
+
+	t.Run("invalid expiresIn", func(t *testing.T) {
+		_, err := (&mutationResolver{newResolver("TESTSECRET")}).PermalinksCreate(context.Background(), input, ptr.To("tomorrow"))
+		assert.Error(t, err)
+	})
+}
+
+func TestLogRecordsFetchAudit(t *testing.T) {
+	cm := &k8shelpersmock.MockConnectionManager{}
+	cm.On("DerefKubeContext", mock.Anything).Return("minikube")
+
+	var buf bytes.Buffer
+	identify := func(ctx context.Context) (string, []string) {
+		return "alice", nil
+	}
+
+	r := &queryResolver{&Resolver{
+		cm:    cm,
+		audit: audit.NewLogger("dashboard", audit.NewWriterSink(&buf), identify),
+	}}
+
+	// Invalid mode fails before the stream is created
+	mode := model.LogRecordsQueryMode("XXX")
+	_, err := r.LogRecordsFetch(context.Background(), nil, []string{"default:pods/web"}, &mode, ptr.To("PT1H"), nil, nil, nil, ptr.To("error"), &model.LogSourceFilter{Node: []string{"node-1"}}, nil, nil)
+	require.Error(t, err)
+
+	var ev audit.Event
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &ev))
+	assert.Equal(t, "dashboard", ev.App)
+	assert.Equal(t, "logRecordsFetch", ev.Operation)
+	assert.Equal(t, "alice", ev.User)
+	assert.Equal(t, "minikube", ev.KubeContext)
+	assert.Equal(t, []string{"default:pods/web"}, ev.Sources)
+	assert.Equal(t, []string{"node-1"}, ev.SourceFilter.Node)
+	assert.Equal(t, "error", ev.Grep)
+	assert.False(t, ev.Since.IsZero())
+	assert.Equal(t, audit.OutcomeError, ev.Outcome)
+}
+
+func TestLogRecordsRejectedAudit(t *testing.T) {
+	cm := &k8shelpersmock.MockConnectionManager{}
+	cm.On("DerefKubeContext", mock.Anything).Return("minikube")
+
+	var buf bytes.Buffer
+	r := &Resolver{
+		cm:    cm,
+		audit: audit.NewLogger("dashboard", audit.NewWriterSink(&buf), nil),
+	}
+
+	// Invalid time args are audited
+	_, err := (&queryResolver{r}).LogRecordsFetch(context.Background(), nil, []string{"default:pods/web"}, nil, ptr.To("xxx"), nil, nil, nil, nil, nil, nil, nil)
+	require.Error(t, err)
+
+	var ev audit.Event
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &ev))
+	assert.Equal(t, "logRecordsFetch", ev.Operation)
+	assert.Equal(t, "minikube", ev.KubeContext)
+	assert.Equal(t, audit.OutcomeError, ev.Outcome)
+
+	buf.Reset()
+	_, err = (&subscriptionResolver{r}).LogRecordsFollow(context.Background(), nil, []string{"default:pods/web"}, ptr.To("xxx"), nil, nil, nil, nil, nil)
+	require.Error(t, err)
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &ev))
+	assert.Equal(t, "logRecordsFollow", ev.Operation)
+	assert.Equal(t, audit.OutcomeError, ev.Outcome)
+}
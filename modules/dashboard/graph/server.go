@@ -25,13 +25,22 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler/lru"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/gorilla/websocket"
+	zlog "github.com/rs/zerolog/log"
 	"github.com/vektah/gqlparser/v2/ast"
 
+	"github.com/kubetail-org/kubetail/modules/shared/audit"
+	clusterapiclient "github.com/kubetail-org/kubetail/modules/shared/clusterapi"
 	"github.com/kubetail-org/kubetail/modules/shared/config"
 	"github.com/kubetail-org/kubetail/modules/shared/graphql/directives"
+	"github.com/kubetail-org/kubetail/modules/shared/graphql/limits"
 	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/metrics"
+	"github.com/kubetail-org/kubetail/modules/shared/otel"
+	"github.com/kubetail-org/kubetail/modules/shared/quota"
 
+	"github.com/kubetail-org/kubetail/modules/dashboard/graph/model"
 	clusterapi "github.com/kubetail-org/kubetail/modules/dashboard/internal/cluster-api"
+	"github.com/kubetail-org/kubetail/modules/dashboard/internal/store"
 )
 
 // Represents Server
@@ -46,24 +55,38 @@ type Server struct {
 // It's defined at the package level to avoid re-allocation on every WebSocket upgrade request.
 var allowedSecFetchSite = []string{"same-origin"}
 
-// Create new Server instance
-func NewServer(config *config.Config, cm k8shelpers.ConnectionManager) *Server {
+// Create new Server instance. Allowed namespaces and CSRF settings are read
+// from `live` on every request so they can be reloaded at runtime.
+func NewServer(live *config.Live, cm k8shelpers.ConnectionManager, st *store.Store, auditLogger *audit.Logger, quotas *quota.Limiter) *Server {
+	config := live.Load()
+
 	// Init health monitor
 	hm := clusterapi.NewHealthMonitor(config, cm)
 
+	// Init cluster-api client
+	clusterAPIClient, err := clusterapiclient.NewClient(config, cm)
+	if err != nil {
+		zlog.Warn().Err(err).Msg("Cluster API client unavailable")
+	}
+
 	// Init resolver
 	r := &Resolver{
 		config:            config,
 		cm:                cm,
 		hm:                hm,
+		clusterAPIClient:  clusterAPIClient,
+		store:             st,
+		audit:             auditLogger,
+		quotas:            quotas,
 		environment:       config.Dashboard.Environment,
-		allowedNamespaces: config.AllowedNamespaces,
+		allowedNamespaces: live.AllowedNamespaces,
 	}
 
 	// Init config
 	cfg := Config{Resolvers: r}
 	cfg.Directives.Validate = directives.ValidateDirective
 	cfg.Directives.NullIfValidationFailed = directives.NullIfValidationFailedDirective
+	setComplexity(&cfg, live.AllowedNamespaces)
 
 	// Init schema
 	schema := NewExecutableSchema(cfg)
@@ -84,7 +107,7 @@ func NewServer(config *config.Config, cm k8shelpers.ConnectionManager) *Server {
 		Upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				// Allow all if CSRF protection is disabled
-				if !config.Dashboard.CSRF.Enabled {
+				if !live.Load().Dashboard.CSRF.Enabled {
 					return true
 				}
 
@@ -120,6 +143,13 @@ func NewServer(config *config.Config, cm k8shelpers.ConnectionManager) *Server {
 	})
 
 	h.Use(extension.Introspection{})
+	h.Use(metrics.SubscriptionTracker{})
+	h.Use(otel.GraphQLTracer{})
+	h.Use(limits.New(limits.Limits{
+		MaxComplexity: config.Dashboard.GraphQL.MaxComplexity,
+		MaxDepth:      config.Dashboard.GraphQL.MaxDepth,
+		MaxAliases:    config.Dashboard.GraphQL.MaxAliases,
+	}))
 	h.Use(extension.AutomaticPersistedQuery{
 		Cache: lru.New[string](100),
 	})
@@ -127,12 +157,50 @@ func NewServer(config *config.Config, cm k8shelpers.ConnectionManager) *Server {
 	return &Server{r, h, hm, shutdownCh}
 }
 
+// Set complexity functions for fields that are expensive to resolve
+func setComplexity(cfg *Config, allowedNamespaces func() []string) {
+	namespacedList := limits.NamespacedListComplexity(allowedNamespaces)
+
+	cfg.Complexity.Query.AppsV1DaemonSetsList = namespacedList
+	cfg.Complexity.Query.AppsV1DeploymentsList = namespacedList
+	cfg.Complexity.Query.AppsV1ReplicaSetsList = namespacedList
+	cfg.Complexity.Query.AppsV1StatefulSetsList = namespacedList
+	cfg.Complexity.Query.BatchV1CronJobsList = namespacedList
+	cfg.Complexity.Query.BatchV1JobsList = namespacedList
+	cfg.Complexity.Query.CoreV1EventsList = namespacedList
+	cfg.Complexity.Query.CoreV1PodsList = namespacedList
+	cfg.Complexity.Query.CoreV1ServicesList = namespacedList
+	cfg.Complexity.Query.ClusterAPIServicesList = limits.ClusterListComplexity
+	cfg.Complexity.Query.CoreV1NamespacesList = limits.ClusterListComplexity
+	cfg.Complexity.Query.CoreV1NodesList = limits.ClusterListComplexity
+
+	cfg.Complexity.Subscription.AppsV1DaemonSetsWatch = namespacedList
+	cfg.Complexity.Subscription.AppsV1DeploymentsWatch = namespacedList
+	cfg.Complexity.Subscription.AppsV1ReplicaSetsWatch = namespacedList
+	cfg.Complexity.Subscription.AppsV1StatefulSetsWatch = namespacedList
+	cfg.Complexity.Subscription.BatchV1CronJobsWatch = namespacedList
+	cfg.Complexity.Subscription.BatchV1JobsWatch = namespacedList
+	cfg.Complexity.Subscription.CoreV1EventsWatch = namespacedList
+	cfg.Complexity.Subscription.CoreV1PodsWatch = namespacedList
+	cfg.Complexity.Subscription.CoreV1ServicesWatch = namespacedList
+	cfg.Complexity.Subscription.ClusterAPIServicesWatch = limits.ClusterListComplexity
+	cfg.Complexity.Subscription.CoreV1NamespacesWatch = limits.ClusterListComplexity
+	cfg.Complexity.Subscription.CoreV1NodesWatch = limits.ClusterListComplexity
+
+	cfg.Complexity.Query.LogRecordsFetch = func(childComplexity int, kubeContext *string, sources []string, mode *model.LogRecordsQueryMode, since *string, until *string, after *string, before *string, grep *string, sourceFilter *model.LogSourceFilter, servingOnly *bool, limit *int) int {
+		return limits.LogRecordsFetchComplexity(childComplexity, limit)
+	}
+}
+
 // Shutdown
 func (s *Server) Shutdown() {
 	close(s.shutdownCh)
 	if s.hm != nil {
 		s.hm.Shutdown()
 	}
+	if s.r.clusterAPIClient != nil {
+		s.r.clusterAPIClient.Shutdown()
+	}
 }
 
 // ServeHTTP
@@ -77,7 +77,7 @@ func TestServer(t *testing.T) {
 			cfg.Dashboard.Environment = config.EnvironmentCluster
 			cfg.Dashboard.CSRF.Enabled = tt.setCsrfEnabled
 
-			graphqlServer := NewServer(cfg, nil)
+			graphqlServer := NewServer(config.NewLive(cfg), nil, nil, nil, nil)
 
 			client := testutils.NewWebTestClient(t, graphqlServer)
 			defer client.Teardown()
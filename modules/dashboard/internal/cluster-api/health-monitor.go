@@ -32,6 +32,7 @@ import (
 
 	"github.com/kubetail-org/kubetail/modules/shared/config"
 	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/metrics"
 	"github.com/kubetail-org/kubetail/modules/shared/util"
 )
 
@@ -46,6 +47,14 @@ const (
 	HealthStatusUknown   = "UNKNOWN"
 )
 
+var allHealthStatuses = []HealthStatus{
+	HealthStatusSuccess,
+	HealthStatusFailure,
+	HealthStatusPending,
+	HealthStatusNotFound,
+	HealthStatusUknown,
+}
+
 // Represents HealthMonitor
 type HealthMonitor interface {
 	Shutdown()
@@ -154,7 +163,7 @@ func (hm *DesktopHealthMonitor) getOrCreateWorker(ctx context.Context, kubeConte
 			worker = newNoopHealthMonitorWorker()
 		} else {
 			// EndpointSlices supported, initialize EndpointSlicesHealthMonitor
-			worker, err = newEndpointSlicesHealthMonitorWorker(clientset, namespace, serviceName)
+			worker, err = newEndpointSlicesHealthMonitorWorker(clientset, kubeContext, namespace, serviceName)
 			if err != nil {
 				return nil, err
 			}
@@ -257,7 +266,7 @@ func (hm *InClusterHealthMonitor) getOrCreateWorker(ctx context.Context) (health
 			worker = newNoopHealthMonitorWorker()
 		} else {
 			// EndpointSlices supported, initialize EndpointSlicesHealthMonitor
-			worker, err = newEndpointSlicesHealthMonitorWorker(clientset, connectArgs.Namespace, connectArgs.ServiceName)
+			worker, err = newEndpointSlicesHealthMonitorWorker(clientset, "", connectArgs.Namespace, connectArgs.ServiceName)
 			if err != nil {
 				return nil, err
 			}
@@ -287,17 +296,18 @@ type healthMonitorWorker interface {
 
 // Represents endpointSlicesHealthMonitorWorker
 type endpointSlicesHealthMonitorWorker struct {
-	lastStatus HealthStatus
-	factory    informers.SharedInformerFactory
-	informer   cache.SharedIndexInformer
-	esCache    map[string]*discoveryv1.EndpointSlice
-	eventbus   evbus.Bus
-	shutdownCh chan struct{}
-	mu         sync.RWMutex
+	lastStatus   HealthStatus
+	metricLabels []string
+	factory      informers.SharedInformerFactory
+	informer     cache.SharedIndexInformer
+	esCache      map[string]*discoveryv1.EndpointSlice
+	eventbus     evbus.Bus
+	shutdownCh   chan struct{}
+	mu           sync.RWMutex
 }
 
 // Create new endpointSlicesHealthMonitorWorker instance
-func newEndpointSlicesHealthMonitorWorker(clientset kubernetes.Interface, namespace string, serviceName string) (*endpointSlicesHealthMonitorWorker, error) {
+func newEndpointSlicesHealthMonitorWorker(clientset kubernetes.Interface, kubeContext string, namespace string, serviceName string) (*endpointSlicesHealthMonitorWorker, error) {
 	// Init factory
 	labelSelector := labels.Set{
 		discoveryv1.LabelServiceName: serviceName,
@@ -312,12 +322,13 @@ func newEndpointSlicesHealthMonitorWorker(clientset kubernetes.Interface, namesp
 
 	// Initialize instance
 	w := &endpointSlicesHealthMonitorWorker{
-		lastStatus: HealthStatusUknown,
-		factory:    factory,
-		informer:   informer,
-		esCache:    make(map[string]*discoveryv1.EndpointSlice),
-		eventbus:   evbus.New(),
-		shutdownCh: make(chan struct{}),
+		lastStatus:   HealthStatusUknown,
+		metricLabels: []string{kubeContext, namespace, serviceName},
+		factory:      factory,
+		informer:     informer,
+		esCache:      make(map[string]*discoveryv1.EndpointSlice),
+		eventbus:     evbus.New(),
+		shutdownCh:   make(chan struct{}),
 	}
 
 	// Register event handlers
@@ -367,6 +378,11 @@ func (w *endpointSlicesHealthMonitorWorker) Start(ctx context.Context) error {
 func (w *endpointSlicesHealthMonitorWorker) Shutdown() {
 	close(w.shutdownCh)
 	w.factory.Shutdown()
+
+	// Remove metrics
+	for _, status := range allHealthStatuses {
+		metrics.HealthStatus.DeleteLabelValues(append(w.metricLabels, string(status))...)
+	}
 }
 
 // GetHealthStatus
@@ -496,6 +512,15 @@ func (w *endpointSlicesHealthMonitorWorker) updateHealthStatus_UNSAFE() {
 		w.lastStatus = newStatus
 		w.eventbus.Publish("UPDATE", newStatus)
 	}
+
+	// Update metrics
+	for _, status := range allHealthStatuses {
+		val := 0.0
+		if status == newStatus {
+			val = 1
+		}
+		metrics.HealthStatus.WithLabelValues(append(w.metricLabels, string(status))...).Set(val)
+	}
 }
 
 // Represents noopHealthMonitorWorker
@@ -15,7 +15,7 @@
 package clusterapi
 
 import (
-	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -26,8 +26,11 @@ import (
 	"strings"
 	"sync"
 
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"k8s.io/client-go/rest"
 	"k8s.io/kubectl/pkg/proxy"
 
+	clusterapiclient "github.com/kubetail-org/kubetail/modules/shared/clusterapi"
 	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
 )
 
@@ -47,10 +50,10 @@ type Proxy interface {
 type DesktopProxy struct {
 	cm         k8shelpers.ConnectionManager
 	pathPrefix string
+	useTLS     bool
 	phCache    map[string]http.Handler
-	satCache   map[string]*k8shelpers.ServiceAccountToken
+	tokens     *clusterapiclient.ServiceAccountTokenCache
 	mu         sync.Mutex
-	shutdownCh chan struct{}
 }
 
 // ServeHTTP
@@ -76,7 +79,7 @@ func (p *DesktopProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Re-write url
-	newPath := path.Join("/api/v1/namespaces", namespace, "services", fmt.Sprintf("%s:http", serviceName), "proxy", relPath)
+	newPath := path.Join("/api/v1/namespaces", namespace, "services", clusterapiclient.ServiceProxyName(serviceName, p.useTLS), "proxy", relPath)
 	if strings.HasSuffix(newPath, "/proxy") {
 		newPath += "/"
 	}
@@ -84,20 +87,21 @@ func (p *DesktopProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	u.Path = newPath
 	r.URL = &u
 
-	// Get service-account-token
-	sat, err := p.getOrCreateServiceAccountToken(r.Context(), kubeContext, namespace)
+	// Add service-account-token to authentication header
+	token, err := p.tokens.Token(r.Context(), kubeContext, namespace)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	r.Header.Add("X-Forwarded-Authorization", fmt.Sprintf("Bearer %s", token))
 
-	// Add token to authentication header
-	token, err := sat.Token(r.Context())
+	// Forward identity (replaces any headers sent by the client)
+	restConfig, err := p.cm.GetOrCreateRestConfig(kubeContext)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	r.Header.Add("X-Forwarded-Authorization", fmt.Sprintf("Bearer %s", token))
+	k8shelpers.SetForwardedImpersonationHeaders(r.Header, k8shelpers.EffectiveImpersonation(r.Context(), restConfig.Impersonate))
 
 	// Passthrough if upgrade request
 	if r.Header.Get("Upgrade") != "" {
@@ -129,7 +133,7 @@ func (p *DesktopProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 // Shutdown
 func (p *DesktopProxy) Shutdown() {
-	close(p.shutdownCh)
+	p.tokens.Shutdown()
 }
 
 // Get or create Kubernetes proxy handler
@@ -159,43 +163,15 @@ func (p *DesktopProxy) getOrCreateKubernetesProxyHandler(kubeContext string) (ht
 	return h, nil
 }
 
-// Get or create service-account-token
-func (p *DesktopProxy) getOrCreateServiceAccountToken(ctx context.Context, kubeContext string, namespace string) (*k8shelpers.ServiceAccountToken, error) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
-	// Generate cache key
-	k := fmt.Sprintf("%s/%s", kubeContext, namespace)
-
-	// Check cache
-	sat, exists := p.satCache[k]
-	if !exists {
-		clientset, err := p.cm.GetOrCreateClientset(kubeContext)
-		if err != nil {
-			return nil, err
-		}
-
-		// Initialize new service-account-token
-		sat, err = k8shelpers.NewServiceAccountToken(ctx, clientset, namespace, "kubetail-cli", p.shutdownCh)
-		if err != nil {
-			return nil, err
-		}
-
-		// Add to cache
-		p.satCache[k] = sat
-	}
-
-	return sat, nil
-}
-
-// Create new DesktopProxy
-func NewDesktopProxy(cm k8shelpers.ConnectionManager, pathPrefix string) (*DesktopProxy, error) {
+// Create new DesktopProxy. If `useTLS` is true, the Kubernetes API server
+// connects to the Cluster API service using https.
+func NewDesktopProxy(cm k8shelpers.ConnectionManager, pathPrefix string, useTLS bool) (*DesktopProxy, error) {
 	return &DesktopProxy{
 		cm:         cm,
 		pathPrefix: pathPrefix,
+		useTLS:     useTLS,
 		phCache:    make(map[string]http.Handler),
-		satCache:   make(map[string]*k8shelpers.ServiceAccountToken),
-		shutdownCh: make(chan struct{}),
+		tokens:     clusterapiclient.NewServiceAccountTokenCache(cm),
 	}, nil
 }
 
@@ -208,8 +184,10 @@ type InClusterProxy struct {
 func (p *InClusterProxy) Shutdown() {
 }
 
-// Create new InClusterProxy
-func NewInClusterProxy(clusterAPIEndpoint string, pathPrefix string) (*InClusterProxy, error) {
+// Create new InClusterProxy. If `tlsCfg` is not nil, it's used for
+// connections to the Cluster API endpoint. Requests are made on behalf of
+// the identity in the request context or `impersonate`, if set.
+func NewInClusterProxy(clusterAPIEndpoint string, pathPrefix string, tlsCfg *tls.Config, impersonate rest.ImpersonationConfig) (*InClusterProxy, error) {
 	// Parse endpoint url
 	endpointUrl, err := url.Parse(clusterAPIEndpoint)
 	if err != nil {
@@ -220,9 +198,13 @@ func NewInClusterProxy(clusterAPIEndpoint string, pathPrefix string) (*InCluster
 	reverseProxy := &httputil.ReverseProxy{
 		Director: func(r *http.Request) {
 			// Re-write url
-			targetUrl := endpointUrl
+			targetUrl := *endpointUrl
 			targetUrl.Path = path.Join("/", strings.TrimPrefix(r.URL.Path, pathPrefix))
-			r.URL = targetUrl
+			targetUrl.RawQuery = r.URL.RawQuery
+			r.URL = &targetUrl
+
+			// Forward identity (replaces any headers sent by the client)
+			k8shelpers.SetForwardedImpersonationHeaders(r.Header, k8shelpers.EffectiveImpersonation(r.Context(), impersonate))
 		},
 		ModifyResponse: func(resp *http.Response) error {
 			// Re-write cookie path
@@ -238,11 +220,11 @@ func NewInClusterProxy(clusterAPIEndpoint string, pathPrefix string) (*InCluster
 	}
 
 	// Init service account token round tripper
-	rt, err := k8shelpers.NewInClusterSATRoundTripper(http.DefaultTransport)
+	rt, err := k8shelpers.NewInClusterSATRoundTripper(clusterapiclient.NewTransport(tlsCfg))
 	if err != nil {
 		return nil, err
 	}
-	reverseProxy.Transport = rt
+	reverseProxy.Transport = otelhttp.NewTransport(rt) // propagate trace context
 
 	return &InClusterProxy{reverseProxy}, nil
 }
@@ -0,0 +1,392 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+)
+
+// Name of the database file inside the data directory
+const dbFilename = "dashboard.json"
+
+// ErrNotFound is returned when a record doesn't exist (or belongs to another owner)
+var ErrNotFound = errors.New("record not found")
+
+// Represents source filters attached to a saved search
+type SourceFilter struct {
+	Region    []string `json:"region,omitempty"`
+	Zone      []string `json:"zone,omitempty"`
+	OS        []string `json:"os,omitempty"`
+	Arch      []string `json:"arch,omitempty"`
+	Node      []string `json:"node,omitempty"`
+	Container []string `json:"container,omitempty"`
+}
+
+// Represents a saved search
+type SavedSearch struct {
+	ID          string       `json:"id"`
+	Owner       string       `json:"owner"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	KubeContext string       `json:"kubeContext,omitempty"`
+	Sources     []string     `json:"sources"`
+	Filter      SourceFilter `json:"filter"`
+	Grep        string       `json:"grep,omitempty"`
+	Since       string       `json:"since,omitempty"`
+	Until       string       `json:"until,omitempty"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	UpdatedAt   time.Time    `json:"updatedAt"`
+}
+
+// Represents a bookmarked log record
+type Bookmark struct {
+	ID            string         `json:"id"`
+	Owner         string         `json:"owner"`
+	SavedSearchID string         `json:"savedSearchID,omitempty"`
+	KubeContext   string         `json:"kubeContext,omitempty"`
+	Timestamp     time.Time      `json:"timestamp"`
+	Message       string         `json:"message"`
+	Source        logs.LogSource `json:"source"`
+	Note          string         `json:"note,omitempty"`
+	CreatedAt     time.Time      `json:"createdAt"`
+	UpdatedAt     time.Time      `json:"updatedAt"`
+}
+
+// On-disk representation of the database
+type database struct {
+	SavedSearches []*SavedSearch `json:"savedSearches"`
+	Bookmarks     []*Bookmark    `json:"bookmarks"`
+}
+
+// Represents Store
+type Store struct {
+	path string
+	db   database
+	mu   sync.RWMutex
+}
+
+// Open store backed by a file in `dataDir`. If `dataDir` is empty the
+// store is kept in memory and discarded on shutdown.
+func Open(dataDir string) (*Store, error) {
+	s := &Store{}
+
+	if dataDir == "" {
+		return s, nil
+	}
+
+	// Ensure data dir exists
+	if err := os.MkdirAll(dataDir, 0o700); err != nil {
+		return nil, err
+	}
+	s.path = filepath.Join(dataDir, dbFilename)
+
+	// Load existing data
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &s.db); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// ListSavedSearches returns the owner's saved searches, most recent first
+func (s *Store) ListSavedSearches(owner string) []*SavedSearch {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := []*SavedSearch{}
+	for _, item := range slices.Backward(s.db.SavedSearches) {
+		if item.Owner == owner {
+			out = append(out, item.clone())
+		}
+	}
+	return out
+}
+
+// GetSavedSearch returns a single saved search
+func (s *Store) GetSavedSearch(owner string, id string) (*SavedSearch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	idx := s.findSavedSearch_UNSAFE(owner, id)
+	if idx < 0 {
+		return nil, ErrNotFound
+	}
+	return s.db.SavedSearches[idx].clone(), nil
+}
+
+// CreateSavedSearch adds a new saved search and returns the stored copy
+func (s *Store) CreateSavedSearch(owner string, item SavedSearch) (*SavedSearch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+
+	item.ID = newID()
+	item.Owner = owner
+	item.CreatedAt = now
+	item.UpdatedAt = now
+
+	// Don't share slices with caller
+	stored := item.clone()
+	s.db.SavedSearches = append(s.db.SavedSearches, stored)
+
+	if err := s.flush_UNSAFE(); err != nil {
+		s.db.SavedSearches = s.db.SavedSearches[:len(s.db.SavedSearches)-1]
+		return nil, err
+	}
+
+	return stored.clone(), nil
+}
+
+// UpdateSavedSearch replaces the contents of an existing saved search
+func (s *Store) UpdateSavedSearch(owner string, id string, item SavedSearch) (*SavedSearch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := s.findSavedSearch_UNSAFE(owner, id)
+	if idx < 0 {
+		return nil, ErrNotFound
+	}
+	prev := s.db.SavedSearches[idx]
+
+	item.ID = prev.ID
+	item.Owner = prev.Owner
+	item.CreatedAt = prev.CreatedAt
+	item.UpdatedAt = time.Now().UTC()
+
+	// Don't share slices with caller
+	stored := item.clone()
+	s.db.SavedSearches[idx] = stored
+
+	if err := s.flush_UNSAFE(); err != nil {
+		s.db.SavedSearches[idx] = prev
+		return nil, err
+	}
+
+	return stored.clone(), nil
+}
+
+// DeleteSavedSearch removes a saved search. Bookmarks that referenced it are kept.
+func (s *Store) DeleteSavedSearch(owner string, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := s.findSavedSearch_UNSAFE(owner, id)
+	if idx < 0 {
+		return ErrNotFound
+	}
+
+	prev := s.db.SavedSearches
+	s.db.SavedSearches = slices.Delete(slices.Clone(prev), idx, idx+1)
+
+	if err := s.flush_UNSAFE(); err != nil {
+		s.db.SavedSearches = prev
+		return err
+	}
+
+	return nil
+}
+
+// ListBookmarks returns the owner's bookmarks ordered by record timestamp. If
+// `savedSearchID` is non-empty, only bookmarks attached to it are returned.
+func (s *Store) ListBookmarks(owner string, savedSearchID string) []*Bookmark {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := []*Bookmark{}
+	for _, item := range s.db.Bookmarks {
+		if item.Owner != owner {
+			continue
+		}
+		if savedSearchID != "" && item.SavedSearchID != savedSearchID {
+			continue
+		}
+		out = append(out, item.clone())
+	}
+
+	slices.SortStableFunc(out, func(a, b *Bookmark) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	return out
+}
+
+// CreateBookmark adds a new bookmark and returns the stored copy
+func (s *Store) CreateBookmark(owner string, item Bookmark) (*Bookmark, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Check saved search reference
+	if item.SavedSearchID != "" && s.findSavedSearch_UNSAFE(owner, item.SavedSearchID) < 0 {
+		return nil, ErrNotFound
+	}
+
+	now := time.Now().UTC()
+
+	item.ID = newID()
+	item.Owner = owner
+	item.CreatedAt = now
+	item.UpdatedAt = now
+
+	stored := item.clone()
+	s.db.Bookmarks = append(s.db.Bookmarks, stored)
+
+	if err := s.flush_UNSAFE(); err != nil {
+		s.db.Bookmarks = s.db.Bookmarks[:len(s.db.Bookmarks)-1]
+		return nil, err
+	}
+
+	return stored.clone(), nil
+}
+
+// UpdateBookmarkNote changes the note attached to a bookmark
+func (s *Store) UpdateBookmarkNote(owner string, id string, note string) (*Bookmark, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := s.findBookmark_UNSAFE(owner, id)
+	if idx < 0 {
+		return nil, ErrNotFound
+	}
+	prev := s.db.Bookmarks[idx]
+
+	item := prev.clone()
+	item.Note = note
+	item.UpdatedAt = time.Now().UTC()
+
+	s.db.Bookmarks[idx] = item
+
+	if err := s.flush_UNSAFE(); err != nil {
+		s.db.Bookmarks[idx] = prev
+		return nil, err
+	}
+
+	return item.clone(), nil
+}
+
+// DeleteBookmark removes a bookmark
+func (s *Store) DeleteBookmark(owner string, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := s.findBookmark_UNSAFE(owner, id)
+	if idx < 0 {
+		return ErrNotFound
+	}
+
+	prev := s.db.Bookmarks
+	s.db.Bookmarks = slices.Delete(slices.Clone(prev), idx, idx+1)
+
+	if err := s.flush_UNSAFE(); err != nil {
+		s.db.Bookmarks = prev
+		return err
+	}
+
+	return nil
+}
+
+// Return index of saved search or -1 if not found
+func (s *Store) findSavedSearch_UNSAFE(owner string, id string) int {
+	return slices.IndexFunc(s.db.SavedSearches, func(item *SavedSearch) bool {
+		return item.ID == id && item.Owner == owner
+	})
+}
+
+// Return index of bookmark or -1 if not found
+func (s *Store) findBookmark_UNSAFE(owner string, id string) int {
+	return slices.IndexFunc(s.db.Bookmarks, func(item *Bookmark) bool {
+		return item.ID == id && item.Owner == owner
+	})
+}
+
+// Write database to disk (atomically)
+func (s *Store) flush_UNSAFE() error {
+	if s.path == "" {
+		return nil
+	}
+
+	b, err := json.Marshal(&s.db)
+	if err != nil {
+		return err
+	}
+
+	// Write to temp file first and then rename to avoid partial writes
+	f, err := os.CreateTemp(filepath.Dir(s.path), dbFilename+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), s.path)
+}
+
+// Generate new random id
+func newID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Return deep copy so callers can't mutate stored data
+func (item *SavedSearch) clone() *SavedSearch {
+	c := *item
+	c.Sources = slices.Clone(item.Sources)
+	c.Filter = SourceFilter{
+		Region:    slices.Clone(item.Filter.Region),
+		Zone:      slices.Clone(item.Filter.Zone),
+		OS:        slices.Clone(item.Filter.OS),
+		Arch:      slices.Clone(item.Filter.Arch),
+		Node:      slices.Clone(item.Filter.Node),
+		Container: slices.Clone(item.Filter.Container),
+	}
+	return &c
+}
+
+// Return copy so callers can't mutate stored data
+func (item *Bookmark) clone() *Bookmark {
+	c := *item
+	return &c
+}
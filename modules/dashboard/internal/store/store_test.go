@@ -0,0 +1,196 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+)
+
+func TestSavedSearchesCRUD(t *testing.T) {
+	s, err := Open("")
+	require.NoError(t, err)
+
+	// Create
+	created, err := s.CreateSavedSearch("user:alice", SavedSearch{
+		Name:    "web errors",
+		Sources: []string{"default:deployments/web"},
+		Filter:  SourceFilter{Region: []string{"us-east-1"}},
+		Grep:    "error",
+		Since:   "PT30M",
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, "user:alice", created.Owner)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	// Get
+	fetched, err := s.GetSavedSearch("user:alice", created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created, fetched)
+
+	// Update
+	updated, err := s.UpdateSavedSearch("user:alice", created.ID, SavedSearch{
+		Name:    "web warnings",
+		Sources: []string{"default:deployments/web"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, updated.ID)
+	assert.Equal(t, created.CreatedAt, updated.CreatedAt)
+	assert.Equal(t, "web warnings", updated.Name)
+	assert.Empty(t, updated.Grep)
+
+	// List
+	list := s.ListSavedSearches("user:alice")
+	require.Len(t, list, 1)
+	assert.Equal(t, "web warnings", list[0].Name)
+
+	// Delete
+	require.NoError(t, s.DeleteSavedSearch("user:alice", created.ID))
+	assert.Empty(t, s.ListSavedSearches("user:alice"))
+
+	_, err = s.GetSavedSearch("user:alice", created.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSavedSearchesAreCopied(t *testing.T) {
+	s, err := Open("")
+	require.NoError(t, err)
+
+	sources := []string{"default:deployments/web"}
+	created, err := s.CreateSavedSearch("user:alice", SavedSearch{
+		Name:    "web",
+		Sources: sources,
+		Filter:  SourceFilter{Region: []string{"us-east-1"}},
+	})
+	require.NoError(t, err)
+
+	// Mutating input and returned values doesn't change stored data
+	sources[0] = "mutated"
+	created.Sources[0] = "mutated"
+	created.Filter.Region[0] = "mutated"
+
+	fetched, err := s.GetSavedSearch("user:alice", created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default:deployments/web"}, fetched.Sources)
+	assert.Equal(t, []string{"us-east-1"}, fetched.Filter.Region)
+}
+
+func TestOwnerScoping(t *testing.T) {
+	s, err := Open("")
+	require.NoError(t, err)
+
+	search, err := s.CreateSavedSearch("user:alice", SavedSearch{Name: "mine", Sources: []string{"pods/x"}})
+	require.NoError(t, err)
+
+	bookmark, err := s.CreateBookmark("user:alice", Bookmark{Message: "boom", Timestamp: time.Now()})
+	require.NoError(t, err)
+
+	// Other users can't see or modify records
+	assert.Empty(t, s.ListSavedSearches("user:bob"))
+	assert.Empty(t, s.ListBookmarks("user:bob", ""))
+
+	_, err = s.GetSavedSearch("user:bob", search.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = s.UpdateSavedSearch("user:bob", search.ID, SavedSearch{Name: "theirs"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.ErrorIs(t, s.DeleteSavedSearch("user:bob", search.ID), ErrNotFound)
+	assert.ErrorIs(t, s.DeleteBookmark("user:bob", bookmark.ID), ErrNotFound)
+
+	// Bookmarks can't reference another user's saved search
+	_, err = s.CreateBookmark("user:bob", Bookmark{SavedSearchID: search.ID})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestBookmarks(t *testing.T) {
+	s, err := Open("")
+	require.NoError(t, err)
+
+	search, err := s.CreateSavedSearch("", SavedSearch{Name: "shared", Sources: []string{"pods/x"}})
+	require.NoError(t, err)
+
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	b2, err := s.CreateBookmark("", Bookmark{SavedSearchID: search.ID, Timestamp: ts.Add(time.Second), Message: "second"})
+	require.NoError(t, err)
+
+	_, err = s.CreateBookmark("", Bookmark{Timestamp: ts, Message: "first", Note: "look here"})
+	require.NoError(t, err)
+
+	// Ordered by record timestamp
+	list := s.ListBookmarks("", "")
+	require.Len(t, list, 2)
+	assert.Equal(t, "first", list[0].Message)
+	assert.Equal(t, "second", list[1].Message)
+
+	// Filter by saved search
+	list = s.ListBookmarks("", search.ID)
+	require.Len(t, list, 1)
+	assert.Equal(t, b2.ID, list[0].ID)
+
+	// Update note
+	updated, err := s.UpdateBookmarkNote("", b2.ID, "root cause")
+	require.NoError(t, err)
+	assert.Equal(t, "root cause", updated.Note)
+	assert.Equal(t, "second", updated.Message)
+
+	// Delete
+	require.NoError(t, s.DeleteBookmark("", b2.ID))
+	assert.Len(t, s.ListBookmarks("", ""), 1)
+}
+
+func TestPersistence(t *testing.T) {
+	dataDir := t.TempDir()
+
+	s1, err := Open(dataDir)
+	require.NoError(t, err)
+
+	search, err := s1.CreateSavedSearch("", SavedSearch{
+		Name:    "handoff",
+		Sources: []string{"default:deployments/web"},
+		Filter:  SourceFilter{Node: []string{"node-1"}},
+	})
+	require.NoError(t, err)
+
+	_, err = s1.CreateBookmark("", Bookmark{
+		SavedSearchID: search.ID,
+		Timestamp:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Message:       "panic: nil pointer",
+		Source:        logs.LogSource{Namespace: "default", PodName: "web-abc", ContainerName: "web"},
+		Note:          "started here",
+	})
+	require.NoError(t, err)
+
+	// Re-open and check contents
+	s2, err := Open(dataDir)
+	require.NoError(t, err)
+
+	searches := s2.ListSavedSearches("")
+	require.Len(t, searches, 1)
+	assert.Equal(t, search.ID, searches[0].ID)
+	assert.Equal(t, []string{"node-1"}, searches[0].Filter.Node)
+
+	bookmarks := s2.ListBookmarks("", search.ID)
+	require.Len(t, bookmarks, 1)
+	assert.Equal(t, "started here", bookmarks[0].Note)
+	assert.Equal(t, "web-abc", bookmarks[0].Source.PodName)
+}
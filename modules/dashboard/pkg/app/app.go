@@ -28,29 +28,54 @@ import (
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-contrib/sessions/cookie"
 	"github.com/gin-gonic/gin"
+	zlog "github.com/rs/zerolog/log"
+	"k8s.io/client-go/kubernetes"
 
+	"github.com/kubetail-org/kubetail/modules/shared/audit"
 	"github.com/kubetail-org/kubetail/modules/shared/config"
+	"github.com/kubetail-org/kubetail/modules/shared/download"
 	"github.com/kubetail-org/kubetail/modules/shared/ginhelpers"
 	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/metrics"
 	"github.com/kubetail-org/kubetail/modules/shared/middleware"
+	"github.com/kubetail-org/kubetail/modules/shared/otel"
+	"github.com/kubetail-org/kubetail/modules/shared/quota"
 
 	"github.com/kubetail-org/kubetail/modules/dashboard"
 	"github.com/kubetail-org/kubetail/modules/dashboard/graph"
 	clusterapi "github.com/kubetail-org/kubetail/modules/dashboard/internal/cluster-api"
+	"github.com/kubetail-org/kubetail/modules/dashboard/internal/store"
 )
 
 type App struct {
 	*gin.Engine
 	config          *config.Config
+	live            *config.Live
 	cm              k8shelpers.ConnectionManager
 	graphqlServer   *graph.Server
 	clusterAPIProxy clusterapi.Proxy
 	queryHelpers    queryHelpers
+	store           *store.Store
+	oidc            *oidcAuthenticator
+	auditLogger     *audit.Logger
+	quotas          *quota.Limiter
+	shutdownTracer  otel.ShutdownFunc
 
 	// for testing
 	dynamicRoutes *gin.RouterGroup
 }
 
+// ApplyConfig applies the hot-swappable fields of `cfg` (allowed namespaces,
+// csrf, quotas and logging) to the running app
+func (a *App) ApplyConfig(cfg *config.Config) {
+	a.live.Store(cfg)
+	a.quotas.SetLimits(newQuotaLimits(cfg))
+
+	if err := config.SetLogLevel(cfg.Dashboard.Logging.Level); err != nil {
+		zlog.Error().Err(err).Msg("Failed to set log level")
+	}
+}
+
 // Shutdown
 func (a *App) Shutdown(ctx context.Context) error {
 	// Shutdown GraphQL server
@@ -59,6 +84,16 @@ func (a *App) Shutdown(ctx context.Context) error {
 	// Shudown Cluster API proxy
 	a.clusterAPIProxy.Shutdown()
 
+	// Flush audit log
+	a.auditLogger.Close()
+
+	// Flush pending spans
+	if a.shutdownTracer != nil {
+		if err := a.shutdownTracer(ctx); err != nil {
+			zlog.Error().Err(err).Msg("Failed to flush spans")
+		}
+	}
+
 	// Shutdown connection manager
 	return a.cm.Shutdown(ctx)
 }
@@ -66,14 +101,24 @@ func (a *App) Shutdown(ctx context.Context) error {
 // Create new gin app
 func NewApp(cfg *config.Config) (*App, error) {
 	// Init app
-	app := &App{Engine: gin.New(), config: cfg}
+	app := &App{Engine: gin.New(), config: cfg, live: config.NewLive(cfg)}
+
+	// Init tracer
+	shutdownTracer, err := otel.InitTracer(newOTelConfig(cfg))
+	if err != nil {
+		return nil, err
+	}
+	app.shutdownTracer = shutdownTracer
 
 	// If not in test-mode
 	if gin.Mode() != gin.TestMode {
 		app.Use(gin.Recovery())
 
 		// Init connection manager
-		cm, err := k8shelpers.NewConnectionManager(cfg.Dashboard.Environment, k8shelpers.WithKubeconfigPath(cfg.KubeconfigPath))
+		cm, err := k8shelpers.NewConnectionManager(cfg.Dashboard.Environment,
+			k8shelpers.WithKubeconfigPath(cfg.KubeconfigPath),
+			k8shelpers.WithImpersonation(k8shelpers.ImpersonationFromConfig(cfg)),
+		)
 		if err != nil {
 			return nil, err
 		}
@@ -90,6 +135,39 @@ func NewApp(cfg *config.Config) (*App, error) {
 		app.queryHelpers = newRealQueryHelpers(app.cm)
 	}
 
+	// Init store
+	st, err := store.Open(cfg.Dashboard.DataDir)
+	if err != nil {
+		return nil, err
+	}
+	app.store = st
+
+	// Init identifier (shared by audit log and quotas)
+	identify := audit.NewTokenReviewIdentifier(func() (kubernetes.Interface, error) {
+		return app.cm.GetOrCreateClientset("")
+	})
+
+	// Init audit logger
+	if cfg.Dashboard.Audit.Enabled {
+		sink, err := audit.NewSink(cfg.Dashboard.Audit.Sink, cfg.Dashboard.Audit.File, cfg.Dashboard.Audit.WebhookURL)
+		if err != nil {
+			return nil, err
+		}
+		app.auditLogger = audit.NewLogger("dashboard", sink, identify)
+	}
+
+	// Init quota limiter
+	app.quotas = quota.NewReloadableLimiter(newQuotaLimits(cfg), identify)
+
+	// Init OIDC authenticator
+	if cfg.Dashboard.AuthMode == config.AuthModeOIDC {
+		oidc, err := newOIDCAuthenticator(context.Background(), cfg)
+		if err != nil {
+			return nil, err
+		}
+		app.oidc = oidc
+	}
+
 	// Register templates
 	tmpl := template.Must(template.New("").
 		Funcs(template.FuncMap{
@@ -103,13 +181,18 @@ func NewApp(cfg *config.Config) (*App, error) {
 	// Add request-id middleware
 	app.Use(requestid.New())
 
+	// Add tracing middleware
+	app.Use(middleware.TracingMiddleware())
+
 	// Add logging middleware
-	if cfg.Dashboard.Logging.AccessLog.Enabled {
-		app.Use(middleware.LoggingMiddleware(cfg.Dashboard.Logging.AccessLog.HideHealthChecks))
-	}
+	app.Use(middleware.DynamicLoggingMiddleware(func() middleware.AccessLogOptions {
+		accessLog := app.live.Load().Dashboard.Logging.AccessLog
+		return middleware.AccessLogOptions{Enabled: accessLog.Enabled, HideHealthChecks: accessLog.HideHealthChecks}
+	}))
 
 	// Add gzip middleware
 	clusterAPIProxyPath := path.Join(cfg.Dashboard.BasePath, "/cluster-api-proxy/")
+	downloadPath := path.Join(cfg.Dashboard.BasePath, "/api/logs/download")
 	app.Use(gzip.Gzip(gzip.DefaultCompression,
 		gzip.WithCustomShouldCompressFn(func(c *gin.Context) bool {
 			ae := c.GetHeader("Accept-Encoding")
@@ -121,6 +204,9 @@ func NewApp(cfg *config.Config) (*App, error) {
 			if strings.HasPrefix(requestPath, clusterAPIProxyPath) {
 				return false
 			}
+			if requestPath == downloadPath {
+				return false // compressed by handler
+			}
 			if strings.HasSuffix(requestPath, ".woff2") {
 				return false
 			}
@@ -136,7 +222,7 @@ func NewApp(cfg *config.Config) (*App, error) {
 	dynamicRoutes := root.Group("/")
 	{
 		// Add session middleware
-		sessionStore := cookie.NewStore([]byte(cfg.Dashboard.Session.Secret))
+		sessionStore := cookie.NewStore(sessionKeys(cfg.Dashboard.Session.Secret)...)
 		sessionStore.Options(sessions.Options{
 			Path:     cfg.Dashboard.Session.Cookie.Path,
 			Domain:   cfg.Dashboard.Session.Cookie.Domain,
@@ -157,6 +243,12 @@ func NewApp(cfg *config.Config) (*App, error) {
 		}))
 
 		// Add authentication middleware
+		if app.oidc != nil {
+			dynamicRoutes.Use(oidcAuthenticationMiddleware(app.oidc))
+		}
+		if cfg.Dashboard.AuthMode == config.AuthModeHeader {
+			dynamicRoutes.Use(trustedHeaderAuthenticationMiddleware(cfg))
+		}
 		dynamicRoutes.Use(authenticationMiddleware(cfg.Dashboard.AuthMode))
 
 		// Auth routes
@@ -166,6 +258,12 @@ func NewApp(cfg *config.Config) (*App, error) {
 			auth.POST("/login", h.LoginPOST)
 			auth.POST("/logout", h.LogoutPOST)
 			auth.GET("/session", h.SessionGET)
+
+			if app.oidc != nil {
+				oh := oidcHandlers{app}
+				auth.GET("/oidc/login", oh.LoginGET)
+				auth.GET("/oidc/callback", oh.CallbackGET)
+			}
 		}
 
 		// Protected routes
@@ -175,9 +273,17 @@ func NewApp(cfg *config.Config) (*App, error) {
 			protectedRoutes.Use(k8sAuthenticationMiddleware(cfg.Dashboard.AuthMode))
 
 			// GraphQL endpoint
-			app.graphqlServer = graph.NewServer(cfg, app.cm)
+			app.graphqlServer = graph.NewServer(app.live, app.cm, app.store, app.auditLogger, app.quotas)
 			protectedRoutes.Any("/graphql", gin.WrapH(app.graphqlServer))
 
+			// Log download endpoint (served by the Cluster API in cluster mode so
+			// records are read from the cluster agents)
+			if cfg.Dashboard.Environment == config.EnvironmentCluster && cfg.Dashboard.UI.ClusterAPIEnabled && app.clusterAPIProxy != nil {
+				protectedRoutes.GET("/api/logs/download", clusterAPIDownloadHandler(app.clusterAPIProxy, clusterAPIProxyPath))
+			} else {
+				protectedRoutes.GET("/api/logs/download", gin.WrapH(download.NewHandler(app.cm, app.live.AllowedNamespaces, app.auditLogger, app.quotas)))
+			}
+
 			// Cluster API proxy routes
 			protectedRoutes.Any("/cluster-api-proxy/*path", gin.WrapH(app.clusterAPIProxy))
 		}
@@ -202,6 +308,11 @@ func NewApp(cfg *config.Config) (*App, error) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
 
+	// Metrics endpoint
+	if cfg.Dashboard.Metrics.Enabled {
+		root.GET("/metrics", gin.WrapH(metrics.Handler(k8shelpers.NewInformerCacheCollector(app.cm))))
+	}
+
 	// Serve website from "/" and also unknown routes
 	websiteFS, err := fs.Sub(dashboard.WebsiteEmbedFS, "website")
 	if err != nil {
@@ -166,6 +166,31 @@ func TestHealthz(t *testing.T) {
 	assert.Equal(t, "{\"status\":\"ok\"}", w.Body.String())
 }
 
+func TestMetrics(t *testing.T) {
+	t.Run("enabled", func(t *testing.T) {
+		cfg := newTestConfig()
+		cfg.Dashboard.Metrics.Enabled = true
+		app := newTestApp(cfg)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/metrics", nil)
+		app.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Contains(t, w.Body.String(), "kubetail_logs_streams_open")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		app := newTestApp(nil)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/metrics", nil)
+		app.ServeHTTP(w, r)
+
+		assert.NotContains(t, w.Body.String(), "kubetail_logs_streams_open")
+	})
+}
+
 func TestClusterAPIProxyRouteWithBasePath(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -221,3 +246,45 @@ func TestClusterAPIProxyRouteWithBasePath(t *testing.T) {
 		})
 	}
 }
+
+func TestLogsDownload(t *testing.T) {
+	t.Run("unauthenticated", func(t *testing.T) {
+		cfg := newTestConfig()
+		cfg.Dashboard.AuthMode = config.AuthModeToken
+		app := newTestApp(cfg)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/api/logs/download?source=ns1/pods/pod1", nil)
+		app.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	})
+
+	t.Run("bad request", func(t *testing.T) {
+		app := newTestApp(nil)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/api/logs/download?format=xml", nil)
+		app.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+}
+
+func TestClusterAPIDownloadHandler(t *testing.T) {
+	var gotURL string
+	proxy := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	})
+
+	router := gin.New()
+	router.GET("/base/api/logs/download", clusterAPIDownloadHandler(proxy, "/base/cluster-api-proxy"))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/base/api/logs/download?source=ns1/pods/pod1&format=csv", nil)
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	assert.Equal(t, "/base/cluster-api-proxy/api/logs/download?source=ns1/pods/pod1&format=csv", gotURL)
+}
@@ -16,10 +16,12 @@ package app
 
 import (
 	"net/http"
+	"path"
 	"time"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
+	"k8s.io/client-go/rest"
 
 	"github.com/kubetail-org/kubetail/modules/shared/config"
 
@@ -86,6 +88,9 @@ func (app *authHandlers) LoginPOST(c *gin.Context) {
 // Logout endpoint
 func (app *authHandlers) LogoutPOST(c *gin.Context) {
 	session := sessions.Default(c)
+	if app.oidc != nil {
+		app.oidc.clearSessionUser(session)
+	}
 	session.Clear()
 	session.Save()
 
@@ -128,6 +133,16 @@ func (app *authHandlers) SessionGET(c *gin.Context) {
 			response["message"] = tokenReview.Status.Error
 			response["user"] = nil
 		}
+	case config.AuthModeOIDC:
+		response["login_url"] = path.Join(app.config.Dashboard.BasePath, "/api/auth/oidc/login")
+
+		if user, ok := c.Value(oidcUserGinKey).(*oidcUser); ok {
+			response["user"] = user.Username
+		}
+	case config.AuthModeHeader:
+		if imp, ok := c.Value(k8sImpersonateGinKey).(*rest.ImpersonationConfig); ok && imp != nil {
+			response["user"] = imp.UserName
+		}
 	default:
 		panic("not implemented")
 	}
@@ -231,3 +231,38 @@ func (suite *authTestSuite) TestSessionGET() {
 		})
 	}
 }
+
+func (suite *authTestSuite) TestSessionGETHeaderMode() {
+	type Session struct {
+		User *string
+	}
+
+	// Init config (test server listens on loopback)
+	cfg := newTestConfig()
+	cfg.Dashboard.AuthMode = config.AuthModeHeader
+	cfg.Dashboard.TrustedHeader.User = "X-Remote-User"
+	cfg.Dashboard.TrustedHeader.TrustedProxies = []string{"127.0.0.1/32", "::1/128"}
+
+	client := testutils.NewWebTestClient(suite.T(), newTestApp(cfg))
+	defer client.Teardown()
+
+	// Without identity header
+	{
+		resp := client.Get("/api/auth/session")
+
+		var session Session
+		suite.Nil(json.Unmarshal(resp.Body, &session))
+		suite.Nil(session.User)
+	}
+
+	// With identity header
+	{
+		req := client.NewRequest("GET", "/api/auth/session", nil)
+		req.Header.Set("X-Remote-User", "alice")
+		resp := client.Do(req)
+
+		var session Session
+		suite.Nil(json.Unmarshal(resp.Body, &session))
+		suite.Equal(ptr.To("alice"), session.User)
+	}
+}
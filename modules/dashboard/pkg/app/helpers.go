@@ -16,13 +16,20 @@ package app
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
+	"net/http"
+	"path"
 
+	"github.com/gin-gonic/gin"
 	authv1 "k8s.io/api/authentication/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	clusterapiclient "github.com/kubetail-org/kubetail/modules/shared/clusterapi"
 	"github.com/kubetail-org/kubetail/modules/shared/config"
 	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/otel"
+	"github.com/kubetail-org/kubetail/modules/shared/quota"
 
 	clusterapi "github.com/kubetail-org/kubetail/modules/dashboard/internal/cluster-api"
 )
@@ -31,19 +38,73 @@ const k8sTokenSessionKey = "k8sToken"
 
 const k8sTokenGinKey = "k8sToken"
 
+const k8sImpersonateGinKey = "k8sImpersonate"
+
+// Return session cookie hash and encryption keys derived from `secret` so
+// tokens stored in the session can't be read by clients
+func sessionKeys(secret string) [][]byte {
+	encKey := sha256.Sum256([]byte("kubetail-session-encryption:" + secret))
+	return [][]byte{[]byte(secret), encKey[:]}
+}
+
+// Return tracer config
+func newOTelConfig(cfg *config.Config) *otel.OTelConfig {
+	opts := cfg.Dashboard.Tracing
+
+	otelCfg := &otel.OTelConfig{
+		Enabled:       opts.Enabled,
+		Exporter:      otel.Exporter(opts.Exporter),
+		Endpoint:      opts.Endpoint,
+		Headers:       opts.Headers,
+		SamplingRatio: opts.SamplingRatio,
+		ServiceName:   "kubetail-dashboard",
+	}
+	otelCfg.TLS.Enabled = opts.TLS.Enabled
+	otelCfg.TLS.CAFile = opts.TLS.CAFile
+	otelCfg.TLS.CertFile = opts.TLS.CertFile
+	otelCfg.TLS.KeyFile = opts.TLS.KeyFile
+
+	return otelCfg
+}
+
+// Return per-user quota limits
+func newQuotaLimits(cfg *config.Config) quota.Limits {
+	return quota.Limits{
+		MaxConcurrentSubscriptions: cfg.Dashboard.Quotas.MaxConcurrentSubscriptions,
+		MaxConcurrentFetches:       cfg.Dashboard.Quotas.MaxConcurrentFetches,
+		RequestsPerMinute:          cfg.Dashboard.Quotas.RequestsPerMinute,
+		MaxSourcesPerStream:        cfg.Dashboard.Quotas.MaxSourcesPerStream,
+	}
+}
+
 // newClusterAPIProxy
 func newClusterAPIProxy(cfg *config.Config, cm k8shelpers.ConnectionManager, pathPrefix string) (clusterapi.Proxy, error) {
 	// Initialize new ClusterAPI proxy depending on environment
 	switch cfg.Dashboard.Environment {
 	case config.EnvironmentDesktop:
-		return clusterapi.NewDesktopProxy(cm, pathPrefix)
+		return clusterapi.NewDesktopProxy(cm, pathPrefix, cfg.Dashboard.ClusterAPITLS.Enabled)
 	case config.EnvironmentCluster:
-		return clusterapi.NewInClusterProxy(cfg.Dashboard.ClusterAPIEndpoint, pathPrefix)
+		tlsCfg, err := clusterapiclient.NewTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return clusterapi.NewInClusterProxy(cfg.Dashboard.ClusterAPIEndpoint, pathPrefix, tlsCfg, k8shelpers.ImpersonationFromConfig(cfg))
 	default:
 		return nil, fmt.Errorf("env not supported: %s", cfg.Dashboard.Environment)
 	}
 }
 
+// Return handler that forwards log download requests to the Cluster API's
+// download endpoint
+func clusterAPIDownloadHandler(proxy http.Handler, proxyPathPrefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r := c.Request.Clone(c.Request.Context())
+		r.URL.Path = path.Join(proxyPathPrefix, "/api/logs/download")
+		r.URL.RawPath = ""
+		proxy.ServeHTTP(c.Writer, r)
+	}
+}
+
 // queryHelpers interface
 type queryHelpers interface {
 	HasAccess(ctx context.Context, token string) (*authv1.TokenReview, error)
@@ -16,11 +16,13 @@ package app
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"strings"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
+	"k8s.io/client-go/rest"
 
 	"github.com/kubetail-org/kubetail/modules/shared/config"
 	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
@@ -55,6 +57,72 @@ func authenticationMiddleware(mode config.AuthMode) gin.HandlerFunc {
 	}
 }
 
+// Middleware that authenticates requests using identity headers set by a
+// trusted authenticating proxy. Headers from other clients are ignored.
+func trustedHeaderAuthenticationMiddleware(cfg *config.Config) gin.HandlerFunc {
+	opts := cfg.Dashboard.TrustedHeader
+
+	// CIDRs were checked during config validation
+	trustedProxies := []*net.IPNet{}
+	for _, cidr := range opts.TrustedProxies {
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+			trustedProxies = append(trustedProxies, ipNet)
+		}
+	}
+
+	isTrusted := func(remoteAddr string) bool {
+		host, _, err := net.SplitHostPort(remoteAddr)
+		if err != nil {
+			host = remoteAddr
+		}
+		ip := net.ParseIP(host)
+		if ip == nil {
+			return false
+		}
+		for _, ipNet := range trustedProxies {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(c *gin.Context) {
+		// Use connection address (X-Forwarded-For can be spoofed)
+		if !isTrusted(c.Request.RemoteAddr) {
+			c.Next()
+			return
+		}
+
+		userName := strings.TrimSpace(c.GetHeader(opts.User))
+		if userName == "" {
+			c.Next()
+			return
+		}
+
+		imp := &rest.ImpersonationConfig{UserName: userName}
+
+		if opts.UID != "" {
+			imp.UID = strings.TrimSpace(c.GetHeader(opts.UID))
+		}
+
+		// Groups can be sent as repeated headers or comma-separated values
+		if opts.Groups != "" {
+			for _, val := range c.Request.Header.Values(opts.Groups) {
+				for _, group := range strings.Split(val, ",") {
+					if group = strings.TrimSpace(group); group != "" {
+						imp.Groups = append(imp.Groups, group)
+					}
+				}
+			}
+		}
+
+		c.Set(k8sImpersonateGinKey, imp)
+
+		c.Next()
+	}
+}
+
 func k8sAuthenticationMiddleware(mode config.AuthMode) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// set "Cache-Control: no-store" so that pages aren't stored in the users browser cache
@@ -63,8 +131,14 @@ func k8sAuthenticationMiddleware(mode config.AuthMode) gin.HandlerFunc {
 		// Get token from gin session
 		token := c.GetString(k8sTokenGinKey)
 
-		// Reject unauthenticated requests if auth-mode: token
-		if mode == config.AuthModeToken && token == "" {
+		// Get impersonation config (bearer tokens take precedence)
+		var imp *rest.ImpersonationConfig
+		if token == "" {
+			imp, _ = c.Value(k8sImpersonateGinKey).(*rest.ImpersonationConfig)
+		}
+
+		// Reject unauthenticated requests if auth-mode: token, oidc or header
+		if (mode == config.AuthModeToken || mode == config.AuthModeOIDC || mode == config.AuthModeHeader) && token == "" && imp == nil {
 			c.AbortWithStatus(http.StatusUnauthorized)
 			return
 		}
@@ -77,6 +151,13 @@ func k8sAuthenticationMiddleware(mode config.AuthMode) gin.HandlerFunc {
 			c.Request = c.Request.WithContext(ctx)
 		}
 
+		// If impersonating add to request context for kubernetes requests downstream
+		if imp != nil {
+			ctx := context.WithValue(c.Request.Context(), k8shelpers.ImpersonateCtxKey, imp)
+
+			c.Request = c.Request.WithContext(ctx)
+		}
+
 		c.Next()
 	}
 }
@@ -23,8 +23,10 @@ import (
 	"github.com/gin-contrib/sessions/cookie"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
 
 	"github.com/kubetail-org/kubetail/modules/shared/config"
+	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
 )
 
 func TestAuthenticationMiddleware(t *testing.T) {
@@ -113,6 +115,8 @@ func TestK8sTokenRequiredMiddleware(t *testing.T) {
 		{"auto-mode without session token", config.AuthModeAuto, false, http.StatusOK},
 		{"token-mode with session token", config.AuthModeToken, true, http.StatusOK},
 		{"token-mode without session token", config.AuthModeToken, false, http.StatusUnauthorized},
+		{"header-mode with session token", config.AuthModeHeader, true, http.StatusOK},
+		{"header-mode without session token", config.AuthModeHeader, false, http.StatusUnauthorized},
 	}
 
 	for _, tt := range tests {
@@ -149,3 +153,78 @@ func TestK8sTokenRequiredMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestTrustedHeaderAuthenticationMiddleware(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Dashboard.AuthMode = config.AuthModeHeader
+	cfg.Dashboard.TrustedHeader.UID = "X-Remote-Uid"
+	cfg.Dashboard.TrustedHeader.TrustedProxies = []string{"10.0.0.0/8", "::1/128"}
+
+	tests := []struct {
+		name           string
+		setRemoteAddr  string
+		setHeaders     map[string][]string
+		wantStatusCode int
+		wantImp        *rest.ImpersonationConfig
+	}{
+		{
+			"trusted proxy",
+			"10.1.2.3:4567",
+			map[string][]string{"X-Remote-User": {"alice"}, "X-Remote-Uid": {"42"}, "X-Remote-Group": {"dev, ops", "qa"}},
+			http.StatusOK,
+			&rest.ImpersonationConfig{UserName: "alice", UID: "42", Groups: []string{"dev", "ops", "qa"}},
+		},
+		{
+			"trusted ipv6 proxy",
+			"[::1]:4567",
+			map[string][]string{"X-Remote-User": {"alice"}},
+			http.StatusOK,
+			&rest.ImpersonationConfig{UserName: "alice"},
+		},
+		{
+			"untrusted client",
+			"192.0.2.1:4567",
+			map[string][]string{"X-Remote-User": {"admin"}, "X-Forwarded-For": {"10.1.2.3"}},
+			http.StatusUnauthorized,
+			nil,
+		},
+		{
+			"missing user header",
+			"10.1.2.3:4567",
+			map[string][]string{"X-Remote-Group": {"dev"}},
+			http.StatusUnauthorized,
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// set up router
+			router := gin.New()
+
+			// add middleware
+			router.Use(trustedHeaderAuthenticationMiddleware(cfg))
+			router.Use(k8sAuthenticationMiddleware(cfg.Dashboard.AuthMode))
+
+			// add route for testing
+			router.GET("/", func(c *gin.Context) {
+				assert.Equal(t, tt.wantImp, k8shelpers.ImpersonationFromContext(c.Request.Context()))
+				c.String(http.StatusOK, "ok")
+			})
+
+			// execute request
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.setRemoteAddr
+			for key, vals := range tt.setHeaders {
+				for _, val := range vals {
+					r.Header.Add(key, val)
+				}
+			}
+			router.ServeHTTP(w, r)
+
+			// check result
+			assert.Equal(t, tt.wantStatusCode, w.Result().StatusCode)
+		})
+	}
+}
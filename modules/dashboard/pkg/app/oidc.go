@@ -0,0 +1,471 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	zlog "github.com/rs/zerolog/log"
+	"golang.org/x/oauth2"
+	"golang.org/x/sync/singleflight"
+	"k8s.io/client-go/rest"
+
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+	"github.com/kubetail-org/kubetail/modules/shared/util"
+)
+
+// Session keys used during the login flow
+const (
+	oidcStateSessionKey    = "oidcState"
+	oidcNonceSessionKey    = "oidcNonce"
+	oidcVerifierSessionKey = "oidcVerifier"
+	oidcRedirectSessionKey = "oidcRedirect"
+)
+
+// Session key that references server-side session data. Tokens are kept
+// out of the cookie so they can't be read by clients and don't overflow it.
+const oidcSessionIDSessionKey = "oidcSessionID"
+
+const oidcUserGinKey = "oidcUser"
+
+// Refresh ID tokens this long before they expire
+const oidcRefreshSkew = 1 * time.Minute
+
+// Idle timeout for server-side sessions if cookies don't expire
+const oidcDefaultSessionTTL = 24 * time.Hour
+
+var errOIDCSessionExpired = errors.New("oidc session expired")
+
+// Represents an authenticated OIDC user
+type oidcUser struct {
+	Username  string
+	Groups    []string
+	IDToken   string
+	ExpiresAt time.Time
+}
+
+// Represents server-side OIDC session data
+type oidcSession struct {
+	user         *oidcUser
+	refreshToken string
+	lastSeen     atomic.Int64
+}
+
+// Represents OIDC authenticator
+type oidcAuthenticator struct {
+	verifier     *oidc.IDTokenVerifier
+	oauth2Config oauth2.Config
+	httpClient   *http.Client
+	tokenMode    config.OIDCTokenMode
+
+	usernameClaim  string
+	usernamePrefix string
+	groupsClaim    string
+	groupsPrefix   string
+
+	sessions     util.SyncMap[string, *oidcSession]
+	sessionTTL   time.Duration
+	refreshGroup singleflight.Group
+}
+
+// Create new oidcAuthenticator instance (performs provider discovery)
+func newOIDCAuthenticator(ctx context.Context, cfg *config.Config) (*oidcAuthenticator, error) {
+	opts := cfg.Dashboard.OIDC
+
+	// Init http client
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	if opts.CAFile != "" {
+		caCert, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse oidc ca-file: %s", opts.CAFile)
+		}
+
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+		httpClient.Transport = transport
+	}
+
+	// Discover provider endpoints
+	provider, err := oidc.NewProvider(oidc.ClientContext(ctx, httpClient), opts.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("oidc discovery failed: %w", err)
+	}
+
+	// Always request "openid" scope
+	scopes := []string{oidc.ScopeOpenID}
+	for _, scope := range opts.Scopes {
+		if scope != oidc.ScopeOpenID && !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	tokenMode := opts.TokenMode
+	if tokenMode == "" {
+		tokenMode = config.OIDCTokenModePassthrough
+	}
+
+	// Expire idle sessions together with their cookies
+	sessionTTL := time.Duration(cfg.Dashboard.Session.Cookie.MaxAge) * time.Second
+	if sessionTTL <= 0 {
+		sessionTTL = oidcDefaultSessionTTL
+	}
+
+	return &oidcAuthenticator{
+		verifier: provider.Verifier(&oidc.Config{ClientID: opts.ClientID}),
+		oauth2Config: oauth2.Config{
+			ClientID:     opts.ClientID,
+			ClientSecret: opts.ClientSecret,
+			RedirectURL:  opts.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       scopes,
+		},
+		httpClient:     httpClient,
+		tokenMode:      tokenMode,
+		usernameClaim:  opts.UsernameClaim,
+		usernamePrefix: opts.UsernamePrefix,
+		groupsClaim:    opts.GroupsClaim,
+		groupsPrefix:   opts.GroupsPrefix,
+		sessionTTL:     sessionTTL,
+	}, nil
+}
+
+// Return context that uses the authenticator's http client for outgoing requests
+func (a *oidcAuthenticator) clientContext(ctx context.Context) context.Context {
+	return oidc.ClientContext(ctx, a.httpClient)
+}
+
+// Verify raw ID token and extract user
+func (a *oidcAuthenticator) verify(ctx context.Context, rawIDToken string, nonce string) (*oidcUser, error) {
+	idToken, err := a.verifier.Verify(a.clientContext(ctx), rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if nonce != "" && idToken.Nonce != nonce {
+		return nil, fmt.Errorf("oidc nonce mismatch")
+	}
+
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+
+	// Get username
+	usernameClaim := a.usernameClaim
+	if usernameClaim == "" {
+		usernameClaim = "sub"
+	}
+
+	username, _ := claims[usernameClaim].(string)
+	if username == "" {
+		return nil, fmt.Errorf("oidc claim not found: %s", usernameClaim)
+	}
+
+	// Follow kube-apiserver and only accept verified emails
+	if usernameClaim == "email" {
+		if verified, ok := claims["email_verified"].(bool); ok && !verified {
+			return nil, fmt.Errorf("oidc email not verified: %s", username)
+		}
+	}
+
+	// Get groups (claim can be a list or a single string)
+	groups := []string{}
+	if a.groupsClaim != "" {
+		switch v := claims[a.groupsClaim].(type) {
+		case string:
+			groups = append(groups, a.groupsPrefix+v)
+		case []any:
+			for _, g := range v {
+				if s, ok := g.(string); ok {
+					groups = append(groups, a.groupsPrefix+s)
+				}
+			}
+		}
+	}
+
+	return &oidcUser{
+		Username:  a.usernamePrefix + username,
+		Groups:    groups,
+		IDToken:   rawIDToken,
+		ExpiresAt: idToken.Expiry,
+	}, nil
+}
+
+// Get user from session, refreshing the ID token if necessary. Returns nil if
+// the session doesn't have an OIDC user.
+func (a *oidcAuthenticator) sessionUser(ctx context.Context, session sessions.Session) (*oidcUser, error) {
+	id, _ := session.Get(oidcSessionIDSessionKey).(string)
+	if id == "" {
+		return nil, nil
+	}
+
+	s, ok := a.sessions.Load(id)
+	if !ok || time.Since(time.Unix(s.lastSeen.Load(), 0)) > a.sessionTTL {
+		a.sessions.Delete(id)
+		return nil, errOIDCSessionExpired
+	}
+	s.lastSeen.Store(time.Now().Unix())
+
+	// Return cached user if token is still fresh
+	if time.Until(s.user.ExpiresAt) > oidcRefreshSkew {
+		return s.user, nil
+	}
+
+	// Concurrent requests share one refresh so the refresh token is only redeemed once
+	v, err, _ := a.refreshGroup.Do(id, func() (any, error) {
+		return a.refresh(context.WithoutCancel(ctx), id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*oidcUser), nil
+}
+
+// Refresh ID token of server-side session
+func (a *oidcAuthenticator) refresh(ctx context.Context, id string) (*oidcUser, error) {
+	s, ok := a.sessions.Load(id)
+	if !ok {
+		return nil, errOIDCSessionExpired
+	}
+
+	// Check if session was refreshed while waiting
+	if time.Until(s.user.ExpiresAt) > oidcRefreshSkew {
+		return s.user, nil
+	}
+
+	if s.refreshToken == "" {
+		return nil, errOIDCSessionExpired
+	}
+
+	token, err := a.oauth2Config.TokenSource(a.clientContext(ctx), &oauth2.Token{RefreshToken: s.refreshToken}).Token()
+	if err != nil {
+		return nil, err
+	}
+
+	newRawIDToken, _ := token.Extra("id_token").(string)
+	if newRawIDToken == "" {
+		return nil, fmt.Errorf("oidc refresh response missing id_token")
+	}
+
+	user, err := a.verify(ctx, newRawIDToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Providers may rotate refresh tokens
+	refreshToken := s.refreshToken
+	if token.RefreshToken != "" {
+		refreshToken = token.RefreshToken
+	}
+
+	a.storeSession(id, user, refreshToken)
+
+	return user, nil
+}
+
+// Create server-side session for user and reference it from the cookie session
+func (a *oidcAuthenticator) setSessionUser(session sessions.Session, user *oidcUser, refreshToken string) {
+	a.clearSessionUser(session)
+	a.pruneSessions()
+
+	id := randomString()
+	a.storeSession(id, user, refreshToken)
+	session.Set(oidcSessionIDSessionKey, id)
+}
+
+// Remove server-side session and its reference from the cookie session
+func (a *oidcAuthenticator) clearSessionUser(session sessions.Session) {
+	if id, _ := session.Get(oidcSessionIDSessionKey).(string); id != "" {
+		a.sessions.Delete(id)
+	}
+	session.Delete(oidcSessionIDSessionKey)
+}
+
+// Store server-side session data
+func (a *oidcAuthenticator) storeSession(id string, user *oidcUser, refreshToken string) {
+	s := &oidcSession{user: user, refreshToken: refreshToken}
+	s.lastSeen.Store(time.Now().Unix())
+	a.sessions.Store(id, s)
+}
+
+// Remove idle server-side sessions
+func (a *oidcAuthenticator) pruneSessions() {
+	a.sessions.Range(func(id string, s *oidcSession) bool {
+		if time.Since(time.Unix(s.lastSeen.Load(), 0)) > a.sessionTTL {
+			a.sessions.Delete(id)
+		}
+		return true
+	})
+}
+
+// Represents oidc handlers
+type oidcHandlers struct {
+	*App
+}
+
+// Login endpoint (redirects to provider)
+func (app *oidcHandlers) LoginGET(c *gin.Context) {
+	state := randomString()
+	nonce := randomString()
+	verifier := oauth2.GenerateVerifier()
+
+	// Only allow local redirects
+	redirect := c.Query("redirect")
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") || strings.HasPrefix(redirect, "/\\") {
+		redirect = app.config.Dashboard.BasePath
+	}
+
+	// Add data to session
+	session := sessions.Default(c)
+	session.Set(oidcStateSessionKey, state)
+	session.Set(oidcNonceSessionKey, nonce)
+	session.Set(oidcVerifierSessionKey, verifier)
+	session.Set(oidcRedirectSessionKey, redirect)
+
+	if err := session.Save(); err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	authURL := app.oidc.oauth2Config.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback endpoint (exchanges code for tokens)
+func (app *oidcHandlers) CallbackGET(c *gin.Context) {
+	session := sessions.Default(c)
+
+	state, _ := session.Get(oidcStateSessionKey).(string)
+	nonce, _ := session.Get(oidcNonceSessionKey).(string)
+	verifier, _ := session.Get(oidcVerifierSessionKey).(string)
+	redirect, _ := session.Get(oidcRedirectSessionKey).(string)
+
+	// Login data is single-use
+	session.Delete(oidcStateSessionKey)
+	session.Delete(oidcNonceSessionKey)
+	session.Delete(oidcVerifierSessionKey)
+	session.Delete(oidcRedirectSessionKey)
+
+	// Handle provider errors
+	if errCode := c.Query("error"); errCode != "" {
+		session.Save()
+		c.String(http.StatusUnauthorized, fmt.Sprintf("%s: %s", errCode, c.Query("error_description")))
+		return
+	}
+
+	// Check state
+	if state == "" || c.Query("state") != state {
+		session.Save()
+		c.String(http.StatusBadRequest, "invalid state")
+		return
+	}
+
+	ctx := app.oidc.clientContext(c.Request.Context())
+
+	// Exchange code
+	token, err := app.oidc.oauth2Config.Exchange(ctx, c.Query("code"), oauth2.VerifierOption(verifier))
+	if err != nil {
+		zlog.Debug().Err(err).Msg("oidc code exchange failed")
+		session.Save()
+		c.String(http.StatusUnauthorized, "code exchange failed")
+		return
+	}
+
+	rawIDToken, _ := token.Extra("id_token").(string)
+	if rawIDToken == "" {
+		session.Save()
+		c.String(http.StatusUnauthorized, "missing id_token")
+		return
+	}
+
+	// Verify ID token
+	user, err := app.oidc.verify(ctx, rawIDToken, nonce)
+	if err != nil {
+		zlog.Debug().Err(err).Msg("oidc id_token verification failed")
+		session.Save()
+		c.String(http.StatusUnauthorized, "invalid id_token")
+		return
+	}
+
+	// Add user to session
+	app.oidc.setSessionUser(session, user, token.RefreshToken)
+	if err := session.Save(); err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if redirect == "" {
+		redirect = app.config.Dashboard.BasePath
+	}
+	c.Redirect(http.StatusFound, redirect)
+}
+
+// Middleware that authenticates requests using the OIDC session
+func oidcAuthenticationMiddleware(a *oidcAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		session := sessions.Default(c)
+
+		user, err := a.sessionUser(c.Request.Context(), session)
+		if err != nil {
+			zlog.Debug().Err(err).Msg("oidc session invalidated")
+			a.clearSessionUser(session)
+			session.Save()
+		}
+
+		if user != nil {
+			c.Set(oidcUserGinKey, user)
+
+			switch a.tokenMode {
+			case config.OIDCTokenModeImpersonate:
+				c.Set(k8sImpersonateGinKey, &rest.ImpersonationConfig{
+					UserName: user.Username,
+					Groups:   user.Groups,
+				})
+			default:
+				c.Set(k8sTokenGinKey, user.IDToken)
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// Generate random url-safe string
+func randomString() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
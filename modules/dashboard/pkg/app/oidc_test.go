@@ -0,0 +1,498 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/testutils"
+)
+
+// Represents pending authorization code
+type mockOIDCCode struct {
+	challenge string
+	nonce     string
+}
+
+// Represents minimal OIDC provider for testing
+type mockOIDCProvider struct {
+	*httptest.Server
+	key          *rsa.PrivateKey
+	tokenTTL     time.Duration
+	claims       jwt.MapClaims
+	codes        map[string]mockOIDCCode
+	refreshCount int
+	failRefresh  bool
+	refreshDelay time.Duration
+	usedRefresh  map[string]bool
+	mu           sync.Mutex
+}
+
+// Create new mock provider
+func newMockOIDCProvider(t *testing.T) *mockOIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	p := &mockOIDCProvider{
+		key:      key,
+		tokenTTL: time.Hour,
+		claims: jwt.MapClaims{
+			"sub":            "1234",
+			"email":          "alice@example.com",
+			"email_verified": true,
+			"groups":         []string{"dev", "ops"},
+		},
+		codes:       map[string]mockOIDCCode{},
+		usedRefresh: map[string]bool{},
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                                p.URL,
+			"authorization_endpoint":                p.URL + "/authorize",
+			"token_endpoint":                        p.URL + "/token",
+			"jwks_uri":                              p.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]any{{
+				"kty": "RSA",
+				"kid": "test",
+				"alg": "RS256",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+			}},
+		})
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		delay := p.refreshDelay
+		p.mu.Unlock()
+		time.Sleep(delay)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		r.ParseForm()
+
+		var nonce, refreshToken string
+		switch r.PostForm.Get("grant_type") {
+		case "authorization_code":
+			code, ok := p.codes[r.PostForm.Get("code")]
+			delete(p.codes, r.PostForm.Get("code"))
+
+			// Check PKCE verifier
+			sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+			if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != code.challenge {
+				http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+				return
+			}
+			nonce = code.nonce
+			refreshToken = "refresh-0"
+		case "refresh_token":
+			// Refresh tokens are rotated and single-use
+			if p.failRefresh || r.PostForm.Get("refresh_token") == "" || p.usedRefresh[r.PostForm.Get("refresh_token")] {
+				http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+				return
+			}
+			p.usedRefresh[r.PostForm.Get("refresh_token")] = true
+			p.refreshCount += 1
+			refreshToken = fmt.Sprintf("refresh-%d", p.refreshCount)
+		default:
+			http.Error(w, `{"error":"unsupported_grant_type"}`, http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "access",
+			"token_type":    "Bearer",
+			"expires_in":    int(p.tokenTTL.Seconds()),
+			"refresh_token": refreshToken,
+			"id_token":      p.signIDToken_UNSAFE(t, nonce),
+		})
+	})
+
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+
+	return p
+}
+
+// Register authorization code
+func (p *mockOIDCProvider) addCode(code string, challenge string, nonce string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.codes[code] = mockOIDCCode{challenge, nonce}
+}
+
+// Generate signed ID token
+func (p *mockOIDCProvider) signIDToken_UNSAFE(t *testing.T, nonce string) string {
+	now := time.Now()
+
+	claims := jwt.MapClaims{
+		"iss": p.URL,
+		"aud": "kubetail",
+		"iat": now.Unix(),
+		"exp": now.Add(p.tokenTTL).Unix(),
+	}
+	for k, v := range p.claims {
+		claims[k] = v
+	}
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test"
+
+	signed, err := token.SignedString(p.key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+// Create new app configured for the mock provider
+func newOIDCTestApp(p *mockOIDCProvider, tokenMode config.OIDCTokenMode) *App {
+	cfg := newTestConfig()
+	cfg.Dashboard.AuthMode = config.AuthModeOIDC
+	cfg.Dashboard.Session.Cookie.Path = "/"
+	cfg.Dashboard.OIDC.IssuerURL = p.URL
+	cfg.Dashboard.OIDC.ClientID = "kubetail"
+	cfg.Dashboard.OIDC.ClientSecret = "secret"
+	cfg.Dashboard.OIDC.RedirectURL = "http://localhost/api/auth/oidc/callback"
+	cfg.Dashboard.OIDC.UsernameClaim = "email"
+	cfg.Dashboard.OIDC.GroupsClaim = "groups"
+	cfg.Dashboard.OIDC.GroupsPrefix = "oidc:"
+	cfg.Dashboard.OIDC.TokenMode = tokenMode
+
+	app := newTestApp(cfg)
+
+	// add protected route for testing
+	app.dynamicRoutes.GET("/x", k8sAuthenticationMiddleware(cfg.Dashboard.AuthMode), func(c *gin.Context) {
+		ctx := c.Request.Context()
+		token, _ := ctx.Value(k8shelpers.K8STokenCtxKey).(string)
+		imp, _ := ctx.Value(k8shelpers.ImpersonateCtxKey).(*rest.ImpersonationConfig)
+		c.JSON(http.StatusOK, gin.H{"token": token, "impersonate": imp})
+	})
+
+	return app
+}
+
+// Run login flow against the mock provider and return callback response
+func doOIDCLogin(t *testing.T, client *testutils.WebTestClient, p *mockOIDCProvider, redirect string) testutils.WebTestResponse {
+	resp := client.Get("/api/auth/oidc/login?redirect=" + url.QueryEscape(redirect))
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	authURL, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, p.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+
+	q := authURL.Query()
+	assert.Equal(t, "code", q.Get("response_type"))
+	assert.Equal(t, "kubetail", q.Get("client_id"))
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+	assert.Contains(t, q.Get("scope"), "openid")
+
+	p.addCode("code-1", q.Get("code_challenge"), q.Get("nonce"))
+
+	return client.Get("/api/auth/oidc/callback?code=code-1&state=" + url.QueryEscape(q.Get("state")))
+}
+
+func TestOIDCLoginPassthrough(t *testing.T) {
+	p := newMockOIDCProvider(t)
+	client := testutils.NewWebTestClient(t, newOIDCTestApp(p, config.OIDCTokenModePassthrough))
+	defer client.Teardown()
+
+	// protected routes require login
+	resp := client.Get("/x")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// login
+	resp = doOIDCLogin(t, client, p, "/console")
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.Equal(t, "/console", resp.Header.Get("Location"))
+
+	// check session
+	resp = client.Get("/api/auth/session")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var session map[string]any
+	require.NoError(t, json.Unmarshal(resp.Body, &session))
+	assert.Equal(t, "oidc", session["auth_mode"])
+	assert.Equal(t, "alice@example.com", session["user"])
+	assert.Equal(t, "/api/auth/oidc/login", session["login_url"])
+
+	// ID token is passed through to kubernetes requests
+	resp = client.Get("/x")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Token       string
+		Impersonate *rest.ImpersonationConfig
+	}
+	require.NoError(t, json.Unmarshal(resp.Body, &result))
+	assert.NotEmpty(t, result.Token)
+	assert.Nil(t, result.Impersonate)
+
+	// logout
+	resp = client.Post("/api/auth/logout", "", nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp = client.Get("/x")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestOIDCLoginImpersonate(t *testing.T) {
+	p := newMockOIDCProvider(t)
+	client := testutils.NewWebTestClient(t, newOIDCTestApp(p, config.OIDCTokenModeImpersonate))
+	defer client.Teardown()
+
+	resp := doOIDCLogin(t, client, p, "/")
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	resp = client.Get("/x")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Token       string
+		Impersonate *rest.ImpersonationConfig
+	}
+	require.NoError(t, json.Unmarshal(resp.Body, &result))
+	assert.Empty(t, result.Token)
+	require.NotNil(t, result.Impersonate)
+	assert.Equal(t, "alice@example.com", result.Impersonate.UserName)
+	assert.Equal(t, []string{"oidc:dev", "oidc:ops"}, result.Impersonate.Groups)
+}
+
+func TestOIDCCallbackErrors(t *testing.T) {
+	t.Run("invalid state", func(t *testing.T) {
+		p := newMockOIDCProvider(t)
+		client := testutils.NewWebTestClient(t, newOIDCTestApp(p, config.OIDCTokenModePassthrough))
+		defer client.Teardown()
+
+		resp := client.Get("/api/auth/oidc/login")
+		require.Equal(t, http.StatusFound, resp.StatusCode)
+
+		resp = client.Get("/api/auth/oidc/callback?code=code-1&state=xxx")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		p := newMockOIDCProvider(t)
+		client := testutils.NewWebTestClient(t, newOIDCTestApp(p, config.OIDCTokenModePassthrough))
+		defer client.Teardown()
+
+		resp := client.Get("/api/auth/oidc/callback?error=access_denied")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Contains(t, string(resp.Body), "access_denied")
+	})
+
+	t.Run("unknown code", func(t *testing.T) {
+		p := newMockOIDCProvider(t)
+		client := testutils.NewWebTestClient(t, newOIDCTestApp(p, config.OIDCTokenModePassthrough))
+		defer client.Teardown()
+
+		resp := client.Get("/api/auth/oidc/login")
+		require.Equal(t, http.StatusFound, resp.StatusCode)
+
+		authURL, err := url.Parse(resp.Header.Get("Location"))
+		require.NoError(t, err)
+
+		resp = client.Get("/api/auth/oidc/callback?code=bad&state=" + url.QueryEscape(authURL.Query().Get("state")))
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("nonce mismatch", func(t *testing.T) {
+		p := newMockOIDCProvider(t)
+		client := testutils.NewWebTestClient(t, newOIDCTestApp(p, config.OIDCTokenModePassthrough))
+		defer client.Teardown()
+
+		resp := client.Get("/api/auth/oidc/login")
+		require.Equal(t, http.StatusFound, resp.StatusCode)
+
+		authURL, err := url.Parse(resp.Header.Get("Location"))
+		require.NoError(t, err)
+		q := authURL.Query()
+
+		p.addCode("code-1", q.Get("code_challenge"), "other-nonce")
+
+		resp = client.Get("/api/auth/oidc/callback?code=code-1&state=" + url.QueryEscape(q.Get("state")))
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		resp = client.Get("/x")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("unverified email", func(t *testing.T) {
+		p := newMockOIDCProvider(t)
+		p.claims["email_verified"] = false
+		client := testutils.NewWebTestClient(t, newOIDCTestApp(p, config.OIDCTokenModePassthrough))
+		defer client.Teardown()
+
+		resp := doOIDCLogin(t, client, p, "/")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestOIDCOpenRedirect(t *testing.T) {
+	for _, redirect := range []string{"https://evil.example.com", "//evil.example.com", "/\\evil.example.com"} {
+		t.Run(redirect, func(t *testing.T) {
+			p := newMockOIDCProvider(t)
+			client := testutils.NewWebTestClient(t, newOIDCTestApp(p, config.OIDCTokenModePassthrough))
+			defer client.Teardown()
+
+			resp := doOIDCLogin(t, client, p, redirect)
+			require.Equal(t, http.StatusFound, resp.StatusCode)
+			assert.Equal(t, "/", resp.Header.Get("Location"))
+		})
+	}
+}
+
+func TestOIDCRefresh(t *testing.T) {
+	p := newMockOIDCProvider(t)
+
+	// issue tokens that are always within the refresh window
+	p.tokenTTL = 30 * time.Second
+
+	client := testutils.NewWebTestClient(t, newOIDCTestApp(p, config.OIDCTokenModePassthrough))
+	defer client.Teardown()
+
+	resp := doOIDCLogin(t, client, p, "/")
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	// request triggers refresh
+	resp = client.Get("/x")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	p.mu.Lock()
+	assert.Equal(t, 1, p.refreshCount)
+	p.mu.Unlock()
+
+	// failed refresh ends the session
+	p.mu.Lock()
+	p.failRefresh = true
+	p.mu.Unlock()
+
+	resp = client.Get("/x")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp = client.Get("/api/auth/session")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var session map[string]any
+	require.NoError(t, json.Unmarshal(resp.Body, &session))
+	assert.Nil(t, session["user"])
+}
+
+func TestOIDCSessionCookie(t *testing.T) {
+	p := newMockOIDCProvider(t)
+
+	app := newOIDCTestApp(p, config.OIDCTokenModePassthrough)
+	client := testutils.NewWebTestClient(t, app)
+	defer client.Teardown()
+
+	resp := doOIDCLogin(t, client, p, "/")
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	// Tokens are kept server-side so the cookie stays small
+	require.Len(t, resp.Cookies, 1)
+	assert.Less(t, len(resp.Cookies[0].Value), 1024)
+
+	var count int
+	app.oidc.sessions.Range(func(id string, s *oidcSession) bool {
+		count++
+		assert.NotEmpty(t, s.user.IDToken)
+		assert.Equal(t, "refresh-0", s.refreshToken)
+		return true
+	})
+	assert.Equal(t, 1, count)
+
+	// Logout removes server-side session
+	resp = client.Post("/api/auth/logout", "", nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	count = 0
+	app.oidc.sessions.Range(func(id string, s *oidcSession) bool {
+		count++
+		return true
+	})
+	assert.Equal(t, 0, count)
+}
+
+func TestOIDCConcurrentRefresh(t *testing.T) {
+	p := newMockOIDCProvider(t)
+
+	// issue tokens that are always within the refresh window
+	p.tokenTTL = 30 * time.Second
+
+	client := testutils.NewWebTestClient(t, newOIDCTestApp(p, config.OIDCTokenModePassthrough))
+	defer client.Teardown()
+
+	resp := doOIDCLogin(t, client, p, "/")
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	// slow down provider so requests overlap
+	p.mu.Lock()
+	p.refreshDelay = 200 * time.Millisecond
+	p.mu.Unlock()
+
+	// concurrent requests redeem the refresh token once
+	var wg sync.WaitGroup
+	statusCodes := make([]int, 5)
+	for i := range statusCodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statusCodes[i] = client.Get("/x").StatusCode
+		}()
+	}
+	wg.Wait()
+
+	for _, code := range statusCodes {
+		assert.Equal(t, http.StatusOK, code)
+	}
+
+	p.mu.Lock()
+	assert.Equal(t, 1, p.refreshCount)
+	p.mu.Unlock()
+}
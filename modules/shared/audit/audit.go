@@ -0,0 +1,166 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Outcome values
+const (
+	OutcomeSuccess  = "success"
+	OutcomeError    = "error"
+	OutcomeCanceled = "canceled"
+)
+
+// Represents source filters used by a query
+type SourceFilter struct {
+	Region    []string `json:"region,omitempty"`
+	Zone      []string `json:"zone,omitempty"`
+	OS        []string `json:"os,omitempty"`
+	Arch      []string `json:"arch,omitempty"`
+	Node      []string `json:"node,omitempty"`
+	Container []string `json:"container,omitempty"`
+}
+
+// Represents a single audit log entry
+type Event struct {
+	Time         time.Time    `json:"time"`
+	App          string       `json:"app"`
+	Operation    string       `json:"operation"`
+	User         string       `json:"user,omitempty"`
+	Groups       []string     `json:"groups,omitempty"`
+	KubeContext  string       `json:"kubeContext,omitempty"`
+	Namespaces   []string     `json:"namespaces,omitempty"`
+	Sources      []string     `json:"sources,omitempty"`
+	SourceFilter SourceFilter `json:"sourceFilter,omitzero"`
+	Grep         string       `json:"grep,omitempty"`
+	Mode         string       `json:"mode,omitempty"`
+	Since        time.Time    `json:"since,omitzero"`
+	Until        time.Time    `json:"until,omitzero"`
+	RecordCount  int64        `json:"recordCount"`
+	DurationMS   int64        `json:"durationMs"`
+	Outcome      string       `json:"outcome"`
+	Error        string       `json:"error,omitempty"`
+}
+
+// IdentifyFunc returns the user and groups associated with a request
+type IdentifyFunc func(ctx context.Context) (user string, groups []string)
+
+// Represents Logger
+type Logger struct {
+	app      string
+	sink     Sink
+	identify IdentifyFunc
+}
+
+// Create new Logger instance. `identify` can be nil.
+func NewLogger(app string, sink Sink, identify IdentifyFunc) *Logger {
+	return &Logger{app: app, sink: sink, identify: identify}
+}
+
+// Start begins a new audit entry. The caller must call Finish() when the
+// operation is done. Calling Start() on a nil logger returns a nil entry
+// whose methods are no-ops.
+func (l *Logger) Start(ctx context.Context, ev Event) *Entry {
+	if l == nil {
+		return nil
+	}
+
+	ev.App = l.app
+	if l.identify != nil {
+		ev.User, ev.Groups = l.identify(ctx)
+	}
+
+	return &Entry{logger: l, ev: ev, startedAt: time.Now()}
+}
+
+// Close underlying sink
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+	return l.sink.Close()
+}
+
+// Represents an in-flight audit entry
+type Entry struct {
+	logger      *Logger
+	ev          Event
+	startedAt   time.Time
+	recordCount atomic.Int64
+	once        sync.Once
+}
+
+// SetNamespaces records the namespaces once they're resolved. Must be called
+// before Finish().
+func (e *Entry) SetNamespaces(namespaces []string) {
+	if e == nil {
+		return
+	}
+	e.ev.Namespaces = namespaces
+}
+
+// SetTimeRange records the query time range once it's parsed. Must be called
+// before Finish().
+func (e *Entry) SetTimeRange(since time.Time, until time.Time) {
+	if e == nil {
+		return
+	}
+	e.ev.Since = since
+	e.ev.Until = until
+}
+
+// AddRecords increments the number of records returned to the user
+func (e *Entry) AddRecords(n int) {
+	if e == nil {
+		return
+	}
+	e.recordCount.Add(int64(n))
+}
+
+// Finish writes the entry to the sink. Subsequent calls are ignored.
+func (e *Entry) Finish(err error) {
+	if e == nil {
+		return
+	}
+
+	e.once.Do(func() {
+		ev := e.ev
+		ev.Time = e.startedAt.UTC()
+		ev.RecordCount = e.recordCount.Load()
+		ev.DurationMS = time.Since(e.startedAt).Milliseconds()
+
+		switch {
+		case err == nil:
+			ev.Outcome = OutcomeSuccess
+		case errors.Is(err, context.Canceled):
+			ev.Outcome = OutcomeCanceled
+		default:
+			ev.Outcome = OutcomeError
+			ev.Error = err.Error()
+		}
+
+		if err := e.logger.sink.Write(&ev); err != nil {
+			zlog.Error().Err(err).Str("operation", ev.Operation).Msg("failed to write audit event")
+		}
+	})
+}
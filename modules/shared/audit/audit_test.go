@@ -0,0 +1,242 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	ktesting "k8s.io/client-go/testing"
+
+	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+
+	identify := func(ctx context.Context) (string, []string) {
+		return "alice", []string{"dev"}
+	}
+	logger := NewLogger("dashboard", NewWriterSink(&buf), identify)
+
+	entry := logger.Start(context.Background(), Event{
+		Operation: "logRecordsFetch",
+		Sources:   []string{"default:pods/web"},
+		Grep:      "error",
+	})
+	entry.SetNamespaces([]string{"default"})
+	entry.SetTimeRange(time.Unix(1, 0), time.Time{})
+	entry.AddRecords(3)
+	entry.AddRecords(2)
+	entry.Finish(nil)
+
+	// Subsequent calls are ignored
+	entry.Finish(errors.New("ignored"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 1)
+
+	var ev Event
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &ev))
+	assert.Equal(t, "dashboard", ev.App)
+	assert.Equal(t, "logRecordsFetch", ev.Operation)
+	assert.Equal(t, "alice", ev.User)
+	assert.Equal(t, []string{"dev"}, ev.Groups)
+	assert.Equal(t, []string{"default:pods/web"}, ev.Sources)
+	assert.Equal(t, []string{"default"}, ev.Namespaces)
+	assert.True(t, ev.Since.Equal(time.Unix(1, 0)))
+	assert.Equal(t, int64(5), ev.RecordCount)
+	assert.Equal(t, OutcomeSuccess, ev.Outcome)
+	assert.False(t, ev.Time.IsZero())
+}
+
+func TestOutcome(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantOutcome string
+		wantError   string
+	}{
+		{"success", nil, OutcomeSuccess, ""},
+		{"canceled", context.Canceled, OutcomeCanceled, ""},
+		{"error", errors.New("boom"), OutcomeError, "boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewLogger("cluster-api", NewWriterSink(&buf), nil)
+			logger.Start(context.Background(), Event{Operation: "x"}).Finish(tt.err)
+
+			var ev Event
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &ev))
+			assert.Equal(t, tt.wantOutcome, ev.Outcome)
+			assert.Equal(t, tt.wantError, ev.Error)
+		})
+	}
+}
+
+func TestNilLogger(t *testing.T) {
+	var logger *Logger
+
+	entry := logger.Start(context.Background(), Event{Operation: "x"})
+	assert.Nil(t, entry)
+
+	// No-ops
+	entry.SetNamespaces([]string{"default"})
+	entry.SetTimeRange(time.Now(), time.Now())
+	entry.AddRecords(1)
+	entry.Finish(nil)
+	assert.NoError(t, logger.Close())
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+
+	sink, err := NewFileSink(path)
+	require.NoError(t, err)
+
+	logger := NewLogger("dashboard", sink, nil)
+	logger.Start(context.Background(), Event{Operation: "a"}).Finish(nil)
+	logger.Start(context.Background(), Event{Operation: "b"}).Finish(nil)
+	require.NoError(t, logger.Close())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	assert.Len(t, lines, 2)
+}
+
+func TestWebhookSink(t *testing.T) {
+	var mu sync.Mutex
+	received := []Event{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var ev Event
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&ev))
+
+		mu.Lock()
+		received = append(received, ev)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(server.URL, nil)
+	require.NoError(t, err)
+
+	logger := NewLogger("cluster-api", sink, nil)
+	logger.Start(context.Background(), Event{Operation: "logRecordsFollow"}).Finish(nil)
+
+	// Close flushes queued events
+	require.NoError(t, logger.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 1)
+	assert.Equal(t, "logRecordsFollow", received[0].Operation)
+}
+
+func TestWebhookSinkWriteAfterClose(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(server.URL, nil)
+	require.NoError(t, err)
+	require.NoError(t, sink.Close())
+
+	// Late events (e.g. from hijacked connections) are dropped
+	assert.NotPanics(t, func() {
+		assert.Error(t, sink.Write(&Event{Operation: "logRecordsFollow"}))
+	})
+	assert.NoError(t, sink.Close())
+}
+
+func TestNewSink(t *testing.T) {
+	_, err := NewSink(SinkFile, "", "")
+	assert.Error(t, err)
+
+	_, err = NewSink(SinkWebhook, "", "")
+	assert.Error(t, err)
+
+	_, err = NewSink("xxx", "", "")
+	assert.Error(t, err)
+
+	sink, err := NewSink(SinkStdout, "", "")
+	require.NoError(t, err)
+	assert.NoError(t, sink.Close())
+}
+
+func TestTokenReviewIdentifier(t *testing.T) {
+	numReviews := 0
+
+	clientset := fake.NewSimpleClientset()
+	clientset.Fake.PrependReactor("create", "tokenreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		numReviews += 1
+		obj := action.(ktesting.CreateAction).GetObject().(*authv1.TokenReview)
+		if obj.Spec.Token == "alice-token" {
+			obj.Status = authv1.TokenReviewStatus{Authenticated: true, User: authv1.UserInfo{Username: "alice", Groups: []string{"dev"}}}
+		}
+		return true, obj, nil
+	})
+
+	identify := NewTokenReviewIdentifier(func() (kubernetes.Interface, error) {
+		return clientset, nil
+	})
+
+	// No credentials
+	user, groups := identify(context.Background())
+	assert.Equal(t, "", user)
+	assert.Nil(t, groups)
+
+	// Valid token (cached after first call)
+	ctx := context.WithValue(context.Background(), k8shelpers.K8STokenCtxKey, "alice-token")
+	for range 2 {
+		user, groups = identify(ctx)
+		assert.Equal(t, "alice", user)
+		assert.Equal(t, []string{"dev"}, groups)
+	}
+	assert.Equal(t, 1, numReviews)
+
+	// Invalid token
+	ctx = context.WithValue(context.Background(), k8shelpers.K8STokenCtxKey, "bad-token")
+	user, _ = identify(ctx)
+	assert.Equal(t, "unknown", user)
+
+	// Impersonated user
+	ctx = context.WithValue(context.Background(), k8shelpers.ImpersonateCtxKey, &rest.ImpersonationConfig{UserName: "bob", Groups: []string{"ops"}})
+	user, groups = identify(ctx)
+	assert.Equal(t, "bob", user)
+	assert.Equal(t, []string{"ops"}, groups)
+}
@@ -0,0 +1,110 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"strings"
+	"time"
+
+	zlog "github.com/rs/zerolog/log"
+	authv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/util"
+)
+
+// How long TokenReview results are cached
+const identityCacheTTL = 5 * time.Minute
+
+// UnknownUser is returned when a token can't be resolved
+const UnknownUser = "unknown"
+
+// Represents cached TokenReview result
+type identity struct {
+	user       string
+	groups     []string
+	expiration time.Time
+}
+
+// NewTokenReviewIdentifier returns an IdentifyFunc that resolves the bearer
+// token in the request context using a TokenReview. Impersonated identities
+// are returned as-is and requests without credentials return an empty user.
+func NewTokenReviewIdentifier(getClientset func() (kubernetes.Interface, error)) IdentifyFunc {
+	cache := util.SyncMap[[sha256.Size]byte, identity]{}
+
+	return func(ctx context.Context) (string, []string) {
+		if imp, ok := ctx.Value(k8shelpers.ImpersonateCtxKey).(*rest.ImpersonationConfig); ok && imp != nil && imp.UserName != "" {
+			return imp.UserName, imp.Groups
+		}
+
+		token, _ := ctx.Value(k8shelpers.K8STokenCtxKey).(string)
+		token = strings.TrimSpace(token)
+		if token == "" {
+			return "", nil
+		}
+
+		// Check cache
+		key := sha256.Sum256([]byte(token))
+		if val, ok := cache.Load(key); ok && time.Now().Before(val.expiration) {
+			return val.user, val.groups
+		}
+
+		clientset, err := getClientset()
+		if err != nil {
+			zlog.Error().Err(err).Msg("audit: unable to get clientset")
+			return UnknownUser, nil
+		}
+
+		tokenReview := &authv1.TokenReview{
+			Spec: authv1.TokenReviewSpec{
+				Token: token,
+			},
+		}
+
+		// Use new context so the review isn't sent with the user's credentials
+		reviewCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := clientset.AuthenticationV1().TokenReviews().Create(reviewCtx, tokenReview, metav1.CreateOptions{})
+		if err != nil {
+			zlog.Error().Err(err).Msg("audit: token review failed")
+			return UnknownUser, nil
+		}
+
+		id := identity{user: UnknownUser, expiration: time.Now().Add(identityCacheTTL)}
+		if result.Status.Authenticated {
+			id.user = result.Status.User.Username
+			id.groups = result.Status.User.Groups
+		}
+
+		// Drop expired entries
+		now := time.Now()
+		cache.Range(func(k [sha256.Size]byte, v identity) bool {
+			if now.After(v.expiration) {
+				cache.Delete(k)
+			}
+			return true
+		})
+
+		cache.Store(key, id)
+
+		return id.user, id.groups
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	zlog "github.com/rs/zerolog/log"
+)
+
+// Sink types
+const (
+	SinkStdout  = "stdout"
+	SinkFile    = "file"
+	SinkWebhook = "webhook"
+)
+
+// Number of events buffered by the webhook sink before new ones are dropped
+const webhookBufferSize = 1024
+
+// Represents an audit event destination
+type Sink interface {
+	Write(ev *Event) error
+	Close() error
+}
+
+// NewSink returns a sink for the given type
+func NewSink(sinkType string, path string, webhookURL string) (Sink, error) {
+	switch sinkType {
+	case SinkStdout, "":
+		return NewWriterSink(os.Stdout), nil
+	case SinkFile:
+		return NewFileSink(path)
+	case SinkWebhook:
+		return NewWebhookSink(webhookURL, nil)
+	default:
+		return nil, fmt.Errorf("audit sink not supported: %s", sinkType)
+	}
+}
+
+// Represents sink that writes JSON lines to an io.Writer
+type WriterSink struct {
+	w   io.Writer
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// Create new WriterSink instance
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w, enc: json.NewEncoder(w)}
+}
+
+// Write event as a single JSON line
+func (s *WriterSink) Write(ev *Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(ev)
+}
+
+// Close underlying writer if it isn't stdout/stderr
+func (s *WriterSink) Close() error {
+	if c, ok := s.w.(io.Closer); ok && s.w != os.Stdout && s.w != os.Stderr {
+		return c.Close()
+	}
+	return nil
+}
+
+// NewFileSink returns sink that appends JSON lines to the file at `path`
+func NewFileSink(path string) (*WriterSink, error) {
+	if path == "" {
+		return nil, fmt.Errorf("audit file sink requires a path")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWriterSink(f), nil
+}
+
+// Represents sink that POSTs events to a webhook. Events are sent from a
+// background goroutine so slow receivers don't block queries.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+	ch     chan *Event
+	doneCh chan struct{}
+	mu     sync.RWMutex
+	closed bool
+	once   sync.Once
+}
+
+// Create new WebhookSink instance. If `client` is nil a default client is used.
+func NewWebhookSink(url string, client *http.Client) (*WebhookSink, error) {
+	if url == "" {
+		return nil, fmt.Errorf("audit webhook sink requires a url")
+	}
+
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	s := &WebhookSink{
+		url:    url,
+		client: client,
+		ch:     make(chan *Event, webhookBufferSize),
+		doneCh: make(chan struct{}),
+	}
+
+	go s.run()
+
+	return s, nil
+}
+
+// Queue event for delivery. Events written after Close are dropped.
+func (s *WebhookSink) Write(ev *Event) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return fmt.Errorf("audit webhook sink closed, event dropped")
+	}
+
+	select {
+	case s.ch <- ev:
+		return nil
+	default:
+		return fmt.Errorf("audit webhook buffer full, event dropped")
+	}
+}
+
+// Flush queued events and stop background goroutine
+func (s *WebhookSink) Close() error {
+	s.once.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		close(s.ch)
+		s.mu.Unlock()
+	})
+	<-s.doneCh
+	return nil
+}
+
+// Deliver events until channel is closed
+func (s *WebhookSink) run() {
+	defer close(s.doneCh)
+
+	for ev := range s.ch {
+		if err := s.post(ev); err != nil {
+			zlog.Error().Err(err).Str("operation", ev.Operation).Msg("failed to deliver audit event")
+		}
+	}
+}
+
+// Send single event
+func (s *WebhookSink) post(ev *Event) error {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("audit webhook returned %s", resp.Status)
+	}
+
+	return nil
+}
@@ -89,6 +89,67 @@ func (FollowFrom) EnumDescriptor() ([]byte, []int) {
 	return file_cluster_agent_proto_rawDescGZIP(), []int{0}
 }
 
+// Comparison performed by a field predicate.
+type FieldOperator int32
+
+const (
+	// Field value is equal to the predicate value.
+	FieldOperator_EQUALS FieldOperator = 0
+	// Field is missing or its value is not equal to the predicate value.
+	FieldOperator_NOT_EQUALS FieldOperator = 1
+	// Field value contains the predicate value.
+	FieldOperator_CONTAINS FieldOperator = 2
+	// Field value matches the predicate value as a regular expression.
+	FieldOperator_MATCHES FieldOperator = 3
+	// Field is present.
+	FieldOperator_EXISTS FieldOperator = 4
+)
+
+// Enum value maps for FieldOperator.
+var (
+	FieldOperator_name = map[int32]string{
+		0: "EQUALS",
+		1: "NOT_EQUALS",
+		2: "CONTAINS",
+		3: "MATCHES",
+		4: "EXISTS",
+	}
+	FieldOperator_value = map[string]int32{
+		"EQUALS":     0,
+		"NOT_EQUALS": 1,
+		"CONTAINS":   2,
+		"MATCHES":    3,
+		"EXISTS":     4,
+	}
+)
+
+func (x FieldOperator) Enum() *FieldOperator {
+	p := new(FieldOperator)
+	*p = x
+	return p
+}
+
+func (x FieldOperator) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FieldOperator) Descriptor() protoreflect.EnumDescriptor {
+	return file_cluster_agent_proto_enumTypes[1].Descriptor()
+}
+
+func (FieldOperator) Type() protoreflect.EnumType {
+	return &file_cluster_agent_proto_enumTypes[1]
+}
+
+func (x FieldOperator) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FieldOperator.Descriptor instead.
+func (FieldOperator) EnumDescriptor() ([]byte, []int) {
+	return file_cluster_agent_proto_rawDescGZIP(), []int{1}
+}
+
 // Metadata for a log file.
 type LogMetadata struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -486,7 +547,9 @@ type LogRecordsStreamRequest struct {
 	// Filter the logs according to this grep.
 	Grep string `protobuf:"bytes,7,opt,name=grep,proto3" json:"grep,omitempty"`
 	// Keep the stream connection open to keep receiving new log lines.
-	FollowFrom    FollowFrom `protobuf:"varint,8,opt,name=follow_from,json=followFrom,proto3,enum=cluster_agent.FollowFrom" json:"follow_from,omitempty"`
+	FollowFrom FollowFrom `protobuf:"varint,8,opt,name=follow_from,json=followFrom,proto3,enum=cluster_agent.FollowFrom" json:"follow_from,omitempty"`
+	// Filter the logs according to these structured predicates.
+	Filter        *LogRecordsFilter `protobuf:"bytes,9,opt,name=filter,proto3" json:"filter,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -577,6 +640,356 @@ func (x *LogRecordsStreamRequest) GetFollowFrom() FollowFrom {
 	return FollowFrom_NOOP
 }
 
+func (x *LogRecordsStreamRequest) GetFilter() *LogRecordsFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+// A request to count log lines.
+type LogRecordsCountRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// K8s namepsace.
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// K8s pod name.
+	PodName       string `protobuf:"bytes,2,opt,name=pod_name,json=podName,proto3" json:"pod_name,omitempty"`
+	ContainerName string `protobuf:"bytes,3,opt,name=container_name,json=containerName,proto3" json:"container_name,omitempty"`
+	ContainerId   string `protobuf:"bytes,4,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+	// Count only the logs that happened after this timestamp.
+	StartTime string `protobuf:"bytes,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// Count only the logs that happened before this timestamp.
+	StopTime string `protobuf:"bytes,6,opt,name=stop_time,json=stopTime,proto3" json:"stop_time,omitempty"`
+	// Filter the logs according to this grep.
+	Grep string `protobuf:"bytes,7,opt,name=grep,proto3" json:"grep,omitempty"`
+	// Filter the logs according to these structured predicates.
+	Filter *LogRecordsFilter `protobuf:"bytes,8,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Width of each time bucket in seconds. If zero, all matches are counted
+	// in a single bucket.
+	BucketSeconds int64 `protobuf:"varint,9,opt,name=bucket_seconds,json=bucketSeconds,proto3" json:"bucket_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogRecordsCountRequest) Reset() {
+	*x = LogRecordsCountRequest{}
+	mi := &file_cluster_agent_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogRecordsCountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogRecordsCountRequest) ProtoMessage() {}
+
+func (x *LogRecordsCountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cluster_agent_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogRecordsCountRequest.ProtoReflect.Descriptor instead.
+func (*LogRecordsCountRequest) Descriptor() ([]byte, []int) {
+	return file_cluster_agent_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *LogRecordsCountRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *LogRecordsCountRequest) GetPodName() string {
+	if x != nil {
+		return x.PodName
+	}
+	return ""
+}
+
+func (x *LogRecordsCountRequest) GetContainerName() string {
+	if x != nil {
+		return x.ContainerName
+	}
+	return ""
+}
+
+func (x *LogRecordsCountRequest) GetContainerId() string {
+	if x != nil {
+		return x.ContainerId
+	}
+	return ""
+}
+
+func (x *LogRecordsCountRequest) GetStartTime() string {
+	if x != nil {
+		return x.StartTime
+	}
+	return ""
+}
+
+func (x *LogRecordsCountRequest) GetStopTime() string {
+	if x != nil {
+		return x.StopTime
+	}
+	return ""
+}
+
+func (x *LogRecordsCountRequest) GetGrep() string {
+	if x != nil {
+		return x.Grep
+	}
+	return ""
+}
+
+func (x *LogRecordsCountRequest) GetFilter() *LogRecordsFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *LogRecordsCountRequest) GetBucketSeconds() int64 {
+	if x != nil {
+		return x.BucketSeconds
+	}
+	return 0
+}
+
+type LogRecordsCountBucket struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Start of the bucket. Buckets are aligned to multiples of the bucket width
+	// since the Unix epoch. Unset if the request has no bucket width.
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogRecordsCountBucket) Reset() {
+	*x = LogRecordsCountBucket{}
+	mi := &file_cluster_agent_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogRecordsCountBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogRecordsCountBucket) ProtoMessage() {}
+
+func (x *LogRecordsCountBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_cluster_agent_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogRecordsCountBucket.ProtoReflect.Descriptor instead.
+func (*LogRecordsCountBucket) Descriptor() ([]byte, []int) {
+	return file_cluster_agent_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *LogRecordsCountBucket) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *LogRecordsCountBucket) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type LogRecordsCountResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Non-empty buckets in chronological order.
+	Buckets       []*LogRecordsCountBucket `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogRecordsCountResponse) Reset() {
+	*x = LogRecordsCountResponse{}
+	mi := &file_cluster_agent_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogRecordsCountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogRecordsCountResponse) ProtoMessage() {}
+
+func (x *LogRecordsCountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cluster_agent_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogRecordsCountResponse.ProtoReflect.Descriptor instead.
+func (*LogRecordsCountResponse) Descriptor() ([]byte, []int) {
+	return file_cluster_agent_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *LogRecordsCountResponse) GetBuckets() []*LogRecordsCountBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+// Structured filters evaluated against each log line on the node.
+type LogRecordsFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Return only the lines whose JSON or logfmt fields match all of these.
+	Fields []*FieldPredicate `protobuf:"bytes,1,rep,name=fields,proto3" json:"fields,omitempty"`
+	// Drop the lines that match any of these regular expressions.
+	Exclude []string `protobuf:"bytes,2,rep,name=exclude,proto3" json:"exclude,omitempty"`
+	// Return only the lines whose level is one of these (case-insensitive).
+	Levels        []string `protobuf:"bytes,3,rep,name=levels,proto3" json:"levels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogRecordsFilter) Reset() {
+	*x = LogRecordsFilter{}
+	mi := &file_cluster_agent_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogRecordsFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogRecordsFilter) ProtoMessage() {}
+
+func (x *LogRecordsFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_cluster_agent_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogRecordsFilter.ProtoReflect.Descriptor instead.
+func (*LogRecordsFilter) Descriptor() ([]byte, []int) {
+	return file_cluster_agent_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *LogRecordsFilter) GetFields() []*FieldPredicate {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *LogRecordsFilter) GetExclude() []string {
+	if x != nil {
+		return x.Exclude
+	}
+	return nil
+}
+
+func (x *LogRecordsFilter) GetLevels() []string {
+	if x != nil {
+		return x.Levels
+	}
+	return nil
+}
+
+// A predicate on a field of a JSON or logfmt log line.
+type FieldPredicate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Field name. Dots descend into nested JSON objects.
+	Field string        `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Op    FieldOperator `protobuf:"varint,2,opt,name=op,proto3,enum=cluster_agent.FieldOperator" json:"op,omitempty"`
+	// Value to compare with. Ignored by EXISTS.
+	Value         string `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldPredicate) Reset() {
+	*x = FieldPredicate{}
+	mi := &file_cluster_agent_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldPredicate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldPredicate) ProtoMessage() {}
+
+func (x *FieldPredicate) ProtoReflect() protoreflect.Message {
+	mi := &file_cluster_agent_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldPredicate.ProtoReflect.Descriptor instead.
+func (*FieldPredicate) Descriptor() ([]byte, []int) {
+	return file_cluster_agent_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FieldPredicate) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldPredicate) GetOp() FieldOperator {
+	if x != nil {
+		return x.Op
+	}
+	return FieldOperator_EQUALS
+}
+
+func (x *FieldPredicate) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
 // A log record
 type LogRecord struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -589,7 +1002,7 @@ type LogRecord struct {
 
 func (x *LogRecord) Reset() {
 	*x = LogRecord{}
-	mi := &file_cluster_agent_proto_msgTypes[8]
+	mi := &file_cluster_agent_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -601,7 +1014,7 @@ func (x *LogRecord) String() string {
 func (*LogRecord) ProtoMessage() {}
 
 func (x *LogRecord) ProtoReflect() protoreflect.Message {
-	mi := &file_cluster_agent_proto_msgTypes[8]
+	mi := &file_cluster_agent_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -614,7 +1027,7 @@ func (x *LogRecord) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogRecord.ProtoReflect.Descriptor instead.
 func (*LogRecord) Descriptor() ([]byte, []int) {
-	return file_cluster_agent_proto_rawDescGZIP(), []int{8}
+	return file_cluster_agent_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *LogRecord) GetTimestamp() *timestamppb.Timestamp {
@@ -638,6 +1051,52 @@ func (x *LogRecord) GetIsFinal() bool {
 	return false
 }
 
+// A group of consecutive log lines from a single stream. Batches are flushed
+// when they reach a size limit or when their oldest line has waited too long.
+type LogRecordBatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*LogRecord           `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogRecordBatch) Reset() {
+	*x = LogRecordBatch{}
+	mi := &file_cluster_agent_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogRecordBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogRecordBatch) ProtoMessage() {}
+
+func (x *LogRecordBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_cluster_agent_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogRecordBatch.ProtoReflect.Descriptor instead.
+func (*LogRecordBatch) Descriptor() ([]byte, []int) {
+	return file_cluster_agent_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *LogRecordBatch) GetRecords() []*LogRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
 var File_cluster_agent_proto protoreflect.FileDescriptor
 
 const file_cluster_agent_proto_rawDesc = "" +
@@ -668,7 +1127,7 @@ const file_cluster_agent_proto_rawDesc = "" +
 	"namespaces\"_\n" +
 	"\x15LogMetadataWatchEvent\x12\x12\n" +
 	"\x04type\x18\x01 \x01(\tR\x04type\x122\n" +
-	"\x06object\x18\x02 \x01(\v2\x1a.cluster_agent.LogMetadataR\x06object\"\xa8\x02\n" +
+	"\x06object\x18\x02 \x01(\v2\x1a.cluster_agent.LogMetadataR\x06object\"\xe1\x02\n" +
 	"\x17LogRecordsStreamRequest\x12\x1c\n" +
 	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x19\n" +
 	"\bpod_name\x18\x02 \x01(\tR\apodName\x12%\n" +
@@ -679,22 +1138,61 @@ const file_cluster_agent_proto_rawDesc = "" +
 	"\tstop_time\x18\x06 \x01(\tR\bstopTime\x12\x12\n" +
 	"\x04grep\x18\a \x01(\tR\x04grep\x12:\n" +
 	"\vfollow_from\x18\b \x01(\x0e2\x19.cluster_agent.FollowFromR\n" +
-	"followFrom\"z\n" +
+	"followFrom\x127\n" +
+	"\x06filter\x18\t \x01(\v2\x1f.cluster_agent.LogRecordsFilterR\x06filter\"\xcb\x02\n" +
+	"\x16LogRecordsCountRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x19\n" +
+	"\bpod_name\x18\x02 \x01(\tR\apodName\x12%\n" +
+	"\x0econtainer_name\x18\x03 \x01(\tR\rcontainerName\x12!\n" +
+	"\fcontainer_id\x18\x04 \x01(\tR\vcontainerId\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x05 \x01(\tR\tstartTime\x12\x1b\n" +
+	"\tstop_time\x18\x06 \x01(\tR\bstopTime\x12\x12\n" +
+	"\x04grep\x18\a \x01(\tR\x04grep\x127\n" +
+	"\x06filter\x18\b \x01(\v2\x1f.cluster_agent.LogRecordsFilterR\x06filter\x12%\n" +
+	"\x0ebucket_seconds\x18\t \x01(\x03R\rbucketSeconds\"_\n" +
+	"\x15LogRecordsCountBucket\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"Y\n" +
+	"\x17LogRecordsCountResponse\x12>\n" +
+	"\abuckets\x18\x01 \x03(\v2$.cluster_agent.LogRecordsCountBucketR\abuckets\"{\n" +
+	"\x10LogRecordsFilter\x125\n" +
+	"\x06fields\x18\x01 \x03(\v2\x1d.cluster_agent.FieldPredicateR\x06fields\x12\x18\n" +
+	"\aexclude\x18\x02 \x03(\tR\aexclude\x12\x16\n" +
+	"\x06levels\x18\x03 \x03(\tR\x06levels\"j\n" +
+	"\x0eFieldPredicate\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12,\n" +
+	"\x02op\x18\x02 \x01(\x0e2\x1c.cluster_agent.FieldOperatorR\x02op\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\"z\n" +
 	"\tLogRecord\x128\n" +
 	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x19\n" +
-	"\bis_final\x18\x03 \x01(\bR\aisFinal*,\n" +
+	"\bis_final\x18\x03 \x01(\bR\aisFinal\"D\n" +
+	"\x0eLogRecordBatch\x122\n" +
+	"\arecords\x18\x01 \x03(\v2\x18.cluster_agent.LogRecordR\arecords*,\n" +
 	"\n" +
 	"FollowFrom\x12\b\n" +
 	"\x04NOOP\x10\x00\x12\v\n" +
 	"\aDEFAULT\x10\x01\x12\a\n" +
-	"\x03END\x10\x022\xbc\x01\n" +
+	"\x03END\x10\x02*R\n" +
+	"\rFieldOperator\x12\n" +
+	"\n" +
+	"\x06EQUALS\x10\x00\x12\x0e\n" +
+	"\n" +
+	"NOT_EQUALS\x10\x01\x12\f\n" +
+	"\bCONTAINS\x10\x02\x12\v\n" +
+	"\aMATCHES\x10\x03\x12\n" +
+	"\n" +
+	"\x06EXISTS\x10\x042\xbc\x01\n" +
 	"\x12LogMetadataService\x12M\n" +
 	"\x04List\x12%.cluster_agent.LogMetadataListRequest\x1a\x1e.cluster_agent.LogMetadataList\x12W\n" +
-	"\x05Watch\x12&.cluster_agent.LogMetadataWatchRequest\x1a$.cluster_agent.LogMetadataWatchEvent0\x012\xbe\x01\n" +
+	"\x05Watch\x12&.cluster_agent.LogMetadataWatchRequest\x1a$.cluster_agent.LogMetadataWatchEvent0\x012\xd5\x03\n" +
 	"\x11LogRecordsService\x12S\n" +
 	"\rStreamForward\x12&.cluster_agent.LogRecordsStreamRequest\x1a\x18.cluster_agent.LogRecord0\x01\x12T\n" +
-	"\x0eStreamBackward\x12&.cluster_agent.LogRecordsStreamRequest\x1a\x18.cluster_agent.LogRecord0\x01B\x17Z\x15shared/clusteragentpbb\x06proto3"
+	"\x0eStreamBackward\x12&.cluster_agent.LogRecordsStreamRequest\x1a\x18.cluster_agent.LogRecord0\x01\x12]\n" +
+	"\x12StreamForwardBatch\x12&.cluster_agent.LogRecordsStreamRequest\x1a\x1d.cluster_agent.LogRecordBatch0\x01\x12^\n" +
+	"\x13StreamBackwardBatch\x12&.cluster_agent.LogRecordsStreamRequest\x1a\x1d.cluster_agent.LogRecordBatch0\x01\x12V\n" +
+	"\x05Count\x12%.cluster_agent.LogRecordsCountRequest\x1a&.cluster_agent.LogRecordsCountResponseB\x17Z\x15shared/clusteragentpbb\x06proto3"
 
 var (
 	file_cluster_agent_proto_rawDescOnce sync.Once
@@ -708,42 +1206,62 @@ func file_cluster_agent_proto_rawDescGZIP() []byte {
 	return file_cluster_agent_proto_rawDescData
 }
 
-var file_cluster_agent_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_cluster_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_cluster_agent_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_cluster_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_cluster_agent_proto_goTypes = []any{
 	(FollowFrom)(0),                 // 0: cluster_agent.FollowFrom
-	(*LogMetadata)(nil),             // 1: cluster_agent.LogMetadata
-	(*LogMetadataFileInfo)(nil),     // 2: cluster_agent.LogMetadataFileInfo
-	(*LogMetadataSpec)(nil),         // 3: cluster_agent.LogMetadataSpec
-	(*LogMetadataList)(nil),         // 4: cluster_agent.LogMetadataList
-	(*LogMetadataListRequest)(nil),  // 5: cluster_agent.LogMetadataListRequest
-	(*LogMetadataWatchRequest)(nil), // 6: cluster_agent.LogMetadataWatchRequest
-	(*LogMetadataWatchEvent)(nil),   // 7: cluster_agent.LogMetadataWatchEvent
-	(*LogRecordsStreamRequest)(nil), // 8: cluster_agent.LogRecordsStreamRequest
-	(*LogRecord)(nil),               // 9: cluster_agent.LogRecord
-	(*timestamppb.Timestamp)(nil),   // 10: google.protobuf.Timestamp
+	(FieldOperator)(0),              // 1: cluster_agent.FieldOperator
+	(*LogMetadata)(nil),             // 2: cluster_agent.LogMetadata
+	(*LogMetadataFileInfo)(nil),     // 3: cluster_agent.LogMetadataFileInfo
+	(*LogMetadataSpec)(nil),         // 4: cluster_agent.LogMetadataSpec
+	(*LogMetadataList)(nil),         // 5: cluster_agent.LogMetadataList
+	(*LogMetadataListRequest)(nil),  // 6: cluster_agent.LogMetadataListRequest
+	(*LogMetadataWatchRequest)(nil), // 7: cluster_agent.LogMetadataWatchRequest
+	(*LogMetadataWatchEvent)(nil),   // 8: cluster_agent.LogMetadataWatchEvent
+	(*LogRecordsStreamRequest)(nil), // 9: cluster_agent.LogRecordsStreamRequest
+	(*LogRecordsCountRequest)(nil),  // 10: cluster_agent.LogRecordsCountRequest
+	(*LogRecordsCountBucket)(nil),   // 11: cluster_agent.LogRecordsCountBucket
+	(*LogRecordsCountResponse)(nil), // 12: cluster_agent.LogRecordsCountResponse
+	(*LogRecordsFilter)(nil),        // 13: cluster_agent.LogRecordsFilter
+	(*FieldPredicate)(nil),          // 14: cluster_agent.FieldPredicate
+	(*LogRecord)(nil),               // 15: cluster_agent.LogRecord
+	(*LogRecordBatch)(nil),          // 16: cluster_agent.LogRecordBatch
+	(*timestamppb.Timestamp)(nil),   // 17: google.protobuf.Timestamp
 }
 var file_cluster_agent_proto_depIdxs = []int32{
-	3,  // 0: cluster_agent.LogMetadata.spec:type_name -> cluster_agent.LogMetadataSpec
-	2,  // 1: cluster_agent.LogMetadata.fileInfo:type_name -> cluster_agent.LogMetadataFileInfo
-	10, // 2: cluster_agent.LogMetadataFileInfo.last_modified_at:type_name -> google.protobuf.Timestamp
-	1,  // 3: cluster_agent.LogMetadataList.items:type_name -> cluster_agent.LogMetadata
-	1,  // 4: cluster_agent.LogMetadataWatchEvent.object:type_name -> cluster_agent.LogMetadata
+	4,  // 0: cluster_agent.LogMetadata.spec:type_name -> cluster_agent.LogMetadataSpec
+	3,  // 1: cluster_agent.LogMetadata.fileInfo:type_name -> cluster_agent.LogMetadataFileInfo
+	17, // 2: cluster_agent.LogMetadataFileInfo.last_modified_at:type_name -> google.protobuf.Timestamp
+	2,  // 3: cluster_agent.LogMetadataList.items:type_name -> cluster_agent.LogMetadata
+	2,  // 4: cluster_agent.LogMetadataWatchEvent.object:type_name -> cluster_agent.LogMetadata
 	0,  // 5: cluster_agent.LogRecordsStreamRequest.follow_from:type_name -> cluster_agent.FollowFrom
-	10, // 6: cluster_agent.LogRecord.timestamp:type_name -> google.protobuf.Timestamp
-	5,  // 7: cluster_agent.LogMetadataService.List:input_type -> cluster_agent.LogMetadataListRequest
-	6,  // 8: cluster_agent.LogMetadataService.Watch:input_type -> cluster_agent.LogMetadataWatchRequest
-	8,  // 9: cluster_agent.LogRecordsService.StreamForward:input_type -> cluster_agent.LogRecordsStreamRequest
-	8,  // 10: cluster_agent.LogRecordsService.StreamBackward:input_type -> cluster_agent.LogRecordsStreamRequest
-	4,  // 11: cluster_agent.LogMetadataService.List:output_type -> cluster_agent.LogMetadataList
-	7,  // 12: cluster_agent.LogMetadataService.Watch:output_type -> cluster_agent.LogMetadataWatchEvent
-	9,  // 13: cluster_agent.LogRecordsService.StreamForward:output_type -> cluster_agent.LogRecord
-	9,  // 14: cluster_agent.LogRecordsService.StreamBackward:output_type -> cluster_agent.LogRecord
-	11, // [11:15] is the sub-list for method output_type
-	7,  // [7:11] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	13, // 6: cluster_agent.LogRecordsStreamRequest.filter:type_name -> cluster_agent.LogRecordsFilter
+	13, // 7: cluster_agent.LogRecordsCountRequest.filter:type_name -> cluster_agent.LogRecordsFilter
+	17, // 8: cluster_agent.LogRecordsCountBucket.start:type_name -> google.protobuf.Timestamp
+	11, // 9: cluster_agent.LogRecordsCountResponse.buckets:type_name -> cluster_agent.LogRecordsCountBucket
+	14, // 10: cluster_agent.LogRecordsFilter.fields:type_name -> cluster_agent.FieldPredicate
+	1,  // 11: cluster_agent.FieldPredicate.op:type_name -> cluster_agent.FieldOperator
+	17, // 12: cluster_agent.LogRecord.timestamp:type_name -> google.protobuf.Timestamp
+	15, // 13: cluster_agent.LogRecordBatch.records:type_name -> cluster_agent.LogRecord
+	6,  // 14: cluster_agent.LogMetadataService.List:input_type -> cluster_agent.LogMetadataListRequest
+	7,  // 15: cluster_agent.LogMetadataService.Watch:input_type -> cluster_agent.LogMetadataWatchRequest
+	9,  // 16: cluster_agent.LogRecordsService.StreamForward:input_type -> cluster_agent.LogRecordsStreamRequest
+	9,  // 17: cluster_agent.LogRecordsService.StreamBackward:input_type -> cluster_agent.LogRecordsStreamRequest
+	9,  // 18: cluster_agent.LogRecordsService.StreamForwardBatch:input_type -> cluster_agent.LogRecordsStreamRequest
+	9,  // 19: cluster_agent.LogRecordsService.StreamBackwardBatch:input_type -> cluster_agent.LogRecordsStreamRequest
+	10, // 20: cluster_agent.LogRecordsService.Count:input_type -> cluster_agent.LogRecordsCountRequest
+	5,  // 21: cluster_agent.LogMetadataService.List:output_type -> cluster_agent.LogMetadataList
+	8,  // 22: cluster_agent.LogMetadataService.Watch:output_type -> cluster_agent.LogMetadataWatchEvent
+	15, // 23: cluster_agent.LogRecordsService.StreamForward:output_type -> cluster_agent.LogRecord
+	15, // 24: cluster_agent.LogRecordsService.StreamBackward:output_type -> cluster_agent.LogRecord
+	16, // 25: cluster_agent.LogRecordsService.StreamForwardBatch:output_type -> cluster_agent.LogRecordBatch
+	16, // 26: cluster_agent.LogRecordsService.StreamBackwardBatch:output_type -> cluster_agent.LogRecordBatch
+	12, // 27: cluster_agent.LogRecordsService.Count:output_type -> cluster_agent.LogRecordsCountResponse
+	21, // [21:28] is the sub-list for method output_type
+	14, // [14:21] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_cluster_agent_proto_init() }
@@ -756,8 +1274,8 @@ func file_cluster_agent_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_cluster_agent_proto_rawDesc), len(file_cluster_agent_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   9,
+			NumEnums:      2,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
@@ -185,8 +185,11 @@ var LogMetadataService_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	LogRecordsService_StreamForward_FullMethodName  = "/cluster_agent.LogRecordsService/StreamForward"
-	LogRecordsService_StreamBackward_FullMethodName = "/cluster_agent.LogRecordsService/StreamBackward"
+	LogRecordsService_StreamForward_FullMethodName       = "/cluster_agent.LogRecordsService/StreamForward"
+	LogRecordsService_StreamBackward_FullMethodName      = "/cluster_agent.LogRecordsService/StreamBackward"
+	LogRecordsService_StreamForwardBatch_FullMethodName  = "/cluster_agent.LogRecordsService/StreamForwardBatch"
+	LogRecordsService_StreamBackwardBatch_FullMethodName = "/cluster_agent.LogRecordsService/StreamBackwardBatch"
+	LogRecordsService_Count_FullMethodName               = "/cluster_agent.LogRecordsService/Count"
 )
 
 // LogRecordsServiceClient is the client API for LogRecordsService service.
@@ -200,6 +203,12 @@ type LogRecordsServiceClient interface {
 	StreamForward(ctx context.Context, in *LogRecordsStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogRecord], error)
 	// Streams log lines from new to old ones.
 	StreamBackward(ctx context.Context, in *LogRecordsStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogRecord], error)
+	// Same as StreamForward but sends several log lines per message.
+	StreamForwardBatch(ctx context.Context, in *LogRecordsStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogRecordBatch], error)
+	// Same as StreamBackward but sends several log lines per message.
+	StreamBackwardBatch(ctx context.Context, in *LogRecordsStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogRecordBatch], error)
+	// Counts matching log lines per time bucket without returning them.
+	Count(ctx context.Context, in *LogRecordsCountRequest, opts ...grpc.CallOption) (*LogRecordsCountResponse, error)
 }
 
 type logRecordsServiceClient struct {
@@ -248,6 +257,54 @@ func (c *logRecordsServiceClient) StreamBackward(ctx context.Context, in *LogRec
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type LogRecordsService_StreamBackwardClient = grpc.ServerStreamingClient[LogRecord]
 
+func (c *logRecordsServiceClient) StreamForwardBatch(ctx context.Context, in *LogRecordsStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogRecordBatch], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LogRecordsService_ServiceDesc.Streams[2], LogRecordsService_StreamForwardBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[LogRecordsStreamRequest, LogRecordBatch]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LogRecordsService_StreamForwardBatchClient = grpc.ServerStreamingClient[LogRecordBatch]
+
+func (c *logRecordsServiceClient) StreamBackwardBatch(ctx context.Context, in *LogRecordsStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogRecordBatch], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LogRecordsService_ServiceDesc.Streams[3], LogRecordsService_StreamBackwardBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[LogRecordsStreamRequest, LogRecordBatch]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LogRecordsService_StreamBackwardBatchClient = grpc.ServerStreamingClient[LogRecordBatch]
+
+func (c *logRecordsServiceClient) Count(ctx context.Context, in *LogRecordsCountRequest, opts ...grpc.CallOption) (*LogRecordsCountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogRecordsCountResponse)
+	err := c.cc.Invoke(ctx, LogRecordsService_Count_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LogRecordsServiceServer is the server API for LogRecordsService service.
 // All implementations must embed UnimplementedLogRecordsServiceServer
 // for forward compatibility.
@@ -259,6 +316,12 @@ type LogRecordsServiceServer interface {
 	StreamForward(*LogRecordsStreamRequest, grpc.ServerStreamingServer[LogRecord]) error
 	// Streams log lines from new to old ones.
 	StreamBackward(*LogRecordsStreamRequest, grpc.ServerStreamingServer[LogRecord]) error
+	// Same as StreamForward but sends several log lines per message.
+	StreamForwardBatch(*LogRecordsStreamRequest, grpc.ServerStreamingServer[LogRecordBatch]) error
+	// Same as StreamBackward but sends several log lines per message.
+	StreamBackwardBatch(*LogRecordsStreamRequest, grpc.ServerStreamingServer[LogRecordBatch]) error
+	// Counts matching log lines per time bucket without returning them.
+	Count(context.Context, *LogRecordsCountRequest) (*LogRecordsCountResponse, error)
 	mustEmbedUnimplementedLogRecordsServiceServer()
 }
 
@@ -275,6 +338,15 @@ func (UnimplementedLogRecordsServiceServer) StreamForward(*LogRecordsStreamReque
 func (UnimplementedLogRecordsServiceServer) StreamBackward(*LogRecordsStreamRequest, grpc.ServerStreamingServer[LogRecord]) error {
 	return status.Errorf(codes.Unimplemented, "method StreamBackward not implemented")
 }
+func (UnimplementedLogRecordsServiceServer) StreamForwardBatch(*LogRecordsStreamRequest, grpc.ServerStreamingServer[LogRecordBatch]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamForwardBatch not implemented")
+}
+func (UnimplementedLogRecordsServiceServer) StreamBackwardBatch(*LogRecordsStreamRequest, grpc.ServerStreamingServer[LogRecordBatch]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamBackwardBatch not implemented")
+}
+func (UnimplementedLogRecordsServiceServer) Count(context.Context, *LogRecordsCountRequest) (*LogRecordsCountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Count not implemented")
+}
 func (UnimplementedLogRecordsServiceServer) mustEmbedUnimplementedLogRecordsServiceServer() {}
 func (UnimplementedLogRecordsServiceServer) testEmbeddedByValue()                           {}
 
@@ -318,13 +390,58 @@ func _LogRecordsService_StreamBackward_Handler(srv interface{}, stream grpc.Serv
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type LogRecordsService_StreamBackwardServer = grpc.ServerStreamingServer[LogRecord]
 
+func _LogRecordsService_StreamForwardBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LogRecordsStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LogRecordsServiceServer).StreamForwardBatch(m, &grpc.GenericServerStream[LogRecordsStreamRequest, LogRecordBatch]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LogRecordsService_StreamForwardBatchServer = grpc.ServerStreamingServer[LogRecordBatch]
+
+func _LogRecordsService_StreamBackwardBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LogRecordsStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LogRecordsServiceServer).StreamBackwardBatch(m, &grpc.GenericServerStream[LogRecordsStreamRequest, LogRecordBatch]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LogRecordsService_StreamBackwardBatchServer = grpc.ServerStreamingServer[LogRecordBatch]
+
+func _LogRecordsService_Count_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogRecordsCountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogRecordsServiceServer).Count(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogRecordsService_Count_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogRecordsServiceServer).Count(ctx, req.(*LogRecordsCountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // LogRecordsService_ServiceDesc is the grpc.ServiceDesc for LogRecordsService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var LogRecordsService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "cluster_agent.LogRecordsService",
 	HandlerType: (*LogRecordsServiceServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Count",
+			Handler:    _LogRecordsService_Count_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "StreamForward",
@@ -336,6 +453,16 @@ var LogRecordsService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _LogRecordsService_StreamBackward_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StreamForwardBatch",
+			Handler:       _LogRecordsService_StreamForwardBatch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamBackwardBatch",
+			Handler:       _LogRecordsService_StreamBackwardBatch_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "cluster_agent.proto",
 }
@@ -0,0 +1,298 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"k8s.io/client-go/rest"
+
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+)
+
+const DefaultNamespace = "kubetail-system"
+const DefaultServiceName = "kubetail-cluster-api"
+
+const logUsageSummaryQuery = `
+query LogUsageSummary($namespace: String, $groupBy: LogUsageGroupBy) {
+  logUsageSummary(namespace: $namespace, groupBy: $groupBy) {
+    namespace
+    workloadKind
+    workloadName
+    nodeName
+    containerName
+    bytes
+    bytesPerSecond
+    fileCount
+  }
+}
+`
+
+// Client executes queries against the Cluster API
+type Client interface {
+	LogUsageSummary(ctx context.Context, kubeContext string, namespace *string, groupBy logs.UsageGroupBy) ([]logs.UsageGroup, error)
+	Shutdown()
+}
+
+// Create new Client instance depending on environment
+func NewClient(cfg *config.Config, cm k8shelpers.ConnectionManager) (Client, error) {
+	switch cfg.Dashboard.Environment {
+	case config.EnvironmentDesktop:
+		c := NewDesktopClient(cm, DefaultNamespace, DefaultServiceName)
+		c.useTLS = cfg.Dashboard.ClusterAPITLS.Enabled
+		return c, nil
+	case config.EnvironmentCluster:
+		if cfg.Dashboard.ClusterAPIEndpoint == "" {
+			return nil, fmt.Errorf("cluster-api endpoint not configured")
+		}
+		tlsCfg, err := NewTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		c, err := NewInClusterClient(cfg.Dashboard.ClusterAPIEndpoint, tlsCfg)
+		if err != nil {
+			return nil, err
+		}
+		c.impersonate = k8shelpers.ImpersonationFromConfig(cfg)
+		return c, nil
+	default:
+		return nil, fmt.Errorf("env not supported: %s", cfg.Dashboard.Environment)
+	}
+}
+
+// Represents DesktopClient
+type DesktopClient struct {
+	cm          k8shelpers.ConnectionManager
+	namespace   string
+	serviceName string
+	useTLS      bool
+	tokens      *ServiceAccountTokenCache
+}
+
+// LogUsageSummary
+func (c *DesktopClient) LogUsageSummary(ctx context.Context, kubeContext string, namespace *string, groupBy logs.UsageGroupBy) ([]logs.UsageGroup, error) {
+	body, err := newLogUsageSummaryRequest(namespace, groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := c.do(ctx, kubeContext, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseLogUsageSummaryResponse(respBody)
+}
+
+// Shutdown
+func (c *DesktopClient) Shutdown() {
+	c.tokens.Shutdown()
+}
+
+// Execute request using the Kubernetes API service proxy
+func (c *DesktopClient) do(ctx context.Context, kubeContext string, body []byte) ([]byte, error) {
+	clientset, err := c.cm.GetOrCreateClientset(kubeContext)
+	if err != nil {
+		return nil, err
+	}
+
+	restConfig, err := c.cm.GetOrCreateRestConfig(kubeContext)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get service-account-token
+	token, err := c.tokens.Token(ctx, kubeContext, c.namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	req := clientset.CoreV1().RESTClient().Post().
+		AbsPath("/api/v1/namespaces", c.namespace, "services", ServiceProxyName(c.serviceName, c.useTLS), "proxy", "graphql").
+		SetHeader("Content-Type", "application/json").
+		SetHeader("X-Forwarded-Authorization", fmt.Sprintf("Bearer %s", token))
+
+	// Forward identity
+	header := http.Header{}
+	k8shelpers.SetForwardedImpersonationHeaders(header, k8shelpers.EffectiveImpersonation(ctx, restConfig.Impersonate))
+	for k, v := range header {
+		req.SetHeader(k, v...)
+	}
+
+	return req.Body(body).DoRaw(ctx)
+}
+
+// Create new DesktopClient instance
+func NewDesktopClient(cm k8shelpers.ConnectionManager, namespace string, serviceName string) *DesktopClient {
+	return &DesktopClient{
+		cm:          cm,
+		namespace:   namespace,
+		serviceName: serviceName,
+		tokens:      NewServiceAccountTokenCache(cm),
+	}
+}
+
+// Represents InClusterClient
+type InClusterClient struct {
+	endpointUrl *url.URL
+	httpClient  *http.Client
+	impersonate rest.ImpersonationConfig
+}
+
+// LogUsageSummary
+func (c *InClusterClient) LogUsageSummary(ctx context.Context, _kubeContext string, namespace *string, groupBy logs.UsageGroupBy) ([]logs.UsageGroup, error) {
+	body, err := newLogUsageSummaryRequest(namespace, groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := c.do(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseLogUsageSummaryResponse(respBody)
+}
+
+// Shutdown
+func (c *InClusterClient) Shutdown() {
+}
+
+// Execute request against the Cluster API endpoint
+func (c *InClusterClient) do(ctx context.Context, body []byte) ([]byte, error) {
+	u := *c.endpointUrl
+	u.Path = path.Join("/", u.Path, "graphql")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// Forward identity
+	k8shelpers.SetForwardedImpersonationHeaders(req.Header, k8shelpers.EffectiveImpersonation(ctx, c.impersonate))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cluster-api request failed: %s", resp.Status)
+	}
+
+	return respBody, nil
+}
+
+// Create new InClusterClient instance
+func NewInClusterClient(clusterAPIEndpoint string, tlsCfg *tls.Config) (*InClusterClient, error) {
+	// Init service account token round tripper
+	rt, err := k8shelpers.NewInClusterSATRoundTripper(NewTransport(tlsCfg))
+	if err != nil {
+		return nil, err
+	}
+
+	return newInClusterClient(clusterAPIEndpoint, &http.Client{Transport: otelhttp.NewTransport(rt)})
+}
+
+// Create new InClusterClient instance with custom http client
+func newInClusterClient(clusterAPIEndpoint string, httpClient *http.Client) (*InClusterClient, error) {
+	endpointUrl, err := url.Parse(clusterAPIEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InClusterClient{endpointUrl: endpointUrl, httpClient: httpClient}, nil
+}
+
+// Represents a GraphQL request
+type graphqlRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// Represents a GraphQL response
+type graphqlResponse[T any] struct {
+	Data   T `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Represents a log usage group on the wire
+type logUsageGroup struct {
+	Namespace      string   `json:"namespace"`
+	WorkloadKind   string   `json:"workloadKind"`
+	WorkloadName   string   `json:"workloadName"`
+	NodeName       string   `json:"nodeName"`
+	ContainerName  string   `json:"containerName"`
+	Bytes          int64    `json:"bytes,string"`
+	BytesPerSecond *float64 `json:"bytesPerSecond"`
+	FileCount      int      `json:"fileCount"`
+}
+
+// Return request body for logUsageSummary query
+func newLogUsageSummaryRequest(namespace *string, groupBy logs.UsageGroupBy) ([]byte, error) {
+	return json.Marshal(graphqlRequest{
+		Query: logUsageSummaryQuery,
+		Variables: map[string]any{
+			"namespace": namespace,
+			"groupBy":   groupBy,
+		},
+	})
+}
+
+// Parse response body of logUsageSummary query
+func parseLogUsageSummaryResponse(body []byte) ([]logs.UsageGroup, error) {
+	var resp graphqlResponse[struct {
+		LogUsageSummary []logUsageGroup `json:"logUsageSummary"`
+	}]
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+
+	if len(resp.Errors) > 0 {
+		errs := make([]error, len(resp.Errors))
+		for i, e := range resp.Errors {
+			errs[i] = errors.New(e.Message)
+		}
+		return nil, errors.Join(errs...)
+	}
+
+	out := make([]logs.UsageGroup, len(resp.Data.LogUsageSummary))
+	for i, g := range resp.Data.LogUsageSummary {
+		out[i] = logs.UsageGroup(g)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+)
+
+func TestInClusterClientLogUsageSummary(t *testing.T) {
+	var gotReq graphqlRequest
+	var gotPath string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		w.Write([]byte(`{"data":{"logUsageSummary":[{"namespace":"ns1","workloadKind":"Deployment","workloadName":"web","nodeName":"","containerName":"","bytes":"1024","bytesPerSecond":2.5,"fileCount":3}]}}`))
+	}))
+	defer srv.Close()
+
+	c, err := newInClusterClient(srv.URL, srv.Client())
+	require.NoError(t, err)
+
+	groups, err := c.LogUsageSummary(context.Background(), "", ptr.To("ns1"), logs.UsageGroupByWorkload)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/graphql", gotPath)
+	assert.Equal(t, "ns1", gotReq.Variables["namespace"])
+	assert.Equal(t, "WORKLOAD", gotReq.Variables["groupBy"])
+
+	require.Len(t, groups, 1)
+	assert.Equal(t, logs.UsageGroup{
+		Namespace:      "ns1",
+		WorkloadKind:   "Deployment",
+		WorkloadName:   "web",
+		Bytes:          1024,
+		BytesPerSecond: ptr.To(2.5),
+		FileCount:      3,
+	}, groups[0])
+}
+
+func TestInClusterClientImpersonation(t *testing.T) {
+	var gotHeader http.Header
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Clone()
+		w.Write([]byte(`{"data":{"logUsageSummary":[]}}`))
+	}))
+	defer srv.Close()
+
+	c, err := newInClusterClient(srv.URL, srv.Client())
+	require.NoError(t, err)
+	c.impersonate = rest.ImpersonationConfig{UserName: "bob", Groups: []string{"ops"}}
+
+	// Static identity
+	_, err = c.LogUsageSummary(context.Background(), "", nil, logs.UsageGroupByWorkload)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", gotHeader.Get(k8shelpers.ForwardedImpersonateUserHeader))
+	assert.Equal(t, []string{"ops"}, gotHeader.Values(k8shelpers.ForwardedImpersonateGroupHeader))
+
+	// Request-scoped identity
+	ctx := context.WithValue(context.Background(), k8shelpers.ImpersonateCtxKey, &rest.ImpersonationConfig{UserName: "alice"})
+	_, err = c.LogUsageSummary(ctx, "", nil, logs.UsageGroupByWorkload)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", gotHeader.Get(k8shelpers.ForwardedImpersonateUserHeader))
+	assert.Empty(t, gotHeader.Values(k8shelpers.ForwardedImpersonateGroupHeader))
+}
+
+func TestInClusterClientErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		setStatus  int
+		setBody    string
+		wantErrMsg string
+	}{
+		{"graphql error", http.StatusOK, `{"errors":[{"message":"Forbidden"}],"data":{"logUsageSummary":null}}`, "Forbidden"},
+		{"http error", http.StatusUnauthorized, ``, "401 Unauthorized"},
+		{"invalid json", http.StatusOK, `xxx`, "invalid character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.setStatus)
+				w.Write([]byte(tt.setBody))
+			}))
+			defer srv.Close()
+
+			c, err := newInClusterClient(srv.URL, srv.Client())
+			require.NoError(t, err)
+
+			_, err = c.LogUsageSummary(context.Background(), "", nil, logs.UsageGroupByNode)
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+)
+
+// NewTLSConfig returns the tls config for connecting to the Cluster API or
+// nil if tls is disabled. If no CA bundle is configured the server is
+// verified using the system roots.
+func NewTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	opts := cfg.Dashboard.ClusterAPITLS
+	if !opts.Enabled {
+		return nil, nil
+	}
+
+	tlsCfg := &tls.Config{
+		ServerName: opts.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	// Client cert for mTLS
+	if opts.CertFile != "" {
+		clientCert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.Certificates = []tls.Certificate{clientCert}
+	}
+
+	// Root CA for server verification
+	if opts.CAFile != "" {
+		caPem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(caPem) {
+			return nil, fmt.Errorf("no certificates found in %s", opts.CAFile)
+		}
+		tlsCfg.RootCAs = roots
+	}
+
+	return tlsCfg, nil
+}
+
+// NewTransport returns the transport for connecting to the Cluster API
+// using the given tls config (if any)
+func NewTransport(tlsCfg *tls.Config) http.RoundTripper {
+	if tlsCfg == nil {
+		return http.DefaultTransport
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = tlsCfg
+	return t
+}
+
+// ServiceProxyName returns the service name used in Kubernetes API service
+// proxy requests. The API server doesn't present client certificates or
+// verify the service certificate when proxying so only the scheme changes.
+func ServiceProxyName(serviceName string, useTLS bool) string {
+	if useTLS {
+		return fmt.Sprintf("https:%s:http", serviceName)
+	}
+	return fmt.Sprintf("%s:http", serviceName)
+}
@@ -0,0 +1,129 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterapi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+	"github.com/kubetail-org/kubetail/modules/shared/testutils"
+)
+
+func TestNewTLSConfig(t *testing.T) {
+	certs := testutils.NewTestCerts(t)
+
+	t.Run("disabled", func(t *testing.T) {
+		tlsCfg, err := NewTLSConfig(config.DefaultConfig())
+		require.NoError(t, err)
+		assert.Nil(t, tlsCfg)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		cfg := config.DefaultConfig()
+		cfg.Dashboard.ClusterAPITLS.Enabled = true
+		cfg.Dashboard.ClusterAPITLS.CertFile = certs.ClientCertFile
+		cfg.Dashboard.ClusterAPITLS.KeyFile = certs.ClientKeyFile
+		cfg.Dashboard.ClusterAPITLS.CAFile = certs.CAFile
+		cfg.Dashboard.ClusterAPITLS.ServerName = "localhost"
+
+		tlsCfg, err := NewTLSConfig(cfg)
+		require.NoError(t, err)
+		assert.Len(t, tlsCfg.Certificates, 1)
+		assert.NotNil(t, tlsCfg.RootCAs)
+		assert.Equal(t, "localhost", tlsCfg.ServerName)
+	})
+
+	t.Run("invalid ca file", func(t *testing.T) {
+		cfg := config.DefaultConfig()
+		cfg.Dashboard.ClusterAPITLS.Enabled = true
+		cfg.Dashboard.ClusterAPITLS.CAFile = certs.ClientKeyFile
+
+		_, err := NewTLSConfig(cfg)
+		assert.Error(t, err)
+	})
+}
+
+func TestServiceProxyName(t *testing.T) {
+	assert.Equal(t, "kubetail-cluster-api:http", ServiceProxyName("kubetail-cluster-api", false))
+	assert.Equal(t, "https:kubetail-cluster-api:http", ServiceProxyName("kubetail-cluster-api", true))
+}
+
+func TestInClusterClientMutualTLS(t *testing.T) {
+	certs := testutils.NewTestCerts(t)
+
+	// Init server that requires client certs signed by the test CA
+	caPem, err := os.ReadFile(certs.CAFile)
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	require.True(t, clientCAs.AppendCertsFromPEM(caPem))
+
+	serverCert, err := tls.LoadX509KeyPair(certs.ServerCertFile, certs.ServerKeyFile)
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"logUsageSummary":[]}}`))
+	}))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	tests := []struct {
+		name          string
+		setClientCert bool
+		wantErr       bool
+	}{
+		{"with client cert", true, false},
+		{"without client cert", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Dashboard.ClusterAPITLS.Enabled = true
+			cfg.Dashboard.ClusterAPITLS.CAFile = certs.CAFile
+			if tt.setClientCert {
+				cfg.Dashboard.ClusterAPITLS.CertFile = certs.ClientCertFile
+				cfg.Dashboard.ClusterAPITLS.KeyFile = certs.ClientKeyFile
+			}
+
+			tlsCfg, err := NewTLSConfig(cfg)
+			require.NoError(t, err)
+
+			c, err := newInClusterClient(srv.URL, &http.Client{Transport: NewTransport(tlsCfg)})
+			require.NoError(t, err)
+
+			_, err = c.LogUsageSummary(context.Background(), "", nil, logs.UsageGroupByWorkload)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
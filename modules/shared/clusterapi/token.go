@@ -0,0 +1,90 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterapi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+)
+
+// Name of service account used to authenticate with the Cluster API through
+// the Kubernetes API service proxy
+const serviceAccountName = "kubetail-cli"
+
+// ServiceAccountTokenCache creates and caches the service-account-tokens
+// used to authenticate with the Cluster API through the Kubernetes API
+// service proxy
+type ServiceAccountTokenCache struct {
+	cm         k8shelpers.ConnectionManager
+	cache      map[string]*k8shelpers.ServiceAccountToken
+	mu         sync.Mutex
+	shutdownCh chan struct{}
+}
+
+// Token returns a token for the Cluster API service account in the given
+// kube context and namespace
+func (c *ServiceAccountTokenCache) Token(ctx context.Context, kubeContext string, namespace string) (string, error) {
+	sat, err := c.getOrCreate(ctx, kubeContext, namespace)
+	if err != nil {
+		return "", err
+	}
+	return sat.Token(ctx)
+}
+
+// Shutdown stops background token refreshes
+func (c *ServiceAccountTokenCache) Shutdown() {
+	close(c.shutdownCh)
+}
+
+// Get or create service-account-token
+func (c *ServiceAccountTokenCache) getOrCreate(ctx context.Context, kubeContext string, namespace string) (*k8shelpers.ServiceAccountToken, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Generate cache key
+	k := fmt.Sprintf("%s/%s", kubeContext, namespace)
+
+	// Check cache
+	sat, exists := c.cache[k]
+	if !exists {
+		clientset, err := c.cm.GetOrCreateClientset(kubeContext)
+		if err != nil {
+			return nil, err
+		}
+
+		// Initialize new service-account-token
+		sat, err = k8shelpers.NewServiceAccountToken(ctx, clientset, namespace, serviceAccountName, c.shutdownCh)
+		if err != nil {
+			return nil, err
+		}
+
+		// Add to cache
+		c.cache[k] = sat
+	}
+
+	return sat, nil
+}
+
+// Create new ServiceAccountTokenCache instance
+func NewServiceAccountTokenCache(cm k8shelpers.ConnectionManager) *ServiceAccountTokenCache {
+	return &ServiceAccountTokenCache{
+		cm:         cm,
+		cache:      make(map[string]*k8shelpers.ServiceAccountToken),
+		shutdownCh: make(chan struct{}),
+	}
+}
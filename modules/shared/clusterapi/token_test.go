@@ -0,0 +1,71 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterapi
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	authv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	k8shelpersmock "github.com/kubetail-org/kubetail/modules/shared/k8shelpers/mock"
+)
+
+func TestServiceAccountTokenCache(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	// Count token requests
+	var numRequests atomic.Int32
+	clientset.Fake.PrependReactor("create", "serviceaccounts", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		numRequests.Add(1)
+		return true, &authv1.TokenRequest{
+			Status: authv1.TokenRequestStatus{
+				Token:               "mock-token",
+				ExpirationTimestamp: metav1.NewTime(time.Now().Add(time.Hour)),
+			},
+		}, nil
+	})
+
+	cm := &k8shelpersmock.MockConnectionManager{}
+	cm.On("GetOrCreateClientset", mock.Anything).Return(clientset, nil)
+
+	c := NewServiceAccountTokenCache(cm)
+	defer c.Shutdown()
+
+	// Tokens are cached per kube context and namespace
+	for range 2 {
+		token, err := c.Token(context.Background(), "ctx1", DefaultNamespace)
+		require.NoError(t, err)
+		assert.Equal(t, "mock-token", token)
+	}
+	assert.Equal(t, int32(1), numRequests.Load())
+
+	_, err := c.Token(context.Background(), "ctx1", "other")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), numRequests.Load())
+
+	_, err = c.Token(context.Background(), "ctx2", DefaultNamespace)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), numRequests.Load())
+}
@@ -39,8 +39,18 @@ import (
 type AuthMode string
 
 const (
-	AuthModeAuto  AuthMode = "auto"
-	AuthModeToken AuthMode = "token"
+	AuthModeAuto   AuthMode = "auto"
+	AuthModeToken  AuthMode = "token"
+	AuthModeOIDC   AuthMode = "oidc"
+	AuthModeHeader AuthMode = "header"
+)
+
+// OIDC token-mode
+type OIDCTokenMode string
+
+const (
+	OIDCTokenModePassthrough OIDCTokenMode = "passthrough"
+	OIDCTokenModeImpersonate OIDCTokenMode = "impersonate"
 )
 
 // Environment
@@ -62,14 +72,89 @@ type Config struct {
 		AuthMode           AuthMode `mapstructure:"auth-mode"`
 		BasePath           string   `mapstructure:"base-path"`
 		ClusterAPIEndpoint string   `mapstructure:"cluster-api-endpoint"`
+		DataDir            string   `mapstructure:"data-dir"`
 		GinMode            string   `mapstructure:"gin-mode" validate:"omitempty,oneof=debug release"`
 		Environment        Environment
 
+		// audit-log options
+		Audit struct {
+			// enable audit log
+			Enabled bool
+
+			// destination (stdout, file or webhook)
+			Sink string `validate:"omitempty,oneof=stdout file webhook"`
+
+			// path of file (for "file" sink)
+			File string
+
+			// receiver url (for "webhook" sink)
+			WebhookURL string `mapstructure:"webhook-url" validate:"omitempty,url"`
+		}
+
 		// csrf options
 		CSRF struct {
 			Enabled bool
 		}
 
+		// prometheus metrics options
+		Metrics struct {
+			// serve metrics at `/metrics`
+			Enabled bool
+		}
+
+		// OpenTelemetry tracing options
+		Tracing struct {
+			// enable tracing
+			Enabled bool
+
+			// span exporter (otlp-grpc, otlp-http or stdout)
+			Exporter string `validate:"omitempty,oneof=otlp-grpc otlp-http stdout"`
+
+			// collector endpoint (host:port)
+			Endpoint string
+
+			// headers sent with each export request
+			Headers map[string]string
+
+			// fraction of new traces to sample
+			SamplingRatio float64 `mapstructure:"sampling-ratio" validate:"gte=0,lte=1"`
+
+			// collector tls options
+			TLS struct {
+				Enabled  bool
+				CAFile   string `mapstructure:"ca-file" validate:"omitempty,file"`
+				CertFile string `mapstructure:"cert-file" validate:"omitempty,file"`
+				KeyFile  string `mapstructure:"key-file" validate:"omitempty,file"`
+			}
+		}
+
+		// per-user quotas for log queries (0 means unlimited)
+		Quotas struct {
+			// max number of concurrent log subscriptions
+			MaxConcurrentSubscriptions int `mapstructure:"max-concurrent-subscriptions" validate:"gte=0"`
+
+			// max number of concurrent log fetches
+			MaxConcurrentFetches int `mapstructure:"max-concurrent-fetches" validate:"gte=0"`
+
+			// max number of log queries per minute
+			RequestsPerMinute int `mapstructure:"requests-per-minute" validate:"gte=0"`
+
+			// max number of sources per log stream
+			MaxSourcesPerStream int `mapstructure:"max-sources-per-stream" validate:"gte=0"`
+		}
+
+		// graphql query limits (0 means unlimited)
+		GraphQL struct {
+			// max complexity score per operation
+			MaxComplexity int `mapstructure:"max-complexity" validate:"gte=0"`
+
+			// max selection depth per operation
+			MaxDepth int `mapstructure:"max-depth" validate:"gte=0"`
+
+			// max number of aliased fields per operation
+			MaxAliases int `mapstructure:"max-aliases" validate:"gte=0"`
+		}
+
 		// logging options
 		Logging struct {
 			// enable logging
@@ -91,6 +176,55 @@ type Config struct {
 			} `mapstructure:"access-log"`
 		}
 
+		// OIDC options (used when auth-mode is "oidc")
+		OIDC struct {
+			// Issuer URL used for discovery
+			IssuerURL string `mapstructure:"issuer-url" validate:"omitempty,url"`
+
+			// OAuth2 client credentials
+			ClientID     string `mapstructure:"client-id"`
+			ClientSecret string `mapstructure:"client-secret"`
+
+			// Absolute URL of the callback endpoint (e.g. https://kubetail.example.com/api/auth/oidc/callback)
+			RedirectURL string `mapstructure:"redirect-url" validate:"omitempty,url"`
+
+			// Scopes requested in addition to "openid"
+			Scopes []string
+
+			// Claim mapped to the Kubernetes username and optional prefix
+			UsernameClaim  string `mapstructure:"username-claim"`
+			UsernamePrefix string `mapstructure:"username-prefix"`
+
+			// Claim mapped to the Kubernetes groups and optional prefix
+			GroupsClaim  string `mapstructure:"groups-claim"`
+			GroupsPrefix string `mapstructure:"groups-prefix"`
+
+			// How requests are made to the Kubernetes API ("passthrough" or "impersonate")
+			TokenMode OIDCTokenMode `mapstructure:"token-mode" validate:"omitempty,oneof=passthrough impersonate"`
+
+			// CA bundle used to connect to the issuer
+			CAFile string `mapstructure:"ca-file" validate:"omitempty,file"`
+		}
+
+		// Trusted header options (used when auth-mode is "header")
+		TrustedHeader struct {
+			// Headers containing the authenticated user's name, groups and uid
+			User   string
+			Groups string
+			UID    string `mapstructure:"uid"`
+
+			// CIDRs of proxies allowed to set the headers
+			TrustedProxies []string `mapstructure:"trusted-proxies" validate:"dive,cidr"`
+		} `mapstructure:"trusted-header"`
+
+		// Identity used for all Kubernetes and Cluster API requests that
+		// aren't made on behalf of an authenticated user
+		Impersonate struct {
+			User   string
+			UID    string `mapstructure:"uid"`
+			Groups []string
+		}
+
 		// session options
 		Session struct {
 			Secret string
@@ -119,6 +253,24 @@ type Config struct {
 			KeyFile string `mapstructure:"key-file" validate:"omitempty,file"`
 		}
 
+		// Cluster API connection options
+		ClusterAPITLS struct {
+			// Enable tls when connecting to Cluster API
+			Enabled bool
+
+			// Client certificate file (for mTLS)
+			CertFile string `mapstructure:"cert-file" validate:"omitempty,file"`
+
+			// Client certificate key file (for mTLS)
+			KeyFile string `mapstructure:"key-file" validate:"omitempty,file"`
+
+			// CA bundle file used to verify the server
+			CAFile string `mapstructure:"ca-file" validate:"omitempty,file"`
+
+			// Server name used to verify the server certificate
+			ServerName string `mapstructure:"server-name"`
+		} `mapstructure:"cluster-api-tls"`
+
 		// UI optins
 		UI struct {
 			ClusterAPIEnabled bool `mapstructure:"cluster-api-enabled"`
@@ -131,11 +283,115 @@ type Config struct {
 		GinMode  string `mapstructure:"gin-mode" validate:"omitempty,oneof=debug release"`
 		BasePath string `mapstructure:"base-path"`
 
+		// audit-log options
+		Audit struct {
+			// enable audit log
+			Enabled bool
+
+			// destination (stdout, file or webhook)
+			Sink string `validate:"omitempty,oneof=stdout file webhook"`
+
+			// path of file (for "file" sink)
+			File string
+
+			// receiver url (for "webhook" sink)
+			WebhookURL string `mapstructure:"webhook-url" validate:"omitempty,url"`
+		}
+
 		// csrf options
 		CSRF struct {
 			Enabled bool
 		}
 
+		// prometheus metrics options
+		Metrics struct {
+			// serve metrics at `/metrics`
+			Enabled bool
+		}
+
+		// OpenTelemetry tracing options
+		Tracing struct {
+			// enable tracing
+			Enabled bool
+
+			// span exporter (otlp-grpc, otlp-http or stdout)
+			Exporter string `validate:"omitempty,oneof=otlp-grpc otlp-http stdout"`
+
+			// collector endpoint (host:port)
+			Endpoint string
+
+			// headers sent with each export request
+			Headers map[string]string
+
+			// fraction of new traces to sample
+			SamplingRatio float64 `mapstructure:"sampling-ratio" validate:"gte=0,lte=1"`
+
+			// collector tls options
+			TLS struct {
+				Enabled  bool
+				CAFile   string `mapstructure:"ca-file" validate:"omitempty,file"`
+				CertFile string `mapstructure:"cert-file" validate:"omitempty,file"`
+				KeyFile  string `mapstructure:"key-file" validate:"omitempty,file"`
+			}
+		}
+
+		// per-user quotas for log queries (0 means unlimited)
+		Quotas struct {
+			// max number of concurrent log subscriptions
+			MaxConcurrentSubscriptions int `mapstructure:"max-concurrent-subscriptions" validate:"gte=0"`
+
+			// max number of concurrent log fetches
+			MaxConcurrentFetches int `mapstructure:"max-concurrent-fetches" validate:"gte=0"`
+
+			// max number of log queries per minute
+			RequestsPerMinute int `mapstructure:"requests-per-minute" validate:"gte=0"`
+
+			// max number of sources per log stream
+			MaxSourcesPerStream int `mapstructure:"max-sources-per-stream" validate:"gte=0"`
+		}
+
+		// recent-records cache options
+		RecordCache struct {
+			// enable cache
+			Enabled bool
+
+			// max number of records kept per container
+			MaxRecords int `mapstructure:"max-records" validate:"gte=0"`
+
+			// max number of containers cached at the same time
+			MaxContainers int `mapstructure:"max-containers" validate:"gte=0"`
+		} `mapstructure:"record-cache"`
+
+		// forward records from selected sources to an external endpoint
+		Forwarder struct {
+			// enable forwarder
+			Enabled bool
+
+			// destination url (e.g. otlp://otel-collector:4317 or loki=http://loki:3100)
+			Endpoint string
+
+			// headers sent with each export request
+			Headers map[string]string
+
+			// source paths to follow (e.g. default:deployments/web)
+			Sources []string
+
+			// only forward records that match this regular expression
+			Grep string
+		}
+
+		// graphql query limits (0 means unlimited)
+		GraphQL struct {
+			// max complexity score per operation
+			MaxComplexity int `mapstructure:"max-complexity" validate:"gte=0"`
+
+			// max selection depth per operation
+			MaxDepth int `mapstructure:"max-depth" validate:"gte=0"`
+
+			// max number of aliased fields per operation
+			MaxAliases int `mapstructure:"max-aliases" validate:"gte=0"`
+		}
+
 		// Cluster Agent connection options
 		ClusterAgent struct {
 			DispatchUrl string `mapstructure:"dispatch-url"`
@@ -179,6 +435,12 @@ type Config struct {
 
 			// TLS certificate key file
 			KeyFile string `mapstructure:"key-file" validate:"omitempty,file"`
+
+			// CA bundle file used to verify the client
+			CAFile string `mapstructure:"ca-file" validate:"omitempty,file"`
+
+			// Client certificate authentication behavior
+			ClientAuth tls.ClientAuthType `mapstructure:"client-auth"`
 		}
 	} `mapstructure:"cluster-api"`
 
@@ -229,7 +491,94 @@ type Config struct {
 
 // Validate config
 func (cfg *Config) validate() error {
-	return validator.New().Struct(cfg)
+	if err := validator.New().Struct(cfg); err != nil {
+		return err
+	}
+
+	// Check required oidc options
+	if cfg.Dashboard.AuthMode == AuthModeOIDC {
+		oidc := cfg.Dashboard.OIDC
+		if oidc.IssuerURL == "" || oidc.ClientID == "" || oidc.RedirectURL == "" {
+			return fmt.Errorf("auth-mode oidc requires issuer-url, client-id and redirect-url")
+		}
+	}
+
+	// Check trusted header options
+	if cfg.Dashboard.AuthMode == AuthModeHeader {
+		th := cfg.Dashboard.TrustedHeader
+		if th.User == "" || len(th.TrustedProxies) == 0 {
+			return fmt.Errorf("auth-mode header requires trusted-header user and trusted-proxies")
+		}
+	}
+
+	// Check impersonation options
+	if imp := cfg.Dashboard.Impersonate; imp.User == "" && (imp.UID != "" || len(imp.Groups) > 0) {
+		return fmt.Errorf("impersonate uid and groups require user")
+	}
+
+	// Check tls options
+	switch cfg.ClusterAPI.TLS.ClientAuth {
+	case tls.VerifyClientCertIfGiven, tls.RequireAndVerifyClientCert:
+		if cfg.ClusterAPI.TLS.CAFile == "" {
+			return fmt.Errorf("cluster-api tls client-auth %s requires ca-file", cfg.ClusterAPI.TLS.ClientAuth)
+		}
+	}
+
+	clusterAPITLS := cfg.Dashboard.ClusterAPITLS
+	if (clusterAPITLS.CertFile == "") != (clusterAPITLS.KeyFile == "") {
+		return fmt.Errorf("dashboard cluster-api-tls requires both cert-file and key-file")
+	}
+
+	// Check audit sink options
+	for _, audit := range []struct {
+		Enabled                bool
+		Sink, File, WebhookURL string
+	}{
+		{cfg.Dashboard.Audit.Enabled, cfg.Dashboard.Audit.Sink, cfg.Dashboard.Audit.File, cfg.Dashboard.Audit.WebhookURL},
+		{cfg.ClusterAPI.Audit.Enabled, cfg.ClusterAPI.Audit.Sink, cfg.ClusterAPI.Audit.File, cfg.ClusterAPI.Audit.WebhookURL},
+	} {
+		if !audit.Enabled {
+			continue
+		}
+		if audit.Sink == "file" && audit.File == "" {
+			return fmt.Errorf("audit sink file requires file")
+		}
+		if audit.Sink == "webhook" && audit.WebhookURL == "" {
+			return fmt.Errorf("audit sink webhook requires webhook-url")
+		}
+	}
+
+	// Check forwarder options
+	if fwd := cfg.ClusterAPI.Forwarder; fwd.Enabled {
+		if fwd.Endpoint == "" {
+			return fmt.Errorf("forwarder requires endpoint")
+		}
+		if len(fwd.Sources) == 0 {
+			return fmt.Errorf("forwarder requires at least one source")
+		}
+	}
+
+	// Check tracing options
+	for _, tracing := range []struct {
+		Enabled                 bool
+		Exporter, Endpoint      string
+		TLSCertFile, TLSKeyFile string
+	}{
+		{cfg.Dashboard.Tracing.Enabled, cfg.Dashboard.Tracing.Exporter, cfg.Dashboard.Tracing.Endpoint, cfg.Dashboard.Tracing.TLS.CertFile, cfg.Dashboard.Tracing.TLS.KeyFile},
+		{cfg.ClusterAPI.Tracing.Enabled, cfg.ClusterAPI.Tracing.Exporter, cfg.ClusterAPI.Tracing.Endpoint, cfg.ClusterAPI.Tracing.TLS.CertFile, cfg.ClusterAPI.Tracing.TLS.KeyFile},
+	} {
+		if !tracing.Enabled {
+			continue
+		}
+		if tracing.Exporter != "stdout" && tracing.Endpoint == "" {
+			return fmt.Errorf("tracing exporter %s requires endpoint", tracing.Exporter)
+		}
+		if (tracing.TLSCertFile == "") != (tracing.TLSKeyFile == "") {
+			return fmt.Errorf("tracing tls requires both cert-file and key-file")
+		}
+	}
+
+	return nil
 }
 
 func DefaultConfig() *Config {
@@ -240,14 +589,50 @@ func DefaultConfig() *Config {
 	cfg.Dashboard.AuthMode = AuthModeAuto
 	cfg.Dashboard.BasePath = "/"
 	cfg.Dashboard.ClusterAPIEndpoint = ""
+	cfg.Dashboard.DataDir = ""
 	cfg.Dashboard.Environment = EnvironmentCluster
 	cfg.Dashboard.GinMode = "release"
+	cfg.Dashboard.Audit.Enabled = false
+	cfg.Dashboard.Audit.Sink = "stdout"
+	cfg.Dashboard.Audit.File = ""
+	cfg.Dashboard.Audit.WebhookURL = ""
 	cfg.Dashboard.CSRF.Enabled = true
+	cfg.Dashboard.Metrics.Enabled = false
+	cfg.Dashboard.Tracing.Enabled = false
+	cfg.Dashboard.Tracing.Exporter = "otlp-grpc"
+	cfg.Dashboard.Tracing.Endpoint = "localhost:4317"
+	cfg.Dashboard.Tracing.SamplingRatio = 1.0
+	cfg.Dashboard.Tracing.TLS.Enabled = false
+	cfg.Dashboard.Quotas.MaxConcurrentSubscriptions = 0
+	cfg.Dashboard.Quotas.MaxConcurrentFetches = 0
+	cfg.Dashboard.Quotas.RequestsPerMinute = 0
+	cfg.Dashboard.Quotas.MaxSourcesPerStream = 0
+	cfg.Dashboard.GraphQL.MaxComplexity = 10000
+	cfg.Dashboard.GraphQL.MaxDepth = 15
+	cfg.Dashboard.GraphQL.MaxAliases = 30
 	cfg.Dashboard.Logging.Enabled = true
 	cfg.Dashboard.Logging.Level = "info"
 	cfg.Dashboard.Logging.Format = "json"
 	cfg.Dashboard.Logging.AccessLog.Enabled = true
 	cfg.Dashboard.Logging.AccessLog.HideHealthChecks = false
+	cfg.Dashboard.OIDC.IssuerURL = ""
+	cfg.Dashboard.OIDC.ClientID = ""
+	cfg.Dashboard.OIDC.ClientSecret = ""
+	cfg.Dashboard.OIDC.RedirectURL = ""
+	cfg.Dashboard.OIDC.Scopes = []string{"email", "profile", "groups", "offline_access"}
+	cfg.Dashboard.OIDC.UsernameClaim = "email"
+	cfg.Dashboard.OIDC.UsernamePrefix = ""
+	cfg.Dashboard.OIDC.GroupsClaim = "groups"
+	cfg.Dashboard.OIDC.GroupsPrefix = ""
+	cfg.Dashboard.OIDC.TokenMode = OIDCTokenModePassthrough
+	cfg.Dashboard.OIDC.CAFile = ""
+	cfg.Dashboard.TrustedHeader.User = "X-Remote-User"
+	cfg.Dashboard.TrustedHeader.Groups = "X-Remote-Group"
+	cfg.Dashboard.TrustedHeader.UID = ""
+	cfg.Dashboard.TrustedHeader.TrustedProxies = []string{"127.0.0.1/32", "::1/128"}
+	cfg.Dashboard.Impersonate.User = ""
+	cfg.Dashboard.Impersonate.UID = ""
+	cfg.Dashboard.Impersonate.Groups = []string{}
 	cfg.Dashboard.Session.Secret = ""
 	cfg.Dashboard.Session.Cookie.Name = "kubetail_dashboard_session"
 	cfg.Dashboard.Session.Cookie.Path = "/"
@@ -259,10 +644,19 @@ func DefaultConfig() *Config {
 	cfg.Dashboard.TLS.Enabled = false
 	cfg.Dashboard.TLS.CertFile = ""
 	cfg.Dashboard.TLS.KeyFile = ""
+	cfg.Dashboard.ClusterAPITLS.Enabled = false
+	cfg.Dashboard.ClusterAPITLS.CertFile = ""
+	cfg.Dashboard.ClusterAPITLS.KeyFile = ""
+	cfg.Dashboard.ClusterAPITLS.CAFile = ""
+	cfg.Dashboard.ClusterAPITLS.ServerName = ""
 	cfg.Dashboard.UI.ClusterAPIEnabled = true
 
 	cfg.ClusterAPI.Addr = ":8080"
 	cfg.ClusterAPI.BasePath = "/"
+	cfg.ClusterAPI.Audit.Enabled = false
+	cfg.ClusterAPI.Audit.Sink = "stdout"
+	cfg.ClusterAPI.Audit.File = ""
+	cfg.ClusterAPI.Audit.WebhookURL = ""
 	cfg.ClusterAPI.ClusterAgent.DispatchUrl = "kubernetes://kubetail-cluster-agent:50051"
 	cfg.ClusterAPI.ClusterAgent.TLS.Enabled = false
 	cfg.ClusterAPI.ClusterAgent.TLS.CertFile = ""
@@ -271,6 +665,27 @@ func DefaultConfig() *Config {
 	cfg.ClusterAPI.ClusterAgent.TLS.ServerName = ""
 	cfg.ClusterAPI.GinMode = "release"
 	cfg.ClusterAPI.CSRF.Enabled = true
+	cfg.ClusterAPI.Metrics.Enabled = false
+	cfg.ClusterAPI.Tracing.Enabled = false
+	cfg.ClusterAPI.Tracing.Exporter = "otlp-grpc"
+	cfg.ClusterAPI.Tracing.Endpoint = "localhost:4317"
+	cfg.ClusterAPI.Tracing.SamplingRatio = 1.0
+	cfg.ClusterAPI.Tracing.TLS.Enabled = false
+	cfg.ClusterAPI.Quotas.MaxConcurrentSubscriptions = 0
+	cfg.ClusterAPI.Quotas.MaxConcurrentFetches = 0
+	cfg.ClusterAPI.Quotas.RequestsPerMinute = 0
+	cfg.ClusterAPI.Quotas.MaxSourcesPerStream = 0
+	cfg.ClusterAPI.RecordCache.Enabled = true
+	cfg.ClusterAPI.RecordCache.MaxRecords = 2000
+	cfg.ClusterAPI.RecordCache.MaxContainers = 1000
+	cfg.ClusterAPI.Forwarder.Enabled = false
+	cfg.ClusterAPI.Forwarder.Endpoint = ""
+	cfg.ClusterAPI.Forwarder.Headers = map[string]string{}
+	cfg.ClusterAPI.Forwarder.Sources = []string{}
+	cfg.ClusterAPI.Forwarder.Grep = ""
+	cfg.ClusterAPI.GraphQL.MaxComplexity = 10000
+	cfg.ClusterAPI.GraphQL.MaxDepth = 15
+	cfg.ClusterAPI.GraphQL.MaxAliases = 30
 	cfg.ClusterAPI.Logging.Enabled = true
 	cfg.ClusterAPI.Logging.Level = "info"
 	cfg.ClusterAPI.Logging.Format = "json"
@@ -279,6 +694,8 @@ func DefaultConfig() *Config {
 	cfg.ClusterAPI.TLS.Enabled = false
 	cfg.ClusterAPI.TLS.CertFile = ""
 	cfg.ClusterAPI.TLS.KeyFile = ""
+	cfg.ClusterAPI.TLS.CAFile = ""
+	cfg.ClusterAPI.TLS.ClientAuth = tls.NoClientCert
 
 	cfg.ClusterAgent.Addr = ":50051"
 	cfg.ClusterAgent.ContainerLogsDir = "/var/log/containers"
@@ -315,6 +732,10 @@ func authModeDecodeHook(f reflect.Type, t reflect.Type, data interface{}) (inter
 		authMode = AuthModeAuto
 	case "token":
 		authMode = AuthModeToken
+	case "oidc":
+		authMode = AuthModeOIDC
+	case "header":
+		authMode = AuthModeHeader
 	default:
 		return nil, fmt.Errorf("invalid AuthMode value: %s", authModeStr)
 	}
@@ -403,6 +824,22 @@ func tlsClientAuthTypeDecodeHook(f reflect.Type, t reflect.Type, data any) (any,
 }
 
 func NewConfig(v *viper.Viper, f string) (*Config, error) {
+	cfg, err := loadConfig(v, f)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate config
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Read config file into viper and unmarshal it on top of the defaults
+// without validating the result
+func loadConfig(v *viper.Viper, f string) (*Config, error) {
 	if f != "" {
 		// read contents
 		configBytes, err := os.ReadFile(f)
@@ -433,11 +870,6 @@ func NewConfig(v *viper.Viper, f string) (*Config, error) {
 		return nil, err
 	}
 
-	// validate config
-	if err := cfg.validate(); err != nil {
-		return nil, err
-	}
-
 	return cfg, nil
 }
 
@@ -450,6 +882,16 @@ type LoggerOptions struct {
 
 var configureLoggerOnce sync.Once
 
+// SetLogLevel changes the global log level of a configured logger
+func SetLogLevel(level string) error {
+	l, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	zerolog.SetGlobalLevel(l)
+	return nil
+}
+
 func ConfigureLogger(opts LoggerOptions) {
 	// ensure this will only be called once
 	configureLoggerOnce.Do(func() {
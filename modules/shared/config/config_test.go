@@ -1,9 +1,11 @@
 package config
 
 import (
+	"crypto/tls"
 	"net/http"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/spf13/viper"
@@ -58,3 +60,333 @@ func TestNewConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestOIDCConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr bool
+	}{
+		{
+			"missing required options",
+			"dashboard:\n  auth-mode: oidc\n",
+			true,
+		},
+		{
+			"invalid token-mode",
+			"dashboard:\n  auth-mode: oidc\n  oidc:\n    issuer-url: https://idp.example.com\n    client-id: kubetail\n    redirect-url: https://kubetail.example.com/api/auth/oidc/callback\n    token-mode: xxx\n",
+			true,
+		},
+		{
+			"valid",
+			"dashboard:\n  auth-mode: oidc\n  oidc:\n    issuer-url: https://idp.example.com\n    client-id: kubetail\n    redirect-url: https://kubetail.example.com/api/auth/oidc/callback\n    token-mode: impersonate\n",
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile, err := os.CreateTemp("", "config-test-*.yaml")
+			require.Nil(t, err)
+			defer os.Remove(tmpFile.Name())
+
+			_, err = tmpFile.WriteString(tt.yaml)
+			require.Nil(t, err)
+			tmpFile.Close()
+
+			cfg, err := NewConfig(viper.New(), tmpFile.Name())
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			require.Nil(t, err)
+			assert.Equal(t, AuthModeOIDC, cfg.Dashboard.AuthMode)
+			assert.Equal(t, OIDCTokenModeImpersonate, cfg.Dashboard.OIDC.TokenMode)
+			assert.Equal(t, "email", cfg.Dashboard.OIDC.UsernameClaim)
+		})
+	}
+}
+
+func TestTLSConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr bool
+	}{
+		{
+			"defaults",
+			"",
+			false,
+		},
+		{
+			"client verification without ca-file",
+			"cluster-api:\n  tls:\n    client-auth: require-and-verify\n",
+			true,
+		},
+		{
+			"invalid client-auth",
+			"cluster-api:\n  tls:\n    client-auth: xxx\n",
+			true,
+		},
+		{
+			"client cert without key",
+			"dashboard:\n  cluster-api-tls:\n    enabled: true\n    cert-file: PEMFILE\n",
+			true,
+		},
+		{
+			"valid",
+			"cluster-api:\n  tls:\n    client-auth: require-and-verify\n    ca-file: PEMFILE\ndashboard:\n  cluster-api-tls:\n    enabled: true\n    cert-file: PEMFILE\n    key-file: PEMFILE\n    ca-file: PEMFILE\n    server-name: kubetail-cluster-api\n",
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Any existing file passes file validation
+			pemFile, err := os.CreateTemp("", "config-test-*.pem")
+			require.Nil(t, err)
+			defer os.Remove(pemFile.Name())
+			pemFile.Close()
+
+			tmpFile, err := os.CreateTemp("", "config-test-*.yaml")
+			require.Nil(t, err)
+			defer os.Remove(tmpFile.Name())
+
+			_, err = tmpFile.WriteString(strings.ReplaceAll(tt.yaml, "PEMFILE", pemFile.Name()))
+			require.Nil(t, err)
+			tmpFile.Close()
+
+			cfg, err := NewConfig(viper.New(), tmpFile.Name())
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+
+			if tt.yaml != "" {
+				assert.Equal(t, tls.RequireAndVerifyClientCert, cfg.ClusterAPI.TLS.ClientAuth)
+				assert.Equal(t, pemFile.Name(), cfg.ClusterAPI.TLS.CAFile)
+				assert.True(t, cfg.Dashboard.ClusterAPITLS.Enabled)
+				assert.Equal(t, "kubetail-cluster-api", cfg.Dashboard.ClusterAPITLS.ServerName)
+			}
+		})
+	}
+}
+
+func TestTracingConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr bool
+	}{
+		{
+			"defaults",
+			"",
+			false,
+		},
+		{
+			"invalid exporter",
+			"cluster-api:\n  tracing:\n    enabled: true\n    exporter: xxx\n",
+			true,
+		},
+		{
+			"sampling ratio out of range",
+			"cluster-api:\n  tracing:\n    sampling-ratio: 2\n",
+			true,
+		},
+		{
+			"otlp without endpoint",
+			"dashboard:\n  tracing:\n    enabled: true\n    endpoint: \"\"\n",
+			true,
+		},
+		{
+			"valid",
+			"cluster-api:\n  tracing:\n    enabled: true\n    exporter: otlp-http\n    endpoint: otel-collector:4318\n    sampling-ratio: 0.25\n    headers:\n      authorization: Bearer xxx\n",
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile, err := os.CreateTemp("", "config-test-*.yaml")
+			require.Nil(t, err)
+			defer os.Remove(tmpFile.Name())
+
+			_, err = tmpFile.WriteString(tt.yaml)
+			require.Nil(t, err)
+			tmpFile.Close()
+
+			cfg, err := NewConfig(viper.New(), tmpFile.Name())
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+
+			if tt.yaml == "" {
+				assert.False(t, cfg.ClusterAPI.Tracing.Enabled)
+				assert.Equal(t, 1.0, cfg.ClusterAPI.Tracing.SamplingRatio)
+				return
+			}
+
+			assert.True(t, cfg.ClusterAPI.Tracing.Enabled)
+			assert.Equal(t, "otlp-http", cfg.ClusterAPI.Tracing.Exporter)
+			assert.Equal(t, 0.25, cfg.ClusterAPI.Tracing.SamplingRatio)
+			assert.Equal(t, map[string]string{"authorization": "Bearer xxx"}, cfg.ClusterAPI.Tracing.Headers)
+		})
+	}
+}
+
+func TestForwarderConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr bool
+	}{
+		{
+			"defaults",
+			"",
+			false,
+		},
+		{
+			"missing endpoint",
+			"cluster-api:\n  forwarder:\n    enabled: true\n    sources:\n      - default:deployments/web\n",
+			true,
+		},
+		{
+			"missing sources",
+			"cluster-api:\n  forwarder:\n    enabled: true\n    endpoint: otlp://otel-collector:4317\n",
+			true,
+		},
+		{
+			"valid",
+			"cluster-api:\n  forwarder:\n    enabled: true\n    endpoint: otlp://otel-collector:4317\n    sources:\n      - default:deployments/web\n    grep: error\n",
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile, err := os.CreateTemp("", "config-test-*.yaml")
+			require.Nil(t, err)
+			defer os.Remove(tmpFile.Name())
+
+			_, err = tmpFile.WriteString(tt.yaml)
+			require.Nil(t, err)
+			tmpFile.Close()
+
+			cfg, err := NewConfig(viper.New(), tmpFile.Name())
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+
+			if tt.yaml == "" {
+				assert.False(t, cfg.ClusterAPI.Forwarder.Enabled)
+				return
+			}
+
+			assert.True(t, cfg.ClusterAPI.Forwarder.Enabled)
+			assert.Equal(t, "otlp://otel-collector:4317", cfg.ClusterAPI.Forwarder.Endpoint)
+			assert.Equal(t, []string{"default:deployments/web"}, cfg.ClusterAPI.Forwarder.Sources)
+			assert.Equal(t, "error", cfg.ClusterAPI.Forwarder.Grep)
+		})
+	}
+}
+
+func TestTrustedHeaderConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr bool
+	}{
+		{
+			"defaults",
+			"dashboard:\n  auth-mode: header\n",
+			false,
+		},
+		{
+			"missing user header",
+			"dashboard:\n  auth-mode: header\n  trusted-header:\n    user: \"\"\n",
+			true,
+		},
+		{
+			"invalid trusted proxy",
+			"dashboard:\n  auth-mode: header\n  trusted-header:\n    trusted-proxies:\n      - 10.0.0.1\n",
+			true,
+		},
+		{
+			"custom headers",
+			"dashboard:\n  auth-mode: header\n  trusted-header:\n    user: X-Forwarded-User\n    groups: X-Forwarded-Groups\n    uid: X-Forwarded-Uid\n    trusted-proxies:\n      - 10.0.0.0/8\n",
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile, err := os.CreateTemp("", "config-test-*.yaml")
+			require.Nil(t, err)
+			defer os.Remove(tmpFile.Name())
+
+			_, err = tmpFile.WriteString(tt.yaml)
+			require.Nil(t, err)
+			tmpFile.Close()
+
+			cfg, err := NewConfig(viper.New(), tmpFile.Name())
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			require.Nil(t, err)
+			assert.Equal(t, AuthModeHeader, cfg.Dashboard.AuthMode)
+			assert.NotEmpty(t, cfg.Dashboard.TrustedHeader.User)
+			assert.NotEmpty(t, cfg.Dashboard.TrustedHeader.TrustedProxies)
+		})
+	}
+}
+
+func TestImpersonateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr bool
+	}{
+		{
+			"defaults",
+			"",
+			false,
+		},
+		{
+			"user and groups",
+			"dashboard:\n  impersonate:\n    user: alice\n    groups:\n      - dev\n",
+			false,
+		},
+		{
+			"groups without user",
+			"dashboard:\n  impersonate:\n    groups:\n      - dev\n",
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile, err := os.CreateTemp("", "config-test-*.yaml")
+			require.Nil(t, err)
+			defer os.Remove(tmpFile.Name())
+
+			_, err = tmpFile.WriteString(tt.yaml)
+			require.Nil(t, err)
+			tmpFile.Close()
+
+			_, err = NewConfig(viper.New(), tmpFile.Name())
+			if tt.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
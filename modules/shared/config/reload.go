@@ -0,0 +1,126 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// Keys whose values are left out of logged diffs
+var redactedKeys = []string{"client-secret", "secret", "headers"}
+
+// Live holds the configuration of a running app. Components that support
+// hot-swappable fields read them through Load() so a reload is seen by all
+// of them at once.
+type Live struct {
+	cfg atomic.Pointer[Config]
+}
+
+// Create new Live instance
+func NewLive(cfg *Config) *Live {
+	l := &Live{}
+	l.cfg.Store(cfg)
+	return l
+}
+
+// Load returns the current config
+func (l *Live) Load() *Config {
+	return l.cfg.Load()
+}
+
+// Store replaces the current config
+func (l *Live) Store(cfg *Config) {
+	l.cfg.Store(cfg)
+}
+
+// AllowedNamespaces returns the current namespace allow-list
+func (l *Live) AllowedNamespaces() []string {
+	return l.cfg.Load().AllowedNamespaces
+}
+
+// Diff returns a sorted list of the leaf keys that differ between `a` and
+// `b` formatted as "key: old -> new"
+func Diff(a, b *Config) []string {
+	var out []string
+	diffValues("", reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem(), &out)
+	slices.Sort(out)
+	return out
+}
+
+// Return copy of `cur` with the hot-swappable fields taken from `next`
+func mergeReloadable(cur, next *Config) *Config {
+	merged := *cur
+
+	merged.AllowedNamespaces = next.AllowedNamespaces
+
+	merged.Dashboard.CSRF = next.Dashboard.CSRF
+	merged.Dashboard.Quotas = next.Dashboard.Quotas
+	merged.Dashboard.Logging.Level = next.Dashboard.Logging.Level
+	merged.Dashboard.Logging.AccessLog = next.Dashboard.Logging.AccessLog
+
+	merged.ClusterAPI.CSRF = next.ClusterAPI.CSRF
+	merged.ClusterAPI.Quotas = next.ClusterAPI.Quotas
+	merged.ClusterAPI.Logging.Level = next.ClusterAPI.Logging.Level
+	merged.ClusterAPI.Logging.AccessLog = next.ClusterAPI.Logging.AccessLog
+
+	return &merged
+}
+
+// Walk struct fields and record leaf values that differ
+func diffValues(key string, a, b reflect.Value, out *[]string) {
+	if a.Kind() == reflect.Struct {
+		t := a.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name := strings.ToLower(field.Name)
+			if tag, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ","); tag != "" {
+				name = tag
+			}
+			if key != "" {
+				name = key + "." + name
+			}
+
+			diffValues(name, a.Field(i), b.Field(i), out)
+		}
+		return
+	}
+
+	// Treat nil and empty slices/maps as equal
+	switch a.Kind() {
+	case reflect.Slice, reflect.Map:
+		if a.Len() == 0 && b.Len() == 0 {
+			return
+		}
+	}
+
+	if reflect.DeepEqual(a.Interface(), b.Interface()) {
+		return
+	}
+
+	if slices.Contains(redactedKeys, key[strings.LastIndex(key, ".")+1:]) {
+		*out = append(*out, fmt.Sprintf("%s: (redacted)", key))
+		return
+	}
+
+	*out = append(*out, fmt.Sprintf("%s: %v -> %v", key, a.Interface(), b.Interface()))
+}
@@ -0,0 +1,66 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	a := DefaultConfig()
+	b := DefaultConfig()
+	assert.Empty(t, Diff(a, b))
+
+	b.AllowedNamespaces = []string{"ns1"}
+	b.Dashboard.Logging.Level = "debug"
+	b.ClusterAPI.Forwarder.Headers = map[string]string{"authorization": "Bearer xyz"}
+	b.Dashboard.Session.Secret = "s3cr3t"
+
+	assert.Equal(t, []string{
+		"allowed-namespaces: [] -> [ns1]",
+		"cluster-api.forwarder.headers: (redacted)",
+		"dashboard.logging.level: info -> debug",
+		"dashboard.session.secret: (redacted)",
+	}, Diff(a, b))
+}
+
+func TestMergeReloadable(t *testing.T) {
+	cur := DefaultConfig()
+
+	next := DefaultConfig()
+	next.AllowedNamespaces = []string{"ns1"}
+	next.ClusterAPI.CSRF.Enabled = false
+	next.ClusterAPI.Quotas.RequestsPerMinute = 60
+	next.Dashboard.Logging.AccessLog.Enabled = false
+	next.Dashboard.Addr = ":9999"
+
+	merged := mergeReloadable(cur, next)
+
+	// Hot-swappable fields are taken from next
+	assert.Equal(t, []string{"ns1"}, merged.AllowedNamespaces)
+	assert.False(t, merged.ClusterAPI.CSRF.Enabled)
+	assert.Equal(t, 60, merged.ClusterAPI.Quotas.RequestsPerMinute)
+	assert.False(t, merged.Dashboard.Logging.AccessLog.Enabled)
+
+	// Other fields are kept
+	assert.Equal(t, cur.Dashboard.Addr, merged.Dashboard.Addr)
+	assert.Equal(t, []string{"dashboard.addr: " + cur.Dashboard.Addr + " -> :9999"}, Diff(merged, next))
+
+	// Current config is left untouched
+	assert.Empty(t, cur.AllowedNamespaces)
+	assert.True(t, cur.ClusterAPI.CSRF.Enabled)
+}
@@ -0,0 +1,180 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/fsnotify/fsnotify"
+	zlog "github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+// Time to wait for writes to settle before reloading
+const reloadDebounceDelay = 100 * time.Millisecond
+
+// Represents Watcher that reloads the config file when it changes on disk or
+// when the process receives SIGHUP. Updates that fail validation are
+// rejected. Of the valid updates only the hot-swappable fields are applied,
+// changes to other fields are logged and take effect on the next restart.
+type Watcher struct {
+	v        *viper.Viper
+	f        string
+	cfg      *Config
+	watcher  *fsnotify.Watcher
+	sigCh    chan os.Signal
+	eventbus evbus.Bus
+	mu       sync.Mutex
+}
+
+// Create new Watcher instance. The viper instance `v` must be the one `cfg`
+// was loaded with so that command-line flag bindings keep precedence.
+func NewWatcher(v *viper.Viper, f string, cfg *Config) (*Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	// Watch parent directory so that files replaced by rename (e.g. mounted
+	// ConfigMaps) are picked up
+	if err := watcher.Add(filepath.Dir(f)); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	w := &Watcher{
+		v:        v,
+		f:        f,
+		cfg:      cfg,
+		watcher:  watcher,
+		sigCh:    make(chan os.Signal, 1),
+		eventbus: evbus.New(),
+	}
+
+	signal.Notify(w.sigCh, syscall.SIGHUP)
+
+	// Start event listeners
+	go w.start()
+
+	return w, nil
+}
+
+// Get returns the current config
+func (w *Watcher) Get() *Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.cfg
+}
+
+// Subscribe registers `fn` to be called with the new config after an update
+// has been applied
+func (w *Watcher) Subscribe(fn func(cfg *Config)) {
+	w.eventbus.Subscribe("MODIFIED", fn)
+}
+
+// Reload re-reads the config file and applies the hot-swappable fields. An
+// error is returned if the new config is invalid.
+func (w *Watcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	next, err := loadConfig(w.v, w.f)
+	if err != nil {
+		zlog.Error().Err(err).Str("file", w.f).Msg("Rejected config update")
+		return err
+	}
+
+	if err := next.validate(); err != nil {
+		zlog.Error().Err(err).Str("file", w.f).Strs("diff", Diff(w.cfg, next)).Msg("Rejected invalid config update")
+		return err
+	}
+
+	merged := mergeReloadable(w.cfg, next)
+
+	if pending := Diff(merged, next); len(pending) > 0 {
+		zlog.Warn().Str("file", w.f).Strs("diff", pending).Msg("Config changes require a restart to take effect")
+	}
+
+	applied := Diff(w.cfg, merged)
+	if len(applied) == 0 {
+		return nil
+	}
+
+	w.cfg = merged
+	zlog.Info().Str("file", w.f).Strs("diff", applied).Msg("Applied config update")
+
+	w.eventbus.Publish("MODIFIED", merged)
+
+	return nil
+}
+
+// Close stops watching for changes
+func (w *Watcher) Close() {
+	signal.Stop(w.sigCh)
+	w.watcher.Close()
+}
+
+// Start
+func (w *Watcher) start() {
+	var debounceTimer *time.Timer
+
+	name := filepath.Base(w.f)
+
+	for {
+		select {
+		case err, ok := <-w.watcher.Errors:
+			// Kill goroutine on watcher close
+			if !ok {
+				return
+			}
+
+			// Log error and keep listening
+			zlog.Error().Err(err).Caller().Send()
+		case fsEv, ok := <-w.watcher.Events:
+			// Kill goroutine on watcher close
+			if !ok {
+				return
+			}
+
+			// Ignore other files in the directory (ConfigMap mounts swap the
+			// `..data` symlink)
+			base := filepath.Base(fsEv.Name)
+			if base != name && !strings.HasPrefix(base, "..") {
+				continue
+			}
+
+			if fsEv.Has(fsnotify.Create) || fsEv.Has(fsnotify.Write) || fsEv.Has(fsnotify.Rename) {
+				// Reset timer if it's already running
+				if debounceTimer != nil {
+					debounceTimer.Stop()
+				}
+
+				debounceTimer = time.AfterFunc(reloadDebounceDelay, func() {
+					w.Reload()
+				})
+			}
+		case <-w.sigCh:
+			zlog.Info().Str("file", w.f).Msg("Received SIGHUP, reloading config")
+			w.Reload()
+		}
+	}
+}
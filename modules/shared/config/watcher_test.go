@@ -0,0 +1,101 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Write config file and return a watcher for it
+func newTestWatcher(t *testing.T, yaml string) (*Watcher, string) {
+	f := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(f, []byte(yaml), 0644))
+
+	v := viper.New()
+	cfg, err := NewConfig(v, f)
+	require.NoError(t, err)
+
+	w, err := NewWatcher(v, f, cfg)
+	require.NoError(t, err)
+	t.Cleanup(w.Close)
+
+	return w, f
+}
+
+func TestWatcherReload(t *testing.T) {
+	w, f := newTestWatcher(t, "allowed-namespaces:\n  - ns1\n")
+
+	var got []*Config
+	w.Subscribe(func(cfg *Config) {
+		got = append(got, cfg)
+	})
+
+	t.Run("hot-swappable fields are applied", func(t *testing.T) {
+		yaml := "allowed-namespaces:\n  - ns1\n  - ns2\ncluster-api:\n  quotas:\n    requests-per-minute: 60\n"
+		require.NoError(t, os.WriteFile(f, []byte(yaml), 0644))
+		require.NoError(t, w.Reload())
+
+		assert.Equal(t, []string{"ns1", "ns2"}, w.Get().AllowedNamespaces)
+		assert.Equal(t, 60, w.Get().ClusterAPI.Quotas.RequestsPerMinute)
+		require.Len(t, got, 1)
+		assert.Same(t, w.Get(), got[0])
+	})
+
+	t.Run("other fields require a restart", func(t *testing.T) {
+		yaml := "allowed-namespaces:\n  - ns1\n  - ns2\ncluster-api:\n  addr: :9999\n  quotas:\n    requests-per-minute: 60\n"
+		require.NoError(t, os.WriteFile(f, []byte(yaml), 0644))
+		require.NoError(t, w.Reload())
+
+		assert.Equal(t, ":8080", w.Get().ClusterAPI.Addr)
+		assert.Len(t, got, 1)
+	})
+
+	t.Run("invalid updates are rejected", func(t *testing.T) {
+		yaml := "allowed-namespaces:\n  - ns3\ncluster-api:\n  logging:\n    level: verbose\n"
+		require.NoError(t, os.WriteFile(f, []byte(yaml), 0644))
+		require.Error(t, w.Reload())
+
+		assert.Equal(t, []string{"ns1", "ns2"}, w.Get().AllowedNamespaces)
+		assert.Len(t, got, 1)
+	})
+}
+
+func TestWatcherFileChange(t *testing.T) {
+	w, f := newTestWatcher(t, "")
+
+	var mu sync.Mutex
+	var got *Config
+	w.Subscribe(func(cfg *Config) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = cfg
+	})
+
+	require.NoError(t, os.WriteFile(f, []byte("dashboard:\n  csrf:\n    enabled: false\n"), 0644))
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return got != nil && !got.Dashboard.CSRF.Enabled
+	}, 2*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,403 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package download
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	zlog "github.com/rs/zerolog/log"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kubetail-org/kubetail/modules/shared/audit"
+	gqlerrors "github.com/kubetail-org/kubetail/modules/shared/graphql/errors"
+	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+	"github.com/kubetail-org/kubetail/modules/shared/quota"
+	"github.com/kubetail-org/kubetail/modules/shared/util"
+)
+
+// Number of records written between flushes to the client
+const flushInterval = 500
+
+// Represents a parsed download request
+type Query struct {
+	KubeContext string
+	Sources     []string
+	Mode        string
+	Limit       int64
+	Since       time.Time
+	Until       time.Time
+	Grep        string
+	Region      []string
+	Zone        []string
+	OS          []string
+	Arch        []string
+	Node        []string
+	Container   []string
+	Format      Format
+	Gzip        bool
+}
+
+// ParseQuery parses url query parameters into a Query. Accepts the same
+// arguments as `logRecordsFetch`:
+//
+//	kubeContext, source (repeated), mode (head|tail), limit (0 means all),
+//	since, until, grep, region, zone, os, arch, node, container (repeated),
+//	format (ndjson|csv|text), gzip (bool)
+func ParseQuery(values url.Values) (*Query, error) {
+	q := &Query{
+		KubeContext: values.Get("kubeContext"),
+		Sources:     values["source"],
+		Mode:        strings.ToLower(values.Get("mode")),
+		Grep:        values.Get("grep"),
+		Region:      values["region"],
+		Zone:        values["zone"],
+		OS:          values["os"],
+		Arch:        values["arch"],
+		Node:        values["node"],
+		Container:   values["container"],
+	}
+
+	if len(q.Sources) == 0 {
+		return nil, fmt.Errorf("at least one source is required")
+	}
+
+	switch q.Mode {
+	case "":
+		q.Mode = "head"
+	case "head", "tail":
+	default:
+		return nil, fmt.Errorf("invalid mode: %s", q.Mode)
+	}
+
+	if v := values.Get("limit"); v != "" {
+		limit, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid limit: %s", v)
+		}
+		q.Limit = limit
+	}
+
+	var err error
+	if q.Since, err = util.ParseTimeArg(values.Get("since")); err != nil {
+		return nil, err
+	}
+	if q.Until, err = util.ParseTimeArg(values.Get("until")); err != nil {
+		return nil, err
+	}
+
+	if q.Format, err = parseFormat(strings.ToLower(values.Get("format"))); err != nil {
+		return nil, err
+	}
+
+	if v := values.Get("gzip"); v != "" {
+		if q.Gzip, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("invalid gzip: %s", v)
+		}
+	}
+
+	return q, nil
+}
+
+// Filename returns the attachment filename for the query
+func (q *Query) Filename(now time.Time) string {
+	name := fmt.Sprintf("kubetail-logs-%s.%s", now.UTC().Format("20060102T150405Z"), q.Format.Extension())
+	if q.Gzip {
+		name += ".gz"
+	}
+	return name
+}
+
+// Handler streams log records matching the request query to the client
+type Handler struct {
+	cm                k8shelpers.ConnectionManager
+	allowedNamespaces func() []string
+	audit             *audit.Logger
+	quotas            *quota.Limiter
+	streamOpts        []logs.Option
+}
+
+// Create new Handler instance. `allowedNamespaces` is called on every request
+// so the allow-list can change at runtime. `streamOpts` are passed to every
+// stream (e.g. a custom log fetcher).
+func NewHandler(cm k8shelpers.ConnectionManager, allowedNamespaces func() []string, auditLogger *audit.Logger, quotas *quota.Limiter, streamOpts ...logs.Option) *Handler {
+	return &Handler{
+		cm:                cm,
+		allowedNamespaces: allowedNamespaces,
+		audit:             auditLogger,
+		quotas:            quotas,
+		streamOpts:        streamOpts,
+	}
+}
+
+// Return current namespace allow-list
+func (h *Handler) getAllowedNamespaces() []string {
+	if h.allowedNamespaces == nil {
+		return nil
+	}
+	return h.allowedNamespaces()
+}
+
+// ServeHTTP
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	q, err := ParseQuery(r.URL.Query())
+	if err != nil {
+		// Audit rejected requests with the raw args
+		values := r.URL.Query()
+		h.audit.Start(ctx, audit.Event{
+			Operation:   "logsDownload",
+			KubeContext: values.Get("kubeContext"),
+			Sources:     values["source"],
+			Grep:        values.Get("grep"),
+		}).Finish(err)
+
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	kubeContext := q.KubeContext
+	if h.cm != nil {
+		kubeContext = h.cm.DerefKubeContext(&q.KubeContext)
+	}
+
+	// Get bearer token
+	token, _ := ctx.Value(k8shelpers.K8STokenCtxKey).(string)
+
+	// Init audit entry
+	ae := h.audit.Start(ctx, audit.Event{
+		Operation:   "logsDownload",
+		KubeContext: kubeContext,
+		Sources:     q.Sources,
+		SourceFilter: audit.SourceFilter{
+			Region:    q.Region,
+			Zone:      q.Zone,
+			OS:        q.OS,
+			Arch:      q.Arch,
+			Node:      q.Node,
+			Container: q.Container,
+		},
+		Grep:  q.Grep,
+		Mode:  strings.ToUpper(q.Mode),
+		Since: q.Since,
+		Until: q.Until,
+	})
+
+	// Check quotas
+	release, err := h.quotas.AcquireFetch(ctx)
+	if err != nil {
+		ae.Finish(err)
+		writeError(w, err)
+		return
+	}
+	defer release()
+
+	// Init stream
+	streamOpts := []logs.Option{
+		logs.WithKubeContext(kubeContext),
+		logs.WithBearerToken(token),
+		logs.WithAllowedNamespaces(h.getAllowedNamespaces()),
+		logs.WithSince(q.Since),
+		logs.WithUntil(q.Until),
+		logs.WithGrep(q.Grep),
+		logs.WithRegions(q.Region),
+		logs.WithZones(q.Zone),
+		logs.WithOSes(q.OS),
+		logs.WithArches(q.Arch),
+		logs.WithNodes(q.Node),
+		logs.WithContainers(q.Container),
+		logs.WithMaxSources(h.quotas.MaxSourcesPerStream()),
+	}
+
+	switch {
+	case q.Limit == 0:
+		streamOpts = append(streamOpts, logs.WithAll())
+	case q.Mode == "tail":
+		streamOpts = append(streamOpts, logs.WithTail(q.Limit))
+	default:
+		streamOpts = append(streamOpts, logs.WithHead(q.Limit))
+	}
+
+	streamOpts = append(streamOpts, h.streamOpts...)
+
+	stream, err := logs.NewStream(ctx, h.cm, q.Sources, streamOpts...)
+	if err != nil {
+		ae.Finish(err)
+		writeError(w, err)
+		return
+	}
+	defer stream.Close()
+
+	// Start stream
+	if err := stream.Start(ctx); err != nil {
+		err = h.quotas.Error(err)
+		ae.Finish(err)
+		writeError(w, err)
+		return
+	}
+
+	// Write headers
+	w.Header().Set("Content-Type", q.Format.ContentType())
+	if q.Gzip {
+		w.Header().Set("Content-Type", "application/gzip")
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, q.Filename(time.Now())))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	// Init writers
+	bw := bufio.NewWriter(w)
+
+	var out io.Writer = bw
+	var gz *gzip.Writer
+	if q.Gzip {
+		gz = gzip.NewWriter(bw)
+		out = gz
+	}
+
+	rw := NewRecordWriter(out, q.Format)
+
+	flush := func() error {
+		if err := rw.Flush(); err != nil {
+			return err
+		}
+		if gz != nil {
+			if err := gz.Flush(); err != nil {
+				return err
+			}
+		}
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		return nil
+	}
+
+	// Write out records
+	count := 0
+	for record := range stream.Records() {
+		if err := rw.Write(&record); err != nil {
+			ae.Finish(err)
+			return
+		}
+		ae.AddRecords(1)
+
+		count += 1
+		if count%flushInterval == 0 {
+			if err := flush(); err != nil {
+				ae.Finish(err)
+				return
+			}
+		}
+	}
+
+	// Abort response on error so the client doesn't mistake a partial download
+	// for a complete one
+	if err := ctx.Err(); err != nil {
+		ae.Finish(err)
+		return
+	}
+
+	if err := stream.Err(); err != nil {
+		ae.Finish(err)
+		zlog.Error().Err(err).Msg("log download failed")
+		flush()
+		abortResponse(w)
+		return
+	}
+
+	if err := flush(); err != nil {
+		ae.Finish(err)
+		return
+	}
+
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			ae.Finish(err)
+			return
+		}
+		bw.Flush()
+	}
+
+	ae.Finish(nil)
+}
+
+// End response without terminating the body so clients see a truncated
+// transfer instead of a complete download. Panicking with ErrAbortHandler
+// isn't enough on its own since recovery middleware (e.g. gin.Recovery)
+// swallows the panic and the response then ends cleanly.
+func abortResponse(w http.ResponseWriter) {
+	// Hijack the underlying writer since wrappers (e.g. gin) refuse to hijack
+	// responses that have already been written
+	for {
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			break
+		}
+		w = u.Unwrap()
+	}
+
+	conn, buf, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		// Connections that can't be hijacked (e.g. HTTP/2) are reset by
+		// net/http if the panic reaches it
+		panic(http.ErrAbortHandler)
+	}
+	buf.Flush()
+	conn.Close()
+}
+
+// Write error response with an appropriate status code
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+
+	var gqlErr *gqlerror.Error
+	switch {
+	case errors.As(err, &gqlErr):
+		switch gqlErr.Extensions["code"] {
+		case gqlerrors.ErrForbidden.Extensions["code"]:
+			status = http.StatusForbidden
+		case gqlerrors.ErrUnauthenticated.Extensions["code"]:
+			status = http.StatusUnauthorized
+		case gqlerrors.ErrRateLimited.Extensions["code"], "KUBETAIL_QUOTA_EXCEEDED":
+			status = http.StatusTooManyRequests
+		}
+		err = errors.New(gqlErr.Message)
+	case k8serrors.IsForbidden(err):
+		status = http.StatusForbidden
+	case k8serrors.IsUnauthorized(err):
+		status = http.StatusUnauthorized
+	}
+
+	http.Error(w, err.Error(), status)
+}
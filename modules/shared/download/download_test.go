@@ -0,0 +1,212 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package download
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubetail-org/kubetail/modules/shared/audit"
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+	"github.com/kubetail-org/kubetail/modules/shared/quota"
+)
+
+func TestParseQuery(t *testing.T) {
+	ts := time.Date(2025, 3, 13, 11, 46, 1, 123456789, time.UTC)
+
+	t.Run("defaults", func(t *testing.T) {
+		q, err := ParseQuery(url.Values{"source": {"ns1/pods/pod1"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ns1/pods/pod1"}, q.Sources)
+		assert.Equal(t, "head", q.Mode)
+		assert.Equal(t, int64(0), q.Limit)
+		assert.Equal(t, FormatNDJSON, q.Format)
+		assert.False(t, q.Gzip)
+		assert.True(t, q.Since.IsZero())
+		assert.True(t, q.Until.IsZero())
+	})
+
+	t.Run("all args", func(t *testing.T) {
+		q, err := ParseQuery(url.Values{
+			"kubeContext": {"ctx1"},
+			"source":      {"ns1/pods/pod1", "ns2/deployments/app"},
+			"mode":        {"TAIL"},
+			"limit":       {"1000"},
+			"since":       {"PT1H"},
+			"until":       {ts.Format(time.RFC3339Nano)},
+			"grep":        {"error"},
+			"node":        {"node1", "node2"},
+			"container":   {"c1"},
+			"format":      {"csv"},
+			"gzip":        {"true"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "ctx1", q.KubeContext)
+		assert.Equal(t, []string{"ns1/pods/pod1", "ns2/deployments/app"}, q.Sources)
+		assert.Equal(t, "tail", q.Mode)
+		assert.Equal(t, int64(1000), q.Limit)
+		assert.WithinDuration(t, time.Now().Add(-1*time.Hour), q.Since, time.Minute)
+		assert.Equal(t, ts, q.Until)
+		assert.Equal(t, "error", q.Grep)
+		assert.Equal(t, []string{"node1", "node2"}, q.Node)
+		assert.Equal(t, []string{"c1"}, q.Container)
+		assert.Equal(t, FormatCSV, q.Format)
+		assert.True(t, q.Gzip)
+		assert.Equal(t, "kubetail-logs-20250313T114601Z.csv.gz", q.Filename(ts))
+	})
+
+	errorTests := []struct {
+		name      string
+		setValues url.Values
+	}{
+		{"missing source", url.Values{}},
+		{"invalid mode", url.Values{"source": {"x"}, "mode": {"follow"}}},
+		{"invalid limit", url.Values{"source": {"x"}, "limit": {"-1"}}},
+		{"invalid since", url.Values{"source": {"x"}, "since": {"yesterday"}}},
+		{"invalid format", url.Values{"source": {"x"}, "format": {"xml"}}},
+		{"invalid gzip", url.Values{"source": {"x"}, "gzip": {"maybe"}}},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseQuery(tt.setValues)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRecordWriter(t *testing.T) {
+	record := &logs.LogRecord{
+		Timestamp: time.Date(2025, 3, 13, 11, 46, 1, 123456789, time.UTC),
+		Message:   `hello, "world"`,
+		Source: logs.LogSource{
+			Metadata:      logs.LogSourceMetadata{Node: "node1"},
+			Namespace:     "ns1",
+			PodName:       "pod1",
+			ContainerName: "c1",
+			ContainerID:   "abc",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		setFormat Format
+		want      string
+	}{
+		{
+			"ndjson",
+			FormatNDJSON,
+			`{"timestamp":"2025-03-13T11:46:01.123456789Z","message":"hello, \"world\"","source":{"metadata":{"region":"","zone":"","os":"","arch":"","node":"node1"},"namespace":"ns1","podName":"pod1","containerName":"c1","containerID":"abc"}}` + "\n",
+		},
+		{
+			"csv",
+			FormatCSV,
+			"timestamp,namespace,pod,container,node,message\n" +
+				`2025-03-13T11:46:01.123456789Z,ns1,pod1,c1,node1,"hello, ""world"""` + "\n",
+		},
+		{
+			"text",
+			FormatText,
+			`2025-03-13T11:46:01.123456789Z ns1/pod1/c1 hello, "world"` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewRecordWriter(&buf, tt.setFormat)
+			require.NoError(t, w.Write(record))
+			require.NoError(t, w.Flush())
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestAbortResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.GET("/", func(c *gin.Context) {
+		c.Writer.WriteHeader(http.StatusOK)
+		c.Writer.Write([]byte("line-0\n"))
+		c.Writer.Flush()
+		abortResponse(c.Writer)
+	})
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Client can tell the download is incomplete
+	body, err := io.ReadAll(resp.Body)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Equal(t, "line-0\n", string(body))
+}
+
+func TestHandlerErrors(t *testing.T) {
+	t.Run("method not allowed", func(t *testing.T) {
+		h := NewHandler(nil, nil, nil, nil)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/?source=x", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+
+	t.Run("bad request", func(t *testing.T) {
+		var buf bytes.Buffer
+		h := NewHandler(nil, nil, audit.NewLogger("dashboard", audit.NewWriterSink(&buf), nil), nil)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?source=ns1/pods/pod1&format=xml", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		// Rejected requests are audited
+		var ev audit.Event
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &ev))
+		assert.Equal(t, "logsDownload", ev.Operation)
+		assert.Equal(t, []string{"ns1/pods/pod1"}, ev.Sources)
+		assert.Equal(t, audit.OutcomeError, ev.Outcome)
+	})
+
+	t.Run("quota exceeded", func(t *testing.T) {
+		quotas := quota.NewLimiter(quota.Limits{MaxConcurrentFetches: 1}, nil)
+
+		release, err := quotas.AcquireFetch(context.Background())
+		require.NoError(t, err)
+		defer release()
+
+		h := NewHandler(nil, nil, nil, quotas)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?source=ns1/pods/pod1", nil))
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	})
+}
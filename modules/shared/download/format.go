@@ -0,0 +1,192 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package download
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+)
+
+// Format represents an output format
+type Format string
+
+const (
+	FormatNDJSON Format = "ndjson"
+	FormatCSV    Format = "csv"
+	FormatText   Format = "text"
+)
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatText:
+		return "text/plain; charset=utf-8"
+	default:
+		return "application/x-ndjson"
+	}
+}
+
+// Extension returns the file extension of the format
+func (f Format) Extension() string {
+	switch f {
+	case FormatCSV:
+		return "csv"
+	case FormatText:
+		return "log"
+	default:
+		return "ndjson"
+	}
+}
+
+// Parse format string (empty string defaults to ndjson)
+func parseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatNDJSON:
+		return FormatNDJSON, nil
+	case FormatCSV, FormatText:
+		return Format(s), nil
+	}
+	return "", fmt.Errorf("invalid format: %s", s)
+}
+
+// RecordWriter writes log records in a specific format
+type RecordWriter interface {
+	Write(record *logs.LogRecord) error
+	Flush() error
+}
+
+// Create new RecordWriter instance for the given format
+func NewRecordWriter(w io.Writer, format Format) RecordWriter {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}
+	case FormatText:
+		return &textWriter{w: w}
+	default:
+		return &ndjsonWriter{enc: json.NewEncoder(w)}
+	}
+}
+
+// Represents NDJSON record source metadata (matches GraphQL field names)
+type ndjsonSourceMetadata struct {
+	Region string `json:"region"`
+	Zone   string `json:"zone"`
+	OS     string `json:"os"`
+	Arch   string `json:"arch"`
+	Node   string `json:"node"`
+}
+
+// Represents NDJSON record source (matches GraphQL field names)
+type ndjsonSource struct {
+	Metadata      ndjsonSourceMetadata `json:"metadata"`
+	Namespace     string               `json:"namespace"`
+	PodName       string               `json:"podName"`
+	ContainerName string               `json:"containerName"`
+	ContainerID   string               `json:"containerID"`
+}
+
+// Represents NDJSON record (matches GraphQL field names)
+type ndjsonRecord struct {
+	Timestamp time.Time    `json:"timestamp"`
+	Message   string       `json:"message"`
+	Source    ndjsonSource `json:"source"`
+}
+
+// Writes one JSON object per line
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+// Write
+func (w *ndjsonWriter) Write(record *logs.LogRecord) error {
+	src := record.Source
+	return w.enc.Encode(ndjsonRecord{
+		Timestamp: record.Timestamp,
+		Message:   record.Message,
+		Source: ndjsonSource{
+			Metadata: ndjsonSourceMetadata{
+				Region: src.Metadata.Region,
+				Zone:   src.Metadata.Zone,
+				OS:     src.Metadata.OS,
+				Arch:   src.Metadata.Arch,
+				Node:   src.Metadata.Node,
+			},
+			Namespace:     src.Namespace,
+			PodName:       src.PodName,
+			ContainerName: src.ContainerName,
+			ContainerID:   src.ContainerID,
+		},
+	})
+}
+
+// Flush
+func (w *ndjsonWriter) Flush() error {
+	return nil
+}
+
+// Writes records as CSV rows with a header row
+type csvWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+// Write
+func (w *csvWriter) Write(record *logs.LogRecord) error {
+	if !w.headerWritten {
+		if err := w.w.Write([]string{"timestamp", "namespace", "pod", "container", "node", "message"}); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+
+	return w.w.Write([]string{
+		record.Timestamp.Format(time.RFC3339Nano),
+		record.Source.Namespace,
+		record.Source.PodName,
+		record.Source.ContainerName,
+		record.Source.Metadata.Node,
+		record.Message,
+	})
+}
+
+// Flush
+func (w *csvWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// Writes records as plain text lines
+type textWriter struct {
+	w io.Writer
+}
+
+// Write
+func (w *textWriter) Write(record *logs.LogRecord) error {
+	src := record.Source
+	_, err := fmt.Fprintf(w.w, "%s %s/%s/%s %s\n", record.Timestamp.Format(time.RFC3339Nano), src.Namespace, src.PodName, src.ContainerName, record.Message)
+	return err
+}
+
+// Flush
+func (w *textWriter) Flush() error {
+	return nil
+}
@@ -0,0 +1,216 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forward
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	zlog "github.com/rs/zerolog/log"
+
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+)
+
+// Index used when the url doesn't include one
+const defaultElasticsearchIndex = "kubetail"
+
+// Represents document written for each record (ECS field names)
+type elasticsearchDoc struct {
+	Timestamp  string `json:"@timestamp"`
+	Message    string `json:"message"`
+	Kubernetes struct {
+		Namespace string `json:"namespace,omitempty"`
+		Pod       struct {
+			Name string `json:"name,omitempty"`
+		} `json:"pod,omitzero"`
+		Container struct {
+			Name string `json:"name,omitempty"`
+		} `json:"container,omitzero"`
+		Node struct {
+			Name string `json:"name,omitempty"`
+		} `json:"node,omitzero"`
+	} `json:"kubernetes,omitzero"`
+	Cloud struct {
+		Region           string `json:"region,omitempty"`
+		AvailabilityZone string `json:"availability_zone,omitempty"`
+	} `json:"cloud,omitzero"`
+}
+
+// Represents the parts of a bulk response used to find failed documents
+type elasticsearchBulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int             `json:"status"`
+		Error  json.RawMessage `json:"error"`
+	} `json:"items"`
+}
+
+// Represents sink that sends records to the Elasticsearch bulk API. Document
+// ids are derived from the record so retried requests don't create
+// duplicates.
+type ElasticsearchSink struct {
+	bulkURL string
+	headers map[string]string
+	client  *http.Client
+}
+
+// Create new ElasticsearchSink instance. The index is taken from the url path
+// (e.g. http://elasticsearch:9200/my-index). If `client` is nil a default
+// client is used.
+func NewElasticsearchSink(rawURL string, headers map[string]string, client *http.Client) (*ElasticsearchSink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	index := strings.Trim(u.Path, "/")
+	if index == "" {
+		index = defaultElasticsearchIndex
+	}
+	if strings.Contains(index, "/") {
+		return nil, fmt.Errorf("elasticsearch url path must be an index name: %s", u.Path)
+	}
+	u.Path = "/" + index + "/_bulk"
+
+	if client == nil {
+		client = newHTTPClient()
+	}
+
+	return &ElasticsearchSink{bulkURL: u.String(), headers: headers, client: client}, nil
+}
+
+// Export records in a single bulk request
+func (s *ElasticsearchSink) Export(ctx context.Context, records []logs.LogRecord) error {
+	body, err := newElasticsearchBulkBody(records)
+	if err != nil {
+		return Permanent(err)
+	}
+
+	httpResp, err := postHTTP(ctx, s.client, s.bulkURL, "application/x-ndjson", s.headers, body)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	// Decode from the stream since bulk responses grow with the batch size
+	var resp elasticsearchBulkResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+			return Permanent(err)
+		}
+		// Truncated or interrupted response
+		return err
+	}
+
+	if !resp.Errors {
+		return nil
+	}
+
+	// Documents that already exist were written by an earlier attempt
+	var throttled, rejected int
+	var firstErr json.RawMessage
+	for _, item := range resp.Items {
+		for _, result := range item {
+			switch {
+			case result.Status < 300 || result.Status == http.StatusConflict:
+			case result.Status == http.StatusTooManyRequests:
+				throttled++
+			default:
+				rejected++
+				if firstErr == nil {
+					firstErr = result.Error
+				}
+			}
+		}
+	}
+
+	if rejected > 0 {
+		zlog.Warn().Int("rejected", rejected).RawJSON("error", firstErr).Msg("elasticsearch rejected log records")
+	}
+
+	if throttled > 0 {
+		return fmt.Errorf("elasticsearch throttled %d of %d documents", throttled, len(records))
+	}
+
+	return nil
+}
+
+// Close idle connections
+func (s *ElasticsearchSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// Return bulk request body with a create action per record
+func newElasticsearchBulkBody(records []logs.LogRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+
+	for _, record := range records {
+		action := map[string]any{"create": map[string]string{"_id": elasticsearchDocID(record)}}
+		if err := enc.Encode(action); err != nil {
+			return nil, err
+		}
+		if err := enc.Encode(newElasticsearchDoc(record)); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Convert record to document
+func newElasticsearchDoc(record logs.LogRecord) *elasticsearchDoc {
+	doc := &elasticsearchDoc{
+		Timestamp: record.Timestamp.UTC().Format(time.RFC3339Nano),
+		Message:   record.Message,
+	}
+	doc.Kubernetes.Namespace = record.Source.Namespace
+	doc.Kubernetes.Pod.Name = record.Source.PodName
+	doc.Kubernetes.Container.Name = record.Source.ContainerName
+	doc.Kubernetes.Node.Name = record.Source.Metadata.Node
+	doc.Cloud.Region = record.Source.Metadata.Region
+	doc.Cloud.AvailabilityZone = record.Source.Metadata.Zone
+	return doc
+}
+
+// Return document id derived from the record's source, timestamp and message
+func elasticsearchDocID(record logs.LogRecord) string {
+	h := sha256.New()
+	for _, part := range []string{
+		record.Source.Namespace,
+		record.Source.PodName,
+		record.Source.ContainerName,
+		record.Source.ContainerID,
+		strconv.FormatInt(record.Timestamp.UnixNano(), 10),
+		record.Message,
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
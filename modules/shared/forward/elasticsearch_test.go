@@ -0,0 +1,154 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forward
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+)
+
+// Return bulk request lines decoded as JSON
+func readBulkLines(t *testing.T, r *http.Request) []map[string]any {
+	var lines []map[string]any
+	scanner := bufio.NewScanner(r.Body)
+	for scanner.Scan() {
+		var line map[string]any
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestElasticsearchSinkExport(t *testing.T) {
+	var gotPath, gotUser string
+	var gotLines []map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotUser, _, _ = r.BasicAuth()
+		gotLines = readBulkLines(t, r)
+		w.Write([]byte(`{"errors":false,"items":[]}`))
+	}))
+	defer server.Close()
+
+	sink, err := NewSink("elasticsearch=http://elastic:secret@"+server.Listener.Addr().String()+"/backfill", nil)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	source := logs.LogSource{Namespace: "default", PodName: "web-abc", ContainerName: "web", Metadata: logs.LogSourceMetadata{Node: "node-1", Region: "us-east-1"}}
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 123, time.UTC)
+
+	records := []logs.LogRecord{
+		{Timestamp: ts, Message: "hello", Source: source},
+		{Timestamp: ts.Add(time.Second), Message: "world", Source: source},
+	}
+	require.NoError(t, sink.Export(context.Background(), records))
+
+	assert.Equal(t, "/backfill/_bulk", gotPath)
+	assert.Equal(t, "elastic", gotUser)
+
+	require.Len(t, gotLines, 4)
+	assert.Equal(t, map[string]any{"create": map[string]any{"_id": elasticsearchDocID(records[0])}}, gotLines[0])
+	assert.Equal(t, map[string]any{
+		"@timestamp": "2025-01-01T00:00:00.000000123Z",
+		"message":    "hello",
+		"kubernetes": map[string]any{
+			"namespace": "default",
+			"pod":       map[string]any{"name": "web-abc"},
+			"container": map[string]any{"name": "web"},
+			"node":      map[string]any{"name": "node-1"},
+		},
+		"cloud": map[string]any{"region": "us-east-1"},
+	}, gotLines[1])
+	assert.NotEqual(t, gotLines[0], gotLines[2], "document ids should differ")
+}
+
+func TestElasticsearchSinkItemErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		response      string
+		wantErr       bool
+		wantRetryable bool
+	}{
+		{
+			"conflicts are ignored",
+			`{"errors":true,"items":[{"create":{"status":201}},{"create":{"status":409}}]}`,
+			false,
+			false,
+		},
+		{
+			"rejected documents are dropped",
+			`{"errors":true,"items":[{"create":{"status":201}},{"create":{"status":400,"error":{"type":"mapper_parsing_exception"}}}]}`,
+			false,
+			false,
+		},
+		{
+			"throttled documents are retried",
+			`{"errors":true,"items":[{"create":{"status":201}},{"create":{"status":429}}]}`,
+			true,
+			true,
+		},
+		{
+			"large responses are parsed in full",
+			`{"errors":true,"items":[` + strings.Repeat(`{"create":{"status":409}},`, 50000) + `{"create":{"status":429}}]}`,
+			true,
+			true,
+		},
+		{
+			"truncated responses are retried",
+			`{"errors":true,"items":[{"create":{"status":201}},{"cre`,
+			true,
+			true,
+		},
+		{
+			"invalid responses are permanent",
+			`<html>bad gateway</html>`,
+			true,
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			sink, err := NewElasticsearchSink(server.URL, nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, server.URL+"/"+defaultElasticsearchIndex+"/_bulk", sink.bulkURL)
+
+			err = sink.Export(context.Background(), []logs.LogRecord{{Message: "a"}, {Message: "b"}})
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tt.wantRetryable, isRetryable(err))
+		})
+	}
+}
@@ -0,0 +1,331 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	zlog "github.com/rs/zerolog/log"
+
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+	"github.com/kubetail-org/kubetail/modules/shared/metrics"
+)
+
+// Default forwarder settings
+const (
+	DefaultBatchSize     = 512
+	DefaultFlushInterval = time.Second
+	DefaultMaxRetries    = 5
+	DefaultQueueSize     = 4096
+)
+
+// Delay before the first retry (doubled after each attempt)
+const initialRetryDelay = 500 * time.Millisecond
+
+// Upper bound on the delay between retries
+const maxRetryDelay = 30 * time.Second
+
+// Returned by Forward after the forwarder has been closed
+var errForwarderClosed = errors.New("forwarder closed")
+
+// Sink types
+const (
+	SinkOTLP          = "otlp"
+	SinkLoki          = "loki"
+	SinkElasticsearch = "elasticsearch"
+)
+
+// ParseSinkSpec returns the sink type and url of the given spec. The spec is
+// either an OTLP url whose scheme selects the transport (e.g.
+// otlp://collector:4317) or a url prefixed with the sink type (e.g.
+// loki=http://loki:3100).
+func ParseSinkSpec(spec string) (sinkType string, rawURL string) {
+	sinkType, rawURL, found := strings.Cut(spec, "=")
+	if !found || strings.Contains(sinkType, "://") {
+		return SinkOTLP, spec
+	}
+	return sinkType, rawURL
+}
+
+// NewSink returns a sink for the given spec (see ParseSinkSpec)
+func NewSink(spec string, headers map[string]string) (logs.Sink, error) {
+	sinkType, rawURL := ParseSinkSpec(spec)
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("forward url is missing a host: %s", rawURL)
+	}
+
+	switch sinkType {
+	case SinkOTLP:
+		switch u.Scheme {
+		case "otlp":
+			return NewOTLPSink(u.Host, false, headers)
+		case "otlps":
+			return NewOTLPSink(u.Host, true, headers)
+		default:
+			return nil, fmt.Errorf("otlp scheme not supported: %s", u.Scheme)
+		}
+	case SinkLoki:
+		return NewLokiSink(rawURL, headers, nil)
+	case SinkElasticsearch:
+		return NewElasticsearchSink(rawURL, headers, nil)
+	default:
+		return nil, fmt.Errorf("sink type not supported: %s", sinkType)
+	}
+}
+
+// Represents an error that won't go away if the export is retried
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks an export error as not retryable
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+// Return true if the export should be retried
+func isRetryable(err error) bool {
+	var perr *permanentError
+	return !errors.As(err, &perr)
+}
+
+// Represents variadic option type for Forwarder
+type Option func(f *Forwarder)
+
+// WithBatchSize sets the maximum number of records sent in a single export
+func WithBatchSize(n int) Option {
+	return func(f *Forwarder) {
+		f.batchSize = n
+	}
+}
+
+// WithFlushInterval sets the maximum time a record waits before its batch
+// is exported
+func WithFlushInterval(d time.Duration) Option {
+	return func(f *Forwarder) {
+		f.flushInterval = d
+	}
+}
+
+// WithMaxRetries sets the number of times a failed export is retried before
+// its records are dropped
+func WithMaxRetries(n int) Option {
+	return func(f *Forwarder) {
+		f.maxRetries = n
+	}
+}
+
+// WithQueueSize sets the number of records buffered ahead of the exporter
+func WithQueueSize(n int) Option {
+	return func(f *Forwarder) {
+		f.queueSize = n
+	}
+}
+
+// Forwarder batches log records and exports them to a sink from a
+// background goroutine. Failed exports are retried with exponential backoff.
+type Forwarder struct {
+	sink          logs.Sink
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+	queueSize     int
+	ch            chan logs.LogRecord
+	ctx           context.Context
+	cancel        context.CancelFunc
+	mu            sync.RWMutex
+	stopCh        chan struct{}
+	drainCh       chan struct{}
+	doneCh        chan struct{}
+	closeOnce     sync.Once
+}
+
+// Create new Forwarder instance
+func NewForwarder(sink logs.Sink, opts ...Option) *Forwarder {
+	f := &Forwarder{
+		sink:          sink,
+		batchSize:     DefaultBatchSize,
+		flushInterval: DefaultFlushInterval,
+		maxRetries:    DefaultMaxRetries,
+		queueSize:     DefaultQueueSize,
+		stopCh:        make(chan struct{}),
+		drainCh:       make(chan struct{}),
+		doneCh:        make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	f.ch = make(chan logs.LogRecord, f.queueSize)
+	f.ctx, f.cancel = context.WithCancel(context.Background())
+
+	go f.run()
+
+	return f
+}
+
+// Forward queues record for export. Blocks while the queue is full so
+// producers slow down instead of losing records. Synthetic lifecycle
+// markers are skipped.
+func (f *Forwarder) Forward(ctx context.Context, record logs.LogRecord) error {
+	if record.Lifecycle != nil {
+		return nil
+	}
+
+	// Hold read lock so Close waits for in-flight calls before draining
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	select {
+	case <-f.stopCh:
+		return errForwarderClosed
+	default:
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-f.stopCh:
+		return errForwarderClosed
+	case f.ch <- record:
+		return nil
+	}
+}
+
+// Close exports queued records and closes the sink. If `ctx` expires first,
+// the remaining records are dropped.
+func (f *Forwarder) Close(ctx context.Context) error {
+	f.closeOnce.Do(func() {
+		close(f.stopCh)
+
+		// Wait for in-flight Forward calls so no record is queued after the
+		// queue has been drained
+		f.mu.Lock()
+		close(f.drainCh)
+		f.mu.Unlock()
+	})
+
+	var err error
+	select {
+	case <-f.doneCh:
+	case <-ctx.Done():
+		// Abandon pending exports
+		err = ctx.Err()
+		f.cancel()
+		<-f.doneCh
+	}
+	f.cancel()
+
+	if closeErr := f.sink.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// Collect records into batches until the forwarder is closed
+func (f *Forwarder) run() {
+	defer close(f.doneCh)
+
+	ticker := time.NewTicker(f.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]logs.LogRecord, 0, f.batchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		f.export(f.ctx, batch)
+		batch = make([]logs.LogRecord, 0, f.batchSize)
+	}
+
+	for {
+		select {
+		case record := <-f.ch:
+			batch = append(batch, record)
+			if len(batch) >= f.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-f.drainCh:
+			// Drain queue
+			for {
+				select {
+				case record := <-f.ch:
+					batch = append(batch, record)
+					if len(batch) >= f.batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// Export batch, retrying with exponential backoff on transient errors
+func (f *Forwarder) export(ctx context.Context, batch []logs.LogRecord) {
+	delay := initialRetryDelay
+
+	for attempt := 0; ; attempt++ {
+		err := f.sink.Export(ctx, batch)
+		if err == nil {
+			metrics.ExportedRecords.WithLabelValues("sent").Add(float64(len(batch)))
+			return
+		}
+
+		if ctx.Err() != nil || !isRetryable(err) || attempt >= f.maxRetries {
+			metrics.ExportedRecords.WithLabelValues("dropped").Add(float64(len(batch)))
+			zlog.Error().Err(err).Int("records", len(batch)).Msg("failed to forward log records")
+			return
+		}
+
+		zlog.Debug().Err(err).Int("attempt", attempt+1).Msg("retrying log record export")
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forward
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Timeout for a single http export request
+const httpExportTimeout = 10 * time.Second
+
+// Max number of response body bytes read for error messages
+const maxResponseBodySize = 1 << 20
+
+// Return default client for http sinks
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: httpExportTimeout}
+}
+
+// Send body to url and return the response. Throttling and server errors are
+// retryable, other non-2xx responses are permanent. The caller must close the
+// response body.
+func postHTTP(ctx context.Context, client *http.Client, url string, contentType string, headers map[string]string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, Permanent(err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
+	if err != nil {
+		return nil, err
+	}
+
+	err = fmt.Errorf("%s returned %s: %s", url, resp.Status, bytes.TrimSpace(respBody))
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return nil, err
+	}
+	return nil, Permanent(err)
+}
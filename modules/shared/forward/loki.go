@@ -0,0 +1,123 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forward
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+)
+
+// Path of the push endpoint used when the url doesn't include one
+const lokiPushPath = "/loki/api/v1/push"
+
+// Represents Loki push request body
+type lokiPushRequest struct {
+	Streams []*lokiStream `json:"streams"`
+}
+
+// Represents Loki stream with its label set and [timestamp, line] values
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// Represents sink that sends records to the Loki push API
+type LokiSink struct {
+	pushURL string
+	headers map[string]string
+	client  *http.Client
+}
+
+// Create new LokiSink instance. If `client` is nil a default client is used.
+func NewLokiSink(rawURL string, headers map[string]string, client *http.Client) (*LokiSink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Path == "" || u.Path == "/" {
+		u.Path = lokiPushPath
+	}
+
+	if client == nil {
+		client = newHTTPClient()
+	}
+
+	return &LokiSink{pushURL: u.String(), headers: headers, client: client}, nil
+}
+
+// Export records in a single push request
+func (s *LokiSink) Export(ctx context.Context, records []logs.LogRecord) error {
+	body, err := json.Marshal(newLokiPushRequest(records))
+	if err != nil {
+		return Permanent(err)
+	}
+
+	resp, err := postHTTP(ctx, s.client, s.pushURL, "application/json", s.headers, body)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Close idle connections
+func (s *LokiSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// Convert records to a push request with one stream per source
+func newLokiPushRequest(records []logs.LogRecord) *lokiPushRequest {
+	req := &lokiPushRequest{}
+	bySource := make(map[logs.LogSource]*lokiStream)
+
+	for _, record := range records {
+		stream, exists := bySource[record.Source]
+		if !exists {
+			stream = &lokiStream{Stream: lokiLabels(record.Source)}
+			req.Streams = append(req.Streams, stream)
+			bySource[record.Source] = stream
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(record.Timestamp.UnixNano(), 10), record.Message})
+	}
+
+	return req
+}
+
+// Return stream labels describing the source (empty values are skipped)
+func lokiLabels(source logs.LogSource) map[string]string {
+	labels := map[string]string{"job": "kubetail"}
+	for _, kv := range []struct {
+		key   string
+		value string
+	}{
+		{"namespace", source.Namespace},
+		{"pod", source.PodName},
+		{"container", source.ContainerName},
+		{"node", source.Metadata.Node},
+		{"region", source.Metadata.Region},
+		{"zone", source.Metadata.Zone},
+	} {
+		if kv.value != "" {
+			labels[kv.key] = kv.value
+		}
+	}
+	return labels
+}
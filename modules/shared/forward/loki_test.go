@@ -0,0 +1,94 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forward
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+)
+
+func TestLokiSinkExport(t *testing.T) {
+	var gotPath, gotOrgID string
+	var got lokiPushRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotOrgID = r.Header.Get("X-Scope-OrgID")
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink, err := NewSink("loki="+server.URL, map[string]string{"x-scope-orgid": "team-1"})
+	require.NoError(t, err)
+	defer sink.Close()
+
+	web := logs.LogSource{Namespace: "default", PodName: "web-abc", ContainerName: "web", Metadata: logs.LogSourceMetadata{Node: "node-1", Zone: "us-east-1a"}}
+	worker := logs.LogSource{Namespace: "default", PodName: "worker-xyz", ContainerName: "worker"}
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	err = sink.Export(context.Background(), []logs.LogRecord{
+		{Timestamp: ts, Message: "hello", Source: web},
+		{Timestamp: ts.Add(time.Second), Message: "working", Source: worker},
+		{Timestamp: ts.Add(2 * time.Second), Message: "world", Source: web},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, lokiPushPath, gotPath)
+	assert.Equal(t, "team-1", gotOrgID)
+
+	require.Len(t, got.Streams, 2)
+	assert.Equal(t, map[string]string{"job": "kubetail", "namespace": "default", "pod": "web-abc", "container": "web", "node": "node-1", "zone": "us-east-1a"}, got.Streams[0].Stream)
+	assert.Equal(t, [][2]string{{"1735689600000000000", "hello"}, {"1735689602000000000", "world"}}, got.Streams[0].Values)
+	assert.Equal(t, map[string]string{"job": "kubetail", "namespace": "default", "pod": "worker-xyz", "container": "worker"}, got.Streams[1].Stream)
+}
+
+func TestLokiSinkErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		wantRetryable bool
+	}{
+		{"too many requests", http.StatusTooManyRequests, true},
+		{"server error", http.StatusBadGateway, true},
+		{"bad request", http.StatusBadRequest, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "failed", tt.status)
+			}))
+			defer server.Close()
+
+			sink, err := NewLokiSink(server.URL+"/custom/push", nil, nil)
+			require.NoError(t, err)
+
+			err = sink.Export(context.Background(), []logs.LogRecord{{Message: "hello"}})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "/custom/push")
+			assert.Equal(t, tt.wantRetryable, isRetryable(err))
+		})
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forward
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	zlog "github.com/rs/zerolog/log"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+)
+
+// Instrumentation scope attached to forwarded records
+const otlpScopeName = "github.com/kubetail-org/kubetail/modules/shared/forward"
+
+// Timeout for a single export request
+const otlpExportTimeout = 10 * time.Second
+
+// Represents sink that sends records to an OTLP/gRPC logs endpoint
+type OTLPSink struct {
+	conn    *grpc.ClientConn
+	client  collogspb.LogsServiceClient
+	headers metadata.MD
+}
+
+// Create new OTLPSink instance
+func NewOTLPSink(endpoint string, useTLS bool, headers map[string]string) (*OTLPSink, error) {
+	creds := insecure.NewCredentials()
+	if useTLS {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+
+	conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+
+	return &OTLPSink{
+		conn:    conn,
+		client:  collogspb.NewLogsServiceClient(conn),
+		headers: metadata.New(headers),
+	}, nil
+}
+
+// Export records in a single request
+func (s *OTLPSink) Export(ctx context.Context, records []logs.LogRecord) error {
+	ctx, cancel := context.WithTimeout(ctx, otlpExportTimeout)
+	defer cancel()
+
+	if len(s.headers) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, s.headers)
+	}
+
+	resp, err := s.client.Export(ctx, newOTLPExportRequest(records, time.Now()))
+	if err != nil {
+		if isRetryableOTLPCode(status.Code(err)) {
+			return err
+		}
+		return Permanent(err)
+	}
+
+	if ps := resp.GetPartialSuccess(); ps.GetRejectedLogRecords() > 0 {
+		zlog.Warn().Int64("rejected", ps.GetRejectedLogRecords()).Str("message", ps.GetErrorMessage()).Msg("otlp endpoint rejected log records")
+	}
+
+	return nil
+}
+
+// Close connection
+func (s *OTLPSink) Close() error {
+	return s.conn.Close()
+}
+
+// Return true if the OTLP spec allows retrying a request that failed with
+// this code
+func isRetryableOTLPCode(code codes.Code) bool {
+	switch code {
+	case codes.Canceled, codes.DeadlineExceeded, codes.Aborted, codes.OutOfRange, codes.Unavailable, codes.DataLoss, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// Convert records to an OTLP export request with one resource per source
+func newOTLPExportRequest(records []logs.LogRecord, observedAt time.Time) *collogspb.ExportLogsServiceRequest {
+	var resourceLogs []*logspb.ResourceLogs
+	bySource := make(map[logs.LogSource]*logspb.ScopeLogs)
+
+	for _, record := range records {
+		scopeLogs, exists := bySource[record.Source]
+		if !exists {
+			scopeLogs = &logspb.ScopeLogs{
+				Scope: &commonpb.InstrumentationScope{Name: otlpScopeName},
+			}
+			resourceLogs = append(resourceLogs, &logspb.ResourceLogs{
+				Resource:  &resourcepb.Resource{Attributes: otlpResourceAttributes(record.Source)},
+				ScopeLogs: []*logspb.ScopeLogs{scopeLogs},
+			})
+			bySource[record.Source] = scopeLogs
+		}
+		scopeLogs.LogRecords = append(scopeLogs.LogRecords, newOTLPLogRecord(record, observedAt))
+	}
+
+	return &collogspb.ExportLogsServiceRequest{ResourceLogs: resourceLogs}
+}
+
+// Return resource attributes describing the source (empty values are skipped)
+func otlpResourceAttributes(source logs.LogSource) []*commonpb.KeyValue {
+	var attrs []*commonpb.KeyValue
+	for _, kv := range []struct {
+		key   string
+		value string
+	}{
+		{"k8s.namespace.name", source.Namespace},
+		{"k8s.pod.name", source.PodName},
+		{"k8s.container.name", source.ContainerName},
+		{"k8s.node.name", source.Metadata.Node},
+		{"cloud.region", source.Metadata.Region},
+		{"cloud.availability_zone", source.Metadata.Zone},
+	} {
+		if kv.value != "" {
+			attrs = append(attrs, otlpStringAttribute(kv.key, kv.value))
+		}
+	}
+	return attrs
+}
+
+// Convert record to OTLP log record
+func newOTLPLogRecord(record logs.LogRecord, observedAt time.Time) *logspb.LogRecord {
+	lr := &logspb.LogRecord{
+		TimeUnixNano:         uint64(record.Timestamp.UnixNano()),
+		ObservedTimeUnixNano: uint64(observedAt.UnixNano()),
+		Body:                 &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: record.Message}},
+	}
+
+	// Kubernetes events carry a severity and describe the involved object
+	if ev := record.Event; ev != nil {
+		lr.SeverityText = ev.Type
+		lr.SeverityNumber = logspb.SeverityNumber_SEVERITY_NUMBER_INFO
+		if ev.Type == "Warning" {
+			lr.SeverityNumber = logspb.SeverityNumber_SEVERITY_NUMBER_WARN
+		}
+		lr.Attributes = []*commonpb.KeyValue{
+			otlpStringAttribute("k8s.event.reason", ev.Reason),
+			otlpStringAttribute("k8s.object.kind", ev.Kind),
+			otlpStringAttribute("k8s.object.name", ev.Name),
+		}
+	}
+
+	return lr
+}
+
+// Return string-valued key/value pair
+func otlpStringAttribute(key string, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key:   key,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}},
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forward
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+)
+
+// Stand-in for an OTLP collector that records export requests
+type testCollector struct {
+	collogspb.UnimplementedLogsServiceServer
+
+	mu       sync.Mutex
+	requests []*collogspb.ExportLogsServiceRequest
+	headers  []metadata.MD
+	err      error
+}
+
+func (c *testCollector) Export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	c.requests = append(c.requests, req)
+	c.headers = append(c.headers, md)
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+// Start collector on a local port and return its address
+func startTestCollector(t *testing.T, c *testCollector) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	collogspb.RegisterLogsServiceServer(server, c)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String()
+}
+
+// Return attributes as a map of string values
+func attributeMap(attrs []*commonpb.KeyValue) map[string]string {
+	m := make(map[string]string)
+	for _, kv := range attrs {
+		m[kv.Key] = kv.Value.GetStringValue()
+	}
+	return m
+}
+
+func TestOTLPSinkExport(t *testing.T) {
+	collector := &testCollector{}
+	addr := startTestCollector(t, collector)
+
+	sink, err := NewSink("otlp://"+addr, map[string]string{"authorization": "Bearer xyz"})
+	require.NoError(t, err)
+
+	web := logs.LogSource{
+		Namespace:     "default",
+		PodName:       "web-abc",
+		ContainerName: "web",
+		Metadata:      logs.LogSourceMetadata{Node: "node-1", Region: "us-east-1", Zone: "us-east-1a"},
+	}
+	worker := logs.LogSource{Namespace: "default", PodName: "worker-xyz", ContainerName: "worker"}
+
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	fwd := NewForwarder(sink)
+	for _, r := range []logs.LogRecord{
+		{Timestamp: ts, Message: "hello", Source: web},
+		{Timestamp: ts.Add(time.Second), Message: "working", Source: worker},
+		{Timestamp: ts.Add(2 * time.Second), Message: "world", Source: web},
+		{Timestamp: ts.Add(3 * time.Second), Message: "Back-off restarting", Source: web, Event: &logs.KubeEvent{Type: "Warning", Reason: "BackOff", Kind: "Pod", Name: "web-abc"}},
+	} {
+		require.NoError(t, fwd.Forward(context.Background(), r))
+	}
+	require.NoError(t, fwd.Close(context.Background()))
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	require.Len(t, collector.requests, 1)
+	assert.Equal(t, []string{"Bearer xyz"}, collector.headers[0].Get("authorization"))
+
+	// One resource per source in order of first appearance
+	resourceLogs := collector.requests[0].ResourceLogs
+	require.Len(t, resourceLogs, 2)
+
+	assert.Equal(t, map[string]string{
+		"k8s.namespace.name":      "default",
+		"k8s.pod.name":            "web-abc",
+		"k8s.container.name":      "web",
+		"k8s.node.name":           "node-1",
+		"cloud.region":            "us-east-1",
+		"cloud.availability_zone": "us-east-1a",
+	}, attributeMap(resourceLogs[0].Resource.Attributes))
+
+	assert.Equal(t, map[string]string{
+		"k8s.namespace.name": "default",
+		"k8s.pod.name":       "worker-xyz",
+		"k8s.container.name": "worker",
+	}, attributeMap(resourceLogs[1].Resource.Attributes))
+
+	webRecords := resourceLogs[0].ScopeLogs[0].LogRecords
+	require.Len(t, webRecords, 3)
+	assert.Equal(t, "hello", webRecords[0].Body.GetStringValue())
+	assert.Equal(t, uint64(ts.UnixNano()), webRecords[0].TimeUnixNano)
+	assert.NotZero(t, webRecords[0].ObservedTimeUnixNano)
+	assert.Equal(t, "world", webRecords[1].Body.GetStringValue())
+
+	// Events
+	assert.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_WARN, webRecords[2].SeverityNumber)
+	assert.Equal(t, "Warning", webRecords[2].SeverityText)
+	assert.Equal(t, map[string]string{
+		"k8s.event.reason": "BackOff",
+		"k8s.object.kind":  "Pod",
+		"k8s.object.name":  "web-abc",
+	}, attributeMap(webRecords[2].Attributes))
+
+	workerRecords := resourceLogs[1].ScopeLogs[0].LogRecords
+	require.Len(t, workerRecords, 1)
+	assert.Equal(t, "working", workerRecords[0].Body.GetStringValue())
+}
+
+func TestOTLPSinkErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		code          codes.Code
+		wantRetryable bool
+	}{
+		{"unavailable", codes.Unavailable, true},
+		{"resource exhausted", codes.ResourceExhausted, true},
+		{"invalid argument", codes.InvalidArgument, false},
+		{"unauthenticated", codes.Unauthenticated, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := &testCollector{err: status.Error(tt.code, "failed")}
+			addr := startTestCollector(t, collector)
+
+			sink, err := NewOTLPSink(addr, false, nil)
+			require.NoError(t, err)
+			defer sink.Close()
+
+			err = sink.Export(context.Background(), []logs.LogRecord{{Message: "hello"}})
+			require.Error(t, err)
+			assert.Equal(t, tt.code, status.Code(err))
+			assert.Equal(t, tt.wantRetryable, isRetryable(err))
+		})
+	}
+}
@@ -14,7 +14,11 @@
 
 package errors
 
-import "github.com/vektah/gqlparser/v2/gqlerror"
+import (
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
 
 // custom errors
 var (
@@ -24,6 +28,8 @@ var (
 	ErrForbidden           = NewError("KUBETAIL_FORBIDDEN", "Forbidden")
 	ErrWatchError          = NewError("KUBETAIL_WATCH_ERROR", "Watch error")
 	ErrServiceUnavailable  = NewError("KUBETAIL_SERVICE_UNAVAILABLE", "Service unavailable")
+	ErrRateLimited         = NewError("KUBETAIL_RATE_LIMITED", "Rate limit exceeded")
+	ErrSlowSubscriber      = NewError("KUBETAIL_SLOW_SUBSCRIBER", "Subscriber fell too far behind the log stream")
 	ErrInternalServerError = NewError("INTERNAL_SERVER_ERROR", "Internal server error")
 )
 
@@ -47,3 +53,28 @@ func NewValidationError(rule string, message string) *gqlerror.Error {
 		},
 	}
 }
+
+// New quota exceeded error
+func NewQuotaExceededError(quota string, limit int) *gqlerror.Error {
+	return &gqlerror.Error{
+		Message: fmt.Sprintf("Quota exceeded: %s (limit %d)", quota, limit),
+		Extensions: map[string]interface{}{
+			"code":  "KUBETAIL_QUOTA_EXCEEDED",
+			"quota": quota,
+			"limit": limit,
+		},
+	}
+}
+
+// New query limit exceeded error
+func NewQueryLimitExceededError(limit string, value int, max int) *gqlerror.Error {
+	return &gqlerror.Error{
+		Message: fmt.Sprintf("Query %s of %d exceeds the limit of %d", limit, value, max),
+		Extensions: map[string]interface{}{
+			"code":  "KUBETAIL_QUERY_LIMIT_EXCEEDED",
+			"limit": limit,
+			"value": value,
+			"max":   max,
+		},
+	}
+}
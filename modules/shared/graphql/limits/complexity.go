@@ -0,0 +1,71 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package limits
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Multiplier used for queries across all namespaces when the allowed
+// namespaces aren't restricted
+const allNamespacesMultiplier = 10
+
+// Multiplier used for list queries without a page limit
+const unboundedListMultiplier = 10
+
+// Number of list items that count as one unit of work
+const listItemsPerUnit = 100
+
+// Number of log records that count as one unit of work
+const logRecordsPerUnit = 10
+
+// NamespacedListComplexity returns a complexity function for namespaced
+// list/watch fields. Queries across all namespaces and queries without a page
+// limit are scored higher. `allowedNamespaces` is called on every query.
+func NamespacedListComplexity(allowedNamespaces func() []string) func(childComplexity int, kubeContext *string, namespace *string, options *metav1.ListOptions) int {
+	return func(childComplexity int, kubeContext *string, namespace *string, options *metav1.ListOptions) int {
+		return ClusterListComplexity(childComplexity, kubeContext, options) * NamespaceMultiplier(allowedNamespaces(), namespace)
+	}
+}
+
+// ClusterListComplexity is the complexity function for cluster-scoped
+// list/watch fields
+func ClusterListComplexity(childComplexity int, kubeContext *string, options *metav1.ListOptions) int {
+	multiplier := unboundedListMultiplier
+	if options != nil && options.Limit > 0 {
+		multiplier = max(1, int(options.Limit)/listItemsPerUnit)
+	}
+	return 1 + childComplexity*multiplier
+}
+
+// NamespaceMultiplier returns the number of namespaces a query will touch
+func NamespaceMultiplier(allowedNamespaces []string, namespace *string) int {
+	if namespace == nil || *namespace != metav1.NamespaceAll {
+		return 1
+	}
+	if len(allowedNamespaces) > 0 {
+		return len(allowedNamespaces)
+	}
+	return allNamespacesMultiplier
+}
+
+// LogRecordsFetchComplexity scores log record fetches by their limit
+func LogRecordsFetchComplexity(childComplexity int, limit *int) int {
+	n := 100
+	if limit != nil {
+		n = *limit
+	}
+	return 1 + childComplexity*max(1, n/logRecordsPerUnit)
+}
@@ -0,0 +1,151 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package limits
+
+import (
+	"context"
+	"strings"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	gqlerrors "github.com/kubetail-org/kubetail/modules/shared/graphql/errors"
+	"github.com/kubetail-org/kubetail/modules/shared/metrics"
+)
+
+// Limit names used in errors and metrics
+const (
+	LimitComplexity = "complexity"
+	LimitDepth      = "depth"
+	LimitAliases    = "aliases"
+)
+
+// Limits holds max values for incoming operations. Zero values mean
+// unlimited.
+type Limits struct {
+	MaxComplexity int
+	MaxDepth      int
+	MaxAliases    int
+}
+
+// Extension is a gqlgen extension that rejects operations that exceed
+// the configured limits
+type Extension struct {
+	limits Limits
+	es     graphql.ExecutableSchema
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = &Extension{}
+
+// Create new Extension instance
+func New(limits Limits) *Extension {
+	return &Extension{limits: limits}
+}
+
+// ExtensionName
+func (e *Extension) ExtensionName() string {
+	return "QueryLimits"
+}
+
+// Validate
+func (e *Extension) Validate(schema graphql.ExecutableSchema) error {
+	e.es = schema
+	return nil
+}
+
+// MutateOperationContext
+func (e *Extension) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	op := opCtx.Operation
+
+	// Check depth
+	if e.limits.MaxDepth > 0 {
+		if depth := selectionSetDepth(op.SelectionSet); depth > e.limits.MaxDepth {
+			return reject(LimitDepth, depth, e.limits.MaxDepth)
+		}
+	}
+
+	// Check aliases
+	if e.limits.MaxAliases > 0 {
+		if n := countAliases(op.SelectionSet); n > e.limits.MaxAliases {
+			return reject(LimitAliases, n, e.limits.MaxAliases)
+		}
+	}
+
+	// Check complexity
+	score := complexity.Calculate(ctx, e.es, op, opCtx.Variables)
+	metrics.OperationComplexity.Observe(float64(score))
+
+	if e.limits.MaxComplexity > 0 && score > e.limits.MaxComplexity {
+		return reject(LimitComplexity, score, e.limits.MaxComplexity)
+	}
+
+	return nil
+}
+
+// Record rejection and return error
+func reject(limit string, value int, max int) *gqlerror.Error {
+	metrics.RejectedOperations.WithLabelValues(limit).Inc()
+	return gqlerrors.NewQueryLimitExceededError(limit, value, max)
+}
+
+// Returns max field depth of selection set. Introspection fields are ignored
+// so that GraphQL clients can still load the schema.
+func selectionSetDepth(selectionSet ast.SelectionSet) int {
+	maxDepth := 0
+	for _, sel := range selectionSet {
+		depth := 0
+		switch s := sel.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			depth = 1 + selectionSetDepth(s.SelectionSet)
+		case *ast.InlineFragment:
+			depth = selectionSetDepth(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				depth = selectionSetDepth(s.Definition.SelectionSet)
+			}
+		}
+		maxDepth = max(maxDepth, depth)
+	}
+	return maxDepth
+}
+
+// Returns number of aliased fields in selection set
+func countAliases(selectionSet ast.SelectionSet) int {
+	n := 0
+	for _, sel := range selectionSet {
+		switch s := sel.(type) {
+		case *ast.Field:
+			if s.Alias != "" && s.Alias != s.Name {
+				n += 1
+			}
+			n += countAliases(s.SelectionSet)
+		case *ast.InlineFragment:
+			n += countAliases(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				n += countAliases(s.Definition.SelectionSet)
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,210 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package limits
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubetail-org/kubetail/modules/shared/metrics"
+)
+
+var testSchema = gqlparser.MustLoadSchema(&ast.Source{Input: `
+	type Query {
+		node: Node
+		list(limit: Int): [Node!]!
+	}
+
+	type Node {
+		name: String!
+		child: Node
+	}
+`})
+
+// Returns mock schema that scores `list` by its limit argument
+func newTestExecutableSchema() graphql.ExecutableSchema {
+	return &graphql.ExecutableSchemaMock{
+		SchemaFunc: func() *ast.Schema {
+			return testSchema
+		},
+		ComplexityFunc: func(ctx context.Context, typeName string, fieldName string, childComplexity int, args map[string]any) (int, bool) {
+			if typeName == "Query" && fieldName == "list" {
+				limit, _ := args["limit"].(int64)
+				return 1 + childComplexity*int(limit), true
+			}
+			return 0, false
+		},
+	}
+}
+
+// Runs query through extension
+func runQuery(t *testing.T, limits Limits, query string) error {
+	doc := gqlparser.MustLoadQuery(testSchema, query)
+	require.Len(t, doc.Operations, 1)
+
+	ext := New(limits)
+	require.NoError(t, ext.Validate(newTestExecutableSchema()))
+
+	opCtx := &graphql.OperationContext{
+		Doc:       doc,
+		Operation: doc.Operations[0],
+		Variables: map[string]any{},
+	}
+
+	if gqlErr := ext.MutateOperationContext(context.Background(), opCtx); gqlErr != nil {
+		return gqlErr
+	}
+	return nil
+}
+
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		setLimits Limits
+		setQuery  string
+		wantLimit string
+		wantValue int
+	}{
+		{
+			"unlimited",
+			Limits{},
+			`{ a: node { name } b: node { child { child { child { name } } } } list(limit: 1000) { name } }`,
+			"",
+			0,
+		},
+		{
+			"depth within limit",
+			Limits{MaxDepth: 3},
+			`{ node { child { name } } }`,
+			"",
+			0,
+		},
+		{
+			"depth exceeded",
+			Limits{MaxDepth: 3},
+			`{ node { child { child { name } } } }`,
+			LimitDepth,
+			4,
+		},
+		{
+			"depth exceeded via fragments",
+			Limits{MaxDepth: 3},
+			`{ node { ...F } } fragment F on Node { child { ... on Node { child { name } } } }`,
+			LimitDepth,
+			4,
+		},
+		{
+			"introspection is ignored",
+			Limits{MaxDepth: 1},
+			`{ __schema { types { fields { type { name } } } } }`,
+			"",
+			0,
+		},
+		{
+			"aliases within limit",
+			Limits{MaxAliases: 2},
+			`{ a: node { name } b: node { name } }`,
+			"",
+			0,
+		},
+		{
+			"aliases exceeded",
+			Limits{MaxAliases: 2},
+			`{ a: node { name } b: node { name } node { c: name } }`,
+			LimitAliases,
+			3,
+		},
+		{
+			"complexity within limit",
+			Limits{MaxComplexity: 100},
+			`{ list(limit: 10) { name } }`,
+			"",
+			0,
+		},
+		{
+			"complexity exceeded",
+			Limits{MaxComplexity: 100},
+			`{ list(limit: 1000) { name } }`,
+			LimitComplexity,
+			1001,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantLimit == "" {
+				require.NoError(t, runQuery(t, tt.setLimits, tt.setQuery))
+				return
+			}
+
+			rejectedBefore := promtestutil.ToFloat64(metrics.RejectedOperations.WithLabelValues(tt.wantLimit))
+
+			err := runQuery(t, tt.setLimits, tt.setQuery)
+
+			var gqlErr *gqlerror.Error
+			require.True(t, errors.As(err, &gqlErr))
+			assert.Equal(t, "KUBETAIL_QUERY_LIMIT_EXCEEDED", gqlErr.Extensions["code"])
+			assert.Equal(t, tt.wantLimit, gqlErr.Extensions["limit"])
+			assert.Equal(t, tt.wantValue, gqlErr.Extensions["value"])
+
+			// Check metric
+			rejectedAfter := promtestutil.ToFloat64(metrics.RejectedOperations.WithLabelValues(tt.wantLimit))
+			assert.Equal(t, rejectedBefore+1, rejectedAfter)
+		})
+	}
+}
+
+func TestListComplexity(t *testing.T) {
+	ns := func(s string) *string { return &s }
+
+	tests := []struct {
+		name              string
+		allowedNamespaces []string
+		namespace         *string
+		options           *metav1.ListOptions
+		want              int
+	}{
+		{"default namespace, unbounded", nil, nil, nil, 1 + 10*10},
+		{"single namespace, limit", nil, ns("ns1"), &metav1.ListOptions{Limit: 200}, 1 + 10*2},
+		{"single namespace, small limit", nil, ns("ns1"), &metav1.ListOptions{Limit: 5}, 1 + 10*1},
+		{"all namespaces, unrestricted", nil, ns(""), &metav1.ListOptions{Limit: 100}, (1 + 10*1) * 10},
+		{"all namespaces, allowed", []string{"ns1", "ns2"}, ns(""), &metav1.ListOptions{Limit: 100}, (1 + 10*1) * 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := NamespacedListComplexity(func() []string { return tt.allowedNamespaces })
+			assert.Equal(t, tt.want, fn(10, nil, tt.namespace, tt.options))
+		})
+	}
+}
+
+func TestLogRecordsFetchComplexity(t *testing.T) {
+	limit := func(n int) *int { return &n }
+
+	assert.Equal(t, 1+5*10, LogRecordsFetchComplexity(5, nil))
+	assert.Equal(t, 1+5*1, LogRecordsFetchComplexity(5, limit(1)))
+	assert.Equal(t, 1+5*100, LogRecordsFetchComplexity(5, limit(1000)))
+}
@@ -19,11 +19,23 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+	"k8s.io/client-go/rest"
 )
 
 type ctxKey int
 
-const K8STokenCtxKey ctxKey = iota
+const (
+	K8STokenCtxKey ctxKey = iota
+	ImpersonateCtxKey
+)
+
+// Metadata keys used to forward an impersonated identity. The agent sends
+// them to the Kubernetes API alongside the bearer token.
+const (
+	impersonateUserMDKey  = "impersonate-user"
+	impersonateUIDMDKey   = "impersonate-uid"
+	impersonateGroupMDKey = "impersonate-group"
+)
 
 // Represents wrap of original stream that returns modified context
 type wrappedStream struct {
@@ -37,14 +49,8 @@ func (s *wrappedStream) Context() context.Context {
 
 // Create new auth server unary interceptor
 func AuthUnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-	// Add token to context, if present
-	if md, ok := metadata.FromIncomingContext(ctx); ok {
-		authorization := md["authorization"]
-		if len(authorization) > 0 {
-			// Add token to context
-			ctx = context.WithValue(ctx, K8STokenCtxKey, authorization[0])
-		}
-	}
+	// Add token and impersonated identity to context, if present
+	ctx = withIncomingAuth(ctx)
 
 	// Continue
 	return handler(ctx, req)
@@ -52,10 +58,8 @@ func AuthUnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc
 
 // Create new auth client unary interceptor
 func AuthUnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
-	// Get token context and add to metadata, if present
-	if token, ok := ctx.Value(K8STokenCtxKey).(string); ok {
-		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
-	}
+	// Get token and impersonated identity from context and add to metadata, if present
+	ctx = withOutgoingAuth(ctx)
 
 	// Continue
 	return invoker(ctx, method, req, reply, cc, opts...)
@@ -65,14 +69,8 @@ func AuthUnaryClientInterceptor(ctx context.Context, method string, req, reply i
 func AuthStreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 	ctx := ss.Context()
 
-	// Add token to context, if present
-	if md, ok := metadata.FromIncomingContext(ctx); ok {
-		authorization := md["authorization"]
-		if len(authorization) > 0 {
-			// Add token to context
-			ctx = context.WithValue(ctx, K8STokenCtxKey, authorization[0])
-		}
-	}
+	// Add token and impersonated identity to context, if present
+	ctx = withIncomingAuth(ctx)
 
 	newStream := &wrappedStream{
 		ServerStream: ss,
@@ -85,10 +83,8 @@ func AuthStreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *gr
 
 // Create new auth client stream interceptor
 func AuthStreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
-	// Get token from context and add to metadata, if present
-	if token, ok := ctx.Value(K8STokenCtxKey).(string); ok {
-		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
-	}
+	// Get token and impersonated identity from context and add to metadata, if present
+	ctx = withOutgoingAuth(ctx)
 
 	// Call the original streamer to proceed with the RPC
 	clientStream, err := streamer(ctx, desc, cc, method, opts...)
@@ -98,3 +94,55 @@ func AuthStreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc
 
 	return clientStream, nil
 }
+
+// Add token and impersonated identity from incoming metadata to context
+func withIncomingAuth(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	authorization := md["authorization"]
+	if len(authorization) == 0 {
+		return ctx
+	}
+
+	// Add token to context
+	ctx = context.WithValue(ctx, K8STokenCtxKey, authorization[0])
+
+	// Impersonation is only honored alongside a token
+	if users := md[impersonateUserMDKey]; len(users) > 0 && users[0] != "" {
+		imp := &rest.ImpersonationConfig{
+			UserName: users[0],
+			Groups:   md[impersonateGroupMDKey],
+		}
+		if uids := md[impersonateUIDMDKey]; len(uids) > 0 {
+			imp.UID = uids[0]
+		}
+		ctx = context.WithValue(ctx, ImpersonateCtxKey, imp)
+	}
+
+	return ctx
+}
+
+// Add token and impersonated identity from context to outgoing metadata
+func withOutgoingAuth(ctx context.Context) context.Context {
+	token, ok := ctx.Value(K8STokenCtxKey).(string)
+	if !ok {
+		return ctx
+	}
+
+	kv := []string{"authorization", token}
+
+	if imp, ok := ctx.Value(ImpersonateCtxKey).(*rest.ImpersonationConfig); ok && imp != nil && imp.UserName != "" {
+		kv = append(kv, impersonateUserMDKey, imp.UserName)
+		if imp.UID != "" {
+			kv = append(kv, impersonateUIDMDKey, imp.UID)
+		}
+		for _, group := range imp.Groups {
+			kv = append(kv, impersonateGroupMDKey, group)
+		}
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
@@ -19,7 +19,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"k8s.io/client-go/rest"
 )
 
 func TestAuthUnaryServerInterceptor(t *testing.T) {
@@ -122,3 +125,31 @@ func TestAuthStreamClientInterceptor(t *testing.T) {
 		AuthStreamClientInterceptor(ctxIn, nil, nil, "", mockStreamer)
 	})
 }
+
+func TestAuthImpersonationMetadata(t *testing.T) {
+	t.Run("forwarded with token", func(t *testing.T) {
+		imp := &rest.ImpersonationConfig{UserName: "alice", UID: "42", Groups: []string{"dev", "ops"}}
+
+		ctxIn := context.WithValue(context.Background(), K8STokenCtxKey, "xxx")
+		ctxIn = context.WithValue(ctxIn, ImpersonateCtxKey, imp)
+
+		// Convert outgoing metadata to incoming metadata
+		md, _ := metadata.FromOutgoingContext(withOutgoingAuth(ctxIn))
+		ctx := withIncomingAuth(metadata.NewIncomingContext(context.Background(), md))
+
+		token, _ := ctx.Value(K8STokenCtxKey).(string)
+		assert.Equal(t, "xxx", token)
+
+		got, _ := ctx.Value(ImpersonateCtxKey).(*rest.ImpersonationConfig)
+		require.NotNil(t, got)
+		assert.Equal(t, imp, got)
+	})
+
+	t.Run("ignored without token", func(t *testing.T) {
+		md := metadata.Pairs("impersonate-user", "admin", "impersonate-group", "system:masters")
+		ctx := withIncomingAuth(metadata.NewIncomingContext(context.Background(), md))
+
+		assert.Nil(t, ctx.Value(K8STokenCtxKey))
+		assert.Nil(t, ctx.Value(ImpersonateCtxKey))
+	})
+}
@@ -39,6 +39,7 @@ const (
 
 // cacheKey represents a unique key for caching authorization results
 type cacheKey struct {
+	identity  string
 	namespace string
 	group     string
 	resource  string
@@ -53,12 +54,15 @@ type cacheValue struct {
 
 // Represents DesktopAuthorizer interface
 type DesktopAuthorizer interface {
-	IsAllowedInformer(ctx context.Context, clientset kubernetes.Interface, namespace string, gvr schema.GroupVersionResource) error
+	IsAllowedInformer(ctx context.Context, kubeContext string, clientset kubernetes.Interface, namespace string, gvr schema.GroupVersionResource) error
+	Permissions(ctx context.Context, kubeContext string, clientset kubernetes.Interface, namespace string) (*Permissions, error)
+	Invalidate()
 }
 
 // Represents DesktopAuthorizer
 type DefaultDesktopAuthorizer struct {
 	cache util.SyncMap[cacheKey, cacheValue]
+	rules rulesCache
 }
 
 // Create new DesktopAuthorizer instance
@@ -69,11 +73,27 @@ func NewDesktopAuthorizer() DesktopAuthorizer {
 }
 
 // Check permission for creating new informers
-func (a *DefaultDesktopAuthorizer) IsAllowedInformer(ctx context.Context, clientset kubernetes.Interface, namespace string, gvr schema.GroupVersionResource) error {
+func (a *DefaultDesktopAuthorizer) IsAllowedInformer(ctx context.Context, kubeContext string, clientset kubernetes.Interface, namespace string, gvr schema.GroupVersionResource) error {
+	// Results depend on the cluster and the impersonated identity (SAR is sent
+	// with impersonation headers)
+	identity := desktopIdentity(ctx, kubeContext)
+
+	// Check namespace rules first and fall back to SARs unless they allow
+	// access. Cached rules may be stale so denials are confirmed with SARs,
+	// which only cache allows, to pick up newly granted permissions.
+	if namespace != "" {
+		if permissions, err := a.rules.get(ctx, clientset, identity, namespace); err == nil {
+			if permissions.checkInformer(gvr) == nil {
+				return nil
+			}
+		}
+	}
+
 	// Convenience method for handing errors
 	doSAR := func(verb string) error {
 		// Check cache first
 		key := cacheKey{
+			identity:  identity,
 			namespace: namespace,
 			group:     gvr.Group,
 			resource:  gvr.Resource,
@@ -85,14 +105,7 @@ func (a *DefaultDesktopAuthorizer) IsAllowedInformer(ctx context.Context, client
 			if time.Now().Before(cachedVal.expiration) {
 				// Cache hit and still valid
 				if !cachedVal.allowed {
-					attrs := &authv1.ResourceAttributes{
-						Namespace: namespace,
-						Group:     gvr.Group,
-						Verb:      verb,
-						Resource:  gvr.Resource,
-					}
-					fmt := "permission denied: `%s \"%s\"/\"%s\"` in namespace `%s`"
-					return status.Errorf(codes.Unauthenticated, fmt, attrs.Verb, attrs.Group, attrs.Resource, attrs.Namespace)
+					return newPermissionDeniedError(verb, gvr.Group, gvr.Resource, namespace)
 				}
 				return nil
 			}
@@ -126,9 +139,7 @@ func (a *DefaultDesktopAuthorizer) IsAllowedInformer(ctx context.Context, client
 		}
 
 		if !result.Status.Allowed {
-			attrs := result.Spec.ResourceAttributes
-			fmt := "permission denied: `%s \"%s\"/\"%s\"` in namespace `%s`"
-			return status.Errorf(codes.Unauthenticated, fmt, attrs.Verb, attrs.Group, attrs.Resource, attrs.Namespace)
+			return newPermissionDeniedError(verb, gvr.Group, gvr.Resource, namespace)
 		}
 
 		return nil
@@ -150,15 +161,47 @@ func (a *DefaultDesktopAuthorizer) IsAllowedInformer(ctx context.Context, client
 	return g.Wait()
 }
 
-// Represents InClusterAuthorizer interface
+// Return user's resource rules in namespace
+func (a *DefaultDesktopAuthorizer) Permissions(ctx context.Context, kubeContext string, clientset kubernetes.Interface, namespace string) (*Permissions, error) {
+	return a.rules.get(ctx, clientset, desktopIdentity(ctx, kubeContext), namespace)
+}
+
+// Remove all cached authorization results
+func (a *DefaultDesktopAuthorizer) Invalidate() {
+	a.cache.Range(func(key cacheKey, _ cacheValue) bool {
+		a.cache.Delete(key)
+		return true
+	})
+	a.rules.invalidate()
+}
+
+// Return cache identity for desktop requests
+func desktopIdentity(ctx context.Context, kubeContext string) string {
+	identity := fmt.Sprintf("%s:", kubeContext)
+
+	// Include impersonated identity (SAR is sent with impersonation headers)
+	if imp := ImpersonationFromContext(ctx); imp != nil {
+		identity = fmt.Sprintf("%s%s:%s:%s:", identity, imp.UserName, imp.UID, strings.Join(imp.Groups, ","))
+	}
+
+	return identity
+}
+
+// Represents InClusterAuthorizer interface. Nothing watches RBAC objects in
+// cluster so cached results only expire after `cacheTTL`, which bounds how long
+// granted or revoked permissions take to apply.
 type InClusterAuthorizer interface {
 	IsAllowedInformer(ctx context.Context, restConfig *rest.Config, token string, namespace string, gvr schema.GroupVersionResource) error
+	IsAllowedPodLogs(ctx context.Context, restConfig *rest.Config, token string, namespace string) error
+	Permissions(ctx context.Context, restConfig *rest.Config, token string, namespace string) (*Permissions, error)
+	Invalidate()
 }
 
 // Represents InClusterAuthorizer
 type DefaultInClusterAuthorizer struct {
 	clientsetInitializer clientsetInitializer
 	cache                util.SyncMap[string, cacheValue]
+	rules                rulesCache
 }
 
 // Create new InClusterAuthorizer instance
@@ -171,91 +214,33 @@ func NewInClusterAuthorizer() InClusterAuthorizer {
 
 // Check permission for creating new informers
 func (a *DefaultInClusterAuthorizer) IsAllowedInformer(ctx context.Context, restConfig *rest.Config, token string, namespace string, gvr schema.GroupVersionResource) error {
-	tokenTrimmed := strings.TrimSpace(token)
-
 	// For in-cluster authorizer, include token in cache key
-	cacheKeyPrefix := fmt.Sprintf("%s:", tokenTrimmed)
+	cacheKeyPrefix := inClusterIdentity(ctx, token)
 
-	// Clone rest config and set bearer token
-	rcClone := *restConfig
-	rcClone.BearerToken = tokenTrimmed
-
-	if tokenTrimmed != "" {
-		rcClone.BearerTokenFile = ""
-	}
-
-	// Init clientset
-	// TODO: use kubernetes.NewForConfigAndClient to re-use underlying transport
-	clientset, err := a.clientsetInitializer.newClientset(&rcClone)
+	clientset, err := a.newClientset(restConfig, token)
 	if err != nil {
 		return err
 	}
 
-	// Convenience method for handing errors
-	doSAR := func(verb string) error {
-		// Check cache first
-		key := cacheKey{
-			namespace: namespace,
-			group:     gvr.Group,
-			resource:  gvr.Resource,
-			verb:      verb,
-		}
-
-		// Add token to make the key unique for each token
-		tokenKey := fmt.Sprintf("%s%v", cacheKeyPrefix, key)
-
-		// Check if we have a valid cached result
-		if cachedVal, ok := a.cache.Load(tokenKey); ok {
-			if time.Now().Before(cachedVal.expiration) {
-				// Cache hit and still valid
-				if !cachedVal.allowed {
-					attrs := &authv1.ResourceAttributes{
-						Namespace: namespace,
-						Group:     gvr.Group,
-						Verb:      verb,
-						Resource:  gvr.Resource,
-					}
-					fmt := "permission denied: `%s \"%s\"/\"%s\"` in namespace `%s`"
-					return status.Errorf(codes.Unauthenticated, fmt, attrs.Verb, attrs.Group, attrs.Resource, attrs.Namespace)
-				}
+	// Check namespace rules first and fall back to SARs unless they allow
+	// access. Cached rules may be stale so denials are confirmed with SARs,
+	// which only cache allows, to pick up newly granted permissions.
+	if namespace != "" {
+		if permissions, err := a.rules.get(ctx, clientset, cacheKeyPrefix, namespace); err == nil {
+			if permissions.checkInformer(gvr) == nil {
 				return nil
 			}
-			// Cache expired, remove it
-			a.cache.Delete(tokenKey)
-		}
-
-		// Cache miss or expired, perform the actual check
-		sar := &authv1.SelfSubjectAccessReview{
-			Spec: authv1.SelfSubjectAccessReviewSpec{
-				ResourceAttributes: &authv1.ResourceAttributes{
-					Namespace: namespace,
-					Group:     gvr.Group,
-					Verb:      verb,
-					Resource:  gvr.Resource,
-				},
-			},
-		}
-
-		result, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, sar, metav1.CreateOptions{})
-		if err != nil {
-			return err
-		}
-
-		// Cache the result only if the user is authorized
-		if result.Status.Allowed {
-			a.cache.Store(tokenKey, cacheValue{
-				allowed:    true,
-				expiration: time.Now().Add(cacheTTL),
-			})
-		}
-
-		if !result.Status.Allowed {
-			attrs := result.Spec.ResourceAttributes
-			fmt := "permission denied: `%s \"%s\"/\"%s\"` in namespace `%s`"
-			return status.Errorf(codes.Unauthenticated, fmt, attrs.Verb, attrs.Group, attrs.Resource, attrs.Namespace)
 		}
+	}
 
-		return nil
+	// Convenience method for handing errors
+	doSAR := func(verb string) error {
+		return a.checkAccess(ctx, clientset, cacheKeyPrefix, authv1.ResourceAttributes{
+			Namespace: namespace,
+			Group:     gvr.Group,
+			Verb:      verb,
+			Resource:  gvr.Resource,
+		})
 	}
 
 	// Make individual requests in an error group
@@ -274,6 +259,132 @@ func (a *DefaultInClusterAuthorizer) IsAllowedInformer(ctx context.Context, rest
 	return g.Wait()
 }
 
+// Check permission for reading pod logs. Uses the same check as the cluster
+// agent (`list` on pods/log).
+func (a *DefaultInClusterAuthorizer) IsAllowedPodLogs(ctx context.Context, restConfig *rest.Config, token string, namespace string) error {
+	clientset, err := a.newClientset(restConfig, token)
+	if err != nil {
+		return err
+	}
+
+	return a.checkAccess(ctx, clientset, inClusterIdentity(ctx, token), authv1.ResourceAttributes{
+		Namespace:   namespace,
+		Verb:        "list",
+		Resource:    "pods",
+		Subresource: "log",
+	})
+}
+
+// Check access with a SelfSubjectAccessReview. Only allowed results are
+// cached so newly granted permissions apply immediately.
+func (a *DefaultInClusterAuthorizer) checkAccess(ctx context.Context, clientset kubernetes.Interface, identity string, attrs authv1.ResourceAttributes) error {
+	resource := attrs.Resource
+	if attrs.Subresource != "" {
+		resource = resource + "/" + attrs.Subresource
+	}
+
+	key := cacheKey{
+		namespace: attrs.Namespace,
+		group:     attrs.Group,
+		resource:  resource,
+		verb:      attrs.Verb,
+	}
+
+	// Add identity to make the key unique for each token
+	tokenKey := fmt.Sprintf("%s%v", identity, key)
+
+	// Check if we have a valid cached result
+	if cachedVal, ok := a.cache.Load(tokenKey); ok {
+		if time.Now().Before(cachedVal.expiration) {
+			// Cache hit and still valid
+			if !cachedVal.allowed {
+				return newPermissionDeniedError(attrs.Verb, attrs.Group, resource, attrs.Namespace)
+			}
+			return nil
+		}
+		// Cache expired, remove it
+		a.cache.Delete(tokenKey)
+	}
+
+	// Cache miss or expired, perform the actual check
+	sar := &authv1.SelfSubjectAccessReview{
+		Spec: authv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &attrs,
+		},
+	}
+
+	result, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, sar, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+
+	if !result.Status.Allowed {
+		return newPermissionDeniedError(attrs.Verb, attrs.Group, resource, attrs.Namespace)
+	}
+
+	// Cache the result only if the user is authorized
+	a.cache.Store(tokenKey, cacheValue{
+		allowed:    true,
+		expiration: time.Now().Add(cacheTTL),
+	})
+
+	return nil
+}
+
+// Return user's resource rules in namespace
+func (a *DefaultInClusterAuthorizer) Permissions(ctx context.Context, restConfig *rest.Config, token string, namespace string) (*Permissions, error) {
+	clientset, err := a.newClientset(restConfig, token)
+	if err != nil {
+		return nil, err
+	}
+	return a.rules.get(ctx, clientset, inClusterIdentity(ctx, token), namespace)
+}
+
+// Remove all cached authorization results (not called by the connection
+// manager, see InClusterAuthorizer)
+func (a *DefaultInClusterAuthorizer) Invalidate() {
+	a.cache.Range(func(key string, _ cacheValue) bool {
+		a.cache.Delete(key)
+		return true
+	})
+	a.rules.invalidate()
+}
+
+// Create new clientset that authenticates with token
+func (a *DefaultInClusterAuthorizer) newClientset(restConfig *rest.Config, token string) (kubernetes.Interface, error) {
+	tokenTrimmed := strings.TrimSpace(token)
+
+	// Clone rest config and set bearer token
+	rcClone := *restConfig
+	rcClone.BearerToken = tokenTrimmed
+
+	if tokenTrimmed != "" {
+		rcClone.BearerTokenFile = ""
+	}
+
+	// Init clientset
+	// TODO: use kubernetes.NewForConfigAndClient to re-use underlying transport
+	return a.clientsetInitializer.newClientset(&rcClone)
+}
+
+// Return cache identity for in-cluster requests
+func inClusterIdentity(ctx context.Context, token string) string {
+	identity := fmt.Sprintf("%s:", strings.TrimSpace(token))
+
+	// Include impersonated identity (SAR is sent with impersonation headers)
+	if imp := ImpersonationFromContext(ctx); imp != nil {
+		identity = fmt.Sprintf("%s%s:%s:%s:", identity, imp.UserName, imp.UID, strings.Join(imp.Groups, ","))
+	}
+
+	return identity
+}
+
+// Create new permission denied error
+func newPermissionDeniedError(verb string, group string, resource string, namespace string) error {
+	fmt := "permission denied: `%s \"%s\"/\"%s\"` in namespace `%s`"
+	return status.Errorf(codes.Unauthenticated, fmt, verb, group, resource, namespace)
+}
+
 // Interface to facilitate testing
 type clientsetInitializer interface {
 	newClientset(restConfig *rest.Config) (kubernetes.Interface, error)
@@ -17,12 +17,15 @@ package k8shelpers
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/kubetail-org/kubetail/modules/shared/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	authorizationv1 "k8s.io/api/authorization/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -73,7 +76,7 @@ func TestDesktopAuthorizer_IsAllowedInformer_Success(t *testing.T) {
 
 	// Create authorizer and test
 	authorizer := NewDesktopAuthorizer()
-	err := authorizer.IsAllowedInformer(context.Background(), clientset, setNamespace, setGVR)
+	err := authorizer.IsAllowedInformer(context.Background(), "test-context", clientset, setNamespace, setGVR)
 	assert.NoError(t, err)
 
 	// Verify the SSARs were created
@@ -129,7 +132,7 @@ func TestDesktopAuthorizer_IsAllowedInformer_ListPermissionDenied(t *testing.T)
 
 	// Create authorizer and test
 	authorizer := NewDesktopAuthorizer()
-	err := authorizer.IsAllowedInformer(context.Background(), clientset, setNamespace, setGVR)
+	err := authorizer.IsAllowedInformer(context.Background(), "test-context", clientset, setNamespace, setGVR)
 	assert.Error(t, err)
 }
 
@@ -179,7 +182,7 @@ func TestDesktopAuthorizer_IsAllowedInformer_WatchPermissionDenied(t *testing.T)
 
 	// Create authorizer and test
 	authorizer := NewDesktopAuthorizer()
-	err := authorizer.IsAllowedInformer(context.Background(), clientset, setNamespace, setGVR)
+	err := authorizer.IsAllowedInformer(context.Background(), "test-context", clientset, setNamespace, setGVR)
 	assert.Error(t, err)
 }
 
@@ -214,7 +217,7 @@ func TestDesktopAuthorizer_IsAllowedInformer_Error(t *testing.T) {
 
 	// Create authorizer and test
 	authorizer := NewDesktopAuthorizer()
-	err := authorizer.IsAllowedInformer(context.Background(), clientset, setNamespace, setGVR)
+	err := authorizer.IsAllowedInformer(context.Background(), "test-context", clientset, setNamespace, setGVR)
 	assert.Error(t, err)
 }
 
@@ -254,7 +257,7 @@ func TestDesktopAuthorizer_IsAllowedInformer_CacheExpiry(t *testing.T) {
 	authorizer := &DefaultDesktopAuthorizer{
 		cache: util.SyncMap[cacheKey, cacheValue]{},
 	}
-	err := authorizer.IsAllowedInformer(context.Background(), clientset, setNamespace, setGVR)
+	err := authorizer.IsAllowedInformer(context.Background(), "test-context", clientset, setNamespace, setGVR)
 	assert.NoError(t, err)
 
 	// Check call and cache
@@ -268,7 +271,7 @@ func TestDesktopAuthorizer_IsAllowedInformer_CacheExpiry(t *testing.T) {
 	assert.Equal(t, 2, count)
 
 	// Execute again and ensure cache was used
-	err = authorizer.IsAllowedInformer(context.Background(), clientset, setNamespace, setGVR)
+	err = authorizer.IsAllowedInformer(context.Background(), "test-context", clientset, setNamespace, setGVR)
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(capturedSARs))
 
@@ -279,11 +282,174 @@ func TestDesktopAuthorizer_IsAllowedInformer_CacheExpiry(t *testing.T) {
 		return true
 	})
 
-	err = authorizer.IsAllowedInformer(context.Background(), clientset, setNamespace, setGVR)
+	err = authorizer.IsAllowedInformer(context.Background(), "test-context", clientset, setNamespace, setGVR)
 	assert.NoError(t, err)
 	assert.Equal(t, 4, len(capturedSARs))
 }
 
+func TestDesktopAuthorizer_IsAllowedInformer_CachePerIdentity(t *testing.T) {
+	setNamespace := "test-namespace"
+	setGVR := schema.GroupVersionResource{
+		Group:    "apps",
+		Version:  "v1",
+		Resource: "deployments",
+	}
+
+	// Prepare fake clientset that only allows alice
+	var capturedSARs atomic.Int32
+
+	clientset := fake.NewSimpleClientset()
+	clientset.Fake.PrependReactor("create", "selfsubjectaccessreviews", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+		n := capturedSARs.Add(1)
+		obj := action.(ktesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		return true, &authorizationv1.SelfSubjectAccessReview{
+			Spec: obj.Spec,
+			Status: authorizationv1.SubjectAccessReviewStatus{
+				Allowed: n <= 2,
+			},
+		}, nil
+	})
+
+	authorizer := NewDesktopAuthorizer()
+
+	aliceCtx := context.WithValue(context.Background(), ImpersonateCtxKey, &rest.ImpersonationConfig{UserName: "alice"})
+	bobCtx := context.WithValue(context.Background(), ImpersonateCtxKey, &rest.ImpersonationConfig{UserName: "bob"})
+
+	// Alice is allowed and result is cached
+	assert.NoError(t, authorizer.IsAllowedInformer(aliceCtx, "test-context", clientset, setNamespace, setGVR))
+	assert.NoError(t, authorizer.IsAllowedInformer(aliceCtx, "test-context", clientset, setNamespace, setGVR))
+	assert.Equal(t, int32(2), capturedSARs.Load())
+
+	// Bob doesn't re-use alice's cached result
+	err := authorizer.IsAllowedInformer(bobCtx, "test-context", clientset, setNamespace, setGVR)
+	assert.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, int32(4), capturedSARs.Load())
+}
+
+func TestDesktopAuthorizer_Permissions_CachePerKubeContext(t *testing.T) {
+	// Each kube context has its own cluster and credentials
+	var prodSSRRs, devSSRRs atomic.Int32
+	prodClientset := newRulesReviewClientset(nil, &prodSSRRs)
+	devClientset := newRulesReviewClientset([]authorizationv1.ResourceRule{
+		{Verbs: []string{"list", "watch"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+	}, &devSSRRs)
+
+	authorizer := NewDesktopAuthorizer()
+
+	p, err := authorizer.Permissions(context.Background(), "dev", devClientset, "test-namespace")
+	assert.NoError(t, err)
+	assert.Len(t, p.Rules, 1)
+
+	// Prod doesn't re-use dev's cached rules
+	p, err = authorizer.Permissions(context.Background(), "prod", prodClientset, "test-namespace")
+	assert.NoError(t, err)
+	assert.Empty(t, p.Rules)
+	assert.Equal(t, int32(1), devSSRRs.Load())
+	assert.Equal(t, int32(1), prodSSRRs.Load())
+}
+
+func TestDesktopAuthorizer_IsAllowedInformer_RulesAllowed(t *testing.T) {
+	setGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+	// Rules allow list and watch so no SARs should be sent
+	var capturedSSRRs, capturedSARs atomic.Int32
+	clientset := newRulesReviewClientset([]authorizationv1.ResourceRule{
+		{Verbs: []string{"list", "watch"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}},
+	}, &capturedSSRRs)
+	clientset.Fake.PrependReactor("create", "selfsubjectaccessreviews", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+		capturedSARs.Add(1)
+		return true, &authorizationv1.SelfSubjectAccessReview{}, nil
+	})
+
+	authorizer := NewDesktopAuthorizer()
+
+	// One rules review covers multiple resources
+	assert.NoError(t, authorizer.IsAllowedInformer(context.Background(), "test-context", clientset, "test-namespace", setGVR))
+	assert.NoError(t, authorizer.IsAllowedInformer(context.Background(), "test-context", clientset, "test-namespace", setGVR))
+	assert.Equal(t, int32(1), capturedSSRRs.Load())
+	assert.Equal(t, int32(0), capturedSARs.Load())
+}
+
+func TestDesktopAuthorizer_IsAllowedInformer_RulesDenied(t *testing.T) {
+	setGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+	// Complete rules without watch are a definitive denial
+	var capturedSSRRs, capturedSARs atomic.Int32
+	clientset := newRulesReviewClientset([]authorizationv1.ResourceRule{
+		{Verbs: []string{"list"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}},
+	}, &capturedSSRRs)
+	clientset.Fake.PrependReactor("create", "selfsubjectaccessreviews", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+		capturedSARs.Add(1)
+		return true, &authorizationv1.SelfSubjectAccessReview{}, nil
+	})
+
+	authorizer := NewDesktopAuthorizer()
+
+	// Denials are confirmed with SARs every time
+	for range 2 {
+		err := authorizer.IsAllowedInformer(context.Background(), "test-context", clientset, "test-namespace", setGVR)
+		assert.Error(t, err)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+	assert.Equal(t, int32(1), capturedSSRRs.Load())
+	assert.Equal(t, int32(4), capturedSARs.Load())
+
+	// Newly granted permissions apply without waiting for the rules to expire
+	clientset.Fake.PrependReactor("create", "selfsubjectaccessreviews", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, &authorizationv1.SelfSubjectAccessReview{
+			Status: authorizationv1.SubjectAccessReviewStatus{Allowed: true},
+		}, nil
+	})
+	assert.NoError(t, authorizer.IsAllowedInformer(context.Background(), "test-context", clientset, "test-namespace", setGVR))
+	assert.Equal(t, int32(1), capturedSSRRs.Load())
+}
+
+func TestDesktopAuthorizer_IsAllowedInformer_RulesIncomplete(t *testing.T) {
+	setGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+	// Incomplete rules fall back to SARs
+	var capturedSARs atomic.Int32
+	clientset := fake.NewSimpleClientset()
+	clientset.Fake.PrependReactor("create", "selfsubjectrulesreviews", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, &authorizationv1.SelfSubjectRulesReview{
+			Status: authorizationv1.SubjectRulesReviewStatus{
+				ResourceRules: []authorizationv1.ResourceRule{
+					{Verbs: []string{"create"}, APIGroups: []string{"authorization.k8s.io"}, Resources: []string{"selfsubjectaccessreviews"}},
+				},
+				Incomplete: true,
+			},
+		}, nil
+	})
+	clientset.Fake.PrependReactor("create", "selfsubjectaccessreviews", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+		capturedSARs.Add(1)
+		return true, &authorizationv1.SelfSubjectAccessReview{
+			Status: authorizationv1.SubjectAccessReviewStatus{Allowed: true},
+		}, nil
+	})
+
+	authorizer := NewDesktopAuthorizer()
+	assert.NoError(t, authorizer.IsAllowedInformer(context.Background(), "test-context", clientset, "test-namespace", setGVR))
+	assert.Equal(t, int32(2), capturedSARs.Load())
+}
+
+func TestDesktopAuthorizer_Permissions(t *testing.T) {
+	rules := []authorizationv1.ResourceRule{
+		{Verbs: []string{"get", "list", "watch"}, APIGroups: []string{""}, Resources: []string{"pods", "pods/log"}},
+	}
+
+	var capturedSSRRs atomic.Int32
+	clientset := newRulesReviewClientset(rules, &capturedSSRRs)
+
+	authorizer := NewDesktopAuthorizer()
+	p, err := authorizer.Permissions(context.Background(), "test-context", clientset, "test-namespace")
+	assert.NoError(t, err)
+	assert.Equal(t, "test-namespace", p.Namespace)
+	assert.Equal(t, rules, p.Rules)
+	assert.False(t, p.Incomplete)
+	assert.True(t, p.Allows("", "pods/log", "get"))
+}
+
 type MockClientsetInitializer struct {
 	mock.Mock
 }
@@ -712,3 +878,111 @@ func TestInClusterAuthorizer_IsAllowedInformer_Success(t *testing.T) {
 	// Verify the number of calls to the mock
 	mockClientsetInitializer.AssertNumberOfCalls(t, "newClientset", 1)
 }
+
+func TestInClusterAuthorizer_IsAllowedInformer_RulesAllowed(t *testing.T) {
+	setGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+	var capturedSSRRs, capturedSARs atomic.Int32
+	clientset := newRulesReviewClientset([]authorizationv1.ResourceRule{
+		{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+	}, &capturedSSRRs)
+	clientset.Fake.PrependReactor("create", "selfsubjectaccessreviews", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+		capturedSARs.Add(1)
+		return true, &authorizationv1.SelfSubjectAccessReview{}, nil
+	})
+
+	// Create mock clientsetInitializer
+	mockClientsetInitializer := new(MockClientsetInitializer)
+	mockClientsetInitializer.On("newClientset", mock.Anything).Return(clientset, nil)
+
+	authorizer := &DefaultInClusterAuthorizer{
+		clientsetInitializer: mockClientsetInitializer,
+		cache:                util.SyncMap[string, cacheValue]{},
+	}
+
+	// Rules are cached per token
+	assert.NoError(t, authorizer.IsAllowedInformer(context.Background(), &rest.Config{}, "token-1", "test-namespace", setGVR))
+	assert.NoError(t, authorizer.IsAllowedInformer(context.Background(), &rest.Config{}, "token-1", "test-namespace", setGVR))
+	assert.Equal(t, int32(1), capturedSSRRs.Load())
+
+	assert.NoError(t, authorizer.IsAllowedInformer(context.Background(), &rest.Config{}, "token-2", "test-namespace", setGVR))
+	assert.Equal(t, int32(2), capturedSSRRs.Load())
+	assert.Equal(t, int32(0), capturedSARs.Load())
+}
+
+func TestInClusterAuthorizer_Permissions(t *testing.T) {
+	rules := []authorizationv1.ResourceRule{
+		{Verbs: []string{"list"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+	}
+
+	var capturedSSRRs atomic.Int32
+	clientset := newRulesReviewClientset(rules, &capturedSSRRs)
+
+	// Create a mock clientsetInitializer that captures the restConfig
+	mockClientsetInitializer := new(MockClientsetInitializer)
+	mockClientsetInitializer.On("newClientset", mock.MatchedBy(func(rc *rest.Config) bool {
+		return rc.BearerToken == "test-token" && rc.BearerTokenFile == ""
+	})).Return(clientset, nil)
+
+	authorizer := &DefaultInClusterAuthorizer{
+		clientsetInitializer: mockClientsetInitializer,
+	}
+
+	p, err := authorizer.Permissions(context.Background(), &rest.Config{BearerTokenFile: "/path/to/token"}, " test-token ", "test-namespace")
+	assert.NoError(t, err)
+	assert.Equal(t, rules, p.Rules)
+	assert.True(t, p.Allows("", "pods", "list"))
+	assert.False(t, p.Allows("", "pods", "watch"))
+	mockClientsetInitializer.AssertExpectations(t)
+}
+
+func TestInClusterAuthorizer_IsAllowedPodLogs(t *testing.T) {
+	var numCalls atomic.Int32
+
+	// Allow "allowed-token" only
+	clientset := fake.NewSimpleClientset()
+	clientset.Fake.PrependReactor("create", "selfsubjectaccessreviews", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+		numCalls.Add(1)
+		obj := action.(ktesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+
+		ra := obj.Spec.ResourceAttributes
+		assert.Equal(t, "list", ra.Verb)
+		assert.Equal(t, "pods", ra.Resource)
+		assert.Equal(t, "log", ra.Subresource)
+		assert.Equal(t, "test-namespace", ra.Namespace)
+
+		return true, &authorizationv1.SelfSubjectAccessReview{}, nil
+	})
+
+	mockClientsetInitializer := new(MockClientsetInitializer)
+	mockClientsetInitializer.On("newClientset", mock.Anything).Return(clientset, nil)
+
+	authorizer := &DefaultInClusterAuthorizer{
+		clientsetInitializer: mockClientsetInitializer,
+		cache:                util.SyncMap[string, cacheValue]{},
+	}
+
+	restConfig := &rest.Config{Host: "https://example.com"}
+
+	// Denials are returned and not cached
+	for i := 0; i < 2; i++ {
+		err := authorizer.IsAllowedPodLogs(context.Background(), restConfig, "denied-token", "test-namespace")
+		assert.Error(t, err)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+	assert.Equal(t, int32(2), numCalls.Load())
+
+	// Allows are cached
+	clientset.Fake.PrependReactor("create", "selfsubjectaccessreviews", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+		numCalls.Add(1)
+		return true, &authorizationv1.SelfSubjectAccessReview{
+			Status: authorizationv1.SubjectAccessReviewStatus{Allowed: true},
+		}, nil
+	})
+
+	for i := 0; i < 2; i++ {
+		err := authorizer.IsAllowedPodLogs(context.Background(), restConfig, "allowed-token", "test-namespace")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(3), numCalls.Load())
+}
@@ -17,7 +17,6 @@ package k8shelpers
 import (
 	"context"
 	"fmt"
-	"net/http"
 	"sync"
 	"time"
 
@@ -42,6 +41,13 @@ type factoryCacheKey struct {
 	namespace   string
 }
 
+// Represents informer cache key
+type informerCacheKey struct {
+	kubeContext string
+	namespace   string
+	gvr         schema.GroupVersionResource
+}
+
 // Signature for permission checker function
 type CheckPermissionsFunc func(clientset *kubernetes.Clientset) error
 
@@ -53,6 +59,7 @@ type ConnectionManager interface {
 	GetDefaultNamespace(kubeContext string) string
 	DerefKubeContext(kubeContext *string) string
 	NewInformer(ctx context.Context, kubeContext string, token string, namespace string, gvr schema.GroupVersionResource) (informers.GenericInformer, func(), error)
+	GetPermissions(ctx context.Context, kubeContext string, token string, namespace string) (*Permissions, error)
 	WaitUntilReady(ctx context.Context, kubeContext string) error
 	Shutdown(ctx context.Context) error
 }
@@ -63,11 +70,13 @@ type DesktopConnectionManager struct {
 	kubeconfigPath    string
 	kubeConfig        *api.Config
 	isLazy            bool
+	impersonate       rest.ImpersonationConfig
 	authorizer        DesktopAuthorizer
 	rcCache           util.SyncGroup[string, *rest.Config]
 	csCache           util.SyncGroup[string, *kubernetes.Clientset]
 	dcCache           util.SyncGroup[string, *dynamic.DynamicClient]
 	factoryCache      util.SyncGroup[factoryCacheKey, informers.SharedInformerFactory]
+	informerCache     util.SyncMap[informerCacheKey, informers.GenericInformer]
 	isReadyCache      util.SyncGroup[string, bool]
 	rootCtx           context.Context
 	rootCtxCancel     context.CancelFunc
@@ -161,10 +170,11 @@ func (cm *DesktopConnectionManager) GetOrCreateRestConfig(kubeContext string) (*
 			return nil, err
 		}
 
-		// Add authentication handler
-		rc.WrapTransport = func(transport http.RoundTripper) http.RoundTripper {
-			return NewBearerTokenRoundTripper(transport)
-		}
+		// Add authentication and tracing handlers
+		rc.WrapTransport = wrapTransport
+
+		// Act as the configured identity (request-scoped identities take precedence)
+		rc.Impersonate = cm.impersonate
 
 		return rc, nil
 	})
@@ -189,7 +199,7 @@ func (cm *DesktopConnectionManager) NewInformer(ctx context.Context, kubeContext
 	}
 
 	// Check permission
-	if err := cm.authorizer.IsAllowedInformer(ctx, clientset, namespace, gvr); err != nil {
+	if err := cm.authorizer.IsAllowedInformer(ctx, kubeContext, clientset, namespace, gvr); err != nil {
 		return nil, nil, err
 	}
 
@@ -204,6 +214,7 @@ func (cm *DesktopConnectionManager) NewInformer(ctx context.Context, kubeContext
 	if err != nil {
 		return nil, nil, err
 	}
+	cm.informerCache.Store(informerCacheKey{kubeContext, namespace, gvr}, informer)
 
 	// Create start function
 	startFn := func() {
@@ -213,6 +224,30 @@ func (cm *DesktopConnectionManager) NewInformer(ctx context.Context, kubeContext
 	return informer, startFn, nil
 }
 
+// Get user's resource rules in namespace
+func (cm *DesktopConnectionManager) GetPermissions(ctx context.Context, kubeContext string, token string, namespace string) (*Permissions, error) {
+	// Get clientset
+	clientset, err := cm.GetOrCreateClientset(kubeContext)
+	if err != nil {
+		return nil, err
+	}
+
+	return cm.authorizer.Permissions(ctx, kubeContext, clientset, namespace)
+}
+
+// Call `fn` for each running informer created by the connection manager and
+// prune the ones that have stopped
+func (cm *DesktopConnectionManager) rangeInformers(fn func(key informerCacheKey, informer informers.GenericInformer)) {
+	cm.informerCache.Range(func(k informerCacheKey, informer informers.GenericInformer) bool {
+		if informer.Informer().IsStopped() {
+			cm.informerCache.Delete(k)
+		} else {
+			fn(k, informer)
+		}
+		return true
+	})
+}
+
 // GetDefaultNamespace
 func (cm *DesktopConnectionManager) GetDefaultNamespace(kubeContext string) string {
 	cm.mu.Lock()
@@ -355,6 +390,19 @@ func (cm *DesktopConnectionManager) kubeConfigModified(newConfig *api.Config) {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 	cm.kubeConfig = newConfig
+
+	// Stop tracking informers for contexts that were removed
+	cm.informerCache.Range(func(k informerCacheKey, _ informers.GenericInformer) bool {
+		if _, exists := newConfig.Contexts[k.kubeContext]; !exists {
+			cm.informerCache.Delete(k)
+		}
+		return true
+	})
+
+	// Credentials may have changed so cached permissions are stale
+	if cm.authorizer != nil {
+		cm.authorizer.Invalidate()
+	}
 }
 
 // Represents InClusterConnectionManager
@@ -362,8 +410,10 @@ type InClusterConnectionManager struct {
 	restConfig    *rest.Config
 	clientset     *kubernetes.Clientset
 	dynamicClient *dynamic.DynamicClient
+	impersonate   rest.ImpersonationConfig
 	authorizer    InClusterAuthorizer
 	factoryCache  map[string]informers.SharedInformerFactory
+	informerCache map[informerCacheKey]informers.GenericInformer
 	stopCh        chan struct{}
 	mu            sync.Mutex
 }
@@ -371,9 +421,10 @@ type InClusterConnectionManager struct {
 // Initialize new InClusterConnectionManager instance
 func NewInClusterConnectionManager(options ...ConnectionManagerOption) (*InClusterConnectionManager, error) {
 	cm := &InClusterConnectionManager{
-		authorizer:   NewInClusterAuthorizer(),
-		factoryCache: make(map[string]informers.SharedInformerFactory),
-		stopCh:       make(chan struct{}),
+		authorizer:    NewInClusterAuthorizer(),
+		factoryCache:  make(map[string]informers.SharedInformerFactory),
+		informerCache: make(map[informerCacheKey]informers.GenericInformer),
+		stopCh:        make(chan struct{}),
 	}
 
 	// Apply options
@@ -502,6 +553,7 @@ func (cm *InClusterConnectionManager) NewInformer(ctx context.Context, kubeConte
 	if err != nil {
 		return nil, nil, err
 	}
+	cm.informerCache[informerCacheKey{kubeContext, namespace, gvr}] = informer
 
 	// Create start function
 	startFn := func() {
@@ -511,6 +563,35 @@ func (cm *InClusterConnectionManager) NewInformer(ctx context.Context, kubeConte
 	return informer, startFn, nil
 }
 
+// Get user's resource rules in namespace
+func (cm *InClusterConnectionManager) GetPermissions(ctx context.Context, kubeContext string, token string, namespace string) (*Permissions, error) {
+	if kubeContext != "" {
+		return nil, fmt.Errorf("kubeContext is not supported")
+	}
+
+	// Get rest config
+	restConfig, err := cm.GetOrCreateRestConfig(kubeContext)
+	if err != nil {
+		return nil, err
+	}
+
+	return cm.authorizer.Permissions(ctx, restConfig, token, namespace)
+}
+
+// Call `fn` for each running informer created by the connection manager and
+// prune the ones that have stopped
+func (cm *InClusterConnectionManager) rangeInformers(fn func(key informerCacheKey, informer informers.GenericInformer)) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	for k, informer := range cm.informerCache {
+		if informer.Informer().IsStopped() {
+			delete(cm.informerCache, k)
+			continue
+		}
+		fn(k, informer)
+	}
+}
+
 // Get default namespace from local filesystem on pod
 func (cm *InClusterConnectionManager) GetDefaultNamespace(kubeContext string) string {
 	return metav1.NamespaceDefault
@@ -543,10 +624,11 @@ func (cm *InClusterConnectionManager) getOrCreateRestConfig_UNSAFE() (*rest.Conf
 	rc.QPS = 10.0
 	rc.Burst = 40
 
-	// Add authentication middleware
-	rc.WrapTransport = func(transport http.RoundTripper) http.RoundTripper {
-		return NewBearerTokenRoundTripper(transport)
-	}
+	// Add authentication and tracing middleware
+	rc.WrapTransport = wrapTransport
+
+	// Act as the configured identity (request-scoped identities take precedence)
+	rc.Impersonate = cm.impersonate
 
 	// Add to cache
 	cm.restConfig = rc
@@ -647,3 +729,16 @@ func WithLazyConnect(isLazy bool) ConnectionManagerOption {
 		}
 	}
 }
+
+// WithImpersonation makes all Kubernetes requests on behalf of the given
+// identity. Identities in the request context take precedence.
+func WithImpersonation(imp rest.ImpersonationConfig) ConnectionManagerOption {
+	return func(cm ConnectionManager) {
+		switch t := cm.(type) {
+		case *DesktopConnectionManager:
+			t.impersonate = imp
+		case *InClusterConnectionManager:
+			t.impersonate = imp
+		}
+	}
+}
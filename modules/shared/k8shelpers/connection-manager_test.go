@@ -25,6 +25,7 @@ import (
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
 )
 
 // MockDesktopAuthorizer is a mock implementation of DesktopAuthorizer for testing
@@ -33,11 +34,25 @@ type MockDesktopAuthorizer struct {
 }
 
 // IsAllowedInformer is a mock implementation of the DesktopAuthorizer.IsAllowedInformer method
-func (m *MockDesktopAuthorizer) IsAllowedInformer(ctx context.Context, clientset kubernetes.Interface, namespace string, gvr schema.GroupVersionResource) error {
-	args := m.Called(ctx, clientset, namespace, gvr)
+func (m *MockDesktopAuthorizer) IsAllowedInformer(ctx context.Context, kubeContext string, clientset kubernetes.Interface, namespace string, gvr schema.GroupVersionResource) error {
+	args := m.Called(ctx, kubeContext, clientset, namespace, gvr)
 	return args.Error(0)
 }
 
+// Permissions is a mock implementation of the DesktopAuthorizer.Permissions method
+func (m *MockDesktopAuthorizer) Permissions(ctx context.Context, kubeContext string, clientset kubernetes.Interface, namespace string) (*Permissions, error) {
+	args := m.Called(ctx, kubeContext, clientset, namespace)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Permissions), args.Error(1)
+}
+
+// Invalidate is a mock implementation of the DesktopAuthorizer.Invalidate method
+func (m *MockDesktopAuthorizer) Invalidate() {
+	m.Called()
+}
+
 func TestDesktopConnectionManager_NewInformer_AuthorizationFailure(t *testing.T) {
 	// Set up the expected error
 	expectedError := errors.New("authorization failed")
@@ -46,6 +61,7 @@ func TestDesktopConnectionManager_NewInformer_AuthorizationFailure(t *testing.T)
 	mockAuthorizer := new(MockDesktopAuthorizer)
 	mockAuthorizer.On("IsAllowedInformer",
 		mock.Anything,    // context
+		"test-context",   // kubeContext
 		mock.Anything,    // clientset
 		"test-namespace", // namespace
 		mock.MatchedBy(func(gvr schema.GroupVersionResource) bool {
@@ -95,6 +111,26 @@ func (m *MockInClusterAuthorizer) IsAllowedInformer(ctx context.Context, restCon
 	return args.Error(0)
 }
 
+// IsAllowedPodLogs is a mock implementation of the InClusterAuthorizer.IsAllowedPodLogs method
+func (m *MockInClusterAuthorizer) IsAllowedPodLogs(ctx context.Context, restConfig *rest.Config, token string, namespace string) error {
+	args := m.Called(ctx, restConfig, token, namespace)
+	return args.Error(0)
+}
+
+// Permissions is a mock implementation of the InClusterAuthorizer.Permissions method
+func (m *MockInClusterAuthorizer) Permissions(ctx context.Context, restConfig *rest.Config, token string, namespace string) (*Permissions, error) {
+	args := m.Called(ctx, restConfig, token, namespace)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Permissions), args.Error(1)
+}
+
+// Invalidate is a mock implementation of the InClusterAuthorizer.Invalidate method
+func (m *MockInClusterAuthorizer) Invalidate() {
+	m.Called()
+}
+
 func TestInClusterConnectionManager_NewInformer_AuthorizationFailure(t *testing.T) {
 	// Set up the expected error
 	expectedError := errors.New("authorization failed")
@@ -176,3 +212,51 @@ func TestInClusterConnectionManager_NewInformer_KubeContextNotSupported(t *testi
 	// The mock should not have been called since the error happens before authorization check
 	mockAuthorizer.AssertNotCalled(t, "IsAllowedInformer")
 }
+
+func TestDesktopConnectionManager_WithImpersonation(t *testing.T) {
+	kubeConfig := api.NewConfig()
+	kubeConfig.Clusters["test-cluster"] = &api.Cluster{Server: "https://127.0.0.1:6443"}
+	kubeConfig.AuthInfos["test-user"] = &api.AuthInfo{Token: "test-token"}
+	kubeConfig.Contexts["test-context"] = &api.Context{Cluster: "test-cluster", AuthInfo: "test-user"}
+
+	imp := rest.ImpersonationConfig{UserName: "alice", Groups: []string{"dev"}}
+
+	cm := &DesktopConnectionManager{kubeConfig: kubeConfig}
+	WithImpersonation(imp)(cm)
+
+	rc, err := cm.GetOrCreateRestConfig("test-context")
+	assert.NoError(t, err)
+	assert.Equal(t, imp, rc.Impersonate)
+	assert.NotNil(t, rc.WrapTransport)
+}
+
+func TestDesktopConnectionManager_KubeConfigModified_InvalidatesAuthorizer(t *testing.T) {
+	mockAuthorizer := new(MockDesktopAuthorizer)
+	mockAuthorizer.On("Invalidate").Return()
+
+	cm := &DesktopConnectionManager{authorizer: mockAuthorizer}
+	cm.kubeConfigModified(api.NewConfig())
+
+	mockAuthorizer.AssertCalled(t, "Invalidate")
+}
+
+func TestInClusterConnectionManager_GetPermissions(t *testing.T) {
+	expected := &Permissions{Namespace: "test-namespace"}
+
+	mockAuthorizer := new(MockInClusterAuthorizer)
+	mockAuthorizer.On("Permissions", mock.Anything, mock.Anything, "test-token", "test-namespace").Return(expected, nil)
+
+	cm := &InClusterConnectionManager{
+		restConfig: &rest.Config{},
+		authorizer: mockAuthorizer,
+	}
+
+	// Non-empty kubeContext isn't supported
+	_, err := cm.GetPermissions(context.Background(), "some-context", "test-token", "test-namespace")
+	assert.Error(t, err)
+	mockAuthorizer.AssertNotCalled(t, "Permissions")
+
+	p, err := cm.GetPermissions(context.Background(), "", "test-token", "test-namespace")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, p)
+}
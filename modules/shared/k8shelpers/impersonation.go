@@ -0,0 +1,128 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8shelpers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+)
+
+// Headers used to forward an impersonated identity to the Cluster API. They
+// are only honored alongside a bearer token, which must be allowed to
+// impersonate the identity.
+const (
+	ForwardedImpersonateUserHeader  = "X-Forwarded-Impersonate-User"
+	ForwardedImpersonateUIDHeader   = "X-Forwarded-Impersonate-Uid"
+	ForwardedImpersonateGroupHeader = "X-Forwarded-Impersonate-Group"
+)
+
+// Return impersonated identity from request context, if present
+func ImpersonationFromContext(ctx context.Context) *rest.ImpersonationConfig {
+	imp, ok := ctx.Value(ImpersonateCtxKey).(*rest.ImpersonationConfig)
+	if !ok || imp == nil || imp.UserName == "" {
+		return nil
+	}
+	return imp
+}
+
+// Return identity that requests made with the given context act as.
+// Request-scoped identities take precedence and the static identity doesn't
+// apply to requests with a request-scoped bearer token.
+func EffectiveImpersonation(ctx context.Context, static rest.ImpersonationConfig) *rest.ImpersonationConfig {
+	if imp := ImpersonationFromContext(ctx); imp != nil {
+		return imp
+	}
+
+	if token, _ := ctx.Value(K8STokenCtxKey).(string); token != "" || static.UserName == "" {
+		return nil
+	}
+
+	return &static
+}
+
+// Return static identity from dashboard config
+func ImpersonationFromConfig(cfg *config.Config) rest.ImpersonationConfig {
+	imp := cfg.Dashboard.Impersonate
+	return rest.ImpersonationConfig{
+		UserName: imp.User,
+		UID:      imp.UID,
+		Groups:   imp.Groups,
+	}
+}
+
+// Replace forwarded impersonation headers with the given identity. Headers
+// supplied by the client are always removed so they can't be spoofed.
+func SetForwardedImpersonationHeaders(h http.Header, imp *rest.ImpersonationConfig) {
+	h.Del(ForwardedImpersonateUserHeader)
+	h.Del(ForwardedImpersonateUIDHeader)
+	h.Del(ForwardedImpersonateGroupHeader)
+
+	if imp == nil || imp.UserName == "" {
+		return
+	}
+
+	h.Set(ForwardedImpersonateUserHeader, imp.UserName)
+	if imp.UID != "" {
+		h.Set(ForwardedImpersonateUIDHeader, imp.UID)
+	}
+	for _, group := range imp.Groups {
+		h.Add(ForwardedImpersonateGroupHeader, group)
+	}
+}
+
+// Parse forwarded impersonation headers, returns nil if absent
+func ParseForwardedImpersonationHeaders(h http.Header) *rest.ImpersonationConfig {
+	userName := strings.TrimSpace(h.Get(ForwardedImpersonateUserHeader))
+	if userName == "" {
+		return nil
+	}
+
+	return &rest.ImpersonationConfig{
+		UserName: userName,
+		UID:      strings.TrimSpace(h.Get(ForwardedImpersonateUIDHeader)),
+		Groups:   h.Values(ForwardedImpersonateGroupHeader),
+	}
+}
+
+// Remove Kubernetes impersonation headers
+func clearImpersonationHeaders(h http.Header) {
+	for k := range h {
+		if strings.HasPrefix(k, transport.ImpersonateUserExtraHeaderPrefix) {
+			h.Del(k)
+		}
+	}
+	h.Del(transport.ImpersonateUserHeader)
+	h.Del(transport.ImpersonateUIDHeader)
+	h.Del(transport.ImpersonateGroupHeader)
+}
+
+// Replace Kubernetes impersonation headers with the given identity
+func setImpersonationHeaders(h http.Header, imp *rest.ImpersonationConfig) {
+	clearImpersonationHeaders(h)
+
+	h.Set(transport.ImpersonateUserHeader, imp.UserName)
+	if imp.UID != "" {
+		h.Set(transport.ImpersonateUIDHeader, imp.UID)
+	}
+	for _, group := range imp.Groups {
+		h.Add(transport.ImpersonateGroupHeader, group)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8shelpers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func TestImpersonationFromContext(t *testing.T) {
+	assert.Nil(t, ImpersonationFromContext(context.Background()))
+
+	ctx := context.WithValue(context.Background(), ImpersonateCtxKey, &rest.ImpersonationConfig{})
+	assert.Nil(t, ImpersonationFromContext(ctx))
+
+	imp := &rest.ImpersonationConfig{UserName: "alice"}
+	ctx = context.WithValue(context.Background(), ImpersonateCtxKey, imp)
+	assert.Equal(t, imp, ImpersonationFromContext(ctx))
+}
+
+func TestEffectiveImpersonation(t *testing.T) {
+	static := rest.ImpersonationConfig{UserName: "bob", Groups: []string{"ops"}}
+
+	// Static identity
+	imp := EffectiveImpersonation(context.Background(), static)
+	require.NotNil(t, imp)
+	assert.Equal(t, "bob", imp.UserName)
+
+	// No static identity
+	assert.Nil(t, EffectiveImpersonation(context.Background(), rest.ImpersonationConfig{}))
+
+	// Request-scoped identity takes precedence
+	ctx := context.WithValue(context.Background(), ImpersonateCtxKey, &rest.ImpersonationConfig{UserName: "alice"})
+	imp = EffectiveImpersonation(ctx, static)
+	require.NotNil(t, imp)
+	assert.Equal(t, "alice", imp.UserName)
+
+	// Request-scoped token disables static identity
+	ctx = context.WithValue(context.Background(), K8STokenCtxKey, "xxx")
+	assert.Nil(t, EffectiveImpersonation(ctx, static))
+}
+
+func TestForwardedImpersonationHeaders(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		h := http.Header{}
+		SetForwardedImpersonationHeaders(h, &rest.ImpersonationConfig{UserName: "alice", UID: "42", Groups: []string{"dev", "ops"}})
+
+		imp := ParseForwardedImpersonationHeaders(h)
+		require.NotNil(t, imp)
+		assert.Equal(t, "alice", imp.UserName)
+		assert.Equal(t, "42", imp.UID)
+		assert.Equal(t, []string{"dev", "ops"}, imp.Groups)
+	})
+
+	t.Run("client supplied headers are removed", func(t *testing.T) {
+		h := http.Header{}
+		h.Set(ForwardedImpersonateUserHeader, "admin")
+		h.Add(ForwardedImpersonateGroupHeader, "system:masters")
+
+		SetForwardedImpersonationHeaders(h, &rest.ImpersonationConfig{UserName: "alice"})
+		assert.Equal(t, "alice", h.Get(ForwardedImpersonateUserHeader))
+		assert.Empty(t, h.Values(ForwardedImpersonateGroupHeader))
+
+		SetForwardedImpersonationHeaders(h, nil)
+		assert.Nil(t, ParseForwardedImpersonationHeaders(h))
+	})
+}
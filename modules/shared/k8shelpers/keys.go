@@ -16,4 +16,7 @@ package k8shelpers
 
 type Key int
 
-const K8STokenCtxKey Key = iota
+const (
+	K8STokenCtxKey Key = iota
+	ImpersonateCtxKey
+)
@@ -0,0 +1,68 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8shelpers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/informers"
+)
+
+var informerCacheObjectsDesc = prometheus.NewDesc(
+	"kubetail_connection_manager_informer_cache_objects",
+	"Number of objects held in the connection manager's informer caches.",
+	[]string{"kube_context", "namespace", "resource"},
+	nil,
+)
+
+// Implemented by connection managers that track their informers
+type informerRanger interface {
+	rangeInformers(fn func(key informerCacheKey, informer informers.GenericInformer))
+}
+
+// Represents prometheus collector for informer cache sizes
+type informerCacheCollector struct {
+	cm ConnectionManager
+}
+
+// NewInformerCacheCollector returns a prometheus collector that reports the
+// number of objects in each informer cache created by `cm`
+func NewInformerCacheCollector(cm ConnectionManager) prometheus.Collector {
+	return &informerCacheCollector{cm}
+}
+
+// Describe implements prometheus.Collector
+func (c *informerCacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- informerCacheObjectsDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *informerCacheCollector) Collect(ch chan<- prometheus.Metric) {
+	r, ok := c.cm.(informerRanger)
+	if !ok {
+		return
+	}
+
+	r.rangeInformers(func(key informerCacheKey, informer informers.GenericInformer) {
+		size := len(informer.Informer().GetStore().ListKeys())
+		ch <- prometheus.MustNewConstMetric(
+			informerCacheObjectsDesc,
+			prometheus.GaugeValue,
+			float64(size),
+			key.kubeContext,
+			key.namespace,
+			key.gvr.GroupResource().String(),
+		)
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8shelpers
+
+import (
+	"strings"
+	"testing"
+
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestInformerCacheCollector(t *testing.T) {
+	cm, err := NewInClusterConnectionManager()
+	require.NoError(t, err)
+
+	// Add informer with two cached pods
+	gvr := corev1.SchemeGroupVersion.WithResource("pods")
+	factory := informers.NewSharedInformerFactoryWithOptions(fake.NewClientset(), 0, informers.WithNamespace("ns1"))
+	informer, err := factory.ForResource(gvr)
+	require.NoError(t, err)
+
+	store := informer.Informer().GetStore()
+	require.NoError(t, store.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pod1"}}))
+	require.NoError(t, store.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pod2"}}))
+
+	cm.informerCache[informerCacheKey{"", "ns1", gvr}] = informer
+
+	expected := `
+# HELP kubetail_connection_manager_informer_cache_objects Number of objects held in the connection manager's informer caches.
+# TYPE kubetail_connection_manager_informer_cache_objects gauge
+kubetail_connection_manager_informer_cache_objects{kube_context="",namespace="ns1",resource="pods"} 2
+`
+	err = promtestutil.CollectAndCompare(NewInformerCacheCollector(cm), strings.NewReader(expected))
+	assert.NoError(t, err)
+}
+
+func TestInformerCacheCollector_PrunesStopped(t *testing.T) {
+	cm, err := NewInClusterConnectionManager()
+	require.NoError(t, err)
+
+	// Add informer and stop it
+	gvr := corev1.SchemeGroupVersion.WithResource("pods")
+	factory := informers.NewSharedInformerFactoryWithOptions(fake.NewClientset(), 0, informers.WithNamespace("ns1"))
+	informer, err := factory.ForResource(gvr)
+	require.NoError(t, err)
+
+	cm.informerCache[informerCacheKey{"", "ns1", gvr}] = informer
+
+	stopCh := make(chan struct{})
+	factory.Start(stopCh)
+	close(stopCh)
+	factory.Shutdown()
+	require.True(t, informer.Informer().IsStopped())
+
+	assert.Equal(t, 0, promtestutil.CollectAndCount(NewInformerCacheCollector(cm)))
+	assert.Empty(t, cm.informerCache)
+}
+
+func TestDesktopConnectionManager_KubeConfigModified_PrunesInformers(t *testing.T) {
+	gvr := corev1.SchemeGroupVersion.WithResource("pods")
+	factory := informers.NewSharedInformerFactory(fake.NewClientset(), 0)
+	informer, err := factory.ForResource(gvr)
+	require.NoError(t, err)
+
+	cm := &DesktopConnectionManager{}
+	cm.informerCache.Store(informerCacheKey{"ctx1", "", gvr}, informer)
+	cm.informerCache.Store(informerCacheKey{"ctx2", "", gvr}, informer)
+
+	// Informers for removed contexts are dropped
+	kubeConfig := api.NewConfig()
+	kubeConfig.Contexts["ctx1"] = &api.Context{}
+	cm.kubeConfigModified(kubeConfig)
+
+	_, ok := cm.informerCache.Load(informerCacheKey{"ctx1", "", gvr})
+	assert.True(t, ok)
+	_, ok = cm.informerCache.Load(informerCacheKey{"ctx2", "", gvr})
+	assert.False(t, ok)
+}
+
+func TestInformerCacheCollector_Unsupported(t *testing.T) {
+	// Connection managers that don't track informers report nothing
+	assert.Equal(t, 0, promtestutil.CollectAndCount(NewInformerCacheCollector(nil)))
+}
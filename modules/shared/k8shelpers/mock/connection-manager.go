@@ -23,6 +23,8 @@ import (
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+
+	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
 )
 
 // Represents mock for connection manager
@@ -79,6 +81,17 @@ func (m *MockConnectionManager) NewInformer(ctx context.Context, kubeContext str
 	return r0, r1, ret.Error(2)
 }
 
+func (m *MockConnectionManager) GetPermissions(ctx context.Context, kubeContext string, token string, namespace string) (*k8shelpers.Permissions, error) {
+	ret := m.Called(ctx, kubeContext, token, namespace)
+
+	var r0 *k8shelpers.Permissions
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*k8shelpers.Permissions)
+	}
+
+	return r0, ret.Error(1)
+}
+
 func (m *MockConnectionManager) GetDefaultNamespace(kubeContext string) string {
 	ret := m.Called(kubeContext)
 	return ret.String(0)
@@ -0,0 +1,180 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8shelpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubetail-org/kubetail/modules/shared/util"
+)
+
+// Returned when rules are incomplete and a SelfSubjectAccessReview is needed
+var errRulesIncomplete = errors.New("rules incomplete")
+
+// Represents the resource rules of a user in a namespace
+type Permissions struct {
+	Namespace string
+	Rules     []authv1.ResourceRule
+
+	// Rules may be missing some permissions (e.g. with webhook authorizers)
+	Incomplete bool
+}
+
+// Check if rules allow `verb` on all objects of `resource` in API group
+// `group`. Resources may include a subresource (e.g. "pods/log").
+func (p *Permissions) Allows(group string, resource string, verb string) bool {
+	for _, rule := range p.Rules {
+		// Rules restricted to named objects don't apply to collections
+		if len(rule.ResourceNames) > 0 {
+			continue
+		}
+
+		if ruleMatches(rule.Verbs, verb) && ruleMatches(rule.APIGroups, group) && resourceMatches(rule.Resources, resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check if rules allow creating informers for `gvr`. Returns errRulesIncomplete
+// if rules don't allow it but may be incomplete.
+func (p *Permissions) checkInformer(gvr schema.GroupVersionResource) error {
+	for _, verb := range []string{"list", "watch"} {
+		if p.Allows(gvr.Group, gvr.Resource, verb) {
+			continue
+		}
+		// Authenticated users always have some rules so an empty list means the
+		// authorizer doesn't support rules reviews
+		if p.Incomplete || len(p.Rules) == 0 {
+			return errRulesIncomplete
+		}
+		return newPermissionDeniedError(verb, gvr.Group, gvr.Resource, p.Namespace)
+	}
+	return nil
+}
+
+// Check if `val` is in rule values (supports wildcard)
+func ruleMatches(ruleVals []string, val string) bool {
+	for _, ruleVal := range ruleVals {
+		if ruleVal == "*" || ruleVal == val {
+			return true
+		}
+	}
+	return false
+}
+
+// Check if `resource` is in rule resources (supports wildcards and subresources)
+func resourceMatches(ruleResources []string, resource string) bool {
+	_, subresource, hasSubresource := strings.Cut(resource, "/")
+	for _, ruleResource := range ruleResources {
+		if ruleResource == "*" || ruleResource == resource {
+			return true
+		}
+		if hasSubresource && ruleResource == "*/"+subresource {
+			return true
+		}
+	}
+	return false
+}
+
+// rulesCacheKey represents a unique key for caching rules
+type rulesCacheKey struct {
+	identity  string
+	namespace string
+}
+
+// rulesCacheValue represents cached rules with expiration
+type rulesCacheValue struct {
+	permissions *Permissions
+	expiration  time.Time
+}
+
+// Caches SelfSubjectRulesReview results (allow and deny) by identity and namespace
+type rulesCache struct {
+	entries util.SyncMap[rulesCacheKey, rulesCacheValue]
+	group   singleflight.Group
+}
+
+// Return cached rules or fetch them using `clientset`
+func (c *rulesCache) get(ctx context.Context, clientset kubernetes.Interface, identity string, namespace string) (*Permissions, error) {
+	key := rulesCacheKey{identity, namespace}
+
+	// Check if we have a valid cached result
+	if cachedVal, ok := c.entries.Load(key); ok && time.Now().Before(cachedVal.expiration) {
+		return cachedVal.permissions, nil
+	}
+
+	// Concurrent requests for the same key share one review
+	v, err, _ := c.group.Do(fmt.Sprintf("%q/%q", identity, namespace), func() (any, error) {
+		review := &authv1.SelfSubjectRulesReview{
+			Spec: authv1.SelfSubjectRulesReviewSpec{
+				Namespace: namespace,
+			},
+		}
+
+		result, err := clientset.AuthorizationV1().SelfSubjectRulesReviews().Create(ctx, review, metav1.CreateOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		permissions := &Permissions{
+			Namespace:  namespace,
+			Rules:      result.Status.ResourceRules,
+			Incomplete: result.Status.Incomplete,
+		}
+
+		c.pruneExpired()
+		c.entries.Store(key, rulesCacheValue{
+			permissions: permissions,
+			expiration:  time.Now().Add(cacheTTL),
+		})
+
+		return permissions, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*Permissions), nil
+}
+
+// Remove all cached rules
+func (c *rulesCache) invalidate() {
+	c.entries.Range(func(key rulesCacheKey, _ rulesCacheValue) bool {
+		c.entries.Delete(key)
+		return true
+	})
+}
+
+// Remove expired rules
+func (c *rulesCache) pruneExpired() {
+	now := time.Now()
+	c.entries.Range(func(key rulesCacheKey, val rulesCacheValue) bool {
+		if now.After(val.expiration) {
+			c.entries.Delete(key)
+		}
+		return true
+	})
+}
@@ -0,0 +1,221 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8shelpers
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func TestPermissionsAllows(t *testing.T) {
+	tests := []struct {
+		name        string
+		setRules    []authorizationv1.ResourceRule
+		setGroup    string
+		setResource string
+		setVerb     string
+		wantAllowed bool
+	}{
+		{
+			"no rules",
+			nil,
+			"", "pods", "list",
+			false,
+		},
+		{
+			"exact match",
+			[]authorizationv1.ResourceRule{{Verbs: []string{"list"}, APIGroups: []string{""}, Resources: []string{"pods"}}},
+			"", "pods", "list",
+			true,
+		},
+		{
+			"verb mismatch",
+			[]authorizationv1.ResourceRule{{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}}},
+			"", "pods", "list",
+			false,
+		},
+		{
+			"group mismatch",
+			[]authorizationv1.ResourceRule{{Verbs: []string{"list"}, APIGroups: []string{"apps"}, Resources: []string{"pods"}}},
+			"", "pods", "list",
+			false,
+		},
+		{
+			"wildcards",
+			[]authorizationv1.ResourceRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}},
+			"apps", "deployments", "watch",
+			true,
+		},
+		{
+			"subresource exact match",
+			[]authorizationv1.ResourceRule{{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods/log"}}},
+			"", "pods/log", "get",
+			true,
+		},
+		{
+			"subresource wildcard",
+			[]authorizationv1.ResourceRule{{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"*/log"}}},
+			"", "pods/log", "get",
+			true,
+		},
+		{
+			"resource doesn't grant subresource",
+			[]authorizationv1.ResourceRule{{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}}},
+			"", "pods/log", "get",
+			false,
+		},
+		{
+			"named resources are ignored",
+			[]authorizationv1.ResourceRule{{Verbs: []string{"list"}, APIGroups: []string{""}, Resources: []string{"pods"}, ResourceNames: []string{"my-pod"}}},
+			"", "pods", "list",
+			false,
+		},
+		{
+			"multiple rules",
+			[]authorizationv1.ResourceRule{
+				{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+				{Verbs: []string{"list", "watch"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+			},
+			"", "pods", "watch",
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Permissions{Namespace: "default", Rules: tt.setRules}
+			assert.Equal(t, tt.wantAllowed, p.Allows(tt.setGroup, tt.setResource, tt.setVerb))
+		})
+	}
+}
+
+func TestPermissionsCheckInformer(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+	listOnly := []authorizationv1.ResourceRule{{Verbs: []string{"list"}, APIGroups: []string{""}, Resources: []string{"pods"}}}
+	listWatch := []authorizationv1.ResourceRule{{Verbs: []string{"list", "watch"}, APIGroups: []string{""}, Resources: []string{"pods"}}}
+
+	t.Run("allowed", func(t *testing.T) {
+		p := &Permissions{Namespace: "default", Rules: listWatch}
+		assert.NoError(t, p.checkInformer(gvr))
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		p := &Permissions{Namespace: "default", Rules: listOnly}
+		err := p.checkInformer(gvr)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "permission denied: `watch \"\"/\"pods\"` in namespace `default`")
+	})
+
+	t.Run("incomplete", func(t *testing.T) {
+		p := &Permissions{Namespace: "default", Rules: listOnly, Incomplete: true}
+		assert.ErrorIs(t, p.checkInformer(gvr), errRulesIncomplete)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		p := &Permissions{Namespace: "default"}
+		assert.ErrorIs(t, p.checkInformer(gvr), errRulesIncomplete)
+	})
+}
+
+// Create fake clientset that responds to rules reviews with `rules`
+func newRulesReviewClientset(rules []authorizationv1.ResourceRule, count *atomic.Int32) *fake.Clientset {
+	clientset := fake.NewSimpleClientset()
+	clientset.Fake.PrependReactor("create", "selfsubjectrulesreviews", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+		count.Add(1)
+		obj := action.(ktesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectRulesReview)
+		return true, &authorizationv1.SelfSubjectRulesReview{
+			Spec: obj.Spec,
+			Status: authorizationv1.SubjectRulesReviewStatus{
+				ResourceRules: rules,
+			},
+		}, nil
+	})
+	return clientset
+}
+
+func TestRulesCacheGet(t *testing.T) {
+	rules := []authorizationv1.ResourceRule{{Verbs: []string{"list"}, APIGroups: []string{""}, Resources: []string{"pods"}}}
+
+	var count atomic.Int32
+	clientset := newRulesReviewClientset(rules, &count)
+
+	c := &rulesCache{}
+
+	// First call fetches rules
+	p, err := c.get(context.Background(), clientset, "", "ns1")
+	require.NoError(t, err)
+	assert.Equal(t, "ns1", p.Namespace)
+	assert.Equal(t, rules, p.Rules)
+	assert.Equal(t, int32(1), count.Load())
+
+	// Second call uses cache
+	_, err = c.get(context.Background(), clientset, "", "ns1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), count.Load())
+
+	// Different namespace and identity are cached separately
+	_, err = c.get(context.Background(), clientset, "", "ns2")
+	require.NoError(t, err)
+	_, err = c.get(context.Background(), clientset, "alice", "ns1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), count.Load())
+}
+
+func TestRulesCacheExpiry(t *testing.T) {
+	var count atomic.Int32
+	clientset := newRulesReviewClientset(nil, &count)
+
+	c := &rulesCache{}
+
+	_, err := c.get(context.Background(), clientset, "", "ns1")
+	require.NoError(t, err)
+
+	// Expire cache and try again
+	c.entries.Range(func(key rulesCacheKey, value rulesCacheValue) bool {
+		value.expiration = time.Now().Add(-1 * time.Minute)
+		c.entries.Store(key, value)
+		return true
+	})
+
+	_, err = c.get(context.Background(), clientset, "", "ns1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), count.Load())
+}
+
+func TestRulesCacheInvalidate(t *testing.T) {
+	var count atomic.Int32
+	clientset := newRulesReviewClientset(nil, &count)
+
+	c := &rulesCache{}
+
+	_, err := c.get(context.Background(), clientset, "", "ns1")
+	require.NoError(t, err)
+
+	c.invalidate()
+
+	_, err = c.get(context.Background(), clientset, "", "ns1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), count.Load())
+}
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 type BearerTokenRoundTripper struct {
@@ -38,6 +39,14 @@ func (b *BearerTokenRoundTripper) RoundTrip(req *http.Request) (*http.Response,
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 	}
 
+	// Add impersonation headers. Request-scoped identities override the
+	// statically configured one, which doesn't apply to request-scoped tokens.
+	if imp := ImpersonationFromContext(ctx); imp != nil {
+		setImpersonationHeaders(req.Header, imp)
+	} else if ok {
+		clearImpersonationHeaders(req.Header)
+	}
+
 	// Call original transport
 	return b.Transport.RoundTrip(req)
 }
@@ -46,6 +55,16 @@ func NewBearerTokenRoundTripper(transport http.RoundTripper) *BearerTokenRoundTr
 	return &BearerTokenRoundTripper{transport}
 }
 
+// Wrap Kubernetes API transport with request-scoped authentication and a
+// span for each request
+func wrapTransport(transport http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(NewBearerTokenRoundTripper(transport),
+		otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
+			return "k8s " + r.Method
+		}),
+	)
+}
+
 // Represents round tripper for service account tokens mounted locally
 // at "/var/run/secrets/kubernetes.io/serviceaccount/token"
 type InClusterSATRoundTripper struct {
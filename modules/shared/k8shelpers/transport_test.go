@@ -13,6 +13,8 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
 )
 
 // Note: The InClusterSATRoundTripper tests were written by codex. They're not bad
@@ -167,3 +169,75 @@ func newTestTokenWithoutExp(t *testing.T) string {
 
 	return signed
 }
+
+func TestBearerTokenRoundTripper_impersonationHeaders(t *testing.T) {
+	testserver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "", r.Header.Get("Authorization"))
+		assert.Equal(t, "alice@example.com", r.Header.Get("Impersonate-User"))
+		assert.Equal(t, []string{"dev", "ops"}, r.Header.Values("Impersonate-Group"))
+	}))
+	defer testserver.Close()
+
+	c := &http.Client{Transport: NewBearerTokenRoundTripper(http.DefaultTransport)}
+
+	req, err := http.NewRequest("GET", testserver.URL, nil)
+	assert.Nil(t, err)
+
+	imp := &rest.ImpersonationConfig{UserName: "alice@example.com", Groups: []string{"dev", "ops"}}
+	req = req.WithContext(context.WithValue(req.Context(), ImpersonateCtxKey, imp))
+
+	_, err = c.Do(req)
+	assert.Nil(t, err)
+}
+
+func TestBearerTokenRoundTripper_impersonationOverridesStatic(t *testing.T) {
+	testserver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "alice@example.com", r.Header.Get("Impersonate-User"))
+		assert.Equal(t, "", r.Header.Get("Impersonate-Uid"))
+		assert.Equal(t, []string{"dev"}, r.Header.Values("Impersonate-Group"))
+		assert.Equal(t, "", r.Header.Get("Impersonate-Extra-Scopes"))
+	}))
+	defer testserver.Close()
+
+	// Statically configured identity is added before the bearer token round tripper runs
+	static := transport.ImpersonationConfig{
+		UserName: "admin",
+		UID:      "1234",
+		Groups:   []string{"system:masters"},
+		Extra:    map[string][]string{"scopes": {"all"}},
+	}
+	rt := transport.NewImpersonatingRoundTripper(static, NewBearerTokenRoundTripper(http.DefaultTransport))
+
+	c := &http.Client{Transport: rt}
+
+	req, err := http.NewRequest("GET", testserver.URL, nil)
+	assert.Nil(t, err)
+
+	imp := &rest.ImpersonationConfig{UserName: "alice@example.com", Groups: []string{"dev"}}
+	req = req.WithContext(context.WithValue(req.Context(), ImpersonateCtxKey, imp))
+
+	_, err = c.Do(req)
+	assert.Nil(t, err)
+}
+
+func TestBearerTokenRoundTripper_tokenIgnoresStaticImpersonation(t *testing.T) {
+	testserver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer "+TEST_TOKEN, r.Header.Get("Authorization"))
+		assert.Equal(t, "", r.Header.Get("Impersonate-User"))
+		assert.Empty(t, r.Header.Values("Impersonate-Group"))
+	}))
+	defer testserver.Close()
+
+	static := transport.ImpersonationConfig{UserName: "admin", Groups: []string{"system:masters"}}
+	rt := transport.NewImpersonatingRoundTripper(static, NewBearerTokenRoundTripper(http.DefaultTransport))
+
+	c := &http.Client{Transport: rt}
+
+	req, err := http.NewRequest("GET", testserver.URL, nil)
+	assert.Nil(t, err)
+
+	req = req.WithContext(context.WithValue(req.Context(), K8STokenCtxKey, TEST_TOKEN))
+
+	_, err = c.Do(req)
+	assert.Nil(t, err)
+}
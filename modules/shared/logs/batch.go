@@ -0,0 +1,234 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"container/heap"
+	"context"
+)
+
+// Maximum number of records in a batch passed between internal goroutines
+const maxBatchSize = 256
+
+// Return error if batch is an error batch. Errors end a stream and are sent
+// in a batch of their own.
+func batchErr(batch []LogRecord) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	return batch[len(batch)-1].err
+}
+
+// Return single-record batch containing the error
+func newErrBatch(err error) []LogRecord {
+	return []LogRecord{{err: err}}
+}
+
+// batchRecords groups records into batches of the records that are ready
+// whenever the consumer asks for more
+func batchRecords(ctx context.Context, inCh <-chan LogRecord) <-chan []LogRecord {
+	outCh := make(chan []LogRecord)
+
+	// Send batch to output channel
+	send := func(batch []LogRecord) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case outCh <- batch:
+			return true
+		}
+	}
+
+	go func() {
+		defer close(outCh)
+
+		for {
+			// Wait for first record
+			var first LogRecord
+			select {
+			case <-ctx.Done():
+				return
+			case r, ok := <-inCh:
+				if !ok {
+					return
+				}
+				first = r
+			}
+
+			if first.err != nil {
+				send(newErrBatch(first.err))
+				return
+			}
+
+			// Add records that are already available
+			batch := []LogRecord{first}
+			var err error
+
+		DrainLoop:
+			for len(batch) < maxBatchSize {
+				select {
+				case r, ok := <-inCh:
+					if !ok {
+						break DrainLoop
+					}
+					if r.err != nil {
+						err = r.err
+						break DrainLoop
+					}
+					batch = append(batch, r)
+				default:
+					break DrainLoop
+				}
+			}
+
+			if !send(batch) {
+				return
+			}
+
+			if err != nil {
+				send(newErrBatch(err))
+				return
+			}
+		}
+	}()
+
+	return outCh
+}
+
+// unbatchRecords sends the records in each batch one at a time
+func unbatchRecords(ctx context.Context, inCh <-chan []LogRecord) <-chan LogRecord {
+	outCh := make(chan LogRecord)
+
+	go func() {
+		defer close(outCh)
+
+		for batch := range inCh {
+			for _, r := range batch {
+				select {
+				case <-ctx.Done():
+					return
+				case outCh <- r:
+				}
+			}
+		}
+	}()
+
+	return outCh
+}
+
+// mergeLogBatches merges multiple ordered streams of batches into a single
+// stream of batches. If a stream sends an error, the error is forwarded and
+// the merged stream ends.
+func mergeLogBatches(ctx context.Context, reverse bool, streams ...<-chan []LogRecord) <-chan []LogRecord {
+	outCh := make(chan []LogRecord)
+
+	// Run in goroutine
+	go func() {
+		defer close(outCh)
+
+		var batch []LogRecord
+
+		// Send pending batch
+		flush := func() bool {
+			if len(batch) == 0 {
+				return true
+			}
+			select {
+			case <-ctx.Done():
+				return false
+			case outCh <- batch:
+				batch = nil
+				return true
+			}
+		}
+
+		// Build a min-heap of the first item from each stream.
+		pq := newPriorityQueue(reverse)
+		heap.Init(pq)
+
+		// Push the next entry from the current batch or from the source
+		// channel. Returns false if the merged stream should end.
+		push := func(rest []LogRecord, srcCh <-chan []LogRecord) bool {
+			for len(rest) == 0 {
+				var next []LogRecord
+				var ok bool
+
+				// Flush before blocking so downstream isn't kept waiting
+				select {
+				case next, ok = <-srcCh:
+				default:
+					if !flush() {
+						return false
+					}
+					select {
+					case <-ctx.Done():
+						return false
+					case next, ok = <-srcCh:
+					}
+				}
+
+				if !ok {
+					return true
+				}
+
+				if err := batchErr(next); err != nil {
+					if flush() {
+						select {
+						case <-ctx.Done():
+						case outCh <- next:
+						}
+					}
+					return false
+				}
+
+				rest = next
+			}
+
+			heap.Push(pq, recordWithSource{
+				record: rest[0],
+				rest:   rest[1:],
+				srcCh:  srcCh,
+			})
+			return true
+		}
+
+		// Initialize the heap with the first entry from each stream
+		for _, ch := range streams {
+			if !push(nil, ch) {
+				return
+			}
+		}
+
+		// Repeatedly pop the earliest entry and replace it with
+		// the next from the same source.
+		for pq.Len() > 0 {
+			// Pop the earliest entry
+			earliest := heap.Pop(pq).(recordWithSource)
+
+			batch = append(batch, earliest.record)
+			if len(batch) >= maxBatchSize && !flush() {
+				return
+			}
+
+			if !push(earliest.rest, earliest.srcCh) {
+				return
+			}
+		}
+
+		flush()
+	}()
+
+	return outCh
+}
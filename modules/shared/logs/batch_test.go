@@ -0,0 +1,352 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Return closed channel containing records in batches of the given size
+func newBatchChannel(records []LogRecord, size int) <-chan []LogRecord {
+	ch := make(chan []LogRecord, len(records)+1)
+	for batch := range slices.Chunk(records, size) {
+		ch <- batch
+	}
+	close(ch)
+	return ch
+}
+
+func TestBatchRecords(t *testing.T) {
+	now := time.Now()
+
+	t.Run("groups available records", func(t *testing.T) {
+		inCh := make(chan LogRecord, 3)
+		inCh <- LogRecord{Timestamp: now, Message: "msg1"}
+		inCh <- LogRecord{Timestamp: now, Message: "msg2"}
+		inCh <- LogRecord{Timestamp: now, Message: "msg3"}
+		close(inCh)
+
+		var batches [][]LogRecord
+		for batch := range batchRecords(context.Background(), inCh) {
+			batches = append(batches, batch)
+		}
+
+		require.Len(t, batches, 1)
+		assert.Len(t, batches[0], 3)
+	})
+
+	t.Run("sends error in its own batch", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+
+		inCh := make(chan LogRecord, 3)
+		inCh <- LogRecord{Timestamp: now, Message: "msg1"}
+		inCh <- LogRecord{err: expectedErr}
+		inCh <- LogRecord{Timestamp: now, Message: "msg2"}
+		close(inCh)
+
+		var batches [][]LogRecord
+		for batch := range batchRecords(context.Background(), inCh) {
+			batches = append(batches, batch)
+		}
+
+		require.Len(t, batches, 2)
+		assert.Equal(t, "msg1", batches[0][0].Message)
+		assert.NoError(t, batchErr(batches[0]))
+		assert.ErrorIs(t, batchErr(batches[1]), expectedErr)
+	})
+
+	t.Run("limits batch size", func(t *testing.T) {
+		inCh := make(chan LogRecord, maxBatchSize+1)
+		for range maxBatchSize + 1 {
+			inCh <- LogRecord{Timestamp: now}
+		}
+		close(inCh)
+
+		var sizes []int
+		for batch := range batchRecords(context.Background(), inCh) {
+			sizes = append(sizes, len(batch))
+		}
+
+		assert.Equal(t, []int{maxBatchSize, 1}, sizes)
+	})
+}
+
+func TestUnbatchRecords(t *testing.T) {
+	records := []LogRecord{{Message: "msg1"}, {Message: "msg2"}, {Message: "msg3"}}
+
+	var results []LogRecord
+	for r := range unbatchRecords(context.Background(), newBatchChannel(records, 2)) {
+		results = append(results, r)
+	}
+
+	assert.Equal(t, records, results)
+}
+
+func TestMergeLogBatches(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		streams  [][]LogRecord
+		expected []LogRecord
+	}{
+		{
+			name: "merge two ordered streams",
+			streams: [][]LogRecord{
+				{
+					{Timestamp: now, Message: "stream1-1"},
+					{Timestamp: now.Add(2 * time.Second), Message: "stream1-2"},
+				},
+				{
+					{Timestamp: now.Add(time.Second), Message: "stream2-1"},
+					{Timestamp: now.Add(3 * time.Second), Message: "stream2-2"},
+				},
+			},
+			expected: []LogRecord{
+				{Timestamp: now, Message: "stream1-1"},
+				{Timestamp: now.Add(time.Second), Message: "stream2-1"},
+				{Timestamp: now.Add(2 * time.Second), Message: "stream1-2"},
+				{Timestamp: now.Add(3 * time.Second), Message: "stream2-2"},
+			},
+		},
+		{
+			name: "merge empty streams",
+			streams: [][]LogRecord{
+				{},
+				{},
+			},
+			expected: []LogRecord{},
+		},
+		{
+			name: "merge single stream",
+			streams: [][]LogRecord{
+				{
+					{Timestamp: now, Message: "msg1"},
+					{Timestamp: now.Add(time.Second), Message: "msg2"},
+				},
+			},
+			expected: []LogRecord{
+				{Timestamp: now, Message: "msg1"},
+				{Timestamp: now.Add(time.Second), Message: "msg2"},
+			},
+		},
+		{
+			name: "merge streams with same timestamps",
+			streams: [][]LogRecord{
+				{
+					{Timestamp: now, Message: "stream1"},
+				},
+				{
+					{Timestamp: now, Message: "stream2"},
+				},
+			},
+			expected: []LogRecord{
+				{Timestamp: now, Message: "stream1"},
+				{Timestamp: now, Message: "stream2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create channels and feed test data
+			ctx := context.Background()
+			streams := make([]<-chan []LogRecord, len(tt.streams))
+			for i, records := range tt.streams {
+				streams[i] = newBatchChannel(records, 1)
+			}
+
+			// Run mergeLogBatches
+			merged := mergeLogBatches(ctx, false, streams...)
+
+			// Collect results
+			var results []LogRecord
+			for batch := range merged {
+				results = append(results, batch...)
+			}
+
+			// Verify results
+			assert.Equal(t, len(tt.expected), len(results), "number of records should match")
+			for i := range results {
+				assert.Equal(t, tt.expected[i].Timestamp, results[i].Timestamp, "timestamps should match at index %d", i)
+				assert.Equal(t, tt.expected[i].Message, results[i].Message, "messages should match at index %d", i)
+			}
+		})
+	}
+}
+
+func TestMergeLogBatchesReverse(t *testing.T) {
+	baseTS := time.Now()
+
+	tests := []struct {
+		name     string
+		streams  [][]LogRecord
+		expected []LogRecord
+	}{
+		{
+			name: "merge two reverse ordered streams",
+			streams: [][]LogRecord{
+				{
+					{Timestamp: baseTS.Add(2 * time.Second), Message: "stream1-2"},
+					{Timestamp: baseTS, Message: "stream1-1"},
+				},
+				{
+					{Timestamp: baseTS.Add(3 * time.Second), Message: "stream2-2"},
+					{Timestamp: baseTS.Add(time.Second), Message: "stream2-1"},
+				},
+			},
+			expected: []LogRecord{
+				{Timestamp: baseTS.Add(3 * time.Second), Message: "stream2-2"},
+				{Timestamp: baseTS.Add(2 * time.Second), Message: "stream1-2"},
+				{Timestamp: baseTS.Add(time.Second), Message: "stream2-1"},
+				{Timestamp: baseTS, Message: "stream1-1"},
+			},
+		},
+		{
+			name: "merge empty streams",
+			streams: [][]LogRecord{
+				{},
+				{},
+			},
+			expected: []LogRecord{},
+		},
+		{
+			name: "merge single stream",
+			streams: [][]LogRecord{
+				{
+					{Timestamp: baseTS.Add(time.Second), Message: "msg2"},
+					{Timestamp: baseTS, Message: "msg1"},
+				},
+			},
+			expected: []LogRecord{
+				{Timestamp: baseTS.Add(time.Second), Message: "msg2"},
+				{Timestamp: baseTS, Message: "msg1"},
+			},
+		},
+		{
+			name: "merge streams with same timestamps",
+			streams: [][]LogRecord{
+				{
+					{Timestamp: baseTS, Message: "stream1"},
+				},
+				{
+					{Timestamp: baseTS, Message: "stream2"},
+				},
+			},
+			expected: []LogRecord{
+				{Timestamp: baseTS, Message: "stream1"},
+				{Timestamp: baseTS, Message: "stream2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create channels and feed test data
+			ctx := context.Background()
+			streams := make([]<-chan []LogRecord, len(tt.streams))
+			for i, records := range tt.streams {
+				streams[i] = newBatchChannel(records, 1)
+			}
+
+			// Run mergeLogBatches in reverse mode
+			merged := mergeLogBatches(ctx, true, streams...)
+
+			// Collect results
+			var results []LogRecord
+			for batch := range merged {
+				results = append(results, batch...)
+			}
+
+			// Verify results
+			assert.Equal(t, len(tt.expected), len(results), "number of records should match")
+			for i := range results {
+				assert.Equal(t, tt.expected[i].Timestamp, results[i].Timestamp, "timestamps should match at index %d", i)
+				assert.Equal(t, tt.expected[i].Message, results[i].Message, "messages should match at index %d", i)
+			}
+		})
+	}
+}
+
+func TestMergeLogBatchesContextCancellation(t *testing.T) {
+	// Create test data
+	now := time.Now()
+	records := []LogRecord{
+		{Timestamp: now, Message: "msg1"},
+		{Timestamp: now.Add(time.Second), Message: "msg2"},
+		{Timestamp: now.Add(2 * time.Second), Message: "msg3"},
+	}
+
+	// Create channel with test data
+	ch := make(chan []LogRecord, len(records))
+	for _, record := range records {
+		ch <- []LogRecord{record}
+	}
+
+	// Create context with cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Run mergeLogBatches
+	merged := mergeLogBatches(ctx, false, ch)
+
+	// Read first record
+	batch := <-merged
+	record := batch[0]
+
+	// Verify first record
+	assert.Equal(t, records[0].Timestamp, record.Timestamp)
+	assert.Equal(t, records[0].Message, record.Message)
+
+	// Cancel context
+	cancel()
+
+	// Verify channel is closed
+	_, ok := <-merged
+	assert.False(t, ok, "channel should be closed after context cancellation")
+}
+
+func TestMergeLogBatchesError(t *testing.T) {
+	now := time.Now()
+	expectedErr := errors.New("test error")
+
+	ch1 := newBatchChannel([]LogRecord{{Timestamp: now, Message: "msg1"}, {Timestamp: now.Add(2 * time.Second), Message: "msg3"}}, 1)
+
+	ch2 := make(chan []LogRecord, 2)
+	ch2 <- []LogRecord{{Timestamp: now.Add(time.Second), Message: "msg2"}}
+	ch2 <- newErrBatch(expectedErr)
+	close(ch2)
+
+	var results []LogRecord
+	var err error
+	for batch := range mergeLogBatches(context.Background(), false, ch1, ch2) {
+		if err = batchErr(batch); err != nil {
+			break
+		}
+		results = append(results, batch...)
+	}
+
+	// Records before the error are delivered in order
+	assert.ErrorIs(t, err, expectedErr)
+	require.Len(t, results, 2)
+	assert.Equal(t, "msg1", results[0].Message)
+	assert.Equal(t, "msg2", results[1].Message)
+}
@@ -0,0 +1,383 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"k8s.io/client-go/kubernetes"
+
+	grpcdispatcher "github.com/kubetail-org/grpc-dispatcher-go"
+	"github.com/kubetail-org/kubetail/modules/shared/clusteragentpb"
+	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/metrics"
+)
+
+// Maximum number of sources counted concurrently
+const maxConcurrentCounts = 10
+
+// CountBucket represents the number of matching records in a time bucket
+type CountBucket struct {
+	Start time.Time // zero if counts aren't bucketed
+	Count int64
+}
+
+// SourceCount represents the number of matching records in a source
+type SourceCount struct {
+	Source  LogSource
+	Total   int64
+	Buckets []CountBucket
+}
+
+// CountResult represents the merged counts of all sources
+type CountResult struct {
+	Total   int64
+	Buckets []CountBucket
+	Sources []SourceCount
+}
+
+// CounterOptions defines options for counting logs
+type CounterOptions struct {
+	StartTime  time.Time
+	StopTime   time.Time
+	Grep       string
+	GrepRegex  *regexp.Regexp
+	Filter     *Filter
+	BucketSize time.Duration
+}
+
+// LogCounter defines counting of matching records per time bucket
+type LogCounter interface {
+	Count(ctx context.Context, source LogSource, opts CounterOptions) ([]CountBucket, error)
+}
+
+// KubeLogCounter implements LogCounter by reading logs from the Kubernetes API
+type KubeLogCounter struct {
+	fetcher *KubeLogFetcher
+}
+
+// NewKubeLogCounter creates a new KubeLogCounter
+func NewKubeLogCounter(clientset kubernetes.Interface) *KubeLogCounter {
+	return &KubeLogCounter{
+		fetcher: NewKubeLogFetcher(clientset),
+	}
+}
+
+// Count returns the number of matching records in the given source
+func (c *KubeLogCounter) Count(ctx context.Context, source LogSource, opts CounterOptions) ([]CountBucket, error) {
+	if err := validateBucketSize(opts.BucketSize); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.fetcher.StreamForward(ctx, source, FetcherOptions{
+		StartTime: opts.StartTime,
+		StopTime:  opts.StopTime,
+		Grep:      opts.Grep,
+		GrepRegex: opts.GrepRegex,
+		Filter:    opts.Filter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[int64]int64{}
+	for record := range stream {
+		if record.err != nil {
+			return nil, record.err
+		}
+		counts[bucketStart(record.Timestamp, opts.BucketSize)] += 1
+	}
+
+	return newCountBuckets(counts, opts.BucketSize), nil
+}
+
+// AgentLogCounter implements LogCounter using Kubetail Cluster Agent
+type AgentLogCounter struct {
+	grpcDispatcher *grpcdispatcher.Dispatcher
+}
+
+// NewAgentLogCounter creates a new AgentLogCounter
+func NewAgentLogCounter(grpcDispatcher *grpcdispatcher.Dispatcher) *AgentLogCounter {
+	return &AgentLogCounter{
+		grpcDispatcher: grpcDispatcher,
+	}
+}
+
+// Count returns the number of matching records in the given source
+func (c *AgentLogCounter) Count(ctx context.Context, source LogSource, opts CounterOptions) ([]CountBucket, error) {
+	if err := validateBucketSize(opts.BucketSize); err != nil {
+		return nil, err
+	}
+
+	// Init gRPC request
+	req := &clusteragentpb.LogRecordsCountRequest{
+		Namespace:     source.Namespace,
+		PodName:       source.PodName,
+		ContainerName: source.ContainerName,
+		ContainerId:   source.ContainerID,
+		Grep:          opts.Grep,
+		Filter:        opts.Filter.toProto(),
+		BucketSeconds: int64(opts.BucketSize / time.Second),
+	}
+
+	if !opts.StartTime.IsZero() {
+		req.StartTime = opts.StartTime.Format(time.RFC3339Nano)
+	}
+
+	if !opts.StopTime.IsZero() {
+		req.StopTime = opts.StopTime.Format(time.RFC3339Nano)
+	}
+
+	var resp *clusteragentpb.LogRecordsCountResponse
+	var err error
+	called := false
+
+	c.grpcDispatcher.Unicast(ctx, source.Metadata.Node, func(ctx context.Context, conn *grpc.ClientConn) {
+		called = true
+		resp, err = clusteragentpb.NewLogRecordsServiceClient(conn).Count(ctx, req)
+	})
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if !called {
+		return nil, fmt.Errorf("no cluster agent available on node %s", source.Metadata.Node)
+	}
+
+	if err != nil {
+		metrics.AgentErrors.WithLabelValues(source.Metadata.Node, "Count").Inc()
+		return nil, err
+	}
+
+	buckets := make([]CountBucket, len(resp.Buckets))
+	for i, b := range resp.Buckets {
+		buckets[i].Count = b.Count
+		if b.Start != nil {
+			buckets[i].Start = b.Start.AsTime()
+		}
+	}
+
+	return buckets, nil
+}
+
+// CompositeLogCounter implements LogCounter by counting on the cluster agent
+// of nodes that have one and falling back to the Kubernetes API elsewhere
+type CompositeLogCounter struct {
+	agentCounter LogCounter
+	kubeCounter  LogCounter
+	hasAgent     func(ctx context.Context, nodeName string) bool
+}
+
+// NewCompositeLogCounter creates a new CompositeLogCounter
+func NewCompositeLogCounter(grpcDispatcher *grpcdispatcher.Dispatcher, clientset kubernetes.Interface) *CompositeLogCounter {
+	return &CompositeLogCounter{
+		agentCounter: NewAgentLogCounter(grpcDispatcher),
+		kubeCounter:  NewKubeLogCounter(clientset),
+		hasAgent: func(ctx context.Context, nodeName string) bool {
+			return hasServingAgent(ctx, grpcDispatcher, nodeName)
+		},
+	}
+}
+
+// Count returns the number of matching records in the given source
+func (c *CompositeLogCounter) Count(ctx context.Context, source LogSource, opts CounterOptions) ([]CountBucket, error) {
+	if c.hasAgent(ctx, source.Metadata.Node) {
+		return c.agentCounter.Count(ctx, source, opts)
+	}
+
+	metrics.AgentFallbacks.WithLabelValues(source.Metadata.Node).Inc()
+	return c.kubeCounter.Count(ctx, source, opts)
+}
+
+// Counter counts matching records across a set of sources
+type Counter struct {
+	sinceTime time.Time
+	untilTime time.Time
+
+	grep       string
+	grepRegex  *regexp.Regexp
+	filter     *Filter
+	bucketSize time.Duration
+	maxSources int
+
+	kubeContext string
+	bearerToken string
+	sw          SourceWatcher
+	logCounter  LogCounter
+}
+
+// Initialize new counter
+func NewCounter(cm k8shelpers.ConnectionManager, sourcePaths []string, opts ...Option) (*Counter, error) {
+	// Init counter instance
+	counter := &Counter{}
+
+	// Apply options
+	for _, opt := range opts {
+		if err := opt(counter); err != nil {
+			return nil, err
+		}
+	}
+
+	// Init source watcher
+	sw, err := NewSourceWatcher(cm, sourcePaths, opts...)
+	if err != nil {
+		return nil, err
+	}
+	counter.sw = sw
+
+	// Init log counter if not already set
+	if counter.logCounter == nil {
+		clientset, err := cm.GetOrCreateClientset(counter.kubeContext)
+		if err != nil {
+			return nil, err
+		}
+		counter.logCounter = NewKubeLogCounter(clientset)
+	}
+
+	return counter, nil
+}
+
+// Count matching records in all sources and merge the results
+func (c *Counter) Count(ctx context.Context) (*CountResult, error) {
+	// Resolve sources
+	if err := c.sw.Start(ctx); err != nil {
+		return nil, err
+	}
+	defer c.sw.Close()
+
+	sources := c.sw.Set().ToSlice()
+
+	// Check source limit
+	if c.maxSources > 0 && len(sources) > c.maxSources {
+		return nil, fmt.Errorf("%w: %d sources matched (limit %d)", ErrMaxSourcesExceeded, len(sources), c.maxSources)
+	}
+
+	opts := CounterOptions{
+		StartTime:  c.sinceTime,
+		StopTime:   c.untilTime,
+		Grep:       c.grep,
+		GrepRegex:  c.grepRegex,
+		Filter:     c.filter,
+		BucketSize: c.bucketSize,
+	}
+
+	// Count sources concurrently
+	sourceCounts := make([]SourceCount, len(sources))
+
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentCounts)
+	for i, source := range sources {
+		g.Go(func() error {
+			buckets, err := c.logCounter.Count(gCtx, source, opts)
+			if err != nil {
+				return err
+			}
+
+			sc := SourceCount{Source: source, Buckets: buckets}
+			for _, b := range buckets {
+				sc.Total += b.Count
+			}
+			sourceCounts[i] = sc
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return mergeSourceCounts(sourceCounts, c.bucketSize), nil
+}
+
+// Merge per-source counts into a single result
+func mergeSourceCounts(sourceCounts []SourceCount, bucketSize time.Duration) *CountResult {
+	result := &CountResult{Sources: sourceCounts}
+
+	counts := map[int64]int64{}
+	for _, sc := range sourceCounts {
+		result.Total += sc.Total
+		for _, b := range sc.Buckets {
+			counts[bucketStart(b.Start, bucketSize)] += b.Count
+		}
+	}
+	result.Buckets = newCountBuckets(counts, bucketSize)
+
+	// Sort sources for stable output
+	slices.SortFunc(result.Sources, func(a, b SourceCount) int {
+		return cmp.Or(
+			cmp.Compare(a.Source.Namespace, b.Source.Namespace),
+			cmp.Compare(a.Source.PodName, b.Source.PodName),
+			cmp.Compare(a.Source.ContainerName, b.Source.ContainerName),
+			cmp.Compare(a.Source.ContainerID, b.Source.ContainerID),
+		)
+	})
+
+	return result
+}
+
+// Check that bucket size is 0 (single bucket) or a whole number of seconds
+// of at least one second
+func validateBucketSize(d time.Duration) error {
+	if d < 0 || (d > 0 && d < time.Second) || d%time.Second != 0 {
+		return fmt.Errorf("bucket size must be 0 or a whole number of seconds (minimum 1s)")
+	}
+	return nil
+}
+
+// Return start of the bucket containing `ts` in seconds since the Unix
+// epoch (0 if counts aren't bucketed)
+func bucketStart(ts time.Time, bucketSize time.Duration) int64 {
+	width := int64(bucketSize / time.Second)
+	if width <= 0 {
+		return 0
+	}
+
+	secs := ts.Unix()
+	start := secs / width * width
+	if secs < 0 && secs%width != 0 {
+		start -= width
+	}
+	return start
+}
+
+// Return non-empty buckets in chronological order
+func newCountBuckets(counts map[int64]int64, bucketSize time.Duration) []CountBucket {
+	buckets := make([]CountBucket, 0, len(counts))
+	for start, count := range counts {
+		b := CountBucket{Count: count}
+		if bucketSize > 0 {
+			b.Start = time.Unix(start, 0).UTC()
+		}
+		buckets = append(buckets, b)
+	}
+
+	slices.SortFunc(buckets, func(a, b CountBucket) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	return buckets
+}
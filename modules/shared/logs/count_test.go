@@ -0,0 +1,254 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	set "github.com/deckarep/golang-set/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type mockLogCounter struct {
+	mock.Mock
+}
+
+func (m *mockLogCounter) Count(ctx context.Context, source LogSource, opts CounterOptions) ([]CountBucket, error) {
+	ret := m.Called(ctx, source, opts)
+
+	var r0 []CountBucket
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]CountBucket)
+	}
+
+	return r0, ret.Error(1)
+}
+
+func TestBucketStart(t *testing.T) {
+	tests := []struct {
+		name          string
+		setTS         time.Time
+		setBucketSize time.Duration
+		want          int64
+	}{
+		{"no bucket size", time.Unix(125, 0), 0, 0},
+		{"aligned", time.Unix(120, 0), time.Minute, 120},
+		{"rounds down", time.Unix(179, 999), time.Minute, 120},
+		{"before epoch", time.Unix(-1, 0), time.Minute, -60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, bucketStart(tt.setTS, tt.setBucketSize))
+		})
+	}
+}
+
+func TestWithBucketSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		setBucketSize time.Duration
+		wantErr       bool
+	}{
+		{"zero", 0, false},
+		{"minute", time.Minute, false},
+		{"negative", -time.Second, true},
+		{"fractional", 1500 * time.Millisecond, true},
+		{"sub-second", 500 * time.Millisecond, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Counter{}
+			err := WithBucketSize(tt.setBucketSize)(c)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.setBucketSize, c.bucketSize)
+			}
+		})
+	}
+}
+
+func TestCounterCount(t *testing.T) {
+	s1 := LogSource{Namespace: "ns1", PodName: "pod1", ContainerName: "container1"}
+	s2 := LogSource{Namespace: "ns2", PodName: "pod2", ContainerName: "container2"}
+
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newSourceWatcher := func(sources ...LogSource) *mockSourceWatcher {
+		sw := &mockSourceWatcher{}
+		sw.On("Start", mock.Anything).Return(nil)
+		sw.On("Set").Return(set.NewSet(sources...))
+		sw.On("Close").Return()
+		return sw
+	}
+
+	t.Run("merges buckets", func(t *testing.T) {
+		lc := &mockLogCounter{}
+		lc.On("Count", mock.Anything, s1, mock.Anything).Return([]CountBucket{
+			{Start: ts, Count: 2},
+			{Start: ts.Add(time.Minute), Count: 3},
+		}, nil)
+		lc.On("Count", mock.Anything, s2, mock.Anything).Return([]CountBucket{
+			{Start: ts.Add(time.Minute), Count: 1},
+			{Start: ts.Add(2 * time.Minute), Count: 4},
+		}, nil)
+
+		c := &Counter{sw: newSourceWatcher(s2, s1), logCounter: lc, bucketSize: time.Minute}
+
+		result, err := c.Count(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(10), result.Total)
+		assert.Equal(t, []CountBucket{
+			{Start: ts, Count: 2},
+			{Start: ts.Add(time.Minute), Count: 4},
+			{Start: ts.Add(2 * time.Minute), Count: 4},
+		}, result.Buckets)
+
+		require.Len(t, result.Sources, 2)
+		assert.Equal(t, s1, result.Sources[0].Source)
+		assert.Equal(t, int64(5), result.Sources[0].Total)
+		assert.Equal(t, s2, result.Sources[1].Source)
+		assert.Equal(t, int64(5), result.Sources[1].Total)
+	})
+
+	t.Run("single bucket", func(t *testing.T) {
+		lc := &mockLogCounter{}
+		lc.On("Count", mock.Anything, s1, mock.Anything).Return([]CountBucket{{Count: 2}}, nil)
+		lc.On("Count", mock.Anything, s2, mock.Anything).Return([]CountBucket{{Count: 3}}, nil)
+
+		c := &Counter{sw: newSourceWatcher(s1, s2), logCounter: lc}
+
+		result, err := c.Count(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(5), result.Total)
+		assert.Equal(t, []CountBucket{{Count: 5}}, result.Buckets)
+	})
+
+	t.Run("passes options", func(t *testing.T) {
+		lc := &mockLogCounter{}
+		lc.On("Count", mock.Anything, s1, mock.Anything).Return([]CountBucket{}, nil)
+
+		c := &Counter{sw: newSourceWatcher(s1), logCounter: lc}
+		for _, opt := range []Option{WithSince(ts), WithGrep("error"), WithBucketSize(time.Hour)} {
+			require.NoError(t, opt(c))
+		}
+
+		_, err := c.Count(context.Background())
+		require.NoError(t, err)
+
+		opts := lc.Calls[0].Arguments.Get(2).(CounterOptions)
+		assert.Equal(t, ts, opts.StartTime)
+		assert.Equal(t, "(?i)error", opts.Grep)
+		assert.NotNil(t, opts.GrepRegex)
+		assert.Equal(t, time.Hour, opts.BucketSize)
+	})
+
+	t.Run("bounds concurrency", func(t *testing.T) {
+		sources := []LogSource{}
+		for i := range 3 * maxConcurrentCounts {
+			sources = append(sources, LogSource{Namespace: "ns", PodName: fmt.Sprintf("pod%d", i), ContainerName: "c"})
+		}
+
+		var current, peak atomic.Int32
+		lc := &mockLogCounter{}
+		lc.On("Count", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			n := current.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			current.Add(-1)
+		}).Return([]CountBucket{{Count: 1}}, nil)
+
+		c := &Counter{sw: newSourceWatcher(sources...), logCounter: lc}
+
+		result, err := c.Count(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(len(sources)), result.Total)
+		assert.LessOrEqual(t, peak.Load(), int32(maxConcurrentCounts))
+	})
+
+	t.Run("max sources exceeded", func(t *testing.T) {
+		c := &Counter{sw: newSourceWatcher(s1, s2), logCounter: &mockLogCounter{}, maxSources: 1}
+
+		_, err := c.Count(context.Background())
+		assert.ErrorIs(t, err, ErrMaxSourcesExceeded)
+	})
+
+	t.Run("counter error", func(t *testing.T) {
+		expectedErr := errors.New("agent unavailable")
+
+		lc := &mockLogCounter{}
+		lc.On("Count", mock.Anything, s1, mock.Anything).Return(nil, expectedErr)
+
+		c := &Counter{sw: newSourceWatcher(s1), logCounter: lc}
+
+		_, err := c.Count(context.Background())
+		assert.ErrorIs(t, err, expectedErr)
+	})
+}
+
+func TestKubeLogCounterInvalidBucketSize(t *testing.T) {
+	c := NewKubeLogCounter(fake.NewSimpleClientset())
+	_, err := c.Count(context.Background(), LogSource{Namespace: "ns1", PodName: "pod1", ContainerName: "c1"}, CounterOptions{BucketSize: 500 * time.Millisecond})
+	assert.Error(t, err)
+}
+
+func TestCompositeLogCounter(t *testing.T) {
+	s1 := LogSource{Namespace: "ns", PodName: "pod1", ContainerName: "c", Metadata: LogSourceMetadata{Node: "node1"}}
+	s2 := LogSource{Namespace: "ns", PodName: "pod2", ContainerName: "c", Metadata: LogSourceMetadata{Node: "node2"}}
+
+	agentCounter := &mockLogCounter{}
+	kubeCounter := &mockLogCounter{}
+	c := &CompositeLogCounter{
+		agentCounter: agentCounter,
+		kubeCounter:  kubeCounter,
+		hasAgent: func(ctx context.Context, nodeName string) bool {
+			return nodeName == "node1"
+		},
+	}
+
+	agentCounter.On("Count", mock.Anything, s1, mock.Anything).Return([]CountBucket{{Count: 1}}, nil)
+	kubeCounter.On("Count", mock.Anything, s2, mock.Anything).Return([]CountBucket{{Count: 2}}, nil)
+
+	// Sources on nodes without an agent are counted via the Kubernetes API
+	buckets, err := c.Count(context.Background(), s1, CounterOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []CountBucket{{Count: 1}}, buckets)
+
+	buckets, err = c.Count(context.Background(), s2, CounterOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []CountBucket{{Count: 2}}, buckets)
+
+	agentCounter.AssertNotCalled(t, "Count", mock.Anything, s2, mock.Anything)
+	kubeCounter.AssertNotCalled(t, "Count", mock.Anything, s1, mock.Anything)
+}
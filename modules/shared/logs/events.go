@@ -0,0 +1,294 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Number of informer events buffered before handlers wait on the stream
+const eventsChSize = 256
+
+// KubeEvent holds the details of records that come from Kubernetes events
+type KubeEvent struct {
+	Type   string // "Normal" or "Warning"
+	Reason string
+	Kind   string // kind of the involved object
+	Name   string // name of the involved object
+	Count  int32
+}
+
+// Implemented by source watchers that can tell which objects a stream's
+// sources belong to
+type eventMatcher interface {
+	eventNamespaces() []string
+	isRelatedObject(ref corev1.ObjectReference) bool
+}
+
+// Convert Kubernetes event to log record
+func newEventRecord(ev *corev1.Event) LogRecord {
+	ref := ev.InvolvedObject
+
+	source := LogSource{
+		Metadata:  LogSourceMetadata{Node: ev.Source.Host},
+		Namespace: ev.Namespace,
+	}
+	if ref.Kind == "Pod" {
+		source.PodName = ref.Name
+	}
+
+	return LogRecord{
+		Timestamp: eventTimestamp(ev),
+		Message:   strings.TrimSpace(ev.Message),
+		Source:    source,
+		Event: &KubeEvent{
+			Type:   ev.Type,
+			Reason: ev.Reason,
+			Kind:   ref.Kind,
+			Name:   ref.Name,
+			Count:  ev.Count,
+		},
+	}
+}
+
+// Return the time of the most recent occurrence of an event
+func eventTimestamp(ev *corev1.Event) time.Time {
+	switch {
+	case !ev.LastTimestamp.IsZero():
+		return ev.LastTimestamp.Time
+	case !ev.EventTime.IsZero():
+		return ev.EventTime.Time
+	case !ev.FirstTimestamp.IsZero():
+		return ev.FirstTimestamp.Time
+	default:
+		return ev.CreationTimestamp.Time
+	}
+}
+
+// Return channel that emits records in order in batches and then closes
+func recordsChan(ctx context.Context, records []LogRecord) <-chan []LogRecord {
+	ch := make(chan []LogRecord)
+	go func() {
+		defer close(ch)
+		for batch := range slices.Chunk(records, maxBatchSize) {
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- batch:
+			}
+		}
+	}()
+	return ch
+}
+
+// Start event informers and collect past events. Must be called with the
+// lock held.
+func (s *Stream) startEvents_UNSAFE(ctx context.Context) error {
+	// Get namespaces
+	var namespaces []string
+	if m, ok := s.sw.(eventMatcher); ok {
+		namespaces = m.eventNamespaces()
+	} else {
+		s.sources.Each(func(source LogSource) bool {
+			if !slices.Contains(namespaces, source.Namespace) {
+				namespaces = append(namespaces, source.Namespace)
+			}
+			return false // continue
+		})
+	}
+
+	gvr := corev1.SchemeGroupVersion.WithResource("events")
+	startedAt := time.Now()
+
+	var wg sync.WaitGroup
+	errs := ThreadSafeSlice[error]{}
+	stores := ThreadSafeSlice[cache.Store]{}
+	cleanups := ThreadSafeSlice[func()]{}
+
+	for _, namespace := range namespaces {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			informer, start, err := s.cm.NewInformer(ctx, s.kubeContext, s.bearerToken, namespace, gvr)
+			if err != nil {
+				errs.Add(err)
+				return
+			}
+
+			// Forward new events when following (the handler waits on the stream
+			// lock so only the informer store sync is awaited below)
+			if s.follow {
+				handle, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+					AddFunc: func(obj any) {
+						s.handleEvent(obj, startedAt)
+					},
+					UpdateFunc: func(oldObj any, newObj any) {
+						s.handleEvent(newObj, startedAt)
+					},
+				})
+				if err != nil {
+					errs.Add(err)
+					return
+				}
+
+				cleanups.Add(func() {
+					informer.Informer().RemoveEventHandler(handle)
+				})
+			}
+
+			start()
+
+			if !cache.WaitForCacheSync(s.rootCtx.Done(), informer.Informer().HasSynced) {
+				errs.Add(fmt.Errorf("cache did not sync"))
+				return
+			}
+
+			stores.Add(informer.Informer().GetStore())
+		}()
+	}
+
+	wg.Wait()
+
+	s.eventCleanups = cleanups.ToSlice()
+
+	if errs.Len() > 0 {
+		return fmt.Errorf("encountered errors: %v", errs.ToSlice())
+	}
+
+	// Collect past events
+	pastEvents := []LogRecord{}
+	for _, store := range stores.ToSlice() {
+		for _, obj := range store.List() {
+			ev, ok := obj.(*corev1.Event)
+			if !ok || !s.isRelatedEvent_UNSAFE(ev) {
+				continue
+			}
+
+			r := newEventRecord(ev)
+			if !s.sinceTime.IsZero() && r.Timestamp.Before(s.sinceTime) {
+				continue
+			}
+			if !s.untilTime.IsZero() && r.Timestamp.After(s.untilTime) {
+				continue
+			}
+			pastEvents = append(pastEvents, r)
+		}
+	}
+
+	sort.SliceStable(pastEvents, func(i, j int) bool {
+		return pastEvents[i].Timestamp.Before(pastEvents[j].Timestamp)
+	})
+
+	// Keep only the events that can fall within the limit
+	if n := int(s.maxNum); n >= 0 && len(pastEvents) > n {
+		switch s.mode {
+		case streamModeHead:
+			pastEvents = pastEvents[:n]
+		case streamModeTail:
+			pastEvents = pastEvents[len(pastEvents)-n:]
+		}
+	}
+	s.pastEvents = pastEvents
+
+	// Forward new events to future channel
+	if s.follow {
+		s.futureWG.Add(1)
+		go func() {
+			defer s.futureWG.Done()
+			for {
+				select {
+				case <-s.rootCtx.Done():
+					return
+				case ev := <-s.eventsCh:
+					s.mu.Lock()
+					isRelated := s.isRelatedEvent_UNSAFE(ev)
+					s.mu.Unlock()
+
+					if !isRelated {
+						continue
+					}
+
+					select {
+					case <-s.rootCtx.Done():
+						return
+					case s.futureCh <- []LogRecord{newEventRecord(ev)}:
+					}
+				}
+			}
+		}()
+	}
+
+	return nil
+}
+
+// Handle event informer ADD/UPDATE. Events are handed off to the forwarding
+// goroutine without taking the stream lock so the informer isn't held up.
+func (s *Stream) handleEvent(obj any, startedAt time.Time) {
+	ev, ok := obj.(*corev1.Event)
+	if !ok {
+		return
+	}
+
+	// Skip events that happened before the stream started
+	ts := eventTimestamp(ev)
+	if ts.Before(startedAt) {
+		return
+	}
+
+	if !s.untilTime.IsZero() && ts.After(s.untilTime) {
+		return
+	}
+
+	select {
+	case <-s.rootCtx.Done():
+	case s.eventsCh <- ev:
+	}
+}
+
+// Check if event involves one of the stream's pods or their owners and
+// matches the grep filter
+func (s *Stream) isRelatedEvent_UNSAFE(ev *corev1.Event) bool {
+	if s.grepRegex != nil && !s.grepRegex.MatchString(ev.Message) {
+		return false
+	}
+
+	ref := ev.InvolvedObject
+
+	if m, ok := s.sw.(eventMatcher); ok && m.isRelatedObject(ref) {
+		return true
+	}
+
+	if ref.Kind != "Pod" {
+		return false
+	}
+
+	found := false
+	s.sources.Each(func(source LogSource) bool {
+		found = source.Namespace == ref.Namespace && source.PodName == ref.Name
+		return found // stop when found
+	})
+
+	return found
+}
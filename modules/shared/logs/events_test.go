@@ -0,0 +1,243 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	set "github.com/deckarep/golang-set/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	k8shelpersmock "github.com/kubetail-org/kubetail/modules/shared/k8shelpers/mock"
+)
+
+// Returns new event object
+func newTestEvent(name string, namespace string, kind string, objName string, ts time.Time, message string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      kind,
+			Namespace: namespace,
+			Name:      objName,
+		},
+		Type:          corev1.EventTypeWarning,
+		Reason:        "BackOff",
+		Message:       message,
+		Count:         1,
+		LastTimestamp: metav1.NewTime(ts),
+	}
+}
+
+func TestNewEventRecord(t *testing.T) {
+	ts := time.Date(2025, 3, 13, 11, 46, 1, 0, time.UTC)
+
+	ev := newTestEvent("ev1", "ns1", "Pod", "pod1", ts, " Back-off restarting failed container \n")
+	ev.Source.Host = "node1"
+	ev.Count = 3
+
+	r := newEventRecord(ev)
+	assert.Equal(t, ts, r.Timestamp)
+	assert.Equal(t, "Back-off restarting failed container", r.Message)
+	assert.Equal(t, "ns1", r.Source.Namespace)
+	assert.Equal(t, "pod1", r.Source.PodName)
+	assert.Equal(t, "node1", r.Source.Metadata.Node)
+	assert.Equal(t, &KubeEvent{Type: "Warning", Reason: "BackOff", Kind: "Pod", Name: "pod1", Count: 3}, r.Event)
+
+	t.Run("non-pod object", func(t *testing.T) {
+		r := newEventRecord(newTestEvent("ev2", "ns1", "Deployment", "app", ts, "msg"))
+		assert.Equal(t, "", r.Source.PodName)
+		assert.Equal(t, "Deployment", r.Event.Kind)
+	})
+}
+
+func TestEventTimestamp(t *testing.T) {
+	t1 := time.Date(2025, 3, 13, 11, 46, 1, 0, time.UTC)
+	t2 := time.Date(2025, 3, 13, 11, 46, 2, 0, time.UTC)
+	t3 := time.Date(2025, 3, 13, 11, 46, 3, 0, time.UTC)
+	t4 := time.Date(2025, 3, 13, 11, 46, 4, 0, time.UTC)
+
+	ev := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(t1)},
+		FirstTimestamp: metav1.NewTime(t2),
+		EventTime:      metav1.NewMicroTime(t3),
+		LastTimestamp:  metav1.NewTime(t4),
+	}
+	assert.Equal(t, t4, eventTimestamp(ev))
+
+	ev.LastTimestamp = metav1.Time{}
+	assert.Equal(t, t3, eventTimestamp(ev))
+
+	ev.EventTime = metav1.MicroTime{}
+	assert.Equal(t, t2, eventTimestamp(ev))
+
+	ev.FirstTimestamp = metav1.Time{}
+	assert.Equal(t, t1, eventTimestamp(ev))
+}
+
+func TestStreamWithEvents(t *testing.T) {
+	s1 := LogSource{Namespace: "ns1", PodName: "pod1", ContainerName: "container1"}
+
+	t1 := time.Date(2025, 3, 13, 11, 46, 1, 123456789, time.UTC)
+	t2 := time.Date(2025, 3, 13, 11, 46, 2, 123456789, time.UTC)
+	t3 := time.Date(2025, 3, 13, 11, 46, 3, 123456789, time.UTC)
+	t4 := time.Date(2025, 3, 13, 11, 46, 4, 123456789, time.UTC)
+
+	logs1 := []LogRecord{
+		{Source: s1, Timestamp: t1, Message: "s1-a"},
+		{Source: s1, Timestamp: t3, Message: "s1-b"},
+	}
+
+	events := []runtime.Object{
+		newTestEvent("ev1", "ns1", "Pod", "pod1", t2, "ev-a"),
+		newTestEvent("ev2", "ns1", "Pod", "other", t2, "unrelated"),
+		newTestEvent("ev3", "ns1", "Pod", "pod1", t4, "ev-b"),
+	}
+
+	tests := []struct {
+		name      string
+		setMode   streamMode
+		setMaxNum int64
+		wantLines []string
+	}{
+		{"head mode", streamModeHead, 10, []string{"s1-a", "ev-a", "s1-b", "ev-b"}},
+		{"head mode with maxNum", streamModeHead, 2, []string{"s1-a", "ev-a"}},
+		{"tail mode", streamModeTail, 10, []string{"s1-a", "ev-a", "s1-b", "ev-b"}},
+		{"tail mode with maxNum", streamModeTail, 2, []string{"s1-b", "ev-b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init mock logFetcher
+			m := mockLogFetcher{}
+			m.On("StreamForward", mock.Anything, s1, mock.Anything).
+				Return((<-chan LogRecord)(newForwardChannel(logs1, time.Time{}, time.Time{})), nil)
+			m.On("StreamBackward", mock.Anything, s1, mock.Anything).
+				Return((<-chan LogRecord)(newBackwardChannel(logs1, time.Time{}, time.Time{})), nil)
+
+			// Init mock source watcher
+			sw := mockSourceWatcher{}
+			sw.On("Start", mock.Anything).Return(nil)
+			sw.On("Set").Return(set.NewSet(s1))
+			sw.On("Subscribe", mock.Anything, mock.Anything).Return()
+			sw.On("Unsubscribe", mock.Anything, mock.Anything).Return()
+			sw.On("Close").Return()
+
+			// Init connection manager with fake event informer
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			factory := informers.NewSharedInformerFactory(fake.NewClientset(events...), 0)
+			informer, err := factory.ForResource(corev1.SchemeGroupVersion.WithResource("events"))
+			require.NoError(t, err)
+
+			cm := &k8shelpersmock.MockConnectionManager{}
+			cm.On("GetOrCreateClientset", mock.Anything).Return(&fake.Clientset{}, nil)
+			cm.On("GetDefaultNamespace", mock.Anything).Return("default")
+			cm.On("NewInformer", mock.Anything, mock.Anything, mock.Anything, "ns1", mock.Anything).
+				Return(informer, func() { factory.Start(ctx.Done()) }, nil)
+
+			// Create stream
+			opts := []Option{WithEvents(true)}
+			if tt.setMode == streamModeTail {
+				opts = append(opts, WithTail(tt.setMaxNum))
+			} else {
+				opts = append(opts, WithHead(tt.setMaxNum))
+			}
+
+			stream, err := NewStream(ctx, cm, []string{}, opts...)
+			require.NoError(t, err)
+			defer stream.Close()
+
+			stream.sw = &sw
+			stream.logFetcher = &m
+
+			err = stream.Start(context.Background())
+			require.NoError(t, err)
+
+			// Past events are capped to the limit
+			assert.LessOrEqual(t, len(stream.pastEvents), int(tt.setMaxNum))
+
+			// Get log records
+			messages := []string{}
+			for r := range stream.Records() {
+				messages = append(messages, r.Message)
+				if r.Message == "ev-a" {
+					require.NotNil(t, r.Event)
+					assert.Equal(t, "BackOff", r.Event.Reason)
+				} else if r.Message == "s1-a" {
+					assert.Nil(t, r.Event)
+				}
+			}
+			assert.Equal(t, tt.wantLines, messages)
+			require.NoError(t, stream.Err())
+		})
+	}
+}
+
+func TestHandleEventDoesNotLock(t *testing.T) {
+	stream := &Stream{
+		rootCtx:  t.Context(),
+		eventsCh: make(chan *corev1.Event, eventsChSize),
+	}
+
+	startedAt := time.Now()
+	ev := newTestEvent("ev1", "ns1", "Pod", "pod1", startedAt.Add(time.Second), "ev-a")
+
+	// Informer handlers don't wait on the stream lock
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		stream.handleEvent(ev, startedAt)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handleEvent blocked on stream lock")
+	}
+
+	assert.Equal(t, ev, <-stream.eventsCh)
+}
+
+func TestWorkloadIndexIsOwnedBy(t *testing.T) {
+	wi := newWorkloadIndex()
+
+	deploymentID := types.UID("deployment")
+	replicaSetID := types.UID("replicaset")
+	podID := types.UID("pod")
+
+	wi.ownershipMap.Add(deploymentID, replicaSetID)
+	wi.ownershipMap.Add(replicaSetID, podID)
+
+	assert.True(t, wi.IsOwnedBy(deploymentID, deploymentID))
+	assert.True(t, wi.IsOwnedBy(deploymentID, replicaSetID))
+	assert.True(t, wi.IsOwnedBy(deploymentID, podID))
+	assert.True(t, wi.IsOwnedBy(replicaSetID, podID))
+	assert.False(t, wi.IsOwnedBy(podID, deploymentID))
+	assert.False(t, wi.IsOwnedBy(deploymentID, types.UID("other")))
+}
@@ -0,0 +1,355 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kubetail-org/kubetail/modules/shared/clusteragentpb"
+)
+
+// FieldOperator enum type
+type FieldOperator string
+
+const (
+	FieldOperatorEquals    FieldOperator = "EQUALS"
+	FieldOperatorNotEquals FieldOperator = "NOT_EQUALS"
+	FieldOperatorContains  FieldOperator = "CONTAINS"
+	FieldOperatorMatches   FieldOperator = "MATCHES"
+	FieldOperatorExists    FieldOperator = "EXISTS"
+)
+
+// FieldPredicate represents a predicate on a field of a JSON or logfmt line
+type FieldPredicate struct {
+	Field string
+	Op    FieldOperator
+	Value string
+}
+
+// Filter represents structured filters evaluated against each log line. When
+// using the cluster agent the filter is evaluated on the node.
+type Filter struct {
+	Fields  []FieldPredicate
+	Exclude []string
+	Levels  []string
+
+	fieldRegexes   []*regexp.Regexp
+	excludeRegexes []*regexp.Regexp
+	levelSet       map[string]struct{}
+}
+
+// Field names checked (in order) for a line's level
+var levelFieldNames = []string{"level", "lvl", "severity", "loglevel"}
+
+// NewFilter validates the arguments and returns a new Filter instance. If all
+// the arguments are empty it returns nil.
+func NewFilter(fields []FieldPredicate, exclude []string, levels []string) (*Filter, error) {
+	if len(fields) == 0 && len(exclude) == 0 && len(levels) == 0 {
+		return nil, nil
+	}
+
+	f := &Filter{
+		Fields:         fields,
+		Exclude:        exclude,
+		Levels:         levels,
+		fieldRegexes:   make([]*regexp.Regexp, len(fields)),
+		excludeRegexes: make([]*regexp.Regexp, len(exclude)),
+		levelSet:       make(map[string]struct{}, len(levels)),
+	}
+
+	for i, pred := range fields {
+		if pred.Field == "" {
+			return nil, fmt.Errorf("field predicate is missing field name")
+		}
+
+		switch pred.Op {
+		case FieldOperatorEquals, FieldOperatorNotEquals, FieldOperatorContains, FieldOperatorExists:
+		case FieldOperatorMatches:
+			regex, err := regexp.Compile(pred.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern for field %s: %w", pred.Field, err)
+			}
+			f.fieldRegexes[i] = regex
+		default:
+			return nil, fmt.Errorf("invalid field operator: %s", pred.Op)
+		}
+	}
+
+	for i, pattern := range exclude {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern: %w", err)
+		}
+		f.excludeRegexes[i] = regex
+	}
+
+	for _, level := range levels {
+		f.levelSet[normalizeLevel(level)] = struct{}{}
+	}
+
+	return f, nil
+}
+
+// ParseFieldPredicate parses expressions of the form `field=value`,
+// `field!=value`, `field*=value` (contains), `field=~regex` and `field`
+// (exists)
+func ParseFieldPredicate(expr string) (FieldPredicate, error) {
+	expr = strings.TrimSpace(expr)
+
+	i := strings.IndexAny(expr, "=!*")
+	if i < 0 {
+		if expr == "" {
+			return FieldPredicate{}, fmt.Errorf("empty field predicate")
+		}
+		return FieldPredicate{Field: expr, Op: FieldOperatorExists}, nil
+	}
+
+	pred := FieldPredicate{Field: expr[:i]}
+	if pred.Field == "" {
+		return FieldPredicate{}, fmt.Errorf("field predicate is missing field name: %s", expr)
+	}
+
+	rest := expr[i:]
+	switch {
+	case strings.HasPrefix(rest, "!="):
+		pred.Op, pred.Value = FieldOperatorNotEquals, rest[2:]
+	case strings.HasPrefix(rest, "*="):
+		pred.Op, pred.Value = FieldOperatorContains, rest[2:]
+	case strings.HasPrefix(rest, "=~"):
+		pred.Op, pred.Value = FieldOperatorMatches, rest[2:]
+	case strings.HasPrefix(rest, "="):
+		pred.Op, pred.Value = FieldOperatorEquals, rest[1:]
+	default:
+		return FieldPredicate{}, fmt.Errorf("invalid field predicate: %s", expr)
+	}
+
+	return pred, nil
+}
+
+// Match returns true if the message passes the filter
+func (f *Filter) Match(message string) bool {
+	if f == nil {
+		return true
+	}
+
+	for _, regex := range f.excludeRegexes {
+		if regex.MatchString(message) {
+			return false
+		}
+	}
+
+	if len(f.Fields) == 0 && len(f.levelSet) == 0 {
+		return true
+	}
+
+	fields := parseFields(message)
+
+	if len(f.levelSet) > 0 {
+		level, ok := lineLevel(fields)
+		if !ok {
+			return false
+		}
+		if _, exists := f.levelSet[level]; !exists {
+			return false
+		}
+	}
+
+	for i, pred := range f.Fields {
+		value, ok := lookupField(fields, pred.Field)
+		switch pred.Op {
+		case FieldOperatorEquals:
+			ok = ok && value == pred.Value
+		case FieldOperatorNotEquals:
+			ok = !ok || value != pred.Value
+		case FieldOperatorContains:
+			ok = ok && strings.Contains(value, pred.Value)
+		case FieldOperatorMatches:
+			ok = ok && f.fieldRegexes[i].MatchString(value)
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Return protobuf representation of filter
+func (f *Filter) toProto() *clusteragentpb.LogRecordsFilter {
+	if f == nil {
+		return nil
+	}
+
+	out := &clusteragentpb.LogRecordsFilter{
+		Exclude: f.Exclude,
+		Levels:  f.Levels,
+	}
+
+	for _, pred := range f.Fields {
+		out.Fields = append(out.Fields, &clusteragentpb.FieldPredicate{
+			Field: pred.Field,
+			Op:    clusteragentpb.FieldOperator(clusteragentpb.FieldOperator_value[string(pred.Op)]),
+			Value: pred.Value,
+		})
+	}
+
+	return out
+}
+
+// Parse fields of a JSON or logfmt line. Returns nil for unstructured lines.
+func parseFields(message string) map[string]any {
+	trimmed := strings.TrimSpace(message)
+
+	if strings.HasPrefix(trimmed, "{") {
+		dec := json.NewDecoder(strings.NewReader(trimmed))
+		dec.UseNumber()
+
+		var fields map[string]any
+		if err := dec.Decode(&fields); err == nil {
+			return fields
+		}
+	}
+
+	return parseLogfmt(trimmed)
+}
+
+// Parse `key=value` pairs from a logfmt line. Bare keys are ignored.
+func parseLogfmt(line string) map[string]any {
+	var fields map[string]any
+
+	i := 0
+	for i < len(line) {
+		// Skip whitespace
+		for i < len(line) && line[i] == ' ' {
+			i++
+		}
+
+		// Read key
+		start := i
+		for i < len(line) && line[i] != ' ' && line[i] != '=' {
+			i++
+		}
+		key := line[start:i]
+
+		if i >= len(line) || line[i] != '=' {
+			continue
+		}
+		i++
+
+		// Read value
+		var value string
+		if i < len(line) && line[i] == '"' {
+			var buf bytes.Buffer
+			i++
+			for i < len(line) && line[i] != '"' {
+				if line[i] == '\\' && i+1 < len(line) {
+					i++
+				}
+				buf.WriteByte(line[i])
+				i++
+			}
+			i++
+			value = buf.String()
+		} else {
+			start = i
+			for i < len(line) && line[i] != ' ' {
+				i++
+			}
+			value = line[start:i]
+		}
+
+		if key == "" || strings.Contains(key, `"`) {
+			continue
+		}
+
+		if fields == nil {
+			fields = map[string]any{}
+		}
+		if _, exists := fields[key]; !exists {
+			fields[key] = value
+		}
+	}
+
+	return fields
+}
+
+// Return string representation of field. Dots in the name descend into
+// nested objects unless the name itself is present.
+func lookupField(fields map[string]any, name string) (string, bool) {
+	if fields == nil {
+		return "", false
+	}
+
+	if v, exists := fields[name]; exists {
+		return fieldString(v), true
+	}
+
+	var current any = fields
+	for _, part := range strings.Split(name, ".") {
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		if current, ok = obj[part]; !ok {
+			return "", false
+		}
+	}
+
+	return fieldString(current), true
+}
+
+// Return string representation of field value
+func fieldString(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case json.Number:
+		return t.String()
+	case nil:
+		return "null"
+	default:
+		b, err := json.Marshal(t)
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+}
+
+// Return normalized level of a parsed line
+func lineLevel(fields map[string]any) (string, bool) {
+	for _, name := range levelFieldNames {
+		if v, ok := lookupField(fields, name); ok {
+			return normalizeLevel(v), true
+		}
+	}
+	return "", false
+}
+
+// Return lower-case level with common aliases collapsed
+func normalizeLevel(level string) string {
+	level = strings.ToLower(strings.TrimSpace(level))
+	switch level {
+	case "warning":
+		return "warn"
+	case "err":
+		return "error"
+	}
+	return level
+}
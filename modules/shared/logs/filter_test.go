@@ -0,0 +1,145 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubetail-org/kubetail/modules/shared/clusteragentpb"
+)
+
+func TestParseFieldPredicate(t *testing.T) {
+	tests := []struct {
+		name     string
+		setExpr  string
+		wantPred FieldPredicate
+		wantErr  bool
+	}{
+		{"equals", "level=error", FieldPredicate{"level", FieldOperatorEquals, "error"}, false},
+		{"not equals", "user.id!=42", FieldPredicate{"user.id", FieldOperatorNotEquals, "42"}, false},
+		{"contains", "msg*=timeout", FieldPredicate{"msg", FieldOperatorContains, "timeout"}, false},
+		{"matches", "path=~^/api/", FieldPredicate{"path", FieldOperatorMatches, "^/api/"}, false},
+		{"exists", "trace_id", FieldPredicate{"trace_id", FieldOperatorExists, ""}, false},
+		{"empty value", "msg=", FieldPredicate{"msg", FieldOperatorEquals, ""}, false},
+		{"missing field", "=error", FieldPredicate{}, true},
+		{"invalid operator", "level!error", FieldPredicate{}, true},
+		{"empty", " ", FieldPredicate{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pred, err := ParseFieldPredicate(tt.setExpr)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPred, pred)
+		})
+	}
+}
+
+func TestNewFilter(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		f, err := NewFilter(nil, nil, nil)
+		require.NoError(t, err)
+		assert.Nil(t, f)
+		assert.True(t, f.Match("anything"))
+	})
+
+	t.Run("invalid field pattern", func(t *testing.T) {
+		_, err := NewFilter([]FieldPredicate{{"msg", FieldOperatorMatches, "("}}, nil, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid exclude pattern", func(t *testing.T) {
+		_, err := NewFilter(nil, []string{"("}, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid operator", func(t *testing.T) {
+		_, err := NewFilter([]FieldPredicate{{"msg", "LIKE", "x"}}, nil, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestFilterMatch(t *testing.T) {
+	jsonLine := `{"level":"warning","msg":"request timeout","status":504,"user":{"id":42},"http.method":"GET"}`
+	logfmtLine := `ts=2025-01-01T00:00:00Z level=ERROR msg="connection reset by peer" status=500`
+	plainLine := "listening on :8080"
+
+	tests := []struct {
+		name       string
+		setFields  []FieldPredicate
+		setExclude []string
+		setLevels  []string
+		setMessage string
+		want       bool
+	}{
+		{"json equals", []FieldPredicate{{"status", FieldOperatorEquals, "504"}}, nil, nil, jsonLine, true},
+		{"json equals mismatch", []FieldPredicate{{"status", FieldOperatorEquals, "500"}}, nil, nil, jsonLine, false},
+		{"json nested", []FieldPredicate{{"user.id", FieldOperatorEquals, "42"}}, nil, nil, jsonLine, true},
+		{"json dotted key", []FieldPredicate{{"http.method", FieldOperatorEquals, "GET"}}, nil, nil, jsonLine, true},
+		{"json contains", []FieldPredicate{{"msg", FieldOperatorContains, "timeout"}}, nil, nil, jsonLine, true},
+		{"json matches", []FieldPredicate{{"msg", FieldOperatorMatches, "^request"}}, nil, nil, jsonLine, true},
+		{"json object value", []FieldPredicate{{"user", FieldOperatorEquals, `{"id":42}`}}, nil, nil, jsonLine, true},
+		{"json exists", []FieldPredicate{{"user", FieldOperatorExists, ""}}, nil, nil, jsonLine, true},
+		{"json missing", []FieldPredicate{{"trace_id", FieldOperatorExists, ""}}, nil, nil, jsonLine, false},
+		{"json all predicates", []FieldPredicate{{"status", FieldOperatorEquals, "504"}, {"msg", FieldOperatorContains, "reset"}}, nil, nil, jsonLine, false},
+		{"logfmt quoted", []FieldPredicate{{"msg", FieldOperatorEquals, "connection reset by peer"}}, nil, nil, logfmtLine, true},
+		{"logfmt unquoted", []FieldPredicate{{"status", FieldOperatorEquals, "500"}}, nil, nil, logfmtLine, true},
+		{"not equals", []FieldPredicate{{"status", FieldOperatorNotEquals, "500"}}, nil, nil, logfmtLine, false},
+		{"not equals missing", []FieldPredicate{{"status", FieldOperatorNotEquals, "500"}}, nil, nil, plainLine, true},
+		{"plain line", []FieldPredicate{{"msg", FieldOperatorExists, ""}}, nil, nil, plainLine, false},
+		{"level alias", nil, nil, []string{"warn"}, jsonLine, true},
+		{"level case", nil, nil, []string{"error", "fatal"}, logfmtLine, true},
+		{"level mismatch", nil, nil, []string{"error"}, jsonLine, false},
+		{"level missing", nil, nil, []string{"info"}, plainLine, false},
+		{"exclude", nil, []string{"timeout"}, nil, jsonLine, false},
+		{"exclude mismatch", nil, []string{"healthz"}, nil, plainLine, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFilter(tt.setFields, tt.setExclude, tt.setLevels)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, f.Match(tt.setMessage))
+		})
+	}
+}
+
+func TestFilterToProto(t *testing.T) {
+	var empty *Filter
+	assert.Nil(t, empty.toProto())
+
+	f, err := NewFilter(
+		[]FieldPredicate{{"level", FieldOperatorEquals, "error"}, {"msg", FieldOperatorMatches, "time.*out"}},
+		[]string{"healthz"},
+		[]string{"error"},
+	)
+	require.NoError(t, err)
+
+	pb := f.toProto()
+	require.Len(t, pb.Fields, 2)
+	assert.Equal(t, "level", pb.Fields[0].Field)
+	assert.Equal(t, clusteragentpb.FieldOperator_EQUALS, pb.Fields[0].Op)
+	assert.Equal(t, clusteragentpb.FieldOperator_MATCHES, pb.Fields[1].Op)
+	assert.Equal(t, "time.*out", pb.Fields[1].Value)
+	assert.Equal(t, []string{"healthz"}, pb.Exclude)
+	assert.Equal(t, []string{"error"}, pb.Levels)
+}
@@ -17,7 +17,6 @@ package logs
 import (
 	"bufio"
 	"bytes"
-	"container/heap"
 	"context"
 	"errors"
 	"fmt"
@@ -55,6 +54,9 @@ const (
 	WorkloadTypePod
 	WorkloadTypeReplicaSet
 	WorkloadTypeStatefulSet
+	WorkloadTypeEndpointSlice // used internally to resolve serving pods
+	WorkloadTypeIngress
+	WorkloadTypeService
 )
 
 // String method for readable output
@@ -66,12 +68,18 @@ func (w WorkloadType) String() string {
 		return "DaemonSet"
 	case WorkloadTypeDeployment:
 		return "Deployment"
+	case WorkloadTypeEndpointSlice:
+		return "EndpointSlice"
+	case WorkloadTypeIngress:
+		return "Ingress"
 	case WorkloadTypeJob:
 		return "Job"
 	case WorkloadTypePod:
 		return "Pod"
 	case WorkloadTypeReplicaSet:
 		return "ReplicaSet"
+	case WorkloadTypeService:
+		return "Service"
 	case WorkloadTypeStatefulSet:
 		return "StatefulSet"
 	default:
@@ -88,12 +96,18 @@ func (w WorkloadType) GroupResource() (string, string, error) {
 		return "apps", "daemonsets", nil
 	case WorkloadTypeDeployment:
 		return "apps", "deployments", nil
+	case WorkloadTypeEndpointSlice:
+		return "discovery.k8s.io", "endpointslices", nil
+	case WorkloadTypeIngress:
+		return "networking.k8s.io", "ingresses", nil
 	case WorkloadTypeJob:
 		return "batch", "jobs", nil
 	case WorkloadTypePod:
 		return "", "pods", nil
 	case WorkloadTypeReplicaSet:
 		return "apps", "replicasets", nil
+	case WorkloadTypeService:
+		return "", "services", nil
 	case WorkloadTypeStatefulSet:
 		return "apps", "statefulsets", nil
 	default:
@@ -110,12 +124,18 @@ func (w WorkloadType) GVR() schema.GroupVersionResource {
 		return schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}
 	case WorkloadTypeDeployment:
 		return schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	case WorkloadTypeEndpointSlice:
+		return schema.GroupVersionResource{Group: "discovery.k8s.io", Version: "v1", Resource: "endpointslices"}
+	case WorkloadTypeIngress:
+		return schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}
 	case WorkloadTypeJob:
 		return schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
 	case WorkloadTypePod:
 		return schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
 	case WorkloadTypeReplicaSet:
 		return schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}
+	case WorkloadTypeService:
+		return schema.GroupVersionResource{Group: "", Version: "v1", Resource: "services"}
 	case WorkloadTypeStatefulSet:
 		return schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}
 	default:
@@ -137,12 +157,16 @@ func parseWorkloadType(workloadStr string) WorkloadType {
 		return WorkloadTypeDaemonSet
 	case "deployments", "deployment", "deploy":
 		return WorkloadTypeDeployment
+	case "ingresses", "ingress", "ing":
+		return WorkloadTypeIngress
 	case "jobs", "job":
 		return WorkloadTypeJob
 	case "pods", "pod", "po":
 		return WorkloadTypePod
 	case "replicasets", "replicaset", "rs":
 		return WorkloadTypeReplicaSet
+	case "services", "service", "svc":
+		return WorkloadTypeService
 	case "statefulsets", "statefulset", "sts":
 		return WorkloadTypeStatefulSet
 	default:
@@ -213,57 +237,6 @@ func extractTimestampFromBytes(line []byte) (int, time.Time, error) {
 	return pos, ts, nil
 }
 
-// mergeLogStreams merges multiple ordered log streams into a single channel
-// that yields them in ascending order by timestamp.
-func mergeLogStreams(ctx context.Context, reverse bool, streams ...<-chan LogRecord) <-chan LogRecord {
-	outCh := make(chan LogRecord)
-
-	// Run in goroutine
-	go func() {
-		defer close(outCh)
-
-		// Build a min-heap of the first item from each stream.
-		pq := newPriorityQueue(reverse)
-		heap.Init(pq)
-
-		// Initialize the heap with the first entry from each stream
-		for _, ch := range streams {
-			// Read one entry if available
-			entry, ok := <-ch
-			if ok {
-				heap.Push(pq, recordWithSource{
-					record: entry,
-					srcCh:  ch,
-				})
-			}
-		}
-
-		// Repeatedly pop the earliest entry and replace it with
-		// the next from the same source channel.
-		for pq.Len() > 0 {
-			// Pop the earliest entry
-			earliest := heap.Pop(pq).(recordWithSource)
-
-			select {
-			case <-ctx.Done():
-				return
-			case outCh <- earliest.record:
-			}
-
-			// Read the next entry from the same source channel
-			entry, ok := <-earliest.srcCh
-			if ok {
-				heap.Push(pq, recordWithSource{
-					record: entry,
-					srcCh:  earliest.srcCh,
-				})
-			}
-		}
-	}()
-
-	return outCh
-}
-
 // Get first timestamp from a log
 func getFirstTimestamp(ctx context.Context, clientset kubernetes.Interface, source LogSource, sinceTime time.Time) (time.Time, error) {
 	var zero time.Time
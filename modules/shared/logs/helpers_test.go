@@ -15,7 +15,6 @@
 package logs
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -221,238 +220,6 @@ func TestExtractTimestampFromBytes(t *testing.T) {
 	})
 }
 
-func TestMergeLogStreams(t *testing.T) {
-	now := time.Now()
-
-	tests := []struct {
-		name     string
-		streams  [][]LogRecord
-		expected []LogRecord
-	}{
-		{
-			name: "merge two ordered streams",
-			streams: [][]LogRecord{
-				{
-					{Timestamp: now, Message: "stream1-1"},
-					{Timestamp: now.Add(2 * time.Second), Message: "stream1-2"},
-				},
-				{
-					{Timestamp: now.Add(time.Second), Message: "stream2-1"},
-					{Timestamp: now.Add(3 * time.Second), Message: "stream2-2"},
-				},
-			},
-			expected: []LogRecord{
-				{Timestamp: now, Message: "stream1-1"},
-				{Timestamp: now.Add(time.Second), Message: "stream2-1"},
-				{Timestamp: now.Add(2 * time.Second), Message: "stream1-2"},
-				{Timestamp: now.Add(3 * time.Second), Message: "stream2-2"},
-			},
-		},
-		{
-			name: "merge empty streams",
-			streams: [][]LogRecord{
-				{},
-				{},
-			},
-			expected: []LogRecord{},
-		},
-		{
-			name: "merge single stream",
-			streams: [][]LogRecord{
-				{
-					{Timestamp: now, Message: "msg1"},
-					{Timestamp: now.Add(time.Second), Message: "msg2"},
-				},
-			},
-			expected: []LogRecord{
-				{Timestamp: now, Message: "msg1"},
-				{Timestamp: now.Add(time.Second), Message: "msg2"},
-			},
-		},
-		{
-			name: "merge streams with same timestamps",
-			streams: [][]LogRecord{
-				{
-					{Timestamp: now, Message: "stream1"},
-				},
-				{
-					{Timestamp: now, Message: "stream2"},
-				},
-			},
-			expected: []LogRecord{
-				{Timestamp: now, Message: "stream1"},
-				{Timestamp: now, Message: "stream2"},
-			},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// Create channels and feed test data
-			streams := make([]<-chan LogRecord, len(tt.streams))
-			for i, records := range tt.streams {
-				ch := make(chan LogRecord, len(records))
-				for _, record := range records {
-					ch <- record
-				}
-				close(ch)
-				streams[i] = ch
-			}
-
-			// Run mergeLogStreams
-			ctx := context.Background()
-			merged := mergeLogStreams(ctx, false, streams...)
-
-			// Collect results
-			var results []LogRecord
-			for record := range merged {
-				results = append(results, record)
-			}
-
-			// Verify results
-			assert.Equal(t, len(tt.expected), len(results), "number of records should match")
-			for i := range results {
-				assert.Equal(t, tt.expected[i].Timestamp, results[i].Timestamp, "timestamps should match at index %d", i)
-				assert.Equal(t, tt.expected[i].Message, results[i].Message, "messages should match at index %d", i)
-			}
-		})
-	}
-}
-
-func TestMergeLogStreamsReverse(t *testing.T) {
-	baseTS := time.Now()
-
-	tests := []struct {
-		name     string
-		streams  [][]LogRecord
-		expected []LogRecord
-	}{
-		{
-			name: "merge two reverse ordered streams",
-			streams: [][]LogRecord{
-				{
-					{Timestamp: baseTS.Add(2 * time.Second), Message: "stream1-2"},
-					{Timestamp: baseTS, Message: "stream1-1"},
-				},
-				{
-					{Timestamp: baseTS.Add(3 * time.Second), Message: "stream2-2"},
-					{Timestamp: baseTS.Add(time.Second), Message: "stream2-1"},
-				},
-			},
-			expected: []LogRecord{
-				{Timestamp: baseTS.Add(3 * time.Second), Message: "stream2-2"},
-				{Timestamp: baseTS.Add(2 * time.Second), Message: "stream1-2"},
-				{Timestamp: baseTS.Add(time.Second), Message: "stream2-1"},
-				{Timestamp: baseTS, Message: "stream1-1"},
-			},
-		},
-		{
-			name: "merge empty streams",
-			streams: [][]LogRecord{
-				{},
-				{},
-			},
-			expected: []LogRecord{},
-		},
-		{
-			name: "merge single stream",
-			streams: [][]LogRecord{
-				{
-					{Timestamp: baseTS.Add(time.Second), Message: "msg2"},
-					{Timestamp: baseTS, Message: "msg1"},
-				},
-			},
-			expected: []LogRecord{
-				{Timestamp: baseTS.Add(time.Second), Message: "msg2"},
-				{Timestamp: baseTS, Message: "msg1"},
-			},
-		},
-		{
-			name: "merge streams with same timestamps",
-			streams: [][]LogRecord{
-				{
-					{Timestamp: baseTS, Message: "stream1"},
-				},
-				{
-					{Timestamp: baseTS, Message: "stream2"},
-				},
-			},
-			expected: []LogRecord{
-				{Timestamp: baseTS, Message: "stream1"},
-				{Timestamp: baseTS, Message: "stream2"},
-			},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// Create channels and feed test data
-			streams := make([]<-chan LogRecord, len(tt.streams))
-			for i, records := range tt.streams {
-				ch := make(chan LogRecord, len(records))
-				for _, record := range records {
-					ch <- record
-				}
-				close(ch)
-				streams[i] = ch
-			}
-
-			// Run mergeLogStreams in reverse mode
-			ctx := context.Background()
-			merged := mergeLogStreams(ctx, true, streams...)
-
-			// Collect results
-			var results []LogRecord
-			for record := range merged {
-				results = append(results, record)
-			}
-
-			// Verify results
-			assert.Equal(t, len(tt.expected), len(results), "number of records should match")
-			for i := range results {
-				assert.Equal(t, tt.expected[i].Timestamp, results[i].Timestamp, "timestamps should match at index %d", i)
-				assert.Equal(t, tt.expected[i].Message, results[i].Message, "messages should match at index %d", i)
-			}
-		})
-	}
-}
-
-func TestMergeLogStreamsContextCancellation(t *testing.T) {
-	// Create test data
-	now := time.Now()
-	records := []LogRecord{
-		{Timestamp: now, Message: "msg1"},
-		{Timestamp: now.Add(time.Second), Message: "msg2"},
-		{Timestamp: now.Add(2 * time.Second), Message: "msg3"},
-	}
-
-	// Create channel with test data
-	ch := make(chan LogRecord, len(records))
-	for _, record := range records {
-		ch <- record
-	}
-
-	// Create context with cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-
-	// Run mergeLogStreams
-	merged := mergeLogStreams(ctx, false, ch)
-
-	// Read first record
-	record := <-merged
-
-	// Verify first record
-	assert.Equal(t, records[0].Timestamp, record.Timestamp)
-	assert.Equal(t, records[0].Message, record.Message)
-
-	// Cancel context
-	cancel()
-
-	// Verify channel is closed
-	_, ok := <-merged
-	assert.False(t, ok, "channel should be closed after context cancellation")
-}
-
 func TestParseWorkloadType(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -463,18 +230,22 @@ func TestParseWorkloadType(t *testing.T) {
 		{name: "cronjob", input: "cronjob", expected: WorkloadTypeCronJob},
 		{name: "daemonset", input: "daemonset", expected: WorkloadTypeDaemonSet},
 		{name: "deployment", input: "deployment", expected: WorkloadTypeDeployment},
+		{name: "ingress", input: "ingress", expected: WorkloadTypeIngress},
 		{name: "job", input: "job", expected: WorkloadTypeJob},
 		{name: "pod", input: "pod", expected: WorkloadTypePod},
 		{name: "replicaset", input: "replicaset", expected: WorkloadTypeReplicaSet},
+		{name: "service", input: "service", expected: WorkloadTypeService},
 		{name: "statefulset", input: "statefulset", expected: WorkloadTypeStatefulSet},
 
 		// Test with trailing 's'
 		{name: "cronjobs", input: "cronjobs", expected: WorkloadTypeCronJob},
 		{name: "daemonsets", input: "daemonsets", expected: WorkloadTypeDaemonSet},
 		{name: "deployments", input: "deployments", expected: WorkloadTypeDeployment},
+		{name: "ingresses", input: "ingresses", expected: WorkloadTypeIngress},
 		{name: "jobs", input: "jobs", expected: WorkloadTypeJob},
 		{name: "pods", input: "pods", expected: WorkloadTypePod},
 		{name: "replicasets", input: "replicasets", expected: WorkloadTypeReplicaSet},
+		{name: "services", input: "services", expected: WorkloadTypeService},
 		{name: "statefulsets", input: "statefulsets", expected: WorkloadTypeStatefulSet},
 
 		// Test with mixed case
@@ -499,8 +270,10 @@ func TestParseWorkloadType(t *testing.T) {
 		{name: "cj", input: "cj", expected: WorkloadTypeCronJob},
 		{name: "ds", input: "ds", expected: WorkloadTypeDaemonSet},
 		{name: "deploy", input: "deploy", expected: WorkloadTypeDeployment},
+		{name: "ing", input: "ing", expected: WorkloadTypeIngress},
 		{name: "po", input: "po", expected: WorkloadTypePod},
 		{name: "rs", input: "rs", expected: WorkloadTypeReplicaSet},
+		{name: "svc", input: "svc", expected: WorkloadTypeService},
 		{name: "sts", input: "sts", expected: WorkloadTypeStatefulSet},
 
 		// Test unknown workload types
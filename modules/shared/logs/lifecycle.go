@@ -0,0 +1,190 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// LifecycleType enum type
+type LifecycleType string
+
+const (
+	LifecycleTypeSourceAdded        LifecycleType = "SOURCE_ADDED"
+	LifecycleTypeSourceRemoved      LifecycleType = "SOURCE_REMOVED"
+	LifecycleTypeContainerRestarted LifecycleType = "CONTAINER_RESTARTED"
+	LifecycleTypeSourceSkipped      LifecycleType = "SOURCE_SKIPPED"
+)
+
+// Lifecycle holds the details of synthetic records that mark changes to a
+// stream's sources
+type Lifecycle struct {
+	Type         LifecycleType
+	RestartCount int32
+	ExitCode     int32  // set if type is CONTAINER_RESTARTED
+	Reason       string // set if type is CONTAINER_RESTARTED
+}
+
+// Implemented by source watchers that can look up the current status of a
+// source's container
+type containerStatusGetter interface {
+	containerStatus(namespace string, podName string, containerName string) *corev1.ContainerStatus
+}
+
+// Return lifecycle record for a source that was added to a running stream
+func newSourceAddedRecord(source LogSource, status *corev1.ContainerStatus) LogRecord {
+	lc := &Lifecycle{Type: LifecycleTypeSourceAdded}
+
+	// New pods start with zero restarts so a restart count with a terminated
+	// last state means the container was restarted in place
+	if status != nil && status.ContainerID == source.ContainerID {
+		lc.RestartCount = status.RestartCount
+		if term := status.LastTerminationState.Terminated; status.RestartCount > 0 && term != nil {
+			lc.Type = LifecycleTypeContainerRestarted
+			lc.ExitCode = term.ExitCode
+			lc.Reason = term.Reason
+		}
+	}
+
+	return LogRecord{
+		Timestamp: time.Now(),
+		Message:   lifecycleMessage(source, lc),
+		Source:    source,
+		Lifecycle: lc,
+	}
+}
+
+// Return lifecycle record for a source that was removed from a running stream
+func newSourceRemovedRecord(source LogSource) LogRecord {
+	lc := &Lifecycle{Type: LifecycleTypeSourceRemoved}
+	return LogRecord{
+		Timestamp: time.Now(),
+		Message:   lifecycleMessage(source, lc),
+		Source:    source,
+		Lifecycle: lc,
+	}
+}
+
+// Return lifecycle record for a source that wasn't added to a running stream
+// because it already follows `maxSources` sources
+func newSourceSkippedRecord(source LogSource, maxSources int) LogRecord {
+	lc := &Lifecycle{Type: LifecycleTypeSourceSkipped}
+	name := fmt.Sprintf("%s/%s/%s", source.Namespace, source.PodName, source.ContainerName)
+	return LogRecord{
+		Timestamp: time.Now(),
+		Message:   fmt.Sprintf("Source %s skipped (limit of %d sources reached)", name, maxSources),
+		Source:    source,
+		Lifecycle: lc,
+	}
+}
+
+// Return human readable description of lifecycle change
+func lifecycleMessage(source LogSource, lc *Lifecycle) string {
+	name := fmt.Sprintf("%s/%s/%s", source.Namespace, source.PodName, source.ContainerName)
+
+	switch lc.Type {
+	case LifecycleTypeContainerRestarted:
+		msg := fmt.Sprintf("Container %s restarted (restart count %d, exit code %d", name, lc.RestartCount, lc.ExitCode)
+		if lc.Reason != "" {
+			msg += ", reason " + lc.Reason
+		}
+		return msg + ")"
+	case LifecycleTypeSourceRemoved:
+		return fmt.Sprintf("Source %s removed", name)
+	default:
+		return fmt.Sprintf("Source %s added", name)
+	}
+}
+
+// Check if a removed source was replaced by a new instance of the same
+// container (i.e. a restart, which is reported when the new instance is added)
+func (s *Stream) isReplacedSource(source LogSource) bool {
+	getter, ok := s.sw.(containerStatusGetter)
+	if !ok {
+		return false
+	}
+
+	status := getter.containerStatus(source.Namespace, source.PodName, source.ContainerName)
+	return status != nil && status.ContainerID != "" && status.ContainerID != source.ContainerID
+}
+
+// Return current status of source container if available
+func (s *Stream) sourceContainerStatus(source LogSource) *corev1.ContainerStatus {
+	getter, ok := s.sw.(containerStatusGetter)
+	if !ok {
+		return nil
+	}
+	return getter.containerStatus(source.Namespace, source.PodName, source.ContainerName)
+}
+
+// Queued lifecycle record
+type lifecycleItem struct {
+	record LogRecord
+	sentCh chan struct{}
+}
+
+// Queue lifecycle record for the lifecycle forwarder and return a channel
+// that's closed once the record has been sent. Never blocks so source watcher
+// event handlers aren't held up by slow consumers. Records are emitted in the
+// order they were queued.
+func (s *Stream) queueLifecycleRecord(record LogRecord) <-chan struct{} {
+	item := lifecycleItem{record: record, sentCh: make(chan struct{})}
+
+	s.lifecycleMu.Lock()
+	s.lifecycleQueue = append(s.lifecycleQueue, item)
+	s.lifecycleMu.Unlock()
+
+	// Wake up forwarder
+	select {
+	case s.lifecycleWakeCh <- struct{}{}:
+	default:
+	}
+
+	return item.sentCh
+}
+
+// Send queued lifecycle records to future channel
+func (s *Stream) runLifecycleForwarder() {
+	defer s.futureWG.Done()
+
+	for {
+		select {
+		case <-s.rootCtx.Done():
+			return
+		case <-s.lifecycleWakeCh:
+		}
+
+		for {
+			s.lifecycleMu.Lock()
+			if len(s.lifecycleQueue) == 0 {
+				s.lifecycleMu.Unlock()
+				break
+			}
+			item := s.lifecycleQueue[0]
+			s.lifecycleQueue = s.lifecycleQueue[1:]
+			s.lifecycleMu.Unlock()
+
+			select {
+			case <-s.rootCtx.Done():
+				return
+			case s.futureCh <- []LogRecord{item.record}:
+				close(item.sentCh)
+			}
+		}
+	}
+}
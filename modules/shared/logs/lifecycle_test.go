@@ -0,0 +1,288 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	set "github.com/deckarep/golang-set/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	k8shelpersmock "github.com/kubetail-org/kubetail/modules/shared/k8shelpers/mock"
+)
+
+// mockStatusSourceWatcher implements sourceWatcher and containerStatusGetter
+// for testing
+type mockStatusSourceWatcher struct {
+	mockSourceWatcher
+	statuses map[string]*corev1.ContainerStatus
+}
+
+func (m *mockStatusSourceWatcher) containerStatus(namespace string, podName string, containerName string) *corev1.ContainerStatus {
+	return m.statuses[namespace+"/"+podName+"/"+containerName]
+}
+
+func TestNewSourceAddedRecord(t *testing.T) {
+	source := LogSource{Namespace: "ns1", PodName: "pod1", ContainerName: "c1", ContainerID: "id2"}
+
+	tests := []struct {
+		name          string
+		setStatus     *corev1.ContainerStatus
+		wantLifecycle *Lifecycle
+		wantMessage   string
+	}{
+		{
+			"no status",
+			nil,
+			&Lifecycle{Type: LifecycleTypeSourceAdded},
+			"Source ns1/pod1/c1 added",
+		},
+		{
+			"new container",
+			&corev1.ContainerStatus{ContainerID: "id2"},
+			&Lifecycle{Type: LifecycleTypeSourceAdded},
+			"Source ns1/pod1/c1 added",
+		},
+		{
+			"restarted container",
+			&corev1.ContainerStatus{
+				ContainerID:  "id2",
+				RestartCount: 3,
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"},
+				},
+			},
+			&Lifecycle{Type: LifecycleTypeContainerRestarted, RestartCount: 3, ExitCode: 137, Reason: "OOMKilled"},
+			"Container ns1/pod1/c1 restarted (restart count 3, exit code 137, reason OOMKilled)",
+		},
+		{
+			"status for other container instance",
+			&corev1.ContainerStatus{ContainerID: "id3", RestartCount: 4},
+			&Lifecycle{Type: LifecycleTypeSourceAdded},
+			"Source ns1/pod1/c1 added",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newSourceAddedRecord(source, tt.setStatus)
+			assert.Equal(t, source, r.Source)
+			assert.Equal(t, tt.wantLifecycle, r.Lifecycle)
+			assert.Equal(t, tt.wantMessage, r.Message)
+			assert.False(t, r.Timestamp.IsZero())
+		})
+	}
+}
+
+func TestStreamWithLifecycle(t *testing.T) {
+	s1 := LogSource{Namespace: "ns1", PodName: "pod1", ContainerName: "c1", ContainerID: "id1"}
+	s1Restarted := LogSource{Namespace: "ns1", PodName: "pod1", ContainerName: "c1", ContainerID: "id2"}
+	s2 := LogSource{Namespace: "ns1", PodName: "pod2", ContainerName: "c1", ContainerID: "id3"}
+
+	// Init mock logFetcher
+	pastCh := make(chan LogRecord)
+	close(pastCh)
+
+	followCh := make(chan LogRecord)
+	defer close(followCh)
+
+	m := mockLogFetcher{}
+	m.On("StreamForward", mock.Anything, s1, FetcherOptions{MaxChunkSize: DEFAULT_MAX_CHUNK_SIZE}).
+		Return((<-chan LogRecord)(pastCh), nil)
+	m.On("StreamForward", mock.Anything, mock.Anything, mock.Anything).
+		Return((<-chan LogRecord)(followCh), nil)
+
+	// Init mock source watcher
+	sw := mockStatusSourceWatcher{
+		statuses: map[string]*corev1.ContainerStatus{
+			"ns1/pod1/c1": {
+				ContainerID:  "id2",
+				RestartCount: 1,
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"},
+				},
+			},
+		},
+	}
+	sw.On("Start", mock.Anything).Return(nil)
+	sw.On("Set").Return(set.NewSet(s1))
+	sw.On("Subscribe", mock.Anything, mock.Anything).Return()
+	sw.On("Unsubscribe", mock.Anything, mock.Anything).Return()
+
+	// Init connection manager
+	cm := &k8shelpersmock.MockConnectionManager{}
+	cm.On("GetOrCreateClientset", mock.Anything).Return(&fake.Clientset{}, nil)
+	cm.On("GetDefaultNamespace", mock.Anything).Return("default")
+
+	// Create stream
+	stream, err := NewStream(t.Context(), cm, []string{}, WithAll(), WithFollow(true), WithLifecycle(true))
+	require.NoError(t, err)
+	defer stream.Close()
+
+	stream.sw = &sw
+	stream.logFetcher = &m
+
+	err = stream.Start(context.Background())
+	require.NoError(t, err)
+
+	// Simulate source watcher events
+	go func() {
+		stream.handleSourceAdd(s1Restarted)
+		stream.handleSourceDelete(s1) // replaced by restart so no marker
+		stream.handleSourceAdd(s2)
+		stream.handleSourceDelete(s2)
+	}()
+
+	records := []LogRecord{}
+	for r := range stream.Records() {
+		records = append(records, r)
+		if len(records) == 3 {
+			break
+		}
+	}
+
+	require.Len(t, records, 3)
+
+	assert.Equal(t, s1Restarted, records[0].Source)
+	assert.Equal(t, &Lifecycle{Type: LifecycleTypeContainerRestarted, RestartCount: 1, ExitCode: 1, Reason: "Error"}, records[0].Lifecycle)
+
+	assert.Equal(t, s2, records[1].Source)
+	assert.Equal(t, LifecycleTypeSourceAdded, records[1].Lifecycle.Type)
+
+	assert.Equal(t, s2, records[2].Source)
+	assert.Equal(t, LifecycleTypeSourceRemoved, records[2].Lifecycle.Type)
+	assert.Equal(t, "Source ns1/pod2/c1 removed", records[2].Message)
+}
+
+func TestStreamWithLifecycleMaxSources(t *testing.T) {
+	s1 := LogSource{Namespace: "ns1", PodName: "pod1", ContainerName: "c1", ContainerID: "id1"}
+	s2 := LogSource{Namespace: "ns1", PodName: "pod2", ContainerName: "c1", ContainerID: "id2"}
+
+	// Init mock logFetcher
+	pastCh := make(chan LogRecord)
+	close(pastCh)
+
+	followCh := make(chan LogRecord)
+	defer close(followCh)
+
+	m := mockLogFetcher{}
+	m.On("StreamForward", mock.Anything, s1, FetcherOptions{MaxChunkSize: DEFAULT_MAX_CHUNK_SIZE}).
+		Return((<-chan LogRecord)(pastCh), nil)
+	m.On("StreamForward", mock.Anything, mock.Anything, mock.Anything).
+		Return((<-chan LogRecord)(followCh), nil)
+
+	// Init mock source watcher
+	sw := mockStatusSourceWatcher{}
+	sw.On("Start", mock.Anything).Return(nil)
+	sw.On("Set").Return(set.NewSet(s1))
+	sw.On("Subscribe", mock.Anything, mock.Anything).Return()
+	sw.On("Unsubscribe", mock.Anything, mock.Anything).Return()
+
+	// Init connection manager
+	cm := &k8shelpersmock.MockConnectionManager{}
+	cm.On("GetOrCreateClientset", mock.Anything).Return(&fake.Clientset{}, nil)
+	cm.On("GetDefaultNamespace", mock.Anything).Return("default")
+
+	// Create stream
+	stream, err := NewStream(t.Context(), cm, []string{}, WithAll(), WithFollow(true), WithLifecycle(true), WithMaxSources(1))
+	require.NoError(t, err)
+	defer stream.Close()
+
+	stream.sw = &sw
+	stream.logFetcher = &m
+
+	err = stream.Start(context.Background())
+	require.NoError(t, err)
+
+	// Sources past the limit are reported
+	go stream.handleSourceAdd(s2)
+
+	record := <-stream.Records()
+	assert.Equal(t, s2, record.Source)
+	assert.Equal(t, LifecycleTypeSourceSkipped, record.Lifecycle.Type)
+	assert.Equal(t, "Source ns1/pod2/c1 skipped (limit of 1 sources reached)", record.Message)
+	assert.Equal(t, 1, stream.sources.Cardinality())
+}
+
+func TestStreamWithLifecycleMarkerOrder(t *testing.T) {
+	s1 := LogSource{Namespace: "ns1", PodName: "pod1", ContainerName: "c1", ContainerID: "id1"}
+	s2 := LogSource{Namespace: "ns1", PodName: "pod2", ContainerName: "c1", ContainerID: "id2"}
+
+	// Init mock logFetcher
+	pastCh := make(chan LogRecord)
+	close(pastCh)
+
+	followCh := make(chan LogRecord)
+	defer close(followCh)
+
+	// New source has records ready immediately
+	s2Ch := make(chan LogRecord, 1)
+	s2Ch <- LogRecord{Timestamp: time.Now().Add(time.Second), Message: "hello", Source: s2}
+
+	m := mockLogFetcher{}
+	m.On("StreamForward", mock.Anything, s1, FetcherOptions{MaxChunkSize: DEFAULT_MAX_CHUNK_SIZE}).
+		Return((<-chan LogRecord)(pastCh), nil)
+	m.On("StreamForward", mock.Anything, s2, mock.Anything).
+		Return((<-chan LogRecord)(s2Ch), nil)
+	m.On("StreamForward", mock.Anything, mock.Anything, mock.Anything).
+		Return((<-chan LogRecord)(followCh), nil)
+
+	// Init mock source watcher
+	sw := mockStatusSourceWatcher{}
+	sw.On("Start", mock.Anything).Return(nil)
+	sw.On("Set").Return(set.NewSet(s1))
+	sw.On("Subscribe", mock.Anything, mock.Anything).Return()
+	sw.On("Unsubscribe", mock.Anything, mock.Anything).Return()
+
+	// Init connection manager
+	cm := &k8shelpersmock.MockConnectionManager{}
+	cm.On("GetOrCreateClientset", mock.Anything).Return(&fake.Clientset{}, nil)
+	cm.On("GetDefaultNamespace", mock.Anything).Return("default")
+
+	// Create stream
+	stream, err := NewStream(t.Context(), cm, []string{}, WithAll(), WithFollow(true), WithLifecycle(true))
+	require.NoError(t, err)
+	defer stream.Close()
+
+	stream.sw = &sw
+	stream.logFetcher = &m
+
+	err = stream.Start(context.Background())
+	require.NoError(t, err)
+
+	// Handlers return without a consumer reading records
+	stream.handleSourceAdd(s2)
+	stream.handleSourceDelete(s2)
+
+	records := []LogRecord{}
+	for r := range stream.Records() {
+		records = append(records, r)
+		if len(records) == 3 {
+			break
+		}
+	}
+
+	require.Len(t, records, 3)
+
+	// Marker precedes the source's records
+	assert.Equal(t, LifecycleTypeSourceAdded, records[0].Lifecycle.Type)
+	assert.ElementsMatch(t, []string{"hello", "Source ns1/pod2/c1 removed"}, []string{records[1].Message, records[2].Message})
+}
@@ -22,6 +22,8 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc"
@@ -34,6 +36,7 @@ import (
 
 	grpcdispatcher "github.com/kubetail-org/grpc-dispatcher-go"
 	"github.com/kubetail-org/kubetail/modules/shared/clusteragentpb"
+	"github.com/kubetail-org/kubetail/modules/shared/metrics"
 )
 
 // FollowFrom defines the starting point for following logs
@@ -51,9 +54,11 @@ type FetcherOptions struct {
 	StopTime      time.Time
 	Grep          string
 	GrepRegex     *regexp.Regexp
+	Filter        *Filter
 	FollowFrom    FollowFrom
 	BatchSizeHint int64
 	MaxChunkSize  int
+	MaxNum        int64 // Number of records the caller will read, 0 if unbounded
 }
 
 // LogFetcher defines forward and backward streaming.
@@ -62,6 +67,14 @@ type LogFetcher interface {
 	StreamBackward(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan LogRecord, error)
 }
 
+// BatchLogFetcher defines forward and backward streaming in batches. It can
+// be implemented by a LogFetcher to cut per-record channel overhead. Errors
+// are sent in a batch of their own and end the stream.
+type BatchLogFetcher interface {
+	StreamForwardBatch(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan []LogRecord, error)
+	StreamBackwardBatch(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan []LogRecord, error)
+}
+
 // KubeLogFetcher implements LogFetcher using Kubernetes clientset
 type KubeLogFetcher struct {
 	clientset kubernetes.Interface
@@ -142,6 +155,11 @@ func (f *KubeLogFetcher) StreamForward(ctx context.Context, source LogSource, op
 				continue
 			}
 
+			// Check filter
+			if !opts.Filter.Match(record.Message) {
+				continue
+			}
+
 			// Set source
 			record.Source = source
 
@@ -291,6 +309,11 @@ func (f *KubeLogFetcher) StreamBackward(ctx context.Context, source LogSource, o
 					continue
 				}
 
+				// Check filter
+				if !opts.Filter.Match(record.Message) {
+					continue
+				}
+
 				select {
 				case <-ctx.Done():
 					return // exit
@@ -312,11 +335,17 @@ func (f *KubeLogFetcher) StreamBackward(ctx context.Context, source LogSource, o
 	return outCh, nil
 }
 
-// AgentLogFetcher implements LogFetcher using Kubetail Cluster Agent
+// AgentLogFetcher implements LogFetcher and BatchLogFetcher using Kubetail
+// Cluster Agent
 type AgentLogFetcher struct {
 	grpcDispatcher *grpcdispatcher.Dispatcher
+	unbatchedNodes sync.Map // node -> time agent was found not to support batched streams
 }
 
+// Time after which nodes whose agents didn't support batched streams are
+// tried again (e.g. after the agent was upgraded)
+const unbatchedNodeTTL = 5 * time.Minute
+
 // NewAgentLogFetcher creates a new AgentLogFetcher
 func NewAgentLogFetcher(grpcDispatcher *grpcdispatcher.Dispatcher) *AgentLogFetcher {
 	return &AgentLogFetcher{
@@ -326,75 +355,142 @@ func NewAgentLogFetcher(grpcDispatcher *grpcdispatcher.Dispatcher) *AgentLogFetc
 
 // StreamForward returns a channel of LogRecords in chronological order for the given source
 func (f *AgentLogFetcher) StreamForward(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan LogRecord, error) {
+	batchCh, err := f.StreamForwardBatch(ctx, source, opts)
+	if err != nil {
+		return nil, err
+	}
+	return unbatchRecords(ctx, batchCh), nil
+}
+
+// StreamBackward returns a channel of LogRecords in reverse chronological order for the given source
+func (f *AgentLogFetcher) StreamBackward(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan LogRecord, error) {
+	batchCh, err := f.StreamBackwardBatch(ctx, source, opts)
+	if err != nil {
+		return nil, err
+	}
+	return unbatchRecords(ctx, batchCh), nil
+}
+
+// StreamForwardBatch returns a channel of LogRecord batches in chronological order for the given source
+func (f *AgentLogFetcher) StreamForwardBatch(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan []LogRecord, error) {
+	req := newLogRecordsStreamRequest(source, opts)
+
+	switch opts.FollowFrom {
+	case FollowFromNoop:
+		req.FollowFrom = clusteragentpb.FollowFrom_NOOP
+	case FollowFromDefault:
+		req.FollowFrom = clusteragentpb.FollowFrom_DEFAULT
+	case FollowFromEnd:
+		req.FollowFrom = clusteragentpb.FollowFrom_END
+	default:
+		return nil, fmt.Errorf("invalid follow from: %s", opts.FollowFrom)
+	}
+
+	return f.streamBatches(ctx, source, req, opts.Filter, true)
+}
+
+// StreamBackwardBatch returns a channel of LogRecord batches in reverse chronological order for the given source
+func (f *AgentLogFetcher) StreamBackwardBatch(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan []LogRecord, error) {
+	return f.streamBatches(ctx, source, newLogRecordsStreamRequest(source, opts), opts.Filter, false)
+}
+
+// Execute stream request on the source's node. Uses the batched variant of
+// the RPC unless the agent has reported that it doesn't support it, in which
+// case each record is sent in a batch of its own.
+func (f *AgentLogFetcher) streamBatches(ctx context.Context, source LogSource, req *clusteragentpb.LogRecordsStreamRequest, filter *Filter, forward bool) (<-chan []LogRecord, error) {
 	// Init output channel
-	outCh := make(chan LogRecord)
+	outCh := make(chan []LogRecord)
+
+	node := source.Metadata.Node
 
-	err := f.grpcDispatcher.UnicastSubscribeOnce(ctx, source.Metadata.Node, func(ctx context.Context, conn *grpc.ClientConn) {
+	method := "StreamBackward"
+	if forward {
+		method = "StreamForward"
+	}
+
+	err := f.grpcDispatcher.UnicastSubscribeOnce(ctx, node, func(ctx context.Context, conn *grpc.ClientConn) {
 		defer close(outCh)
 
 		// init client
 		c := clusteragentpb.NewLogRecordsServiceClient(conn)
 
-		// Init gRPC request
-		req := &clusteragentpb.LogRecordsStreamRequest{
-			Namespace:     source.Namespace,
-			PodName:       source.PodName,
-			ContainerName: source.ContainerName,
-			ContainerId:   source.ContainerID,
-			Grep:          opts.Grep,
+		// Send batch to output channel
+		send := func(batch []LogRecord) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case outCh <- batch:
+				return true
+			}
 		}
 
-		if !opts.StartTime.IsZero() {
-			req.StartTime = opts.StartTime.Format(time.RFC3339Nano)
-		}
+		// Send unexpected errors
+		handleErr := func(err error) {
+			// Ignore normal errors
+			if errors.Is(err, io.EOF) ||
+				errors.Is(err, context.Canceled) ||
+				status.Code(err) == codes.Canceled {
+				return
+			}
 
-		if !opts.StopTime.IsZero() {
-			req.StopTime = opts.StopTime.Format(time.RFC3339Nano)
+			metrics.AgentErrors.WithLabelValues(node, method).Inc()
+			send(newErrBatch(err))
 		}
 
-		switch opts.FollowFrom {
-		case FollowFromNoop:
-			req.FollowFrom = clusteragentpb.FollowFrom_NOOP
-		case FollowFromDefault:
-			req.FollowFrom = clusteragentpb.FollowFrom_DEFAULT
-		case FollowFromEnd:
-			req.FollowFrom = clusteragentpb.FollowFrom_END
-		default:
-			return
+		// Try batched stream first
+		if !f.isUnbatched(node) {
+			var stream grpc.ServerStreamingClient[clusteragentpb.LogRecordBatch]
+			var err error
+			if forward {
+				stream, err = c.StreamForwardBatch(ctx, req)
+			} else {
+				stream, err = c.StreamBackwardBatch(ctx, req)
+			}
+
+			isFirst := true
+			for err == nil {
+				var ev *clusteragentpb.LogRecordBatch
+				if ev, err = stream.Recv(); err != nil {
+					break
+				}
+				isFirst = false
+
+				batch := newAgentRecords(source, filter, ev.Records...)
+				if len(batch) > 0 && !send(batch) {
+					return
+				}
+			}
+
+			// Fall back to unbatched stream on older agents
+			if !isFirst || status.Code(err) != codes.Unimplemented {
+				handleErr(err)
+				return
+			}
+			f.unbatchedNodes.Store(node, time.Now())
 		}
 
 		// Execute
-		stream, err := c.StreamForward(ctx, req)
-		if err != nil {
-			// Send error
-			outCh <- LogRecord{err: err}
-			return
+		var stream grpc.ServerStreamingClient[clusteragentpb.LogRecord]
+		var err error
+		if forward {
+			stream, err = c.StreamForward(ctx, req)
+		} else {
+			stream, err = c.StreamBackward(ctx, req)
 		}
 
-		for {
-			ev, err := stream.Recv()
-
-			// Handle errors
-			if err != nil {
-				// Ignore normal errors
-				if errors.Is(err, io.EOF) ||
-					errors.Is(err, context.Canceled) ||
-					status.Code(err) == codes.Canceled {
-					break
-				}
-
-				// Send unexpected error
-				outCh <- LogRecord{err: err}
+		for err == nil {
+			var ev *clusteragentpb.LogRecord
+			if ev, err = stream.Recv(); err != nil {
 				break
 			}
 
-			// Send event
-			outCh <- LogRecord{
-				Message:   ev.Message,
-				Timestamp: ev.Timestamp.AsTime(),
-				Source:    source,
+			batch := newAgentRecords(source, filter, ev)
+			if len(batch) > 0 && !send(batch) {
+				return
 			}
 		}
+
+		handleErr(err)
 	})
 	if err != nil {
 		return nil, err
@@ -403,70 +499,219 @@ func (f *AgentLogFetcher) StreamForward(ctx context.Context, source LogSource, o
 	return outCh, nil
 }
 
-// StreamBackward returns a channel of LogRecords in reverse chronological order for the given source
-func (f *AgentLogFetcher) StreamBackward(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan LogRecord, error) {
-	// Init output channel
-	outCh := make(chan LogRecord)
+// Convert agent records that match `filter`. Agents that predate structured
+// filters ignore the request field so the filter is re-applied here.
+func newAgentRecords(source LogSource, filter *Filter, records ...*clusteragentpb.LogRecord) []LogRecord {
+	out := make([]LogRecord, 0, len(records))
+	for _, r := range records {
+		if !filter.Match(r.Message) {
+			continue
+		}
+		out = append(out, LogRecord{
+			Message:   r.Message,
+			Timestamp: r.Timestamp.AsTime(),
+			Source:    source,
+		})
+	}
+	return out
+}
 
-	err := f.grpcDispatcher.UnicastSubscribeOnce(ctx, source.Metadata.Node, func(ctx context.Context, conn *grpc.ClientConn) {
-		defer close(outCh)
+// Check if node's agent was recently found not to support batched streams.
+// Expired entries are removed.
+func (f *AgentLogFetcher) isUnbatched(node string) bool {
+	v, exists := f.unbatchedNodes.Load(node)
+	if !exists {
+		return false
+	}
 
-		// init client
-		c := clusteragentpb.NewLogRecordsServiceClient(conn)
+	if time.Since(v.(time.Time)) < unbatchedNodeTTL {
+		return true
+	}
 
-		// Init gRPC request
-		req := &clusteragentpb.LogRecordsStreamRequest{
-			Namespace:     source.Namespace,
-			PodName:       source.PodName,
-			ContainerName: source.ContainerName,
-			ContainerId:   source.ContainerID,
-			Grep:          opts.Grep,
-		}
+	f.unbatchedNodes.CompareAndDelete(node, v)
+	return false
+}
 
-		if !opts.StartTime.IsZero() {
-			req.StartTime = opts.StartTime.Format(time.RFC3339Nano)
-		}
+// Return agent stream request for source
+func newLogRecordsStreamRequest(source LogSource, opts FetcherOptions) *clusteragentpb.LogRecordsStreamRequest {
+	req := &clusteragentpb.LogRecordsStreamRequest{
+		Namespace:     source.Namespace,
+		PodName:       source.PodName,
+		ContainerName: source.ContainerName,
+		ContainerId:   source.ContainerID,
+		Grep:          opts.Grep,
+		Filter:        opts.Filter.toProto(),
+	}
 
-		if !opts.StopTime.IsZero() {
-			req.StopTime = opts.StopTime.Format(time.RFC3339Nano)
-		}
+	if !opts.StartTime.IsZero() {
+		req.StartTime = opts.StartTime.Format(time.RFC3339Nano)
+	}
 
-		// Execute
-		stream, err := c.StreamBackward(ctx, req)
-		if err != nil {
-			// Send error
-			outCh <- LogRecord{err: err}
-			return
-		}
+	if !opts.StopTime.IsZero() {
+		req.StopTime = opts.StopTime.Format(time.RFC3339Nano)
+	}
 
-		for {
-			ev, err := stream.Recv()
+	return req
+}
 
-			// Handle errors
-			if err != nil {
-				// Ignore normal errors
-				if errors.Is(err, io.EOF) ||
-					errors.Is(err, context.Canceled) ||
-					status.Code(err) == codes.Canceled {
-					break
-				}
+// FetchPath identifies the backend that served a source
+type FetchPath string
 
-				// Send unexpected error
-				outCh <- LogRecord{err: err}
-				break
-			}
+const (
+	FetchPathCache   FetchPath = "CACHE"
+	FetchPathAgent   FetchPath = "AGENT"
+	FetchPathKubeAPI FetchPath = "KUBE_API"
+)
 
-			// Send event
-			outCh <- LogRecord{
-				Message:   ev.Message,
-				Timestamp: ev.Timestamp.AsTime(),
-				Source:    source,
-			}
-		}
-	})
+// Implemented by fetchers that track the path that served each source
+type fetchPathRecorder interface {
+	recordFetchPath(source LogSource, path FetchPath)
+}
+
+// CompositeLogFetcher implements LogFetcher and BatchLogFetcher using
+// Kubetail Cluster Agent on nodes where an agent is available and the
+// Kubernetes API on the rest
+type CompositeLogFetcher struct {
+	agentFetcher LogFetcher
+	kubeFetcher  LogFetcher
+	hasAgent     func(ctx context.Context, nodeName string) bool
+	fetchPaths   *sync.Map // LogSource -> FetchPath (nil unless tracked)
+}
+
+// NewCompositeLogFetcher creates a new CompositeLogFetcher
+func NewCompositeLogFetcher(grpcDispatcher *grpcdispatcher.Dispatcher, clientset kubernetes.Interface) *CompositeLogFetcher {
+	return &CompositeLogFetcher{
+		agentFetcher: NewAgentLogFetcher(grpcDispatcher),
+		kubeFetcher:  NewKubeLogFetcher(clientset),
+		hasAgent: func(ctx context.Context, nodeName string) bool {
+			return hasServingAgent(ctx, grpcDispatcher, nodeName)
+		},
+	}
+}
+
+// StreamForward returns a channel of LogRecords in chronological order for the given source
+func (f *CompositeLogFetcher) StreamForward(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan LogRecord, error) {
+	return f.fetcherFor(ctx, source).StreamForward(ctx, source, opts)
+}
+
+// StreamBackward returns a channel of LogRecords in reverse chronological order for the given source
+func (f *CompositeLogFetcher) StreamBackward(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan LogRecord, error) {
+	return f.fetcherFor(ctx, source).StreamBackward(ctx, source, opts)
+}
+
+// StreamForwardBatch returns a channel of LogRecord batches in chronological order for the given source
+func (f *CompositeLogFetcher) StreamForwardBatch(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan []LogRecord, error) {
+	fetcher := f.fetcherFor(ctx, source)
+	if bf, ok := fetcher.(BatchLogFetcher); ok {
+		return bf.StreamForwardBatch(ctx, source, opts)
+	}
+
+	recordCh, err := fetcher.StreamForward(ctx, source, opts)
 	if err != nil {
 		return nil, err
 	}
+	return batchRecords(ctx, recordCh), nil
+}
 
-	return outCh, nil
+// StreamBackwardBatch returns a channel of LogRecord batches in reverse chronological order for the given source
+func (f *CompositeLogFetcher) StreamBackwardBatch(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan []LogRecord, error) {
+	fetcher := f.fetcherFor(ctx, source)
+	if bf, ok := fetcher.(BatchLogFetcher); ok {
+		return bf.StreamBackwardBatch(ctx, source, opts)
+	}
+
+	recordCh, err := fetcher.StreamBackward(ctx, source, opts)
+	if err != nil {
+		return nil, err
+	}
+	return batchRecords(ctx, recordCh), nil
+}
+
+// WithAgentFetcher sets the fetcher used on nodes with an agent and returns
+// the fetcher. Sharing one AgentLogFetcher across requests lets it remember
+// which agents don't support batched streams.
+func (f *CompositeLogFetcher) WithAgentFetcher(agentFetcher *AgentLogFetcher) *CompositeLogFetcher {
+	f.agentFetcher = agentFetcher
+	return f
+}
+
+// TrackFetchPaths enables recording of the path that served each source and
+// returns the fetcher. Tracked fetchers should be used for a single stream
+// so the record doesn't grow without bound.
+func (f *CompositeLogFetcher) TrackFetchPaths() *CompositeLogFetcher {
+	f.fetchPaths = &sync.Map{}
+	return f
+}
+
+// FetchPaths returns the path that served each source fetched so far
+func (f *CompositeLogFetcher) FetchPaths() map[LogSource]FetchPath {
+	out := map[LogSource]FetchPath{}
+	if f.fetchPaths == nil {
+		return out
+	}
+	f.fetchPaths.Range(func(key, value any) bool {
+		out[key.(LogSource)] = value.(FetchPath)
+		return true
+	})
+	return out
+}
+
+// Return fetcher for the source's node and record the path that serves it if
+// tracking is enabled
+func (f *CompositeLogFetcher) fetcherFor(ctx context.Context, source LogSource) LogFetcher {
+	if f.hasAgent(ctx, source.Metadata.Node) {
+		f.recordFetchPath(source, FetchPathAgent)
+		return f.agentFetcher
+	}
+
+	metrics.AgentFallbacks.WithLabelValues(source.Metadata.Node).Inc()
+	f.recordFetchPath(source, FetchPathKubeAPI)
+	return f.kubeFetcher
+}
+
+// Record the path that served a source if tracking is enabled. A source is
+// reported with the slowest path used for it (cache, then agent, then
+// Kubernetes API).
+func (f *CompositeLogFetcher) recordFetchPath(source LogSource, path FetchPath) {
+	if f.fetchPaths == nil {
+		return
+	}
+
+	for {
+		prev, loaded := f.fetchPaths.LoadOrStore(source, path)
+		if !loaded || fetchPathRank(prev.(FetchPath)) >= fetchPathRank(path) {
+			return
+		}
+		if f.fetchPaths.CompareAndSwap(source, prev, path) {
+			return
+		}
+	}
+}
+
+// Return rank of fetch path from fastest to slowest
+func fetchPathRank(path FetchPath) int {
+	switch path {
+	case FetchPathCache:
+		return 0
+	case FetchPathAgent:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Return true if the dispatcher has a serving agent on the node. The
+// dispatcher only tracks agents whose endpoints are serving so missing,
+// unschedulable and crashlooping agents are all reported as unavailable.
+func hasServingAgent(ctx context.Context, grpcDispatcher *grpcdispatcher.Dispatcher, nodeName string) bool {
+	if nodeName == "" {
+		return false
+	}
+
+	// Unicast only invokes the handler if a server exists on the node
+	var found atomic.Bool
+	grpcDispatcher.Unicast(ctx, nodeName, func(ctx context.Context, conn *grpc.ClientConn) {
+		found.Store(true)
+	})
+	return found.Load()
 }
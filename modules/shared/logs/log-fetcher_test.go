@@ -0,0 +1,185 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubetail-org/kubetail/modules/shared/clusteragentpb"
+)
+
+func TestCompositeLogFetcher(t *testing.T) {
+	s1 := LogSource{Namespace: "ns", PodName: "pod1", ContainerName: "c", Metadata: LogSourceMetadata{Node: "node1"}}
+	s2 := LogSource{Namespace: "ns", PodName: "pod2", ContainerName: "c", Metadata: LogSourceMetadata{Node: "node2"}}
+
+	newRecordCh := func(messages ...string) <-chan LogRecord {
+		ch := make(chan LogRecord, len(messages))
+		for _, msg := range messages {
+			ch <- LogRecord{Message: msg}
+		}
+		close(ch)
+		return ch
+	}
+
+	newFetcher := func(agentNodes ...string) (*CompositeLogFetcher, *mockLogFetcher, *mockLogFetcher) {
+		agentFetcher := &mockLogFetcher{}
+		kubeFetcher := &mockLogFetcher{}
+		f := &CompositeLogFetcher{
+			agentFetcher: agentFetcher,
+			kubeFetcher:  kubeFetcher,
+			hasAgent: func(ctx context.Context, nodeName string) bool {
+				for _, n := range agentNodes {
+					if n == nodeName {
+						return true
+					}
+				}
+				return false
+			},
+		}
+		return f.TrackFetchPaths(), agentFetcher, kubeFetcher
+	}
+
+	t.Run("routes sources by node", func(t *testing.T) {
+		f, agentFetcher, kubeFetcher := newFetcher("node1")
+		agentFetcher.On("StreamForward", mock.Anything, s1, mock.Anything).Return(newRecordCh("agent"), nil)
+		kubeFetcher.On("StreamForward", mock.Anything, s2, mock.Anything).Return(newRecordCh("kube"), nil)
+
+		ch1, err := f.StreamForward(context.Background(), s1, FetcherOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "agent", (<-ch1).Message)
+
+		ch2, err := f.StreamForward(context.Background(), s2, FetcherOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "kube", (<-ch2).Message)
+
+		assert.Equal(t, map[LogSource]FetchPath{
+			s1: FetchPathAgent,
+			s2: FetchPathKubeAPI,
+		}, f.FetchPaths())
+	})
+
+	t.Run("batches records from fetchers without batch support", func(t *testing.T) {
+		f, _, kubeFetcher := newFetcher()
+		kubeFetcher.On("StreamBackward", mock.Anything, s1, mock.Anything).Return(newRecordCh("r2", "r1"), nil)
+
+		batchCh, err := f.StreamBackwardBatch(context.Background(), s1, FetcherOptions{})
+		require.NoError(t, err)
+
+		var messages []string
+		for batch := range batchCh {
+			for _, r := range batch {
+				messages = append(messages, r.Message)
+			}
+		}
+		assert.Equal(t, []string{"r2", "r1"}, messages)
+	})
+
+	t.Run("fallback takes precedence in fetch paths", func(t *testing.T) {
+		agentNodes := []string{"node1"}
+		f, agentFetcher, kubeFetcher := newFetcher()
+		f.hasAgent = func(ctx context.Context, nodeName string) bool {
+			return len(agentNodes) > 0
+		}
+		agentFetcher.On("StreamBackward", mock.Anything, s1, mock.Anything).Return(newRecordCh(), nil)
+		kubeFetcher.On("StreamForward", mock.Anything, s1, mock.Anything).Return(newRecordCh(), nil)
+
+		_, err := f.StreamBackward(context.Background(), s1, FetcherOptions{})
+		require.NoError(t, err)
+
+		// Agent goes away before the follow request
+		agentNodes = nil
+		_, err = f.StreamForward(context.Background(), s1, FetcherOptions{FollowFrom: FollowFromEnd})
+		require.NoError(t, err)
+
+		assert.Equal(t, FetchPathKubeAPI, f.FetchPaths()[s1])
+	})
+
+	t.Run("untracked", func(t *testing.T) {
+		agentFetcher := &mockLogFetcher{}
+		f := &CompositeLogFetcher{
+			agentFetcher: agentFetcher,
+			kubeFetcher:  &mockLogFetcher{},
+			hasAgent:     func(ctx context.Context, nodeName string) bool { return true },
+		}
+		assert.Same(t, agentFetcher, f.fetcherFor(context.Background(), s1))
+		assert.Empty(t, f.FetchPaths())
+	})
+
+	t.Run("no node", func(t *testing.T) {
+		assert.False(t, hasServingAgent(context.Background(), nil, ""))
+	})
+}
+
+func TestAgentLogFetcherUnbatchedNodes(t *testing.T) {
+	f := NewAgentLogFetcher(nil)
+
+	assert.False(t, f.isUnbatched("node1"))
+
+	// Recently marked nodes use unbatched streams
+	f.unbatchedNodes.Store("node1", time.Now())
+	assert.True(t, f.isUnbatched("node1"))
+
+	// Expired entries are tried again
+	f.unbatchedNodes.Store("node2", time.Now().Add(-unbatchedNodeTTL))
+	assert.False(t, f.isUnbatched("node2"))
+
+	_, exists := f.unbatchedNodes.Load("node2")
+	assert.False(t, exists)
+}
+
+func TestCompositeLogFetcherSharedAgentFetcher(t *testing.T) {
+	agentFetcher := NewAgentLogFetcher(nil)
+
+	// First request finds an agent without batched streams
+	first := NewCompositeLogFetcher(nil, &fake.Clientset{}).WithAgentFetcher(agentFetcher)
+	first.agentFetcher.(*AgentLogFetcher).unbatchedNodes.Store("node1", time.Now())
+
+	// Second request skips the batched attempt
+	second := NewCompositeLogFetcher(nil, &fake.Clientset{}).WithAgentFetcher(agentFetcher)
+	assert.True(t, second.agentFetcher.(*AgentLogFetcher).isUnbatched("node1"))
+
+	// Unshared fetchers start over
+	assert.False(t, NewCompositeLogFetcher(nil, &fake.Clientset{}).agentFetcher.(*AgentLogFetcher).isUnbatched("node1"))
+}
+
+func TestNewAgentRecords(t *testing.T) {
+	source := LogSource{Namespace: "ns", PodName: "pod1", ContainerName: "c"}
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Agents without filter support return unfiltered records
+	records := []*clusteragentpb.LogRecord{
+		{Message: "GET /healthz", Timestamp: timestamppb.New(ts)},
+		{Message: "GET /api", Timestamp: timestamppb.New(ts.Add(time.Second))},
+	}
+
+	filter, err := NewFilter(nil, []string{"healthz"}, nil)
+	require.NoError(t, err)
+
+	out := newAgentRecords(source, filter, records...)
+	require.Len(t, out, 1)
+	assert.Equal(t, "GET /api", out[0].Message)
+	assert.Equal(t, ts.Add(time.Second), out[0].Timestamp)
+	assert.Equal(t, source, out[0].Source)
+
+	assert.Len(t, newAgentRecords(source, nil, records...), 2)
+}
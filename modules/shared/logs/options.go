@@ -23,7 +23,7 @@ import (
 
 type Option func(target any) error
 
-// WithKubeContext sets the kube context of the stream or source watcher
+// WithKubeContext sets the kube context of the stream, counter or source watcher
 func WithKubeContext(kubeContext string) Option {
 	return func(target any) error {
 		switch t := target.(type) {
@@ -31,39 +31,49 @@ func WithKubeContext(kubeContext string) Option {
 			t.kubeContext = kubeContext
 		case *sourceWatcher:
 			t.kubeContext = kubeContext
+		case *Counter:
+			t.kubeContext = kubeContext
 		}
 		return nil
 	}
 }
 
-// WithBearerToken sets the bearer token of the source watcher
+// WithBearerToken sets the bearer token of the stream, counter or source watcher
 func WithBearerToken(token string) Option {
 	return func(target any) error {
 		switch t := target.(type) {
+		case *Stream:
+			t.bearerToken = token
 		case *sourceWatcher:
 			t.bearerToken = token
+		case *Counter:
+			t.bearerToken = token
 		}
 		return nil
 	}
 }
 
-// WithSince sets the since time for the stream
+// WithSince sets the since time for the stream or counter
 func WithSince(ts time.Time) Option {
 	return func(target any) error {
 		switch t := target.(type) {
 		case *Stream:
 			t.sinceTime = ts
+		case *Counter:
+			t.sinceTime = ts
 		}
 		return nil
 	}
 }
 
-// WithUntil sets the until time for the stream
+// WithUntil sets the until time for the stream or counter
 func WithUntil(ts time.Time) Option {
 	return func(target any) error {
 		switch t := target.(type) {
 		case *Stream:
 			t.untilTime = ts
+		case *Counter:
+			t.untilTime = ts
 		}
 		return nil
 	}
@@ -121,30 +131,98 @@ func WithAll() Option {
 	}
 }
 
-// WithGrep sets the grep filter for the stream
+// WithEvents sets whether to interleave Kubernetes events involving the
+// stream's pods and their owners into the stream
+func WithEvents(withEvents bool) Option {
+	return func(target any) error {
+		switch t := target.(type) {
+		case *Stream:
+			t.withEvents = withEvents
+		}
+		return nil
+	}
+}
+
+// WithLifecycle sets whether to emit synthetic records when sources are
+// added, removed or restarted while following
+func WithLifecycle(withLifecycle bool) Option {
+	return func(target any) error {
+		switch t := target.(type) {
+		case *Stream:
+			t.withLifecycle = withLifecycle
+		}
+		return nil
+	}
+}
+
+// WithGrep sets the grep filter for the stream or counter
 func WithGrep(pattern string) Option {
 	return func(target any) error {
 		switch t := target.(type) {
 		case *Stream:
-			pattern = strings.TrimSpace(pattern)
-			if pattern == "" {
-				return nil
+			grep, regex, err := compileGrep(pattern)
+			if err != nil {
+				return err
 			}
+			t.grep = grep
+			t.grepRegex = regex
+		case *Counter:
+			grep, regex, err := compileGrep(pattern)
+			if err != nil {
+				return err
+			}
+			t.grep = grep
+			t.grepRegex = regex
+		}
+		return nil
+	}
+}
 
-			// Replace spaces with ANSI-tolerant pattern
-			pattern = strings.ReplaceAll(pattern, " ", `(?:(?:\x1B\[[0-9;]*[mK])?)*\s(?:(?:\x1B\[[0-9;]*[mK])?)*`)
+// Return the grep pattern and its compiled regex (nil if pattern is empty)
+func compileGrep(pattern string) (string, *regexp.Regexp, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return "", nil, nil
+	}
 
-			// Prepend the (?i) flag to make the regex case-insensitive
-			pattern = "(?i)" + pattern
+	// Replace spaces with ANSI-tolerant pattern
+	pattern = strings.ReplaceAll(pattern, " ", `(?:(?:\x1B\[[0-9;]*[mK])?)*\s(?:(?:\x1B\[[0-9;]*[mK])?)*`)
 
-			// Compile the regex pattern
-			regex, err := regexp.Compile(pattern)
-			if err != nil {
-				return fmt.Errorf("invalid grep pattern: %w", err)
-			}
+	// Prepend the (?i) flag to make the regex case-insensitive
+	pattern = "(?i)" + pattern
 
-			t.grep = pattern
-			t.grepRegex = regex
+	// Compile the regex pattern
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid grep pattern: %w", err)
+	}
+
+	return pattern, regex, nil
+}
+
+// WithFilter sets the structured filter for the stream or counter
+func WithFilter(filter *Filter) Option {
+	return func(target any) error {
+		switch t := target.(type) {
+		case *Stream:
+			t.filter = filter
+		case *Counter:
+			t.filter = filter
+		}
+		return nil
+	}
+}
+
+// WithBucketSize sets the width of the time buckets for the counter
+// (0 means a single bucket)
+func WithBucketSize(d time.Duration) Option {
+	return func(target any) error {
+		switch t := target.(type) {
+		case *Counter:
+			if err := validateBucketSize(d); err != nil {
+				return err
+			}
+			t.bucketSize = d
 		}
 		return nil
 	}
@@ -227,6 +305,17 @@ func WithLogFetcher(logFetcher LogFetcher) Option {
 	}
 }
 
+// WithLogCounter sets the log counter for the counter
+func WithLogCounter(logCounter LogCounter) Option {
+	return func(target any) error {
+		switch t := target.(type) {
+		case *Counter:
+			t.logCounter = logCounter
+		}
+		return nil
+	}
+}
+
 // WithAllowedNamespaces restricts the allowed namespaces
 func WithAllowedNamespaces(allowedNamespaces []string) Option {
 	return func(target any) error {
@@ -250,6 +339,19 @@ func WithAllContainers(allContainers bool) Option {
 	}
 }
 
+// WithServingOnly restricts service and ingress sources to pods that are
+// serving endpoints
+func WithServingOnly(servingOnly bool) Option {
+	return func(target any) error {
+		switch t := target.(type) {
+		case *sourceWatcher:
+			t.servingOnly = servingOnly
+		}
+
+		return nil
+	}
+}
+
 // WithMaxChunkSize sets the maximum number of bytes
 // to include in each message chunk
 func WithMaxChunkSize(n int) Option {
@@ -261,3 +363,17 @@ func WithMaxChunkSize(n int) Option {
 		return nil
 	}
 }
+
+// WithMaxSources sets the maximum number of sources a stream or counter
+// can read from (0 means unlimited)
+func WithMaxSources(n int) Option {
+	return func(target any) error {
+		switch t := target.(type) {
+		case *Stream:
+			t.maxSources = n
+		case *Counter:
+			t.maxSources = n
+		}
+		return nil
+	}
+}
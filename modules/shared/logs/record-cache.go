@@ -0,0 +1,400 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubetail-org/kubetail/modules/shared/metrics"
+)
+
+// Default limits for RecordCache
+const (
+	DefaultRecordCacheMaxRecords    = 2000
+	DefaultRecordCacheMaxContainers = 1000
+)
+
+// RecordCache holds the most recent records of containers that are being
+// followed so repeated tail queries can be answered without going back to
+// the node. A container's entry is kept warm by a single unfiltered follow
+// stream and removed when that stream ends or the log file is deleted.
+type RecordCache struct {
+	maxRecords    int
+	maxContainers int
+	entries       map[string]*recordCacheEntry
+	mu            sync.Mutex
+}
+
+// Represents the cached records of a container
+type recordCacheEntry struct {
+	records      []LogRecord // chronological
+	coveredSince time.Time   // every record at or after this time is cached
+	hasCoverage  bool
+	lastAppendAt time.Time
+	modifiedAt   time.Time // last time the log file was reported modified
+	maxChunkSize int
+}
+
+// NewRecordCache creates a new RecordCache that keeps up to `maxRecords`
+// records for each of up to `maxContainers` containers
+func NewRecordCache(maxRecords int, maxContainers int) *RecordCache {
+	if maxRecords <= 0 {
+		maxRecords = DefaultRecordCacheMaxRecords
+	}
+	if maxContainers <= 0 {
+		maxContainers = DefaultRecordCacheMaxContainers
+	}
+	return &RecordCache{
+		maxRecords:    maxRecords,
+		maxContainers: maxContainers,
+		entries:       make(map[string]*recordCacheEntry),
+	}
+}
+
+// ObserveModified marks the container's cached records as out of date until
+// the follow stream delivers a newer record
+func (c *RecordCache) ObserveModified(containerID string, ts time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, exists := c.entries[bareContainerID(containerID)]; exists {
+		e.modifiedAt = ts
+	}
+}
+
+// Invalidate removes the container's cached records
+func (c *RecordCache) Invalidate(containerID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, bareContainerID(containerID))
+}
+
+// Len returns the number of cached containers
+func (c *RecordCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Register a new entry for the container. Returns nil if the container is
+// already being cached or the cache is full.
+func (c *RecordCache) warm(containerID string, maxChunkSize int) *recordCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := bareContainerID(containerID)
+	if _, exists := c.entries[key]; exists || len(c.entries) >= c.maxContainers {
+		return nil
+	}
+
+	e := &recordCacheEntry{maxChunkSize: maxChunkSize}
+	c.entries[key] = e
+	return e
+}
+
+// Remove entry if it's still registered for the container
+func (c *RecordCache) cool(containerID string, e *recordCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := bareContainerID(containerID)
+	if c.entries[key] == e {
+		delete(c.entries, key)
+	}
+}
+
+// Add records from the follow stream. The first record marks the start of
+// the covered window since the stream follows from the end of the file.
+func (c *RecordCache) append(e *recordCacheEntry, records []LogRecord) {
+	if len(records) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !e.hasCoverage {
+		e.coveredSince = records[0].Timestamp
+		e.hasCoverage = true
+	}
+
+	e.records = append(e.records, records...)
+	e.lastAppendAt = time.Now()
+
+	// Trim in chunks to avoid copying on every append
+	if len(e.records) > c.maxRecords+c.maxRecords/4 {
+		drop := len(e.records) - c.maxRecords
+		e.coveredSince = e.records[drop-1].Timestamp.Add(time.Nanosecond)
+		for drop < len(e.records) && e.records[drop].Timestamp.Before(e.coveredSince) {
+			drop++
+		}
+		e.records = append([]LogRecord(nil), e.records[drop:]...)
+	}
+}
+
+// Return the cached records that match `opts` in reverse chronological order
+// along with the start of the covered window. The last return value is false
+// if the container's records can't be answered from the cache.
+func (c *RecordCache) lookup(containerID string, opts FetcherOptions) ([]LogRecord, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, exists := c.entries[bareContainerID(containerID)]
+	if !exists || !e.hasCoverage || e.modifiedAt.After(e.lastAppendAt) || e.maxChunkSize != opts.MaxChunkSize {
+		return nil, time.Time{}, false
+	}
+
+	// Requested window ends before the covered window
+	if !opts.StopTime.IsZero() && opts.StopTime.Before(e.coveredSince) {
+		return nil, time.Time{}, false
+	}
+
+	var out []LogRecord
+	for i := len(e.records) - 1; i >= 0; i-- {
+		record := e.records[i]
+
+		if !opts.StopTime.IsZero() && record.Timestamp.After(opts.StopTime) {
+			continue
+		}
+
+		if !opts.StartTime.IsZero() && record.Timestamp.Before(opts.StartTime) {
+			break
+		}
+
+		if opts.GrepRegex != nil && !opts.GrepRegex.MatchString(record.Message) {
+			continue
+		}
+
+		if !opts.Filter.Match(record.Message) {
+			continue
+		}
+
+		out = append(out, record)
+	}
+
+	return out, e.coveredSince, true
+}
+
+// CachingLogFetcher implements LogFetcher and BatchLogFetcher by answering
+// backward queries from a RecordCache where possible and passing everything
+// else to the underlying fetcher. Unfiltered follow streams that start at the
+// end of the file are used to keep the cache warm.
+type CachingLogFetcher struct {
+	fetcher   LogFetcher
+	cache     *RecordCache
+	authorize func(ctx context.Context, source LogSource) error
+}
+
+// NewCachingLogFetcher creates a new CachingLogFetcher
+func NewCachingLogFetcher(fetcher LogFetcher, cache *RecordCache) *CachingLogFetcher {
+	return &CachingLogFetcher{fetcher: fetcher, cache: cache}
+}
+
+// WithAuthorizer sets a check that must pass before cached records are
+// served and returns the fetcher. The cache is shared by all callers so
+// sources that fail the check are passed to the underlying fetcher, which
+// enforces the caller's own permissions.
+func (f *CachingLogFetcher) WithAuthorizer(authorize func(ctx context.Context, source LogSource) error) *CachingLogFetcher {
+	f.authorize = authorize
+	return f
+}
+
+// StreamForward returns a channel of LogRecords in chronological order for the given source
+func (f *CachingLogFetcher) StreamForward(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan LogRecord, error) {
+	batchCh, err := f.StreamForwardBatch(ctx, source, opts)
+	if err != nil {
+		return nil, err
+	}
+	return unbatchRecords(ctx, batchCh), nil
+}
+
+// StreamBackward returns a channel of LogRecords in reverse chronological order for the given source
+func (f *CachingLogFetcher) StreamBackward(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan LogRecord, error) {
+	batchCh, err := f.StreamBackwardBatch(ctx, source, opts)
+	if err != nil {
+		return nil, err
+	}
+	return unbatchRecords(ctx, batchCh), nil
+}
+
+// StreamForwardBatch returns a channel of LogRecord batches in chronological order for the given source
+func (f *CachingLogFetcher) StreamForwardBatch(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan []LogRecord, error) {
+	// Only unfiltered follow streams from the end of the file can keep the
+	// cache up to date
+	isWarmer := opts.FollowFrom == FollowFromEnd && opts.StopTime.IsZero() && opts.Grep == "" && opts.Filter == nil && source.ContainerID != ""
+	if !isWarmer {
+		return f.streamForwardBatch(ctx, source, opts)
+	}
+
+	e := f.cache.warm(source.ContainerID, opts.MaxChunkSize)
+	if e == nil {
+		return f.streamForwardBatch(ctx, source, opts)
+	}
+
+	inCh, err := f.streamForwardBatch(ctx, source, opts)
+	if err != nil {
+		f.cache.cool(source.ContainerID, e)
+		return nil, err
+	}
+
+	outCh := make(chan []LogRecord)
+
+	go func() {
+		defer close(outCh)
+		defer f.cache.cool(source.ContainerID, e)
+
+		for batch := range inCh {
+			if batchErr(batch) == nil {
+				f.cache.append(e, batch)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case outCh <- batch:
+			}
+		}
+	}()
+
+	return outCh, nil
+}
+
+// StreamBackwardBatch returns a channel of LogRecord batches in reverse chronological order for the given source
+func (f *CachingLogFetcher) StreamBackwardBatch(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan []LogRecord, error) {
+	// Grep patterns are only evaluated locally if they've been compiled
+	if opts.Grep != "" && opts.GrepRegex == nil {
+		metrics.RecordCacheLookups.WithLabelValues("miss").Inc()
+		return f.streamBackwardBatch(ctx, source, opts)
+	}
+
+	// Only serve cached records to callers that can read the source's logs
+	if f.authorize != nil {
+		if err := f.authorize(ctx, source); err != nil {
+			metrics.RecordCacheLookups.WithLabelValues("miss").Inc()
+			return f.streamBackwardBatch(ctx, source, opts)
+		}
+	}
+
+	records, coveredSince, ok := f.cache.lookup(source.ContainerID, opts)
+	if !ok {
+		metrics.RecordCacheLookups.WithLabelValues("miss").Inc()
+		return f.streamBackwardBatch(ctx, source, opts)
+	}
+
+	// Fetch records older than the covered window from the node unless the
+	// cache already holds as many records as the caller will read
+	needsOlder := opts.StartTime.IsZero() || opts.StartTime.Before(coveredSince)
+	if opts.MaxNum > 0 && int64(len(records)) >= opts.MaxNum {
+		needsOlder = false
+	}
+	if needsOlder {
+		metrics.RecordCacheLookups.WithLabelValues("partial").Inc()
+	} else {
+		metrics.RecordCacheLookups.WithLabelValues("hit").Inc()
+	}
+
+	if r, ok := f.fetcher.(fetchPathRecorder); ok {
+		r.recordFetchPath(source, FetchPathCache)
+	}
+
+	outCh := make(chan []LogRecord)
+
+	// Send batch to output channel
+	send := func(batch []LogRecord) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case outCh <- batch:
+			return true
+		}
+	}
+
+	go func() {
+		defer close(outCh)
+
+		numCached := int64(len(records))
+		for len(records) > 0 {
+			n := min(len(records), maxBatchSize)
+			for i := range records[:n] {
+				records[i].Source = source
+			}
+			if !send(records[:n]) {
+				return
+			}
+			records = records[n:]
+		}
+
+		if !needsOlder {
+			return
+		}
+
+		olderOpts := opts
+		olderOpts.StopTime = coveredSince.Add(-time.Nanosecond)
+		if olderOpts.MaxNum > 0 {
+			olderOpts.MaxNum -= numCached
+		}
+
+		olderCh, err := f.streamBackwardBatch(ctx, source, olderOpts)
+		if err != nil {
+			send(newErrBatch(err))
+			return
+		}
+
+		for batch := range olderCh {
+			if !send(batch) {
+				return
+			}
+		}
+	}()
+
+	return outCh, nil
+}
+
+// Start streaming batches from the underlying fetcher in chronological order
+func (f *CachingLogFetcher) streamForwardBatch(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan []LogRecord, error) {
+	if bf, ok := f.fetcher.(BatchLogFetcher); ok {
+		return bf.StreamForwardBatch(ctx, source, opts)
+	}
+
+	recordCh, err := f.fetcher.StreamForward(ctx, source, opts)
+	if err != nil {
+		return nil, err
+	}
+	return batchRecords(ctx, recordCh), nil
+}
+
+// Start streaming batches from the underlying fetcher in reverse chronological order
+func (f *CachingLogFetcher) streamBackwardBatch(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan []LogRecord, error) {
+	if bf, ok := f.fetcher.(BatchLogFetcher); ok {
+		return bf.StreamBackwardBatch(ctx, source, opts)
+	}
+
+	recordCh, err := f.fetcher.StreamBackward(ctx, source, opts)
+	if err != nil {
+		return nil, err
+	}
+	return batchRecords(ctx, recordCh), nil
+}
+
+// Return container id without the runtime prefix (e.g. "containerd://")
+func bareContainerID(containerID string) string {
+	if _, id, found := strings.Cut(containerID, "://"); found {
+		return id
+	}
+	return containerID
+}
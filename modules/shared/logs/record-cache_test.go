@@ -0,0 +1,283 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachingLogFetcher(t *testing.T) {
+	source := LogSource{Namespace: "ns", PodName: "pod1", ContainerName: "c", ContainerID: "containerd://abc"}
+	ts0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	followOpts := FetcherOptions{FollowFrom: FollowFromEnd}
+
+	// Start follow stream through fetcher and return channel used to feed it
+	startFollow := func(t *testing.T, ctx context.Context, f *CachingLogFetcher, m *mockLogFetcher, opts FetcherOptions) (chan<- LogRecord, <-chan LogRecord) {
+		inCh := make(chan LogRecord)
+		m.On("StreamForward", mock.Anything, source, opts).Return((<-chan LogRecord)(inCh), nil).Once()
+
+		outCh, err := f.StreamForward(ctx, source, opts)
+		require.NoError(t, err)
+		return inCh, outCh
+	}
+
+	// Feed records to follow stream and wait for them to come out the other end
+	feed := func(inCh chan<- LogRecord, outCh <-chan LogRecord, messages ...string) {
+		for i, msg := range messages {
+			ts := ts0.Add(time.Duration(i) * time.Second)
+			inCh <- LogRecord{Timestamp: ts, Message: msg, Source: source}
+			<-outCh
+		}
+	}
+
+	// Read messages from backward stream
+	readBackward := func(t *testing.T, f *CachingLogFetcher, opts FetcherOptions) []string {
+		ch, err := f.StreamBackward(context.Background(), source, opts)
+		require.NoError(t, err)
+
+		var messages []string
+		for r := range ch {
+			require.NoError(t, r.err)
+			messages = append(messages, r.Message)
+		}
+		return messages
+	}
+
+	t.Run("answers tail queries covered by follow stream", func(t *testing.T) {
+		m := &mockLogFetcher{}
+		f := NewCachingLogFetcher(m, NewRecordCache(10, 10))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		inCh, outCh := startFollow(t, ctx, f, m, followOpts)
+		feed(inCh, outCh, "r0", "r1", "error r2", "r3")
+
+		opts := FetcherOptions{StartTime: ts0.Add(time.Second)}
+		assert.Equal(t, []string{"r3", "error r2", "r1"}, readBackward(t, f, opts))
+
+		opts.StopTime = ts0.Add(2 * time.Second)
+		assert.Equal(t, []string{"error r2", "r1"}, readBackward(t, f, opts))
+
+		opts.GrepRegex = regexp.MustCompile("error")
+		opts.Grep = opts.GrepRegex.String()
+		assert.Equal(t, []string{"error r2"}, readBackward(t, f, opts))
+
+		m.AssertNotCalled(t, "StreamBackward", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("passes unauthorized callers to underlying fetcher", func(t *testing.T) {
+		type tokenKey struct{}
+
+		m := &mockLogFetcher{}
+		f := NewCachingLogFetcher(m, NewRecordCache(10, 10)).WithAuthorizer(func(ctx context.Context, s LogSource) error {
+			assert.Equal(t, source, s)
+			if ctx.Value(tokenKey{}) != "allowed" {
+				return errors.New("permission denied")
+			}
+			return nil
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		inCh, outCh := startFollow(t, ctx, f, m, followOpts)
+		feed(inCh, outCh, "r0", "r1")
+
+		opts := FetcherOptions{StartTime: ts0}
+
+		// Caller with access gets cached records
+		allowedCtx := context.WithValue(context.Background(), tokenKey{}, "allowed")
+		ch, err := f.StreamBackward(allowedCtx, source, opts)
+		require.NoError(t, err)
+		var messages []string
+		for r := range ch {
+			messages = append(messages, r.Message)
+		}
+		assert.Equal(t, []string{"r1", "r0"}, messages)
+		m.AssertNotCalled(t, "StreamBackward", mock.Anything, mock.Anything, mock.Anything)
+
+		// Caller without access is passed to the underlying fetcher
+		deniedCh := make(chan LogRecord)
+		close(deniedCh)
+		m.On("StreamBackward", mock.Anything, source, opts).Return((<-chan LogRecord)(deniedCh), nil).Once()
+
+		deniedCtx := context.WithValue(context.Background(), tokenKey{}, "denied")
+		ch, err = f.StreamBackward(deniedCtx, source, opts)
+		require.NoError(t, err)
+		for r := range ch {
+			t.Fatalf("unexpected record: %s", r.Message)
+		}
+		m.AssertExpectations(t)
+	})
+
+	t.Run("fetches records older than covered window", func(t *testing.T) {
+		m := &mockLogFetcher{}
+		f := NewCachingLogFetcher(m, NewRecordCache(10, 10))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		inCh, outCh := startFollow(t, ctx, f, m, followOpts)
+		feed(inCh, outCh, "r0", "r1")
+
+		olderCh := make(chan LogRecord, 1)
+		olderCh <- LogRecord{Timestamp: ts0.Add(-time.Second), Message: "old"}
+		close(olderCh)
+
+		olderOpts := FetcherOptions{StopTime: ts0.Add(-time.Nanosecond)}
+		m.On("StreamBackward", mock.Anything, source, olderOpts).Return((<-chan LogRecord)(olderCh), nil).Once()
+
+		assert.Equal(t, []string{"r1", "r0", "old"}, readBackward(t, f, FetcherOptions{}))
+		m.AssertExpectations(t)
+	})
+
+	t.Run("answers tail queries without start time once cache covers limit", func(t *testing.T) {
+		m := &mockLogFetcher{}
+		f := NewCachingLogFetcher(m, NewRecordCache(10, 10))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		inCh, outCh := startFollow(t, ctx, f, m, followOpts)
+		feed(inCh, outCh, "r0", "r1", "r2")
+
+		assert.Equal(t, []string{"r2", "r1", "r0"}, readBackward(t, f, FetcherOptions{MaxNum: 2}))
+		assert.Equal(t, []string{"r2", "r1", "r0"}, readBackward(t, f, FetcherOptions{MaxNum: 3}))
+		m.AssertNotCalled(t, "StreamBackward", mock.Anything, mock.Anything, mock.Anything)
+
+		// Cache runs short
+		olderCh := make(chan LogRecord, 1)
+		olderCh <- LogRecord{Timestamp: ts0.Add(-time.Second), Message: "old"}
+		close(olderCh)
+
+		olderOpts := FetcherOptions{StopTime: ts0.Add(-time.Nanosecond), MaxNum: 2}
+		m.On("StreamBackward", mock.Anything, source, olderOpts).Return((<-chan LogRecord)(olderCh), nil).Once()
+
+		assert.Equal(t, []string{"r2", "r1", "r0", "old"}, readBackward(t, f, FetcherOptions{MaxNum: 5}))
+		m.AssertExpectations(t)
+	})
+
+	t.Run("passes through when not covered", func(t *testing.T) {
+		m := &mockLogFetcher{}
+		cache := NewRecordCache(10, 10)
+		f := NewCachingLogFetcher(m, cache)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		inCh, outCh := startFollow(t, ctx, f, m, followOpts)
+		feed(inCh, outCh, "r0")
+
+		passThrough := func() []string {
+			ch := make(chan LogRecord, 1)
+			ch <- LogRecord{Message: "node"}
+			close(ch)
+			m.On("StreamBackward", mock.Anything, source, mock.Anything).Return((<-chan LogRecord)(ch), nil).Once()
+			return readBackward(t, f, FetcherOptions{StartTime: ts0})
+		}
+
+		// File modified after last record
+		cache.ObserveModified("abc", time.Now())
+		assert.Equal(t, []string{"node"}, passThrough())
+
+		// Newer record catches up
+		feed(inCh, outCh, "r0")
+		assert.Equal(t, []string{"r0", "r0"}, readBackward(t, f, FetcherOptions{StartTime: ts0}))
+
+		// Log file deleted
+		cache.Invalidate("abc")
+		assert.Equal(t, []string{"node"}, passThrough())
+	})
+
+	t.Run("follow stream end removes entry", func(t *testing.T) {
+		m := &mockLogFetcher{}
+		cache := NewRecordCache(10, 10)
+		f := NewCachingLogFetcher(m, cache)
+
+		inCh, outCh := startFollow(t, context.Background(), f, m, followOpts)
+		feed(inCh, outCh, "r0")
+		assert.Equal(t, 1, cache.Len())
+
+		close(inCh)
+		for range outCh {
+		}
+		assert.Equal(t, 0, cache.Len())
+	})
+
+	t.Run("filtered follow streams don't warm cache", func(t *testing.T) {
+		m := &mockLogFetcher{}
+		cache := NewRecordCache(10, 10)
+		f := NewCachingLogFetcher(m, cache)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		startFollow(t, ctx, f, m, FetcherOptions{FollowFrom: FollowFromEnd, Grep: "error"})
+		startFollow(t, ctx, f, m, FetcherOptions{FollowFrom: FollowFromDefault})
+		assert.Equal(t, 0, cache.Len())
+	})
+
+	t.Run("trims oldest records", func(t *testing.T) {
+		m := &mockLogFetcher{}
+		f := NewCachingLogFetcher(m, NewRecordCache(2, 10))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		inCh, outCh := startFollow(t, ctx, f, m, followOpts)
+		feed(inCh, outCh, "r0", "r1", "r2")
+
+		assert.Equal(t, []string{"r2", "r1"}, readBackward(t, f, FetcherOptions{StartTime: ts0.Add(time.Second)}))
+	})
+
+	t.Run("records cache fetch path", func(t *testing.T) {
+		m := &mockLogFetcher{}
+		composite := (&CompositeLogFetcher{
+			agentFetcher: m,
+			kubeFetcher:  &mockLogFetcher{},
+			hasAgent:     func(ctx context.Context, nodeName string) bool { return true },
+		}).TrackFetchPaths()
+		f := NewCachingLogFetcher(composite, NewRecordCache(10, 10))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		inCh, outCh := startFollow(t, ctx, f, m, followOpts)
+		feed(inCh, outCh, "r0")
+
+		// Cache doesn't override the follow stream's path
+		readBackward(t, f, FetcherOptions{StartTime: ts0})
+		assert.Equal(t, FetchPathAgent, composite.FetchPaths()[source])
+
+		composite.fetchPaths.Delete(source)
+		readBackward(t, f, FetcherOptions{StartTime: ts0})
+		assert.Equal(t, FetchPathCache, composite.FetchPaths()[source])
+	})
+}
+
+func TestBareContainerID(t *testing.T) {
+	assert.Equal(t, "abc", bareContainerID("containerd://abc"))
+	assert.Equal(t, "abc", bareContainerID("abc"))
+}
@@ -0,0 +1,29 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+)
+
+// Sink represents an external destination for log records. Implementations
+// receive records from Stream.Records() in batches and in timestamp order.
+type Sink interface {
+	// Export sends records in a single request
+	Export(ctx context.Context, records []LogRecord) error
+
+	// Close releases resources held by the sink
+	Close() error
+}
@@ -26,6 +26,9 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
@@ -88,6 +91,7 @@ type sourceWatcher struct {
 	nodes         []string
 	containers    []string
 	allContainers bool
+	servingOnly   bool
 
 	allowedNamespaces []string
 	parsedPaths       []parsedPath
@@ -168,6 +172,58 @@ func (w *sourceWatcher) Close() {
 	})
 }
 
+// Return namespaces of the watched paths (for use with events)
+func (w *sourceWatcher) eventNamespaces() []string {
+	namespaces := []string{}
+	for _, pp := range w.parsedPaths {
+		if !slices.Contains(namespaces, pp.Namespace) {
+			namespaces = append(namespaces, pp.Namespace)
+		}
+	}
+	return namespaces
+}
+
+// Check if object is one of the watched workloads or is owned by one of them
+// (for use with events)
+func (w *sourceWatcher) isRelatedObject(ref corev1.ObjectReference) bool {
+	for _, pp := range w.parsedPaths {
+		if pp.Namespace != ref.Namespace {
+			continue
+		}
+		for _, workload := range w.index.GetWorkloads(pp.Namespace, pp.WorkloadType, pp.WorkloadName) {
+			if w.index.IsOwnedBy(workload.GetUID(), ref.UID) {
+				return true
+			}
+		}
+
+		// Services and ingresses don't own their pods
+		if pp.WorkloadType == WorkloadTypeService || pp.WorkloadType == WorkloadTypeIngress {
+			for _, pod := range w.getPods(pp) {
+				if pod.UID == ref.UID {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Return current status of a pod container (for use with lifecycle records)
+func (w *sourceWatcher) containerStatus(namespace string, podName string, containerName string) *corev1.ContainerStatus {
+	for _, workload := range w.index.GetWorkloads(namespace, WorkloadTypePod, podName) {
+		pod, ok := workload.(*corev1.Pod)
+		if !ok {
+			continue
+		}
+		for _, status := range slices.Concat(pod.Status.ContainerStatuses, pod.Status.InitContainerStatuses) {
+			if status.Name == containerName {
+				return &status
+			}
+		}
+	}
+	return nil
+}
+
 // Start background processes
 func (w *sourceWatcher) Start(ctx context.Context) error {
 	set := set.NewSet[fetchTuple]()
@@ -181,6 +237,13 @@ func (w *sourceWatcher) Start(ctx context.Context) error {
 			set.Add(fetchTuple{pp.Namespace, WorkloadTypeReplicaSet})
 		case WorkloadTypeCronJob:
 			set.Add(fetchTuple{pp.Namespace, WorkloadTypeJob})
+		case WorkloadTypeIngress:
+			set.Add(fetchTuple{pp.Namespace, WorkloadTypeService})
+		}
+
+		// Track endpoints to determine serving pods
+		if w.servingOnly && (pp.WorkloadType == WorkloadTypeService || pp.WorkloadType == WorkloadTypeIngress) {
+			set.Add(fetchTuple{pp.Namespace, WorkloadTypeEndpointSlice})
 		}
 
 		// Always get pods
@@ -303,9 +366,9 @@ func (w *sourceWatcher) handleWorkloadUpdate(oldObj any, newObj any) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	// Only update pods
+	// Only update pods and objects used to select pods
 	switch newObj.(type) {
-	case *corev1.Pod:
+	case *corev1.Pod, *corev1.Service, *networkingv1.Ingress, *discoveryv1.EndpointSlice:
 		w.index.Update(newObj)
 
 		if w.isReady {
@@ -387,67 +450,65 @@ func (w *sourceWatcher) updateSources_UNSAFE() {
 	wantSources := set.NewSet[LogSource]()
 
 	for _, pp := range w.parsedPaths {
-		for _, workload := range w.index.GetWorkloads(pp.Namespace, pp.WorkloadType, pp.WorkloadName) {
-			for _, pod := range w.index.GetPodsOwnedByWorkload(workload.GetUID()) {
-				wantName := pp.ContainerName
-				for n, status := range slices.Concat(pod.Status.ContainerStatuses, pod.Status.InitContainerStatuses) {
-					// Wait until we have an ID
-					if status.ContainerID == "" {
-						continue
-					}
+		for _, pod := range w.getPods(pp) {
+			wantName := pp.ContainerName
+			for n, status := range slices.Concat(pod.Status.ContainerStatuses, pod.Status.InitContainerStatuses) {
+				// Wait until we have an ID
+				if status.ContainerID == "" {
+					continue
+				}
 
-					// Filter by container
-					k := fmt.Sprintf("%s:%s/%s", pod.Namespace, pod.Name, status.Name)
-					if len(w.containers) > 0 && !slices.Contains(w.containers, k) {
-						continue
-					}
+				// Filter by container
+				k := fmt.Sprintf("%s:%s/%s", pod.Namespace, pod.Name, status.Name)
+				if len(w.containers) > 0 && !slices.Contains(w.containers, k) {
+					continue
+				}
 
-					// Ensure node is available
-					node, exists := w.nodeMap[pod.Spec.NodeName]
-					if !exists {
-						continue
-					}
+				// Ensure node is available
+				node, exists := w.nodeMap[pod.Spec.NodeName]
+				if !exists {
+					continue
+				}
 
-					// Filter by node
-					if len(w.nodes) > 0 && !slices.Contains(w.nodes, node.Name) {
-						continue
-					}
+				// Filter by node
+				if len(w.nodes) > 0 && !slices.Contains(w.nodes, node.Name) {
+					continue
+				}
 
-					// Filter by region
-					if len(w.regions) > 0 && !slices.Contains(w.regions, node.Labels["topology.kubernetes.io/region"]) {
-						continue
-					}
+				// Filter by region
+				if len(w.regions) > 0 && !slices.Contains(w.regions, node.Labels["topology.kubernetes.io/region"]) {
+					continue
+				}
 
-					// Filter by zone
-					if len(w.zones) > 0 && !slices.Contains(w.zones, node.Labels["topology.kubernetes.io/zone"]) {
-						continue
-					}
+				// Filter by zone
+				if len(w.zones) > 0 && !slices.Contains(w.zones, node.Labels["topology.kubernetes.io/zone"]) {
+					continue
+				}
 
-					// Filter by os
-					if len(w.oses) > 0 && !slices.Contains(w.oses, node.Status.NodeInfo.OperatingSystem) {
-						continue
-					}
+				// Filter by os
+				if len(w.oses) > 0 && !slices.Contains(w.oses, node.Status.NodeInfo.OperatingSystem) {
+					continue
+				}
 
-					// Filter by arch
-					if len(w.arches) > 0 && !slices.Contains(w.arches, node.Status.NodeInfo.Architecture) {
-						continue
-					}
+				// Filter by arch
+				if len(w.arches) > 0 && !slices.Contains(w.arches, node.Status.NodeInfo.Architecture) {
+					continue
+				}
 
-					if wantName == "*" || wantName == status.Name || (wantName == "" && n == 0) {
-						wantSources.Add(LogSource{
-							Metadata: LogSourceMetadata{
-								Region: node.Labels["topology.kubernetes.io/region"],
-								Zone:   node.Labels["topology.kubernetes.io/zone"],
-								OS:     node.Status.NodeInfo.OperatingSystem,
-								Arch:   node.Status.NodeInfo.Architecture,
-								Node:   pod.Spec.NodeName,
-							},
-							Namespace:     pod.Namespace,
-							PodName:       pod.Name,
-							ContainerName: status.Name,
-							ContainerID:   status.ContainerID,
-						})
-					}
+				if wantName == "*" || wantName == status.Name || (wantName == "" && n == 0) {
+					wantSources.Add(LogSource{
+						Metadata: LogSourceMetadata{
+							Region: node.Labels["topology.kubernetes.io/region"],
+							Zone:   node.Labels["topology.kubernetes.io/zone"],
+							OS:     node.Status.NodeInfo.OperatingSystem,
+							Arch:   node.Status.NodeInfo.Architecture,
+							Node:   pod.Spec.NodeName,
+						},
+						Namespace:     pod.Namespace,
+						PodName:       pod.Name,
+						ContainerName: status.Name,
+						ContainerID:   status.ContainerID,
+					})
 				}
 			}
 		}
@@ -468,6 +529,87 @@ func (w *sourceWatcher) updateSources_UNSAFE() {
 	w.sources = wantSources
 }
 
+// Return pods matching parsed path
+func (w *sourceWatcher) getPods(pp parsedPath) []*corev1.Pod {
+	pods := []*corev1.Pod{}
+
+	switch pp.WorkloadType {
+	case WorkloadTypeService:
+		for _, workload := range w.index.GetWorkloads(pp.Namespace, WorkloadTypeService, pp.WorkloadName) {
+			if service, ok := workload.(*corev1.Service); ok {
+				pods = append(pods, w.index.GetPodsForService(service, w.servingOnly)...)
+			}
+		}
+	case WorkloadTypeIngress:
+		serviceNames := set.NewSet[string]()
+		for _, workload := range w.index.GetWorkloads(pp.Namespace, WorkloadTypeIngress, pp.WorkloadName) {
+			if ingress, ok := workload.(*networkingv1.Ingress); ok {
+				serviceNames.Append(ingressServiceNames(ingress)...)
+			}
+		}
+
+		seen := set.NewSet[types.UID]()
+		for _, serviceName := range serviceNames.ToSlice() {
+			for _, workload := range w.index.GetWorkloads(pp.Namespace, WorkloadTypeService, serviceName) {
+				service, ok := workload.(*corev1.Service)
+				if !ok {
+					continue
+				}
+				for _, pod := range w.index.GetPodsForService(service, w.servingOnly) {
+					if seen.Add(pod.UID) {
+						pods = append(pods, pod)
+					}
+				}
+			}
+		}
+	default:
+		for _, workload := range w.index.GetWorkloads(pp.Namespace, pp.WorkloadType, pp.WorkloadName) {
+			pods = append(pods, w.index.GetPodsOwnedByWorkload(workload.GetUID())...)
+		}
+	}
+
+	return pods
+}
+
+// Return names of services referenced by ingress backends
+func ingressServiceNames(ingress *networkingv1.Ingress) []string {
+	names := []string{}
+
+	addBackend := func(backend *networkingv1.IngressBackend) {
+		if backend != nil && backend.Service != nil && !slices.Contains(names, backend.Service.Name) {
+			names = append(names, backend.Service.Name)
+		}
+	}
+
+	addBackend(ingress.Spec.DefaultBackend)
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			addBackend(&path.Backend)
+		}
+	}
+
+	return names
+}
+
+// Return the unique namespaces referenced by source paths
+func SourceNamespaces(sourcePaths []string, defaultNamespace string) ([]string, error) {
+	namespaces := []string{}
+	for _, p := range sourcePaths {
+		pp, err := parsePath(p, defaultNamespace, false)
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(namespaces, pp.Namespace) {
+			namespaces = append(namespaces, pp.Namespace)
+		}
+	}
+	return namespaces, nil
+}
+
 // Represents result of parsePath()
 type parsedPath struct {
 	Namespace     string
@@ -613,6 +755,67 @@ func (wi *workloadIndex) GetPodsOwnedByWorkload(workloadID types.UID) []*corev1.
 	return pods
 }
 
+// Get pods selected by a service. If `servingOnly` is true, only pods that
+// are serving endpoints in the service's EndpointSlices are returned.
+func (wi *workloadIndex) GetPodsForService(service *corev1.Service, servingOnly bool) []*corev1.Pod {
+	wi.mu.RLock()
+	defer wi.mu.RUnlock()
+
+	// Get serving pod names from endpoint slices
+	var servingPods set.Set[string]
+	if servingOnly {
+		servingPods = set.NewSet[string]()
+		for _, sliceID := range wi.getIDs_UNSAFE(service.Namespace, WorkloadTypeEndpointSlice) {
+			slice, ok := wi.dataMap[sliceID].(*discoveryv1.EndpointSlice)
+			if !ok || slice.Labels[discoveryv1.LabelServiceName] != service.Name {
+				continue
+			}
+			for _, endpoint := range slice.Endpoints {
+				if endpoint.TargetRef == nil || endpoint.TargetRef.Kind != "Pod" || !isServingEndpoint(endpoint) {
+					continue
+				}
+				servingPods.Add(endpoint.TargetRef.Name)
+			}
+		}
+	}
+
+	// Services without selectors can only be resolved via endpoints
+	selector := labels.SelectorFromSet(service.Spec.Selector)
+	hasSelector := len(service.Spec.Selector) > 0
+
+	pods := []*corev1.Pod{}
+	for _, podID := range wi.getIDs_UNSAFE(service.Namespace, WorkloadTypePod) {
+		pod, ok := wi.dataMap[podID].(*corev1.Pod)
+		if !ok {
+			continue
+		}
+
+		if servingOnly {
+			if servingPods.ContainsOne(pod.Name) {
+				pods = append(pods, pod)
+			}
+		} else if hasSelector && selector.Matches(labels.Set(pod.Labels)) {
+			pods = append(pods, pod)
+		}
+	}
+
+	return pods
+}
+
+// Check if object is the given owner or a descendant of it
+func (wi *workloadIndex) IsOwnedBy(ownerID types.UID, objID types.UID) bool {
+	wi.mu.RLock()
+	defer wi.mu.RUnlock()
+	return wi.isOwnedBy_UNSAFE(ownerID, objID)
+}
+
+// Get object by id
+func (wi *workloadIndex) Get(objID types.UID) any {
+	wi.mu.RLock()
+	defer wi.mu.RUnlock()
+	return wi.dataMap[objID]
+}
+
 // Add workload object to index
 func (wi *workloadIndex) Add(obj any) error {
 	wi.mu.Lock()
@@ -679,6 +882,15 @@ func (wi *workloadIndex) Add(obj any) error {
 		for _, ownerRef := range v.OwnerReferences {
 			wi.ownershipMap.Add(ownerRef.UID, v.UID)
 		}
+	case *corev1.Service:
+		k = wi.generateDataKey(v.Namespace, WorkloadTypeService)
+		objID = v.UID
+	case *networkingv1.Ingress:
+		k = wi.generateDataKey(v.Namespace, WorkloadTypeIngress)
+		objID = v.UID
+	case *discoveryv1.EndpointSlice:
+		k = wi.generateDataKey(v.Namespace, WorkloadTypeEndpointSlice)
+		objID = v.UID
 	default:
 		return fmt.Errorf("not implemented")
 	}
@@ -714,6 +926,12 @@ func (wi *workloadIndex) Update(obj any) error {
 		objID = v.UID
 	case *appsv1.StatefulSet:
 		objID = v.UID
+	case *corev1.Service:
+		objID = v.UID
+	case *networkingv1.Ingress:
+		objID = v.UID
+	case *discoveryv1.EndpointSlice:
+		objID = v.UID
 	default:
 		return fmt.Errorf("not implemented")
 	}
@@ -791,6 +1009,15 @@ func (wi *workloadIndex) Remove(obj any) error {
 		for _, ownerRef := range v.OwnerReferences {
 			wi.ownershipMap.Remove(ownerRef.UID, v.UID)
 		}
+	case *corev1.Service:
+		k = wi.generateDataKey(v.Namespace, WorkloadTypeService)
+		objID = v.UID
+	case *networkingv1.Ingress:
+		k = wi.generateDataKey(v.Namespace, WorkloadTypeIngress)
+		objID = v.UID
+	case *discoveryv1.EndpointSlice:
+		k = wi.generateDataKey(v.Namespace, WorkloadTypeEndpointSlice)
+		objID = v.UID
 	default:
 		return fmt.Errorf("not implemented")
 	}
@@ -804,6 +1031,15 @@ func (wi *workloadIndex) Remove(obj any) error {
 	return nil
 }
 
+// Get ids of objects of a given type in a namespace
+func (wi *workloadIndex) getIDs_UNSAFE(namespace string, t WorkloadType) []types.UID {
+	objIDs, exists := wi.listMap.Get(wi.generateDataKey(namespace, t))
+	if !exists {
+		return nil
+	}
+	return objIDs.ToSlice()
+}
+
 // Return key for use with data map
 func (wi *workloadIndex) generateDataKey(namespace string, t WorkloadType) string {
 	return fmt.Sprintf("%s:%s", namespace, t.String())
@@ -826,3 +1062,34 @@ func (wi *workloadIndex) getLeafIDs_UNSAFE(nodeID types.UID) []types.UID {
 
 	return leaves
 }
+
+// Recursively check ownership map
+func (wi *workloadIndex) isOwnedBy_UNSAFE(ownerID types.UID, objID types.UID) bool {
+	if ownerID == objID {
+		return true
+	}
+
+	children, exists := wi.ownershipMap.Get(ownerID)
+	if !exists {
+		return false
+	}
+
+	found := false
+	children.Each(func(childID types.UID) bool {
+		found = wi.isOwnedBy_UNSAFE(childID, objID)
+		return found // stop when found
+	})
+
+	return found
+}
+
+// Check if endpoint is serving traffic (nil conditions mean serving)
+func isServingEndpoint(endpoint discoveryv1.Endpoint) bool {
+	if endpoint.Conditions.Serving != nil {
+		return *endpoint.Conditions.Serving
+	}
+	if endpoint.Conditions.Ready != nil {
+		return *endpoint.Conditions.Ready
+	}
+	return true
+}
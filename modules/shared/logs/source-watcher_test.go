@@ -24,8 +24,11 @@ import (
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 )
 
 func TestNewSourceWatcher(t *testing.T) {
@@ -521,6 +524,17 @@ func TestParsePath(t *testing.T) {
 	}
 }
 
+func TestSourceNamespaces(t *testing.T) {
+	namespaces, err := SourceNamespaces([]string{
+		"pod-123",
+		"ns1:deployments/web",
+		"ns1:pod-456/container-1",
+		"ns2:pod-789",
+	}, "default")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"default", "ns1", "ns2"}, namespaces)
+}
+
 func TestNewWorkloadIndex(t *testing.T) {
 	wi := newWorkloadIndex()
 	assert.NotNil(t, wi)
@@ -990,3 +1004,156 @@ func TestUpdateSourcesWithNodeFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateSourcesWithServicesAndIngresses(t *testing.T) {
+	// Mock data
+	mockNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node1",
+		},
+	}
+
+	newPod := func(name string, app string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				UID:       types.UID(name + "-uid"),
+				Labels:    map[string]string{"app": app},
+			},
+			Spec: corev1.PodSpec{
+				NodeName: "node1",
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:        "container1",
+						ContainerID: name + "-container1-id",
+					},
+				},
+			},
+		}
+	}
+
+	newSource := func(podName string) LogSource {
+		return LogSource{
+			Metadata:      LogSourceMetadata{Node: "node1"},
+			Namespace:     "default",
+			PodName:       podName,
+			ContainerName: "container1",
+			ContainerID:   podName + "-container1-id",
+		}
+	}
+
+	newService := func(name string, app string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				UID:       types.UID(name + "-uid"),
+			},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{"app": app},
+			},
+		}
+	}
+
+	mockIngress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ingress1",
+			Namespace: "default",
+			UID:       "ingress1-uid",
+		},
+		Spec: networkingv1.IngressSpec{
+			DefaultBackend: &networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{Name: "web"},
+			},
+			Rules: []networkingv1.IngressRule{
+				{
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "api"}}},
+								{Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "web"}}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	mockEndpointSlice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-abc",
+			Namespace: "default",
+			UID:       "web-abc-uid",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "web"},
+		},
+		Endpoints: []discoveryv1.Endpoint{
+			{
+				TargetRef:  &corev1.ObjectReference{Kind: "Pod", Name: "web-1"},
+				Conditions: discoveryv1.EndpointConditions{Serving: ptr.To(true)},
+			},
+			{
+				TargetRef:  &corev1.ObjectReference{Kind: "Pod", Name: "web-2"},
+				Conditions: discoveryv1.EndpointConditions{Serving: ptr.To(false)},
+			},
+		},
+	}
+
+	objs := []any{
+		newPod("web-1", "web"),
+		newPod("web-2", "web"),
+		newPod("api-1", "api"),
+		newPod("other-1", "other"),
+		newService("web", "web"),
+		newService("api", "api"),
+		mockIngress,
+		mockEndpointSlice,
+	}
+
+	// Table-driven tests
+	tests := []struct {
+		name           string
+		setPath        string
+		setServingOnly bool
+		wantSources    []LogSource
+	}{
+		{"service", "default:services/web", false, []LogSource{newSource("web-1"), newSource("web-2")}},
+		{"service serving only", "default:svc/web", true, []LogSource{newSource("web-1")}},
+		{"all services", "default:services/*", false, []LogSource{newSource("web-1"), newSource("web-2"), newSource("api-1")}},
+		{"ingress", "default:ingresses/ingress1", false, []LogSource{newSource("web-1"), newSource("web-2"), newSource("api-1")}},
+		{"ingress serving only", "default:ing/ingress1", true, []LogSource{newSource("web-1")}},
+		{"missing service", "default:services/missing", false, []LogSource{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init connection Manager
+			cm := &k8shelpersmock.MockConnectionManager{}
+			cm.On("GetDefaultNamespace", mock.Anything).Return("default")
+
+			// Initialize source watcher
+			w, err := NewSourceWatcher(cm, []string{tt.setPath}, WithServingOnly(tt.setServingOnly))
+			require.NoError(t, err)
+
+			sw := w.(*sourceWatcher)
+			sw.isReady = true
+
+			// Add node
+			sw.handleNodeAdd(mockNode)
+
+			// Add objects to the index
+			for _, obj := range objs {
+				require.NoError(t, sw.index.Add(obj))
+			}
+
+			// Call updateSources_UNSAFE
+			sw.updateSources_UNSAFE()
+
+			// Verify results
+			assert.ElementsMatch(t, tt.wantSources, sw.sources.ToSlice())
+		})
+	}
+}
@@ -16,25 +16,36 @@ package logs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"sort"
 	"sync"
 	"time"
 
 	set "github.com/deckarep/golang-set/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/metrics"
 )
 
 const DEFAULT_MAX_CHUNK_SIZE = 16 * 1024 // 16 KB
 
+// ErrMaxSourcesExceeded is returned when a stream matches more sources than allowed
+var ErrMaxSourcesExceeded = errors.New("max sources exceeded")
+
 // LogRecord represents a log record
 type LogRecord struct {
 	Timestamp time.Time
 	Message   string
 	Source    LogSource
-	err       error // for use internally
+	Event     *KubeEvent // set if record is a Kubernetes event
+	Lifecycle *Lifecycle // set if record is a synthetic lifecycle marker
+	err       error      // for use internally
 }
 
 // streamMode enum type
@@ -56,6 +67,7 @@ type Stream struct {
 	follow    bool
 	grep      string
 	grepRegex *regexp.Regexp
+	filter    *Filter
 
 	rootCtx       context.Context
 	rootCtxCancel context.CancelFunc
@@ -65,15 +77,28 @@ type Stream struct {
 	sources set.Set[LogSource]
 
 	maxChunkSize int
+	maxSources   int
 
 	kubeContext string
+	bearerToken string
+	cm          k8shelpers.ConnectionManager
 	sw          SourceWatcher
 	logFetcher  LogFetcher
 
+	withEvents    bool
+	pastEvents    []LogRecord
+	eventsCh      chan *corev1.Event
+	eventCleanups []func()
+
+	withLifecycle   bool
+	lifecycleQueue  []lifecycleItem
+	lifecycleMu     sync.Mutex
+	lifecycleWakeCh chan struct{}
+
 	isStarted bool
 	futureWG  sync.WaitGroup
-	pastCh    chan LogRecord
-	futureCh  chan LogRecord
+	pastCh    chan []LogRecord
+	futureCh  chan []LogRecord
 	outCh     chan LogRecord
 	err       error
 	mu        sync.Mutex
@@ -82,6 +107,7 @@ type Stream struct {
 	closeFutureChOnce sync.Once
 	closeOutChOnce    sync.Once
 	setErrorOnce      sync.Once
+	closeMetricsOnce  sync.Once
 }
 
 // Initialize new stream
@@ -90,13 +116,16 @@ func NewStream(ctx context.Context, cm k8shelpers.ConnectionManager, sourcePaths
 
 	// Init stream instance
 	stream := &Stream{
-		rootCtx:       rootCtx,
-		rootCtxCancel: rootCtxCancel,
-		sources:       set.NewSet[LogSource](),
-		maxChunkSize:  DEFAULT_MAX_CHUNK_SIZE,
-		pastCh:        make(chan LogRecord),
-		futureCh:      make(chan LogRecord),
-		outCh:         make(chan LogRecord),
+		rootCtx:         rootCtx,
+		rootCtxCancel:   rootCtxCancel,
+		cm:              cm,
+		sources:         set.NewSet[LogSource](),
+		maxChunkSize:    DEFAULT_MAX_CHUNK_SIZE,
+		pastCh:          make(chan []LogRecord),
+		futureCh:        make(chan []LogRecord),
+		outCh:           make(chan LogRecord),
+		eventsCh:        make(chan *corev1.Event, eventsChSize),
+		lifecycleWakeCh: make(chan struct{}, 1),
 	}
 
 	// Apply options
@@ -136,7 +165,10 @@ func NewStream(ctx context.Context, cm k8shelpers.ConnectionManager, sourcePaths
 
 // Start log fetchers and other background processes
 // TODO: make this idempodent
-func (s *Stream) Start(ctx context.Context) error {
+func (s *Stream) Start(ctx context.Context) (err error) {
+	ctx, span := tracer.Start(ctx, "logs.Stream.Start")
+	defer func() { endSpan(span, err) }()
+
 	// Add source watcher event handlers
 	s.sw.Subscribe(SourceWatcherEventAdded, s.handleSourceAdd)
 	s.sw.Subscribe(SourceWatcherEventDeleted, s.handleSourceDelete)
@@ -151,6 +183,19 @@ func (s *Stream) Start(ctx context.Context) error {
 
 	// Initialize log streams
 	s.sources = s.sw.Set()
+	span.SetAttributes(attribute.Int("logs.sources", s.sources.Cardinality()))
+
+	// Check source limit
+	if s.maxSources > 0 && s.sources.Cardinality() > s.maxSources {
+		return fmt.Errorf("%w: %d sources matched (limit %d)", ErrMaxSourcesExceeded, s.sources.Cardinality(), s.maxSources)
+	}
+
+	// Start event informers
+	if s.withEvents {
+		if err := s.startEvents_UNSAFE(ctx); err != nil {
+			return err
+		}
+	}
 
 	// Start past fetchers
 	switch s.mode {
@@ -170,6 +215,12 @@ func (s *Stream) Start(ctx context.Context) error {
 			return err
 		}
 
+		// Forward lifecycle records in background
+		if s.withLifecycle {
+			s.futureWG.Add(1)
+			go s.runLifecycleForwarder()
+		}
+
 		// Close future channel after writers have finished
 		go func() {
 			<-s.rootCtx.Done()
@@ -184,6 +235,10 @@ func (s *Stream) Start(ctx context.Context) error {
 	// Update isStarted flag
 	s.isStarted = true
 
+	// Update metrics
+	metrics.StreamsOpen.Inc()
+	metrics.StreamSources.Add(float64(s.sources.Cardinality()))
+
 	return nil
 }
 
@@ -215,65 +270,120 @@ func (s *Stream) Close() {
 	// Stop background processes
 	s.rootCtxCancel()
 
+	// Remove event informer handlers
+	s.mu.Lock()
+	for _, cleanup := range s.eventCleanups {
+		cleanup()
+	}
+	s.eventCleanups = nil
+	s.mu.Unlock()
+
 	// Close output channel
 	s.closeOutCh()
+
+	// Update metrics
+	s.closeMetricsOnce.Do(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.isStarted {
+			metrics.StreamsOpen.Dec()
+			metrics.StreamSources.Sub(float64(s.sources.Cardinality()))
+		}
+	})
 }
 
 // Handle source ADDED event
 func (s *Stream) handleSourceAdd(source LogSource) {
+	// Look up container status outside of lock
+	var status *corev1.ContainerStatus
+	if s.withLifecycle {
+		status = s.sourceContainerStatus(source)
+	}
+
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	_, skipped := s.addSource_UNSAFE(source, status)
+	if skipped && s.withLifecycle && s.follow {
+		s.queueLifecycleRecord(newSourceSkippedRecord(source, s.maxSources))
+	}
+	s.mu.Unlock()
+
+	if skipped {
+		metrics.StreamSourcesSkipped.Inc()
+	}
+}
 
+func (s *Stream) addSource_UNSAFE(source LogSource, status *corev1.ContainerStatus) (added bool, skipped bool) {
 	// Check isStarted flag
 	if !s.isStarted {
-		return
+		return false, false
 	}
 
 	// Exit if already exists
 	if s.sources.ContainsOne(source) {
-		return
+		return false, false
+	}
+
+	// Ignore new sources once limit has been reached
+	if s.maxSources > 0 && s.sources.Cardinality() >= s.maxSources {
+		return false, true
 	}
 
 	// Stream from beginning and keep following
 	opts := FetcherOptions{
 		Grep:         s.grep,
 		GrepRegex:    s.grepRegex,
+		Filter:       s.filter,
 		FollowFrom:   FollowFromDefault,
 		MaxChunkSize: s.maxChunkSize,
 	}
 
-	stream, err := s.logFetcher.StreamForward(s.rootCtx, source, opts)
+	stream, err := s.streamForward(s.rootCtx, source, opts)
 	if err != nil {
 		s.setError_UNSAFE(err)
-		return
+		return false, false
+	}
+
+	// Queue marker so it's emitted ahead of the source's records
+	var markerSent <-chan struct{}
+	if s.withLifecycle && s.follow {
+		markerSent = s.queueLifecycleRecord(newSourceAddedRecord(source, status))
 	}
 
-	// Forward records in goroutine
+	// Forward batches in goroutine
 	s.futureWG.Add(1)
 	go func() {
 		defer s.futureWG.Done()
 
+		// Wait for marker
+		if markerSent != nil {
+			select {
+			case <-s.rootCtx.Done():
+				return
+			case <-markerSent:
+			}
+		}
+
 		for {
 			select {
 			case <-s.rootCtx.Done():
 				return
-			case record, ok := <-stream:
+			case batch, ok := <-stream:
 				if !ok {
 					return
 				}
 
 				// Check for errors
-				if record.err != nil {
-					s.setError_SAFE(record.err)
+				if err := batchErr(batch); err != nil {
+					s.setError_SAFE(err)
 					s.rootCtxCancel() // Kill the entire stream
 					return
 				}
 
-				// Send record
+				// Send batch
 				select {
 				case <-s.rootCtx.Done():
 					return
-				case s.futureCh <- record:
+				case s.futureCh <- batch:
 					// Sent successfully
 				}
 			}
@@ -282,20 +392,34 @@ func (s *Stream) handleSourceAdd(source LogSource) {
 
 	// Add to sources
 	s.sources.Add(source)
+	metrics.StreamSources.Inc()
+
+	return true, false
 }
 
 // Handle source DELETED event
 func (s *Stream) handleSourceDelete(source LogSource) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 
 	// Check isStarted flag
 	if !s.isStarted {
+		s.mu.Unlock()
 		return
 	}
 
 	// Remove from sources
-	s.sources.Remove(source)
+	removed := false
+	if s.sources.ContainsOne(source) {
+		s.sources.Remove(source)
+		metrics.StreamSources.Dec()
+		removed = true
+	}
+
+	// Restarts are reported when the new container is added
+	if removed && s.withLifecycle && s.follow && !s.isReplacedSource(source) {
+		s.queueLifecycleRecord(newSourceRemovedRecord(source))
+	}
+	s.mu.Unlock()
 }
 
 // Start fetching log records in `head` mode
@@ -307,12 +431,13 @@ func (s *Stream) startHead_UNSAFE() error {
 		StopTime:     s.untilTime,
 		Grep:         s.grep,
 		GrepRegex:    s.grepRegex,
+		Filter:       s.filter,
 		MaxChunkSize: s.maxChunkSize,
 	}
 
-	streams := make([]<-chan LogRecord, s.sources.Cardinality())
+	streams := make([]<-chan []LogRecord, s.sources.Cardinality())
 	for i, source := range s.sources.ToSlice() {
-		stream, err := s.logFetcher.StreamForward(ctx, source, opts)
+		stream, err := s.streamForward(ctx, source, opts)
 		if err != nil {
 			cancel()
 			return err
@@ -320,31 +445,46 @@ func (s *Stream) startHead_UNSAFE() error {
 		streams[i] = stream
 	}
 
+	// Interleave events
+	if len(s.pastEvents) > 0 {
+		streams = append(streams, recordsChan(ctx, s.pastEvents))
+	}
+
 	// Process in goroutine
 	go func() {
 		defer s.closePastCh()
 		defer cancel()
+		defer observeFetchDuration("head", time.Now())
 
 		N := int(s.maxNum)
 		var count int
 
-		for record := range mergeLogStreams(ctx, false, streams...) {
+		for batch := range mergeLogBatches(ctx, false, streams...) {
 			// Handle errors
-			if record.err != nil {
-				s.setError_SAFE(record.err)
+			if err := batchErr(batch); err != nil {
+				s.setError_SAFE(err)
 				return
 			}
 
+			// Trim batch if we have enough records
+			isLast := s.mode != streamModeAll && count+len(batch) >= N
+			if isLast {
+				batch = batch[:N-count]
+			}
+
 			// Write out
-			select {
-			case <-ctx.Done():
-				return
-			case s.pastCh <- record:
+			if len(batch) > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case s.pastCh <- batch:
+				}
 			}
 
-			count += 1
+			count += len(batch)
+
 			// Exit loop if we have enough records
-			if s.mode != streamModeAll && count >= N {
+			if isLast {
 				break
 			}
 		}
@@ -368,13 +508,15 @@ func (s *Stream) startTail_UNSAFE() error {
 		StopTime:      s.untilTime,
 		Grep:          s.grep,
 		GrepRegex:     s.grepRegex,
+		Filter:        s.filter,
 		BatchSizeHint: batchSize,
 		MaxChunkSize:  s.maxChunkSize,
+		MaxNum:        s.maxNum,
 	}
 
-	streams := make([]<-chan LogRecord, s.sources.Cardinality())
+	streams := make([]<-chan []LogRecord, s.sources.Cardinality())
 	for i, source := range s.sources.ToSlice() {
-		stream, err := s.logFetcher.StreamBackward(ctx, source, opts)
+		stream, err := s.streamBackward(ctx, source, opts)
 		if err != nil {
 			cancel()
 			return err
@@ -382,36 +524,45 @@ func (s *Stream) startTail_UNSAFE() error {
 		streams[i] = stream
 	}
 
+	// Interleave events (newest first)
+	if len(s.pastEvents) > 0 && s.maxNum > 0 {
+		reversed := slices.Clone(s.pastEvents)
+		slices.Reverse(reversed)
+		streams = append(streams, recordsChan(ctx, reversed))
+	}
+
 	// Process in goroutine
 	go func() {
 		defer s.closePastCh()
 		defer cancel()
+		defer observeFetchDuration("tail", time.Now())
 
 		N := int(s.maxNum)
-		var count int
 		tailRecords := []LogRecord{}
 
-		for record := range mergeLogStreams(ctx, true, streams...) {
+		for batch := range mergeLogBatches(ctx, true, streams...) {
 			// Handle errors
-			if record.err != nil {
-				s.setError_SAFE(record.err)
+			if err := batchErr(batch); err != nil {
+				s.setError_SAFE(err)
 				return
 			}
 
-			tailRecords = append(tailRecords, record)
-			count += 1
+			tailRecords = append(tailRecords, batch...)
 
-			if count >= N {
+			if len(tailRecords) >= N {
+				tailRecords = tailRecords[:N]
 				break
 			}
 		}
 
-		// Send the tail records in reverse order
-		for i := len(tailRecords) - 1; i >= 0; i-- {
+		// Send the tail records in chronological order
+		slices.Reverse(tailRecords)
+
+		for batch := range slices.Chunk(tailRecords, maxBatchSize) {
 			select {
 			case <-ctx.Done():
 				return
-			case s.pastCh <- tailRecords[i]:
+			case s.pastCh <- batch:
 			}
 		}
 	}()
@@ -429,21 +580,22 @@ func (s *Stream) startFollow_UNSAFE() error {
 		StopTime:     s.untilTime,
 		Grep:         s.grep,
 		GrepRegex:    s.grepRegex,
+		Filter:       s.filter,
 		FollowFrom:   FollowFromEnd,
 		MaxChunkSize: s.maxChunkSize,
 	}
 
 	for _, source := range s.sources.ToSlice() {
-		stream, err := s.logFetcher.StreamForward(ctx, source, opts)
+		stream, err := s.streamForward(ctx, source, opts)
 		if err != nil {
 			cancel() // stop all fetchers
 			return err
 		}
 
-		// Forward records in goroutine
+		// Forward batches in goroutine
 		wg.Add(1)
 		s.futureWG.Add(1)
-		go func(stream <-chan LogRecord) {
+		go func(stream <-chan []LogRecord) {
 			defer s.futureWG.Done()
 			defer wg.Done()
 
@@ -451,23 +603,23 @@ func (s *Stream) startFollow_UNSAFE() error {
 				select {
 				case <-ctx.Done():
 					return
-				case record, ok := <-stream:
+				case batch, ok := <-stream:
 					if !ok {
 						return
 					}
 
 					// Check for errors
-					if record.err != nil {
-						s.setError_SAFE(record.err)
+					if err := batchErr(batch); err != nil {
+						s.setError_SAFE(err)
 						s.rootCtxCancel()
 						return
 					}
 
-					// Send record
+					// Send batch
 					select {
 					case <-ctx.Done():
 						return
-					case s.futureCh <- record:
+					case s.futureCh <- batch:
 						// Sent successfully
 					}
 				}
@@ -497,26 +649,29 @@ LOOP:
 		select {
 		case <-s.rootCtx.Done():
 			return // exit
-		case r, ok := <-s.pastCh:
+		case batch, ok := <-s.pastCh:
 			if !ok {
 				break LOOP
 			}
 
-			// Save reference to last record
-			lastTSMap[r.Source] = r.Timestamp
+			for _, r := range batch {
+				// Save reference to last record
+				lastTSMap[r.Source] = r.Timestamp
 
-			// Write out
-			select {
-			case <-s.rootCtx.Done():
-				return // exit
-			case s.outCh <- r:
-				// Sent successfully
+				// Write out
+				select {
+				case <-s.rootCtx.Done():
+					return // exit
+				case s.outCh <- r:
+					// Sent successfully
+					recordForwarded(r)
+				}
 			}
-		case r, ok := <-s.futureCh:
+		case batch, ok := <-s.futureCh:
 			if !ok {
 				return // exit
 			}
-			buffer = append(buffer, r)
+			buffer = append(buffer, batch...)
 		}
 	}
 
@@ -532,11 +687,11 @@ DrainLoop:
 			return // exit
 		case <-drainCtx.Done():
 			break DrainLoop
-		case r, ok := <-s.futureCh:
+		case batch, ok := <-s.futureCh:
 			if !ok {
 				break DrainLoop
 			}
-			buffer = append(buffer, r)
+			buffer = append(buffer, batch...)
 		default:
 			break DrainLoop
 		}
@@ -572,21 +727,70 @@ DrainLoop:
 			return // exit
 		case s.outCh <- r:
 			// Sent successfully
+			recordForwarded(r)
 		}
 	}
 	buffer = nil // clear buffer
 
 	// Step 4: any new future events now go directly to out
 	// if futureEvents got closed earlier, the loop will just exit
-	for r := range s.futureCh {
-		// Write out
-		select {
-		case <-s.rootCtx.Done():
-			return // exit
-		case s.outCh <- r:
-			// Sent successfully
+	for batch := range s.futureCh {
+		for _, r := range batch {
+			// Write out
+			select {
+			case <-s.rootCtx.Done():
+				return // exit
+			case s.outCh <- r:
+				// Sent successfully
+				recordForwarded(r)
+			}
+		}
+	}
+}
+
+// Start streaming batches of records from source in chronological order
+func (s *Stream) streamForward(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan []LogRecord, error) {
+	ctx, span := tracer.Start(ctx, "logs.LogFetcher.StreamForward", trace.WithAttributes(sourceAttributes(source)...))
+	span.SetAttributes(attribute.String("logs.follow_from", string(opts.FollowFrom)))
+
+	var stream <-chan []LogRecord
+	var err error
+	if f, ok := s.logFetcher.(BatchLogFetcher); ok {
+		stream, err = f.StreamForwardBatch(ctx, source, opts)
+	} else {
+		var recordCh <-chan LogRecord
+		if recordCh, err = s.logFetcher.StreamForward(ctx, source, opts); err == nil {
+			stream = batchRecords(ctx, recordCh)
+		}
+	}
+
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+	return traceBatches(ctx, span, stream), nil
+}
+
+// Start streaming batches of records from source in reverse chronological order
+func (s *Stream) streamBackward(ctx context.Context, source LogSource, opts FetcherOptions) (<-chan []LogRecord, error) {
+	ctx, span := tracer.Start(ctx, "logs.LogFetcher.StreamBackward", trace.WithAttributes(sourceAttributes(source)...))
+
+	var stream <-chan []LogRecord
+	var err error
+	if f, ok := s.logFetcher.(BatchLogFetcher); ok {
+		stream, err = f.StreamBackwardBatch(ctx, source, opts)
+	} else {
+		var recordCh <-chan LogRecord
+		if recordCh, err = s.logFetcher.StreamBackward(ctx, source, opts); err == nil {
+			stream = batchRecords(ctx, recordCh)
 		}
 	}
+
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+	return traceBatches(ctx, span, stream), nil
 }
 
 // Set error and close channels if needed
@@ -623,3 +827,14 @@ func (s *Stream) closeOutCh() {
 		close(s.outCh)
 	})
 }
+
+// Update forwarded-records metrics
+func recordForwarded(r LogRecord) {
+	metrics.RecordsForwarded.Inc()
+	metrics.BytesForwarded.Add(float64(len(r.Message)))
+}
+
+// Record time taken to fetch past records
+func observeFetchDuration(mode string, startedAt time.Time) {
+	metrics.FetchDuration.WithLabelValues(mode).Observe(time.Since(startedAt).Seconds())
+}
@@ -22,12 +22,14 @@ import (
 	"time"
 
 	set "github.com/deckarep/golang-set/v2"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"k8s.io/client-go/kubernetes/fake"
 
 	k8shelpersmock "github.com/kubetail-org/kubetail/modules/shared/k8shelpers/mock"
+	"github.com/kubetail-org/kubetail/modules/shared/metrics"
 )
 
 // filterRecords filters a slice of LogRecords by start and stop times
@@ -1150,3 +1152,109 @@ func TestStreamErrorHandling(t *testing.T) {
 		assert.Contains(t, stream.Err().Error(), expectedError.Error())
 	})
 }
+
+func TestStreamMetrics(t *testing.T) {
+	s1 := LogSource{Namespace: "ns1", PodName: "pod1", ContainerName: "container1"}
+	s2 := LogSource{Namespace: "ns2", PodName: "pod2", ContainerName: "container2"}
+
+	ts := time.Date(2025, 3, 13, 11, 46, 1, 0, time.UTC)
+	logs1 := []LogRecord{{Source: s1, Timestamp: ts, Message: "hello"}}
+	logs2 := []LogRecord{{Source: s2, Timestamp: ts.Add(time.Second), Message: "world!"}}
+
+	// Init mock logFetcher
+	m := mockLogFetcher{}
+	m.On("StreamForward", mock.Anything, s1, mock.Anything).Return(newForwardChannel(logs1, time.Time{}, time.Time{}), nil)
+	m.On("StreamForward", mock.Anything, s2, mock.Anything).Return(newForwardChannel(logs2, time.Time{}, time.Time{}), nil)
+
+	// Init mock source watcher
+	sw := mockSourceWatcher{}
+	sw.On("Start", mock.Anything).Return(nil)
+	sw.On("Set").Return(set.NewSet(s1, s2))
+	sw.On("Subscribe", mock.Anything, mock.Anything).Return()
+	sw.On("Unsubscribe", mock.Anything, mock.Anything).Return()
+
+	// Init connection manager
+	cm := &k8shelpersmock.MockConnectionManager{}
+	cm.On("GetOrCreateClientset", mock.Anything).Return(&fake.Clientset{}, nil)
+	cm.On("GetDefaultNamespace", mock.Anything).Return("default")
+
+	streamsBefore := promtestutil.ToFloat64(metrics.StreamsOpen)
+	sourcesBefore := promtestutil.ToFloat64(metrics.StreamSources)
+	recordsBefore := promtestutil.ToFloat64(metrics.RecordsForwarded)
+	bytesBefore := promtestutil.ToFloat64(metrics.BytesForwarded)
+
+	stream, err := NewStream(context.Background(), cm, []string{}, WithAll())
+	require.NoError(t, err)
+
+	stream.sw = &sw
+	stream.logFetcher = &m
+
+	err = stream.Start(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, streamsBefore+1, promtestutil.ToFloat64(metrics.StreamsOpen))
+	assert.Equal(t, sourcesBefore+2, promtestutil.ToFloat64(metrics.StreamSources))
+
+	for range stream.Records() {
+	}
+
+	assert.Equal(t, recordsBefore+2, promtestutil.ToFloat64(metrics.RecordsForwarded))
+	assert.Equal(t, bytesBefore+11, promtestutil.ToFloat64(metrics.BytesForwarded))
+
+	// Close is idempotent
+	stream.Close()
+	stream.Close()
+
+	assert.Equal(t, streamsBefore, promtestutil.ToFloat64(metrics.StreamsOpen))
+	assert.Equal(t, sourcesBefore, promtestutil.ToFloat64(metrics.StreamSources))
+}
+
+func TestStreamMaxSources(t *testing.T) {
+	s1 := LogSource{Namespace: "ns1", PodName: "pod1", ContainerName: "container1"}
+	s2 := LogSource{Namespace: "ns2", PodName: "pod2", ContainerName: "container2"}
+
+	tests := []struct {
+		name       string
+		maxSources int
+		wantErr    bool
+	}{
+		{"unlimited", 0, false},
+		{"under limit", 2, false},
+		{"over limit", 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Init mock logFetcher
+			m := mockLogFetcher{}
+			m.On("StreamForward", mock.Anything, mock.Anything, mock.Anything).Return(newForwardChannel(nil, time.Time{}, time.Time{}), nil)
+
+			// Init mock source watcher
+			sw := mockSourceWatcher{}
+			sw.On("Start", mock.Anything).Return(nil)
+			sw.On("Set").Return(set.NewSet(s1, s2))
+			sw.On("Subscribe", mock.Anything, mock.Anything).Return()
+			sw.On("Unsubscribe", mock.Anything, mock.Anything).Return()
+
+			// Init connection manager
+			cm := &k8shelpersmock.MockConnectionManager{}
+			cm.On("GetOrCreateClientset", mock.Anything).Return(&fake.Clientset{}, nil)
+			cm.On("GetDefaultNamespace", mock.Anything).Return("default")
+
+			stream, err := NewStream(context.Background(), cm, []string{}, WithAll(), WithMaxSources(tt.maxSources))
+			require.NoError(t, err)
+			defer stream.Close()
+
+			stream.sw = &sw
+			stream.logFetcher = &m
+
+			err = stream.Start(context.Background())
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrMaxSourcesExceeded)
+				m.AssertNotCalled(t, "StreamForward", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
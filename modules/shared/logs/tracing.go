@@ -0,0 +1,81 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/kubetail-org/kubetail/modules/shared/logs")
+
+// Return span attributes describing a source
+func sourceAttributes(source LogSource) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("k8s.namespace.name", source.Namespace),
+		attribute.String("k8s.pod.name", source.PodName),
+		attribute.String("k8s.container.name", source.ContainerName),
+		attribute.String("k8s.node.name", source.Metadata.Node),
+	}
+}
+
+// Record error on span (if any) and end it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// traceBatches forwards batches from inCh and ends the span when the stream
+// ends. If the span isn't recording the input channel is returned as-is.
+func traceBatches(ctx context.Context, span trace.Span, inCh <-chan []LogRecord) <-chan []LogRecord {
+	if !span.IsRecording() {
+		span.End()
+		return inCh
+	}
+
+	outCh := make(chan []LogRecord)
+
+	go func() {
+		defer close(outCh)
+
+		var count int
+		var err error
+		defer func() {
+			span.SetAttributes(attribute.Int("logs.records", count))
+			endSpan(span, err)
+		}()
+
+		for batch := range inCh {
+			if err = batchErr(batch); err == nil {
+				count += len(batch)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case outCh <- batch:
+			}
+		}
+	}()
+
+	return outCh
+}
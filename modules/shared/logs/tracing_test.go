@@ -0,0 +1,93 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	set "github.com/deckarep/golang-set/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/client-go/kubernetes/fake"
+
+	k8shelpersmock "github.com/kubetail-org/kubetail/modules/shared/k8shelpers/mock"
+)
+
+func TestStreamSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	source := LogSource{Namespace: "ns1", PodName: "pod1", ContainerName: "container1"}
+	ts := time.Date(2025, 3, 13, 11, 46, 1, 0, time.UTC)
+	records := []LogRecord{
+		{Source: source, Timestamp: ts, Message: "a"},
+		{Source: source, Timestamp: ts.Add(time.Second), Message: "b"},
+	}
+
+	// Init mock log fetcher and source watcher
+	m := mockLogFetcher{}
+	m.On("StreamForward", mock.Anything, source, mock.Anything).Return(newForwardChannel(records, time.Time{}, time.Time{}), nil)
+
+	sw := mockSourceWatcher{}
+	sw.On("Start", mock.Anything).Return(nil)
+	sw.On("Set").Return(set.NewSet(source))
+	sw.On("Subscribe", mock.Anything, mock.Anything).Return()
+	sw.On("Unsubscribe", mock.Anything, mock.Anything).Return()
+	sw.On("Shutdown", mock.Anything).Return(nil)
+
+	cm := &k8shelpersmock.MockConnectionManager{}
+	cm.On("GetOrCreateClientset", mock.Anything).Return(&fake.Clientset{}, nil)
+	cm.On("GetDefaultNamespace", mock.Anything).Return("default")
+
+	// Run stream inside a parent span
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+
+	stream, err := NewStream(ctx, cm, []string{}, WithAll())
+	require.NoError(t, err)
+	defer stream.Close()
+
+	stream.sw = &sw
+	stream.logFetcher = &m
+
+	require.NoError(t, stream.Start(ctx))
+	for range stream.Records() {
+	}
+	parent.End()
+
+	// Check spans
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+
+	startSpan := spans["logs.Stream.Start"]
+	require.NotNil(t, startSpan)
+	assert.Equal(t, parent.SpanContext().TraceID(), startSpan.SpanContext().TraceID())
+	assert.Equal(t, parent.SpanContext().SpanID(), startSpan.Parent().SpanID())
+	assert.Contains(t, startSpan.Attributes(), attribute.Int("logs.sources", 1))
+
+	forwardSpan := spans["logs.LogFetcher.StreamForward"]
+	require.NotNil(t, forwardSpan)
+	assert.Equal(t, parent.SpanContext().TraceID(), forwardSpan.SpanContext().TraceID())
+	assert.Contains(t, forwardSpan.Attributes(), attribute.String("k8s.pod.name", "pod1"))
+	assert.Contains(t, forwardSpan.Attributes(), attribute.Int("logs.records", 2))
+}
@@ -0,0 +1,298 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+)
+
+// UsageGroupBy enum type
+type UsageGroupBy string
+
+const (
+	UsageGroupByNamespace UsageGroupBy = "NAMESPACE"
+	UsageGroupByWorkload  UsageGroupBy = "WORKLOAD"
+	UsageGroupByNode      UsageGroupBy = "NODE"
+	UsageGroupByContainer UsageGroupBy = "CONTAINER"
+)
+
+// LogFileUsage represents the size of a container log file on a node
+type LogFileUsage struct {
+	ContainerID   string
+	NodeName      string
+	Namespace     string
+	PodName       string
+	ContainerName string
+	Size          int64
+}
+
+// UsageGroup represents the log volume of a group of log files. Only the
+// fields used for grouping are set.
+type UsageGroup struct {
+	Namespace      string
+	WorkloadKind   string
+	WorkloadName   string
+	NodeName       string
+	ContainerName  string
+	Bytes          int64
+	BytesPerSecond *float64 // nil if no file in the group has a growth rate yet
+	FileCount      int
+}
+
+// Represents the fields used to group log files
+type usageGroupKey struct {
+	namespace     string
+	workloadKind  string
+	workloadName  string
+	nodeName      string
+	containerName string
+}
+
+// Represents a file size observation
+type usageSample struct {
+	ts   time.Time
+	size int64
+}
+
+// UsageTracker records log file sizes over time to compute growth rates
+type UsageTracker struct {
+	window  time.Duration
+	samples map[string][]usageSample
+	mu      sync.Mutex
+}
+
+// NewUsageTracker creates a new UsageTracker that computes growth rates over
+// the given window
+func NewUsageTracker(window time.Duration) *UsageTracker {
+	return &UsageTracker{
+		window:  window,
+		samples: make(map[string][]usageSample),
+	}
+}
+
+// Observe records the size of a log file at the given time
+func (t *UsageTracker) Observe(file LogFileUsage, ts time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.prune_UNSAFE(ts)
+
+	samples := t.samples[file.ContainerID]
+
+	// Start over if the file was rotated
+	if n := len(samples); n > 0 && file.Size < samples[n-1].size {
+		samples = nil
+	}
+
+	t.samples[file.ContainerID] = append(samples, usageSample{ts, file.Size})
+}
+
+// Forget removes the samples of a deleted log file
+func (t *UsageTracker) Forget(containerID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.samples, containerID)
+}
+
+// GrowthRate returns the growth rate of a log file in bytes per second. The
+// second return value is false if fewer than two samples were observed
+// within the window.
+func (t *UsageTracker) GrowthRate(containerID string) (float64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	samples := t.samples[containerID]
+	if len(samples) < 2 {
+		return 0, false
+	}
+
+	first, last := samples[0], samples[len(samples)-1]
+	dt := last.ts.Sub(first.ts).Seconds()
+	if dt <= 0 {
+		return 0, false
+	}
+
+	return float64(last.size-first.size) / dt, true
+}
+
+// Drop samples that fell out of the window
+func (t *UsageTracker) prune_UNSAFE(now time.Time) {
+	cutoff := now.Add(-t.window)
+	for k, samples := range t.samples {
+		i := 0
+		for i < len(samples) && samples[i].ts.Before(cutoff) {
+			i++
+		}
+
+		if i == len(samples) {
+			delete(t.samples, k)
+		} else if i > 0 {
+			t.samples[k] = samples[i:]
+		}
+	}
+}
+
+// WorkloadResolver maps pods to the top-level workloads that own them
+type WorkloadResolver struct {
+	index *workloadIndex
+}
+
+// NewWorkloadResolver creates a new WorkloadResolver from the pods, replica
+// sets and jobs in the given namespaces
+func NewWorkloadResolver(ctx context.Context, cm k8shelpers.ConnectionManager, kubeContext string, token string, namespaces []string) (*WorkloadResolver, error) {
+	index := newWorkloadIndex()
+
+	for _, namespace := range namespaces {
+		for _, t := range []WorkloadType{WorkloadTypePod, WorkloadTypeReplicaSet, WorkloadTypeJob} {
+			informer, start, err := cm.NewInformer(ctx, kubeContext, token, namespace, t.GVR())
+			if err != nil {
+				return nil, err
+			}
+
+			// Start informer
+			start()
+
+			// Wait for cache to sync
+			if !cache.WaitForCacheSync(ctx.Done(), informer.Informer().HasSynced) {
+				return nil, fmt.Errorf("cache did not sync")
+			}
+
+			for _, obj := range informer.Informer().GetStore().List() {
+				index.Add(obj)
+			}
+		}
+	}
+
+	return &WorkloadResolver{index: index}, nil
+}
+
+// Resolve returns the kind and name of the workload that owns a pod. Pods
+// without a controller (or that no longer exist) are their own workload.
+func (r *WorkloadResolver) Resolve(namespace string, podName string) (string, string) {
+	pods := r.index.GetWorkloads(namespace, WorkloadTypePod, podName)
+	if len(pods) == 0 {
+		return WorkloadTypePod.String(), podName
+	}
+
+	pod, ok := pods[0].(*corev1.Pod)
+	if !ok {
+		return WorkloadTypePod.String(), podName
+	}
+
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return WorkloadTypePod.String(), podName
+	}
+
+	// Walk up to deployments and cronjobs
+	var owner *metav1.OwnerReference
+	switch obj := r.index.Get(ref.UID).(type) {
+	case *appsv1.ReplicaSet:
+		owner = metav1.GetControllerOf(obj)
+	case *batchv1.Job:
+		owner = metav1.GetControllerOf(obj)
+	}
+
+	if owner != nil {
+		return owner.Kind, owner.Name
+	}
+
+	return ref.Kind, ref.Name
+}
+
+// SummarizeUsage aggregates log file sizes and growth rates. The tracker and
+// resolver are optional. Groups are sorted by size, largest first.
+func SummarizeUsage(files []LogFileUsage, groupBy UsageGroupBy, tracker *UsageTracker, resolver *WorkloadResolver) []UsageGroup {
+	groups := map[usageGroupKey]*UsageGroup{}
+
+	for _, file := range files {
+		var k usageGroupKey
+
+		switch groupBy {
+		case UsageGroupByNamespace:
+			k.namespace = file.Namespace
+		case UsageGroupByNode:
+			k.nodeName = file.NodeName
+		case UsageGroupByWorkload, UsageGroupByContainer:
+			k.namespace = file.Namespace
+			k.workloadKind, k.workloadName = WorkloadTypePod.String(), file.PodName
+			if resolver != nil {
+				k.workloadKind, k.workloadName = resolver.Resolve(file.Namespace, file.PodName)
+			}
+			if groupBy == UsageGroupByContainer {
+				k.containerName = file.ContainerName
+			}
+		}
+
+		g, exists := groups[k]
+		if !exists {
+			g = &UsageGroup{
+				Namespace:     k.namespace,
+				WorkloadKind:  k.workloadKind,
+				WorkloadName:  k.workloadName,
+				NodeName:      k.nodeName,
+				ContainerName: k.containerName,
+			}
+			groups[k] = g
+		}
+
+		g.Bytes += file.Size
+		g.FileCount += 1
+
+		if tracker == nil {
+			continue
+		}
+
+		if rate, ok := tracker.GrowthRate(file.ContainerID); ok {
+			if g.BytesPerSecond == nil {
+				g.BytesPerSecond = new(float64)
+			}
+			*g.BytesPerSecond += rate
+		}
+	}
+
+	out := make([]UsageGroup, 0, len(groups))
+	for _, g := range groups {
+		out = append(out, *g)
+	}
+
+	slices.SortFunc(out, func(a, b UsageGroup) int {
+		if c := cmp.Compare(b.Bytes, a.Bytes); c != 0 {
+			return c
+		}
+		return cmp.Or(
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.WorkloadKind, b.WorkloadKind),
+			cmp.Compare(a.WorkloadName, b.WorkloadName),
+			cmp.Compare(a.NodeName, b.NodeName),
+			cmp.Compare(a.ContainerName, b.ContainerName),
+		)
+	})
+
+	return out
+}
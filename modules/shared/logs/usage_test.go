@@ -0,0 +1,156 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func TestUsageTrackerGrowthRate(t *testing.T) {
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := NewUsageTracker(5 * time.Minute)
+
+	file := LogFileUsage{ContainerID: "c1", Size: 1000}
+
+	// Single sample
+	tracker.Observe(file, ts)
+	_, ok := tracker.GrowthRate("c1")
+	assert.False(t, ok)
+
+	// Two samples
+	file.Size = 3000
+	tracker.Observe(file, ts.Add(10*time.Second))
+	rate, ok := tracker.GrowthRate("c1")
+	require.True(t, ok)
+	assert.Equal(t, 200.0, rate)
+
+	// Rotation starts over
+	file.Size = 100
+	tracker.Observe(file, ts.Add(20*time.Second))
+	_, ok = tracker.GrowthRate("c1")
+	assert.False(t, ok)
+
+	// Old samples fall out of the window
+	file.Size = 700
+	tracker.Observe(file, ts.Add(6*time.Minute))
+	_, ok = tracker.GrowthRate("c1")
+	assert.False(t, ok)
+
+	// Forget
+	tracker.Forget("c1")
+	assert.Empty(t, tracker.samples)
+}
+
+func TestWorkloadResolverResolve(t *testing.T) {
+	deploymentRef := metav1.OwnerReference{Kind: "Deployment", Name: "web", UID: "d1", Controller: ptr.To(true)}
+	cronJobRef := metav1.OwnerReference{Kind: "CronJob", Name: "backup", UID: "cj1", Controller: ptr.To(true)}
+	daemonSetRef := metav1.OwnerReference{Kind: "DaemonSet", Name: "agent", UID: "ds1", Controller: ptr.To(true)}
+
+	index := newWorkloadIndex()
+	index.Add(&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "web-abc", UID: "rs1", OwnerReferences: []metav1.OwnerReference{deploymentRef}}})
+	index.Add(&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "solo", UID: "rs2"}})
+	index.Add(&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "backup-123", UID: "j1", OwnerReferences: []metav1.OwnerReference{cronJobRef}}})
+
+	addPod := func(name string, uid string, owner *metav1.OwnerReference) {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: name, UID: types.UID(uid)}}
+		if owner != nil {
+			pod.OwnerReferences = []metav1.OwnerReference{*owner}
+		}
+		index.Add(pod)
+	}
+
+	addPod("web-abc-1", "p1", &metav1.OwnerReference{Kind: "ReplicaSet", Name: "web-abc", UID: "rs1", Controller: ptr.To(true)})
+	addPod("solo-1", "p2", &metav1.OwnerReference{Kind: "ReplicaSet", Name: "solo", UID: "rs2", Controller: ptr.To(true)})
+	addPod("backup-123-1", "p3", &metav1.OwnerReference{Kind: "Job", Name: "backup-123", UID: "j1", Controller: ptr.To(true)})
+	addPod("agent-1", "p4", &daemonSetRef)
+	addPod("bare", "p5", nil)
+
+	r := &WorkloadResolver{index: index}
+
+	tests := []struct {
+		name     string
+		setPod   string
+		wantKind string
+		wantName string
+	}{
+		{"deployment", "web-abc-1", "Deployment", "web"},
+		{"replicaset without owner", "solo-1", "ReplicaSet", "solo"},
+		{"cronjob", "backup-123-1", "CronJob", "backup"},
+		{"daemonset", "agent-1", "DaemonSet", "agent"},
+		{"bare pod", "bare", "Pod", "bare"},
+		{"missing pod", "gone", "Pod", "gone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, name := r.Resolve("ns1", tt.setPod)
+			assert.Equal(t, tt.wantKind, kind)
+			assert.Equal(t, tt.wantName, name)
+		})
+	}
+}
+
+func TestSummarizeUsage(t *testing.T) {
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	files := []LogFileUsage{
+		{ContainerID: "c1", NodeName: "node1", Namespace: "ns1", PodName: "web-1", ContainerName: "app", Size: 100},
+		{ContainerID: "c2", NodeName: "node1", Namespace: "ns1", PodName: "web-1", ContainerName: "sidecar", Size: 50},
+		{ContainerID: "c3", NodeName: "node2", Namespace: "ns2", PodName: "db-1", ContainerName: "app", Size: 400},
+	}
+
+	tracker := NewUsageTracker(5 * time.Minute)
+	tracker.Observe(LogFileUsage{ContainerID: "c1", Size: 0}, ts)
+	tracker.Observe(LogFileUsage{ContainerID: "c1", Size: 100}, ts.Add(10*time.Second))
+
+	t.Run("namespace", func(t *testing.T) {
+		groups := SummarizeUsage(files, UsageGroupByNamespace, tracker, nil)
+		require.Len(t, groups, 2)
+		assert.Equal(t, UsageGroup{Namespace: "ns2", Bytes: 400, FileCount: 1}, groups[0])
+		assert.Equal(t, UsageGroup{Namespace: "ns1", Bytes: 150, FileCount: 2, BytesPerSecond: ptr.To(10.0)}, groups[1])
+	})
+
+	t.Run("node", func(t *testing.T) {
+		groups := SummarizeUsage(files, UsageGroupByNode, nil, nil)
+		require.Len(t, groups, 2)
+		assert.Equal(t, UsageGroup{NodeName: "node2", Bytes: 400, FileCount: 1}, groups[0])
+		assert.Equal(t, UsageGroup{NodeName: "node1", Bytes: 150, FileCount: 2}, groups[1])
+	})
+
+	t.Run("workload", func(t *testing.T) {
+		groups := SummarizeUsage(files, UsageGroupByWorkload, nil, nil)
+		require.Len(t, groups, 2)
+		assert.Equal(t, UsageGroup{Namespace: "ns2", WorkloadKind: "Pod", WorkloadName: "db-1", Bytes: 400, FileCount: 1}, groups[0])
+		assert.Equal(t, UsageGroup{Namespace: "ns1", WorkloadKind: "Pod", WorkloadName: "web-1", Bytes: 150, FileCount: 2}, groups[1])
+	})
+
+	t.Run("container", func(t *testing.T) {
+		groups := SummarizeUsage(files, UsageGroupByContainer, nil, nil)
+		require.Len(t, groups, 3)
+		assert.Equal(t, "app", groups[0].ContainerName)
+		assert.Equal(t, int64(100), groups[1].Bytes)
+		assert.Equal(t, "sidecar", groups[2].ContainerName)
+	})
+}
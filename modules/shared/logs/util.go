@@ -119,12 +119,13 @@ func (ts *ThreadSafeSlice[T]) Len() int {
 	return len(ts.slice)
 }
 
-// recordWithSource wraps a LogRecord and also keeps track of which stream
-// channel it came from, so when we pop from the heap we know where
-// to pull the next entry.
+// recordWithSource wraps a LogRecord and also keeps track of the rest of
+// its batch and which stream channel it came from, so when we pop from the
+// heap we know where to pull the next entry.
 type recordWithSource struct {
 	record LogRecord
-	srcCh  <-chan LogRecord
+	rest   []LogRecord
+	srcCh  <-chan []LogRecord
 }
 
 // priorityQueue implements heap.Interface. The "less" comparison
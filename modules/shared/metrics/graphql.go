@@ -0,0 +1,67 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// SubscriptionTracker is a gqlgen extension that keeps the
+// ActiveSubscriptions gauge up to date
+type SubscriptionTracker struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+} = SubscriptionTracker{}
+
+// ExtensionName
+func (SubscriptionTracker) ExtensionName() string {
+	return "SubscriptionTracker"
+}
+
+// Validate
+func (SubscriptionTracker) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptOperation
+func (SubscriptionTracker) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+	if oc == nil || oc.Operation == nil || oc.Operation.Operation != ast.Subscription {
+		return next(ctx)
+	}
+
+	// Use name of first top-level field
+	name := "unknown"
+	if len(oc.Operation.SelectionSet) > 0 {
+		if field, ok := oc.Operation.SelectionSet[0].(*ast.Field); ok {
+			name = field.Name
+		}
+	}
+
+	// Subscription context is canceled when the client unsubscribes
+	gauge := ActiveSubscriptions.WithLabelValues(name)
+	gauge.Inc()
+	go func() {
+		<-ctx.Done()
+		gauge.Dec()
+	}()
+
+	return next(ctx)
+}
@@ -0,0 +1,173 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metric name prefix
+const namespace = "kubetail"
+
+// Registry holds process-wide metrics shared by all components
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	// Active GraphQL subscriptions by subscription field
+	ActiveSubscriptions = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "graphql",
+		Name:      "active_subscriptions",
+		Help:      "Number of active GraphQL subscriptions.",
+	}, []string{"subscription"})
+
+	// GraphQL operations rejected by query limits
+	RejectedOperations = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "graphql",
+		Name:      "rejected_operations_total",
+		Help:      "Total number of GraphQL operations rejected by query limits.",
+	}, []string{"reason"})
+
+	// GraphQL operation complexity
+	OperationComplexity = factory.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "graphql",
+		Name:      "operation_complexity",
+		Help:      "Complexity score of GraphQL operations.",
+		Buckets:   []float64{10, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 50000},
+	})
+
+	// Open log streams
+	StreamsOpen = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "logs",
+		Name:      "streams_open",
+		Help:      "Number of open log streams.",
+	})
+
+	// Sources across all open log streams
+	StreamSources = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "logs",
+		Name:      "stream_sources",
+		Help:      "Number of sources being read by open log streams.",
+	})
+
+	// Sources ignored by running log streams because of the source limit
+	StreamSourcesSkipped = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "logs",
+		Name:      "stream_sources_skipped_total",
+		Help:      "Number of new sources ignored by open log streams because of the source limit.",
+	})
+
+	// Upstream follow streams shared by subscriptions
+	FollowUpstreams = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "logs",
+		Name:      "follow_upstreams",
+		Help:      "Number of upstream follow streams shared by log subscriptions.",
+	})
+
+	// Backward fetches checked against the recent-records cache
+	RecordCacheLookups = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "logs",
+		Name:      "record_cache_lookups_total",
+		Help:      "Total number of backward log fetches checked against the recent-records cache by result (hit, partial or miss).",
+	}, []string{"result"})
+
+	// Records exported by log forwarders
+	ExportedRecords = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "logs",
+		Name:      "exported_records_total",
+		Help:      "Total number of log records exported by log forwarders by result (sent or dropped).",
+	}, []string{"result"})
+
+	// Records forwarded to clients
+	RecordsForwarded = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "logs",
+		Name:      "records_forwarded_total",
+		Help:      "Total number of log records forwarded to clients.",
+	})
+
+	// Message bytes forwarded to clients
+	BytesForwarded = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "logs",
+		Name:      "bytes_forwarded_total",
+		Help:      "Total number of log message bytes forwarded to clients.",
+	})
+
+	// Time taken to fetch past records (head/tail)
+	FetchDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "logs",
+		Name:      "fetch_duration_seconds",
+		Help:      "Time taken to fetch past log records.",
+		Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"mode"})
+
+	// Errors returned by cluster agents
+	AgentErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cluster_agent",
+		Name:      "errors_total",
+		Help:      "Total number of errors returned by cluster agents. The agent label is the node name, or the agent address for fan-out requests.",
+	}, []string{"agent", "method"})
+
+	// Sources read from the Kubernetes API because their node has no agent
+	AgentFallbacks = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cluster_agent",
+		Name:      "fallbacks_total",
+		Help:      "Total number of log sources read from the Kubernetes API because no cluster agent was available on their node.",
+	}, []string{"node"})
+
+	// Cluster API health-monitor state
+	HealthStatus = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "cluster_api",
+		Name:      "health_status",
+		Help:      "Health of the Cluster API as seen by the dashboard health monitor (1 for the current status).",
+	}, []string{"kube_context", "namespace", "service", "status"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler returns an http handler that serves the shared registry along with
+// any app-specific collectors
+func Handler(extra ...prometheus.Collector) http.Handler {
+	local := prometheus.NewRegistry()
+	local.MustRegister(extra...)
+
+	gatherers := prometheus.Gatherers{Registry, local}
+	return promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{})
+}
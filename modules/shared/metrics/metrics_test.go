@@ -0,0 +1,91 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestHandler(t *testing.T) {
+	extra := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_extra_gauge", Help: "test"})
+	extra.Set(42)
+
+	StreamsOpen.Set(0)
+
+	server := httptest.NewServer(Handler(extra))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), "test_extra_gauge 42")
+	assert.Contains(t, string(body), "kubetail_logs_streams_open 0")
+	assert.Contains(t, string(body), "go_goroutines")
+}
+
+func TestSubscriptionTracker(t *testing.T) {
+	tracker := SubscriptionTracker{}
+
+	newCtx := func(op ast.Operation) (context.Context, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ctx = graphql.WithOperationContext(ctx, &graphql.OperationContext{
+			Operation: &ast.OperationDefinition{
+				Operation:    op,
+				SelectionSet: ast.SelectionSet{&ast.Field{Name: "testSubscription"}},
+			},
+		})
+		return ctx, cancel
+	}
+
+	next := func(ctx context.Context) graphql.ResponseHandler {
+		return func(ctx context.Context) *graphql.Response { return nil }
+	}
+
+	gauge := ActiveSubscriptions.WithLabelValues("testSubscription")
+
+	// Queries are ignored
+	ctx, cancel := newCtx(ast.Query)
+	tracker.InterceptOperation(ctx, next)
+	assert.Equal(t, float64(0), promtestutil.ToFloat64(gauge))
+	cancel()
+
+	// Subscriptions are tracked until context is canceled
+	ctx, cancel = newCtx(ast.Subscription)
+	tracker.InterceptOperation(ctx, next)
+	assert.Equal(t, float64(1), promtestutil.ToFloat64(gauge))
+
+	cancel()
+	assert.Eventually(t, func() bool {
+		return promtestutil.ToFloat64(gauge) == 0
+	}, time.Second, 10*time.Millisecond)
+}
@@ -15,18 +15,39 @@
 package middleware
 
 import (
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// Access-log options
+type AccessLogOptions struct {
+	Enabled          bool
+	HideHealthChecks bool
+}
+
 // Log HTTP requests
 func LoggingMiddleware(hideHealthChecks bool) gin.HandlerFunc {
+	return DynamicLoggingMiddleware(func() AccessLogOptions {
+		return AccessLogOptions{Enabled: true, HideHealthChecks: hideHealthChecks}
+	})
+}
+
+// Log HTTP requests using options that are read on every request so that
+// access-logging can be changed at runtime
+func DynamicLoggingMiddleware(opts func() AccessLogOptions) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if hideHealthChecks && strings.HasSuffix(c.Request.URL.Path, "/healthz") {
+		o := opts()
+		if !o.Enabled || (o.HideHealthChecks && strings.HasSuffix(c.Request.URL.Path, "/healthz")) {
 			c.Next()
 			return
 		}
@@ -67,6 +88,47 @@ func LoggingMiddleware(hideHealthChecks bool) gin.HandlerFunc {
 	}
 }
 
+// Start a span for each HTTP request (except health checks), continuing the
+// caller's trace if the request carries trace context headers
+func TracingMiddleware() gin.HandlerFunc {
+	tracer := otel.Tracer("github.com/kubetail-org/kubetail/modules/shared/middleware")
+
+	return func(c *gin.Context) {
+		if strings.HasSuffix(c.Request.URL.Path, "/healthz") {
+			c.Next()
+			return
+		}
+
+		// Use route instead of path to keep span names low-cardinality
+		route := c.FullPath()
+		if route == "" {
+			route = "unknown"
+		}
+
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx, span := tracer.Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("request_id", requestid.Get(c)),
+			),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+
+		// execute request
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
 // Add far-future expires cache headers
 func CacheControlMiddleware(c *gin.Context) {
 	c.Writer.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
@@ -0,0 +1,99 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otel
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/kubetail-org/kubetail/modules/shared/otel")
+
+// GraphQLTracer is a gqlgen extension that creates a span for each GraphQL
+// operation. Resolvers run inside the span so their spans become children.
+type GraphQLTracer struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+} = GraphQLTracer{}
+
+// ExtensionName
+func (GraphQLTracer) ExtensionName() string {
+	return "GraphQLTracer"
+}
+
+// Validate
+func (GraphQLTracer) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptOperation
+func (GraphQLTracer) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+	if oc == nil || oc.Operation == nil {
+		return next(ctx)
+	}
+
+	opType := string(oc.Operation.Operation)
+
+	// Use operation name or name of first top-level field
+	name := oc.OperationName
+	if name == "" && len(oc.Operation.SelectionSet) > 0 {
+		if field, ok := oc.Operation.SelectionSet[0].(*ast.Field); ok {
+			name = field.Name
+		}
+	}
+
+	ctx, span := tracer.Start(ctx, "graphql."+opType+" "+name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("graphql.operation.type", opType),
+			attribute.String("graphql.operation.name", name),
+		),
+	)
+
+	// Subscription context is canceled when the client unsubscribes
+	isSubscription := oc.Operation.Operation == ast.Subscription
+	if isSubscription {
+		go func() {
+			<-ctx.Done()
+			span.End()
+		}()
+	}
+
+	responses := next(ctx)
+
+	return func(ctx context.Context) *graphql.Response {
+		resp := responses(ctx)
+
+		if resp != nil && len(resp.Errors) > 0 {
+			span.SetStatus(codes.Error, resp.Errors.Error())
+		}
+
+		// Queries and mutations send a single response
+		if !isSubscription {
+			span.End()
+		}
+
+		return resp
+	}
+}
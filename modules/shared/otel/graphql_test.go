@@ -0,0 +1,81 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestGraphQLTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	// Run operation through tracer and return the response
+	intercept := func(ctx context.Context, opType ast.Operation, resp *graphql.Response) *graphql.Response {
+		ctx = graphql.WithOperationContext(ctx, &graphql.OperationContext{
+			Operation: &ast.OperationDefinition{
+				Operation:    opType,
+				SelectionSet: ast.SelectionSet{&ast.Field{Name: "logRecordsFetch"}},
+			},
+		})
+
+		responses := GraphQLTracer{}.InterceptOperation(ctx, func(ctx context.Context) graphql.ResponseHandler {
+			return func(ctx context.Context) *graphql.Response { return resp }
+		})
+		return responses(ctx)
+	}
+
+	t.Run("query", func(t *testing.T) {
+		recorder.Reset()
+
+		resp := &graphql.Response{Errors: gqlerror.List{gqlerror.Errorf("boom")}}
+		assert.Equal(t, resp, intercept(context.Background(), ast.Query, resp))
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "graphql.query logRecordsFetch", spans[0].Name())
+		assert.Contains(t, spans[0].Attributes(), attribute.String("graphql.operation.type", "query"))
+		assert.Contains(t, spans[0].Attributes(), attribute.String("graphql.operation.name", "logRecordsFetch"))
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+	})
+
+	t.Run("subscription", func(t *testing.T) {
+		recorder.Reset()
+
+		// Span stays open until the client unsubscribes
+		ctx, cancel := context.WithCancel(context.Background())
+		intercept(ctx, ast.Subscription, &graphql.Response{})
+		assert.Empty(t, recorder.Ended())
+
+		cancel()
+		assert.Eventually(t, func() bool {
+			return len(recorder.Ended()) == 1
+		}, time.Second, 10*time.Millisecond)
+		assert.Equal(t, "graphql.subscription logRecordsFetch", recorder.Ended()[0].Name())
+	})
+}
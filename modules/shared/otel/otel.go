@@ -2,37 +2,85 @@ package otel
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
 	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc/credentials"
+)
+
+// Exporter enum type
+type Exporter string
+
+const (
+	ExporterOTLPGRPC Exporter = "otlp-grpc"
+	ExporterOTLPHTTP Exporter = "otlp-http"
+	ExporterStdout   Exporter = "stdout"
 )
 
 // OTelConfig is the configuration for the OTel tracer provider
 type OTelConfig struct {
 	Enabled bool
-	// Debug mode, set to true to use stdout trace exporter
-	Debug bool
-	// TODO: Add support for a custom collector endpoint
-	Endpoint    string
+
+	// Exporter used to send spans (defaults to otlp-grpc)
+	Exporter Exporter
+
+	// Collector endpoint (host:port)
+	Endpoint string
+
+	// Headers sent with each export request (e.g. for authentication)
+	Headers map[string]string
+
+	// Fraction of new traces to sample. Child spans follow their parent's
+	// sampling decision.
+	SamplingRatio float64
+
+	// Connect to the collector using TLS
+	TLS struct {
+		Enabled bool
+
+		// CA bundle for verifying the collector (defaults to system roots)
+		CAFile string
+
+		// Client certificate for mTLS
+		CertFile string
+		KeyFile  string
+	}
+
 	ServiceName string
 }
 
+// ShutdownFunc flushes pending spans and stops the tracer provider
+type ShutdownFunc func(ctx context.Context) error
+
 // InitTracer initializes the global OTel tracer provider
 // which can be used to create tracers throughout the call stack
-func InitTracer(cfg *OTelConfig) error {
+func InitTracer(cfg *OTelConfig) (ShutdownFunc, error) {
+	// Propagate trace context to downstream services even if tracing is
+	// disabled locally
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
 	// No tracing enabled, set noop tracer provider
 	if !cfg.Enabled {
 		// Set the global TracerProvider to a NoopTracerProvider
 		// this ensures that methods that start a span will not break despite
 		// creating spans and no telemetry will be collected or sent
 		otel.SetTracerProvider(noop.NewTracerProvider())
-		return nil
+		return func(ctx context.Context) error { return nil }, nil
 	}
 
 	// Define common attributes for all spans
@@ -44,42 +92,99 @@ func InitTracer(cfg *OTelConfig) error {
 		),
 	)
 	if err != nil {
-		return err
-	}
-
-	// Debug mode, use stdout trace exporter
-	if cfg.Debug {
-		traceExporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
-		if err != nil {
-			return err
-		}
-
-		traceProvider := trace.NewTracerProvider(
-			trace.WithSampler(trace.AlwaysSample()),
-			trace.WithBatcher(traceExporter,
-				trace.WithBatchTimeout(time.Second)),
-			trace.WithResource(resources))
-		otel.SetTracerProvider(traceProvider)
-
-		return nil
+		return nil, err
 	}
 
-	// Development mode use gRPC trace exporter to an OTel collector without TLS
-	// since this is a local development setup only for now.
-	traceExporter, err := otlptracegrpc.New(context.Background(),
-		otlptracegrpc.WithInsecure(),
-		otlptracegrpc.WithEndpoint(cfg.Endpoint),
-	)
+	traceExporter, err := newExporter(cfg)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	traceProvider := trace.NewTracerProvider(
-		trace.WithSampler(trace.AlwaysSample()),
+		trace.WithSampler(trace.ParentBased(trace.TraceIDRatioBased(cfg.SamplingRatio))),
 		trace.WithBatcher(traceExporter,
 			trace.WithBatchTimeout(time.Second)),
 		trace.WithResource(resources))
 	otel.SetTracerProvider(traceProvider)
 
-	return nil
+	return traceProvider.Shutdown, nil
+}
+
+// Return span exporter for config
+func newExporter(cfg *OTelConfig) (trace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterOTLPHTTP:
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(cfg.Endpoint),
+			otlptracehttp.WithHeaders(cfg.Headers),
+		}
+
+		tlsCfg, err := newTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		if tlsCfg != nil {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsCfg))
+		} else {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		return otlptracehttp.New(context.Background(), opts...)
+	case ExporterOTLPGRPC, "":
+		opts := []otlptracegrpc.Option{
+			otlptracegrpc.WithEndpoint(cfg.Endpoint),
+			otlptracegrpc.WithHeaders(cfg.Headers),
+		}
+
+		tlsCfg, err := newTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		if tlsCfg != nil {
+			opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsCfg)))
+		} else {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		return otlptracegrpc.New(context.Background(), opts...)
+	default:
+		return nil, fmt.Errorf("unknown exporter: %s", cfg.Exporter)
+	}
+}
+
+// Return tls config for the collector connection or nil if tls is disabled
+func newTLSConfig(cfg *OTelConfig) (*tls.Config, error) {
+	if !cfg.TLS.Enabled {
+		return nil, nil
+	}
+
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	// Client cert for mTLS
+	if cfg.TLS.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	// Root CA for collector verification
+	if cfg.TLS.CAFile != "" {
+		caPem, err := os.ReadFile(cfg.TLS.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(caPem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.TLS.CAFile)
+		}
+		tlsCfg.RootCAs = roots
+	}
+
+	return tlsCfg, nil
 }
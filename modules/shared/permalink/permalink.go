@@ -0,0 +1,198 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permalink
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Current token format version
+const version = 1
+
+// Query param used by the dashboard to carry the token
+const QueryParam = "link"
+
+// Number of signature bytes kept in the token
+const sigLen = 16
+
+var (
+	ErrInvalidToken = errors.New("invalid permalink")
+	ErrExpired      = errors.New("permalink has expired")
+	ErrNoSecret     = errors.New("permalinks require a signing secret")
+)
+
+// Represents source filters
+type SourceFilter struct {
+	Region    []string `json:"r,omitempty"`
+	Zone      []string `json:"z,omitempty"`
+	OS        []string `json:"o,omitempty"`
+	Arch      []string `json:"a,omitempty"`
+	Node      []string `json:"n,omitempty"`
+	Container []string `json:"c,omitempty"`
+}
+
+// Represents the highlighted log line
+type Highlight struct {
+	Timestamp   time.Time `json:"t"`
+	ContainerID string    `json:"c,omitempty"`
+}
+
+// Represents the query state of a log view
+type State struct {
+	KubeContext  string       `json:"kc,omitempty"`
+	Sources      []string     `json:"s"`
+	SourceFilter SourceFilter `json:"f,omitzero"`
+	Grep         string       `json:"g,omitempty"`
+	Mode         string       `json:"m,omitempty"`
+	Since        string       `json:"si,omitempty"`
+	Until        string       `json:"u,omitempty"`
+	Highlight    *Highlight   `json:"h,omitempty"`
+}
+
+// Represents a decoded permalink
+type Link struct {
+	State     State
+	ExpiresAt time.Time
+}
+
+// Wire format of the token payload
+type payload struct {
+	Version   int   `json:"v"`
+	State     State `json:"st"`
+	ExpiresAt int64 `json:"exp,omitempty"`
+}
+
+// Sign encodes the link into a URL-safe token signed with `secret`
+func Sign(secret string, link Link) (string, error) {
+	if secret == "" {
+		return "", ErrNoSecret
+	}
+
+	p := payload{Version: version, State: link.State}
+	if !link.ExpiresAt.IsZero() {
+		p.ExpiresAt = link.ExpiresAt.Unix()
+	}
+
+	b, err := json.Marshal(&p)
+	if err != nil {
+		return "", err
+	}
+
+	body := base64.RawURLEncoding.EncodeToString(b)
+	sig := base64.RawURLEncoding.EncodeToString(sign(secret, body))
+
+	return body + "." + sig, nil
+}
+
+// Verify checks the token signature and expiry and returns the decoded link
+func Verify(secret string, token string) (*Link, error) {
+	if secret == "" {
+		return nil, ErrNoSecret
+	}
+
+	body, sigStr, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(sigStr)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if !hmac.Equal(sig, sign(secret, body)) {
+		return nil, ErrInvalidToken
+	}
+
+	return decode(body)
+}
+
+// Parse decodes the token and checks expiry without verifying the signature.
+// It's intended for clients (e.g. the CLI) that don't have access to the
+// secret and run queries with their own credentials.
+func Parse(token string) (*Link, error) {
+	body, _, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	return decode(body)
+}
+
+// TokenFromURL extracts the token from a dashboard URL. Bare tokens are
+// returned as-is.
+func TokenFromURL(s string) string {
+	s = strings.TrimSpace(s)
+
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" {
+		return s
+	}
+
+	if token := u.Query().Get(QueryParam); token != "" {
+		return token
+	}
+
+	// Fall back to fragment query (e.g. /#/console?link=...)
+	if _, query, ok := strings.Cut(u.Fragment, "?"); ok {
+		if values, err := url.ParseQuery(query); err == nil {
+			return values.Get(QueryParam)
+		}
+	}
+
+	return ""
+}
+
+// Decode payload and check expiry
+func decode(body string) (*Link, error) {
+	b, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var p payload
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if p.Version != version {
+		return nil, ErrInvalidToken
+	}
+
+	link := &Link{State: p.State}
+	if p.ExpiresAt != 0 {
+		link.ExpiresAt = time.Unix(p.ExpiresAt, 0).UTC()
+		if time.Now().After(link.ExpiresAt) {
+			return nil, ErrExpired
+		}
+	}
+
+	return link, nil
+}
+
+// Return truncated HMAC-SHA256 signature. The key is derived from the secret
+// so it isn't used directly for two different purposes.
+func sign(secret string, body string) []byte {
+	key := sha256.Sum256([]byte("kubetail-permalink:" + secret))
+	mac := hmac.New(sha256.New, key[:])
+	mac.Write([]byte(body))
+	return mac.Sum(nil)[:sigLen]
+}
@@ -0,0 +1,143 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permalink
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLink() Link {
+	return Link{
+		State: State{
+			KubeContext:  "prod",
+			Sources:      []string{"default:deployments/web", "kube-system:pods/*"},
+			SourceFilter: SourceFilter{Region: []string{"us-east-1"}, Container: []string{"web"}},
+			Grep:         "GET /(about|contact)",
+			Mode:         "HEAD",
+			Since:        "2025-01-01T00:00:00Z",
+			Highlight: &Highlight{
+				Timestamp:   time.Date(2025, 1, 1, 0, 0, 5, 123, time.UTC),
+				ContainerID: "abc123",
+			},
+		},
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	link := newTestLink()
+
+	token, err := Sign("secret", link)
+	require.NoError(t, err)
+
+	// Token is URL-safe
+	assert.Equal(t, token, url.QueryEscape(token))
+
+	decoded, err := Verify("secret", token)
+	require.NoError(t, err)
+	assert.Equal(t, link.State, decoded.State)
+	assert.True(t, decoded.ExpiresAt.IsZero())
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	token, err := Sign("secret", newTestLink())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		secret string
+		token  string
+	}{
+		{"wrong secret", "other", token},
+		{"modified body", "secret", "x" + token[1:]},
+		{"missing signature", "secret", token[:len(token)-23]},
+		{"garbage", "secret", "not-a-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Verify(tt.secret, tt.token)
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	link := newTestLink()
+
+	// Not yet expired
+	link.ExpiresAt = time.Now().Add(time.Hour)
+	token, err := Sign("secret", link)
+	require.NoError(t, err)
+
+	decoded, err := Verify("secret", token)
+	require.NoError(t, err)
+	assert.Equal(t, link.ExpiresAt.Unix(), decoded.ExpiresAt.Unix())
+
+	// Expired
+	link.ExpiresAt = time.Now().Add(-time.Minute)
+	token, err = Sign("secret", link)
+	require.NoError(t, err)
+
+	_, err = Verify("secret", token)
+	assert.ErrorIs(t, err, ErrExpired)
+
+	_, err = Parse(token)
+	assert.ErrorIs(t, err, ErrExpired)
+}
+
+func TestNoSecret(t *testing.T) {
+	_, err := Sign("", newTestLink())
+	assert.ErrorIs(t, err, ErrNoSecret)
+
+	_, err = Verify("", "abc.def")
+	assert.ErrorIs(t, err, ErrNoSecret)
+}
+
+func TestParseWithoutSecret(t *testing.T) {
+	link := newTestLink()
+
+	token, err := Sign("secret", link)
+	require.NoError(t, err)
+
+	decoded, err := Parse(token)
+	require.NoError(t, err)
+	assert.Equal(t, link.State, decoded.State)
+}
+
+func TestTokenFromURL(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"bare token", "abc.def", "abc.def"},
+		{"bare token with whitespace", " abc.def\n", "abc.def"},
+		{"query param", "https://kubetail.example.com/console?link=abc.def", "abc.def"},
+		{"query param with base path", "http://localhost:7500/kubetail/console?foo=bar&link=abc.def", "abc.def"},
+		{"fragment query", "https://kubetail.example.com/#/console?link=abc.def", "abc.def"},
+		{"url without token", "https://kubetail.example.com/console", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, TokenFromURL(tt.input))
+		})
+	}
+}
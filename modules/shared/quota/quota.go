@@ -0,0 +1,254 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/rest"
+
+	"github.com/kubetail-org/kubetail/modules/shared/audit"
+	gqlerrors "github.com/kubetail-org/kubetail/modules/shared/graphql/errors"
+	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+)
+
+// Quota names used in error extensions
+const (
+	QuotaMaxConcurrentSubscriptions = "maxConcurrentSubscriptions"
+	QuotaMaxConcurrentFetches       = "maxConcurrentFetches"
+	QuotaMaxSourcesPerStream        = "maxSourcesPerStream"
+)
+
+// How long idle per-user state is kept around
+const idleTTL = 10 * time.Minute
+
+// Limits holds per-user limits. Zero values mean unlimited.
+type Limits struct {
+	MaxConcurrentSubscriptions int
+	MaxConcurrentFetches       int
+	RequestsPerMinute          int
+	MaxSourcesPerStream        int
+}
+
+// IsZero returns true if no limits are set
+func (l Limits) IsZero() bool {
+	return l == Limits{}
+}
+
+// Represents usage of a single user
+type userState struct {
+	subscriptions int
+	fetches       int
+	limiter       *rate.Limiter
+	lastSeen      time.Time
+}
+
+// Limiter enforces Limits per user. All methods are safe to call on a nil
+// Limiter, in which case nothing is enforced.
+type Limiter struct {
+	limits   Limits
+	identify audit.IdentifyFunc
+	users    map[string]*userState
+	mu       sync.Mutex
+}
+
+// Create new Limiter instance. Returns nil if no limits are set.
+func NewLimiter(limits Limits, identify audit.IdentifyFunc) *Limiter {
+	if limits.IsZero() {
+		return nil
+	}
+	return NewReloadableLimiter(limits, identify)
+}
+
+// Create new Limiter instance whose limits can be changed later with
+// SetLimits. Unlike NewLimiter, never returns nil.
+func NewReloadableLimiter(limits Limits, identify audit.IdentifyFunc) *Limiter {
+	return &Limiter{
+		limits:   limits,
+		identify: identify,
+		users:    make(map[string]*userState),
+	}
+}
+
+// SetLimits replaces the limits. Slots held by active fetches and
+// subscriptions count against the new limits, except for those acquired
+// while no limits were set.
+func (l *Limiter) SetLimits(limits Limits) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rateChanged := limits.RequestsPerMinute != l.limits.RequestsPerMinute
+	l.limits = limits
+
+	// Replace rate limiters of known users
+	if rateChanged {
+		for _, u := range l.users {
+			u.limiter = l.newRateLimiter_UNSAFE()
+		}
+	}
+}
+
+// AcquireFetch reserves a fetch slot for the user in `ctx`. The caller must
+// call the returned release function when the fetch is done.
+func (l *Limiter) AcquireFetch(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	return l.acquire(ctx, QuotaMaxConcurrentFetches, func(limits Limits) int {
+		return limits.MaxConcurrentFetches
+	}, func(u *userState) *int {
+		return &u.fetches
+	})
+}
+
+// AcquireSubscription reserves a subscription slot for the user in `ctx`.
+// The caller must call the returned release function when the subscription
+// ends.
+func (l *Limiter) AcquireSubscription(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	return l.acquire(ctx, QuotaMaxConcurrentSubscriptions, func(limits Limits) int {
+		return limits.MaxConcurrentSubscriptions
+	}, func(u *userState) *int {
+		return &u.subscriptions
+	})
+}
+
+// MaxSourcesPerStream returns the per-stream source limit (0 means unlimited)
+func (l *Limiter) MaxSourcesPerStream() int {
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.limits.MaxSourcesPerStream
+}
+
+// Error converts stream errors caused by quotas into GraphQL errors. Other
+// errors are returned as-is.
+func (l *Limiter) Error(err error) error {
+	if errors.Is(err, logs.ErrMaxSourcesExceeded) {
+		return gqlerrors.NewQuotaExceededError(QuotaMaxSourcesPerStream, l.MaxSourcesPerStream())
+	}
+	return err
+}
+
+// Check rate limit and increment counter
+func (l *Limiter) acquire(ctx context.Context, quota string, limit func(limits Limits) int, counter func(u *userState) *int) (func(), error) {
+	// Skip identifying the user if nothing is enforced
+	l.mu.Lock()
+	isZero := l.limits.IsZero()
+	l.mu.Unlock()
+	if isZero {
+		return func() {}, nil
+	}
+
+	key, err := l.userKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	max := limit(l.limits)
+
+	now := time.Now()
+	l.pruneIdle_UNSAFE(now)
+
+	u, exists := l.users[key]
+	if !exists {
+		u = &userState{limiter: l.newRateLimiter_UNSAFE()}
+		l.users[key] = u
+	}
+	u.lastSeen = now
+
+	// Check concurrency limit
+	n := counter(u)
+	if max > 0 && *n >= max {
+		return nil, gqlerrors.NewQuotaExceededError(quota, max)
+	}
+
+	// Check rate limit
+	if u.limiter != nil && !u.limiter.AllowN(now, 1) {
+		return nil, gqlerrors.ErrRateLimited
+	}
+
+	*n += 1
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			*n -= 1
+			u.lastSeen = time.Now()
+		})
+	}
+
+	return release, nil
+}
+
+// Return the state key of the user in `ctx`. Forwarded identities are scoped
+// to the credentials that sent them so they can't be used to get fresh
+// buckets, and callers whose tokens can't be resolved are rejected. Callers
+// without credentials share one bucket.
+func (l *Limiter) userKey(ctx context.Context) (string, error) {
+	if l.identify == nil {
+		return "", nil
+	}
+
+	// Identify credentials
+	user, _ := l.identify(context.WithValue(ctx, k8shelpers.ImpersonateCtxKey, (*rest.ImpersonationConfig)(nil)))
+	if user == audit.UnknownUser {
+		return "", gqlerrors.ErrUnauthenticated
+	}
+
+	// Add forwarded identity
+	if imp := k8shelpers.ImpersonationFromContext(ctx); imp != nil {
+		return user + "\x00" + imp.UserName, nil
+	}
+
+	return user, nil
+}
+
+// Return per-user rate limiter for the current limits (nil if unlimited)
+func (l *Limiter) newRateLimiter_UNSAFE() *rate.Limiter {
+	if l.limits.RequestsPerMinute <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(float64(l.limits.RequestsPerMinute)/60), l.limits.RequestsPerMinute)
+}
+
+// Remove state of users that have been idle for a while
+func (l *Limiter) pruneIdle_UNSAFE(now time.Time) {
+	for key, u := range l.users {
+		if u.subscriptions == 0 && u.fetches == 0 && now.Sub(u.lastSeen) > idleTTL {
+			delete(l.users, key)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"k8s.io/client-go/rest"
+
+	"github.com/kubetail-org/kubetail/modules/shared/audit"
+	gqlerrors "github.com/kubetail-org/kubetail/modules/shared/graphql/errors"
+	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
+	"github.com/kubetail-org/kubetail/modules/shared/logs"
+)
+
+type userCtxKey struct{}
+
+// Identify users by context value
+func testIdentify(ctx context.Context) (string, []string) {
+	user, _ := ctx.Value(userCtxKey{}).(string)
+	return user, nil
+}
+
+func userCtx(user string) context.Context {
+	return context.WithValue(context.Background(), userCtxKey{}, user)
+}
+
+func TestNewLimiter(t *testing.T) {
+	assert.Nil(t, NewLimiter(Limits{}, testIdentify))
+	assert.NotNil(t, NewLimiter(Limits{MaxConcurrentFetches: 1}, testIdentify))
+}
+
+func TestNilLimiter(t *testing.T) {
+	var l *Limiter
+
+	release, err := l.AcquireFetch(context.Background())
+	require.NoError(t, err)
+	release()
+
+	release, err = l.AcquireSubscription(context.Background())
+	require.NoError(t, err)
+	release()
+
+	assert.Equal(t, 0, l.MaxSourcesPerStream())
+}
+
+func TestConcurrencyLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  Limits
+		acquire func(l *Limiter, ctx context.Context) (func(), error)
+		quota   string
+	}{
+		{
+			"fetches",
+			Limits{MaxConcurrentFetches: 2},
+			(*Limiter).AcquireFetch,
+			QuotaMaxConcurrentFetches,
+		},
+		{
+			"subscriptions",
+			Limits{MaxConcurrentSubscriptions: 2},
+			(*Limiter).AcquireSubscription,
+			QuotaMaxConcurrentSubscriptions,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLimiter(tt.limits, testIdentify)
+
+			// Fill up alice's slots
+			release1, err := tt.acquire(l, userCtx("alice"))
+			require.NoError(t, err)
+
+			release2, err := tt.acquire(l, userCtx("alice"))
+			require.NoError(t, err)
+
+			_, err = tt.acquire(l, userCtx("alice"))
+			require.Error(t, err)
+
+			var gqlErr *gqlerror.Error
+			require.True(t, errors.As(err, &gqlErr))
+			assert.Equal(t, "KUBETAIL_QUOTA_EXCEEDED", gqlErr.Extensions["code"])
+			assert.Equal(t, tt.quota, gqlErr.Extensions["quota"])
+			assert.Equal(t, 2, gqlErr.Extensions["limit"])
+
+			// Other users aren't affected
+			release3, err := tt.acquire(l, userCtx("bob"))
+			require.NoError(t, err)
+			release3()
+
+			// Releasing frees up a slot (calling twice is harmless)
+			release1()
+			release1()
+
+			release4, err := tt.acquire(l, userCtx("alice"))
+			require.NoError(t, err)
+
+			_, err = tt.acquire(l, userCtx("alice"))
+			require.Error(t, err)
+
+			release2()
+			release4()
+		})
+	}
+}
+
+func TestRequestsPerMinute(t *testing.T) {
+	l := NewLimiter(Limits{RequestsPerMinute: 3}, testIdentify)
+
+	for range 3 {
+		release, err := l.AcquireFetch(userCtx("alice"))
+		require.NoError(t, err)
+		release()
+	}
+
+	// Fetches and subscriptions share the same budget
+	_, err := l.AcquireSubscription(userCtx("alice"))
+	assert.Equal(t, gqlerrors.ErrRateLimited, err)
+
+	// Other users aren't affected
+	release, err := l.AcquireFetch(userCtx("bob"))
+	require.NoError(t, err)
+	release()
+}
+
+func TestForwardedIdentities(t *testing.T) {
+	// Impersonated identities take precedence, like the TokenReview identifier
+	identify := func(ctx context.Context) (string, []string) {
+		if imp := k8shelpers.ImpersonationFromContext(ctx); imp != nil {
+			return imp.UserName, nil
+		}
+		return testIdentify(ctx)
+	}
+
+	l := NewLimiter(Limits{MaxConcurrentFetches: 1}, identify)
+
+	impCtx := func(user string, impUser string) context.Context {
+		return context.WithValue(userCtx(user), k8shelpers.ImpersonateCtxKey, &rest.ImpersonationConfig{UserName: impUser})
+	}
+
+	release, err := l.AcquireFetch(impCtx("dashboard", "alice"))
+	require.NoError(t, err)
+	defer release()
+
+	// Same credentials and forwarded identity share a bucket
+	_, err = l.AcquireFetch(impCtx("dashboard", "alice"))
+	require.Error(t, err)
+
+	// Forwarded identities get their own bucket
+	release2, err := l.AcquireFetch(impCtx("dashboard", "bob"))
+	require.NoError(t, err)
+	defer release2()
+
+	// Forwarded identities are scoped to the credentials
+	release3, err := l.AcquireFetch(impCtx("mallory", "alice"))
+	require.NoError(t, err)
+	defer release3()
+
+	// Credentials without forwarded identity are separate too
+	release4, err := l.AcquireFetch(userCtx("dashboard"))
+	require.NoError(t, err)
+	defer release4()
+}
+
+func TestUnknownUser(t *testing.T) {
+	l := NewLimiter(Limits{MaxConcurrentFetches: 1}, testIdentify)
+
+	// Callers with unresolved tokens are rejected
+	_, err := l.AcquireFetch(userCtx(audit.UnknownUser))
+	assert.Equal(t, gqlerrors.ErrUnauthenticated, err)
+
+	// Callers without credentials share one bucket
+	release, err := l.AcquireFetch(context.Background())
+	require.NoError(t, err)
+	defer release()
+
+	_, err = l.AcquireFetch(context.Background())
+	require.Error(t, err)
+}
+
+func TestError(t *testing.T) {
+	l := NewLimiter(Limits{MaxSourcesPerStream: 10}, testIdentify)
+
+	err := l.Error(fmt.Errorf("%w: 11 sources matched (limit 10)", logs.ErrMaxSourcesExceeded))
+
+	var gqlErr *gqlerror.Error
+	require.True(t, errors.As(err, &gqlErr))
+	assert.Equal(t, QuotaMaxSourcesPerStream, gqlErr.Extensions["quota"])
+	assert.Equal(t, 10, gqlErr.Extensions["limit"])
+
+	// Other errors are passed through
+	otherErr := errors.New("other")
+	assert.Equal(t, otherErr, l.Error(otherErr))
+}
+
+func TestSetLimits(t *testing.T) {
+	l := NewReloadableLimiter(Limits{}, testIdentify)
+	require.NotNil(t, l)
+
+	// Nothing is enforced without limits
+	for range 3 {
+		_, err := l.AcquireFetch(userCtx("alice"))
+		require.NoError(t, err)
+	}
+
+	l.SetLimits(Limits{MaxConcurrentFetches: 2})
+	release1, err := l.AcquireFetch(userCtx("alice"))
+	require.NoError(t, err)
+
+	// Slots held before the update count against the new limits
+	l.SetLimits(Limits{MaxConcurrentFetches: 1, MaxSourcesPerStream: 5})
+	assert.Equal(t, 5, l.MaxSourcesPerStream())
+
+	_, err = l.AcquireFetch(userCtx("alice"))
+	assert.Error(t, err)
+
+	release1()
+	release2, err := l.AcquireFetch(userCtx("alice"))
+	require.NoError(t, err)
+	release2()
+
+	// Rate limits apply to known users
+	l.SetLimits(Limits{RequestsPerMinute: 1})
+	release3, err := l.AcquireFetch(userCtx("alice"))
+	require.NoError(t, err)
+	release3()
+
+	_, err = l.AcquireFetch(userCtx("alice"))
+	assert.Equal(t, gqlerrors.ErrRateLimited, err)
+}
@@ -0,0 +1,125 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testutils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+/**
+ * TestCerts - Paths of PEM files signed by a throwaway CA for testing TLS
+ * connections. The server certificate is valid for "localhost" and
+ * 127.0.0.1.
+ */
+type TestCerts struct {
+	CAFile         string
+	ServerCertFile string
+	ServerKeyFile  string
+	ClientCertFile string
+	ClientKeyFile  string
+}
+
+// Generate CA, server and client certificates in a temporary directory
+func NewTestCerts(t *testing.T) *TestCerts {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	// Write PEM block to file in temp dir
+	writePEM := func(name string, blockType string, der []byte) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	// Create certificate signed by parent (self-signed if parent is nil)
+	serial := int64(0)
+	newCert := func(tmpl *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		serial += 1
+		tmpl.SerialNumber = big.NewInt(serial)
+		tmpl.NotBefore = time.Now().Add(-time.Hour)
+		tmpl.NotAfter = time.Now().Add(time.Hour)
+
+		if parent == nil {
+			parent, parentKey = tmpl, key
+		}
+
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return cert, key
+	}
+
+	// Write certificate and key files
+	writePair := func(name string, cert *x509.Certificate, key *ecdsa.PrivateKey) (string, string) {
+		keyDer, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return writePEM(name+".crt", "CERTIFICATE", cert.Raw), writePEM(name+".key", "EC PRIVATE KEY", keyDer)
+	}
+
+	ca, caKey := newCert(&x509.Certificate{
+		Subject:               pkix.Name{CommonName: "kubetail-test-ca"},
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil, nil)
+
+	server, serverKey := newCert(&x509.Certificate{
+		Subject:     pkix.Name{CommonName: "localhost"},
+		DNSNames:    []string{"localhost"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca, caKey)
+
+	client, clientKey := newCert(&x509.Certificate{
+		Subject:     pkix.Name{CommonName: "kubetail-dashboard"},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, caKey)
+
+	certs := &TestCerts{CAFile: writePEM("ca.crt", "CERTIFICATE", ca.Raw)}
+	certs.ServerCertFile, certs.ServerKeyFile = writePair("server", server, serverKey)
+	certs.ClientCertFile, certs.ClientKeyFile = writePair("client", client, clientKey)
+
+	return certs
+}
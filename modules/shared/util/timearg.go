@@ -0,0 +1,58 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sosodev/duration"
+)
+
+// ParseTimeArg parses an input either as an ISO timestamp or an ISO duration
+// string (relative to now). Empty inputs return the zero time.
+func ParseTimeArg(arg string) (time.Time, error) {
+	var zero time.Time
+
+	arg = strings.TrimSpace(arg)
+	if arg == "" {
+		return zero, nil
+	} else if timeAgo, err := duration.Parse(arg); err == nil {
+		// Parsed as ISO duration
+		return time.Now().Add(-1 * timeAgo.ToTimeDuration()), nil
+	} else if ts, err := time.Parse(time.RFC3339Nano, arg); err == nil {
+		// Parsed as ISO timestamp
+		return ts, nil
+	}
+
+	return zero, fmt.Errorf("unable to parse arg %s", arg)
+}
+
+// ParseDurationArg parses an input as an ISO duration string. Empty inputs
+// return zero.
+func ParseDurationArg(arg string) (time.Duration, error) {
+	arg = strings.TrimSpace(arg)
+	if arg == "" {
+		return 0, nil
+	}
+
+	d, err := duration.Parse(arg)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse arg %s", arg)
+	}
+
+	return d.ToTimeDuration(), nil
+}
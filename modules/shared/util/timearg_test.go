@@ -0,0 +1,57 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimeArg(t *testing.T) {
+	// Empty
+	ts, err := ParseTimeArg(" ")
+	require.NoError(t, err)
+	assert.True(t, ts.IsZero())
+
+	// Duration
+	ts, err = ParseTimeArg("PT1H")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(-1*time.Hour), ts, time.Minute)
+
+	// Timestamp
+	ts, err = ParseTimeArg("2024-01-02T03:04:05.123Z")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 123000000, time.UTC), ts)
+
+	// Invalid
+	_, err = ParseTimeArg("xxx")
+	assert.EqualError(t, err, "unable to parse arg xxx")
+}
+
+func TestParseDurationArg(t *testing.T) {
+	d, err := ParseDurationArg("")
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), d)
+
+	d, err = ParseDurationArg("PT5M")
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, d)
+
+	_, err = ParseDurationArg("5m")
+	assert.EqualError(t, err, "unable to parse arg 5m")
+}